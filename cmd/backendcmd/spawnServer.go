@@ -12,12 +12,15 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var app *application.Avalanche
+var (
+	app              *application.Avalanche
+	localNetworkName string
+)
 
 // backendCmd is the command to run the backend gRPC process
 func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
 	app = injectedApp
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:    constants.BackendCmd,
 		Short:  "Run the backend server",
 		Long:   "This tool requires a backend process to run; this command starts it",
@@ -25,9 +28,14 @@ func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
 		Args:   cobra.ExactArgs(0),
 		Hidden: true,
 	}
+	cmd.Flags().StringVar(&localNetworkName, binutils.BackendLocalNetworkFlag, "", "named local network to serve")
+	return cmd
 }
 
 func startBackend(_ *cobra.Command, _ []string) error {
+	if err := binutils.SelectLocalNetwork(app, localNetworkName, false); err != nil {
+		return err
+	}
 	s, err := binutils.NewGRPCServer(app.GetSnapshotsDir())
 	if err != nil {
 		return err
@@ -0,0 +1,194 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// nameCompleter lists the names that can be completed for a positional arg or flag
+type nameCompleter struct {
+	list func() ([]string, error)
+	// commands that take a new name, so there is nothing to complete
+	newNameCmds []string
+}
+
+var (
+	subnetNameCompleter  = nameCompleter{list: listSubnetNames, newNameCmds: []string{"create"}}
+	keyNameCompleter     = nameCompleter{list: listKeyNames, newNameCmds: []string{"create", "import"}}
+	clusterNameCompleter = nameCompleter{list: listClusterNames, newNameCmds: []string{"create", "wiz"}}
+	nodeIDCompleter      = nameCompleter{list: listNodeIDs}
+)
+
+// completers of positional args, by their placeholder in the command usage
+var argCompleters = map[string]nameCompleter{
+	"subnetName":  subnetNameCompleter,
+	"keyName":     keyNameCompleter,
+	"clusterName": clusterNameCompleter,
+}
+
+// completers of flag values, by flag name
+var flagCompleters = map[string]nameCompleter{
+	"subnet":  subnetNameCompleter,
+	"key":     keyNameCompleter,
+	"cluster": clusterNameCompleter,
+	"nodeID":  nodeIDCompleter,
+}
+
+var usePlaceholderRegex = regexp.MustCompile(`[\[<]([A-Za-z]+)(\.\.\.)?[\]>]`)
+
+// registerCompletions sets dynamic shell completion on [cmd] and its subcommands,
+// for the positional args and flags that take subnet, key or cluster names, or
+// node IDs. Names are read from the app dir at completion time
+func registerCompletions(cmd *cobra.Command) {
+	if cmd.ValidArgsFunction == nil {
+		if argsCompleters, variadic := getArgsCompleters(cmd); len(argsCompleters) > 0 {
+			cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+				index := len(args)
+				if index >= len(argsCompleters) {
+					if !variadic {
+						return nil, cobra.ShellCompDirectiveNoFileComp
+					}
+					index = len(argsCompleters) - 1
+				}
+				c := argsCompleters[index]
+				if c == nil {
+					return nil, cobra.ShellCompDirectiveNoFileComp
+				}
+				return completeNames(*c, toComplete, args)
+			}
+		}
+	}
+	for flagName, c := range flagCompleters {
+		c := c
+		flag := cmd.Flags().Lookup(flagName)
+		if flag == nil || flag.Value.Type() != "string" {
+			continue
+		}
+		if _, ok := cmd.GetFlagCompletionFunc(flagName); ok {
+			continue
+		}
+		_ = cmd.RegisterFlagCompletionFunc(flagName, func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completeNames(c, toComplete, nil)
+		})
+	}
+	for _, subCmd := range cmd.Commands() {
+		registerCompletions(subCmd)
+	}
+}
+
+// getArgsCompleters returns a completer (or nil) for each positional arg placeholder
+// of the usage of [cmd], and if the last one can be repeated. It returns no completers
+// if no positional arg can be completed
+func getArgsCompleters(cmd *cobra.Command) ([]*nameCompleter, bool) {
+	completers := []*nameCompleter{}
+	variadic := false
+	found := false
+	// placeholders after '--' are not positional args of the command itself
+	use, _, _ := strings.Cut(cmd.Use, "--")
+	for _, match := range usePlaceholderRegex.FindAllStringSubmatch(use, -1) {
+		var completer *nameCompleter
+		if c, ok := argCompleters[match[1]]; ok && !slices.Contains(c.newNameCmds, cmd.Name()) {
+			completer = &c
+			found = true
+		}
+		completers = append(completers, completer)
+		variadic = match[2] != ""
+	}
+	if !found {
+		return nil, false
+	}
+	return completers, variadic
+}
+
+// completeNames returns the names listed by [c] that start with [toComplete]
+// and were not already given in [args]
+func completeNames(c nameCompleter, toComplete string, args []string) ([]string, cobra.ShellCompDirective) {
+	names, err := c.list()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	completions := []string{}
+	for _, name := range names {
+		if strings.HasPrefix(name, toComplete) && !slices.Contains(args, name) {
+			completions = append(completions, name)
+		}
+	}
+	sort.Strings(completions)
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func listSubnetNames() ([]string, error) {
+	names, err := app.GetSidecarNames()
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return names, err
+}
+
+func listKeyNames() ([]string, error) {
+	files, err := os.ReadDir(app.GetKeyDir())
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, f := range files {
+		if strings.HasSuffix(f.Name(), constants.KeySuffix) {
+			names = append(names, strings.TrimSuffix(f.Name(), constants.KeySuffix))
+		}
+	}
+	return names, nil
+}
+
+func listClusterNames() ([]string, error) {
+	return app.ListClusterNames()
+}
+
+// listNodeIDs returns the node IDs previously used with the CLI: the ones of the
+// cloud nodes it created, and the ones of the validators it added to elastic subnets
+func listNodeIDs() ([]string, error) {
+	nodeIDs := []string{}
+	if nodeDirs, err := os.ReadDir(app.GetNodesDir()); err == nil {
+		for _, nodeDir := range nodeDirs {
+			nodeDirPath := filepath.Join(app.GetNodesDir(), nodeDir.Name())
+			certBytes, err := os.ReadFile(filepath.Join(nodeDirPath, constants.StakerCertFileName))
+			if err != nil {
+				continue
+			}
+			keyBytes, err := os.ReadFile(filepath.Join(nodeDirPath, constants.StakerKeyFileName))
+			if err != nil {
+				continue
+			}
+			if nodeID, err := utils.ToNodeID(certBytes, keyBytes); err == nil {
+				nodeIDs = append(nodeIDs, nodeID.String())
+			}
+		}
+	}
+	subnetNames, err := listSubnetNames()
+	if err != nil {
+		return nil, err
+	}
+	for _, subnetName := range subnetNames {
+		sc, err := app.LoadSidecar(subnetName)
+		if err != nil {
+			continue
+		}
+		for _, elasticSubnet := range sc.ElasticSubnet {
+			for nodeID := range elasticSubnet.Validators {
+				if !slices.Contains(nodeIDs, nodeID) {
+					nodeIDs = append(nodeIDs, nodeID)
+				}
+			}
+		}
+	}
+	return nodeIDs, nil
+}
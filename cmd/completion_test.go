@@ -0,0 +1,39 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetArgsCompleters(t *testing.T) {
+	require := require.New(t)
+
+	completers, variadic := getArgsCompleters(&cobra.Command{Use: "deploy [subnetName]"})
+	require.Len(completers, 1)
+	require.NotNil(completers[0])
+	require.False(variadic)
+
+	// new names are not completed
+	completers, _ = getArgsCompleters(&cobra.Command{Use: "create [subnetName]"})
+	require.Empty(completers)
+
+	completers, _ = getArgsCompleters(&cobra.Command{Use: "start [loadtestName] [clusterName] [subnetName]"})
+	require.Len(completers, 3)
+	require.Nil(completers[0])
+	require.NotNil(completers[1])
+	require.NotNil(completers[2])
+
+	completers, variadic = getArgsCompleters(&cobra.Command{Use: "watch [subnetName...]"})
+	require.Len(completers, 1)
+	require.True(variadic)
+
+	completers, _ = getArgsCompleters(&cobra.Command{Use: "ssh [clusterName|nodeID|instanceID|IP] [-- cmd]"})
+	require.Empty(completers)
+
+	completers, _ = getArgsCompleters(&cobra.Command{Use: "save [snapshotName]"})
+	require.Empty(completers)
+}
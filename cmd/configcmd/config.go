@@ -16,7 +16,10 @@ func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "config",
 		Short: "Modify configuration for Avalanche-CLI",
-		Long:  `Customize configuration for Avalanche-CLI`,
+		Long: `Customize configuration for Avalanche-CLI.
+
+Use config set, get and list to manage the values of the CLI config file,
+eg defaults that allow to skip flags and prompts on repeated workflows.`,
 		Run: func(cmd *cobra.Command, _ []string) {
 			err := cmd.Help()
 			if err != nil {
@@ -30,5 +33,12 @@ func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
 	cmd.AddCommand(newMigrateCmd())
 	cmd.AddCommand(newSingleNodeCmd())
 	cmd.AddCommand(newAuthorizeCloudAccessCmd())
+	cmd.AddCommand(newSnapshotsCmd())
+	cmd.AddCommand(newMaxWeightShareCmd())
+	cmd.AddCommand(newLocalNetworkCmd())
+	cmd.AddCommand(newSetCmd())
+	cmd.AddCommand(newGetCmd())
+	cmd.AddCommand(newListCmd())
+	cmd.AddCommand(newEnvironmentCmd())
 	return cmd
 }
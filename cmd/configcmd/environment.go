@@ -0,0 +1,183 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package configcmd
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metalgo/api/info"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	environmentNetworkID   uint32
+	environmentEndpoint    string
+	environmentDescription string
+	forceEnvironment       bool
+)
+
+// avalanche config environment
+func newEnvironmentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "environment",
+		Short: "Manage named deployment environments",
+		Long: `The config environment command suite registers networks by name, such as a
+staging devnet or an internal testnet, in addition to the built in ones.
+
+Registered environments are selected with the --environment flag of subnet deploy
+and the other subnet commands that operate on a network, and subnet deployments
+to them are tracked by environment name.`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+	}
+	// config environment add
+	addCmd := &cobra.Command{
+		Use:   "add [name]",
+		Short: "Register an environment",
+		Long: `The config environment add command registers the network reachable at --endpoint
+as environment [name]. The network ID is queried from the endpoint unless given
+with --network-id. Use the --force flag to replace an existing environment.`,
+		RunE:         addEnvironment,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	addCmd.Flags().StringVar(&environmentEndpoint, "endpoint", "", "API endpoint of the environment")
+	addCmd.Flags().Uint32Var(&environmentNetworkID, "network-id", 0, "network ID of the environment")
+	addCmd.Flags().StringVar(&environmentDescription, "description", "", "optional description of the environment")
+	addCmd.Flags().BoolVar(&forceEnvironment, "force", false, "overwrite an existing environment")
+	cmd.AddCommand(addCmd)
+	// config environment list
+	listCmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List the registered environments",
+		RunE:         listEnvironments,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	flags.AddJSONOutputFlag(listCmd)
+	cmd.AddCommand(listCmd)
+	// config environment remove
+	removeCmd := &cobra.Command{
+		Use:          "remove [name]",
+		Short:        "Remove a registered environment",
+		RunE:         removeEnvironment,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	removeCmd.Flags().BoolVar(&forceEnvironment, "force", false, "remove the environment without confirmation")
+	cmd.AddCommand(removeCmd)
+	return cmd
+}
+
+func validateEnvironmentName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("environment name can't be empty")
+	case strings.ContainsAny(name, " \t\n/"):
+		return fmt.Errorf("environment name %q can't contain spaces or slashes", name)
+	}
+	return nil
+}
+
+func addEnvironment(_ *cobra.Command, args []string) error {
+	name := args[0]
+	if err := validateEnvironmentName(name); err != nil {
+		return ux.NewCodedError(ux.ErrCodeInvalidArguments, err)
+	}
+	if environmentEndpoint == "" {
+		return ux.NewCodedError(ux.ErrCodeInvalidArguments, errors.New("the environment endpoint must be given with --endpoint"))
+	}
+	if _, err := url.ParseRequestURI(environmentEndpoint); err != nil {
+		return ux.NewCodedError(ux.ErrCodeInvalidArguments, fmt.Errorf("invalid endpoint %q: %w", environmentEndpoint, err))
+	}
+	environments, err := app.LoadEnvironments()
+	if err != nil {
+		return err
+	}
+	if _, ok := environments[name]; ok && !forceEnvironment {
+		return fmt.Errorf("environment %s already exists. Use --force to overwrite it", name)
+	}
+	if environmentNetworkID == 0 {
+		ctx, cancel := utils.GetAPIContext()
+		defer cancel()
+		environmentNetworkID, err = info.NewClient(environmentEndpoint).GetNetworkID(ctx)
+		if err != nil {
+			return fmt.Errorf("failed getting the network ID from %s, provide it with --network-id: %w", environmentEndpoint, err)
+		}
+	}
+	environments[name] = models.Environment{
+		NetworkID:   environmentNetworkID,
+		Endpoint:    strings.TrimSuffix(environmentEndpoint, "/"),
+		Description: environmentDescription,
+	}
+	if err := app.WriteEnvironments(environments); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Environment %s registered, with network ID %d. Select it with --environment %s", name, environmentNetworkID, name)
+	return nil
+}
+
+func listEnvironments(_ *cobra.Command, _ []string) error {
+	environments, err := app.LoadEnvironments()
+	if err != nil {
+		return err
+	}
+	if ux.JSONOutput() {
+		return ux.PrintResult(environments)
+	}
+	if len(environments) == 0 {
+		ux.Logger.PrintToUser("No environment registered. Use 'metal config environment add' to add one")
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Name", "Network ID", "Endpoint", "Description"})
+	table.SetRowLine(true)
+	for _, name := range environments.Names() {
+		env := environments[name]
+		table.Append([]string{name, strconv.FormatUint(uint64(env.NetworkID), 10), env.Endpoint, env.Description})
+	}
+	table.Render()
+	return nil
+}
+
+func removeEnvironment(_ *cobra.Command, args []string) error {
+	name := args[0]
+	environments, err := app.LoadEnvironments()
+	if err != nil {
+		return err
+	}
+	if _, ok := environments[name]; !ok {
+		return ux.NewCodedError(ux.ErrCodeNotFound, fmt.Errorf("environment %s is not registered", name))
+	}
+	if !forceEnvironment {
+		conf, err := app.Prompt.CaptureNoYes(fmt.Sprintf(
+			"Are you sure you want to remove environment %s? Subnets deployed to it won't be manageable until it is registered again", name))
+		if err != nil {
+			return err
+		}
+		if !conf {
+			ux.Logger.PrintToUser("Remove cancelled")
+			return nil
+		}
+	}
+	delete(environments, name)
+	if err := app.WriteEnvironments(environments); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Environment %s removed", name)
+	return nil
+}
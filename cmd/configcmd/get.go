@@ -0,0 +1,38 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package configcmd
+
+import (
+	"fmt"
+
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// metal config get
+func newGetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "get [key]",
+		Short:        "Print a value of the CLI config file",
+		Long:         `The config get command prints a value of the CLI config file, or nothing if it is not set.`,
+		RunE:         getConfigValue,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	flags.AddJSONOutputFlag(cmd)
+	return cmd
+}
+
+func getConfigValue(_ *cobra.Command, args []string) error {
+	s, err := getSetting(args[0])
+	if err != nil {
+		return err
+	}
+	if ux.JSONOutput() {
+		return ux.PrintResult(map[string]string{s.key: s.value()})
+	}
+	// printed raw, so it can be used in scripts
+	fmt.Println(s.value())
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package configcmd
+
+import (
+	"os"
+
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// metal config list
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the values of the CLI config file",
+		Long: `The config list command prints all the keys that can be managed with config set
+and get, together with their current values and descriptions.`,
+		RunE:         listConfigValues,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	flags.AddJSONOutputFlag(cmd)
+	return cmd
+}
+
+func listConfigValues(*cobra.Command, []string) error {
+	if ux.JSONOutput() {
+		values := map[string]string{}
+		for _, s := range settings {
+			values[s.key] = s.value()
+		}
+		return ux.PrintResult(values)
+	}
+	ux.Logger.PrintToUser("Config file: %s", app.Conf.GetConfigPath())
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Key", "Value", "Description"})
+	table.SetRowLine(true)
+	for _, s := range settings {
+		description := s.description
+		if s.readOnlyHint != "" {
+			description += " (set with " + s.readOnlyHint + ")"
+		}
+		table.Append([]string{s.key, s.value(), description})
+	}
+	table.Render()
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package configcmd
+
+import (
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	localHTTPPort    int
+	localStakingPort int
+	localHTTPHost    string
+)
+
+// avalanche config localNetwork command
+func newLocalNetworkCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "localNetwork",
+		Short: "set local network ports and bind address",
+		Long: `set the ports and bind address of the local network nodes
+
+--http-port and --staking-port set the ports of the first node, and the next
+nodes use the following ports, two by node. --http-host sets the address the
+node APIs listen on, eg 0.0.0.0 to reach them from other containers or machines.
+Pass 0 (or "" for --http-host) to go back to the defaults. The settings apply on
+the next network start.
+
+Without flags, prints the current settings.`,
+		RunE:         handleLocalNetworkSettings,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().IntVar(&localHTTPPort, "http-port", -1, "HTTP port of the first node (default 9650)")
+	cmd.Flags().IntVar(&localStakingPort, "staking-port", -1, "staking port of the first node (default 9651)")
+	cmd.Flags().StringVar(&localHTTPHost, "http-host", "", "address the node APIs listen on (default 127.0.0.1)")
+	return cmd
+}
+
+func handleLocalNetworkSettings(cmd *cobra.Command, _ []string) error {
+	prevSettings := subnet.GetLocalNetworkSettings(app)
+	settings := prevSettings
+	if cmd.Flags().Changed("http-port") {
+		settings.HTTPPort = localHTTPPort
+		if localHTTPPort == 0 {
+			settings.HTTPPort = constants.LocalNetworkBaseHTTPPort
+		}
+	}
+	if cmd.Flags().Changed("staking-port") {
+		settings.StakingPort = localStakingPort
+		if localStakingPort == 0 {
+			settings.StakingPort = constants.LocalNetworkBaseStakingPort
+		}
+	}
+	if cmd.Flags().Changed("http-host") {
+		settings.HTTPHost = localHTTPHost
+	}
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+	if settings != prevSettings {
+		for key, value := range map[string]interface{}{
+			constants.ConfigLocalHTTPPortKey:    settings.HTTPPort,
+			constants.ConfigLocalStakingPortKey: settings.StakingPort,
+			constants.ConfigLocalHTTPHostKey:    settings.HTTPHost,
+		} {
+			if err := app.Conf.SetConfigValue(key, value); err != nil {
+				return err
+			}
+		}
+	}
+	httpHost := settings.HTTPHost
+	if httpHost == "" {
+		httpHost = "127.0.0.1 (metalgo default)"
+	}
+	ux.Logger.PrintToUser("HTTP port of the first node: %d", settings.HTTPPort)
+	ux.Logger.PrintToUser("Staking port of the first node: %d", settings.StakingPort)
+	ux.Logger.PrintToUser("HTTP host: %s", httpHost)
+	return nil
+}
@@ -0,0 +1,44 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package configcmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// avalanche config max-weight-share command
+func newMaxWeightShareCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "max-weight-share [percentage]",
+		Short: "set the validator weight share warning threshold",
+		Long: `set the percentage of a subnet total stake weight above which subnet addValidator
+warns that the new validator would centralize the subnet (default 33).
+
+Without arguments, prints the current threshold.`,
+		RunE:         handleMaxWeightShareSettings,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+	}
+	return cmd
+}
+
+func handleMaxWeightShareSettings(_ *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		share, err := strconv.Atoi(args[0])
+		if err != nil || utils.ValidatePercentage(share) != nil {
+			return fmt.Errorf("invalid percentage %q, must be an integer between 1 and 100", args[0])
+		}
+		if err := app.Conf.SetConfigValue(constants.ConfigMaxWeightShareKey, share); err != nil {
+			return err
+		}
+	}
+	ux.Logger.PrintToUser("Max validator weight share: %d%%", subnet.GetMaxWeightShare(app))
+	return nil
+}
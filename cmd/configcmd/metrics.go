@@ -3,23 +3,44 @@
 package configcmd
 
 import (
+	"encoding/json"
 	"errors"
+	"time"
 
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/metrics"
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
 	"github.com/spf13/cobra"
 )
 
+const metricsStatus = "status"
+
+var metricsEndpoint string
+
 // avalanche config metrics command
 func newMetricsCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:          "metrics [enable | disable]",
-		Short:        "opt in or out of metrics collection",
-		Long:         "set user metrics collection preferences",
+		Use:   "metrics [enable | disable | status]",
+		Short: "opt in or out of metrics collection",
+		Long: `set user metrics collection preferences.
+
+Metrics are opt in. When enabled, each command run reports an anonymous usage event:
+the command path, CLI version, OS, architecture, duration, whether it succeeded and,
+if not, the category of the error. Args, flag values, error messages, keys and
+subnet data are never sent. The user is identified by a hash of the local user name.
+
+Events are posted as JSON to the endpoint given with --endpoint (or the
+MetricsEndpoint config value), or to the default one if none is set.
+
+Use status to see the current preferences and an example of the reported event.`,
 		RunE:         handleMetricsSettings,
 		Args:         cobra.ExactArgs(1),
+		ValidArgs:    []string{constants.Enable, constants.Disable, metricsStatus},
 		SilenceUsage: true,
 	}
+	cmd.Flags().StringVar(&metricsEndpoint, "endpoint", "", "on enable, post the usage events as JSON to this endpoint")
+	flags.AddJSONOutputFlag(cmd)
 
 	return cmd
 }
@@ -27,6 +48,14 @@ func newMetricsCmd() *cobra.Command {
 func handleMetricsSettings(_ *cobra.Command, args []string) error {
 	switch args[0] {
 	case constants.Enable:
+		if metricsEndpoint != "" {
+			if err := validateEndpoint(metricsEndpoint); err != nil {
+				return err
+			}
+			if err := app.Conf.SetConfigValue(constants.ConfigMetricsEndpointKey, metricsEndpoint); err != nil {
+				return err
+			}
+		}
 		ux.Logger.PrintToUser("Thank you for opting in Avalanche CLI usage metrics collection")
 		err := saveMetricsPreferences(true)
 		if err != nil {
@@ -38,6 +67,8 @@ func handleMetricsSettings(_ *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
+	case metricsStatus:
+		return printMetricsStatus()
 	default:
 		return errors.New("Invalid metrics argument '" + args[0] + "'")
 	}
@@ -47,3 +78,34 @@ func handleMetricsSettings(_ *cobra.Command, args []string) error {
 func saveMetricsPreferences(enableMetrics bool) error {
 	return app.Conf.SetConfigValue(constants.ConfigMetricsEnabledKey, enableMetrics)
 }
+
+func printMetricsStatus() error {
+	enabled := app.Conf.GetConfigBoolValue(constants.ConfigMetricsEnabledKey)
+	endpoint := app.Conf.GetConfigStringValue(constants.ConfigMetricsEndpointKey)
+	example := metrics.NewEvent("metal subnet deploy", "", errors.New(""), 42*time.Second, map[string]string{constants.Network: "Local Network"})
+	example.ErrorCategory = string(ux.ErrCodeNetworkNotRunning)
+	if ux.JSONOutput() {
+		return ux.PrintResult(struct {
+			Enabled  bool          `json:"enabled"`
+			Endpoint string        `json:"endpoint,omitempty"`
+			Example  metrics.Event `json:"exampleEvent"`
+		}{enabled, endpoint, example})
+	}
+	if enabled {
+		ux.Logger.PrintToUser("Usage metrics collection: enabled")
+	} else {
+		ux.Logger.PrintToUser("Usage metrics collection: disabled")
+	}
+	if endpoint == "" {
+		endpoint = "default"
+	}
+	ux.Logger.PrintToUser("Metrics endpoint: %s", endpoint)
+	exampleBytes, err := json.MarshalIndent(example, "", "  ")
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Example of a reported event:")
+	ux.Logger.PrintToUser(string(exampleBytes))
+	return nil
+}
@@ -0,0 +1,49 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package configcmd
+
+import (
+	"fmt"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// metal config set
+func newSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set [key] [value]",
+		Short: "Set a value of the CLI config file",
+		Long: `The config set command sets a value of the CLI config file, eg defaults
+used to skip flags and prompts on repeated workflows:
+
+  metal config set DefaultKey myKey
+  metal config set AvalancheGoVersion v1.10.0
+  metal config set NonInteractive true
+
+Use an empty value to clear a string value. Run metal config list to see the
+available keys.`,
+		RunE:         setConfigValue,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+	}
+}
+
+func setConfigValue(_ *cobra.Command, args []string) error {
+	s, err := getSetting(args[0])
+	if err != nil {
+		return err
+	}
+	if s.readOnlyHint != "" {
+		return fmt.Errorf("%s can't be set here. Use '%s' instead", s.key, s.readOnlyHint)
+	}
+	value, err := s.parse(args[1])
+	if err != nil {
+		return err
+	}
+	if err := app.Conf.SetConfigValue(s.key, value); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("%s set to %v", s.key, value)
+	return nil
+}
@@ -0,0 +1,198 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package configcmd
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"golang.org/x/mod/semver"
+)
+
+type settingKind int
+
+const (
+	boolSetting settingKind = iota
+	intSetting
+	stringSetting
+)
+
+// setting describes a value of the CLI config file that can be managed with
+// config set/get/list
+type setting struct {
+	key         string
+	kind        settingKind
+	description string
+	// readOnlyHint is set for values managed by other commands, and tells which one
+	readOnlyHint string
+	validate     func(string) error
+}
+
+var settings = []setting{
+	{key: constants.ConfigActiveNetworkKey, kind: stringSetting, description: "network used by default by network aware commands", readOnlyHint: "metal use network"},
+	{key: constants.ConfigActiveSubnetKey, kind: stringSetting, description: "subnet used by default by subnet commands", readOnlyHint: "metal use subnet"},
+	{key: constants.ConfigDefaultKeyKey, kind: stringSetting, description: "stored key used on Tahoe when no key source is given", validate: validateDefaultKey},
+	{key: constants.ConfigLedgerIndexKey, kind: stringSetting, description: "ledger index paying the fees of ledger signed txs", validate: validateLedgerIndex},
+	{key: constants.ConfigAvalancheGoVersionKey, kind: stringSetting, description: "metalgo version used by local networks and new cloud nodes when no version is given", validate: validateVersion},
+	{key: constants.ConfigNonInteractiveKey, kind: boolSetting, description: "fail instead of prompting when some input is missing"},
+	{key: constants.ConfigMetricsEnabledKey, kind: boolSetting, description: "send anonymous usage metrics"},
+	{key: constants.ConfigMetricsEndpointKey, kind: stringSetting, description: "endpoint anonymous usage metrics are posted to as JSON, instead of the default one", validate: validateEndpoint},
+	{key: constants.ConfigSingleNodeEnabledKey, kind: boolSetting, description: "run single node local networks"},
+	{key: constants.ConfigAuthorizeCloudAccessKey, kind: boolSetting, description: "allow creating cloud resources without asking"},
+	{key: constants.ConfigSnapshotAutoKey, kind: boolSetting, description: "snapshot the local network before deploys and upgrades"},
+	{key: constants.ConfigSnapshotKeepKey, kind: intSetting, description: "number of auto snapshots to retain (0 for unlimited)", validate: validateNonNegative},
+	{key: constants.ConfigSnapshotNameTemplateKey, kind: stringSetting, description: "naming template for auto snapshots"},
+	{key: constants.ConfigMaxWeightShareKey, kind: intSetting, description: "validator weight share warning threshold, in percentage", validate: validatePercentage},
+	{key: constants.ConfigMinStakeWeightKey, kind: intSetting, description: "minimum validator stake weight on local networks and devnets, instead of the network's", validate: validatePositive},
+	{key: constants.ConfigMaxStakeWeightKey, kind: intSetting, description: "maximum validator stake weight on local networks and devnets, instead of the network's", validate: validatePositive},
+	{key: constants.ConfigTahoeAPIEndpointKey, kind: stringSetting, description: "API endpoint used for Tahoe, instead of the public one", validate: validateEndpoint},
+	{key: constants.ConfigMainnetAPIEndpointKey, kind: stringSetting, description: "API endpoint used for Mainnet, instead of the public one", validate: validateEndpoint},
+	{key: constants.ConfigDownloadMirrorKey, kind: stringSetting, description: "mirror of github.com used to download metalgo, subnet-evm and relayer releases, verified against the checksums published at github.com", validate: validateMirror},
+	{key: constants.ConfigNotifyWebhookURLKey, kind: stringSetting, description: "Slack compatible webhook notified when state changing commands end", validate: validateEndpoint},
+	{key: constants.ConfigNotifySMTPServerKey, kind: stringSetting, description: "host:port of the SMTP server used to email notifications", validate: validateHostPort},
+	{key: constants.ConfigNotifySMTPUserKey, kind: stringSetting, description: "SMTP user, authenticated with the " + constants.NotifySMTPPasswordEnvVarName + " env var"},
+	{key: constants.ConfigNotifyEmailFromKey, kind: stringSetting, description: "sender of email notifications, defaults to the SMTP user"},
+	{key: constants.ConfigNotifyEmailToKey, kind: stringSetting, description: "comma separated recipients of email notifications"},
+	{key: constants.ConfigFaucetURLKey, kind: stringSetting, description: "faucet used to fund Tahoe keys"},
+	{key: constants.ConfigLocalHTTPPortKey, kind: intSetting, description: "HTTP port of the first local network node", validate: validatePort},
+	{key: constants.ConfigLocalStakingPortKey, kind: intSetting, description: "staking port of the first local network node", validate: validatePort},
+	{key: constants.ConfigLocalHTTPHostKey, kind: stringSetting, description: "address the local network nodes listen on"},
+}
+
+// getSetting returns the setting for key, matched case insensitively
+func getSetting(key string) (setting, error) {
+	for _, s := range settings {
+		if strings.EqualFold(s.key, key) {
+			return s, nil
+		}
+	}
+	return setting{}, fmt.Errorf("unknown config key %q. Run 'metal config list' to see the available keys", key)
+}
+
+// parse validates valueStr and converts it to the type of the setting
+func (s setting) parse(valueStr string) (interface{}, error) {
+	if s.validate != nil {
+		if err := s.validate(valueStr); err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", s.key, err)
+		}
+	}
+	switch s.kind {
+	case boolSetting:
+		value, err := strconv.ParseBool(valueStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %q is not a boolean", s.key, valueStr)
+		}
+		return value, nil
+	case intSetting:
+		value, err := strconv.Atoi(valueStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %q is not an integer", s.key, valueStr)
+		}
+		return value, nil
+	default:
+		return valueStr, nil
+	}
+}
+
+// value returns the current value of the setting, or "" if it is not set
+func (s setting) value() string {
+	if !app.Conf.ConfigValueIsSet(s.key) {
+		return ""
+	}
+	switch s.kind {
+	case boolSetting:
+		return strconv.FormatBool(app.Conf.GetConfigBoolValue(s.key))
+	case intSetting:
+		return strconv.Itoa(app.Conf.GetConfigIntValue(s.key))
+	default:
+		return app.Conf.GetConfigStringValue(s.key)
+	}
+}
+
+func validateDefaultKey(keyName string) error {
+	if keyName == "" {
+		return nil
+	}
+	if !utils.FileExists(app.GetKeyPath(keyName)) {
+		return fmt.Errorf("key %s does not exist", keyName)
+	}
+	return nil
+}
+
+func validateVersion(version string) error {
+	if version != "" && !semver.IsValid(version) {
+		return fmt.Errorf("%q is not a valid version, eg v1.10.0", version)
+	}
+	return nil
+}
+
+func validateNonNegative(valueStr string) error {
+	if value, err := strconv.Atoi(valueStr); err == nil && value < 0 {
+		return fmt.Errorf("must be non negative")
+	}
+	return nil
+}
+
+func validatePositive(valueStr string) error {
+	if value, err := strconv.Atoi(valueStr); err == nil && value < 1 {
+		return fmt.Errorf("must be positive")
+	}
+	return nil
+}
+
+func validatePercentage(valueStr string) error {
+	if value, err := strconv.Atoi(valueStr); err == nil {
+		return utils.ValidatePercentage(value)
+	}
+	return nil
+}
+
+func validatePort(valueStr string) error {
+	if value, err := strconv.Atoi(valueStr); err == nil && (value < 1 || value > 65535) {
+		return fmt.Errorf("must be a valid port number")
+	}
+	return nil
+}
+
+func validateMirror(mirror string) error {
+	if strings.HasPrefix(mirror, "file://") {
+		return nil
+	}
+	return validateEndpoint(mirror)
+}
+
+func validateEndpoint(endpoint string) error {
+	if endpoint == "" {
+		return nil
+	}
+	u, err := url.ParseRequestURI(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%q is not a valid http(s) endpoint", endpoint)
+	}
+	return nil
+}
+
+func validateHostPort(hostPort string) error {
+	if hostPort == "" {
+		return nil
+	}
+	if _, port, err := net.SplitHostPort(hostPort); err != nil || port == "" {
+		return fmt.Errorf("%q is not a valid host:port address", hostPort)
+	}
+	return nil
+}
+
+func validateLedgerIndex(indexStr string) error {
+	if indexStr == "" {
+		return nil
+	}
+	if _, err := strconv.ParseUint(indexStr, 10, 32); err != nil {
+		return fmt.Errorf("%q is not a valid ledger index", indexStr)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package configcmd
+
+import (
+	"testing"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSetting(t *testing.T) {
+	require := require.New(t)
+	s, err := getSetting("noninteractive")
+	require.NoError(err)
+	require.Equal(constants.ConfigNonInteractiveKey, s.key)
+	_, err = getSetting("unknown")
+	require.ErrorContains(err, "unknown config key")
+}
+
+func TestSettingParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         string
+		value       string
+		expected    interface{}
+		expectedErr string
+	}{
+		{name: "bool", key: constants.ConfigNonInteractiveKey, value: "true", expected: true},
+		{name: "invalid bool", key: constants.ConfigNonInteractiveKey, value: "yes please", expectedErr: "is not a boolean"},
+		{name: "int", key: constants.ConfigSnapshotKeepKey, value: "3", expected: 3},
+		{name: "invalid int", key: constants.ConfigSnapshotKeepKey, value: "three", expectedErr: "is not an integer"},
+		{name: "negative int", key: constants.ConfigSnapshotKeepKey, value: "-1", expectedErr: "must be non negative"},
+		{name: "percentage out of range", key: constants.ConfigMaxWeightShareKey, value: "101", expectedErr: "must be between 1 and 100"},
+		{name: "stake weight", key: constants.ConfigMaxStakeWeightKey, value: "1000", expected: 1000},
+		{name: "zero stake weight", key: constants.ConfigMinStakeWeightKey, value: "0", expectedErr: "must be positive"},
+		{name: "port out of range", key: constants.ConfigLocalHTTPPortKey, value: "70000", expectedErr: "must be a valid port number"},
+		{name: "version", key: constants.ConfigAvalancheGoVersionKey, value: "v1.10.0", expected: "v1.10.0"},
+		{name: "clear version", key: constants.ConfigAvalancheGoVersionKey, value: "", expected: ""},
+		{name: "invalid version", key: constants.ConfigAvalancheGoVersionKey, value: "1.10", expectedErr: "is not a valid version"},
+		{name: "ledger index", key: constants.ConfigLedgerIndexKey, value: "3", expected: "3"},
+		{name: "invalid ledger index", key: constants.ConfigLedgerIndexKey, value: "-3", expectedErr: "is not a valid ledger index"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := getSetting(tt.key)
+			require.NoError(t, err)
+			value, err := s.parse(tt.value)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, value)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package configcmd
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/snapshot"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	snapshotKeep         int
+	snapshotAuto         string
+	snapshotNameTemplate string
+)
+
+// avalanche config snapshots command
+func newSnapshotsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshots",
+		Short: "set local network snapshot preferences",
+		Long: `set local network snapshot creation and retention preferences
+
+With --auto enable, a snapshot of the local network is taken before each
+local deploy and upgrade apply. Its name is given by --name-template, which
+supports the {event}, {subnet}, {timestamp} and {gitsha} placeholders, and
+is prefixed by 'auto-'. With --keep N, only the newest N auto snapshots are
+retained.
+
+Without flags, prints the current preferences.`,
+		RunE:         handleSnapshotsSettings,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().IntVar(&snapshotKeep, "keep", -1, "number of auto snapshots to retain (0 for unlimited)")
+	cmd.Flags().StringVar(&snapshotAuto, "auto", "", "[enable | disable] auto snapshots before deploy/upgrade")
+	cmd.Flags().StringVar(&snapshotNameTemplate, "name-template", "", "naming template for auto snapshots (default \""+snapshot.DefaultNameTemplate+"\")")
+	return cmd
+}
+
+func handleSnapshotsSettings(cmd *cobra.Command, _ []string) error {
+	if cmd.Flags().Changed("keep") {
+		if snapshotKeep < 0 {
+			return errors.New("--keep must be non negative")
+		}
+		if err := app.Conf.SetConfigValue(constants.ConfigSnapshotKeepKey, snapshotKeep); err != nil {
+			return err
+		}
+	}
+	switch snapshotAuto {
+	case "":
+	case constants.Enable:
+		if err := app.Conf.SetConfigValue(constants.ConfigSnapshotAutoKey, true); err != nil {
+			return err
+		}
+	case constants.Disable:
+		if err := app.Conf.SetConfigValue(constants.ConfigSnapshotAutoKey, false); err != nil {
+			return err
+		}
+	default:
+		return errors.New("Invalid --auto argument '" + snapshotAuto + "'")
+	}
+	if snapshotNameTemplate != "" {
+		if err := app.Conf.SetConfigValue(constants.ConfigSnapshotNameTemplateKey, snapshotNameTemplate); err != nil {
+			return err
+		}
+	}
+	settings := snapshot.GetSettings(app)
+	keepStr := "unlimited"
+	if settings.Keep > 0 {
+		keepStr = strconv.Itoa(settings.Keep)
+	}
+	ux.Logger.PrintToUser("Auto snapshots: %t", settings.Auto)
+	ux.Logger.PrintToUser("Auto snapshots retained: %s", keepStr)
+	ux.Logger.PrintToUser("Name template: %s", settings.NameTemplate)
+	return nil
+}
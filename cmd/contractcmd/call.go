@@ -0,0 +1,89 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package contractcmd
+
+import (
+	"fmt"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/contract"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var contractAddress string
+
+// metal contract call
+func newCallCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "call <method> [methodArgs...]",
+		Short: "Calls a method of a smart contract deployed into a subnet",
+		Long: `The contract call command calls a method of the smart contract at --address,
+deployed into the blockchain of the Subnet given by --subnet.
+
+Read only (view or pure) methods are evaluated and their outputs printed. Other
+methods are issued as a transaction, paid by the same key as contract deploy.
+Method arguments are given as positional args, with arrays written as JSON arrays.`,
+		SilenceUsage: true,
+		RunE:         callContract,
+		Args:         cobra.MinimumNArgs(1),
+	}
+	addCommonFlags(cmd)
+	cmd.Flags().StringVar(&contractAddress, "address", "", "address of the contract")
+	return cmd
+}
+
+func callContract(_ *cobra.Command, args []string) error {
+	if abiPath == "" {
+		return fmt.Errorf("--abi is required")
+	}
+	if !common.IsHexAddress(contractAddress) {
+		return fmt.Errorf("invalid contract address %q", contractAddress)
+	}
+	contractABI, err := contract.LoadABI(abiPath)
+	if err != nil {
+		return err
+	}
+	methodName, methodArgs := args[0], args[1:]
+	method, ok := contractABI.Methods[methodName]
+	if !ok {
+		return fmt.Errorf("method %q not found in ABI", methodName)
+	}
+	if _, err := contract.ParseArgs(method.Inputs, methodArgs); err != nil {
+		return fmt.Errorf("invalid args for %s: %w", methodName, err)
+	}
+	network, rpcURL, err := getRPCURL()
+	if err != nil {
+		return err
+	}
+	privateKey := ""
+	if !method.IsConstant() {
+		privateKey, err = getPrivateKey(network, "pay for the contract call")
+		if err != nil {
+			return err
+		}
+	}
+	outputs, txHash, err := contract.Call(
+		rpcURL,
+		privateKey,
+		common.HexToAddress(contractAddress),
+		contractABI,
+		methodName,
+		methodArgs,
+	)
+	if err != nil {
+		return err
+	}
+	if !method.IsConstant() {
+		ux.Logger.PrintToUser("Tx hash: %s", txHash.Hex())
+		return nil
+	}
+	for i, output := range outputs {
+		name := method.Outputs[i].Name
+		if name == "" {
+			name = fmt.Sprintf("output%d", i)
+		}
+		ux.Logger.PrintToUser("%s (%s): %s", name, method.Outputs[i].Type, contract.FormatOutput(output))
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package contractcmd
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/key"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
+	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/spf13/cobra"
+)
+
+var (
+	app *application.Avalanche
+
+	supportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Tahoe, networkoptions.Mainnet}
+
+	globalNetworkFlags networkoptions.NetworkFlags
+	subnetName         string
+	keyName            string
+	abiPath            string
+)
+
+// metal contract
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "contract",
+		Short: "Deploy and interact with smart contracts",
+		Long: `The contract command suite provides a collection of tools for deploying
+and calling smart contracts on the blockchains of your Subnet-EVM Subnets.`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+	}
+	app = injectedApp
+	// contract deploy
+	cmd.AddCommand(newDeployCmd())
+	// contract call
+	cmd.AddCommand(newCallCmd())
+	return cmd
+}
+
+// addCommonFlags adds the flags shared by all contract commands
+func addCommonFlags(cmd *cobra.Command) {
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, supportedNetworkOptions)
+	cmd.Flags().StringVar(&subnetName, "subnet", "", "subnet whose blockchain hosts the contract")
+	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to pay for the txs [tahoe/mainnet only]")
+	cmd.Flags().StringVar(&abiPath, "abi", "", "path to the contract ABI, or to a compiler artifact containing it")
+}
+
+// getRPCURL returns the network the subnet blockchain is deployed to, given by
+// flags or prompted, together with the RPC URL of the blockchain
+func getRPCURL() (models.Network, string, error) {
+	if subnetName == "" {
+		return models.UndefinedNetwork, "", fmt.Errorf("--subnet is required")
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return models.UndefinedNetwork, "", fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	if sc.VM != models.SubnetEvm {
+		return models.UndefinedNetwork, "", fmt.Errorf("contracts can only be used on Subnet-EVM based subnets")
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		globalNetworkFlags,
+		false,
+		supportedNetworkOptions,
+		subnetName,
+	)
+	if err != nil {
+		return models.UndefinedNetwork, "", err
+	}
+	blockchainID := sc.Networks[network.Name()].BlockchainID
+	if blockchainID == ids.Empty {
+		return models.UndefinedNetwork, "", fmt.Errorf("subnet %s is not deployed to %s", subnetName, network.Name())
+	}
+	return network, network.BlockchainEndpoint(blockchainID.String()), nil
+}
+
+// getPrivateKey returns the hex encoded private key that pays for the txs. The
+// ewoq key is used on local networks
+func getPrivateKey(network models.Network, goal string) (string, error) {
+	var (
+		k   *key.SoftKey
+		err error
+	)
+	if network.Kind == models.Local && keyName == "" {
+		k, err = key.LoadEwoq(network.ID)
+	} else {
+		if keyName == "" {
+			keyName, err = prompts.CaptureKeyName(app.Prompt, goal, app.GetKeyDir())
+			if err != nil {
+				return "", err
+			}
+		}
+		k, err = key.LoadSoft(network.ID, app.GetKeyPath(keyName))
+	}
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(k.Raw()), nil
+}
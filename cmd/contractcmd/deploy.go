@@ -0,0 +1,70 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package contractcmd
+
+import (
+	"fmt"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/contract"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var bytecodePath string
+
+// metal contract deploy
+func newDeployCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deploy [constructorArgs...]",
+		Short: "Deploys a smart contract into a subnet",
+		Long: `The contract deploy command deploys a smart contract into the blockchain of
+the Subnet given by --subnet, using the RPC URL recorded for the selected network.
+
+The contract is given by its bytecode (--bytecode) and ABI (--abi), either as
+plain hex and ABI JSON files, or as the artifact files generated by Hardhat or
+Foundry. Constructor arguments are given as positional args, with arrays written
+as JSON arrays, eg '["0x8db9...", "0x1f3a..."]'.
+
+On local networks the deploy is paid by the ewoq key, unless --key is given.
+On Tahoe and Mainnet a stored key, funded on the Subnet blockchain, is used.`,
+		SilenceUsage: true,
+		RunE:         deployContract,
+	}
+	addCommonFlags(cmd)
+	cmd.Flags().StringVar(&bytecodePath, "bytecode", "", "path to the contract bytecode, or to a compiler artifact containing it")
+	return cmd
+}
+
+func deployContract(_ *cobra.Command, args []string) error {
+	if bytecodePath == "" || abiPath == "" {
+		return fmt.Errorf("--bytecode and --abi are required")
+	}
+	bytecode, err := contract.LoadBytecode(bytecodePath)
+	if err != nil {
+		return err
+	}
+	contractABI, err := contract.LoadABI(abiPath)
+	if err != nil {
+		return err
+	}
+	// validate the args before prompting for anything
+	if _, err := contract.ParseArgs(contractABI.Constructor.Inputs, args); err != nil {
+		return fmt.Errorf("invalid constructor args: %w", err)
+	}
+	network, rpcURL, err := getRPCURL()
+	if err != nil {
+		return err
+	}
+	privateKey, err := getPrivateKey(network, "pay for the contract deploy")
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Deploying contract into %s on %s", subnetName, network.Name())
+	address, txHash, err := contract.Deploy(rpcURL, privateKey, contractABI, bytecode, args)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Contract address: %s", address.Hex())
+	ux.Logger.PrintToUser("Deploy tx hash: %s", txHash.Hex())
+	return nil
+}
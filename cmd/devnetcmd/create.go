@@ -0,0 +1,225 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package devnetcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/devnet"
+	"github.com/MetalBlockchain/metal-cli/pkg/localnetworkbackend"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	backend        string
+	numNodes       uint32
+	ttl            time.Duration
+	networkID      uint32
+	metalGoVersion string
+	region         string
+)
+
+// metal devnet create
+func newCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create [devnetName]",
+		Short: "Create a short lived devnet",
+		Long: `The devnet create command starts a new devnet, with its own network ID, a
+generated genesis, and new validator keys for all its nodes. The genesis funds
+the same keys as the local network genesis, such as the ewoq key.
+
+With the docker backend (default), the nodes run as docker compose services on
+this host, using their own port range. With the aws and gcp backends, the nodes
+are created as a node cluster with the devnet name, the same way
+'metal node create --devnet' does, and --region is required.
+
+The devnet is destroyed automatically once --ttl is over, by a background
+process started by this command. Devnets expired while that process was not
+running are destroyed by the next devnet create or list. Use --ttl 0 to keep
+the devnet until 'metal devnet destroy'.`,
+		RunE:         createDevnet,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&backend, "backend", devnet.Docker, fmt.Sprintf("where to run the devnet nodes: one of %s", strings.Join(devnet.Backends(), ", ")))
+	cmd.Flags().Uint32Var(&numNodes, "num-nodes", constants.DevnetNumNodes, "number of nodes, all of them validators")
+	cmd.Flags().DurationVar(&ttl, "ttl", constants.DefaultDevnetTTL, "time after which the devnet is destroyed, 0 to keep it")
+	cmd.Flags().Uint32Var(&networkID, "network-id", 0, "network ID of the devnet (docker only, defaults to a random unused one)")
+	cmd.Flags().StringVar(&metalGoVersion, "metalgo-version", "", "metalgo version to run (defaults to the latest release)")
+	cmd.Flags().StringVar(&region, "region", "", "cloud region to create the nodes in (cloud only)")
+	return cmd
+}
+
+func createDevnet(_ *cobra.Command, args []string) error {
+	name := args[0]
+	if err := devnet.ValidateName(name); err != nil {
+		return ux.NewCodedError(ux.ErrCodeInvalidArguments, err)
+	}
+	if err := validateCreateFlags(); err != nil {
+		return ux.NewCodedError(ux.ErrCodeInvalidArguments, err)
+	}
+	if err := destroyExpiredDevnets(); err != nil {
+		return err
+	}
+	devnets, err := devnet.List(app.GetDevnetsDir())
+	if err != nil {
+		return err
+	}
+	if slices.ContainsFunc(devnets, func(d devnet.Devnet) bool { return d.Name == name }) {
+		return fmt.Errorf("devnet %s already exists", name)
+	}
+
+	now := time.Now()
+	d := devnet.Devnet{
+		Name:      name,
+		Backend:   backend,
+		NetworkID: networkID,
+		NumNodes:  numNodes,
+		CreatedAt: now,
+	}
+	if ttl > 0 {
+		d.ExpiresAt = now.Add(ttl)
+	}
+	if d.IsCloud() {
+		// the cluster would be destroyed with the devnet
+		exists, err := app.ClusterExists(name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("node cluster %s already exists, use another devnet name", name)
+		}
+	} else {
+		if d.NetworkID == 0 {
+			d.NetworkID = devnet.NewNetworkID(devnets)
+		}
+		d.HTTPPort, d.StakingPort = devnet.NewPorts(devnets)
+	}
+	// recorded in advance, so that a partially created devnet can be destroyed
+	if err := devnet.Save(app.GetDevnetsDir(), d); err != nil {
+		return err
+	}
+	if d.IsCloud() {
+		err = createCloudDevnet(&d)
+	} else {
+		err = createDockerDevnet(&d)
+	}
+	if err != nil {
+		if cleanErr := destroyDevnet(d); cleanErr != nil {
+			ux.Logger.PrintToUser("Failed to clean up devnet %s: %s. Use 'metal devnet destroy %s' to retry", name, cleanErr, name)
+		}
+		return err
+	}
+	if err := devnet.Save(app.GetDevnetsDir(), d); err != nil {
+		return err
+	}
+	if err := scheduleTeardown(d); err != nil {
+		return fmt.Errorf("failed to schedule the teardown of devnet %s: %w", name, err)
+	}
+
+	ux.Logger.GreenCheckmarkToUser("Devnet %s created", name)
+	ux.Logger.PrintToUser("Network ID: %d", d.NetworkID)
+	ux.Logger.PrintToUser("Endpoint: %s", d.Endpoint)
+	if !d.IsCloud() {
+		ux.Logger.PrintToUser("Plugin dir: %s", devnet.PluginsDir(app.GetDevnetsDir(), name))
+	}
+	if d.ExpiresAt.IsZero() {
+		ux.Logger.PrintToUser("The devnet is kept until 'metal devnet destroy %s'", name)
+	} else {
+		ux.Logger.PrintToUser("The devnet will be destroyed at %s", d.ExpiresAt.Format(constants.TimeParseLayout))
+	}
+	ux.Logger.PrintToUser("Use it with --devnet --endpoint %s", d.Endpoint)
+	return nil
+}
+
+func validateCreateFlags() error {
+	if !slices.Contains(devnet.Backends(), backend) {
+		return fmt.Errorf("unknown devnet backend %q. Use one of %s", backend, strings.Join(devnet.Backends(), ", "))
+	}
+	if numNodes == 0 || numNodes > constants.DevnetPortRangeSize/2 {
+		return fmt.Errorf("number of nodes must be between 1 and %d", constants.DevnetPortRangeSize/2)
+	}
+	if ttl < 0 {
+		return errors.New("ttl can't be negative")
+	}
+	if networkID != 0 && devnet.IsReservedNetworkID(networkID) {
+		return fmt.Errorf("network ID %d is reserved", networkID)
+	}
+	cloud := backend == devnet.AWS || backend == devnet.GCP
+	if cloud && networkID != 0 {
+		return errors.New("--network-id is only supported by the docker backend")
+	}
+	if cloud && region == "" {
+		return fmt.Errorf("--region is required by the %s backend", backend)
+	}
+	if !cloud && region != "" {
+		return errors.New("--region is only supported by the cloud backends")
+	}
+	return nil
+}
+
+// createDockerDevnet starts the nodes of docker devnet [d] on this host
+func createDockerDevnet(d *devnet.Devnet) error {
+	pluginsDir := devnet.PluginsDir(app.GetDevnetsDir(), d.Name)
+	if err := os.MkdirAll(pluginsDir, constants.DefaultPerms755); err != nil {
+		return err
+	}
+	config := localnetworkbackend.Config{
+		NumNodes:       d.NumNodes,
+		MetalGoVersion: metalGoVersion,
+		HTTPPort:       d.HTTPPort,
+		StakingPort:    d.StakingPort,
+		HTTPHost:       "127.0.0.1",
+		PluginDir:      pluginsDir,
+		NetworkID:      d.NetworkID,
+	}
+	ux.Logger.PrintToUser("Starting devnet %s with %d nodes on docker...", d.Name, d.NumNodes)
+	ctx, cancel := context.WithTimeout(context.Background(), devnetTimeout)
+	defer cancel()
+	nodes, err := dockerBackend(d.Name).Start(ctx, config)
+	if err != nil {
+		return err
+	}
+	d.Endpoint = nodes[0].URI
+	return nil
+}
+
+// createCloudDevnet creates the nodes of cloud devnet [d] as a node cluster
+// with the devnet name
+func createCloudDevnet(d *devnet.Devnet) error {
+	args := []string{
+		"node", "create", d.Name,
+		"--devnet",
+		"--" + d.Backend,
+		"--region", region,
+		"--num-validators", strconv.FormatUint(uint64(d.NumNodes), 10),
+		"--num-apis", "0",
+		"--node-type", "default",
+		"--enable-monitoring=false",
+		"--authorize-access",
+	}
+	if metalGoVersion != "" {
+		args = append(args, "--custom-avalanchego-version", metalGoVersion)
+	} else {
+		args = append(args, "--latest-avalanchego-version")
+	}
+	if err := runCLI(args...); err != nil {
+		return fmt.Errorf("failed to create the nodes of devnet %s: %w", d.Name, err)
+	}
+	clusterConfig, err := app.GetClusterConfig(d.Name)
+	if err != nil {
+		return err
+	}
+	d.NetworkID = clusterConfig.Network.ID
+	d.Endpoint = clusterConfig.Network.Endpoint
+	return nil
+}
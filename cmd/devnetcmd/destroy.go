@@ -0,0 +1,89 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package devnetcmd
+
+import (
+	"errors"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/devnet"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// atExpiryFlag makes devnet destroy wait until the devnet TTL is over. It is
+// given to the teardown process started by devnet create
+const atExpiryFlag = "at-expiry"
+
+var (
+	destroyExpired bool
+	atExpiry       bool
+)
+
+// metal devnet destroy
+func newDestroyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "destroy [devnetName]",
+		Short: "Destroy a devnet",
+		Long: `The devnet destroy command stops and removes the nodes of a devnet, and all its
+files. For cloud devnets, the node cluster is destroyed with 'metal node destroy'.
+
+Use --expired to destroy all the devnets whose TTL is over.`,
+		RunE:         destroyDevnetCmd,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().BoolVar(&destroyExpired, "expired", false, "destroy all expired devnets")
+	cmd.Flags().BoolVar(&atExpiry, atExpiryFlag, false, "wait until the devnet expires before destroying it")
+	_ = cmd.Flags().MarkHidden(atExpiryFlag)
+	return cmd
+}
+
+func destroyDevnetCmd(_ *cobra.Command, args []string) error {
+	switch {
+	case destroyExpired && len(args) > 0:
+		return ux.NewCodedError(ux.ErrCodeInvalidArguments, errors.New("--expired doesn't take a devnet name"))
+	case destroyExpired:
+		return destroyExpiredDevnets()
+	case len(args) == 0:
+		return ux.NewCodedError(ux.ErrCodeInvalidArguments, errors.New("a devnet name is required"))
+	case atExpiry:
+		return destroyAtExpiry(args[0])
+	}
+	d, err := devnet.Load(app.GetDevnetsDir(), args[0])
+	if err != nil {
+		return err
+	}
+	if err := destroyDevnet(d); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Devnet %s destroyed", d.Name)
+	return nil
+}
+
+// destroyAtExpiry waits until devnet [name] expires, and then destroys it. The
+// devnet record is read again after waiting, as it may have been destroyed, or
+// recreated with another TTL, in the meantime
+func destroyAtExpiry(name string) error {
+	// keep running after the terminal that created the devnet is closed
+	signal.Ignore(syscall.SIGHUP)
+	for {
+		d, err := devnet.Load(app.GetDevnetsDir(), name)
+		if errors.Is(err, devnet.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if d.ExpiresAt.IsZero() {
+			return nil
+		}
+		if d.Expired(time.Now()) {
+			ux.Logger.PrintToUser("Destroying devnet %s, expired at %s", d.Name, d.ExpiresAt)
+			return destroyDevnet(d)
+		}
+		time.Sleep(time.Until(d.ExpiresAt))
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package devnetcmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/devnet"
+	"github.com/MetalBlockchain/metal-cli/pkg/localnetworkbackend"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/docker/docker/pkg/reexec"
+	"github.com/spf13/cobra"
+)
+
+// devnetTimeout bounds the docker operations, that may include pulling images
+const devnetTimeout = 10 * time.Minute
+
+// teardownLogFileName keeps, into the devnet dir, the output of the process
+// destroying the devnet when its TTL is over
+const teardownLogFileName = "teardown.log"
+
+var app *application.Avalanche
+
+// metal devnet
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "devnet",
+		Short: "Create and destroy short lived devnets",
+		Long: `The devnet command suite manages devnets: short lived networks fully owned by
+the CLI, meant for ephemeral integration testing.
+
+Each devnet has its own network ID, a generated genesis and a few validators
+with new keys. It runs on local docker containers, or on cloud nodes managed
+as a node cluster with the same name. Devnets are destroyed automatically
+once their TTL is over.`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+		Args: cobra.ExactArgs(0),
+	}
+	// devnet create
+	cmd.AddCommand(newCreateCmd())
+	// devnet destroy
+	cmd.AddCommand(newDestroyCmd())
+	// devnet list
+	cmd.AddCommand(newListCmd())
+	return cmd
+}
+
+// dockerBackend returns the local network backend running docker devnet [name]
+func dockerBackend(name string) localnetworkbackend.Backend {
+	return localnetworkbackend.NewDockerBackend(devnet.NetworkDir(app.GetDevnetsDir(), name), "metal-devnet-"+name)
+}
+
+// runCLI runs this binary with [args], attached to the user terminal
+func runCLI(args ...string) error {
+	cmd := exec.Command(reexec.Self(), append(args, "--"+constants.SkipUpdateFlag)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// destroyDevnet removes the nodes of devnet [d] and its files
+func destroyDevnet(d devnet.Devnet) error {
+	if d.IsCloud() {
+		exists, err := app.ClusterExists(d.Name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			if err := runCLI("node", "destroy", d.Name, "--authorize-all"); err != nil {
+				return fmt.Errorf("failed to destroy the nodes of devnet %s: %w", d.Name, err)
+			}
+		}
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), devnetTimeout)
+		defer cancel()
+		if err := dockerBackend(d.Name).Clean(ctx); err != nil {
+			return fmt.Errorf("failed to destroy the nodes of devnet %s: %w", d.Name, err)
+		}
+	}
+	return devnet.Remove(app.GetDevnetsDir(), d.Name)
+}
+
+// destroyExpiredDevnets destroys the devnets whose TTL is over. It covers the
+// devnets the teardown process could not destroy, eg after a reboot
+func destroyExpiredDevnets() error {
+	devnets, err := devnet.List(app.GetDevnetsDir())
+	if err != nil {
+		return err
+	}
+	for _, d := range devnets {
+		if !d.Expired(time.Now()) {
+			continue
+		}
+		ux.Logger.PrintToUser("Destroying devnet %s, expired at %s", d.Name, d.ExpiresAt.Format(constants.TimeParseLayout))
+		if err := destroyDevnet(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scheduleTeardown starts a background process that destroys devnet [d] once
+// its TTL is over
+func scheduleTeardown(d devnet.Devnet) error {
+	if d.ExpiresAt.IsZero() {
+		return nil
+	}
+	logFile, err := os.Create(filepath.Join(devnet.Dir(app.GetDevnetsDir(), d.Name), teardownLogFileName))
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+	cmd := exec.Command(reexec.Self(), "devnet", "destroy", d.Name, "--"+atExpiryFlag, "--"+constants.SkipUpdateFlag)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
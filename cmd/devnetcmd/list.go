@@ -0,0 +1,70 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package devnetcmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
+	"github.com/MetalBlockchain/metal-cli/pkg/devnet"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// metal devnet list
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List devnets",
+		Long: `The devnet list command lists the devnets, oldest first, with their backend,
+network ID, endpoint and remaining lifetime. Expired devnets are destroyed
+before listing.`,
+		RunE:         listDevnets,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	flags.AddJSONOutputFlag(cmd)
+	return cmd
+}
+
+func listDevnets(*cobra.Command, []string) error {
+	if err := destroyExpiredDevnets(); err != nil {
+		return err
+	}
+	devnets, err := devnet.List(app.GetDevnetsDir())
+	if err != nil {
+		return err
+	}
+	if ux.JSONOutput() {
+		return ux.PrintResult(devnets)
+	}
+	if len(devnets) == 0 {
+		ux.Logger.PrintToUser("No devnets found")
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Name", "Backend", "Network ID", "Nodes", "Endpoint", "Expires In"})
+	for _, d := range devnets {
+		table.Append([]string{
+			d.Name,
+			d.Backend,
+			fmt.Sprintf("%d", d.NetworkID),
+			fmt.Sprintf("%d", d.NumNodes),
+			d.Endpoint,
+			expiresIn(d, time.Now()),
+		})
+	}
+	table.Render()
+	return nil
+}
+
+// expiresIn describes the remaining lifetime of [d] at [now]
+func expiresIn(d devnet.Devnet, now time.Time) string {
+	if d.ExpiresAt.IsZero() {
+		return "never"
+	}
+	return d.ExpiresAt.Sub(now).Round(time.Second).String()
+}
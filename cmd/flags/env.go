@@ -0,0 +1,37 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package flags
+
+import (
+	"fmt"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
+	"github.com/spf13/pflag"
+)
+
+// ApplyEnvOverrides sets each flag of flagSet that was not given on the command
+// line from its env var, if present (see prompts.EnvVarName), so commands can
+// be fully scripted without passing flags. Env vars in reserved are skipped, as
+// they have another meaning
+func ApplyEnvOverrides(flagSet *pflag.FlagSet, reserved ...string) error {
+	var err error
+	flagSet.VisitAll(func(flag *pflag.Flag) {
+		if err != nil || flag.Changed || flag.Name == "help" {
+			return
+		}
+		envVarName := prompts.EnvVarName(flag.Name)
+		for _, r := range reserved {
+			if envVarName == r {
+				return
+			}
+		}
+		value, ok := prompts.EnvValue(envVarName)
+		if !ok {
+			return
+		}
+		if setErr := flagSet.Set(flag.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid value %q of env var %s for flag --%s: %w", value, envVarName, flag.Name, setErr)
+		}
+	})
+	return err
+}
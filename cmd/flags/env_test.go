@@ -0,0 +1,65 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package flags
+
+import (
+	"testing"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvVarName(t *testing.T) {
+	require := require.New(t)
+	require.Equal("METAL_KEY", prompts.EnvVarName("key"))
+	require.Equal("METAL_NODE_ID", prompts.EnvVarName("nodeID"))
+	require.Equal("METAL_NODE_ID", prompts.EnvVarName("node-id"))
+	require.Equal("METAL_LEDGER_ADDRS", prompts.EnvVarName("ledger-addrs"))
+	require.Equal("METAL_SUBNET_EVM_VERSION", prompts.EnvVarName("subnet-evm-version"))
+}
+
+func TestApplyEnvOverrides(t *testing.T) {
+	require := require.New(t)
+	var (
+		key    string
+		tahoe  bool
+		weight uint64
+		output string
+	)
+	newFlagSet := func() *pflag.FlagSet {
+		flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flagSet.StringVar(&key, "key", "", "")
+		flagSet.BoolVar(&tahoe, "tahoe", false, "")
+		flagSet.Uint64Var(&weight, "weight", 0, "")
+		flagSet.StringVar(&output, "output", "", "")
+		return flagSet
+	}
+
+	t.Setenv("METAL_KEY", "envKey")
+	t.Setenv("METAL_TAHOE", "true")
+	t.Setenv("METAL_OUTPUT", "json")
+
+	// env values are used for flags not given on the command line
+	flagSet := newFlagSet()
+	require.NoError(flagSet.Parse([]string{"--weight", "20"}))
+	require.NoError(ApplyEnvOverrides(flagSet, "METAL_OUTPUT"))
+	require.Equal("envKey", key)
+	require.True(tahoe)
+	require.True(flagSet.Changed("tahoe"))
+	require.Equal(uint64(20), weight)
+	require.Equal("", output)
+
+	// command line flags take precedence
+	flagSet = newFlagSet()
+	require.NoError(flagSet.Parse([]string{"--key", "flagKey"}))
+	require.NoError(ApplyEnvOverrides(flagSet))
+	require.Equal("flagKey", key)
+	require.Equal("json", output)
+
+	// invalid values are reported with the env var name
+	t.Setenv("METAL_WEIGHT", "heavy")
+	flagSet = newFlagSet()
+	require.NoError(flagSet.Parse(nil))
+	require.ErrorContains(ApplyEnvOverrides(flagSet), "METAL_WEIGHT")
+}
@@ -0,0 +1,52 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package flags
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+const JSONFlag = "json"
+
+// AddJSONOutputFlag adds --json to cmd. Only commands that print their results
+// with ux.PrintResult register it
+func AddJSONOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool(JSONFlag, false, "print command results and errors as JSON (also enabled by METAL_OUTPUT=json)")
+}
+
+// JSONOutputRequested returns true if cmd supports JSON output, and it was
+// selected either with --json or with METAL_OUTPUT=json
+func JSONOutputRequested(cmd *cobra.Command) bool {
+	if cmd.Flags().Lookup(JSONFlag) == nil {
+		return false
+	}
+	enabled, err := cmd.Flags().GetBool(JSONFlag)
+	return (err == nil && enabled) || ux.JSONOutputFromEnv()
+}
+
+// JSONOutputRequestedInArgs is JSONOutputRequested for when the flags of cmd
+// failed to parse, looking for --json in the raw command line [args]
+func JSONOutputRequestedInArgs(cmd *cobra.Command, args []string) bool {
+	if cmd.Flags().Lookup(JSONFlag) == nil {
+		return false
+	}
+	enabled := false
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		name, value, hasValue := strings.Cut(arg, "=")
+		if name != "--"+JSONFlag {
+			continue
+		}
+		enabled = true
+		if hasValue {
+			enabled, _ = strconv.ParseBool(value)
+		}
+	}
+	return enabled || ux.JSONOutputFromEnv()
+}
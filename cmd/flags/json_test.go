@@ -0,0 +1,35 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package flags
+
+import (
+	"testing"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONOutputRequested(t *testing.T) {
+	require := require.New(t)
+	unsupported := &cobra.Command{}
+	supported := &cobra.Command{}
+	AddJSONOutputFlag(supported)
+	require.False(JSONOutputRequested(unsupported))
+	require.False(JSONOutputRequested(supported))
+	require.NoError(supported.ParseFlags([]string{"--json"}))
+	require.True(JSONOutputRequested(supported))
+	require.Error(unsupported.ParseFlags([]string{"--json"}))
+
+	require.True(JSONOutputRequestedInArgs(supported, []string{"subnet", "describe", "--json", "--bad-flag"}))
+	require.True(JSONOutputRequestedInArgs(supported, []string{"--json=true", "--bad-flag"}))
+	require.False(JSONOutputRequestedInArgs(supported, []string{"--json=false", "--bad-flag"}))
+	require.False(JSONOutputRequestedInArgs(supported, []string{"--bad-flag", "--", "--json"}))
+	require.False(JSONOutputRequestedInArgs(unsupported, []string{"--json", "--bad-flag"}))
+
+	t.Setenv(ux.OutputEnvVarName, ux.JSONOutputFormat)
+	require.False(JSONOutputRequested(unsupported))
+	other := &cobra.Command{}
+	AddJSONOutputFlag(other)
+	require.True(JSONOutputRequested(other))
+}
@@ -0,0 +1,13 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package flags
+
+import "github.com/spf13/cobra"
+
+const LocalNetworkNameFlag = "local-network-name"
+
+// AddLocalNetworkNameFlag adds --local-network-name to cmd, to operate on the named
+// local network set on [name] instead of the default one
+func AddLocalNetworkNameFlag(cmd *cobra.Command, name *string, usage string) {
+	cmd.Flags().StringVar(name, LocalNetworkNameFlag, "", usage)
+}
@@ -0,0 +1,13 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package flags
+
+import "github.com/spf13/cobra"
+
+const SignerFlag = "signer"
+
+// AddSignerFlag adds --signer to cmd, to sign its txs with the remote signer set
+// on [signerSpec] instead of a stored key or a ledger
+func AddSignerFlag(cmd *cobra.Command, signerSpec *string) {
+	cmd.Flags().StringVar(signerSpec, SignerFlag, "", "use a KMS key instead of key or ledger: kms:<aws key>, gcpkms:<gcp key version> or vault:<mount>/<key>")
+}
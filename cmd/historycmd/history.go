@@ -0,0 +1,134 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package historycmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/audit"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+const defaultHistoryLimit = 20
+
+var (
+	app *application.Avalanche
+
+	limit         int
+	commandFilter string
+	since         string
+	failedOnly    bool
+)
+
+// metal history
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show the log of state changing operations",
+		Long: `The history command prints the audit log of the state changing operations done
+with the CLI, such as deploys, validator txs, key creation and deletion, and local
+network operations, newest first.
+
+Each entry has the time, the command line with secrets redacted, whether it
+succeeded, and the IDs of the txs it issued. The log is append only, and kept in
+the CLI base dir.`,
+		RunE:         printHistory,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+	}
+	cmd.Flags().IntVar(&limit, "limit", defaultHistoryLimit, "maximum number of entries to show, 0 for all")
+	cmd.Flags().StringVar(&commandFilter, "command", "", "only show entries of commands containing this text, eg \"subnet deploy\"")
+	cmd.Flags().StringVar(&since, "since", "", "only show entries newer than this duration (eg 24h) or date (YYYY-MM-DD)")
+	cmd.Flags().BoolVar(&failedOnly, "failed", false, "only show failed operations")
+	flags.AddJSONOutputFlag(cmd)
+	return cmd
+}
+
+// parseSince returns the time [value] refers to, given as a duration before [now]
+// or as a date
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	for _, layout := range []string{time.RFC3339, time.DateTime, time.DateOnly} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid --since value %q, expected a duration such as 24h or a date such as 2024-01-31", value)
+}
+
+// filterEntries returns the entries of [entries] that match the given filters,
+// newest first, at most [limit] of them if [limit] is positive
+func filterEntries(entries []audit.Entry, command string, after time.Time, failedOnly bool, limit int) []audit.Entry {
+	filtered := []audit.Entry{}
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if command != "" && !strings.Contains(entry.Command, command) {
+			continue
+		}
+		if !after.IsZero() && entry.Time.Before(after) {
+			continue
+		}
+		if failedOnly && entry.Success {
+			continue
+		}
+		filtered = append(filtered, entry)
+		if limit > 0 && len(filtered) == limit {
+			break
+		}
+	}
+	return filtered
+}
+
+func printHistory(_ *cobra.Command, _ []string) error {
+	if limit < 0 {
+		return ux.NewCodedError(ux.ErrCodeInvalidArguments, fmt.Errorf("--limit can't be negative"))
+	}
+	after := time.Time{}
+	if since != "" {
+		var err error
+		after, err = parseSince(since, time.Now())
+		if err != nil {
+			return ux.NewCodedError(ux.ErrCodeInvalidArguments, err)
+		}
+	}
+	entries, err := audit.Read(app.GetAuditLogPath())
+	if err != nil {
+		return err
+	}
+	entries = filterEntries(entries, commandFilter, after, failedOnly, limit)
+	if ux.JSONOutput() {
+		return ux.PrintResult(entries)
+	}
+	if len(entries) == 0 {
+		ux.Logger.PrintToUser("No operations recorded")
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Time", "Command", "Result", "Tx IDs"})
+	table.SetRowLine(true)
+	table.SetAutoWrapText(false)
+	for _, entry := range entries {
+		result := "ok"
+		if !entry.Success {
+			result = "failed: " + entry.Error
+		}
+		table.Append([]string{
+			entry.Time.Local().Format(time.DateTime),
+			strings.Join(entry.Args, " "),
+			result,
+			strings.Join(entry.TxIDs, "\n"),
+		})
+	}
+	table.Render()
+	return nil
+}
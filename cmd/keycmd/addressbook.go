@@ -0,0 +1,196 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package keycmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metalgo/utils/formatting/address"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	addressBookDescription string
+	forceAddressBookAdd    bool
+	forceAddressBookRemove bool
+)
+
+// avalanche key addressbook
+func newAddressBookCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "addressbook",
+		Short: "Manage labeled addresses",
+		Long: `The key addressbook command suite stores addresses under a label, so that
+they can be referenced by it instead of by the raw address. This is useful
+for addresses you don't own the key of, eg your teammates' control keys.
+
+Labels are accepted by the --control-keys and --subnet-auth-keys flags of
+subnet deploy and subnet changeOwner, and by the --target-addr flag of
+key transfer.`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+	}
+	cmd.AddCommand(newAddressBookAddCmd())
+	cmd.AddCommand(newAddressBookListCmd())
+	cmd.AddCommand(newAddressBookRemoveCmd())
+	return cmd
+}
+
+// avalanche key addressbook add
+func newAddressBookAddCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add [label] [address]",
+		Short: "Add a labeled address to the address book",
+		Long: `The key addressbook add command stores [address] under [label].
+
+The address can be a P-Chain or X-Chain bech32 address (eg P-tahoe1...), or
+a C-Chain hex address. Use the --force flag to replace an existing label.`,
+		RunE:         addAddressBookEntry,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&addressBookDescription, "description", "", "optional description of the address")
+	cmd.Flags().BoolVarP(&forceAddressBookAdd, forceFlag, "f", false, "overwrite an existing label")
+	return cmd
+}
+
+// avalanche key addressbook list
+func newAddressBookListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "list",
+		Short:        "List the address book entries",
+		Long:         `The key addressbook list command prints all the labeled addresses.`,
+		RunE:         listAddressBook,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+}
+
+// avalanche key addressbook remove
+func newAddressBookRemoveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove [label]",
+		Short: "Remove a labeled address from the address book",
+		Long: `The key addressbook remove command deletes [label] from the address book.
+
+The command prompts for confirmation. To skip it, provide the --force flag.`,
+		RunE:         removeAddressBookEntry,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().BoolVarP(&forceAddressBookRemove, forceFlag, "f", false, "remove the label without confirmation")
+	return cmd
+}
+
+func validateAddressBookLabel(label string) error {
+	switch {
+	case label == "":
+		return fmt.Errorf("label can't be empty")
+	case strings.ContainsAny(label, ", \t\n"):
+		return fmt.Errorf("label %q can't contain commas or spaces", label)
+	case isBech32Address(label):
+		return fmt.Errorf("label %q can't be an address", label)
+	case common.IsHexAddress(label):
+		return fmt.Errorf("label %q can't be an address", label)
+	}
+	return nil
+}
+
+func isBech32Address(addr string) bool {
+	_, err := address.ParseToID(addr)
+	return err == nil
+}
+
+func validateAddressBookAddress(addr string) error {
+	if isBech32Address(addr) || common.IsHexAddress(addr) {
+		return nil
+	}
+	return fmt.Errorf("invalid address %q: expected a P/X-Chain bech32 address or a C-Chain hex address", addr)
+}
+
+func addAddressBookEntry(_ *cobra.Command, args []string) error {
+	label := args[0]
+	addr := strings.TrimSpace(args[1])
+	if err := validateAddressBookLabel(label); err != nil {
+		return err
+	}
+	if err := validateAddressBookAddress(addr); err != nil {
+		return err
+	}
+	addressBook, err := app.LoadAddressBook()
+	if err != nil {
+		return err
+	}
+	if _, ok := addressBook[label]; ok && !forceAddressBookAdd {
+		return fmt.Errorf("label %s already exists. Use --%s to overwrite it", label, forceFlag)
+	}
+	if otherLabel, ok := addressBook.LabelOf(addr); ok && otherLabel != label {
+		ux.Logger.PrintToUser("Note: address %s is also stored under label %s", addr, otherLabel)
+	}
+	addressBook[label] = models.AddressBookEntry{
+		Address:     addr,
+		Description: addressBookDescription,
+	}
+	if err := app.WriteAddressBook(addressBook); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Address %s saved as %s", addr, label)
+	return nil
+}
+
+func listAddressBook(_ *cobra.Command, _ []string) error {
+	addressBook, err := app.LoadAddressBook()
+	if err != nil {
+		return err
+	}
+	if len(addressBook) == 0 {
+		ux.Logger.PrintToUser("The address book is empty. Use 'metal key addressbook add' to add entries")
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Label", "Address", "Description"})
+	table.SetRowLine(true)
+	for _, label := range addressBook.Labels() {
+		entry := addressBook[label]
+		table.Append([]string{label, entry.Address, entry.Description})
+	}
+	table.Render()
+	return nil
+}
+
+func removeAddressBookEntry(_ *cobra.Command, args []string) error {
+	label := args[0]
+	addressBook, err := app.LoadAddressBook()
+	if err != nil {
+		return err
+	}
+	if _, ok := addressBook[label]; !ok {
+		return fmt.Errorf("label %s not found in the address book", label)
+	}
+	if !forceAddressBookRemove {
+		conf, err := app.Prompt.CaptureNoYes(fmt.Sprintf("Are you sure you want to remove %s from the address book?", label))
+		if err != nil {
+			return err
+		}
+		if !conf {
+			ux.Logger.PrintToUser("Remove cancelled")
+			return nil
+		}
+	}
+	delete(addressBook, label)
+	if err := app.WriteAddressBook(addressBook); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Label %s removed", label)
+	return nil
+}
@@ -3,12 +3,17 @@
 package keycmd
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"os"
 	"regexp"
 
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
 	"github.com/MetalBlockchain/metal-cli/pkg/key"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
 
@@ -17,10 +22,21 @@ const (
 )
 
 var (
-	forceCreate bool
-	filename    string
+	forceCreate    bool
+	filename       string
+	createMnemonic bool
+	createCount    int
+	vanityPrefix   string
+	summaryFile    string
 )
 
+// keySummary holds the addresses of a generated key, for the summary file
+type keySummary struct {
+	CChain        string `json:"cChain"`
+	PChainTahoe   string `json:"pChainTahoe"`
+	PChainMainnet string `json:"pChainMainnet"`
+}
+
 func createKey(_ *cobra.Command, args []string) error {
 	keyName := args[0]
 
@@ -28,11 +44,49 @@ func createKey(_ *cobra.Command, args []string) error {
 		return errors.New("key name contains whitespace")
 	}
 
+	if createMnemonic && filename != "" {
+		return errors.New("--mnemonic and --file are mutually exclusive")
+	}
+
+	if createCount < 1 {
+		return errors.New("--count must be positive")
+	}
+	if createCount > 1 || vanityPrefix != "" || summaryFile != "" {
+		if createMnemonic || filename != "" {
+			return errors.New("--count, --vanity-prefix and --summary-file can't be used with --mnemonic or --file")
+		}
+		return createKeys(keyName)
+	}
+
 	if app.KeyExists(keyName) && !forceCreate {
 		return errors.New("key already exists. Use --" + forceFlag + " parameter to overwrite")
 	}
 
-	if filename == "" {
+	switch {
+	case createMnemonic:
+		// Create key from a new mnemonic
+		ux.Logger.PrintToUser("Generating new mnemonic...")
+		mnemonic, err := key.NewMnemonic()
+		if err != nil {
+			return err
+		}
+		k, err := key.LoadSoftFromMnemonic(0, mnemonic, 0)
+		if err != nil {
+			return err
+		}
+		keyPath := app.GetKeyPath(keyName)
+		if err := k.Save(keyPath); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Key created")
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("Mnemonic phrase, write it down and keep it safe. It is the only way to recover the key:")
+		ux.Logger.PrintToUser("")
+		// printed to stdout only, so the phrase is not written to the log file
+		fmt.Println("  " + mnemonic)
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("The key is derived at index 0, as wallets do. Recover it with 'key import --mnemonic'")
+	case filename == "":
 		// Create key from scratch
 		ux.Logger.PrintToUser("Generating new key...")
 		k, err := key.NewSoft(0)
@@ -44,7 +98,7 @@ func createKey(_ *cobra.Command, args []string) error {
 			return err
 		}
 		ux.Logger.PrintToUser("Key created")
-	} else {
+	default:
 		// Load key from file
 		// TODO add validation that key is legal
 		ux.Logger.PrintToUser("Loading user key...")
@@ -71,6 +125,84 @@ func createKey(_ *cobra.Command, args []string) error {
 	return nil
 }
 
+// createKeys generates [createCount] keys, named [keyName] or [keyName]-N when
+// more than one, optionally matching [vanityPrefix], and writes their addresses
+// to [summaryFile]
+func createKeys(keyName string) error {
+	prefix := ""
+	if vanityPrefix != "" {
+		var err error
+		prefix, err = key.NormalizeVanityPrefix(vanityPrefix)
+		if err != nil {
+			return err
+		}
+	}
+	keyNames := []string{keyName}
+	if createCount > 1 {
+		keyNames = []string{}
+		for i := 1; i <= createCount; i++ {
+			keyNames = append(keyNames, fmt.Sprintf("%s-%d", keyName, i))
+		}
+	}
+	// fail before generating anything
+	for _, name := range keyNames {
+		if app.KeyExists(name) && !forceCreate {
+			return fmt.Errorf("key %s already exists. Use --%s parameter to overwrite", name, forceFlag)
+		}
+	}
+	if prefix != "" {
+		ux.Logger.PrintToUser("Generating %d keys with C-Chain addresses starting with 0x%s...", len(keyNames), prefix)
+	} else {
+		ux.Logger.PrintToUser("Generating %d keys...", len(keyNames))
+	}
+	summary := map[string]keySummary{}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Name", "C-Chain Address", "P-Chain Address (Tahoe)"})
+	for _, name := range keyNames {
+		var (
+			k   *key.SoftKey
+			err error
+		)
+		if prefix != "" {
+			k, _, err = key.NewSoftWithCPrefix(0, prefix)
+		} else {
+			k, err = key.NewSoft(0)
+		}
+		if err != nil {
+			return err
+		}
+		if err := k.Save(app.GetKeyPath(name)); err != nil {
+			return err
+		}
+		keyAddrs := keySummary{CChain: k.C()}
+		for _, network := range []models.Network{models.NewTahoeNetwork(), models.NewMainnetNetwork()} {
+			networkKey, err := key.NewSoft(network.ID, key.WithPrivateKey(k.Key()))
+			if err != nil {
+				return err
+			}
+			if network.Kind == models.Tahoe {
+				keyAddrs.PChainTahoe = networkKey.P()[0]
+			} else {
+				keyAddrs.PChainMainnet = networkKey.P()[0]
+			}
+		}
+		summary[name] = keyAddrs
+		table.Append([]string{name, keyAddrs.CChain, keyAddrs.PChainTahoe})
+	}
+	table.Render()
+	if summaryFile != "" {
+		bs, err := json.MarshalIndent(summary, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(summaryFile, bs, constants.WriteReadReadPerms); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Key addresses written to %s", summaryFile)
+	}
+	return nil
+}
+
 func newCreateCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "create [keyName]",
@@ -83,7 +215,17 @@ The command works by generating a secp256 key and storing it with the provided k
 can use this key in other commands by providing this keyName.
 
 If you'd like to import an existing key instead of generating one from scratch, provide the
---file flag.`,
+--file flag.
+
+With --mnemonic, a 24 words BIP39 mnemonic phrase is generated and printed, and the key
+is derived from it the same way wallets do, so that it can be recovered from the phrase
+with key import --mnemonic.
+
+With --count N, N keys named keyName-1 to keyName-N are generated at once, eg to fund
+test fleets. With --vanity-prefix, keys are generated until their C-Chain address starts
+with the given hex prefix. Each extra char makes the search 16 times longer, so the
+prefix is limited to 6 chars. --summary-file writes a JSON file mapping the names of the
+generated keys to their C-Chain and P-Chain addresses.`,
 		Args:         cobra.ExactArgs(1),
 		RunE:         createKey,
 		SilenceUsage: true,
@@ -95,6 +237,30 @@ If you'd like to import an existing key instead of generating one from scratch,
 		"",
 		"import the key from an existing key file",
 	)
+	cmd.Flags().BoolVar(
+		&createMnemonic,
+		"mnemonic",
+		false,
+		"generate a mnemonic phrase and derive the key from it",
+	)
+	cmd.Flags().IntVar(
+		&createCount,
+		"count",
+		1,
+		"generate this many keys, named keyName-1 to keyName-N",
+	)
+	cmd.Flags().StringVar(
+		&vanityPrefix,
+		"vanity-prefix",
+		"",
+		"generate keys whose C-Chain address starts with this hex prefix",
+	)
+	cmd.Flags().StringVar(
+		&summaryFile,
+		"summary-file",
+		"",
+		"write the addresses of the generated keys to this JSON file",
+	)
 	cmd.Flags().BoolVarP(
 		&forceCreate,
 		forceFlag,
@@ -3,14 +3,22 @@
 package keycmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	exportFilename string
+	forceExport    bool
+)
+
 func newExportCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "export [keyName]",
@@ -19,19 +27,29 @@ func newExportCmd() *cobra.Command {
 applications or import it into another instance of Metal-CLI.
 
 By default, the tool writes the hex encoded key to stdout. If you provide the --output
-flag, the command writes the key to a file of your choosing.`,
+flag, the command writes the key to a file of your choosing, readable only by the user.
+
+As anyone with the exported key can spend its funds, the command prompts for
+confirmation before exporting. To skip the confirmation, provide the --force flag.`,
 		Args:         cobra.ExactArgs(1),
 		RunE:         exportKey,
 		SilenceUsage: true,
 	}
 
 	cmd.Flags().StringVarP(
-		&filename,
+		&exportFilename,
 		"output",
 		"o",
 		"",
 		"write the key to the provided file path",
 	)
+	cmd.Flags().BoolVarP(
+		&forceExport,
+		forceFlag,
+		"f",
+		false,
+		"export the key without confirmation, overwriting the output file if it exists",
+	)
 
 	return cmd
 }
@@ -39,16 +57,39 @@ flag, the command writes the key to a file of your choosing.`,
 func exportKey(_ *cobra.Command, args []string) error {
 	keyName := args[0]
 
+	if !app.KeyExists(keyName) {
+		return errors.New("key does not exist")
+	}
 	keyPath := app.GetKeyPath(keyName)
 	keyBytes, err := os.ReadFile(keyPath)
 	if err != nil {
 		return err
 	}
 
-	if filename == "" {
+	if exportFilename != "" && utils.FileExists(exportFilename) && !forceExport {
+		return fmt.Errorf("file %s already exists. Use --%s parameter to overwrite", exportFilename, forceFlag)
+	}
+
+	if !forceExport {
+		ux.Logger.PrintToUser("Anyone with access to the exported key can spend its funds.")
+		conf, err := app.Prompt.CaptureNoYes("Are you sure you want to export " + keyName + "?")
+		if err != nil {
+			return err
+		}
+		if !conf {
+			ux.Logger.PrintToUser("Export cancelled")
+			return nil
+		}
+	}
+
+	if exportFilename == "" {
 		fmt.Println(string(keyBytes))
 		return nil
 	}
 
-	return os.WriteFile(filename, keyBytes, constants.WriteReadReadPerms)
+	if err := os.WriteFile(exportFilename, keyBytes, constants.WriteReadUserOnlyPerms); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Key exported to %s", exportFilename)
+	return nil
 }
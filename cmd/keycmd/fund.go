@@ -0,0 +1,143 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package keycmd
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/MetalBlockchain/coreth/ethclient"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/faucet"
+	"github.com/MetalBlockchain/metal-cli/pkg/key"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metalgo/utils/units"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+const fundPollInterval = 5 * time.Second
+
+var (
+	fundChain   string
+	faucetURL   string
+	fundTimeout time.Duration
+)
+
+// avalanche key fund
+func newFundCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fund [keyName]",
+		Short: "Request test tokens from the Tahoe faucet",
+		Long: `The key fund command requests test tokens from the Tahoe faucet for the
+C-Chain or P-Chain address of the given stored key, waits for them to arrive,
+and prints the resulting balance.
+
+Use --faucet-url to use a different faucet. It's also taken from the
+` + constants.ConfigFaucetURLKey + ` setting of the CLI config file, if present.`,
+		Args:         cobra.ExactArgs(1),
+		RunE:         fundKey,
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&fundChain, "chain", "c", "chain to fund the key address on [c, p]")
+	cmd.Flags().StringVar(&faucetURL, "faucet-url", "", "faucet endpoint to request the tokens from (default "+constants.TahoeFaucetURL+")")
+	cmd.Flags().DurationVar(&fundTimeout, "timeout", 2*time.Minute, "how long to wait for the tokens to arrive")
+	return cmd
+}
+
+func getFaucetURL() string {
+	if faucetURL != "" {
+		return faucetURL
+	}
+	if url := app.Conf.GetConfigStringValue(constants.ConfigFaucetURLKey); url != "" {
+		return url
+	}
+	return constants.TahoeFaucetURL
+}
+
+func fundKey(_ *cobra.Command, args []string) error {
+	keyName := args[0]
+	if !app.KeyExists(keyName) {
+		return errors.New("key does not exist")
+	}
+	network := models.NewTahoeNetwork()
+	sk, err := key.LoadSoft(network.ID, app.GetKeyPath(keyName))
+	if err != nil {
+		return err
+	}
+
+	var (
+		chain      string
+		addr       string
+		getBalance func() (uint64, error)
+	)
+	switch strings.ToLower(fundChain) {
+	case "c":
+		chain = faucet.CChain
+		addr = sk.C()
+		cClient, err := ethclient.Dial(network.CChainEndpoint())
+		if err != nil {
+			return err
+		}
+		getBalance = func() (uint64, error) {
+			return getCChainBalance(cClient, addr)
+		}
+	case "p":
+		chain = faucet.PChain
+		addr = sk.P()[0]
+		pClient := platformvm.NewClient(network.Endpoint)
+		getBalance = func() (uint64, error) {
+			return getPChainBalance(pClient, addr)
+		}
+	default:
+		return fmt.Errorf("unsupported chain %q, expected one of c, p", fundChain)
+	}
+
+	initialBalance, err := getBalance()
+	if err != nil {
+		return err
+	}
+	url := getFaucetURL()
+	ux.Logger.PrintToUser("Requesting funds for %s from %s...", addr, url)
+	txHash, err := faucet.RequestFunds(url, chain, addr)
+	if err != nil {
+		return err
+	}
+	if txHash != "" {
+		ux.Logger.PrintToUser("Faucet tx: %s", txHash)
+	}
+
+	ux.Logger.PrintToUser("Waiting for the funds to arrive...")
+	deadline := time.Now().Add(fundTimeout)
+	balance := initialBalance
+	for balance <= initialBalance {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("funds not received after %s, check the balance later with 'key list'", fundTimeout)
+		}
+		time.Sleep(fundPollInterval)
+		balance, err = getBalance()
+		if err != nil {
+			return err
+		}
+	}
+	ux.Logger.PrintToUser("Received %.9f %s", float64(balance-initialBalance)/float64(units.Avax), constants.AVAXSymbol)
+	ux.Logger.PrintToUser("%s-Chain balance of %s: %.9f %s", chain, addr, float64(balance)/float64(units.Avax), constants.AVAXSymbol)
+	return nil
+}
+
+// returns the C-Chain balance of [addrStr], in nAvax
+func getCChainBalance(cClient ethclient.Client, addrStr string) (uint64, error) {
+	ctx, cancel := utils.GetAPIContext()
+	balance, err := cClient.BalanceAt(ctx, common.HexToAddress(addrStr), nil)
+	cancel()
+	if err != nil {
+		return 0, err
+	}
+	return balance.Div(balance, big.NewInt(int64(units.Avax))).Uint64(), nil
+}
@@ -0,0 +1,156 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package keycmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/key"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importFilename   string
+	importPrivateKey string
+	forceImport      bool
+	importMnemonic   bool
+	mnemonicIndex    uint32
+)
+
+// avalanche key import
+func newImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import [keyName]",
+		Short: "Import an existing private key",
+		Long: `The key import command stores an existing private key under the given keyName,
+so that it can be used in other commands as keys generated with key create.
+
+The private key can be given in hex (optionally prefixed with 0x) or in the
+PrivateKey-... format, either from a file with --file, directly with
+--private-key, or from stdin if none of them is provided (--file - also reads
+from stdin).
+
+With --mnemonic, the input is a BIP39 mnemonic phrase instead, and the key at
+--index is derived from it the same way wallets do (m/44'/9000'/0'/0/index).
+
+The key is validated before being stored. Importing fails if keyName is already
+in use, unless --force is provided, or if the same private key is already stored
+under another name.`,
+		Args:         cobra.ExactArgs(1),
+		RunE:         importKey,
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&importFilename, "file", "", "import the key from the given file (- for stdin)")
+	cmd.Flags().StringVar(&importPrivateKey, "private-key", "", "import the given private key")
+	cmd.Flags().BoolVar(&importMnemonic, "mnemonic", false, "import the key from a mnemonic phrase")
+	cmd.Flags().Uint32Var(&mnemonicIndex, "index", 0, "derivation index of the key to recover from the mnemonic phrase")
+	cmd.Flags().BoolVarP(&forceImport, forceFlag, "f", false, "overwrite an existing key with the same name")
+	return cmd
+}
+
+func importKey(_ *cobra.Command, args []string) error {
+	keyName := args[0]
+	if match, _ := regexp.MatchString("\\s", keyName); match {
+		return errors.New("key name contains whitespace")
+	}
+	if app.KeyExists(keyName) && !forceImport {
+		return errors.New("key already exists. Use --" + forceFlag + " parameter to overwrite")
+	}
+	if importFilename != "" && importPrivateKey != "" {
+		return errors.New("--file and --private-key are mutually exclusive")
+	}
+	if importMnemonic && importPrivateKey != "" {
+		return errors.New("--mnemonic and --private-key are mutually exclusive")
+	}
+
+	var (
+		keyStr string
+		err    error
+	)
+	switch {
+	case importPrivateKey != "":
+		keyStr = importPrivateKey
+	case importFilename != "" && importFilename != "-":
+		keyBytes, err := os.ReadFile(importFilename)
+		if err != nil {
+			return err
+		}
+		keyStr = string(keyBytes)
+	case importMnemonic:
+		keyStr, err = utils.ReadLongString("Enter the mnemonic phrase to recover the key from:")
+		if err != nil {
+			return err
+		}
+	default:
+		keyStr, err = utils.ReadLongString("Enter the private key to import:")
+		if err != nil {
+			return err
+		}
+	}
+
+	var k *key.SoftKey
+	if importMnemonic {
+		k, err = key.LoadSoftFromMnemonic(models.NewMainnetNetwork().ID, keyStr, mnemonicIndex)
+		if err != nil {
+			return err
+		}
+	} else {
+		k, err = key.LoadSoftFromString(models.NewMainnetNetwork().ID, keyStr)
+		if err != nil {
+			return fmt.Errorf("invalid private key: %w", err)
+		}
+	}
+	existingKeyName, err := findStoredKey(k.Raw())
+	if err != nil {
+		return err
+	}
+	if existingKeyName != "" && existingKeyName != keyName {
+		return fmt.Errorf("private key is already stored as %q", existingKeyName)
+	}
+
+	if err := os.MkdirAll(app.GetKeyDir(), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	if err := k.Save(app.GetKeyPath(keyName)); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Key %s imported", keyName)
+	ux.Logger.PrintToUser("P-Chain address (Mainnet): %s", k.P()[0])
+	ux.Logger.PrintToUser("C-Chain address: %s", k.C())
+	return nil
+}
+
+// returns the name of the stored key with the given private key bytes, if any
+func findStoredKey(privKeyRaw []byte) (string, error) {
+	files, err := os.ReadDir(app.GetKeyDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name(), constants.KeySuffix) {
+			continue
+		}
+		k, err := key.LoadSoft(0, filepath.Join(app.GetKeyDir(), f.Name()))
+		if err != nil {
+			// skip unreadable keys, they can't collide
+			continue
+		}
+		if bytes.Equal(k.Raw(), privKeyRaw) {
+			return strings.TrimSuffix(f.Name(), constants.KeySuffix), nil
+		}
+	}
+	return "", nil
+}
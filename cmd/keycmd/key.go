@@ -34,6 +34,9 @@ To get started, use the key create command.`,
 	// avalanche key create
 	cmd.AddCommand(newCreateCmd())
 
+	// avalanche key import
+	cmd.AddCommand(newImportCmd())
+
 	// avalanche key list
 	cmd.AddCommand(newListCmd())
 
@@ -43,8 +46,26 @@ To get started, use the key create command.`,
 	// avalanche key export
 	cmd.AddCommand(newExportCmd())
 
+	// avalanche key fund
+	cmd.AddCommand(newFundCmd())
+
 	// avalanche key transfer
 	cmd.AddCommand(newTransferCmd())
 
+	// avalanche key sign
+	cmd.AddCommand(newSignCmd())
+
+	// avalanche key verify
+	cmd.AddCommand(newVerifyCmd())
+
+	// avalanche key addressbook
+	cmd.AddCommand(newAddressBookCmd())
+
+	// avalanche key ledger
+	cmd.AddCommand(newLedgerCmd())
+
+	// avalanche key role
+	cmd.AddCommand(newRoleCmd())
+
 	return cmd
 }
@@ -0,0 +1,142 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package keycmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+const defaultLedgerListCount = 10
+
+var (
+	ledgerNetworkFlags            networkoptions.NetworkFlags
+	ledgerSupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Mainnet, networkoptions.Tahoe, networkoptions.Local}
+	ledgerListStart               uint32
+	ledgerListCount               uint32
+)
+
+// avalanche key ledger
+func newLedgerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ledger",
+		Short: "List ledger addresses and select the index to use",
+		Long: `The key ledger command suite lists the P-Chain addresses derived by a ledger,
+and selects which index pays the fees of ledger signed operations.`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			if err := cmd.Help(); err != nil {
+				fmt.Println(err)
+			}
+		},
+	}
+	// avalanche key ledger list
+	cmd.AddCommand(newLedgerListCmd())
+	// avalanche key ledger use
+	cmd.AddCommand(newLedgerUseCmd())
+	return cmd
+}
+
+// avalanche key ledger list
+func newLedgerListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the P-Chain addresses of a ledger",
+		Long: `The key ledger list command derives --count P-Chain addresses from the
+connected ledger, starting at index --start, and prints them with their
+balances. The index selected with key ledger use is marked.`,
+		RunE:         listLedgerAddresses,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &ledgerNetworkFlags, false, ledgerSupportedNetworkOptions)
+	cmd.Flags().Uint32Var(&ledgerListStart, "start", 0, "first ledger index to list")
+	cmd.Flags().Uint32Var(&ledgerListCount, "count", defaultLedgerListCount, "number of ledger indices to list")
+	cmd.Flags().BoolVar(
+		&showBalances,
+		balancesFlag,
+		true,
+		"query live balances, and show which txs P-Chain balances can pay for",
+	)
+	flags.AddJSONOutputFlag(cmd)
+	return cmd
+}
+
+// avalanche key ledger use
+func newLedgerUseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "use [index]",
+		Short: "Select the ledger index to use",
+		Long: `The key ledger use command selects the ledger index that pays the fees of
+ledger signed operations, instead of searching the ledger for funded indices.
+The index is stored in the CLI config file as LedgerIndex. Clear it with:
+
+  metal config set LedgerIndex ""`,
+		RunE:         useLedgerIndex,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+}
+
+func listLedgerAddresses(*cobra.Command, []string) error {
+	if ledgerListCount == 0 {
+		return errors.New("--count must be positive")
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		ledgerNetworkFlags,
+		false,
+		ledgerSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	selectedIndex, selected, err := app.GetLedgerIndex()
+	if err != nil {
+		return err
+	}
+	indices := make([]uint32, 0, ledgerListCount)
+	for i := uint32(0); i < ledgerListCount; i++ {
+		indices = append(indices, ledgerListStart+i)
+	}
+	pClients, _, _, _, err := getClients([]models.Network{network}, true, false, false, "")
+	if err != nil {
+		return err
+	}
+	addrInfos, err := getLedgerIndicesInfo(pClients, indices, []models.Network{network})
+	if err != nil {
+		return err
+	}
+	if selected {
+		selectedName := fmt.Sprintf("index %d", selectedIndex)
+		for i := range addrInfos {
+			if addrInfos[i].name == selectedName {
+				addrInfos[i].name += " (selected)"
+			}
+		}
+	}
+	if ux.JSONOutput() {
+		return ux.PrintResult(addrInfos)
+	}
+	printAddrInfos(addrInfos)
+	return nil
+}
+
+func useLedgerIndex(_ *cobra.Command, args []string) error {
+	index, err := strconv.ParseUint(args[0], 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid ledger index %q", args[0])
+	}
+	if err := app.SetLedgerIndex(strconv.FormatUint(index, 10)); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Ledger index %d selected. Ledger signed operations will use it to pay fees", index)
+	return nil
+}
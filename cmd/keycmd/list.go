@@ -3,6 +3,7 @@
 package keycmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"os"
@@ -10,12 +11,14 @@ import (
 	"strings"
 
 	"github.com/MetalBlockchain/coreth/ethclient"
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
 	"github.com/MetalBlockchain/metal-cli/cmd/subnetcmd"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
 	"github.com/MetalBlockchain/metal-cli/pkg/key"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
 	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
 	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
 	"github.com/MetalBlockchain/metalgo/ids"
 	ledger "github.com/MetalBlockchain/metalgo/utils/crypto/ledger"
 	"github.com/MetalBlockchain/metalgo/utils/formatting/address"
@@ -35,6 +38,8 @@ const (
 	chainsFlag        = "chains"
 	ledgerIndicesFlag = "ledger"
 	useNanoAvaxFlag   = "use-nano-avax"
+	balancesFlag      = "balances"
+	networkFlag       = "network"
 )
 
 var (
@@ -48,6 +53,8 @@ var (
 	useNanoAvax                 bool
 	ledgerIndices               []uint
 	subnetName                  string
+	showBalances                bool
+	networkName                 string
 )
 
 // avalanche subnet list
@@ -56,7 +63,11 @@ func newListCmd() *cobra.Command {
 		Use:   "list",
 		Short: "List stored signing keys or ledger addresses",
 		Long: `The key list command prints information for all stored signing
-keys or for the ledger addresses associated to certain indices.`,
+keys or for the ledger addresses associated to certain indices.
+
+Live balances are queried for each address, and P-Chain balances are matched
+against the network fees to show which txs (deploy, addValidator) each key can
+pay for. Use --balances=false to just list the addresses.`,
 		RunE:         listKeys,
 		SilenceUsage: true,
 	}
@@ -107,12 +118,25 @@ keys or for the ledger addresses associated to certain indices.`,
 		"",
 		"provide balance information for the given subnet (Subnet-Evm based only)",
 	)
+	cmd.Flags().BoolVar(
+		&showBalances,
+		balancesFlag,
+		true,
+		"query live balances, and show which txs P-Chain balances can pay for",
+	)
+	cmd.Flags().StringVar(
+		&networkName,
+		networkFlag,
+		"",
+		"list addresses for the given network [local, tahoe (alias testnet, fuji), mainnet]",
+	)
 	cmd.Flags().StringVar(
 		&chains,
 		chainsFlag,
 		"pxc",
 		"short way to specify which chains to show information about (p=show p-chain, x=show x-chain, c=show c-chain). defaults to pxc",
 	)
+	flags.AddJSONOutputFlag(cmd)
 	return cmd
 }
 
@@ -175,10 +199,67 @@ type addressInfo struct {
 	address string
 	balance string
 	network string
+	// txs the balance is enough to pay the fees of (P-Chain only)
+	canPay []string
+}
+
+func (addrInfo addressInfo) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Kind    string   `json:"kind"`
+		Name    string   `json:"name"`
+		Chain   string   `json:"chain"`
+		Address string   `json:"address"`
+		Balance string   `json:"balance,omitempty"`
+		CanPay  []string `json:"canPay,omitempty"`
+		Network string   `json:"network"`
+	}{
+		Kind:    addrInfo.kind,
+		Name:    addrInfo.name,
+		Chain:   addrInfo.chain,
+		Address: addrInfo.address,
+		Balance: addrInfo.balance,
+		CanPay:  addrInfo.canPay,
+		Network: addrInfo.network,
+	})
+}
+
+// sets the network flags given by name with --network
+func setNetworkFlagsFromName(networkFlags *networkoptions.NetworkFlags, name string) error {
+	switch strings.ToLower(name) {
+	case "":
+	case "local":
+		networkFlags.UseLocal = true
+	case "tahoe", "testnet", "fuji":
+		networkFlags.UseTahoe = true
+	case "mainnet":
+		networkFlags.UseMainnet = true
+	default:
+		return fmt.Errorf("unsupported network %q, expected one of local, tahoe, mainnet", name)
+	}
+	return nil
+}
+
+// returns the txs whose fees can be paid on [network] with a P-Chain [balance]
+func getPayableTxs(network models.Network, balance uint64) []string {
+	params := network.GenesisParams()
+	if params == nil {
+		return nil
+	}
+	payable := []string{}
+	if balance >= params.CreateSubnetTxFee+params.CreateBlockchainTxFee {
+		payable = append(payable, "deploy")
+	}
+	if balance >= params.AddSubnetValidatorFee {
+		payable = append(payable, "addValidator")
+	}
+	return payable
 }
 
 func listKeys(*cobra.Command, []string) error {
 	var addrInfos []addressInfo
+	if err := setNetworkFlagsFromName(&globalNetworkFlags, networkName); err != nil {
+		return err
+	}
 	networks := []models.Network{}
 	if globalNetworkFlags.UseLocal || all {
 		networks = append(networks, models.NewLocalNetwork())
@@ -243,6 +324,9 @@ func listKeys(*cobra.Command, []string) error {
 			return err
 		}
 	}
+	if ux.JSONOutput() {
+		return ux.PrintResult(addrInfos)
+	}
 	printAddrInfos(addrInfos)
 	return nil
 }
@@ -392,21 +476,27 @@ func getPChainAddrInfo(
 	kind string,
 	name string,
 ) (addressInfo, error) {
-	balance, err := getPChainBalanceStr(pClients[network], pChainAddr)
+	addrInfo := addressInfo{
+		kind:    kind,
+		name:    name,
+		chain:   "P-Chain (Bech32 format)",
+		address: pChainAddr,
+		network: network.Name(),
+	}
+	if !showBalances {
+		return addrInfo, nil
+	}
+	balance, err := getPChainBalance(pClients[network], pChainAddr)
 	if err != nil {
 		// just ignore local network errors
 		if network.Kind != models.Local {
 			return addressInfo{}, err
 		}
+		return addrInfo, nil
 	}
-	return addressInfo{
-		kind:    kind,
-		name:    name,
-		chain:   "P-Chain (Bech32 format)",
-		address: pChainAddr,
-		balance: balance,
-		network: network.Name(),
-	}, nil
+	addrInfo.balance = formatBalance(balance)
+	addrInfo.canPay = getPayableTxs(network, balance)
+	return addrInfo, nil
 }
 
 func getXChainAddrInfo(
@@ -416,7 +506,11 @@ func getXChainAddrInfo(
 	kind string,
 	name string,
 ) (addressInfo, error) {
-	balance, err := getXChainBalanceStr(xClients[network], xChainAddr)
+	balance := ""
+	var err error
+	if showBalances {
+		balance, err = getXChainBalanceStr(xClients[network], xChainAddr)
+	}
 	if err != nil {
 		// just ignore local network errors
 		if network.Kind != models.Local {
@@ -441,7 +535,11 @@ func getEvmBasedChainAddrInfo(
 	kind string,
 	name string,
 ) (addressInfo, error) {
-	cChainBalance, err := getCChainBalanceStr(cClients[network], cChainAddr)
+	cChainBalance := ""
+	var err error
+	if showBalances {
+		cChainBalance, err = getCChainBalanceStr(cClients[network], cChainAddr)
+	}
 	if err != nil {
 		// just ignore local network errors
 		if network.Kind != models.Local {
@@ -459,20 +557,25 @@ func getEvmBasedChainAddrInfo(
 }
 
 func printAddrInfos(addrInfos []addressInfo) {
-	header := []string{"Kind", "Name", "Chain", "Address", "Balance", "Network"}
+	header := []string{"Kind", "Name", "Chain", "Address", "Balance", "Can Pay For", "Network"}
+	if !showBalances {
+		header = []string{"Kind", "Name", "Chain", "Address", "Network"}
+	}
 	table := tablewriter.NewWriter(os.Stdout)
 	table.SetHeader(header)
 	table.SetRowLine(true)
 	table.SetAutoMergeCellsByColumnIndex([]int{0, 1, 2})
 	for _, addrInfo := range addrInfos {
-		table.Append([]string{
+		row := []string{
 			addrInfo.kind,
 			addrInfo.name,
 			addrInfo.chain,
 			addrInfo.address,
-			addrInfo.balance,
-			addrInfo.network,
-		})
+		}
+		if showBalances {
+			row = append(row, addrInfo.balance, strings.Join(addrInfo.canPay, ", "))
+		}
+		table.Append(append(row, addrInfo.network))
 	}
 	table.Render()
 }
@@ -499,27 +602,29 @@ func getCChainBalanceStr(cClient ethclient.Client, addrStr string) (string, erro
 	return balanceStr, nil
 }
 
-func getPChainBalanceStr(pClient platformvm.Client, addr string) (string, error) {
+func getPChainBalance(pClient platformvm.Client, addr string) (uint64, error) {
 	pID, err := address.ParseToID(addr)
 	if err != nil {
-		return "", err
+		return 0, err
 	}
 	ctx, cancel := utils.GetAPIContext()
 	resp, err := pClient.GetBalance(ctx, []ids.ShortID{pID})
 	cancel()
 	if err != nil {
-		return "", err
+		return 0, err
 	}
-	if resp.Balance == 0 {
-		return "0", nil
+	return uint64(resp.Balance), nil
+}
+
+// formats a nAvax [balance] as set by --use-nano-avax
+func formatBalance(balance uint64) string {
+	if balance == 0 {
+		return "0"
 	}
-	balanceStr := ""
 	if useNanoAvax {
-		balanceStr = fmt.Sprintf("%9d", resp.Balance)
-	} else {
-		balanceStr = fmt.Sprintf("%.9f", float64(resp.Balance)/float64(units.Avax))
+		return fmt.Sprintf("%9d", balance)
 	}
-	return balanceStr, nil
+	return fmt.Sprintf("%.9f", float64(balance)/float64(units.Avax))
 }
 
 func getXChainBalanceStr(xClient avm.Client, addr string) (string, error) {
@@ -537,14 +642,5 @@ func getXChainBalanceStr(xClient avm.Client, addr string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	if resp.Balance == 0 {
-		return "0", nil
-	}
-	balanceStr := ""
-	if useNanoAvax {
-		balanceStr = fmt.Sprintf("%9d", resp.Balance)
-	} else {
-		balanceStr = fmt.Sprintf("%.9f", float64(resp.Balance)/float64(units.Avax))
-	}
-	return balanceStr, nil
+	return formatBalance(uint64(resp.Balance)), nil
 }
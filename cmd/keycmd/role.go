@@ -0,0 +1,228 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package keycmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/kms"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	keyRoles    []string
+	keyNetworks []string
+)
+
+// avalanche key role
+func newRoleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "role",
+		Short: "Restrict keys to roles and networks",
+		Long: fmt.Sprintf(`The key role command suite tags stored keys, or KMS signers, with the roles
+they are meant for, and optionally with the networks they can be used on. The
+tags are kept in a local policy file.
+
+The roles are:
+  %s: creates subnets and blockchains, and changes their owners
+  %s: adds, removes and renews subnet validators
+  %s: pays fees and stakes, and transfers funds
+
+When a tagged key is used for another role, or on another network, commands
+print a warning. With 'metal key role mode %s', they fail instead. Keys that
+are not tagged can be used for anything.`,
+			models.KeyRoleDeployer,
+			models.KeyRoleValidatorManager,
+			models.KeyRolePayer,
+			models.KeyPolicyRefuse,
+		),
+		Run: func(cmd *cobra.Command, _ []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+	}
+	cmd.AddCommand(newRoleSetCmd())
+	cmd.AddCommand(newRoleListCmd())
+	cmd.AddCommand(newRoleRemoveCmd())
+	cmd.AddCommand(newRoleModeCmd())
+	return cmd
+}
+
+// avalanche key role set
+func newRoleSetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set [keyName]",
+		Short: "Tag a key with roles",
+		Long: `The key role set command restricts [keyName] to the roles given with --roles,
+and to the networks given with --networks, if any. It replaces the previous
+tags of the key.
+
+[keyName] is a stored key name, or a KMS signer as given to --signer, eg
+kms:alias/subnet-owner.`,
+		RunE:         setKeyRoles,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringSliceVar(&keyRoles, "roles", nil, fmt.Sprintf("roles of the key, among %s", strings.Join(models.KeyRoles(), ", ")))
+	cmd.Flags().StringSliceVar(&keyNetworks, "networks", nil, fmt.Sprintf("networks the key can be used on, among %s (defaults to all)", strings.Join(models.KeyPolicyNetworks(), ", ")))
+	return cmd
+}
+
+// avalanche key role list
+func newRoleListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "list",
+		Short:        "List the key roles",
+		Long:         `The key role list command prints the roles and networks of all tagged keys.`,
+		RunE:         listKeyRoles,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+}
+
+// avalanche key role remove
+func newRoleRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "remove [keyName]",
+		Short:        "Remove the roles of a key",
+		Long:         `The key role remove command removes the tags of [keyName], that can then be used for anything.`,
+		RunE:         removeKeyRoles,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+}
+
+// avalanche key role mode
+func newRoleModeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "mode [warn|refuse]",
+		Short: "Set what happens when a key is used outside its roles",
+		Long: `The key role mode command sets whether commands print a warning (warn, the
+default), or fail (refuse), when a tagged key is used outside its roles or
+networks. Without argument, it prints the current mode.`,
+		RunE:         setKeyPolicyMode,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		ValidArgs:    []string{models.KeyPolicyWarn, models.KeyPolicyRefuse},
+	}
+}
+
+// isSignerSpec returns true if [keyName] selects a KMS signer instead of a stored key
+func isSignerSpec(keyName string) bool {
+	scheme, _, found := strings.Cut(keyName, ":")
+	return found && slices.Contains(kms.Schemes(), scheme)
+}
+
+func setKeyRoles(_ *cobra.Command, args []string) error {
+	keyName := args[0]
+	if !isSignerSpec(keyName) && !app.KeyExists(keyName) {
+		return fmt.Errorf("key %s does not exist", keyName)
+	}
+	if len(keyRoles) == 0 {
+		return fmt.Errorf("at least one role must be given with --roles, among %s", strings.Join(models.KeyRoles(), ", "))
+	}
+	for _, role := range keyRoles {
+		if !slices.Contains(models.KeyRoles(), role) {
+			return fmt.Errorf("unknown role %q. Use one of %s", role, strings.Join(models.KeyRoles(), ", "))
+		}
+	}
+	for _, network := range keyNetworks {
+		if !slices.Contains(models.KeyPolicyNetworks(), network) {
+			return fmt.Errorf("unknown network %q. Use one of %s", network, strings.Join(models.KeyPolicyNetworks(), ", "))
+		}
+	}
+	keyPolicy, err := app.LoadKeyPolicy()
+	if err != nil {
+		return err
+	}
+	keyPolicy.Keys[keyName] = models.KeyPolicyEntry{
+		Roles:    keyRoles,
+		Networks: keyNetworks,
+	}
+	if err := app.WriteKeyPolicy(keyPolicy); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Key %s tagged with role(s) %s", keyName, strings.Join(keyRoles, ", "))
+	if len(keyNetworks) > 0 {
+		ux.Logger.PrintToUser("Key %s restricted to %s", keyName, strings.Join(keyNetworks, ", "))
+	}
+	return nil
+}
+
+func listKeyRoles(_ *cobra.Command, _ []string) error {
+	keyPolicy, err := app.LoadKeyPolicy()
+	if err != nil {
+		return err
+	}
+	if len(keyPolicy.Keys) == 0 {
+		ux.Logger.PrintToUser("No key is tagged with roles. Use 'metal key role set' to tag one")
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Key", "Roles", "Networks"})
+	table.SetRowLine(true)
+	for _, keyName := range keyPolicy.KeyNames() {
+		entry := keyPolicy.Keys[keyName]
+		networks := "all"
+		if len(entry.Networks) > 0 {
+			networks = strings.Join(entry.Networks, ", ")
+		}
+		table.Append([]string{keyName, strings.Join(entry.Roles, ", "), networks})
+	}
+	table.Render()
+	ux.Logger.PrintToUser("Mode: %s", keyPolicyMode(keyPolicy))
+	return nil
+}
+
+func removeKeyRoles(_ *cobra.Command, args []string) error {
+	keyName := args[0]
+	keyPolicy, err := app.LoadKeyPolicy()
+	if err != nil {
+		return err
+	}
+	if _, ok := keyPolicy.Keys[keyName]; !ok {
+		return fmt.Errorf("key %s is not tagged with roles", keyName)
+	}
+	delete(keyPolicy.Keys, keyName)
+	if err := app.WriteKeyPolicy(keyPolicy); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Roles of key %s removed", keyName)
+	return nil
+}
+
+func setKeyPolicyMode(_ *cobra.Command, args []string) error {
+	keyPolicy, err := app.LoadKeyPolicy()
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		ux.Logger.PrintToUser("Mode: %s", keyPolicyMode(keyPolicy))
+		return nil
+	}
+	mode := args[0]
+	if mode != models.KeyPolicyWarn && mode != models.KeyPolicyRefuse {
+		return fmt.Errorf("unknown mode %q. Use %s or %s", mode, models.KeyPolicyWarn, models.KeyPolicyRefuse)
+	}
+	keyPolicy.Mode = mode
+	if err := app.WriteKeyPolicy(keyPolicy); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Key role mode set to %s", mode)
+	return nil
+}
+
+func keyPolicyMode(keyPolicy models.KeyPolicy) string {
+	if keyPolicy.Refuses() {
+		return models.KeyPolicyRefuse
+	}
+	return models.KeyPolicyWarn
+}
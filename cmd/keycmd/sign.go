@@ -0,0 +1,84 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package keycmd
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/key"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+const messageFlag = "message"
+
+var signMessage string
+
+// avalanche key sign
+func newSignCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sign [keyName]",
+		Short: "Sign a message with a stored key",
+		Long: `The key sign command signs an arbitrary message with the given key, so that
+control of the key can be proven to others (eg when assembling the control keys
+of a multisig subnet owner).
+
+The message is given with --message, either as a path to a file, or as hex
+(optionally prefixed with 0x). It is signed the same way wallets sign messages:
+a recoverable secp256k1 signature of sha256("\x1AAvalanche Signed Message:\n" +
+uint32 message length + message), encoded in CB58.
+
+The signature can be checked with key verify.`,
+		Args:         cobra.ExactArgs(1),
+		RunE:         signWithKey,
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&signMessage, messageFlag, "", "message to sign, as a file path or hex")
+	return cmd
+}
+
+// loadMessage returns the contents of file [message] if it exists, or else
+// decodes [message] as hex
+func loadMessage(message string) ([]byte, error) {
+	if message == "" {
+		return nil, fmt.Errorf("a message must be provided with --%s", messageFlag)
+	}
+	if info, err := os.Stat(message); err == nil && !info.IsDir() {
+		return os.ReadFile(message)
+	}
+	msg, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(message), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("message %q is neither an existing file nor hex", message)
+	}
+	return msg, nil
+}
+
+func signWithKey(_ *cobra.Command, args []string) error {
+	keyName := args[0]
+	if !app.KeyExists(keyName) {
+		return errors.New("key does not exist")
+	}
+	msg, err := loadMessage(signMessage)
+	if err != nil {
+		return err
+	}
+	sk, err := key.LoadSoft(models.NewMainnetNetwork().ID, app.GetKeyPath(keyName))
+	if err != nil {
+		return err
+	}
+	signature, err := sk.SignMessage(msg)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Message hash: 0x%s", hex.EncodeToString(key.SignedMessageHash(msg)))
+	if err := printSignerAddresses(sk.Key().PublicKey()); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Signature: %s", signature)
+	return nil
+}
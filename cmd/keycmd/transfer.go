@@ -5,14 +5,19 @@ package keycmd
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/MetalBlockchain/metal-cli/pkg/audit"
 	"github.com/MetalBlockchain/metal-cli/pkg/key"
+	clikeychain "github.com/MetalBlockchain/metal-cli/pkg/keychain"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
 	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
 	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
 	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
 	"github.com/MetalBlockchain/metal-cli/pkg/utils"
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metalgo/api/info"
 	"github.com/MetalBlockchain/metalgo/ids"
 	avagoconstants "github.com/MetalBlockchain/metalgo/utils/constants"
 	"github.com/MetalBlockchain/metalgo/utils/crypto/keychain"
@@ -26,6 +31,7 @@ import (
 	"github.com/MetalBlockchain/metalgo/vms/secp256k1fx"
 	"github.com/MetalBlockchain/metalgo/wallet/subnet/primary"
 	"github.com/MetalBlockchain/metalgo/wallet/subnet/primary/common"
+	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 )
 
@@ -38,6 +44,8 @@ const (
 	amountFlag              = "amount"
 	wrongLedgerIndexVal     = 32768
 	receiveRecoveryStepFlag = "receive-recovery-step"
+	fromChainFlag           = "from"
+	toChainFlag             = "to"
 )
 
 var (
@@ -52,15 +60,22 @@ var (
 	receiveRecoveryStep             uint64
 	PToX                            bool
 	PToP                            bool
+	fromChain                       string
+	toChain                         string
 )
 
 func newTransferCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:          "transfer [options]",
-		Short:        "Fund a ledger address or stored key from another one",
-		Long:         `The key transfer command allows to transfer funds between stored keys or ledger addresses.`,
+		Use:   "transfer [keyName] [options]",
+		Short: "Fund a ledger address or stored key from another one",
+		Long: `The key transfer command allows to transfer funds between stored keys or ledger addresses.
+
+With --from and --to, it instead moves funds of a stored key between its own C-Chain
+and P-Chain addresses, by issuing the export and import atomic txs. For example,
+'key transfer mykey --from c --to p --amount 2' funds the P-Chain address of mykey,
+as needed to deploy or to add validators, from its C-Chain balance.`,
 		RunE:         transferF,
-		Args:         cobra.ExactArgs(0),
+		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 	}
 	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, transferSupportedNetworkOptions)
@@ -122,7 +137,19 @@ func newTransferCmd() *cobra.Command {
 		receiverAddrFlag,
 		"a",
 		"",
-		"receiver address",
+		"receiver address, or address book label",
+	)
+	cmd.Flags().StringVar(
+		&fromChain,
+		fromChainFlag,
+		"",
+		"chain to move the key funds from, for C-Chain <-> P-Chain transfers [c, p]",
+	)
+	cmd.Flags().StringVar(
+		&toChain,
+		toChainFlag,
+		"",
+		"chain to move the key funds to, for C-Chain <-> P-Chain transfers [c, p]",
 	)
 	cmd.Flags().Float64VarP(
 		&amountFlt,
@@ -134,7 +161,28 @@ func newTransferCmd() *cobra.Command {
 	return cmd
 }
 
-func transferF(*cobra.Command, []string) error {
+func transferF(_ *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		if keyName != "" && keyName != args[0] {
+			return fmt.Errorf("key name given both as argument and with --%s", keyNameFlag)
+		}
+		keyName = args[0]
+	}
+
+	if fromChain != "" || toChain != "" {
+		network, err := networkoptions.GetNetworkFromCmdLineFlags(
+			app,
+			globalNetworkFlags,
+			false,
+			transferSupportedNetworkOptions,
+			"",
+		)
+		if err != nil {
+			return err
+		}
+		return crossChainTransfer(network)
+	}
+
 	if send && receive {
 		return fmt.Errorf("only one of %s, %s flags should be selected", sendFlag, receiveFlag)
 	}
@@ -197,10 +245,19 @@ func transferF(*cobra.Command, []string) error {
 			return err
 		}
 		if useLedger {
-			ledgerIndex, err = app.Prompt.CaptureUint32("Ledger index to use")
+			selectedIndex, selected, err := app.GetLedgerIndex()
 			if err != nil {
 				return err
 			}
+			if selected {
+				ux.Logger.PrintToUser("Using selected ledger index %d", selectedIndex)
+				ledgerIndex = selectedIndex
+			} else {
+				ledgerIndex, err = app.Prompt.CaptureUint32("Ledger index to use")
+				if err != nil {
+					return err
+				}
+			}
 		}
 	}
 
@@ -227,6 +284,9 @@ func transferF(*cobra.Command, []string) error {
 
 	var kc keychain.Keychain
 	if keyName != "" {
+		if err := clikeychain.CheckKeyPolicy(app, keyName, models.KeyRolePayer, network); err != nil {
+			return err
+		}
 		keyPath := app.GetKeyPath(keyName)
 		sk, err := key.LoadSoft(network.ID, keyPath)
 		if err != nil {
@@ -260,6 +320,11 @@ func transferF(*cobra.Command, []string) error {
 				}
 			}
 		}
+		resolvedAddrs, err := app.ResolveAddressBookLabels([]string{receiverAddrStr})
+		if err != nil {
+			return err
+		}
+		receiverAddrStr = resolvedAddrs[0]
 		receiverAddr, err = address.ParseToID(receiverAddrStr)
 		if err != nil {
 			return err
@@ -366,6 +431,7 @@ func transferF(*cobra.Command, []string) error {
 			}
 			return err
 		}
+		audit.RecordTx(tx.ID())
 	} else {
 		if receiveRecoveryStep == 0 {
 			wallet, err := primary.MakeWallet(
@@ -413,6 +479,7 @@ func transferF(*cobra.Command, []string) error {
 				ux.Logger.PrintToUser(logging.LightRed.Wrap("ERROR: restart from this step by using the same command"))
 				return err
 			}
+			audit.RecordTx(tx.ID())
 
 			if PToX {
 				return nil
@@ -479,3 +546,136 @@ func transferF(*cobra.Command, []string) error {
 
 	return nil
 }
+
+// crossChainTransfer moves funds of a stored key between its C-Chain and P-Chain addresses
+func crossChainTransfer(network models.Network) error {
+	fromChain = strings.ToLower(fromChain)
+	toChain = strings.ToLower(toChain)
+	cToP := fromChain == "c" && toChain == "p"
+	pToC := fromChain == "p" && toChain == "c"
+	if !cToP && !pToC {
+		return fmt.Errorf("unsupported transfer from %q to %q: --%s and --%s must be c and p, or p and c", fromChain, toChain, fromChainFlag, toChainFlag)
+	}
+	if send || receive || PToP || PToX || receiverAddrStr != "" {
+		return fmt.Errorf("--%s/--%s move funds of the key itself, and can't be used with send/receive options", fromChainFlag, toChainFlag)
+	}
+	if ledgerIndex != wrongLedgerIndexVal {
+		return fmt.Errorf("ledger is not supported for C-Chain transfers")
+	}
+
+	var err error
+	if keyName == "" {
+		var useLedger bool
+		useLedger, keyName, err = prompts.GetFujiKeyOrLedger(app.Prompt, "transfer funds", app.GetKeyDir())
+		if err != nil {
+			return err
+		}
+		if useLedger {
+			return fmt.Errorf("ledger is not supported for C-Chain transfers")
+		}
+	}
+	if amountFlt == 0 {
+		amountFlt, err = app.Prompt.CaptureFloat("Amount to transfer (AVAX units)", func(v float64) error {
+			if v <= 0 {
+				return fmt.Errorf("value %f must be greater than zero", v)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	amount := uint64(amountFlt * float64(units.Avax))
+	fee := network.GenesisParams().TxFee
+
+	if err := clikeychain.CheckKeyPolicy(app, keyName, models.KeyRolePayer, network); err != nil {
+		return err
+	}
+	sk, err := key.LoadSoft(network.ID, app.GetKeyPath(keyName))
+	if err != nil {
+		return err
+	}
+	kc := sk.KeyChain()
+	pChainAddr := sk.P()[0]
+	cChainAddr := sk.C()
+	owner := secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs:     kc.Addresses().List(),
+	}
+
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("this operation is going to:")
+	if cToP {
+		ux.Logger.PrintToUser("- move %.9f AVAX from %s to %s", float64(amount)/float64(units.Avax), cChainAddr, pChainAddr)
+		ux.Logger.PrintToUser("- take the P-Chain import fee of %.9f AVAX, plus the C-Chain export gas fee, from %s", float64(fee)/float64(units.Avax), cChainAddr)
+	} else {
+		ux.Logger.PrintToUser("- move %.9f AVAX from %s to %s", float64(amount)/float64(units.Avax), pChainAddr, cChainAddr)
+		ux.Logger.PrintToUser("- take the P-Chain export fee of %.9f AVAX from %s, and the C-Chain import gas fee from the moved amount", float64(fee)/float64(units.Avax), pChainAddr)
+	}
+	ux.Logger.PrintToUser("")
+	if !force {
+		conf, err := app.Prompt.CaptureNoYes("Confirm transfer")
+		if err != nil {
+			return err
+		}
+		if !conf {
+			ux.Logger.PrintToUser("Cancelled")
+			return nil
+		}
+	}
+
+	ctx, cancel := utils.GetAPIContext()
+	cChainID, err := info.NewClient(network.Endpoint).GetBlockchainID(ctx, "C")
+	cancel()
+	if err != nil {
+		return err
+	}
+	makeWallet := func() (primary.Wallet, error) {
+		return primary.MakeWallet(
+			context.Background(),
+			&primary.WalletConfig{
+				URI:          network.Endpoint,
+				AVAXKeychain: kc,
+				EthKeychain:  kc,
+			},
+		)
+	}
+	recoveryMsg := logging.LightRed.Wrap(fmt.Sprintf("ERROR: restart from this step by using the same command with extra arguments: --%s 1", receiveRecoveryStepFlag))
+
+	if receiveRecoveryStep == 0 {
+		wallet, err := makeWallet()
+		if err != nil {
+			return err
+		}
+		if cToP {
+			ux.Logger.PrintToUser("Issuing ExportTx C -> P")
+			_, err = subnet.IssueCToPExportTx(wallet, amount+fee, &owner)
+		} else {
+			ux.Logger.PrintToUser("Issuing ExportTx P -> C")
+			_, err = subnet.IssuePToCExportTx(wallet, cChainID, amount, &owner)
+		}
+		if err != nil {
+			return err
+		}
+		time.Sleep(2 * time.Second)
+	}
+	// the wallet is reloaded so the exported UTXOs are known
+	wallet, err := makeWallet()
+	if err != nil {
+		ux.Logger.PrintToUser(recoveryMsg)
+		return err
+	}
+	if cToP {
+		ux.Logger.PrintToUser("Issuing ImportTx C -> P")
+		_, err = subnet.IssuePFromCImportTx(wallet, cChainID, &owner)
+	} else {
+		ux.Logger.PrintToUser("Issuing ImportTx P -> C")
+		_, err = subnet.IssueCFromPImportTx(wallet, ethcommon.HexToAddress(cChainAddr))
+	}
+	if err != nil {
+		ux.Logger.PrintToUser(recoveryMsg)
+		return err
+	}
+	ux.Logger.PrintToUser("Transfer completed")
+	return nil
+}
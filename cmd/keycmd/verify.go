@@ -0,0 +1,97 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package keycmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/key"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/secp256k1"
+	"github.com/MetalBlockchain/metalgo/utils/formatting/address"
+	"github.com/ethereum/go-ethereum/common"
+	eth_crypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+var (
+	verifyMessage   string
+	verifySignature string
+	verifyAddress   string
+
+	errSignatureMismatch = errors.New("signature was not produced by the given address")
+)
+
+// avalanche key verify
+func newVerifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify a message signature",
+		Long: `The key verify command checks a signature produced by key sign (or by a wallet
+signing a message).
+
+The message is given with --message, as a path to a file or as hex, and the
+CB58 signature with --signature. If --address is provided (a P/X-Chain address,
+a C-Chain address or an address book label), the command fails unless the
+signature was produced by its key. Otherwise, the signer addresses are printed.`,
+		Args:         cobra.ExactArgs(0),
+		RunE:         verifySignedMessage,
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&verifyMessage, messageFlag, "", "signed message, as a file path or hex")
+	cmd.Flags().StringVar(&verifySignature, "signature", "", "CB58 signature to verify")
+	cmd.Flags().StringVar(&verifyAddress, "address", "", "expected signer address or address book label")
+	return cmd
+}
+
+func verifySignedMessage(_ *cobra.Command, _ []string) error {
+	msg, err := loadMessage(verifyMessage)
+	if err != nil {
+		return err
+	}
+	if verifySignature == "" {
+		return errors.New("a signature must be provided with --signature")
+	}
+	pubKey, err := key.RecoverMessageSigner(msg, strings.TrimSpace(verifySignature))
+	if err != nil {
+		return err
+	}
+	if verifyAddress == "" {
+		return printSignerAddresses(pubKey)
+	}
+	resolvedAddrs, err := app.ResolveAddressBookLabels([]string{verifyAddress})
+	if err != nil {
+		return err
+	}
+	expectedAddr := resolvedAddrs[0]
+	var matches bool
+	if common.IsHexAddress(expectedAddr) {
+		matches = common.HexToAddress(expectedAddr) == eth_crypto.PubkeyToAddress(*pubKey.ToECDSA())
+	} else {
+		addr, err := address.ParseToID(expectedAddr)
+		if err != nil {
+			return fmt.Errorf("invalid address %q: %w", expectedAddr, err)
+		}
+		matches = addr == pubKey.Address()
+	}
+	if !matches {
+		return errSignatureMismatch
+	}
+	ux.Logger.PrintToUser("Signature is valid for %s", expectedAddr)
+	return nil
+}
+
+func printSignerAddresses(pubKey *secp256k1.PublicKey) error {
+	for _, network := range []models.Network{models.NewMainnetNetwork(), models.NewTahoeNetwork()} {
+		pAddr, err := address.Format("P", key.GetHRP(network.ID), pubKey.Address().Bytes())
+		if err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Signer P-Chain address (%s): %s", network.Name(), pAddr)
+	}
+	ux.Logger.PrintToUser("Signer C-Chain address: %s", eth_crypto.PubkeyToAddress(*pubKey.ToECDSA()).Hex())
+	return nil
+}
@@ -0,0 +1,173 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/binutils"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/localnetworkbackend"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metal-network-runner/server"
+	"github.com/olekukonko/tablewriter"
+	"golang.org/x/exp/slices"
+)
+
+// backendTimeout bounds the backend operations, that may include pulling images
+const backendTimeout = 10 * time.Minute
+
+// backendName is the backend given to network start
+var backendName string
+
+// anrBackend runs the local network with the avalanche network runner, through
+// the gRPC server started by the CLI. It takes its settings from the start and
+// stop flags, as it also handles snapshots
+type anrBackend struct{}
+
+func (anrBackend) Start(context.Context, localnetworkbackend.Config) ([]localnetworkbackend.NodeInfo, error) {
+	return nil, startANRNetwork()
+}
+
+func (anrBackend) Stop(context.Context) error {
+	return stopANRNetwork()
+}
+
+func (anrBackend) Status(ctx context.Context) ([]localnetworkbackend.NodeInfo, error) {
+	cli, err := binutils.NewGRPCClient(binutils.WithDialTimeout(constants.FastGRPCDialTimeout))
+	if errors.Is(err, binutils.ErrGRPCTimeout) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	status, err := cli.Status(ctx)
+	if server.IsServerError(err, server.ErrNotBootstrapped) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	nodes := []localnetworkbackend.NodeInfo{}
+	for _, nodeInfo := range status.ClusterInfo.NodeInfos {
+		nodes = append(nodes, localnetworkbackend.NodeInfo{
+			Name:    nodeInfo.Name,
+			NodeID:  nodeInfo.Id,
+			URI:     nodeInfo.Uri,
+			Healthy: status.ClusterInfo.Healthy,
+		})
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	return nodes, nil
+}
+
+func (anrBackend) Clean(context.Context) error {
+	return cleanANRNetwork()
+}
+
+// getBackend returns the local network backend named [name]
+func getBackend(name string) (localnetworkbackend.Backend, error) {
+	if name == localnetworkbackend.ANR {
+		return anrBackend{}, nil
+	}
+	if !slices.Contains(localnetworkbackend.Names(), name) {
+		return nil, fmt.Errorf("unknown local network backend %q. Use one of %s", name, strings.Join(localnetworkbackend.Names(), ", "))
+	}
+	return localnetworkbackend.NewContainerBackend(name, app.GetRunDir(), app.GetLocalNetworkName())
+}
+
+// getStartBackendName returns the backend to start the local network with:
+// the one given by flag, or else the current one. The backend can only be
+// changed if the network is not running with the current one
+func getStartBackendName(ctx context.Context) (string, error) {
+	currentName := localnetworkbackend.CurrentName(app.GetRunDir())
+	if backendName == "" || backendName == currentName {
+		return currentName, nil
+	}
+	if !slices.Contains(localnetworkbackend.Names(), backendName) {
+		return "", fmt.Errorf("unknown local network backend %q. Use one of %s", backendName, strings.Join(localnetworkbackend.Names(), ", "))
+	}
+	current, err := getBackend(currentName)
+	if err != nil {
+		return "", err
+	}
+	nodes, err := current.Status(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(nodes) > 0 {
+		return "", fmt.Errorf("local network is running with the %s backend. Stop it before starting it with the %s backend", currentName, backendName)
+	}
+	return backendName, nil
+}
+
+// startContainerNetwork starts the local network with the container based
+// backend [name]
+func startContainerNetwork(ctx context.Context, name string, backend localnetworkbackend.Backend) error {
+	switch {
+	case snapshotName != constants.DefaultSnapshotName:
+		return fmt.Errorf("snapshots are only supported by the %s backend", localnetworkbackend.ANR)
+	case avagoBinaryPath != "":
+		return fmt.Errorf("--metalgo-path is only supported by the %s backend. Use --metalgo-version to set the image tag", localnetworkbackend.ANR)
+	}
+	settings, err := saveLocalNetworkSettings()
+	if err != nil {
+		return err
+	}
+	config := localnetworkbackend.Config{
+		NumNodes:       numNodes,
+		MetalGoVersion: userProvidedAvagoVersion,
+		HTTPPort:       settings.HTTPPort,
+		StakingPort:    settings.StakingPort,
+		HTTPHost:       settings.HTTPHost,
+		PluginDir:      app.GetPluginsDir(),
+	}
+	if config.MetalGoVersion == latest {
+		config.MetalGoVersion = ""
+	}
+	// recorded before starting, so that a partial start can be cleaned
+	if err := localnetworkbackend.SetCurrentName(app.GetRunDir(), name); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Booting Network with the %s backend. Wait until healthy...", name)
+	nodes, err := backend.Start(ctx, config)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Network ready to use.")
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Local network node endpoints:")
+	printBackendNodes(nodes)
+	return nil
+}
+
+// cleanContainerNetwork removes the containers and state of the local network
+// run by the container based backend [name]
+func cleanContainerNetwork(name string) error {
+	backend, err := getBackend(name)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), backendTimeout)
+	defer cancel()
+	if err := backend.Clean(ctx); err != nil {
+		return err
+	}
+	return localnetworkbackend.SetCurrentName(app.GetRunDir(), localnetworkbackend.ANR)
+}
+
+func printBackendNodes(nodes []localnetworkbackend.NodeInfo) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Node", "Node ID", "URI", "Healthy"})
+	table.SetRowLine(true)
+	for _, node := range nodes {
+		table.Append([]string{node.Name, node.NodeID, node.URI, fmt.Sprint(node.Healthy)})
+	}
+	table.Render()
+}
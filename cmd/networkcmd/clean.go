@@ -3,6 +3,7 @@
 package networkcmd
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/MetalBlockchain/metal-cli/pkg/binutils"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/localnetworkbackend"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
 	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
@@ -27,7 +29,12 @@ func newCleanCmd() *cobra.Command {
 		Short: "Stop the running local network and delete state",
 		Long: `The network clean command shuts down your local, multi-node network. All deployed Subnets
 shutdown and delete their state. You can restart the network by deploying a new Subnet
-configuration.`,
+configuration.
+
+With --name, the named local network is stopped and all its state is deleted.
+
+If the network was started with the docker or k8s backend, its containers, volumes
+or kind cluster are removed too, and the next start uses the anr backend again.`,
 		RunE:         clean,
 		Args:         cobra.ExactArgs(0),
 		SilenceUsage: true,
@@ -44,6 +51,26 @@ configuration.`,
 }
 
 func clean(*cobra.Command, []string) error {
+	if name := localnetworkbackend.CurrentName(app.GetRunDir()); name != localnetworkbackend.ANR {
+		if err := cleanContainerNetwork(name); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Removed the %s backend nodes", name)
+	}
+	// the CLI state of the local network is reset for any backend
+	backend, err := getBackend(localnetworkbackend.ANR)
+	if err != nil {
+		return err
+	}
+	return backend.Clean(context.Background())
+}
+
+// cleanANRNetwork stops the network runner and removes the local network state
+func cleanANRNetwork() error {
+	if name := app.GetLocalNetworkName(); name != "" {
+		return cleanNamedLocalNetwork(name)
+	}
+
 	app.Log.Info("killing gRPC server process...")
 
 	configSingleNodeEnabled := app.Conf.GetConfigBoolValue(constants.ConfigSingleNodeEnabledKey)
@@ -57,6 +84,8 @@ func clean(*cobra.Command, []string) error {
 		return err
 	}
 
+	stopRelayer()
+
 	if err := binutils.KillgRPCServerProcess(app); err != nil {
 		app.Log.Warn("failed killing server process", zap.Error(err))
 	} else {
@@ -71,6 +100,10 @@ func clean(*cobra.Command, []string) error {
 		return err
 	}
 
+	if err := os.RemoveAll(app.GetVMAliasesPath()); err != nil {
+		return err
+	}
+
 	if hard {
 		ux.Logger.PrintToUser("hard clean requested via flag, removing all downloaded avalanchego and plugin binaries")
 		binDir := filepath.Join(app.GetBaseDir(), constants.AvalancheCliBinDir)
@@ -91,6 +124,28 @@ func clean(*cobra.Command, []string) error {
 	return nil
 }
 
+// cleanNamedLocalNetwork stops the named local network [name] and removes all its state
+func cleanNamedLocalNetwork(name string) error {
+	app.Log.Info("killing gRPC server process...")
+	if err := binutils.KillgRPCServerProcess(app); err != nil {
+		app.Log.Warn("failed killing server process", zap.Error(err))
+	} else {
+		ux.Logger.PrintToUser("Process terminated.")
+	}
+
+	if hard {
+		ux.Logger.PrintToUser("hard clean requested via flag, removing all downloaded avalanchego and plugin binaries")
+		binDir := filepath.Join(app.GetBaseDir(), constants.AvalancheCliBinDir)
+		cleanBins(binDir)
+	}
+
+	if err := app.RemoveLocalNetwork(name); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Local network %s removed", name)
+	return nil
+}
+
 func removeLocalDeployInfoFromSidecars() error {
 	// Remove all local deployment info from sidecar files
 	deployedSubnets, err := subnet.GetLocallyDeployedSubnetsFromFile(app)
@@ -99,13 +154,10 @@ func removeLocalDeployInfoFromSidecars() error {
 	}
 
 	for _, subnet := range deployedSubnets {
-		sc, err := app.LoadSidecar(subnet)
-		if err != nil {
-			return err
-		}
-
-		delete(sc.Networks, models.Local.String())
-		if err = app.UpdateSidecar(&sc); err != nil {
+		if _, err := app.EditSidecar(subnet, func(sc *models.Sidecar) error {
+			delete(sc.Networks, models.NewLocalNetwork().Name())
+			return nil
+		}); err != nil {
 			return err
 		}
 	}
@@ -120,13 +172,10 @@ func removeLocalElasticSubnetInfoFromSidecars() error {
 	}
 
 	for _, subnet := range elasticSubnets {
-		sc, err := app.LoadSidecar(subnet)
-		if err != nil {
-			return err
-		}
-
-		delete(sc.ElasticSubnet, models.Local.String())
-		if err = app.UpdateSidecar(&sc); err != nil {
+		if _, err := app.EditSidecar(subnet, func(sc *models.Sidecar) error {
+			delete(sc.ElasticSubnet, models.NewLocalNetwork().Name())
+			return nil
+		}); err != nil {
 			return err
 		}
 		if err = deleteElasticSubnetConfigFile(subnet); err != nil {
@@ -0,0 +1,161 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/explorer"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/spf13/cobra"
+)
+
+var explorerPort int
+
+// metal network explorer
+func newExplorerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explorer",
+		Short: "Run block explorers for the locally deployed subnets",
+		Long: `The network explorer command suite runs a Blockscout block explorer for a
+Subnet-EVM subnet deployed to the local network, using docker-compose, so
+its blocks, txs and addresses can be inspected without public infrastructure.`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+		Args: cobra.ExactArgs(0),
+	}
+	cmd.AddCommand(newExplorerStartCmd())
+	cmd.AddCommand(newExplorerStopCmd())
+	return cmd
+}
+
+// metal network explorer start
+func newExplorerStartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start [subnetName]",
+		Short: "Start a block explorer for a locally deployed subnet",
+		Long: `The network explorer start command starts Blockscout and database containers
+indexing the blockchain of the given subnet, or of the active one, on the
+running local network.
+
+The explorer indexes the chain from scratch each time it is started, as the
+database of a previous run is removed. Run it again after redeploying the
+subnet, as its blockchain changes. The explorer only listens on 127.0.0.1.
+
+The containers reach the nodes through the docker host. On Linux, this requires
+the nodes to listen on an address other than 127.0.0.1. Set it with
+metal config localNetwork --http-host 0.0.0.0 and restart the network.`,
+		RunE:         startExplorer,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().IntVar(&explorerPort, "port", constants.LocalExplorerPort, "host port for the explorer UI")
+	return cmd
+}
+
+// metal network explorer stop
+func newExplorerStopCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "stop [subnetName]",
+		Short:        "Stop the block explorer of a locally deployed subnet",
+		Long:         `The network explorer stop command removes the explorer containers of the given subnet, or of the active one, together with its database.`,
+		RunE:         stopExplorer,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+	}
+}
+
+func getExplorerSubnetName(args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+	if subnetName := app.GetActiveSubnet(); subnetName != "" {
+		return subnetName, nil
+	}
+	return "", fmt.Errorf("a subnet name is required, or select one with 'metal use subnet'")
+}
+
+func startExplorer(_ *cobra.Command, args []string) error {
+	if _, err := exec.LookPath(dockerCompose); err != nil {
+		return fmt.Errorf("%s is required to run the explorer: %w", dockerCompose, err)
+	}
+	subnetName, err := getExplorerSubnetName(args)
+	if err != nil {
+		return err
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	if sc.VM != models.SubnetEvm {
+		return fmt.Errorf("the explorer can only be used on Subnet-EVM based subnets")
+	}
+	blockchainID := sc.Networks[models.NewLocalNetwork().Name()].BlockchainID
+	if blockchainID == ids.Empty {
+		return fmt.Errorf("subnet %s is not deployed to the local network", subnetName)
+	}
+	if err := checkNodesReachableFromDocker("explorer"); err != nil {
+		return err
+	}
+	// the chain endpoints, as seen from the explorer containers
+	settings := subnet.GetLocalNetworkSettings(app)
+	network := models.NewNetwork(
+		models.Local,
+		constants.LocalNetworkID,
+		fmt.Sprintf("http://%s:%d", monitoringDockerHostName, settings.HTTPPort),
+		"",
+	)
+	explorerDir := app.GetLocalExplorerDir(subnetName)
+	// the database of a previous run is removed, so the chain is indexed from scratch
+	if composePath := filepath.Join(explorerDir, constants.MonitoringComposeFileName); utils.FileExists(composePath) {
+		if err := utils.RemoveDockerCompose(composePath); err != nil {
+			return err
+		}
+	}
+	if err := explorer.WriteLocalExplorerFiles(explorerDir, explorer.Config{
+		ProjectName: explorer.ProjectName(subnetName, app.GetLocalNetworkName()),
+		SubnetName:  subnetName,
+		ChainID:     sc.ChainID,
+		TokenSymbol: sc.TokenSymbol,
+		RPCURL:      network.BlockchainEndpoint(blockchainID.String()),
+		WSURL:       network.BlockchainWSEndpoint(blockchainID.String()),
+		Port:        explorerPort,
+	}); err != nil {
+		return err
+	}
+	if err := utils.StartDockerCompose(filepath.Join(explorerDir, constants.MonitoringComposeFileName)); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Explorer of subnet %s: http://localhost:%d", subnetName, explorerPort)
+	ux.Logger.PrintToUser("It may take a minute to be available, while its database is set up")
+	return nil
+}
+
+func stopExplorer(_ *cobra.Command, args []string) error {
+	subnetName, err := getExplorerSubnetName(args)
+	if err != nil {
+		return err
+	}
+	composePath := filepath.Join(app.GetLocalExplorerDir(subnetName), constants.MonitoringComposeFileName)
+	if !utils.FileExists(composePath) {
+		ux.Logger.PrintToUser("Explorer of subnet %s is not running", subnetName)
+		return nil
+	}
+	if err := utils.RemoveDockerCompose(composePath); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Explorer of subnet %s stopped", subnetName)
+	return nil
+}
@@ -0,0 +1,323 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/binutils"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metalgo/utils/logging"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+	"golang.org/x/term"
+)
+
+const (
+	logsDirName      = "logs"
+	logFileExt       = ".log"
+	logsPollInterval = 500 * time.Millisecond
+)
+
+var (
+	logsNodes   []string
+	logsFollow  bool
+	logsGrep    string
+	logsLines   int
+	logsNoColor bool
+
+	nodeLogColors = []logging.Color{
+		logging.Cyan,
+		logging.Green,
+		logging.Purple,
+		logging.Blue,
+		logging.Orange,
+		logging.LightCyan,
+		logging.LightGreen,
+		logging.LightPurple,
+		logging.LightBlue,
+	}
+)
+
+// metal network logs
+func newLogsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Print the logs of the local network nodes",
+		Long: `The network logs command prints the metalgo and VM logs of the local network
+nodes, prefixing each line with the node and chain it comes from.
+
+By default it prints the last lines of every log file of every node. Use --node
+to restrict the output to some nodes, --grep to only print the lines matching a
+regular expression, and --follow to keep printing new lines as they are written.
+
+If the local network is not running, the logs of the last run are printed.`,
+		RunE:         networkLogs,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringSliceVar(&logsNodes, "node", nil, "only print the logs of these nodes (eg node1)")
+	cmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "keep printing new log lines")
+	cmd.Flags().StringVar(&logsGrep, "grep", "", "only print lines matching this regular expression")
+	cmd.Flags().IntVarP(&logsLines, "lines", "n", 10, "number of previous lines to print from each log file")
+	cmd.Flags().BoolVar(&logsNoColor, "no-color", false, "don't colorize the node prefixes")
+	return cmd
+}
+
+type nodeLogFile struct {
+	node   string
+	chain  string
+	path   string
+	offset int64
+	// incomplete last line read so far
+	partial []byte
+}
+
+func (f *nodeLogFile) prefix(color logging.Color) string {
+	prefix := fmt.Sprintf("[%s/%s]", f.node, f.chain)
+	if color == "" {
+		return prefix
+	}
+	return color.Wrap(prefix)
+}
+
+func networkLogs(*cobra.Command, []string) error {
+	var grep *regexp.Regexp
+	if logsGrep != "" {
+		var err error
+		grep, err = regexp.Compile(logsGrep)
+		if err != nil {
+			return fmt.Errorf("invalid --grep expression: %w", err)
+		}
+	}
+	rootDataDir, chainNames, err := getLocalNetworkLogsInfo()
+	if err != nil {
+		return err
+	}
+	files, err := findNodeLogFiles(rootDataDir, logsNodes, chainNames)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no log files found at %s", rootDataDir)
+	}
+	useColor := !logsNoColor && term.IsTerminal(int(os.Stdout.Fd()))
+	nodeColors := map[string]logging.Color{}
+	colorOf := func(node string) logging.Color {
+		if !useColor {
+			return ""
+		}
+		if _, ok := nodeColors[node]; !ok {
+			nodeColors[node] = nodeLogColors[len(nodeColors)%len(nodeLogColors)]
+		}
+		return nodeColors[node]
+	}
+	for _, f := range files {
+		lines, err := lastLogLines(f, logsLines)
+		if err != nil {
+			return err
+		}
+		printLogLines(f, lines, grep, colorOf(f.node))
+	}
+	if !logsFollow {
+		return nil
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	ticker := time.NewTicker(logsPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		// pick up log files created after start, eg for newly deployed chains
+		currentFiles, err := findNodeLogFiles(rootDataDir, logsNodes, chainNames)
+		if err != nil {
+			return err
+		}
+		for _, f := range currentFiles {
+			if !slices.ContainsFunc(files, func(known *nodeLogFile) bool { return known.path == f.path }) {
+				files = append(files, f)
+			}
+		}
+		for _, f := range files {
+			lines, err := newLogLines(f)
+			if err != nil {
+				return err
+			}
+			printLogLines(f, lines, grep, colorOf(f.node))
+		}
+	}
+}
+
+// getLocalNetworkLogsInfo returns the data dir of the running local network, along with
+// the names of its custom chains by blockchain ID. If the network is not running, it
+// returns the data dir of the last run
+func getLocalNetworkLogsInfo() (string, map[string]string, error) {
+	chainNames := map[string]string{}
+	cli, err := binutils.NewGRPCClient(
+		binutils.WithAvoidRPCVersionCheck(true),
+		binutils.WithDialTimeout(constants.FastGRPCDialTimeout),
+	)
+	if err == nil {
+		ctx, cancel := utils.GetAPIContext()
+		defer cancel()
+		status, err := cli.Status(ctx)
+		if err == nil && status.ClusterInfo != nil && status.ClusterInfo.RootDataDir != "" {
+			if _, err := subnet.RemoveUndeployedChains(app, status.ClusterInfo); err != nil {
+				return "", nil, err
+			}
+			for blockchainID, chainInfo := range status.ClusterInfo.CustomChains {
+				chainNames[blockchainID] = chainInfo.ChainName
+			}
+			return status.ClusterInfo.RootDataDir, chainNames, nil
+		}
+	}
+	rootDataDir, err := findLastRootDataDir(app.GetRunDir())
+	return rootDataDir, chainNames, err
+}
+
+// findLastRootDataDir returns the most recently modified local network data dir under [runDir]
+func findLastRootDataDir(runDir string) (string, error) {
+	candidates := []string{runDir}
+	entries, err := os.ReadDir(runDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			candidates = append(candidates, filepath.Join(runDir, entry.Name()))
+		}
+	}
+	lastDir := ""
+	var lastModTime time.Time
+	for _, dir := range candidates {
+		logsDirs, err := filepath.Glob(filepath.Join(dir, "node*", logsDirName))
+		if err != nil {
+			return "", err
+		}
+		for _, logsDir := range logsDirs {
+			info, err := os.Stat(logsDir)
+			if err != nil {
+				continue
+			}
+			if info.ModTime().After(lastModTime) {
+				lastDir, lastModTime = dir, info.ModTime()
+			}
+		}
+	}
+	if lastDir == "" {
+		return "", errors.New("no local network logs found. Start the network with 'metal network start'")
+	}
+	return lastDir, nil
+}
+
+// findNodeLogFiles returns the log files at [rootDataDir]/<node>/logs, restricted to
+// [nodes] if given. Chain log files named by blockchain ID are labeled with the
+// chain name found at [chainNames], if any
+func findNodeLogFiles(rootDataDir string, nodes []string, chainNames map[string]string) ([]*nodeLogFile, error) {
+	paths, err := filepath.Glob(filepath.Join(rootDataDir, "*", logsDirName, "*"+logFileExt))
+	if err != nil {
+		return nil, err
+	}
+	files := []*nodeLogFile{}
+	for _, path := range paths {
+		node := filepath.Base(filepath.Dir(filepath.Dir(path)))
+		if len(nodes) > 0 && !slices.Contains(nodes, node) {
+			continue
+		}
+		chain := strings.TrimSuffix(filepath.Base(path), logFileExt)
+		if chainName, ok := chainNames[chain]; ok {
+			chain = chainName
+		}
+		files = append(files, &nodeLogFile{
+			node:  node,
+			chain: chain,
+			path:  path,
+		})
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].node != files[j].node {
+			return files[i].node < files[j].node
+		}
+		return files[i].chain < files[j].chain
+	})
+	return files, nil
+}
+
+// lastLogLines returns the last [n] complete lines of [f], and moves its offset to the end
+func lastLogLines(f *nodeLogFile, n int) ([]string, error) {
+	lines, err := newLogLines(f)
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		n = 0
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines, nil
+}
+
+// newLogLines returns the complete lines written to [f] since the last call
+func newLogLines(f *nodeLogFile) ([]string, error) {
+	file, err := os.Open(f.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() < f.offset {
+		// the file was rotated or truncated
+		f.offset = 0
+		f.partial = nil
+	}
+	if _, err := file.Seek(f.offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	f.offset += int64(len(data))
+	data = append(f.partial, data...)
+	lastNewLine := bytes.LastIndexByte(data, '\n')
+	if lastNewLine < 0 {
+		f.partial = data
+		return nil, nil
+	}
+	f.partial = append([]byte{}, data[lastNewLine+1:]...)
+	return strings.Split(string(data[:lastNewLine]), "\n"), nil
+}
+
+func printLogLines(f *nodeLogFile, lines []string, grep *regexp.Regexp, color logging.Color) {
+	prefix := f.prefix(color)
+	for _, line := range lines {
+		if grep != nil && !grep.MatchString(line) {
+			continue
+		}
+		fmt.Println(prefix + " " + line)
+	}
+}
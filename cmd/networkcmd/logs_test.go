@@ -0,0 +1,71 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networkcmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/stretchr/testify/require"
+)
+
+func writeNodeLog(t *testing.T, rootDataDir, node, chain, content string) string {
+	logsDir := filepath.Join(rootDataDir, node, logsDirName)
+	require.NoError(t, os.MkdirAll(logsDir, constants.DefaultPerms755))
+	path := filepath.Join(logsDir, chain+logFileExt)
+	require.NoError(t, os.WriteFile(path, []byte(content), constants.WriteReadReadPerms))
+	return path
+}
+
+func Test_findNodeLogFiles(t *testing.T) {
+	rootDataDir := t.TempDir()
+	writeNodeLog(t, rootDataDir, "node2", "main", "")
+	writeNodeLog(t, rootDataDir, "node1", "main", "")
+	writeNodeLog(t, rootDataDir, "node1", "2oYMBNV4eNHyqk2fjjV5nVQLDbtmNJzq5s3qs3Lo6ftnC6FByM", "")
+
+	chainNames := map[string]string{"2oYMBNV4eNHyqk2fjjV5nVQLDbtmNJzq5s3qs3Lo6ftnC6FByM": "mySubnet"}
+	files, err := findNodeLogFiles(rootDataDir, nil, chainNames)
+	require.NoError(t, err)
+	labels := []string{}
+	for _, f := range files {
+		labels = append(labels, f.node+"/"+f.chain)
+	}
+	require.Equal(t, []string{"node1/main", "node1/mySubnet", "node2/main"}, labels)
+
+	files, err = findNodeLogFiles(rootDataDir, []string{"node2"}, chainNames)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, "node2", files[0].node)
+
+	lastDir, err := findLastRootDataDir(filepath.Dir(rootDataDir))
+	require.NoError(t, err)
+	require.Equal(t, rootDataDir, lastDir)
+}
+
+func Test_newLogLines(t *testing.T) {
+	path := writeNodeLog(t, t.TempDir(), "node1", "main", "a\nb\nc\npart")
+	f := &nodeLogFile{node: "node1", chain: "main", path: path}
+
+	lines, err := lastLogLines(f, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "c"}, lines)
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = file.WriteString("ial\nd\n")
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	lines, err = newLogLines(f)
+	require.NoError(t, err)
+	require.Equal(t, []string{"partial", "d"}, lines)
+
+	// truncated files are read again from the start
+	require.NoError(t, os.WriteFile(path, []byte("e\n"), constants.WriteReadReadPerms))
+	lines, err = newLogLines(f)
+	require.NoError(t, err)
+	require.Equal(t, []string{"e"}, lines)
+}
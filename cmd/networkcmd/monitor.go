@@ -0,0 +1,181 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/binutils"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/monitoring"
+	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metal-network-runner/server"
+	"github.com/spf13/cobra"
+)
+
+const (
+	dockerCompose            = "docker-compose"
+	monitoringProjectPrefix  = "metal-monitoring"
+	monitoringDockerHostName = "host.docker.internal"
+)
+
+var (
+	monitorGrafanaPort    int
+	monitorPrometheusPort int
+)
+
+// metal network monitor
+func newMonitorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "monitor",
+		Short: "Monitor the local network with Prometheus and Grafana",
+		Long: `The network monitor command suite runs a Prometheus and Grafana monitoring
+stack for the local network, using docker-compose. Prometheus scrapes the
+metrics of all the local network nodes, and Grafana comes with the dashboards
+used for cloud nodes (consensus, chains, subnets, database and network metrics).`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+		Args: cobra.ExactArgs(0),
+	}
+	cmd.AddCommand(newMonitorStartCmd())
+	cmd.AddCommand(newMonitorStopCmd())
+	return cmd
+}
+
+// metal network monitor start
+func newMonitorStartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Start the monitoring stack of the local network",
+		Long: `The network monitor start command starts Prometheus and Grafana containers
+monitoring the running local network.
+
+The scrape targets are the nodes running when the command is called, so run it
+again after adding nodes to the network. Grafana and Prometheus only listen on
+127.0.0.1, and Grafana allows anonymous read only access.
+
+The containers reach the nodes through the docker host. On Linux, this requires
+the nodes to listen on an address other than 127.0.0.1. Set it with
+metal config localNetwork --http-host 0.0.0.0 and restart the network.`,
+		RunE:         startMonitoring,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().IntVar(&monitorGrafanaPort, "grafana-port", constants.AvalanchegoGrafanaPort, "host port for the Grafana UI")
+	cmd.Flags().IntVar(&monitorPrometheusPort, "prometheus-port", constants.AvalanchegoMonitoringPort, "host port for the Prometheus UI")
+	return cmd
+}
+
+// metal network monitor stop
+func newMonitorStopCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "stop",
+		Short:        "Stop the monitoring stack of the local network",
+		Long:         `The network monitor stop command removes the Prometheus and Grafana containers.`,
+		RunE:         stopMonitoring,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+}
+
+func startMonitoring(*cobra.Command, []string) error {
+	if _, err := exec.LookPath(dockerCompose); err != nil {
+		return fmt.Errorf("%s is required to run the monitoring stack: %w", dockerCompose, err)
+	}
+	if err := checkNodesReachableFromDocker("monitoring"); err != nil {
+		return err
+	}
+	targets, err := getMonitoringTargets()
+	if err != nil {
+		return err
+	}
+	monitoringDir := app.GetLocalMonitoringDir()
+	projectName := monitoringProjectPrefix
+	if name := app.GetLocalNetworkName(); name != "" {
+		// compose project names must be lowercase
+		projectName += "-" + strings.ToLower(name)
+	}
+	if err := monitoring.WriteLocalMonitoringFiles(monitoringDir, projectName, monitorPrometheusPort, monitorGrafanaPort); err != nil {
+		return err
+	}
+	prometheusConfigPath := filepath.Join(monitoringDir, constants.NodePrometheusConfigFileName)
+	if err := monitoring.WritePrometheusConfig(prometheusConfigPath, targets, nil, nil); err != nil {
+		return err
+	}
+	if err := utils.StartDockerCompose(filepath.Join(monitoringDir, constants.MonitoringComposeFileName)); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Monitoring %d nodes", len(targets))
+	ux.Logger.PrintToUser("Grafana: http://localhost:%d", monitorGrafanaPort)
+	ux.Logger.PrintToUser("Prometheus: http://localhost:%d", monitorPrometheusPort)
+	return nil
+}
+
+// getMonitoringTargets returns the metrics endpoints of the local network nodes, as
+// seen from the monitoring containers
+func getMonitoringTargets() ([]string, error) {
+	cli, err := binutils.NewGRPCClient(
+		binutils.WithDialTimeout(constants.FastGRPCDialTimeout),
+	)
+	if errors.Is(err, binutils.ErrGRPCTimeout) {
+		return nil, errNoLocalNetwork
+	}
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	status, err := cli.Status(ctx)
+	if err != nil {
+		if server.IsServerError(err, server.ErrNotBootstrapped) {
+			return nil, errNoLocalNetwork
+		}
+		return nil, err
+	}
+	targets := []string{}
+	for _, nodeInfo := range status.GetClusterInfo().GetNodeInfos() {
+		if port := getURIPort(nodeInfo.GetUri()); port != 0 {
+			targets = append(targets, fmt.Sprintf("%s:%d", monitoringDockerHostName, port))
+		}
+	}
+	if len(targets) == 0 {
+		return nil, errNoLocalNetwork
+	}
+	return targets, nil
+}
+
+// checkNodesReachableFromDocker fails if the local network nodes can't be reached
+// from the [purpose] containers through the docker host
+func checkNodesReachableFromDocker(purpose string) error {
+	settings := subnet.GetLocalNetworkSettings(app)
+	if runtime.GOOS == "linux" && (settings.HTTPHost == "" || settings.HTTPHost == "127.0.0.1") {
+		return fmt.Errorf("the local network nodes only listen on 127.0.0.1, so they can't be reached from the %s containers. "+
+			"Run 'metal config localNetwork --http-host 0.0.0.0' and restart the network", purpose)
+	}
+	return nil
+}
+
+func stopMonitoring(*cobra.Command, []string) error {
+	composePath := filepath.Join(app.GetLocalMonitoringDir(), constants.MonitoringComposeFileName)
+	if !utils.FileExists(composePath) {
+		ux.Logger.PrintToUser("Monitoring is not running")
+		return nil
+	}
+	if err := utils.StopDockerCompose(composePath); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Monitoring stopped")
+	return nil
+}
@@ -3,13 +3,19 @@
 package networkcmd
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/binutils"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
 	"github.com/spf13/cobra"
 )
 
-var app *application.Avalanche
+var (
+	app              *application.Avalanche
+	localNetworkName string
+)
 
 func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
 	app = injectedApp
@@ -23,7 +29,13 @@ When you deploy a Subnet locally, it runs on a local, multi-node Avalanche netwo
 subnet deploy command starts this network in the background. This command suite allows you
 to shutdown, restart, and clear that network.
 
-This network currently supports multiple, concurrently deployed Subnets.`,
+This network currently supports multiple, concurrently deployed Subnets.
+
+Several isolated local networks can run at the same time: start a named one with
+network start --name <networkName>, and select it with --name in the other
+network commands and with subnet deploy --local-network-name. Each named network
+uses its own backend ports and keeps its own state and snapshots.`,
+		PersistentPreRunE: selectLocalNetwork,
 		Run: func(cmd *cobra.Command, _ []string) {
 			err := cmd.Help()
 			if err != nil {
@@ -32,13 +44,41 @@ This network currently supports multiple, concurrently deployed Subnets.`,
 		},
 		Args: cobra.ExactArgs(0),
 	}
+	cmd.PersistentFlags().StringVar(&localNetworkName, "name", "", "operate on the given named local network instead of the default one")
 	// network start
 	cmd.AddCommand(newStartCmd())
 	// network stop
 	cmd.AddCommand(newStopCmd())
+	// network restart
+	cmd.AddCommand(newRestartCmd())
 	// network clean
 	cmd.AddCommand(newCleanCmd())
 	// network status
 	cmd.AddCommand(newStatusCmd())
+	// network snapshot
+	cmd.AddCommand(newSnapshotCmd())
+	// network logs
+	cmd.AddCommand(newLogsCmd())
+	// network monitor
+	cmd.AddCommand(newMonitorCmd())
+	// network explorer
+	cmd.AddCommand(newExplorerCmd())
 	return cmd
 }
+
+// selectLocalNetwork makes the network subcommands operate on the network given
+// by --name. Only start and snapshot load may create a new named network
+func selectLocalNetwork(cmd *cobra.Command, args []string) error {
+	// cobra only runs the closest persistent pre run, so the root one (app setup) is called here
+	if root := cmd.Root(); root.PersistentPreRunE != nil {
+		if err := root.PersistentPreRunE(cmd, args); err != nil {
+			return err
+		}
+	}
+	create := cmd.Name() == "start" || cmd.Name() == "load"
+	err := binutils.SelectLocalNetwork(app, localNetworkName, create)
+	if errors.Is(err, application.ErrLocalNetworkNotFound) {
+		return ux.NewCodedError(ux.ErrCodeNotFound, err)
+	}
+	return err
+}
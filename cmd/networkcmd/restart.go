@@ -0,0 +1,96 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/binutils"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	anrutils "github.com/MetalBlockchain/metal-network-runner/utils"
+	"github.com/spf13/cobra"
+)
+
+// metal network restart
+func newRestartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restart",
+		Short: "Restart the local network and preserve state",
+		Long: `The network restart command stops the local network, saving its state into
+the default snapshot, and starts it again from it. All deployed Subnets keep
+their state, so there is no need to clean and redeploy them.
+
+Before starting the network, the VM binaries of the locally deployed Subnets are
+installed again into the plugin directory, so the network can also be restarted
+after the machine reboots, or after those binaries changed.
+
+Use --metalgo-version or --metalgo-path to restart the network with another
+metalgo, eg to upgrade it. As with network start, the given version or binary
+is pinned to the network state.`,
+		RunE:         restartNetwork,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	addAvalancheGoFlags(cmd)
+	return cmd
+}
+
+func restartNetwork(cmd *cobra.Command, args []string) error {
+	snapshotName = constants.DefaultSnapshotName
+	if err := StopNetwork(cmd, args); err != nil {
+		return err
+	}
+	if err := reinstallLocalPlugins(); err != nil {
+		return err
+	}
+	return StartNetwork(cmd, args)
+}
+
+// reinstallLocalPlugins copies the VM binaries of all locally deployed subnets
+// into the plugin dir
+func reinstallLocalPlugins() error {
+	deployedSubnets, err := subnet.GetLocallyDeployedSubnetsFromFile(app)
+	if err != nil {
+		return err
+	}
+	pluginDownloader := binutils.NewPluginBinaryDownloader(app)
+	for _, chain := range deployedSubnets {
+		sc, err := app.LoadSidecar(chain)
+		if err != nil {
+			return err
+		}
+		var vmBin string
+		switch sc.VM {
+		case models.SubnetEvm:
+			_, vmBin, err = binutils.SetupSubnetEVM(app, sc.VMVersion)
+			if err != nil {
+				return fmt.Errorf("failed to install subnet-evm for %s: %w", chain, err)
+			}
+		case models.CustomVM:
+			vmBin = binutils.SetupCustomBin(app, chain)
+		default:
+			return fmt.Errorf("unknown vm %s for %s", sc.VM, chain)
+		}
+		if !utils.FileExists(vmBin) {
+			ux.Logger.PrintToUser("Warning: VM binary %s of %s not found. Its blockchain won't be able to run", vmBin, chain)
+			continue
+		}
+		vmID, err := anrutils.VMID(chain)
+		if err != nil {
+			return err
+		}
+		install := pluginDownloader.InstallVM
+		if utils.FileExists(filepath.Join(app.GetPluginsDir(), vmID.String())) {
+			install = pluginDownloader.UpgradeVM
+		}
+		if err := install(vmID.String(), vmBin); err != nil {
+			return fmt.Errorf("failed installing VM binary of %s: %w", chain, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,310 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/binutils"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/snapshot"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// suffix of the name a snapshot is saved under while it replaces an existing one
+const savingSnapshotSuffix = ".saving"
+
+var (
+	pruneKeep           int
+	pruneIncludeManual  bool
+	pruneDryRun         bool
+	forceSnapshotSave   bool
+	forceSnapshotDelete bool
+
+	errNoLocalNetwork = ux.NewCodedError(ux.ErrCodeNetworkNotRunning, errors.New("no local network running. Start it with 'metal network start'"))
+)
+
+// metal network snapshot
+func newSnapshotCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Manage local network snapshots",
+		Long: `The network snapshot command suite saves, loads, lists, deletes and prunes
+the snapshots of the local network.
+
+A snapshot captures the whole local network state, including deployed
+Subnets and their data, so that it can be restored later instead of
+redeploying from scratch.
+
+Snapshots automatically taken before deploys and upgrades (see
+'metal config snapshots') have names starting with 'auto-'.`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+		Args: cobra.ExactArgs(0),
+	}
+	cmd.AddCommand(newSnapshotSaveCmd())
+	cmd.AddCommand(newSnapshotLoadCmd())
+	cmd.AddCommand(newSnapshotListCmd())
+	cmd.AddCommand(newSnapshotDeleteCmd())
+	cmd.AddCommand(newSnapshotPruneCmd())
+	return cmd
+}
+
+func newSnapshotSaveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "save [snapshotName]",
+		Short: "Save the running local network state into a named snapshot",
+		Long: `The network snapshot save command saves the state of the running local network
+under the given name. The network is briefly stopped to take the snapshot, and then
+resumes running from it.
+
+The command fails if the snapshot already exists, unless --force is given.`,
+		RunE:         saveSnapshot,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().BoolVarP(&forceSnapshotSave, "force", "f", false, "overwrite an existing snapshot with the same name")
+	return cmd
+}
+
+func newSnapshotLoadCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "load [snapshotName]",
+		Short: "Restore the local network state from a named snapshot",
+		Long: `The network snapshot load command starts the local network from the given
+snapshot. If a local network is already running, its state is first saved into the
+default snapshot, as network stop does.
+
+The snapshot itself is not modified by the running network, so it can be loaded
+again later to go back to the same state.`,
+		RunE:         loadSnapshot,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	addAvalancheGoFlags(cmd)
+	return cmd
+}
+
+func newSnapshotDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete [snapshotName]",
+		Short: "Delete a named local network snapshot",
+		Long: `The network snapshot delete command removes the given snapshot, along with
+the CLI data stored for it. The default snapshot can't be deleted, use
+network clean instead.
+
+The command prompts for confirmation. To skip it, provide the --force flag.`,
+		RunE:         deleteSnapshot,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().BoolVarP(&forceSnapshotDelete, "force", "f", false, "delete the snapshot without confirmation")
+	return cmd
+}
+
+func saveSnapshot(_ *cobra.Command, args []string) error {
+	name := args[0]
+	if err := snapshot.ValidateName(name); err != nil {
+		return err
+	}
+	existing, err := snapshot.Get(app.GetSnapshotsDir(), name)
+	switch {
+	case err == nil && !forceSnapshotSave:
+		return fmt.Errorf("snapshot %s already exists. Use --force to overwrite it", name)
+	case err != nil && !errors.Is(err, snapshot.ErrNotFound):
+		return err
+	}
+	cli, err := binutils.NewGRPCClient(
+		binutils.WithDialTimeout(constants.FastGRPCDialTimeout),
+	)
+	if errors.Is(err, binutils.ErrGRPCTimeout) {
+		return errNoLocalNetwork
+	}
+	if err != nil {
+		return err
+	}
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	bootstrapped, err := checkNetworkIsAlreadyBootstrapped(ctx, cli)
+	if err != nil {
+		return err
+	}
+	if !bootstrapped {
+		return errNoLocalNetwork
+	}
+	saveName := name
+	if existing.Name != "" {
+		// the existing snapshot is only replaced once the new one is saved
+		saveName = name + savingSnapshotSuffix
+		if stale, err := snapshot.Get(app.GetSnapshotsDir(), saveName); err == nil {
+			if err := snapshot.Remove(app, stale); err != nil {
+				return err
+			}
+		}
+	}
+	ux.Logger.PrintToUser("Saving local network state as snapshot %s...", name)
+	if _, err := cli.SaveSnapshot(ctx, saveName); err != nil {
+		return fmt.Errorf("failed to save snapshot %s: %w", name, err)
+	}
+	if err := storeSnapshotData(saveName, true); err != nil {
+		return err
+	}
+	if existing.Name != "" {
+		saved, err := snapshot.Get(app.GetSnapshotsDir(), saveName)
+		if err != nil {
+			return err
+		}
+		if err := snapshot.Remove(app, existing); err != nil {
+			return err
+		}
+		if err := snapshot.Rename(app, saved, name); err != nil {
+			return fmt.Errorf("failed to rename snapshot %s to %s: %w", saveName, name, err)
+		}
+	}
+	ux.Logger.PrintToUser("Snapshot %s saved. Resuming the network from it", name)
+	snapshotName = name
+	return StartNetwork(nil, nil)
+}
+
+func loadSnapshot(_ *cobra.Command, args []string) error {
+	name := args[0]
+	if _, err := snapshot.Get(app.GetSnapshotsDir(), name); err != nil {
+		return err
+	}
+	cli, err := binutils.NewGRPCClient(
+		binutils.WithAvoidRPCVersionCheck(true),
+		binutils.WithDialTimeout(constants.FastGRPCDialTimeout),
+	)
+	if err == nil {
+		ctx, cancel := utils.GetANRContext()
+		defer cancel()
+		bootstrapped, err := checkNetworkIsAlreadyBootstrapped(ctx, cli)
+		if err != nil {
+			return err
+		}
+		if bootstrapped {
+			ux.Logger.PrintToUser("Saving the running network state into the default snapshot...")
+			snapshotName = constants.DefaultSnapshotName
+			if err := saveNetwork(); err != nil {
+				return err
+			}
+			if err := storeSnapshotData(snapshotName, false); err != nil {
+				return err
+			}
+		}
+	} else if !errors.Is(err, binutils.ErrGRPCTimeout) {
+		return err
+	}
+	snapshotName = name
+	return StartNetwork(nil, nil)
+}
+
+func deleteSnapshot(_ *cobra.Command, args []string) error {
+	s, err := snapshot.Get(app.GetSnapshotsDir(), args[0])
+	if err != nil {
+		return err
+	}
+	if !forceSnapshotDelete {
+		conf, err := app.Prompt.CaptureNoYes(fmt.Sprintf("Are you sure you want to delete snapshot %s?", s.Name))
+		if err != nil {
+			return err
+		}
+		if !conf {
+			ux.Logger.PrintToUser("Delete cancelled")
+			return nil
+		}
+	}
+	if err := snapshot.Remove(app, s); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Snapshot %s deleted", s.Name)
+	return nil
+}
+
+func newSnapshotListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "list",
+		Short:        "List local network snapshots",
+		Long:         "The network snapshot list command lists the saved local network snapshots, newest first.",
+		RunE:         listSnapshots,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+}
+
+func newSnapshotPruneCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove old local network snapshots",
+		Long: `The network snapshot prune command removes the oldest auto snapshots,
+keeping the newest ones.
+
+By default it keeps the amount of snapshots configured with
+'metal config snapshots --keep'. User named snapshots are only removed
+if --all is given. The default snapshot is never removed.`,
+		RunE:         pruneSnapshots,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().IntVar(&pruneKeep, "keep", -1, "number of snapshots to keep (defaults to the configured retention)")
+	cmd.Flags().BoolVar(&pruneIncludeManual, "all", false, "also prune user named snapshots")
+	cmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "only print the snapshots that would be removed")
+	return cmd
+}
+
+func listSnapshots(*cobra.Command, []string) error {
+	snapshots, err := snapshot.List(app.GetSnapshotsDir())
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		ux.Logger.PrintToUser("No snapshots found")
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Name", "Auto", "Last Modified"})
+	for _, s := range snapshots {
+		table.Append([]string{s.Name, fmt.Sprintf("%t", s.IsAuto()), s.ModTime.Format(constants.TimeParseLayout)})
+	}
+	table.Render()
+	return nil
+}
+
+func pruneSnapshots(*cobra.Command, []string) error {
+	keep := pruneKeep
+	if keep < 0 {
+		keep = snapshot.GetSettings(app).Keep
+		if keep <= 0 {
+			return errors.New("no snapshot retention configured. Use --keep or set it with 'metal config snapshots --keep'")
+		}
+	}
+	snapshots, err := snapshot.List(app.GetSnapshotsDir())
+	if err != nil {
+		return err
+	}
+	toPrune := snapshot.ToPrune(snapshots, keep, pruneIncludeManual)
+	if len(toPrune) == 0 {
+		ux.Logger.PrintToUser("Nothing to prune")
+		return nil
+	}
+	for _, s := range toPrune {
+		if pruneDryRun {
+			ux.Logger.PrintToUser("Would remove snapshot %s", s.Name)
+			continue
+		}
+		if err := snapshot.Remove(app, s); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Removed snapshot %s", s.Name)
+	}
+	return nil
+}
@@ -4,26 +4,37 @@ package networkcmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/MetalBlockchain/metal-cli/pkg/binutils"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/localnetworkbackend"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
 	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
 	"github.com/MetalBlockchain/metal-cli/pkg/utils"
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
 	"github.com/MetalBlockchain/metal-cli/pkg/vm"
 	"github.com/MetalBlockchain/metal-network-runner/client"
+	"github.com/MetalBlockchain/metal-network-runner/rpcpb"
 	"github.com/MetalBlockchain/metal-network-runner/server"
 	anrutils "github.com/MetalBlockchain/metal-network-runner/utils"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/exp/slices"
 )
 
 var (
 	userProvidedAvagoVersion string
 	snapshotName             string
 	avagoBinaryPath          string
+	numNodes                 uint32
+	httpPort                 int
+	stakingPort              int
+	httpHost                 string
+	forceNumNodes            bool
 )
 
 const (
@@ -39,25 +50,100 @@ func newStartCmd() *cobra.Command {
 
 By default, the command loads the default snapshot. If you provide the --snapshot-name
 flag, the network loads that snapshot instead. The command fails if the local network is
-already running.`,
+already running.
+
+The default snapshot runs five nodes. Use --num-nodes to set a different amount: below
+five, the network is based on a single validator node, and above it on the five default
+validators, adding non validating nodes up to the requested amount. Changing the
+default snapshot base of an existing network resets its state, removing its deployed
+subnets, so it requires --force (or network clean first). The number of nodes is
+recorded with the network state, so it is kept when the network is stopped and started
+again.
+
+Use --metalgo-version (or --avalanchego-version) to run a specific metalgo release,
+which is downloaded and cached on first use, or --metalgo-path (or --avalanchego-path)
+to run a locally built binary. The given version or binary is pinned to the network
+state, and reused on the next starts until another one is given. Pass
+--metalgo-version latest to go back to the latest compatible release.
+
+Use --name to start a named local network instead, isolated from the default one:
+it gets its own backend ports, node data and snapshots, so several local networks
+can run at the same time. The named network is created on its first start.
+
+Use --http-port and --staking-port to set the ports of the first node (the next
+nodes use the following ports, two by node), and --http-host to set the address
+the node APIs listen on (eg 0.0.0.0 to reach them from other containers or
+machines). These settings are saved into the config file, so they are used on the
+next starts too. They can also be set with metal config localNetwork.
+
+Use --backend to run the nodes with another backend than the avalanche network
+runner (anr, the default):
+  docker  runs each node in a docker-compose service, using the host network.
+          Requires docker-compose, and a docker engine supporting host networking
+  k8s     runs each node in a Deployment of a single node kind cluster. Requires
+          kind and kubectl
+These backends run the metalblockchain/metalgo image (--metalgo-version sets its
+tag) with the validators of the default snapshot. Their node data is kept when the
+network is stopped, and removed by network clean. The backend is recorded, so the
+other network commands use it too, until network clean. Snapshots and metalgo
+binaries require the anr backend. Subnet deploy installs the VM into the plugin dir
+mounted by the nodes, and restarts them tracking the new subnet (linux hosts only).`,
 
 		RunE:         StartNetwork,
 		Args:         cobra.ExactArgs(0),
 		SilenceUsage: true,
 	}
 
-	cmd.Flags().StringVar(&userProvidedAvagoVersion, "metalgo-version", latest, "use this version of metalgo (ex: v1.17.12)")
-	cmd.Flags().StringVar(&avagoBinaryPath, "metalgo-path", "", "use this avalanchego binary path")
+	addAvalancheGoFlags(cmd)
 	cmd.Flags().StringVar(&snapshotName, "snapshot-name", constants.DefaultSnapshotName, "name of snapshot to use to start the network from")
+	cmd.Flags().Uint32Var(&numNodes, "num-nodes", 0, "number of nodes of the local network (default: the number of nodes of the previous start, or of the snapshot)")
+	cmd.Flags().BoolVar(&forceNumNodes, "force", false, "change the number of nodes of an existing network even if it resets its state")
+	cmd.Flags().IntVar(&httpPort, "http-port", 0, "HTTP port of the first node (default: the configured one, or 9650)")
+	cmd.Flags().IntVar(&stakingPort, "staking-port", 0, "staking port of the first node (default: the configured one, or 9651)")
+	cmd.Flags().StringVar(&httpHost, "http-host", "", "address the node APIs listen on (default: the configured one, or 127.0.0.1)")
+	cmd.Flags().StringVar(&backendName, "backend", "", "backend running the nodes: anr, docker or k8s (default: the current one, or anr)")
 
 	return cmd
 }
 
 func StartNetwork(*cobra.Command, []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), backendTimeout)
+	defer cancel()
+	name, err := getStartBackendName(ctx)
+	if err != nil {
+		return err
+	}
+	backend, err := getBackend(name)
+	if err != nil {
+		return err
+	}
+	if name != localnetworkbackend.ANR {
+		return startContainerNetwork(ctx, name, backend)
+	}
+	if err := localnetworkbackend.SetCurrentName(app.GetRunDir(), name); err != nil {
+		return err
+	}
+	_, err = backend.Start(ctx, localnetworkbackend.Config{})
+	return err
+}
+
+// startANRNetwork starts the local network with the network runner, from
+// the snapshot given by flag
+func startANRNetwork() error {
 	var (
 		err          error
 		avagoVersion string
 	)
+	if err := applyNumNodes(); err != nil {
+		return err
+	}
+	if err := applyPinnedAvalancheGo(); err != nil {
+		return err
+	}
+	settings, err := saveLocalNetworkSettings()
+	if err != nil {
+		return err
+	}
 	if avagoBinaryPath == "" {
 		avagoVersion, err = determineAvagoVersion(userProvidedAvagoVersion)
 		if err != nil {
@@ -129,27 +215,46 @@ func StartNetwork(*cobra.Command, []string) error {
 	if err != nil {
 		return err
 	}
+	configStr, err = settings.GlobalNodeConfig(configStr)
+	if err != nil {
+		return err
+	}
 	if configStr != "" {
 		loadSnapshotOpts = append(loadSnapshotOpts, client.WithGlobalNodeConfig(configStr))
 	}
 
+	if err := subnet.SetSnapshotNodePorts(app.GetSnapshotsDir(), snapshotName, settings); err != nil {
+		return err
+	}
+
 	ux.Logger.PrintToUser("Booting Network. Wait until healthy...")
-	resp, err := cli.LoadSnapshot(
+	resp, err := subnet.LoadSnapshotWithProgress(
 		ctx,
+		cli,
+		app.GetSnapshotsDir(),
 		snapshotName,
+		outputDir,
 		loadSnapshotOpts...,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to start network with the persisted snapshot: %w", err)
 	}
 
-	ux.Logger.PrintToUser("Node logs directory: %s/node<i>/logs", resp.ClusterInfo.RootDataDir)
+	clusterInfo := resp.ClusterInfo
+	if numNodes != 0 {
+		clusterInfo, err = addLocalNodes(ctx, cli, clusterInfo, numNodes, avalancheGoBinPath, configStr, settings)
+		if err != nil {
+			return err
+		}
+	}
+
+	ux.Logger.PrintToUser("Node logs directory: %s/node<i>/logs", clusterInfo.RootDataDir)
 	ux.Logger.PrintToUser("Network ready to use.")
 
-	if subnet.HasEndpoints(resp.ClusterInfo) {
+	if subnet.HasEndpoints(clusterInfo) {
 		ux.Logger.PrintToUser("")
 		ux.Logger.PrintToUser("Local network node endpoints:")
-		if err := ux.PrintEndpointTables(resp.ClusterInfo); err != nil {
+		if err := ux.PrintEndpointTables(clusterInfo); err != nil {
 			return err
 		}
 	}
@@ -171,9 +276,177 @@ func StartNetwork(*cobra.Command, []string) error {
 		}
 	}
 
+	return subnet.UpdateExtraLocalNetworkData(app, func(data *subnet.ExtraLocalNetworkData) {
+		data.NumNodes = uint32(len(clusterInfo.NodeNames))
+		data.AvalancheGoPath = avagoBinaryPath
+		data.AvalancheGoVersion = ""
+		if avagoBinaryPath == "" && userProvidedAvagoVersion != latest {
+			data.AvalancheGoVersion = userProvidedAvagoVersion
+		}
+	})
+}
+
+// applyNumNodes makes the default snapshot keep the number of nodes of its previous
+// start, unless another one is given by --num-nodes. As the default snapshot is based
+// on either one or five validators, switching between them resets the network state,
+// which requires --force for an existing network
+func applyNumNodes() error {
+	if snapshotName != constants.DefaultSnapshotName {
+		return nil
+	}
+	data, err := subnet.GetExtraLocalNetworkData(app)
+	if errors.Is(err, os.ErrNotExist) {
+		data = &subnet.ExtraLocalNetworkData{}
+	} else if err != nil {
+		return err
+	}
+	if numNodes == 0 {
+		numNodes = data.NumNodes
+		return nil
+	}
+	singleNode := numNodes < constants.LocalNetworkNumNodes
+	if singleNode == app.Conf.GetConfigBoolValue(constants.ConfigSingleNodeEnabledKey) {
+		return nil
+	}
+	if data.NumNodes != 0 && !forceNumNodes {
+		return fmt.Errorf(
+			"the local network has %d nodes on a %s validators base, and %d nodes require a %s one, which resets the network state and removes its deployed subnets. Use --force to do it anyway, or 'metal network clean' first",
+			data.NumNodes,
+			baseNumNodesStr(!singleNode),
+			numNodes,
+			baseNumNodesStr(singleNode),
+		)
+	}
+	ux.Logger.PrintToUser("Switching the default snapshot to a %s validators base. This resets the local network state", baseNumNodesStr(singleNode))
+	return app.Conf.SetConfigValue(constants.ConfigSingleNodeEnabledKey, singleNode)
+}
+
+// saveLocalNetworkSettings saves the node ports and bind address given by flag into
+// the config, and returns the resulting settings
+func saveLocalNetworkSettings() (subnet.LocalNetworkSettings, error) {
+	for _, setting := range []struct {
+		key   string
+		value interface{}
+		isSet bool
+	}{
+		{constants.ConfigLocalHTTPPortKey, httpPort, httpPort != 0},
+		{constants.ConfigLocalStakingPortKey, stakingPort, stakingPort != 0},
+		{constants.ConfigLocalHTTPHostKey, httpHost, httpHost != ""},
+	} {
+		if !setting.isSet {
+			continue
+		}
+		if err := app.Conf.SetConfigValue(setting.key, setting.value); err != nil {
+			return subnet.LocalNetworkSettings{}, err
+		}
+	}
+	settings := subnet.GetLocalNetworkSettings(app)
+	if err := settings.Validate(); err != nil {
+		return subnet.LocalNetworkSettings{}, err
+	}
+	models.SetLocalAPIPort(settings.HTTPPort)
+	return settings, nil
+}
+
+func addAvalancheGoFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&userProvidedAvagoVersion, "metalgo-version", "", "use this version of metalgo (ex: v1.17.12) [default: the pinned version, or latest]")
+	cmd.Flags().StringVar(&avagoBinaryPath, "metalgo-path", "", "use this metalgo binary path")
+	cmd.Flags().SetNormalizeFunc(func(_ *pflag.FlagSet, name string) pflag.NormalizedName {
+		switch name {
+		case "avalanchego-version":
+			name = "metalgo-version"
+		case "avalanchego-path":
+			name = "metalgo-path"
+		}
+		return pflag.NormalizedName(name)
+	})
+}
+
+// applyPinnedAvalancheGo sets the metalgo version or binary path pinned to the
+// snapshot to start, if none was given by flag
+func applyPinnedAvalancheGo() error {
+	if userProvidedAvagoVersion != "" || avagoBinaryPath != "" {
+		return nil
+	}
+	userProvidedAvagoVersion = latest
+	if version := app.Conf.GetConfigStringValue(constants.ConfigAvalancheGoVersionKey); version != "" {
+		userProvidedAvagoVersion = version
+	}
+	pinned, err := subnet.GetSnapshotExtraLocalNetworkData(app, snapshotName)
+	if err != nil {
+		return err
+	}
+	switch {
+	case pinned.AvalancheGoPath != "":
+		if !utils.FileExists(pinned.AvalancheGoPath) {
+			ux.Logger.PrintToUser("Pinned metalgo binary %s not found. Using latest metalgo version", pinned.AvalancheGoPath)
+			return nil
+		}
+		ux.Logger.PrintToUser("Using pinned metalgo binary %s", pinned.AvalancheGoPath)
+		avagoBinaryPath = pinned.AvalancheGoPath
+	case pinned.AvalancheGoVersion != "":
+		ux.Logger.PrintToUser("Using pinned metalgo version %s", pinned.AvalancheGoVersion)
+		userProvidedAvagoVersion = pinned.AvalancheGoVersion
+	}
 	return nil
 }
 
+func baseNumNodesStr(singleNode bool) string {
+	if singleNode {
+		return "single node"
+	}
+	return fmt.Sprintf("%d nodes", constants.LocalNetworkNumNodes)
+}
+
+// addLocalNodes adds non validating nodes to the running local network until it
+// has [numNodes] nodes, and waits for it to be healthy
+func addLocalNodes(
+	ctx context.Context,
+	cli client.Client,
+	clusterInfo *rpcpb.ClusterInfo,
+	numNodes uint32,
+	avalancheGoBinPath string,
+	globalNodeConfig string,
+	settings subnet.LocalNetworkSettings,
+) (*rpcpb.ClusterInfo, error) {
+	currentNumNodes := uint32(len(clusterInfo.NodeNames))
+	if numNodes < currentNumNodes {
+		return nil, fmt.Errorf(
+			"snapshot %s already has %d nodes, can't start it with %d. Use 'metal network clean' to start from scratch",
+			snapshotName,
+			currentNumNodes,
+			numNodes,
+		)
+	}
+	if numNodes == currentNumNodes {
+		return clusterInfo, nil
+	}
+	ux.Logger.PrintToUser("Adding %d nodes to the network...", numNodes-currentNumNodes)
+	// follow the node<i> naming of the snapshot nodes, skipping names in use. Nodes
+	// are only launched here, so all of them bootstrap at the same time
+	for i, added := 1, currentNumNodes; added < numNodes; i++ {
+		nodeName := fmt.Sprintf("node%d", i)
+		if slices.Contains(clusterInfo.NodeNames, nodeName) {
+			continue
+		}
+		nodeConfig, err := settings.AddedNodeConfig(globalNodeConfig, i-1)
+		if err != nil {
+			return nil, err
+		}
+		addNodeOpts := []client.OpOption{
+			client.WithPluginDir(app.GetPluginsDir()),
+			client.WithGlobalNodeConfig(nodeConfig),
+		}
+		resp, err := cli.AddNode(ctx, nodeName, avalancheGoBinPath, addNodeOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add node %s: %w", nodeName, err)
+		}
+		clusterInfo = resp.ClusterInfo
+		added++
+	}
+	return subnet.WaitForAddedNodesWithProgress(ctx, cli, clusterInfo)
+}
+
 func determineAvagoVersion(userProvidedAvagoVersion string) (string, error) {
 	// a specific user provided version should override this calculation, so just return
 	if userProvidedAvagoVersion != latest {
@@ -202,15 +475,15 @@ func determineAvagoVersion(userProvidedAvagoVersion string) (string, error) {
 
 		// if you have a custom vm, you must provide the version explicitly
 		// if you upgrade from subnet-evm to a custom vm, the RPC version will be 0
-		if sc.VM == models.CustomVM || sc.Networks[models.Local.String()].RPCVersion == 0 {
+		if sc.VM == models.CustomVM || sc.Networks[models.NewLocalNetwork().Name()].RPCVersion == 0 {
 			continue
 		}
 
 		if currentRPCVersion == -1 {
-			currentRPCVersion = sc.Networks[models.Local.String()].RPCVersion
+			currentRPCVersion = sc.Networks[models.NewLocalNetwork().Name()].RPCVersion
 		}
 
-		if sc.Networks[models.Local.String()].RPCVersion != currentRPCVersion {
+		if sc.Networks[models.NewLocalNetwork().Name()].RPCVersion != currentRPCVersion {
 			return "", fmt.Errorf(
 				"RPC version mismatch. Expected %d, got %d for Subnet %s. Upgrade all subnets to the same RPC version to launch the network",
 				currentRPCVersion,
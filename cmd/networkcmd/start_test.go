@@ -4,11 +4,21 @@
 package networkcmd
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/MetalBlockchain/metal-network-runner/rpcpb"
+
 	"github.com/MetalBlockchain/metal-cli/internal/mocks"
 	"github.com/MetalBlockchain/metal-cli/internal/testutils"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
 	"github.com/MetalBlockchain/metalgo/ids"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -156,3 +166,115 @@ func Test_determineAvagoVersion(t *testing.T) {
 		})
 	}
 }
+
+// writeHealthyNodes writes the process context of healthy nodes [nodeNames] into
+// [networkDir], as the network runner nodes do once started
+func writeHealthyNodes(t *testing.T, networkDir string, nodeNames []string) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		var result interface{} = map[string]bool{"isBootstrapped": true}
+		if r.URL.Path == "/ext/health" {
+			result = map[string]interface{}{"checks": map[string]interface{}{}, "healthy": true}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(server.Close)
+	for _, nodeName := range nodeNames {
+		nodeDir := filepath.Join(networkDir, nodeName)
+		require.NoError(t, os.MkdirAll(nodeDir, constants.DefaultPerms755))
+		processContext := []byte(`{"pid": 1, "uri": "` + server.URL + `"}`)
+		require.NoError(t, os.WriteFile(filepath.Join(nodeDir, "process.json"), processContext, constants.WriteReadReadPerms))
+	}
+}
+
+func Test_addLocalNodes(t *testing.T) {
+	app = testutils.SetupTestInTempDir(t)
+	snapshotName = "test"
+	networkDir := filepath.Join(t.TempDir(), "network_20240101_000000")
+	clusterInfo := &rpcpb.ClusterInfo{NodeNames: []string{"node1", "node3"}, RootDataDir: networkDir}
+	addedInfo := &rpcpb.ClusterInfo{NodeNames: []string{"node1", "node2", "node3"}, RootDataDir: networkDir}
+	healthyInfo := &rpcpb.ClusterInfo{NodeNames: []string{"node1", "node2", "node3", "node4"}, RootDataDir: networkDir, Healthy: true}
+
+	t.Run("less nodes than snapshot", func(t *testing.T) {
+		cli := &mocks.Client{}
+		_, err := addLocalNodes(context.Background(), cli, clusterInfo, 1, "metalgo", "", subnet.GetLocalNetworkSettings(app))
+		require.ErrorContains(t, err, "already has 2 nodes")
+		cli.AssertNotCalled(t, "AddNode")
+	})
+
+	t.Run("same nodes as snapshot", func(t *testing.T) {
+		cli := &mocks.Client{}
+		info, err := addLocalNodes(context.Background(), cli, clusterInfo, 2, "metalgo", "", subnet.GetLocalNetworkSettings(app))
+		require.NoError(t, err)
+		require.Equal(t, clusterInfo, info)
+		cli.AssertNotCalled(t, "AddNode")
+	})
+
+	t.Run("add nodes skipping used names", func(t *testing.T) {
+		cli := &mocks.Client{}
+		cli.On("AddNode", mock.Anything, "node2", "metalgo", mock.Anything, mock.Anything).Return(&rpcpb.AddNodeResponse{ClusterInfo: addedInfo}, nil)
+		cli.On("AddNode", mock.Anything, "node4", "metalgo", mock.Anything, mock.Anything).Return(&rpcpb.AddNodeResponse{ClusterInfo: healthyInfo}, nil)
+		cli.On("Status", mock.Anything).Return(&rpcpb.StatusResponse{ClusterInfo: healthyInfo}, nil)
+		// the added nodes are polled until healthy, instead of waiting on the network runner
+		writeHealthyNodes(t, networkDir, healthyInfo.NodeNames)
+		info, err := addLocalNodes(context.Background(), cli, clusterInfo, 4, "metalgo", "", subnet.GetLocalNetworkSettings(app))
+		require.NoError(t, err)
+		require.Equal(t, healthyInfo, info)
+		cli.AssertNumberOfCalls(t, "AddNode", 2)
+		cli.AssertCalled(t, "AddNode", mock.Anything, "node2", "metalgo", mock.Anything, mock.Anything)
+		cli.AssertCalled(t, "AddNode", mock.Anything, "node4", "metalgo", mock.Anything, mock.Anything)
+		cli.AssertNotCalled(t, "WaitForHealthy", mock.Anything)
+	})
+}
+
+func Test_applyPinnedAvalancheGo(t *testing.T) {
+	app = testutils.SetupTestInTempDir(t)
+	snapshotName = "pinned"
+	storedPath := filepath.Join(app.GetExtraLocalNetworkSnapshotsDir(), snapshotName+jsonExt)
+	require.NoError(t, os.MkdirAll(filepath.Dir(storedPath), constants.DefaultPerms755))
+	require.NoError(t, os.WriteFile(storedPath, []byte(`{"AvalancheGoVersion":"v1.11.3"}`), constants.WriteReadReadPerms))
+
+	// pinned version is used if none is given
+	userProvidedAvagoVersion, avagoBinaryPath = "", ""
+	require.NoError(t, applyPinnedAvalancheGo())
+	require.Equal(t, "v1.11.3", userProvidedAvagoVersion)
+
+	// flags take precedence over the pinned version
+	userProvidedAvagoVersion = latest
+	require.NoError(t, applyPinnedAvalancheGo())
+	require.Equal(t, latest, userProvidedAvagoVersion)
+
+	// no pin defaults to latest
+	snapshotName = "unpinned"
+	userProvidedAvagoVersion = ""
+	require.NoError(t, applyPinnedAvalancheGo())
+	require.Equal(t, latest, userProvidedAvagoVersion)
+	require.Empty(t, avagoBinaryPath)
+}
+
+func Test_applyNumNodes(t *testing.T) {
+	app = testutils.SetupTestInTempDir(t)
+	snapshotName = constants.DefaultSnapshotName
+	forceNumNodes = false
+	require.NoError(t, os.MkdirAll(app.GetRunDir(), constants.DefaultPerms755))
+	require.NoError(t, subnet.UpdateExtraLocalNetworkData(app, func(data *subnet.ExtraLocalNetworkData) {
+		data.NumNodes = 7
+	}))
+
+	// the number of nodes of the previous start is kept
+	numNodes = 0
+	require.NoError(t, applyNumNodes())
+	require.Equal(t, uint32(7), numNodes)
+
+	// same base, nodes are added
+	numNodes = 9
+	require.NoError(t, applyNumNodes())
+
+	// switching to a single node base resets the network, so it must be forced
+	numNodes = 1
+	require.ErrorContains(t, applyNumNodes(), "--force")
+}
@@ -3,72 +3,432 @@
 package networkcmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
 	"github.com/MetalBlockchain/metal-cli/pkg/binutils"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/localnetworkbackend"
+	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
 	"github.com/MetalBlockchain/metal-cli/pkg/utils"
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metal-network-runner/rpcpb"
 	"github.com/MetalBlockchain/metal-network-runner/server"
+	"github.com/MetalBlockchain/metalgo/api/health"
+	"github.com/MetalBlockchain/metalgo/api/info"
+	"github.com/olekukonko/tablewriter"
+	"github.com/shirou/gopsutil/process"
 	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
+var errNetworkUnhealthy = ux.NewCodedError(ux.ErrCodeNetworkUnhealthy, errors.New("local network is not healthy"))
+
+// networkStatusInfo is the status of a local network, as printed by network status --json
+type networkStatusInfo struct {
+	Name                string             `json:"name,omitempty"`
+	Backend             string             `json:"backend,omitempty"`
+	Running             bool               `json:"running"`
+	Healthy             bool               `json:"healthy"`
+	CustomChainsHealthy bool               `json:"customChainsHealthy"`
+	NetworkID           uint32             `json:"networkID,omitempty"`
+	RootDataDir         string             `json:"rootDataDir,omitempty"`
+	Nodes               []nodeStatusInfo   `json:"nodes,omitempty"`
+	Blockchains         []blockchainStatus `json:"blockchains,omitempty"`
+}
+
+type nodeStatusInfo struct {
+	Name           string   `json:"name"`
+	NodeID         string   `json:"nodeID"`
+	URI            string   `json:"uri"`
+	HTTPPort       int      `json:"httpPort,omitempty"`
+	StakingPort    int      `json:"stakingPort,omitempty"`
+	Healthy        bool     `json:"healthy"`
+	Bootstrapped   bool     `json:"bootstrapped"`
+	Paused         bool     `json:"paused,omitempty"`
+	TrackedSubnets []string `json:"trackedSubnets"`
+	// seconds since the node process started
+	UptimeSeconds int64 `json:"uptimeSeconds,omitempty"`
+}
+
+type blockchainStatus struct {
+	Name         string   `json:"name"`
+	BlockchainID string   `json:"blockchainID"`
+	SubnetID     string   `json:"subnetID"`
+	VMID         string   `json:"vmID"`
+	RPCURLs      []string `json:"rpcURLs"`
+}
+
+// nodeProbe is what is learned about a running node from its APIs and process
+type nodeProbe struct {
+	healthy      bool
+	bootstrapped bool
+	stakingPort  int
+	uptime       time.Duration
+}
+
 func newStatusCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Prints the status of the local network",
 		Long: `The network status command prints whether or not a local Avalanche
-network is running and some basic stats about the network.`,
+network is running and some basic stats about the network.
+
+For each node, it prints its health, whether all its chains are bootstrapped,
+the subnets it tracks, its HTTP and staking ports and its uptime. For each
+deployed blockchain, it prints the RPC URLs of all nodes.
+
+Use --json to get the status as JSON, eg for scripting. The command exits with
+a non-zero code if the network is running but not healthy.
+
+Use --name to get the status of a named local network. The names of the
+existing named local networks are printed along with the default network status.`,
 
 		RunE:         networkStatus,
 		Args:         cobra.ExactArgs(0),
 		SilenceUsage: true,
 	}
+	flags.AddJSONOutputFlag(cmd)
+	return cmd
 }
 
 func networkStatus(*cobra.Command, []string) error {
 	ux.Logger.PrintToUser("Requesting network status...")
 
+	statusInfo := networkStatusInfo{Name: app.GetLocalNetworkName()}
+	if name := localnetworkbackend.CurrentName(app.GetRunDir()); name != localnetworkbackend.ANR {
+		backend, err := getBackend(name)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := utils.GetAPIContext()
+		defer cancel()
+		nodes, err := backend.Status(ctx)
+		if err != nil {
+			return err
+		}
+		statusInfo = buildBackendNetworkStatus(statusInfo.Name, name, nodes)
+		return printStatus(statusInfo)
+	}
 	cli, err := binutils.NewGRPCClient(
 		binutils.WithDialTimeout(constants.FastGRPCDialTimeout),
 	)
-	if err != nil {
+	switch {
+	case errors.Is(err, binutils.ErrGRPCTimeout):
+		// no backend, so no network running
+	case err != nil:
 		return err
+	default:
+		ctx, cancel := utils.GetAPIContext()
+		defer cancel()
+		status, err := cli.Status(ctx)
+		if err != nil && !server.IsServerError(err, server.ErrNotBootstrapped) {
+			return err
+		}
+		if err == nil && status != nil && status.ClusterInfo != nil {
+			if _, err := subnet.RemoveUndeployedChains(app, status.ClusterInfo); err != nil {
+				return err
+			}
+			processes := getNodeProcesses()
+			statusInfo = buildNetworkStatus(statusInfo.Name, status.ClusterInfo, func(nodeInfo *rpcpb.NodeInfo) nodeProbe {
+				return probeNode(ctx, nodeInfo, status.ClusterInfo, processes)
+			})
+		}
 	}
+	return printStatus(statusInfo)
+}
 
-	ctx, cancel := utils.GetAPIContext()
-	defer cancel()
-	status, err := cli.Status(ctx)
-	if err != nil {
-		if server.IsServerError(err, server.ErrNotBootstrapped) {
-			ux.Logger.PrintToUser("No local network running")
-			return nil
+// printStatus prints [statusInfo], failing if the network is unhealthy
+func printStatus(statusInfo networkStatusInfo) error {
+	if ux.JSONOutput() {
+		if err := ux.PrintResult(statusInfo); err != nil {
+			return err
 		}
-		return err
+	} else {
+		printNetworkStatus(statusInfo)
+		if statusInfo.Name == "" {
+			if err := printNamedLocalNetworks(); err != nil {
+				return err
+			}
+		}
+	}
+	if statusInfo.Running && !statusInfo.Healthy {
+		return errNetworkUnhealthy
+	}
+	return nil
+}
+
+// buildBackendNetworkStatus gathers the status of the network run by the
+// container based backend [backend], given its running [nodes]
+func buildBackendNetworkStatus(name string, backend string, nodes []localnetworkbackend.NodeInfo) networkStatusInfo {
+	statusInfo := networkStatusInfo{
+		Name:                name,
+		Backend:             backend,
+		Running:             len(nodes) > 0,
+		Healthy:             true,
+		CustomChainsHealthy: true,
+		Nodes:               []nodeStatusInfo{},
+	}
+	for _, node := range nodes {
+		statusInfo.Healthy = statusInfo.Healthy && node.Healthy
+		statusInfo.Nodes = append(statusInfo.Nodes, nodeStatusInfo{
+			Name:           node.Name,
+			NodeID:         node.NodeID,
+			URI:            node.URI,
+			HTTPPort:       getURIPort(node.URI),
+			Healthy:        node.Healthy,
+			Bootstrapped:   node.Healthy,
+			TrackedSubnets: []string{},
+		})
 	}
+	return statusInfo
+}
 
-	// TODO: This layout may break some screens, is there a "failsafe" way?
-	if status != nil && status.ClusterInfo != nil {
-		ux.Logger.PrintToUser("Network is Up. Network information:")
-		ux.Logger.PrintToUser("==================================================================================================")
-		ux.Logger.PrintToUser("Healthy: %t", status.ClusterInfo.Healthy)
-		ux.Logger.PrintToUser("Custom VMs healthy: %t", status.ClusterInfo.CustomChainsHealthy)
-		ux.Logger.PrintToUser("Number of nodes: %d", len(status.ClusterInfo.NodeNames))
-		ux.Logger.PrintToUser("Number of custom VMs: %d", len(status.ClusterInfo.CustomChains))
-		ux.Logger.PrintToUser("======================================== Node information ========================================")
-		for n, nodeInfo := range status.ClusterInfo.NodeInfos {
-			ux.Logger.PrintToUser("%s has ID %s and endpoint %s ", n, nodeInfo.Id, nodeInfo.Uri)
-		}
-		ux.Logger.PrintToUser("==================================== Custom VM information =======================================")
-		for _, nodeInfo := range status.ClusterInfo.NodeInfos {
-			for blockchainID := range status.ClusterInfo.CustomChains {
-				ux.Logger.PrintToUser("Endpoint at %s for blockchain %q: %s/ext/bc/%s/rpc", nodeInfo.Name, blockchainID, nodeInfo.GetUri(), blockchainID)
+// buildNetworkStatus gathers the status of the network described by [clusterInfo],
+// getting the live data of each node from [probe]
+func buildNetworkStatus(
+	name string,
+	clusterInfo *rpcpb.ClusterInfo,
+	probe func(*rpcpb.NodeInfo) nodeProbe,
+) networkStatusInfo {
+	statusInfo := networkStatusInfo{
+		Name:                name,
+		Running:             true,
+		Healthy:             clusterInfo.Healthy,
+		CustomChainsHealthy: clusterInfo.CustomChainsHealthy,
+		NetworkID:           clusterInfo.NetworkId,
+		RootDataDir:         clusterInfo.RootDataDir,
+		Nodes:               []nodeStatusInfo{},
+		Blockchains:         []blockchainStatus{},
+	}
+	nodeNames := append([]string{}, clusterInfo.NodeNames...)
+	sort.Strings(nodeNames)
+	for _, nodeName := range nodeNames {
+		nodeInfo, ok := clusterInfo.NodeInfos[nodeName]
+		if !ok {
+			continue
+		}
+		nodeProbe := probe(nodeInfo)
+		trackedSubnets := []string{}
+		for _, subnetID := range strings.Split(nodeInfo.WhitelistedSubnets, ",") {
+			if subnetID = strings.TrimSpace(subnetID); subnetID != "" {
+				trackedSubnets = append(trackedSubnets, subnetID)
 			}
 		}
-	} else {
+		statusInfo.Nodes = append(statusInfo.Nodes, nodeStatusInfo{
+			Name:           nodeInfo.Name,
+			NodeID:         nodeInfo.Id,
+			URI:            nodeInfo.Uri,
+			HTTPPort:       getURIPort(nodeInfo.Uri),
+			StakingPort:    nodeProbe.stakingPort,
+			Healthy:        nodeProbe.healthy,
+			Bootstrapped:   nodeProbe.bootstrapped,
+			Paused:         nodeInfo.Paused,
+			TrackedSubnets: trackedSubnets,
+			UptimeSeconds:  int64(nodeProbe.uptime.Seconds()),
+		})
+	}
+	blockchainIDs := maps.Keys(clusterInfo.CustomChains)
+	sort.Strings(blockchainIDs)
+	for _, blockchainID := range blockchainIDs {
+		chainInfo := clusterInfo.CustomChains[blockchainID]
+		rpcURLs := []string{}
+		for _, node := range statusInfo.Nodes {
+			// only the nodes tracking the subnet serve the blockchain
+			if slices.Contains(node.TrackedSubnets, chainInfo.SubnetId) {
+				rpcURLs = append(rpcURLs, fmt.Sprintf("%s/ext/bc/%s/rpc", node.URI, blockchainID))
+			}
+		}
+		statusInfo.Blockchains = append(statusInfo.Blockchains, blockchainStatus{
+			Name:         chainInfo.ChainName,
+			BlockchainID: blockchainID,
+			SubnetID:     chainInfo.SubnetId,
+			VMID:         chainInfo.VmId,
+			RPCURLs:      rpcURLs,
+		})
+	}
+	return statusInfo
+}
+
+// probeNode queries the health and bootstrap state of the node APIs, and gets the
+// staking port and uptime from the node process, if found at [processes]
+func probeNode(
+	ctx context.Context,
+	nodeInfo *rpcpb.NodeInfo,
+	clusterInfo *rpcpb.ClusterInfo,
+	processes map[string]*process.Process,
+) nodeProbe {
+	var probe nodeProbe
+	if nodeInfo.Paused {
+		return probe
+	}
+	if reply, err := health.NewClient(nodeInfo.Uri).Health(ctx, nil); err == nil {
+		probe.healthy = reply.Healthy
+	}
+	infoClient := info.NewClient(nodeInfo.Uri)
+	chains := []string{"P", "X", "C"}
+	trackedSubnets := strings.Split(nodeInfo.WhitelistedSubnets, ",")
+	for blockchainID, chainInfo := range clusterInfo.CustomChains {
+		if slices.Contains(trackedSubnets, chainInfo.SubnetId) {
+			chains = append(chains, blockchainID)
+		}
+	}
+	probe.bootstrapped = true
+	for _, chain := range chains {
+		if bootstrapped, err := infoClient.IsBootstrapped(ctx, chain); err != nil || !bootstrapped {
+			probe.bootstrapped = false
+			break
+		}
+	}
+	if proc, ok := processes[filepath.Dir(nodeInfo.LogDir)]; ok {
+		if cmdline, err := proc.CmdlineSlice(); err == nil {
+			probe.stakingPort = getFlagIntValue(cmdline, "staking-port")
+		}
+		if createTime, err := proc.CreateTime(); err == nil {
+			probe.uptime = time.Since(time.UnixMilli(createTime))
+		}
+	}
+	return probe
+}
+
+// getNodeProcesses returns the running metalgo processes, by data dir
+func getNodeProcesses() map[string]*process.Process {
+	nodeProcesses := map[string]*process.Process{}
+	procs, err := process.Processes()
+	if err != nil {
+		app.Log.Debug("failed listing processes", zap.Error(err))
+		return nodeProcesses
+	}
+	for _, proc := range procs {
+		cmdline, err := proc.CmdlineSlice()
+		if err != nil {
+			// ignore errors for processes that just died (macos implementation)
+			continue
+		}
+		if dataDir := getFlagValue(cmdline, "data-dir"); dataDir != "" {
+			nodeProcesses[dataDir] = proc
+		}
+	}
+	return nodeProcesses
+}
+
+// getFlagValue returns the value of --[flag]=<value> at [args], or "" if not found
+func getFlagValue(args []string, flag string) string {
+	prefix := "--" + flag + "="
+	for _, arg := range args {
+		if strings.HasPrefix(arg, prefix) {
+			return strings.TrimPrefix(arg, prefix)
+		}
+	}
+	return ""
+}
+
+func getFlagIntValue(args []string, flag string) int {
+	value, err := strconv.Atoi(getFlagValue(args, flag))
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+func getURIPort(uri string) int {
+	parsedURI, err := url.Parse(uri)
+	if err != nil {
+		return 0
+	}
+	port, err := strconv.Atoi(parsedURI.Port())
+	if err != nil {
+		return 0
+	}
+	return port
+}
+
+func printNetworkStatus(statusInfo networkStatusInfo) {
+	if !statusInfo.Running {
 		ux.Logger.PrintToUser("No local network running")
+		return
 	}
+	// TODO: This layout may break some screens, is there a "failsafe" way?
+	ux.Logger.PrintToUser("Network is Up. Network information:")
+	ux.Logger.PrintToUser("==================================================================================================")
+	if statusInfo.Backend != "" {
+		ux.Logger.PrintToUser("Backend: %s", statusInfo.Backend)
+	}
+	ux.Logger.PrintToUser("Healthy: %t", statusInfo.Healthy)
+	ux.Logger.PrintToUser("Custom VMs healthy: %t", statusInfo.CustomChainsHealthy)
+	ux.Logger.PrintToUser("Number of nodes: %d", len(statusInfo.Nodes))
+	ux.Logger.PrintToUser("Number of custom VMs: %d", len(statusInfo.Blockchains))
+	ux.Logger.PrintToUser("Data directory: %s", statusInfo.RootDataDir)
+	ux.Logger.PrintToUser("======================================== Node information ========================================")
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Node", "Node ID", "Healthy", "Bootstrapped", "HTTP Port", "Staking Port", "Tracked Subnets", "Uptime"})
+	table.SetRowLine(true)
+	table.SetAutoMergeCells(false)
+	for _, node := range statusInfo.Nodes {
+		healthy := strconv.FormatBool(node.Healthy)
+		if node.Paused {
+			healthy = "paused"
+		}
+		table.Append([]string{
+			node.Name,
+			node.NodeID,
+			healthy,
+			strconv.FormatBool(node.Bootstrapped),
+			portStr(node.HTTPPort),
+			portStr(node.StakingPort),
+			strings.Join(node.TrackedSubnets, "\n"),
+			uptimeStr(node.UptimeSeconds),
+		})
+	}
+	table.Render()
+	for _, node := range statusInfo.Nodes {
+		ux.Logger.PrintToUser("%s has ID %s and endpoint %s ", node.Name, node.NodeID, node.URI)
+	}
+	ux.Logger.PrintToUser("==================================== Custom VM information =======================================")
+	for _, blockchain := range statusInfo.Blockchains {
+		ux.Logger.PrintToUser("Blockchain %s (ID %s, subnet %s):", blockchain.Name, blockchain.BlockchainID, blockchain.SubnetID)
+		for _, rpcURL := range blockchain.RPCURLs {
+			ux.Logger.PrintToUser("  %s", rpcURL)
+		}
+	}
+}
 
-	// TODO: verbose output?
-	// ux.Logger.PrintToUser(status.String())
+func portStr(port int) string {
+	if port == 0 {
+		return "-"
+	}
+	return strconv.Itoa(port)
+}
 
+func uptimeStr(seconds int64) string {
+	if seconds == 0 {
+		return "-"
+	}
+	return (time.Duration(seconds) * time.Second).String()
+}
+
+func printNamedLocalNetworks() error {
+	networks, err := app.LoadLocalNetworks()
+	if err != nil {
+		return err
+	}
+	if len(networks) == 0 {
+		return nil
+	}
+	names := maps.Keys(networks)
+	sort.Strings(names)
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Named local networks (select them with --name): %s", strings.Join(names, ", "))
 	return nil
 }
@@ -0,0 +1,77 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/localnetworkbackend"
+	"github.com/MetalBlockchain/metal-network-runner/rpcpb"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_buildNetworkStatus(t *testing.T) {
+	require := require.New(t)
+	clusterInfo := &rpcpb.ClusterInfo{
+		NodeNames: []string{"node2", "node1"},
+		NodeInfos: map[string]*rpcpb.NodeInfo{
+			"node1": {Name: "node1", Id: "NodeID-1", Uri: "http://127.0.0.1:9650", WhitelistedSubnets: "subnetA,subnetB"},
+			"node2": {Name: "node2", Id: "NodeID-2", Uri: "http://127.0.0.1:9652", WhitelistedSubnets: "subnetB"},
+		},
+		Healthy:   true,
+		NetworkId: 12345,
+		CustomChains: map[string]*rpcpb.CustomChainInfo{
+			"chainA": {ChainName: "a", SubnetId: "subnetA", VmId: "vmA"},
+		},
+	}
+	statusInfo := buildNetworkStatus("staging", clusterInfo, func(nodeInfo *rpcpb.NodeInfo) nodeProbe {
+		return nodeProbe{
+			healthy:      nodeInfo.Name == "node1",
+			bootstrapped: true,
+			stakingPort:  9651,
+			uptime:       90 * time.Second,
+		}
+	})
+	require.Equal("staging", statusInfo.Name)
+	require.True(statusInfo.Running)
+	require.True(statusInfo.Healthy)
+	require.Len(statusInfo.Nodes, 2)
+	require.Equal("node1", statusInfo.Nodes[0].Name)
+	require.Equal(9650, statusInfo.Nodes[0].HTTPPort)
+	require.Equal(9651, statusInfo.Nodes[0].StakingPort)
+	require.Equal([]string{"subnetA", "subnetB"}, statusInfo.Nodes[0].TrackedSubnets)
+	require.Equal(int64(90), statusInfo.Nodes[0].UptimeSeconds)
+	require.False(statusInfo.Nodes[1].Healthy)
+	require.Equal([]blockchainStatus{{
+		Name:         "a",
+		BlockchainID: "chainA",
+		SubnetID:     "subnetA",
+		VMID:         "vmA",
+		RPCURLs:      []string{"http://127.0.0.1:9650/ext/bc/chainA/rpc"},
+	}}, statusInfo.Blockchains)
+}
+
+func Test_buildBackendNetworkStatus(t *testing.T) {
+	require := require.New(t)
+	statusInfo := buildBackendNetworkStatus("", localnetworkbackend.Docker, nil)
+	require.False(statusInfo.Running)
+
+	statusInfo = buildBackendNetworkStatus("", localnetworkbackend.Docker, []localnetworkbackend.NodeInfo{
+		{Name: "node1", NodeID: "NodeID-1", URI: "http://127.0.0.1:9650", Healthy: true},
+		{Name: "node2", NodeID: "NodeID-2", URI: "http://127.0.0.1:9652"},
+	})
+	require.Equal(localnetworkbackend.Docker, statusInfo.Backend)
+	require.True(statusInfo.Running)
+	require.False(statusInfo.Healthy)
+	require.Len(statusInfo.Nodes, 2)
+	require.Equal(9652, statusInfo.Nodes[1].HTTPPort)
+	require.True(statusInfo.Nodes[0].Bootstrapped)
+}
+
+func Test_getFlagValue(t *testing.T) {
+	args := []string{"metalgo", "--data-dir=/tmp/node1", "--staking-port=9651"}
+	require.Equal(t, "/tmp/node1", getFlagValue(args, "data-dir"))
+	require.Equal(t, 9651, getFlagIntValue(args, "staking-port"))
+	require.Equal(t, 0, getFlagIntValue(args, "http-port"))
+}
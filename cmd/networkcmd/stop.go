@@ -3,6 +3,7 @@
 package networkcmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -10,6 +11,8 @@ import (
 
 	"github.com/MetalBlockchain/metal-cli/pkg/binutils"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/localnetworkbackend"
+	"github.com/MetalBlockchain/metal-cli/pkg/teleporter"
 	"github.com/MetalBlockchain/metal-cli/pkg/utils"
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
 	"github.com/MetalBlockchain/metal-network-runner/server"
@@ -27,7 +30,12 @@ All deployed Subnets shutdown gracefully and save their state. If you provide th
 --snapshot-name flag, the network saves its state under this named snapshot. You can
 reload this snapshot with network start --snapshot-name <snapshotName>. Otherwise, the
 network saves to the default snapshot, overwriting any existing state. You can reload the
-default snapshot with network start.`,
+default snapshot with network start.
+
+Use --name to stop a named local network, keeping its state.
+
+Networks started with the docker or k8s backend keep their node data, but not
+snapshots, so --snapshot-name is only used by the anr backend.`,
 
 		RunE:         StopNetwork,
 		Args:         cobra.ExactArgs(0),
@@ -37,33 +45,48 @@ default snapshot with network start.`,
 	return cmd
 }
 
+// stopRelayer stops the relayer delivering the local network messages, if it is running
+func stopRelayer() {
+	if err := teleporter.StopRelayer(app); err != nil && !errors.Is(err, teleporter.ErrRelayerNotRunning) {
+		app.Log.Warn("failed stopping relayer", zap.Error(err))
+	}
+}
+
 func StopNetwork(*cobra.Command, []string) error {
+	name := localnetworkbackend.CurrentName(app.GetRunDir())
+	backend, err := getBackend(name)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), backendTimeout)
+	defer cancel()
+	err = backend.Stop(ctx)
+	switch {
+	case name == localnetworkbackend.ANR:
+		return err
+	case errors.Is(err, localnetworkbackend.ErrNotStarted):
+		ux.Logger.PrintToUser("Network already stopped.")
+		return nil
+	case err != nil:
+		return err
+	}
+	ux.Logger.PrintToUser("Network stopped successfully.")
+	return nil
+}
+
+// stopANRNetwork stops the network runner, saving the local network into the
+// snapshot given by flag
+func stopANRNetwork() error {
 	if err := saveNetwork(); errors.Is(err, binutils.ErrGRPCTimeout) {
 		// no server to kill
 		return nil
 	}
 
-	relayerConfigPath := app.GetAWMRelayerConfigPath()
-	if utils.FileExists(relayerConfigPath) {
-		relayerStoredConfigPath := filepath.Join(app.GetAWMRelayerSnapshotConfsDir(), snapshotName+jsonExt)
-		if err := os.MkdirAll(filepath.Dir(relayerStoredConfigPath), constants.DefaultPerms755); err != nil {
-			return err
-		}
-		if err := os.Rename(relayerConfigPath, relayerStoredConfigPath); err != nil {
-			return fmt.Errorf("couldn't store relayer conf from %s into %s", relayerConfigPath, relayerStoredConfigPath)
-		}
+	if err := storeSnapshotData(snapshotName, false); err != nil {
+		return err
 	}
 
-	extraLocalNetworkDataPath := app.GetExtraLocalNetworkDataPath()
-	if utils.FileExists(extraLocalNetworkDataPath) {
-		storedExtraLocalNetowkrDataPath := filepath.Join(app.GetExtraLocalNetworkSnapshotsDir(), snapshotName+jsonExt)
-		if err := os.MkdirAll(filepath.Dir(storedExtraLocalNetowkrDataPath), constants.DefaultPerms755); err != nil {
-			return err
-		}
-		if err := os.Rename(extraLocalNetworkDataPath, storedExtraLocalNetowkrDataPath); err != nil {
-			return fmt.Errorf("couldn't store extra local network data from %s into %s", extraLocalNetworkDataPath, storedExtraLocalNetowkrDataPath)
-		}
-	}
+	stopRelayer()
 
 	var err error
 	if err = binutils.KillgRPCServerProcess(app); err != nil {
@@ -76,6 +99,45 @@ func StopNetwork(*cobra.Command, []string) error {
 	return nil
 }
 
+// storeSnapshotData saves the CLI data of the running local network (relayer config,
+// extra network data) along with snapshot [name]. If [keepCurrent] is false, the data is
+// moved instead of copied
+func storeSnapshotData(name string, keepCurrent bool) error {
+	for _, data := range []struct {
+		currentPath string
+		storedPath  string
+		desc        string
+	}{
+		{
+			currentPath: app.GetAWMRelayerConfigPath(),
+			storedPath:  filepath.Join(app.GetAWMRelayerSnapshotConfsDir(), name+jsonExt),
+			desc:        "relayer conf",
+		},
+		{
+			currentPath: app.GetExtraLocalNetworkDataPath(),
+			storedPath:  filepath.Join(app.GetExtraLocalNetworkSnapshotsDir(), name+jsonExt),
+			desc:        "extra local network data",
+		},
+	} {
+		if !utils.FileExists(data.currentPath) {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(data.storedPath), constants.DefaultPerms755); err != nil {
+			return err
+		}
+		if keepCurrent {
+			if err := binutils.CopyFile(data.currentPath, data.storedPath); err != nil {
+				return fmt.Errorf("couldn't store %s from %s into %s: %w", data.desc, data.currentPath, data.storedPath, err)
+			}
+			continue
+		}
+		if err := os.Rename(data.currentPath, data.storedPath); err != nil {
+			return fmt.Errorf("couldn't store %s from %s into %s", data.desc, data.currentPath, data.storedPath)
+		}
+	}
+	return nil
+}
+
 func saveNetwork() error {
 	cli, err := binutils.NewGRPCClient(
 		binutils.WithAvoidRPCVersionCheck(true),
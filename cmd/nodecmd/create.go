@@ -46,7 +46,7 @@ const (
 )
 
 var (
-	createSupportedNetworkOptions         = []networkoptions.NetworkOption{networkoptions.Tahoe, networkoptions.Devnet}
+	createSupportedNetworkOptions         = []networkoptions.NetworkOption{networkoptions.Tahoe, networkoptions.Mainnet, networkoptions.Devnet}
 	globalNetworkFlags                    networkoptions.NetworkFlags
 	useAWS                                bool
 	useGCP                                bool
@@ -75,7 +75,8 @@ var (
 	versionComments                       = map[string]string{
 		"v1.11.0-fuji": " (recommended for fuji durango)",
 	}
-	grafanaPkg string
+	grafanaPkg    string
+	subnetToTrack string
 )
 
 func newCreateCmd() *cobra.Command {
@@ -93,6 +94,10 @@ to finish bootstrapping on the primary network before running further
 commands on it, e.g. validating a Subnet. You can check the bootstrapping
 status by running avalanche node status 
 
+Use --subnet to also configure the created node(s) to track a Subnet
+already deployed to Tahoe or Mainnet. If no avalanchego version option is
+given, the latest version compatible with that Subnet is installed.
+
 The created node will be part of group of validators called <clusterName> 
 and users can call node commands with <clusterName> so that the command
 will apply to all nodes in the cluster`,
@@ -127,6 +132,7 @@ will apply to all nodes in the cluster`,
 	cmd.Flags().IntVar(&throughput, "aws-throughput", constants.AWSGP3DefaultThroughput, "AWS throughput in MiB/s (for gp3 volume type only)")
 	cmd.Flags().StringVar(&volumeType, "aws-volume-type", "gp3", "AWS volume type")
 	cmd.Flags().IntVar(&volumeSize, "aws-volume-size", constants.CloudServerStorageSize, "AWS volume size in GB")
+	cmd.Flags().StringVar(&subnetToTrack, "subnet", "", "configure created node/s to track given subnet, already deployed to the network [tahoe/mainnet only]")
 	return cmd
 }
 
@@ -237,6 +243,11 @@ func createNodes(cmd *cobra.Command, args []string) error {
 	network = models.NewNetworkFromCluster(network, clusterName)
 
 	globalNetworkFlags.UseDevnet = network.Kind == models.Devnet // set globalNetworkFlags.UseDevnet to true if network is devnet for further use
+	if subnetToTrack != "" {
+		if err := checkSubnetToTrack(network); err != nil {
+			return err
+		}
+	}
 	avalancheGoVersion, err := getAvalancheGoVersion()
 	if err != nil {
 		return err
@@ -657,7 +668,7 @@ func createNodes(cmd *cobra.Command, args []string) error {
 			}
 			ux.SpinComplete(spinner)
 			spinner = spinSession.SpinToUser(utils.ScriptLog(host.NodeID, "Setup Node"))
-			if err := ssh.RunSSHSetupNode(host, app.Conf.GetConfigPath(), avalancheGoVersion, remoteCLIVersion, network.Kind == models.Devnet); err != nil {
+			if err := ssh.RunSSHSetupNode(host, app.Conf.GetConfigPath(), avalancheGoVersion, remoteCLIVersion, network); err != nil {
 				nodeResults.AddResult(host.NodeID, nil, err)
 				ux.SpinFailWithError(spinner, "", err)
 				return
@@ -783,6 +794,41 @@ func createNodes(cmd *cobra.Command, args []string) error {
 		printResults(cloudConfigMap, publicIPMap, monitoringPublicIP)
 		ux.Logger.PrintToUser(logging.Green.Wrap("AvalancheGo and Avalanche-CLI installed and node(s) are bootstrapping!"))
 	}
+	if subnetToTrack != "" {
+		ux.Logger.PrintToUser("Configuring node(s) to track subnet %s...", subnetToTrack)
+		untrackedNodes, err := trackSubnet(hosts, clusterName, subnetToTrack)
+		if err != nil {
+			return err
+		}
+		if len(untrackedNodes) > 0 {
+			return fmt.Errorf("node(s) %s failed to sync with subnet %s", untrackedNodes, subnetToTrack)
+		}
+		ux.Logger.PrintToUser("Node(s) will start syncing with subnet %s once bootstrapped", subnetToTrack)
+		ux.Logger.PrintToUser("Check node subnet syncing status with avalanche node status %s --subnet %s", clusterName, subnetToTrack)
+	}
+	return nil
+}
+
+// checkSubnetToTrack verifies that the subnet given to node create is deployed to the
+// network of the new nodes. If no avalanchego version option was given, selects the
+// latest one compatible with the subnet
+func checkSubnetToTrack(network models.Network) error {
+	if network.Kind == models.Devnet {
+		return fmt.Errorf("--subnet is not supported for devnet nodes. Use avalanche node devnet wiz instead")
+	}
+	if _, err := subnetcmd.ValidateSubnetNameAndGetChains([]string{subnetToTrack}); err != nil {
+		return err
+	}
+	sc, err := app.LoadSidecar(subnetToTrack)
+	if err != nil {
+		return err
+	}
+	if sc.Networks[network.Name()].SubnetID == ids.Empty {
+		return fmt.Errorf("subnet %s is not deployed to %s", subnetToTrack, network.Name())
+	}
+	if !useLatestAvalanchegoReleaseVersion && !useLatestAvalanchegoPreReleaseVersion && useCustomAvalanchegoVersion == "" && useAvalanchegoVersionFromSubnet == "" {
+		useAvalanchegoVersionFromSubnet = subnetToTrack
+	}
 	return nil
 }
 
@@ -1064,6 +1110,12 @@ func getAvalancheGoVersion() (string, error) {
 		return "", err
 	}
 
+	if !useLatestAvalanchegoReleaseVersion && !useLatestAvalanchegoPreReleaseVersion && useCustomAvalanchegoVersion == "" && useAvalanchegoVersionFromSubnet == "" {
+		if version := app.Conf.GetConfigStringValue(constants.ConfigAvalancheGoVersionKey); version != "" {
+			ux.Logger.PrintToUser("Using avalanchego version %s set in the CLI config", version)
+			useCustomAvalanchegoVersion = version
+		}
+	}
 	if !useLatestAvalanchegoReleaseVersion && !useLatestAvalanchegoPreReleaseVersion && useCustomAvalanchegoVersion == "" && useAvalanchegoVersionFromSubnet == "" {
 		err := promptAvalancheGoVersionChoice(latestReleaseVersion, latestPreReleaseVersion)
 		if err != nil {
@@ -70,6 +70,7 @@ func deploySubnet(cmd *cobra.Command, args []string) error {
 	}
 	keyNameParam := ""
 	useLedgerParam := false
+	signerSpecParam := ""
 	useEwoqParam := true
 	sameControlKey := true
 
@@ -80,6 +81,7 @@ func deploySubnet(cmd *cobra.Command, args []string) error {
 		networkFlags,
 		keyNameParam,
 		useLedgerParam,
+		signerSpecParam,
 		useEwoqParam,
 		sameControlKey,
 	); err != nil {
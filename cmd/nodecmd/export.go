@@ -0,0 +1,310 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	awsAPI "github.com/MetalBlockchain/metal-cli/pkg/cloud/aws"
+	gcpAPI "github.com/MetalBlockchain/metal-cli/pkg/cloud/gcp"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/terraform"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/spf13/cobra"
+)
+
+const terraformFileName = "main.tf"
+
+var exportTerraformOutputDir string
+
+// metal node export
+func newExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "(ALPHA Warning) Export the infrastructure of a cluster",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node export command suite provides a collection of commands to export the
+cloud infrastructure of a cluster in other formats.`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+	}
+	// node export terraform
+	cmd.AddCommand(newExportTerraformCmd())
+	return cmd
+}
+
+// metal node export terraform
+func newExportTerraformCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "terraform [clusterName]",
+		Short: "(ALPHA Warning) Export the cloud resources of a cluster as Terraform configuration",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node export terraform command writes a Terraform configuration reproducing
+the cloud resources of the cluster: its instances, with their root disks and
+static IPs, and their security groups (AWS) or networks and firewall rules (GCP).
+
+The instance details are read from the cloud, so cloud access is required. The
+configuration includes import blocks adopting the existing resources on the
+first apply, so the cluster can be managed with Terraform (>= 1.5) from then on.
+
+The configuration is written to main.tf into --output-dir, by default
+<clusterName>-terraform.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE:         exportTerraform,
+	}
+	cmd.Flags().StringVar(&exportTerraformOutputDir, "output-dir", "", "dir to write the terraform configuration into (default: <clusterName>-terraform)")
+	cmd.Flags().StringVar(&awsProfile, "aws-profile", constants.AWSDefaultCredential, "aws profile to use")
+	cmd.Flags().BoolVar(&authorizeAccess, "authorize-access", false, "authorize CLI to read cloud resources")
+	return cmd
+}
+
+func exportTerraform(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	if err := checkCluster(clusterName); err != nil {
+		return err
+	}
+	clustersConfig, err := app.LoadClustersConfig()
+	if err != nil {
+		return err
+	}
+	clusterConfig := clustersConfig.Clusters[clusterName]
+	cloudIDs := clusterConfig.GetCloudIDs()
+	for _, loadTestCloudID := range clusterConfig.LoadTestInstance {
+		cloudIDs = append(cloudIDs, loadTestCloudID)
+	}
+	nodeConfigs := []models.NodeConfig{}
+	for _, cloudID := range cloudIDs {
+		nodeConfig, err := app.LoadClusterNodeConfig(cloudID)
+		if err != nil {
+			return err
+		}
+		nodeConfigs = append(nodeConfigs, nodeConfig)
+	}
+	cloudServices := map[string]bool{}
+	for _, nodeConfig := range nodeConfigs {
+		cloudServices[getCloudService(nodeConfig)] = true
+	}
+	for cloudService := range cloudServices {
+		if !(authorizeAccess || authorizedAccessFromSettings()) && (requestCloudAuth(cloudService) != nil) {
+			return fmt.Errorf("cloud access is required")
+		}
+	}
+
+	infra := terraform.Infra{ClusterName: clusterName}
+	awsRegions := map[string]*terraform.AWSRegion{}
+	awsClouds := map[string]*awsAPI.AwsCloud{}
+	var gcpCloud *gcpAPI.GcpCloud
+	gcpNetworks := map[string]bool{}
+	for _, nodeConfig := range nodeConfigs {
+		roles := strings.Join(clusterConfig.GetHostRoles(nodeConfig), ", ")
+		if nodeConfig.IsLoadTest {
+			roles = "Load Test"
+		}
+		ux.Logger.PrintToUser("Reading %s node %s from %s", roles, nodeConfig.NodeID, nodeConfig.Region)
+		switch getCloudService(nodeConfig) {
+		case constants.AWSCloudService:
+			region, ok := awsRegions[nodeConfig.Region]
+			if !ok {
+				region = &terraform.AWSRegion{Region: nodeConfig.Region}
+				awsRegions[nodeConfig.Region] = region
+				ec2Svc, err := awsAPI.NewAwsCloud(awsProfile, nodeConfig.Region)
+				if err != nil {
+					return err
+				}
+				awsClouds[nodeConfig.Region] = ec2Svc
+			}
+			ec2Svc := awsClouds[nodeConfig.Region]
+			spec, err := ec2Svc.GetInstanceSpec(nodeConfig.NodeID)
+			if err != nil {
+				return fmt.Errorf("failed to read instance %s: %w", nodeConfig.NodeID, err)
+			}
+			if !hasAWSSecurityGroup(region, nodeConfig.SecurityGroup) {
+				securityGroup, err := getAWSSecurityGroup(ec2Svc, nodeConfig.SecurityGroup)
+				if err != nil {
+					return err
+				}
+				region.SecurityGroups = append(region.SecurityGroups, securityGroup)
+			}
+			region.Instances = append(region.Instances, terraform.AWSInstance{
+				ID:               nodeConfig.NodeID,
+				Name:             spec.Tags["Name"],
+				Roles:            roles,
+				AMI:              nodeConfig.AMI,
+				InstanceType:     spec.InstanceType,
+				KeyPair:          nodeConfig.KeyPair,
+				SecurityGroup:    nodeConfig.SecurityGroup,
+				VolumeSize:       spec.VolumeSize,
+				VolumeType:       spec.VolumeType,
+				IOPS:             spec.IOPS,
+				Throughput:       spec.Throughput,
+				EIPAllocationID:  spec.EIPAllocationID,
+				EIPAssociationID: spec.EIPAssociationID,
+				Tags:             spec.Tags,
+			})
+		case constants.GCPCloudService:
+			if gcpCloud == nil {
+				gcpClient, projectName, _, err := getGCPCloudCredentials()
+				if err != nil {
+					return err
+				}
+				gcpCloud, err = gcpAPI.NewGcpCloud(gcpClient, projectName, context.Background())
+				if err != nil {
+					return err
+				}
+				infra.GCP = &terraform.GCPProject{Project: projectName}
+			}
+			spec, err := gcpCloud.GetInstanceSpec(nodeConfig.NodeID, nodeConfig.Region)
+			if err != nil {
+				return fmt.Errorf("failed to read instance %s: %w", nodeConfig.NodeID, err)
+			}
+			if !gcpNetworks[nodeConfig.SecurityGroup] {
+				gcpNetworks[nodeConfig.SecurityGroup] = true
+				network, err := getGCPNetwork(gcpCloud, nodeConfig.SecurityGroup)
+				if err != nil {
+					return err
+				}
+				infra.GCP.Networks = append(infra.GCP.Networks, network)
+			}
+			infra.GCP.Instances = append(infra.GCP.Instances, terraform.GCPInstance{
+				Name:         nodeConfig.NodeID,
+				Roles:        roles,
+				Zone:         nodeConfig.Region,
+				Region:       gcpZoneToRegion(nodeConfig.Region),
+				MachineType:  spec.MachineType,
+				Image:        spec.Image,
+				DiskSizeGb:   spec.DiskSizeGb,
+				DiskType:     spec.DiskType,
+				Network:      nodeConfig.SecurityGroup,
+				SSHKeys:      spec.SSHKeys,
+				StaticIPName: spec.StaticIPName,
+				Labels:       spec.Labels,
+			})
+		default:
+			return fmt.Errorf("cloud service %s is not supported", nodeConfig.CloudService)
+		}
+	}
+	regionNames := make([]string, 0, len(awsRegions))
+	for regionName := range awsRegions {
+		regionNames = append(regionNames, regionName)
+	}
+	sort.Strings(regionNames)
+	for _, regionName := range regionNames {
+		infra.AWSRegions = append(infra.AWSRegions, *awsRegions[regionName])
+	}
+
+	config, err := terraform.Render(infra)
+	if err != nil {
+		return err
+	}
+	outputDir := exportTerraformOutputDir
+	if outputDir == "" {
+		outputDir = clusterName + "-terraform"
+	}
+	if err := os.MkdirAll(outputDir, constants.DefaultPerms755); err != nil {
+		return err
+	}
+	outputPath, err := filepath.Abs(filepath.Join(outputDir, terraformFileName))
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(outputPath, config, constants.WriteReadReadPerms); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Terraform configuration of cluster %s written to %s", clusterName, outputPath)
+	ux.Logger.PrintToUser("Review the plan before applying it with:")
+	ux.Logger.PrintToUser("  cd %s && terraform init && terraform plan", filepath.Dir(outputPath))
+	return nil
+}
+
+func getCloudService(nodeConfig models.NodeConfig) string {
+	if nodeConfig.CloudService == "" {
+		return constants.AWSCloudService
+	}
+	return nodeConfig.CloudService
+}
+
+func hasAWSSecurityGroup(region *terraform.AWSRegion, securityGroupName string) bool {
+	for _, securityGroup := range region.SecurityGroups {
+		if securityGroup.Name == securityGroupName {
+			return true
+		}
+	}
+	return false
+}
+
+// getAWSSecurityGroup returns the ingress rules of the given security group
+func getAWSSecurityGroup(ec2Svc *awsAPI.AwsCloud, securityGroupName string) (terraform.AWSSecurityGroup, error) {
+	securityGroup := terraform.AWSSecurityGroup{Name: securityGroupName}
+	exists, sg, err := ec2Svc.CheckSecurityGroupExists(securityGroupName)
+	if err != nil {
+		return securityGroup, err
+	}
+	if !exists {
+		ux.Logger.PrintToUser("Warning: security group %s not found, it will be created without rules", securityGroupName)
+		return securityGroup, nil
+	}
+	securityGroup.ID = aws.ToString(sg.GroupId)
+	securityGroup.Description = aws.ToString(sg.Description)
+	for _, permission := range sg.IpPermissions {
+		cidrs := []string{}
+		for _, ipRange := range permission.IpRanges {
+			cidrs = append(cidrs, aws.ToString(ipRange.CidrIp))
+		}
+		if len(cidrs) == 0 {
+			continue
+		}
+		securityGroup.Rules = append(securityGroup.Rules, terraform.AWSRule{
+			Protocol: aws.ToString(permission.IpProtocol),
+			FromPort: aws.ToInt32(permission.FromPort),
+			ToPort:   aws.ToInt32(permission.ToPort),
+			CIDRs:    cidrs,
+		})
+	}
+	return securityGroup, nil
+}
+
+// getGCPNetwork returns the ingress firewall rules of the given network
+func getGCPNetwork(gcpCloud *gcpAPI.GcpCloud, networkName string) (terraform.GCPNetwork, error) {
+	network := terraform.GCPNetwork{Name: networkName}
+	firewalls, err := gcpCloud.GetNetworkFirewalls(networkName)
+	if err != nil {
+		return network, err
+	}
+	for _, firewall := range firewalls {
+		if firewall.Direction != "" && firewall.Direction != "INGRESS" {
+			continue
+		}
+		allowed := []terraform.GCPAllowed{}
+		for _, rule := range firewall.Allowed {
+			allowed = append(allowed, terraform.GCPAllowed{Protocol: rule.IPProtocol, Ports: rule.Ports})
+		}
+		network.Firewalls = append(network.Firewalls, terraform.GCPFirewall{
+			Name:         firewall.Name,
+			SourceRanges: firewall.SourceRanges,
+			Allowed:      allowed,
+		})
+	}
+	return network, nil
+}
+
+// gcpZoneToRegion returns the region of a GCP zone (e.g. us-east1-b -> us-east1)
+func gcpZoneToRegion(zone string) string {
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		return zone[:i]
+	}
+	return zone
+}
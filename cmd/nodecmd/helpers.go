@@ -70,7 +70,6 @@ func parseHealthyOutput(byteValue []byte) (bool, error) {
 }
 
 func getNotBootstrappedNodes(hosts []*models.Host) ([]string, error) {
-	ux.Logger.PrintToUser("Checking if node(s) are bootstrapped to Primary Network...")
 	wg := sync.WaitGroup{}
 	wgResults := models.NodeResults{}
 	for _, host := range hosts {
@@ -219,6 +218,7 @@ func checkHostsAreHealthy(hosts []*models.Host) error {
 }
 
 func checkHostsAreBootstrapped(hosts []*models.Host) error {
+	ux.Logger.PrintToUser("Checking if node(s) are bootstrapped to Primary Network...")
 	notBootstrappedNodes, err := getNotBootstrappedNodes(hosts)
 	if err != nil {
 		return err
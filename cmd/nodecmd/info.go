@@ -0,0 +1,87 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/node"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metalgo/utils/formatting"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var infoEndpoint string
+
+// avalanche node info
+func newInfoCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "info",
+		Short: "Get the NodeID, BLS key and versions of a running node",
+		Long: `The node info command queries the info API of a running node, given with
+--endpoint as an URL, IP or host name, and prints its NodeID, derived from its
+staking certificate, the public key and proof of possession of its BLS key, its
+network and its versions.
+
+The same information is fetched by addValidator commands given --from-node, so
+NodeIDs and BLS keys don't need to be copied by hand.`,
+		SilenceUsage: true,
+		RunE:         getNodeInfo,
+		Args:         cobra.ExactArgs(0),
+	}
+	cmd.Flags().StringVar(&infoEndpoint, "endpoint", "", "API URL, IP or host name of the node (e.g. http://111.22.33.44:9650)")
+	_ = cmd.MarkFlagRequired("endpoint")
+	flags.AddJSONOutputFlag(cmd)
+	return cmd
+}
+
+func getNodeInfo(_ *cobra.Command, _ []string) error {
+	nodeInfo, err := node.GetInfo(infoEndpoint)
+	if err != nil {
+		return err
+	}
+	if ux.JSONOutput() {
+		return ux.PrintResult(nodeInfo)
+	}
+	networkName := "Devnet"
+	if network := models.NetworkFromNetworkID(nodeInfo.NetworkID); network.Kind != models.Undefined {
+		networkName = network.Name()
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetRowLine(true)
+	table.Append([]string{"Endpoint", nodeInfo.Endpoint})
+	table.Append([]string{"NodeID", nodeInfo.NodeID.String()})
+	if nodeInfo.ProofOfPossession != nil {
+		publicKey, err := formatting.Encode(formatting.HexNC, nodeInfo.ProofOfPossession.PublicKey[:])
+		if err != nil {
+			return err
+		}
+		pop, err := formatting.Encode(formatting.HexNC, nodeInfo.ProofOfPossession.ProofOfPossession[:])
+		if err != nil {
+			return err
+		}
+		table.Append([]string{"BLS Public Key", publicKey})
+		table.Append([]string{"BLS Proof of Possession", pop})
+	} else {
+		table.Append([]string{"BLS Public Key", "none"})
+	}
+	table.Append([]string{"Network", fmt.Sprintf("%s (%d)", networkName, nodeInfo.NetworkID)})
+	table.Append([]string{"Version", nodeInfo.Version})
+	table.Append([]string{"Database Version", nodeInfo.DatabaseVersion})
+	table.Append([]string{"RPC Protocol Version", fmt.Sprint(nodeInfo.RPCProtocolVersion)})
+	table.Append([]string{"Git Commit", nodeInfo.GitCommit})
+	vms := make([]string, 0, len(nodeInfo.VMVersions))
+	for vm, version := range nodeInfo.VMVersions {
+		vms = append(vms, vm+": "+version)
+	}
+	sort.Strings(vms)
+	table.Append([]string{"VM Versions", strings.Join(vms, "\n")})
+	table.Render()
+	return nil
+}
@@ -58,5 +58,9 @@ rest of the commands to maintain your node and make your node a Subnet Validator
 	cmd.AddCommand(newResizeCmd())
 	// node addDashboard
 	cmd.AddCommand(newAddDashboardCmd())
+	// node export
+	cmd.AddCommand(newExportCmd())
+	// node info
+	cmd.AddCommand(newInfoCmd())
 	return cmd
 }
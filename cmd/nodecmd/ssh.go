@@ -6,12 +6,10 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"strings"
 	"sync"
 
 	"github.com/MetalBlockchain/metal-cli/pkg/ansible"
-	"github.com/MetalBlockchain/metal-cli/pkg/constants"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
 	"github.com/MetalBlockchain/metal-cli/pkg/utils"
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
@@ -23,7 +21,7 @@ var isParallel bool
 
 func newSSHCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "ssh [clusterName|nodeID|instanceID|IP] [cmd]",
+		Use:   "ssh [clusterName|nodeID|instanceID|IP] [-- cmd]",
 		Short: "(ALPHA Warning) Execute ssh command on node(s)",
 		Long: `(ALPHA Warning) This command is currently in experimental mode.
 
@@ -31,6 +29,10 @@ The node ssh command execute a given command [cmd] using ssh on all nodes in the
 If no command is given, just prints the ssh command to be used to connect to each node in the cluster.
 For provided NodeID or InstanceID or IP, the command [cmd] will be executed on that node.
 If no [cmd] is provided for the node, it will open ssh shell there.
+
+The IP and ssh key of each node are taken from the cluster inventory stored by node create.
+Separate [cmd] with -- when it contains flags, so they are not parsed as node ssh flags,
+eg: avalanche node ssh myCluster -- sudo journalctl -u avalanchego --since -1h
 `,
 		SilenceUsage: true,
 		Args:         cobra.MinimumNArgs(0),
@@ -70,35 +72,19 @@ func sshNode(_ *cobra.Command, args []string) error {
 			if len(args[1:]) == 0 {
 				return printClusterConnectionString(clusterNameOrNodeID, clustersConfig.Clusters[clusterNameOrNodeID].Network.Kind.String())
 			} else {
-				clusterHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterNameOrNodeID))
+				clusterHosts, err := getClusterHostsWithMonitoring(clusterNameOrNodeID)
 				if err != nil {
 					return err
 				}
-				monitoringInventoryPath := filepath.Join(app.GetAnsibleInventoryDirPath(clusterNameOrNodeID), constants.MonitoringDir)
-				if utils.DirectoryExists(monitoringInventoryPath) {
-					monitoringHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(monitoringInventoryPath)
-					if err != nil {
-						return err
-					}
-					clusterHosts = append(clusterHosts, monitoringHosts...)
-				}
 				return sshHosts(clusterHosts, cmd, clustersConfig.Clusters[clusterNameOrNodeID])
 			}
 		} else {
 			// try to detect nodeID
 			for clusterName := range clustersConfig.Clusters {
-				clusterHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+				clusterHosts, err := getClusterHostsWithMonitoring(clusterName)
 				if err != nil {
 					return err
 				}
-				monitoringInventoryPath := app.GetMonitoringInventoryDir(clusterName)
-				if utils.DirectoryExists(monitoringInventoryPath) {
-					monitoringHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(monitoringInventoryPath)
-					if err != nil {
-						return err
-					}
-					clusterHosts = append(clusterHosts, monitoringHosts...)
-				}
 				selectedHost := utils.Filter(clusterHosts, func(h *models.Host) bool {
 					_, cloudHostID, _ := models.HostAnsibleIDToCloudID(h.NodeID)
 					hostNodeID, _ := getNodeID(app.GetNodeInstanceDirPath(cloudHostID))
@@ -107,7 +93,7 @@ func sshNode(_ *cobra.Command, args []string) error {
 				switch {
 				case len(selectedHost) == 0:
 					continue
-				case len(selectedHost) > 2:
+				case len(selectedHost) > 1:
 					return fmt.Errorf("more then 1 node found for %s", clusterNameOrNodeID)
 				default:
 					return sshHosts(selectedHost, cmd, clustersConfig.Clusters[clusterName])
@@ -217,21 +203,31 @@ func sshHosts(hosts []*models.Host, cmd string, clusterConf models.ClusterConfig
 
 func printClusterConnectionString(clusterName string, networkName string) error {
 	ux.Logger.PrintToUser("Cluster: %s (%s)", logging.LightBlue.Wrap(clusterName), logging.Green.Wrap(networkName))
-	clusterHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	clusterHosts, err := getClusterHostsWithMonitoring(clusterName)
 	if err != nil {
 		return err
 	}
+	for _, host := range clusterHosts {
+		ux.Logger.PrintToUser(utils.GetSSHConnectionString(host.IP, host.SSHPrivateKeyPath))
+	}
+	ux.Logger.PrintToUser("")
+	return nil
+}
+
+// getClusterHostsWithMonitoring returns the hosts of the cluster inventory, including
+// the monitoring host if there is one
+func getClusterHostsWithMonitoring(clusterName string) ([]*models.Host, error) {
+	clusterHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return nil, err
+	}
 	monitoringInventoryPath := app.GetMonitoringInventoryDir(clusterName)
 	if utils.DirectoryExists(monitoringInventoryPath) {
 		monitoringHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(monitoringInventoryPath)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		clusterHosts = append(clusterHosts, monitoringHosts...)
 	}
-	for _, host := range clusterHosts {
-		ux.Logger.PrintToUser(utils.GetSSHConnectionString(host.IP, host.SSHPrivateKeyPath))
-	}
-	ux.Logger.PrintToUser("")
-	return nil
+	return clusterHosts, nil
 }
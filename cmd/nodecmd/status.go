@@ -8,6 +8,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
 	"github.com/MetalBlockchain/metal-cli/cmd/subnetcmd"
 	"github.com/MetalBlockchain/metal-cli/pkg/ansible"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
@@ -21,10 +22,44 @@ import (
 	"github.com/pborman/ansi"
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/slices"
+	"golang.org/x/mod/semver"
 )
 
 var subnetName string
 
+const (
+	subnetStatusNotBootstrapped = "NOT_BOOTSTRAPPED"
+	subnetStatusSynced          = "SYNCED"
+	subnetStatusValidating      = "VALIDATING"
+)
+
+// clusterStatusInfo is the status of a cluster, as printed by node status --json
+type clusterStatusInfo struct {
+	Cluster string `json:"cluster"`
+	Network string `json:"network"`
+	Subnet  string `json:"subnet,omitempty"`
+	// LatestAvalancheGoVersion is the newest avalanchego version running in the cluster.
+	// VersionSkew is set if some nodes run an older one
+	LatestAvalancheGoVersion string           `json:"latestAvalancheGoVersion,omitempty"`
+	VersionSkew              bool             `json:"versionSkew"`
+	Nodes                    []nodeStatusInfo `json:"nodes"`
+}
+
+type nodeStatusInfo struct {
+	CloudID            string   `json:"cloudID"`
+	NodeID             string   `json:"nodeID,omitempty"`
+	IP                 string   `json:"ip"`
+	Roles              []string `json:"roles"`
+	AvalancheGoHost    bool     `json:"avalancheGoHost"`
+	AvalancheGoVersion string   `json:"avalancheGoVersion,omitempty"`
+	OutdatedVersion    bool     `json:"outdatedVersion,omitempty"`
+	Bootstrapped       bool     `json:"bootstrapped"`
+	Healthy            bool     `json:"healthy"`
+	// SubnetStatus is one of NOT_BOOTSTRAPPED, SYNCED or VALIDATING, the last one
+	// meaning the node is in the subnet validator set
+	SubnetStatus string `json:"subnetStatus,omitempty"`
+}
+
 func newStatusCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "status [clusterName]",
@@ -34,13 +69,18 @@ func newStatusCmd() *cobra.Command {
 The node status command gets the bootstrap status of all nodes in a cluster with the Primary Network. 
 If no cluster is given, defaults to node list behaviour.
 
-To get the bootstrap status of a node with a Subnet, use --subnet flag`,
+To get the bootstrap status of a node with a Subnet, use --subnet flag. A node
+with VALIDATING status for the Subnet is part of the Subnet validator set.
+
+Nodes running an older avalanchego version than the rest of the cluster are
+reported. Use --json to get the status as JSON.`,
 		SilenceUsage: true,
 		Args:         cobra.MinimumNArgs(0),
 		RunE:         statusNode,
 	}
 	cmd.Flags().StringVar(&subnetName, "subnet", "", "specify the subnet the node is syncing with")
 
+	flags.AddJSONOutputFlag(cmd)
 	return cmd
 }
 
@@ -88,6 +128,7 @@ func statusNode(_ *cobra.Command, args []string) error {
 	}
 	defer disconnectHosts(hosts)
 
+	ux.Logger.PrintToUser("Checking if node(s) are bootstrapped to Primary Network...")
 	notBootstrappedNodes, err := getNotBootstrappedNodes(hosts)
 	if err != nil {
 		return err
@@ -128,15 +169,12 @@ func statusNode(_ *cobra.Command, args []string) error {
 		avagoVersions[nodeID] = fmt.Sprintf("%v", avalanchegoVersion)
 	}
 
-	notSyncedNodes := []string{}
 	subnetSyncedNodes := []string{}
 	subnetValidatingNodes := []string{}
 	if subnetName != "" {
 		hostsToCheckSyncStatus := []string{}
 		for _, hostID := range hostIDs {
-			if slices.Contains(notBootstrappedNodes, hostID) {
-				notSyncedNodes = append(notSyncedNodes, hostID)
-			} else {
+			if !slices.Contains(notBootstrappedNodes, hostID) {
 				hostsToCheckSyncStatus = append(hostsToCheckSyncStatus, hostID)
 			}
 		}
@@ -172,8 +210,6 @@ func statusNode(_ *cobra.Command, args []string) error {
 					subnetSyncedNodes = append(subnetSyncedNodes, nodeID)
 				case status.Validating.String():
 					subnetValidatingNodes = append(subnetValidatingNodes, nodeID)
-				default:
-					notSyncedNodes = append(notSyncedNodes, nodeID)
 				}
 			}
 		}
@@ -190,37 +226,104 @@ func statusNode(_ *cobra.Command, args []string) error {
 		}
 		nodeConfigs = append(nodeConfigs, nodeConfig)
 	}
-	printOutput(
+	statusInfo := buildClusterStatus(
 		clusterConf,
 		hostIDs,
 		nodeIDs,
 		avagoVersions,
 		unhealthyNodes,
 		notBootstrappedNodes,
-		notSyncedNodes,
 		subnetSyncedNodes,
 		subnetValidatingNodes,
 		clusterName,
 		subnetName,
 		nodeConfigs,
 	)
+	if ux.JSONOutput() {
+		return ux.PrintResult(statusInfo)
+	}
+	printOutput(statusInfo)
 	return nil
 }
 
-func printOutput(
+func buildClusterStatus(
 	clusterConf models.ClusterConfig,
 	cloudIDs []string,
 	nodeIDs []string,
 	avagoVersions map[string]string,
 	unhealthyHosts []string,
 	notBootstrappedHosts []string,
-	notSyncedHosts []string,
 	subnetSyncedHosts []string,
 	subnetValidatingHosts []string,
 	clusterName string,
 	subnetName string,
 	nodeConfigs []models.NodeConfig,
-) {
+) clusterStatusInfo {
+	statusInfo := clusterStatusInfo{
+		Cluster: clusterName,
+		Network: clusterConf.Network.Kind.String(),
+		Subnet:  subnetName,
+		Nodes:   []nodeStatusInfo{},
+	}
+	for _, version := range avagoVersions {
+		if semver.Compare(version, statusInfo.LatestAvalancheGoVersion) > 0 {
+			statusInfo.LatestAvalancheGoVersion = version
+		}
+	}
+	for i, cloudID := range cloudIDs {
+		nodeInfo := nodeStatusInfo{
+			CloudID: cloudID,
+			IP:      nodeConfigs[i].ElasticIP,
+			Roles:   clusterConf.GetHostRoles(nodeConfigs[i]),
+		}
+		if clusterConf.IsAvalancheGoHost(cloudID) {
+			nodeInfo.AvalancheGoHost = true
+			nodeInfo.NodeID = nodeIDs[i]
+			nodeInfo.AvalancheGoVersion = avagoVersions[cloudID]
+			nodeInfo.OutdatedVersion = semver.Compare(nodeInfo.AvalancheGoVersion, statusInfo.LatestAvalancheGoVersion) < 0
+			nodeInfo.Bootstrapped = !slices.Contains(notBootstrappedHosts, cloudID)
+			nodeInfo.Healthy = !slices.Contains(unhealthyHosts, cloudID)
+			if subnetName != "" {
+				switch {
+				case slices.Contains(subnetValidatingHosts, cloudID):
+					nodeInfo.SubnetStatus = subnetStatusValidating
+				case slices.Contains(subnetSyncedHosts, cloudID):
+					nodeInfo.SubnetStatus = subnetStatusSynced
+				default:
+					nodeInfo.SubnetStatus = subnetStatusNotBootstrapped
+				}
+			}
+			statusInfo.VersionSkew = statusInfo.VersionSkew || nodeInfo.OutdatedVersion
+		}
+		statusInfo.Nodes = append(statusInfo.Nodes, nodeInfo)
+	}
+	return statusInfo
+}
+
+func printOutput(statusInfo clusterStatusInfo) {
+	notBootstrappedHosts := []string{}
+	notSyncedHosts := []string{}
+	subnetSyncedHosts := []string{}
+	outdatedHosts := []string{}
+	for _, nodeInfo := range statusInfo.Nodes {
+		if !nodeInfo.AvalancheGoHost {
+			continue
+		}
+		if !nodeInfo.Bootstrapped {
+			notBootstrappedHosts = append(notBootstrappedHosts, nodeInfo.CloudID)
+		}
+		switch nodeInfo.SubnetStatus {
+		case subnetStatusNotBootstrapped:
+			notSyncedHosts = append(notSyncedHosts, nodeInfo.CloudID)
+		case subnetStatusSynced:
+			subnetSyncedHosts = append(subnetSyncedHosts, nodeInfo.CloudID)
+		}
+		if nodeInfo.OutdatedVersion {
+			outdatedHosts = append(outdatedHosts, nodeInfo.CloudID)
+		}
+	}
+	clusterName := statusInfo.Cluster
+	subnetName := statusInfo.Subnet
 	if subnetName == "" && len(notBootstrappedHosts) == 0 {
 		ux.Logger.PrintToUser("All nodes in cluster %s are bootstrapped to Primary Network!", clusterName)
 	}
@@ -232,6 +335,14 @@ func printOutput(
 		}
 		ux.Logger.PrintToUser("All nodes in cluster %s are %s Subnet %s", logging.LightBlue.Wrap(clusterName), status, subnetName)
 	}
+	if statusInfo.VersionSkew {
+		ux.Logger.PrintToUser(
+			"Node(s) %s run an older avalanchego version than %s. Use avalanche node upgrade %s to upgrade them",
+			strings.Join(outdatedHosts, ", "),
+			statusInfo.LatestAvalancheGoVersion,
+			clusterName,
+		)
+	}
 	ux.Logger.PrintToUser("")
 	tit := fmt.Sprintf("STATUS FOR CLUSTER: %s", logging.LightBlue.Wrap(clusterName))
 	ux.Logger.PrintToUser(tit)
@@ -244,44 +355,41 @@ func printOutput(
 	table := tablewriter.NewWriter(os.Stdout)
 	table.SetHeader(header)
 	table.SetRowLine(true)
-	for i, cloudID := range cloudIDs {
+	for _, nodeInfo := range statusInfo.Nodes {
 		boostrappedStatus := ""
 		healthyStatus := ""
-		nodeIDStr := ""
 		avagoVersion := ""
-		roles := clusterConf.GetHostRoles(nodeConfigs[i])
-		if clusterConf.IsAvalancheGoHost(cloudID) {
+		if nodeInfo.AvalancheGoHost {
 			boostrappedStatus = logging.Green.Wrap("BOOTSTRAPPED")
-			if slices.Contains(notBootstrappedHosts, cloudID) {
+			if !nodeInfo.Bootstrapped {
 				boostrappedStatus = logging.Red.Wrap("NOT_BOOTSTRAPPED")
 			}
 			healthyStatus = logging.Green.Wrap("OK")
-			if slices.Contains(unhealthyHosts, cloudID) {
+			if !nodeInfo.Healthy {
 				healthyStatus = logging.Red.Wrap("UNHEALTHY")
 			}
-			nodeIDStr = nodeIDs[i]
-			avagoVersion = avagoVersions[cloudID]
+			avagoVersion = nodeInfo.AvalancheGoVersion
+			if nodeInfo.OutdatedVersion {
+				avagoVersion = logging.Red.Wrap(avagoVersion)
+			}
 		}
 		row := []string{
-			cloudID,
-			logging.Green.Wrap(nodeIDStr),
-			nodeConfigs[i].ElasticIP,
-			clusterConf.Network.Kind.String(),
-			strings.Join(roles, ","),
+			nodeInfo.CloudID,
+			logging.Green.Wrap(nodeInfo.NodeID),
+			nodeInfo.IP,
+			statusInfo.Network,
+			strings.Join(nodeInfo.Roles, ","),
 			avagoVersion,
 			boostrappedStatus,
 			healthyStatus,
 		}
 		if subnetName != "" {
 			syncedStatus := ""
-			if clusterConf.MonitoringInstance != cloudID {
-				syncedStatus = logging.Red.Wrap("NOT_BOOTSTRAPPED")
-				if slices.Contains(subnetSyncedHosts, cloudID) {
-					syncedStatus = logging.Green.Wrap("SYNCED")
-				}
-				if slices.Contains(subnetValidatingHosts, cloudID) {
-					syncedStatus = logging.Green.Wrap("VALIDATING")
-				}
+			switch nodeInfo.SubnetStatus {
+			case subnetStatusNotBootstrapped:
+				syncedStatus = logging.Red.Wrap(nodeInfo.SubnetStatus)
+			case subnetStatusSynced, subnetStatusValidating:
+				syncedStatus = logging.Green.Wrap(nodeInfo.SubnetStatus)
 			}
 			row = append(row, syncedStatus)
 		}
@@ -5,6 +5,7 @@ package nodecmd
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
@@ -22,9 +23,8 @@ import (
 )
 
 type nodeUpgradeInfo struct {
-	AvalancheGoVersion    string   // avalanche go version to update to on cloud server
-	SubnetEVMVersion      string   // subnet EVM version to update to on cloud server
-	SubnetEVMIDsToUpgrade []string // list of ID of Subnet EVM to be upgraded to subnet EVM version to update to
+	AvalancheGoVersion string            // avalanche go version to update to on cloud server
+	SubnetEVMVersions  map[string]string // subnet EVM version to update to on cloud server, by ID of the Subnet EVM to be upgraded
 }
 
 func newUpgradeCmd() *cobra.Command {
@@ -69,25 +69,34 @@ func upgrade(_ *cobra.Command, args []string) error {
 			}
 			ux.SpinComplete(spinner)
 		}
-		if upgradeInfo.SubnetEVMVersion != "" {
-			subnetEVMVersionToUpgradeToWoPrefix := strings.TrimPrefix(upgradeInfo.SubnetEVMVersion, "v")
-			subnetEVMArchive := fmt.Sprintf(constants.SubnetEVMArchive, subnetEVMVersionToUpgradeToWoPrefix)
-			subnetEVMReleaseURL := fmt.Sprintf(constants.SubnetEVMReleaseURL, upgradeInfo.SubnetEVMVersion, subnetEVMArchive)
-			spinner := spinSession.SpinToUser(utils.ScriptLog(host.NodeID, fmt.Sprintf("Upgrading SubnetEVM to version %s...", upgradeInfo.SubnetEVMVersion)))
-			if err := getNewSubnetEVMRelease(host, subnetEVMReleaseURL, subnetEVMArchive); err != nil {
-				ux.SpinFailWithError(spinner, "", err)
-				return err
-			}
+		if len(upgradeInfo.SubnetEVMVersions) > 0 {
+			subnetEVMVersions := utils.Unique(maps.Values(upgradeInfo.SubnetEVMVersions))
+			sort.Strings(subnetEVMVersions)
+			spinner := spinSession.SpinToUser(utils.ScriptLog(host.NodeID, fmt.Sprintf("Upgrading SubnetEVM to version %s...", strings.Join(subnetEVMVersions, ", "))))
 			if err := ssh.RunSSHStopNode(host); err != nil {
 				ux.SpinFailWithError(spinner, "", err)
 				return err
 			}
-			for _, vmID := range upgradeInfo.SubnetEVMIDsToUpgrade {
-				subnetEVMBinaryPath := fmt.Sprintf(constants.CloudNodeSubnetEvmBinaryPath, vmID)
-				if err := upgradeSubnetEVM(host, subnetEVMBinaryPath); err != nil {
+			// the release binary is unpacked on the same place for all versions, so
+			// VMs are upgraded grouped by version
+			for _, subnetEVMVersion := range subnetEVMVersions {
+				subnetEVMVersionToUpgradeToWoPrefix := strings.TrimPrefix(subnetEVMVersion, "v")
+				subnetEVMArchive := fmt.Sprintf(constants.SubnetEVMArchive, subnetEVMVersionToUpgradeToWoPrefix)
+				subnetEVMReleaseURL := fmt.Sprintf(constants.SubnetEVMReleaseURL, subnetEVMVersion, subnetEVMArchive)
+				if err := getNewSubnetEVMRelease(host, subnetEVMReleaseURL, subnetEVMArchive); err != nil {
 					ux.SpinFailWithError(spinner, "", err)
 					return err
 				}
+				for vmID, vmVersion := range upgradeInfo.SubnetEVMVersions {
+					if vmVersion != subnetEVMVersion {
+						continue
+					}
+					subnetEVMBinaryPath := fmt.Sprintf(constants.CloudNodeSubnetEvmBinaryPath, vmID)
+					if err := upgradeSubnetEVM(host, subnetEVMBinaryPath); err != nil {
+						ux.SpinFailWithError(spinner, "", err)
+						return err
+					}
+				}
 			}
 			if err := ssh.RunSSHStartNode(host); err != nil {
 				ux.SpinFailWithError(spinner, "", err)
@@ -102,7 +111,8 @@ func upgrade(_ *cobra.Command, args []string) error {
 
 // getNodesUpgradeInfo gets the node versions of all given nodes and checks which
 // nodes needs to have Avalanche Go & SubnetEVM upgraded. It first checks the subnet EVM version -
-// it will install the newest subnet EVM version and install the latest avalanche Go that is still compatible with the Subnet EVM version
+// it will install the subnet EVM version pinned at subnet creation, or the newest one if there is no pin,
+// and install the latest avalanche Go that is still compatible with the Subnet EVM version
 // if the node is not tracking any subnet, it will just install latestAvagoVersion
 func getNodesUpgradeInfo(hosts []*models.Host) (map[*models.Host]nodeUpgradeInfo, error) {
 	latestAvagoVersion, err := app.Downloader.GetLatestReleaseVersion(binutils.GetGithubLatestReleaseURL(
@@ -119,7 +129,7 @@ func getNodesUpgradeInfo(hosts []*models.Host) (map[*models.Host]nodeUpgradeInfo
 	if err != nil {
 		return nil, err
 	}
-	rpcVersion, err := vm.GetRPCProtocolVersion(app, models.SubnetEvm, latestSubnetEVMVersion)
+	pinnedSubnetEVMVersions, err := getPinnedSubnetEVMVersions()
 	if err != nil {
 		return nil, err
 	}
@@ -162,23 +172,37 @@ func getNodesUpgradeInfo(hosts []*models.Host) (map[*models.Host]nodeUpgradeInfo
 		currentAvalancheGoVersion := vmVersions[constants.PlatformKeyName]
 		avalancheGoVersionToUpdateTo := latestAvagoVersion
 		nodeUpgradeInfo := nodeUpgradeInfo{}
-		nodeUpgradeInfo.SubnetEVMIDsToUpgrade = []string{}
+		nodeUpgradeInfo.SubnetEVMVersions = map[string]string{}
+		nodeRPCVersion := 0
 		for vmName, vmVersion := range vmVersions {
 			// when calling info.getNodeVersion, this is what we get
 			// "vmVersions":{"avm":"v1.10.12","evm":"v0.12.5","n8Anw9kErmgk7KHviddYtecCmziLZTphDwfL1V2DfnFjWZXbE":"v0.5.6","platform":"v1.10.12"}},
 			// we need to get the VM ID of the subnets that the node is currently validating, in the example above it is n8Anw9kErmgk7KHviddYtecCmziLZTphDwfL1V2DfnFjWZXbE
 			if !checkIfKeyIsStandardVMName(vmName) {
-				if vmVersion != latestSubnetEVMVersion {
+				subnetEVMVersionToUpdateTo := latestSubnetEVMVersion
+				if pinnedVersion, ok := pinnedSubnetEVMVersions[vmName]; ok {
+					subnetEVMVersionToUpdateTo = pinnedVersion
+				}
+				if vmVersion != subnetEVMVersionToUpdateTo {
 					// update subnet EVM version
-					ux.Logger.PrintToUser("Upgrading Subnet EVM version for node %s from version %s to version %s", hostID, vmVersion, latestSubnetEVMVersion)
-					nodeUpgradeInfo.SubnetEVMVersion = latestSubnetEVMVersion
-					nodeUpgradeInfo.SubnetEVMIDsToUpgrade = append(nodeUpgradeInfo.SubnetEVMIDsToUpgrade, vmName)
+					ux.Logger.PrintToUser("Upgrading Subnet EVM version for node %s from version %s to version %s", hostID, vmVersion, subnetEVMVersionToUpdateTo)
+					nodeUpgradeInfo.SubnetEVMVersions[vmName] = subnetEVMVersionToUpdateTo
+				}
+				rpcVersion, err := vm.GetRPCProtocolVersion(app, models.SubnetEvm, subnetEVMVersionToUpdateTo)
+				if err != nil {
+					nodeErrors[hostID] = err
+					break
+				}
+				if nodeRPCVersion != 0 && nodeRPCVersion != rpcVersion {
+					nodeErrors[hostID] = fmt.Errorf("the Subnet EVM versions to install use different RPC protocol versions (%d, %d), and no avalanchego version is compatible with all of them", nodeRPCVersion, rpcVersion)
+					break
 				}
+				nodeRPCVersion = rpcVersion
 				// find the highest version of avalanche go that is still compatible with current highest rpc
 				avalancheGoVersionToUpdateTo, err = GetLatestAvagoVersionForRPC(rpcVersion, latestAvagoVersion)
 				if err != nil {
 					nodeErrors[hostID] = err
-					continue
+					break
 				}
 			}
 		}
@@ -201,6 +225,32 @@ func getNodesUpgradeInfo(hosts []*models.Host) (map[*models.Host]nodeUpgradeInfo
 	return nodesToUpgrade, nil
 }
 
+// getPinnedSubnetEVMVersions returns the Subnet EVM versions pinned at creation time
+// with --vm-version for the locally configured subnets, by VM ID. Subnets without
+// a pin are upgraded to the latest release
+func getPinnedSubnetEVMVersions() (map[string]string, error) {
+	pinnedVersions := map[string]string{}
+	subnetNames, err := app.GetSidecarNames()
+	if err != nil {
+		return nil, err
+	}
+	for _, subnetName := range subnetNames {
+		sc, err := app.LoadSidecar(subnetName)
+		if err != nil {
+			return nil, err
+		}
+		if sc.VM != models.SubnetEvm || !sc.VMVersionPinned || sc.VMVersion == "" {
+			continue
+		}
+		vmID, err := sc.GetVMID()
+		if err != nil {
+			return nil, err
+		}
+		pinnedVersions[vmID] = sc.VMVersion
+	}
+	return pinnedVersions, nil
+}
+
 // checks if vmName is "avm", "evm" or "platform"
 func checkIfKeyIsStandardVMName(vmName string) bool {
 	standardVMNames := []string{constants.PlatformKeyName, constants.EVMKeyName, constants.AVMKeyName}
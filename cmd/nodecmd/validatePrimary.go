@@ -15,11 +15,10 @@ import (
 
 	"github.com/MetalBlockchain/metalgo/utils/units"
 
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
 	"github.com/MetalBlockchain/metal-cli/pkg/ansible"
 	"github.com/MetalBlockchain/metal-cli/pkg/keychain"
 
-	"github.com/MetalBlockchain/metalgo/vms/platformvm"
-
 	subnetcmd "github.com/MetalBlockchain/metal-cli/cmd/subnetcmd"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
@@ -37,8 +36,10 @@ var (
 	useStaticIP                  bool
 	awsProfile                   string
 	ledgerAddresses              []string
+	signerSpec                   string
 	weight                       uint64
 	startTimeStr                 string
+	startTimeZone                string
 	duration                     time.Duration
 	defaultValidatorParams       bool
 	useCustomDuration            bool
@@ -65,25 +66,16 @@ Network.`,
 	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on tahoe/devnet)")
 	cmd.Flags().BoolVarP(&useEwoq, "ewoq", "e", false, "use ewoq key [tahoe/devnet only]")
 	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	flags.AddSignerFlag(cmd, &signerSpec)
 
 	cmd.Flags().Uint64Var(&weight, "stake-amount", 0, "how many AVAX to stake in the validator")
-	cmd.Flags().StringVar(&startTimeStr, "start-time", "", "UTC start time when this validator starts validating, in 'YYYY-MM-DD HH:MM:SS' format")
+	cmd.Flags().StringVar(&startTimeStr, "start-time", "", "start time when this validator starts validating, in 'YYYY-MM-DD HH:MM:SS' format (UTC unless --timezone is given), RFC3339 format with offset, or relative to now (ex: 10m, 'in 2h')")
+	cmd.Flags().StringVar(&startTimeZone, "timezone", "", "time zone of --start-time (ex: Europe/Paris, local) (default UTC)")
 	cmd.Flags().DurationVar(&duration, "staking-period", 0, "how long validator validates for after start time")
 
 	return cmd
 }
 
-func GetMinStakingAmount(network models.Network) (uint64, error) {
-	pClient := platformvm.NewClient(network.Endpoint)
-	ctx, cancel := utils.GetAPIContext()
-	defer cancel()
-	minValStake, _, err := pClient.GetMinStake(ctx, ids.Empty)
-	if err != nil {
-		return 0, err
-	}
-	return minValStake, nil
-}
-
 func joinAsPrimaryNetworkValidator(
 	deployer *subnet.PublicDeployer,
 	network models.Network,
@@ -98,26 +90,26 @@ func joinAsPrimaryNetworkValidator(
 		start time.Time
 		err   error
 	)
-	minValStake, err := GetMinStakingAmount(network)
+	limits, err := subnet.GetStakeWeightLimits(app, network, ids.Empty)
 	if err != nil {
 		return err
 	}
 	if weight == 0 {
-		weight, err = PromptWeightPrimaryNetwork(network)
+		weight, err = PromptWeightPrimaryNetwork(network, limits)
 		if err != nil {
 			return err
 		}
 	}
-	if weight < minValStake {
-		return fmt.Errorf("illegal weight, must be greater than or equal to %d: %d", minValStake, weight)
+	if err := limits.Check(weight); err != nil {
+		return err
 	}
-	start, duration, err = GetTimeParametersPrimaryNetwork(network, nodeIndex, duration, startTimeStr, nodeCmd)
+	start, duration, err = GetTimeParametersPrimaryNetwork(network, nodeIndex, duration, startTimeStr, startTimeZone, nodeCmd)
 	if err != nil {
 		return err
 	}
 
 	recipientAddr := kc.Addresses().List()[0]
-	PrintNodeJoinPrimaryNetworkOutput(nodeID, weight, network, start)
+	PrintNodeJoinPrimaryNetworkOutput(nodeID, weight, network, start, startTimeZone)
 	// we set the starting time for node to be a Primary Network Validator to be in 1 minute
 	// we use min delegation fee as default
 	delegationFee := network.GenesisParams().MinDelegationFee
@@ -144,7 +136,7 @@ func joinAsPrimaryNetworkValidator(
 	return err
 }
 
-func PromptWeightPrimaryNetwork(network models.Network) (uint64, error) {
+func PromptWeightPrimaryNetwork(network models.Network, limits subnet.StakeWeightLimits) (uint64, error) {
 	defaultStake := network.GenesisParams().MinValidatorStake
 	defaultWeight := fmt.Sprintf("Default (%s)", convertNanoAvaxToAvaxString(defaultStake))
 	txt := "What stake weight would you like to assign to the validator?"
@@ -158,11 +150,11 @@ func PromptWeightPrimaryNetwork(network models.Network) (uint64, error) {
 	case defaultWeight:
 		return defaultStake, nil
 	default:
-		return app.Prompt.CaptureWeight(txt)
+		return app.Prompt.CaptureWeight(txt, limits.Check)
 	}
 }
 
-func GetTimeParametersPrimaryNetwork(network models.Network, nodeIndex int, validationDuration time.Duration, validationStartTimeStr string, nodeCmd bool) (time.Time, time.Duration, error) {
+func GetTimeParametersPrimaryNetwork(network models.Network, nodeIndex int, validationDuration time.Duration, validationStartTimeStr string, validationTimeZone string, nodeCmd bool) (time.Time, time.Duration, error) {
 	const (
 		defaultDurationOption = "Minimum staking duration on primary network"
 		custom                = "Custom"
@@ -170,10 +162,17 @@ func GetTimeParametersPrimaryNetwork(network models.Network, nodeIndex int, vali
 	var err error
 	var start time.Time
 	if validationStartTimeStr != "" {
-		start, err = time.Parse(constants.TimeParseLayout, validationStartTimeStr)
+		loc, err := utils.LoadTimeZone(validationTimeZone)
 		if err != nil {
 			return time.Time{}, 0, err
 		}
+		start, err = utils.ParseStartTime(validationStartTimeStr, time.Now(), loc)
+		if err != nil {
+			return time.Time{}, 0, err
+		}
+		if err := utils.CheckStartTimeLeadTime(start, time.Now(), constants.StakingMinimumLeadTime); err != nil {
+			return time.Time{}, 0, err
+		}
 	} else {
 		start = time.Now().Add(constants.PrimaryNetworkValidatingStartLeadTimeNodeCmd)
 		if !nodeCmd {
@@ -230,7 +229,7 @@ func getDefaultValidationTime(start time.Time, network models.Network, nodeIndex
 	}
 	end := start.Add(d)
 	if nodeIndex == 0 {
-		confirm := fmt.Sprintf("Your validator will finish staking by %s", end.Format(constants.TimeParseLayout))
+		confirm := fmt.Sprintf("Your validator will finish staking by %s", utils.FormatTimeWithUTC(end, nil))
 		yes, err := app.Prompt.CaptureYesNo(confirm)
 		if err != nil {
 			return 0, err
@@ -314,11 +313,13 @@ func validatePrimaryNetwork(_ *cobra.Command, args []string) error {
 	kc, err := keychain.GetKeychainFromCmdLineFlags(
 		app,
 		constants.PayTxsFeesMsg,
+		models.KeyRolePayer,
 		network,
 		keyName,
 		useEwoq,
 		useLedger,
 		ledgerAddresses,
+		signerSpec,
 		fee,
 	)
 	if err != nil {
@@ -383,11 +384,17 @@ func convertNanoAvaxToAvaxString(weight uint64) string {
 	return fmt.Sprintf("%.2f %s", float64(weight)/float64(units.Avax), constants.AVAXSymbol)
 }
 
-func PrintNodeJoinPrimaryNetworkOutput(nodeID ids.NodeID, weight uint64, network models.Network, start time.Time) {
+// PrintNodeJoinPrimaryNetworkOutput prints the validator params, with times in
+// [timeZone] (or the system one if empty) and in UTC
+func PrintNodeJoinPrimaryNetworkOutput(nodeID ids.NodeID, weight uint64, network models.Network, start time.Time, timeZone string) {
+	var loc *time.Location
+	if timeZone != "" {
+		loc, _ = utils.LoadTimeZone(timeZone)
+	}
 	ux.Logger.PrintToUser("NodeID: %s", nodeID.String())
 	ux.Logger.PrintToUser("Network: %s", network.Name())
-	ux.Logger.PrintToUser("Start time: %s", start.Format(constants.TimeParseLayout))
-	ux.Logger.PrintToUser("End time: %s", start.Add(duration).Format(constants.TimeParseLayout))
+	ux.Logger.PrintToUser("Start time: %s", utils.FormatTimeWithUTC(start, loc))
+	ux.Logger.PrintToUser("End time: %s", utils.FormatTimeWithUTC(start.Add(duration), loc))
 	// we need to divide by 10 ^ 9 since we were using nanoAvax
 	ux.Logger.PrintToUser("Weight: %s", convertNanoAvaxToAvaxString(weight))
 	ux.Logger.PrintToUser("Inputs complete, issuing transaction to add the provided validator information...")
@@ -10,6 +10,7 @@ import (
 
 	"github.com/MetalBlockchain/metalgo/vms/platformvm/status"
 
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
 	"github.com/MetalBlockchain/metal-cli/pkg/ansible"
 	"github.com/MetalBlockchain/metal-cli/pkg/ssh"
 
@@ -51,10 +52,11 @@ You can check the subnet sync status by calling avalanche node status <clusterNa
 	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on tahoe/devnet)")
 	cmd.Flags().BoolVarP(&useEwoq, "ewoq", "e", false, "use ewoq key [tahoe/devnet only]")
 	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	flags.AddSignerFlag(cmd, &signerSpec)
 
 	cmd.Flags().Uint64Var(&weight, "stake-amount", 0, "how many AVAX to stake in the validator")
 	cmd.Flags().DurationVar(&duration, "staking-period", 0, "how long validator validates for after start time")
-	cmd.Flags().StringVar(&startTimeStr, "start-time", "", "UTC start time when this validator starts validating, in 'YYYY-MM-DD HH:MM:SS' format")
+	cmd.Flags().StringVar(&startTimeStr, "start-time", "", "UTC start time when this validator starts validating, in 'YYYY-MM-DD HH:MM:SS' format, or relative to now (ex: 10m, 'in 2h')")
 	cmd.Flags().BoolVar(&defaultValidatorParams, "default-validator-params", false, "use default weight/start/duration params for subnet validator")
 
 	cmd.Flags().StringSliceVar(&validators, "validators", []string{}, "validate subnet for the given comma separated list of validators. defaults to all cluster nodes")
@@ -230,11 +232,13 @@ func validateSubnet(_ *cobra.Command, args []string) error {
 	kc, err := keychain.GetKeychainFromCmdLineFlags(
 		app,
 		constants.PayTxsFeesMsg,
+		models.KeyRoleValidatorManager,
 		network,
 		keyName,
 		useEwoq,
 		useLedger,
 		ledgerAddresses,
+		signerSpec,
 		fee,
 	)
 	if err != nil {
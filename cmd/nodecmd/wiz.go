@@ -4,6 +4,7 @@ package nodecmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -23,6 +24,7 @@ import (
 	"github.com/MetalBlockchain/metalgo/ids"
 	"github.com/MetalBlockchain/metalgo/utils/set"
 	"github.com/MetalBlockchain/metalgo/vms/platformvm/status"
+	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/slices"
 )
@@ -66,6 +68,7 @@ func newWizCmd() *cobra.Command {
 		Long: `(ALPHA Warning) This command is currently in experimental mode.
 
 The node wiz command creates a devnet and deploys, sync and validate a subnet into it. It creates the subnet if so needed.
+Once done, it prints a summary of the devnet endpoints and of the credentials to use them.
 `,
 		SilenceUsage: true,
 		Args:         cobra.RangeArgs(1, 2),
@@ -343,6 +346,7 @@ func wiz(cmd *cobra.Command, args []string) error {
 	ux.Logger.PrintToUser(logging.Green.Wrap("Subnet %s RPC URL: %s"), subnetName, network.BlockchainEndpoint(blockchainID.String()))
 	ux.Logger.PrintToUser("")
 
+	monitoringHostIP := ""
 	if addMonitoring {
 		if customGrafanaDashboardPath != "" {
 			if err = addCustomDashboard(clusterName, subnetName); err != nil {
@@ -352,13 +356,64 @@ func wiz(cmd *cobra.Command, args []string) error {
 		// no need to check for error, as it's ok not to have monitoring host
 		monitoringHosts, _ := ansible.GetInventoryFromAnsibleInventoryFile(app.GetMonitoringInventoryDir(clusterName))
 		if len(monitoringHosts) > 0 {
-			getMonitoringHint(monitoringHosts[0].IP)
+			monitoringHostIP = monitoringHosts[0].IP
+			getMonitoringHint(monitoringHostIP)
 		}
 	}
 
 	if err := deployClusterYAMLFile(clusterName, subnetName); err != nil {
 		return err
 	}
+	return printWizSummary(clusterName, network, sc, monitoringHostIP)
+}
+
+// printWizSummary prints the endpoints of the devnet and its subnet, together with the
+// credentials needed to use them
+func printWizSummary(clusterName string, network models.Network, sc models.Sidecar, monitoringHostIP string) error {
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	allHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return err
+	}
+	// api nodes are the intended entry points. if there are none, any validator will do
+	rpcHosts := clusterConfig.GetAPIHosts(allHosts)
+	if len(rpcHosts) == 0 {
+		rpcHosts = clusterConfig.GetValidatorHosts(allHosts)
+	}
+	blockchainID := sc.Networks[network.Name()].BlockchainID
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Devnet summary", ""})
+	table.SetRowLine(true)
+	table.SetAutoMergeCells(true)
+	table.Append([]string{"Cluster", clusterName})
+	table.Append([]string{"Network Endpoint", network.Endpoint})
+	table.Append([]string{"Subnet", sc.Name})
+	table.Append([]string{"Subnet ID", sc.Networks[network.Name()].SubnetID.String()})
+	table.Append([]string{"Blockchain ID", blockchainID.String()})
+	if sc.ChainID != "" {
+		table.Append([]string{"EVM Chain ID", sc.ChainID})
+	}
+	if sc.TokenSymbol != "" {
+		table.Append([]string{"Token Symbol", sc.TokenSymbol})
+	}
+	for _, host := range rpcHosts {
+		endpoint := fmt.Sprintf("http://%s:%d", host.IP, constants.AvalanchegoAPIPort)
+		table.Append([]string{"RPC URL", models.NewDevnetNetwork(endpoint, 0).BlockchainEndpoint(blockchainID.String())})
+	}
+	table.Append([]string{"Funded Key", "ewoq (devnet deploys and validations are paid with it)"})
+	if len(allHosts) > 0 {
+		table.Append([]string{"SSH Key", allHosts[0].SSHPrivateKeyPath})
+	}
+	table.Append([]string{"SSH Access", "avalanche node ssh " + clusterName})
+	if monitoringHostIP != "" {
+		table.Append([]string{"Grafana", fmt.Sprintf("http://%s:%d/dashboards (admin/admin)", monitoringHostIP, constants.AvalanchegoGrafanaPort)})
+	}
+	table.Append([]string{"Cluster YAML", app.GetClusterYAMLFilePath(clusterName)})
+	ux.Logger.PrintToUser("")
+	table.Render()
 	return nil
 }
 
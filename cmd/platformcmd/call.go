@@ -0,0 +1,110 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package platformcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metalgo/utils/rpc"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const platformMethodPrefix = "platform."
+
+var (
+	globalNetworkFlags    networkoptions.NetworkFlags
+	callSupportedNetworks = []networkoptions.NetworkOption{
+		networkoptions.Local,
+		networkoptions.Devnet,
+		networkoptions.Cluster,
+		networkoptions.Tahoe,
+		networkoptions.Mainnet,
+	}
+)
+
+// metal platform call
+func newCallCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "call [method] [params-json]",
+		Short: "Issue a raw P-Chain API call",
+		Long: `The platform call command issues an arbitrary P-Chain API call to the selected
+network endpoint, and pretty prints the JSON response.
+
+The method can be given with or without the 'platform.' prefix. Params are
+given as a JSON object, and default to {}. For example:
+
+  metal platform call getCurrentValidators '{"subnetID":"..."}' --tahoe
+
+Requests and responses are recorded in the CLI logs.`,
+		RunE:         call,
+		Args:         cobra.RangeArgs(1, 2),
+		SilenceUsage: true,
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, callSupportedNetworks)
+	return cmd
+}
+
+func call(_ *cobra.Command, args []string) error {
+	method := normalizeMethod(args[0])
+	paramsStr := "{}"
+	if len(args) == 2 {
+		paramsStr = args[1]
+	}
+	params, err := parseParams(paramsStr)
+	if err != nil {
+		return err
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		globalNetworkFlags,
+		true,
+		callSupportedNetworks,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	uri := network.Endpoint + "/ext/P"
+	app.Log.Info("P-Chain API request",
+		zap.String("uri", uri),
+		zap.String("method", method),
+		zap.String("params", string(params)),
+	)
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	var reply json.RawMessage
+	if err := rpc.NewEndpointRequester(uri).SendRequest(ctx, method, params, &reply); err != nil {
+		app.Log.Info("P-Chain API error", zap.String("method", method), zap.Error(err))
+		return fmt.Errorf("%s call to %s failed: %w", method, uri, err)
+	}
+	app.Log.Info("P-Chain API response", zap.String("method", method), zap.String("response", string(reply)))
+	var out bytes.Buffer
+	if err := json.Indent(&out, reply, "", "  "); err != nil {
+		return err
+	}
+	fmt.Println(out.String())
+	return nil
+}
+
+// normalizeMethod adds the 'platform.' namespace to [method] if missing
+func normalizeMethod(method string) string {
+	if strings.HasPrefix(method, platformMethodPrefix) {
+		return method
+	}
+	return platformMethodPrefix + method
+}
+
+// parseParams validates that [paramsStr] is a JSON object
+func parseParams(paramsStr string) (json.RawMessage, error) {
+	var params map[string]interface{}
+	if err := json.Unmarshal([]byte(paramsStr), &params); err != nil {
+		return nil, fmt.Errorf("params must be a JSON object: %w", err)
+	}
+	return json.RawMessage(paramsStr), nil
+}
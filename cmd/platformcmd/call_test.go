@@ -0,0 +1,25 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package platformcmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeMethod(t *testing.T) {
+	require.Equal(t, "platform.getHeight", normalizeMethod("getHeight"))
+	require.Equal(t, "platform.getHeight", normalizeMethod("platform.getHeight"))
+}
+
+func TestParseParams(t *testing.T) {
+	require := require.New(t)
+	params, err := parseParams(`{"subnetID":"11111111111111111111111111111111LpoYY"}`)
+	require.NoError(err)
+	require.JSONEq(`{"subnetID":"11111111111111111111111111111111LpoYY"}`, string(params))
+	_, err = parseParams(`["not", "an", "object"]`)
+	require.Error(err)
+	_, err = parseParams(`{invalid`)
+	require.Error(err)
+}
@@ -0,0 +1,32 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package platformcmd
+
+import (
+	"fmt"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/spf13/cobra"
+)
+
+var app *application.Avalanche
+
+// metal platform
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "platform",
+		Short: "Interact directly with the P-Chain API",
+		Long: `The platform command suite provides low level access to the P-Chain
+(platformvm) API of the networks known to the CLI.`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+	}
+	app = injectedApp
+	// platform call
+	cmd.AddCommand(newCallCmd())
+	return cmd
+}
@@ -3,12 +3,12 @@
 package primarycmd
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
 	"time"
 
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
 	"github.com/MetalBlockchain/metal-cli/cmd/subnetcmd"
 	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
 	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
@@ -20,8 +20,10 @@ import (
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
 	"github.com/MetalBlockchain/metal-cli/pkg/keychain"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/node"
 	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/signer"
 	"github.com/spf13/cobra"
 )
 
@@ -31,48 +33,56 @@ var (
 	keyName                             string
 	useLedger                           bool
 	ledgerAddresses                     []string
+	signerSpec                          string
 	nodeIDStr                           string
 	weight                              uint64
 	delegationFee                       uint32
 	startTimeStr                        string
+	startTimeZone                       string
 	duration                            time.Duration
 	publicKey                           string
 	pop                                 string
-	ErrMutuallyExlusiveKeyLedger        = errors.New("--key and --ledger,--ledger-addrs are mutually exclusive")
+	fromNode                            string
+	ErrMutuallyExlusiveKeyLedger        = errors.New("--key, --ledger/--ledger-addrs and --signer are mutually exclusive")
 	ErrStoredKeyOnMainnet               = errors.New("--key is not available for mainnet operations")
 )
 
-type jsonProofOfPossession struct {
-	PublicKey         string `json:"publicKey"`
-	ProofOfPossession string `json:"proofOfPossession"`
-}
-
 // avalanche primary addValidator
 func newAddValidatorCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "addValidator",
 		Short: "Add a validator to Primary Network",
 		Long: `The primary addValidator command adds a node as a validator 
-in the Primary Network`,
+in the Primary Network.
+
+The BLS public key and proof of possession of the node are given with --public-key
+and --proof-of-possession, or fetched from the node's info API with --from-node,
+along with its NodeID. They are verified before the transaction is built.`,
 		SilenceUsage: true,
 		RunE:         addValidator,
 		Args:         cobra.ExactArgs(0),
 	}
-	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, addValidatorSupportedNetworkOptions)
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, addValidatorSupportedNetworkOptions)
 	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use [tahoe only]")
 	cmd.Flags().StringVar(&nodeIDStr, "nodeID", "", "set the NodeID of the validator to add")
 	cmd.Flags().Uint64Var(&weight, "weight", 0, "set the staking weight of the validator to add")
-	cmd.Flags().StringVar(&startTimeStr, "start-time", "", "UTC start time when this validator starts validating, in 'YYYY-MM-DD HH:MM:SS' format")
+	cmd.Flags().StringVar(&startTimeStr, "start-time", "", "start time when this validator starts validating, in 'YYYY-MM-DD HH:MM:SS' format (UTC unless --timezone is given), RFC3339 format with offset, or relative to now (ex: 10m, 'in 2h')")
+	cmd.Flags().StringVar(&startTimeZone, "timezone", "", "time zone of --start-time (ex: Europe/Paris, local) (default UTC)")
 	cmd.Flags().DurationVar(&duration, "staking-period", 0, "how long this validator will be staking")
 	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on fuji)")
 	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	flags.AddSignerFlag(cmd, &signerSpec)
 	cmd.Flags().StringVar(&publicKey, "public-key", "", "set the BLS public key of the validator to add")
 	cmd.Flags().StringVar(&pop, "proof-of-possession", "", "set the BLS proof of possession of the validator to add")
+	cmd.Flags().StringVar(&fromNode, "from-node", "", "fetch the NodeID (unless --nodeID is given), BLS public key and proof of possession from the info API of the node at this IP or URL")
 	cmd.Flags().Uint32Var(&delegationFee, "delegation-fee", 0, "set the delegation fee (20 000 is equivalent to 2%)")
 	return cmd
 }
 
-func promptProofOfPossession() (jsonProofOfPossession, error) {
+// getProofOfPossession returns the verified BLS public key and proof of possession of
+// validator [nodeID], fetched from the node given with --from-node, taken from flags,
+// or prompted for
+func getProofOfPossession(network models.Network, nodeID ids.NodeID) (*signer.ProofOfPossession, error) {
 	if publicKey != "" {
 		err := prompts.ValidateHexa(publicKey)
 		if err != nil {
@@ -87,27 +97,62 @@ func promptProofOfPossession() (jsonProofOfPossession, error) {
 			pop = ""
 		}
 	}
-	if publicKey == "" || pop == "" {
+	var err error
+	if fromNode == "" && (publicKey == "" || pop == "") {
 		ux.Logger.PrintToUser("Next, we need the public key and proof of possession of the node's BLS")
-		ux.Logger.PrintToUser("SSH into the node and call info.getNodeID API to get the node's BLS info")
-		ux.Logger.PrintToUser("Check https://docs.avax.network/apis/avalanchego/apis/info#infogetnodeid for instructions on calling info.getNodeID API")
+		const (
+			fetchOption  = "Fetch them from the node's info API"
+			manualOption = "Enter them manually"
+		)
+		option, err := app.Prompt.CaptureList(
+			"How would you like to provide them?",
+			[]string{fetchOption, manualOption},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if option == fetchOption {
+			fromNode, err = app.Prompt.CaptureString("What is the IP or API URL of the node? (e.g. 111.22.33.44 or http://111.22.33.44:9650)")
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			ux.Logger.PrintToUser("SSH into the node and call info.getNodeID API to get the node's BLS info")
+			ux.Logger.PrintToUser("Check https://docs.avax.network/apis/avalanchego/apis/info#infogetnodeid for instructions on calling info.getNodeID API")
+		}
+	}
+	if fromNode != "" {
+		ux.Logger.PrintToUser("Fetching the BLS info of the node at %s...", fromNode)
+		nodePoPNodeID, proofOfPossession, err := node.GetProofOfPossession(fromNode, network)
+		if err != nil {
+			return nil, err
+		}
+		if nodePoPNodeID != nodeID {
+			return nil, fmt.Errorf("the node at %s is %s, not the validator to add %s", fromNode, nodePoPNodeID, nodeID)
+		}
+		ux.Logger.PrintToUser("BLS proof of possession of %s verified", nodeID)
+		return proofOfPossession, nil
 	}
-	var err error
 	if publicKey == "" {
 		txt := "What is the public key of the node's BLS?"
 		publicKey, err = app.Prompt.CaptureValidatedString(txt, prompts.ValidateHexa)
 		if err != nil {
-			return jsonProofOfPossession{}, err
+			return nil, err
 		}
 	}
 	if pop == "" {
 		txt := "What is the proof of possession of the node's BLS?"
 		pop, err = app.Prompt.CaptureValidatedString(txt, prompts.ValidateHexa)
 		if err != nil {
-			return jsonProofOfPossession{}, err
+			return nil, err
 		}
 	}
-	return jsonProofOfPossession{PublicKey: publicKey, ProofOfPossession: pop}, nil
+	proofOfPossession, err := node.ParseProofOfPossession(publicKey, pop)
+	if err != nil {
+		return nil, err
+	}
+	ux.Logger.PrintToUser("BLS proof of possession verified")
+	return proofOfPossession, nil
 }
 
 func addValidator(_ *cobra.Command, _ []string) error {
@@ -132,20 +177,20 @@ func addValidator(_ *cobra.Command, _ []string) error {
 		useLedger = true
 	}
 
-	if useLedger && keyName != "" {
+	if !flags.EnsureMutuallyExclusive([]bool{useLedger, keyName != "", signerSpec != ""}) {
 		return ErrMutuallyExlusiveKeyLedger
 	}
 
 	switch network.Kind {
 	case models.Tahoe:
-		if !useLedger && keyName == "" {
+		if !useLedger && keyName == "" && signerSpec == "" {
 			useLedger, keyName, err = prompts.GetFujiKeyOrLedger(app.Prompt, constants.PayTxsFeesMsg, app.GetKeyDir())
 			if err != nil {
 				return err
 			}
 		}
 	case models.Mainnet:
-		useLedger = true
+		useLedger = signerSpec == ""
 		if keyName != "" {
 			return ErrStoredKeyOnMainnet
 		}
@@ -153,8 +198,15 @@ func addValidator(_ *cobra.Command, _ []string) error {
 		return errors.New("unsupported network")
 	}
 
+	var proofOfPossession *signer.ProofOfPossession
 	if nodeIDStr == "" {
-		nodeID, err = subnetcmd.PromptNodeID()
+		if fromNode != "" {
+			// a single query gets both the NodeID and the BLS info of the node
+			ux.Logger.PrintToUser("Fetching the NodeID and BLS info of the node at %s...", fromNode)
+			nodeID, proofOfPossession, err = node.GetProofOfPossession(fromNode, network)
+		} else {
+			nodeID, err = subnetcmd.PromptNodeID()
+		}
 		if err != nil {
 			return err
 		}
@@ -165,42 +217,40 @@ func addValidator(_ *cobra.Command, _ []string) error {
 		}
 	}
 
-	minValStake, err := nodecmd.GetMinStakingAmount(network)
+	limits, err := subnet.GetStakeWeightLimits(app, network, ids.Empty)
 	if err != nil {
 		return err
 	}
 	if weight == 0 {
-		weight, err = nodecmd.PromptWeightPrimaryNetwork(network)
+		weight, err = nodecmd.PromptWeightPrimaryNetwork(network, limits)
 		if err != nil {
 			return err
 		}
 	}
-	if weight < minValStake {
-		return fmt.Errorf("illegal weight, must be greater than or equal to %d: %d", minValStake, weight)
+	if err := limits.Check(weight); err != nil {
+		return err
 	}
 
 	fee := network.GenesisParams().AddPrimaryNetworkValidatorFee
-	kc, err := keychain.GetKeychain(app, false, useLedger, ledgerAddresses, keyName, network, fee)
+	kc, err := keychain.GetKeychain(app, models.KeyRolePayer, false, useLedger, ledgerAddresses, signerSpec, keyName, network, fee)
 	if err != nil {
 		return err
 	}
 
 	network.HandlePublicNetworkSimulation()
 
-	jsonPop, err := promptProofOfPossession()
-	if err != nil {
-		return err
-	}
-	popBytes, err := json.Marshal(jsonPop)
-	if err != nil {
-		return err
+	if proofOfPossession == nil {
+		proofOfPossession, err = getProofOfPossession(network, nodeID)
+		if err != nil {
+			return err
+		}
 	}
-	start, duration, err = nodecmd.GetTimeParametersPrimaryNetwork(network, 0, duration, startTimeStr, false)
+	start, duration, err = nodecmd.GetTimeParametersPrimaryNetwork(network, 0, duration, startTimeStr, startTimeZone, false)
 	if err != nil {
 		return err
 	}
 	deployer := subnet.NewPublicDeployer(app, kc, network)
-	nodecmd.PrintNodeJoinPrimaryNetworkOutput(nodeID, weight, network, start)
+	nodecmd.PrintNodeJoinPrimaryNetworkOutput(nodeID, weight, network, start, startTimeZone)
 	recipientAddr := kc.Addresses().List()[0]
 	if delegationFee == 0 {
 		delegationFee, err = getDelegationFeeOption(app, network)
@@ -213,7 +263,7 @@ func addValidator(_ *cobra.Command, _ []string) error {
 			return fmt.Errorf("delegation fee has to be larger than %d", defaultFee)
 		}
 	}
-	_, err = deployer.AddPermissionlessValidator(ids.Empty, ids.Empty, nodeID, weight, uint64(start.Unix()), uint64(start.Add(duration).Unix()), recipientAddr, delegationFee, popBytes, nil)
+	_, err = deployer.AddPermissionlessValidator(ids.Empty, ids.Empty, nodeID, weight, uint64(start.Unix()), uint64(start.Add(duration).Unix()), recipientAddr, delegationFee, nil, proofOfPossession)
 	return err
 }
 
@@ -0,0 +1,199 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package relayercmd
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/evm"
+	"github.com/MetalBlockchain/metal-cli/pkg/key"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
+	"github.com/MetalBlockchain/metal-cli/pkg/teleporter"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/spf13/cobra"
+)
+
+var (
+	relayerVersion string
+	skipCChain     bool
+
+	// the relayer key pays for the message deliveries on each chain
+	relayerKeyMinBalance = new(big.Int).Mul(big.NewInt(1e18), big.NewInt(10))
+)
+
+// metal relayer deploy
+func newDeployCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deploy [subnetName...]",
+		Short: "Configures and starts a relayer for the local subnets",
+		Long: `The relayer deploy command generates the AWM relayer configuration for the given
+Subnets deployed to the local network, and starts the relayer with it, so that the
+Teleporter messages sent between them are delivered.
+
+If no Subnet is given, all the local Subnets with Teleporter deployed are relayed.
+The C-Chain is also relayed if Teleporter is deployed there, unless --skip-c-chain
+is given. At least two chains are needed. Deploy Teleporter into the Subnets with
+teleporter deploy.
+
+The relayer pays for the deliveries with the key ` + constants.AWMRelayerKeyName + `,
+created if needed and funded on every chain by the ewoq key. A running relayer is
+restarted with the new configuration.`,
+		SilenceUsage: true,
+		RunE:         deploy,
+	}
+	cmd.Flags().StringVar(&relayerVersion, "version", "latest", "awm-relayer release to run")
+	cmd.Flags().BoolVar(&skipCChain, "skip-c-chain", false, "do not relay the C-Chain messages")
+	return cmd
+}
+
+func deploy(_ *cobra.Command, args []string) error {
+	network := models.NewLocalNetwork()
+	chains, err := getRelayerChains(network, args)
+	if err != nil {
+		return err
+	}
+	if len(chains) < 2 {
+		return fmt.Errorf("at least two chains with teleporter deployed are needed to relay messages, found %d", len(chains))
+	}
+	relayerKey, err := loadOrCreateRelayerKey(network)
+	if err != nil {
+		return err
+	}
+	ewoq, err := key.LoadEwoq(network.ID)
+	if err != nil {
+		return err
+	}
+	for _, chain := range chains {
+		if err := fundRelayerKey(chain, relayerKey.C(), hex.EncodeToString(ewoq.Raw())); err != nil {
+			return err
+		}
+	}
+	if running, err := teleporter.RelayerIsRunning(app); err != nil {
+		return err
+	} else if running {
+		ux.Logger.PrintToUser("Stopping running relayer")
+		if err := teleporter.StopRelayer(app); err != nil {
+			return err
+		}
+	}
+	config := teleporter.CreateRelayerConfig(
+		network,
+		app.GetAWMRelayerStorageDir(),
+		chains,
+		hex.EncodeToString(relayerKey.Raw()),
+		relayerKey.C(),
+	)
+	if err := teleporter.WriteRelayerConfig(app, config); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Relayer config written to %s", app.GetAWMRelayerConfigPath())
+	for _, chain := range chains {
+		ux.Logger.PrintToUser("  relaying %s (%s)", chain.Name, chain.BlockchainID)
+	}
+	return startRelayer()
+}
+
+// getRelayerChains returns the local chains to relay: the given subnets, or all
+// the ones with teleporter deployed, and the C-Chain if teleporter is deployed there
+func getRelayerChains(network models.Network, subnetNames []string) ([]teleporter.RelayerChain, error) {
+	explicit := len(subnetNames) > 0
+	if !explicit {
+		var err error
+		subnetNames, err = app.GetSidecarNames()
+		if err != nil {
+			return nil, err
+		}
+	}
+	chains := []teleporter.RelayerChain{}
+	for _, subnetName := range subnetNames {
+		sc, err := app.LoadSidecar(subnetName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load sidecar: %w", err)
+		}
+		networkData := sc.Networks[network.Name()]
+		if networkData.BlockchainID == ids.Empty || networkData.TeleporterMessengerAddress == "" {
+			if explicit {
+				return nil, fmt.Errorf("teleporter is not deployed to subnet %s on %s. Deploy it with 'metal teleporter deploy %s'",
+					subnetName, network.Name(), subnetName)
+			}
+			continue
+		}
+		chains = append(chains, teleporter.RelayerChain{
+			Name:             subnetName,
+			SubnetID:         networkData.SubnetID,
+			BlockchainID:     networkData.BlockchainID,
+			RPCEndpoint:      network.BlockchainEndpoint(networkData.BlockchainID.String()),
+			WSEndpoint:       network.BlockchainWSEndpoint(networkData.BlockchainID.String()),
+			MessengerAddress: networkData.TeleporterMessengerAddress,
+		})
+	}
+	if skipCChain {
+		return chains, nil
+	}
+	extraLocalNetworkData, err := subnet.GetExtraLocalNetworkData(app)
+	if errors.Is(err, os.ErrNotExist) {
+		return chains, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if extraLocalNetworkData.CChainTeleporterMessengerAddress != "" {
+		cChainID, err := subnet.GetChainID(network, "C")
+		if err != nil {
+			return nil, err
+		}
+		chains = append(chains, teleporter.RelayerChain{
+			Name:             "C-Chain",
+			SubnetID:         ids.Empty,
+			BlockchainID:     cChainID,
+			RPCEndpoint:      network.CChainEndpoint(),
+			WSEndpoint:       network.CChainWSEndpoint(),
+			MessengerAddress: extraLocalNetworkData.CChainTeleporterMessengerAddress,
+		})
+	}
+	return chains, nil
+}
+
+func loadOrCreateRelayerKey(network models.Network) (*key.SoftKey, error) {
+	keyPath := app.GetKeyPath(constants.AWMRelayerKeyName)
+	if utils.FileExists(keyPath) {
+		return key.LoadSoft(network.ID, keyPath)
+	}
+	k, err := key.NewSoft(network.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := k.Save(keyPath); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// fundRelayerKey sends funds from [fundingPrivateKey] to the relayer key on
+// [chain], if it has less than the min balance
+func fundRelayerKey(chain teleporter.RelayerChain, relayerAddress string, fundingPrivateKey string) error {
+	client, err := evm.GetClient(chain.RPCEndpoint)
+	if err != nil {
+		return err
+	}
+	balance, err := evm.GetAddressBalance(client, relayerAddress)
+	if err != nil {
+		return err
+	}
+	if balance.Cmp(relayerKeyMinBalance) >= 0 {
+		return nil
+	}
+	toFund := new(big.Int).Sub(relayerKeyMinBalance, balance)
+	if err := evm.FundAddress(client, fundingPrivateKey, relayerAddress, toFund); err != nil {
+		return fmt.Errorf("failure funding relayer key on %s: %w", chain.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package relayercmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const logsPollInterval = 500 * time.Millisecond
+
+var (
+	logsFollow bool
+	logsLines  int
+)
+
+// metal relayer logs
+func newLogsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Prints the relayer logs",
+		Long: `The relayer logs command prints the last lines of the AWM relayer log. Use
+--follow to keep printing new lines as they are written.`,
+		SilenceUsage: true,
+		RunE:         logs,
+		Args:         cobra.ExactArgs(0),
+	}
+	cmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "keep printing new log lines")
+	cmd.Flags().IntVarP(&logsLines, "lines", "n", 10, "number of previous lines to print")
+	return cmd
+}
+
+func logs(*cobra.Command, []string) error {
+	logPath := app.GetAWMRelayerLogPath()
+	data, err := os.ReadFile(logPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no relayer log found at %s. Start the relayer with 'metal relayer deploy'", logPath)
+	}
+	if err != nil {
+		return err
+	}
+	offset := int64(len(data))
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if logsLines < 0 {
+		logsLines = 0
+	}
+	if len(lines) > logsLines {
+		lines = lines[len(lines)-logsLines:]
+	}
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	if !logsFollow {
+		return nil
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	ticker := time.NewTicker(logsPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		newData, err := readFrom(logPath, offset)
+		if err != nil {
+			return err
+		}
+		// only print complete lines
+		lastNewLine := bytes.LastIndexByte(newData, '\n')
+		if lastNewLine < 0 {
+			continue
+		}
+		fmt.Print(string(newData[:lastNewLine+1]))
+		offset += int64(lastNewLine + 1)
+	}
+}
+
+// readFrom returns the content of [path] after [offset], or all of it if the
+// file was truncated
+func readFrom(path string, offset int64) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() < offset {
+		offset = 0
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(file)
+}
@@ -0,0 +1,39 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package relayercmd
+
+import (
+	"fmt"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/spf13/cobra"
+)
+
+var app *application.Avalanche
+
+// metal relayer
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "relayer",
+		Short: "Manage the AWM relayer of the local network",
+		Long: `The relayer command suite provides a collection of tools for running an AWM
+relayer that delivers the Teleporter messages sent between the Subnets deployed
+to the local network.`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+	}
+	app = injectedApp
+	// relayer deploy
+	cmd.AddCommand(newDeployCmd())
+	// relayer start
+	cmd.AddCommand(newStartCmd())
+	// relayer stop
+	cmd.AddCommand(newStopCmd())
+	// relayer logs
+	cmd.AddCommand(newLogsCmd())
+	return cmd
+}
@@ -0,0 +1,43 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package relayercmd
+
+import (
+	"fmt"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/teleporter"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// metal relayer start
+func newStartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Starts the relayer",
+		Long: `The relayer start command starts the AWM relayer in the background, with the
+configuration generated by the last relayer deploy.`,
+		SilenceUsage: true,
+		RunE:         start,
+		Args:         cobra.ExactArgs(0),
+	}
+	cmd.Flags().StringVar(&relayerVersion, "version", "latest", "awm-relayer release to run")
+	return cmd
+}
+
+func start(*cobra.Command, []string) error {
+	if !utils.FileExists(app.GetAWMRelayerConfigPath()) {
+		return fmt.Errorf("there is no relayer config. Generate it with 'metal relayer deploy'")
+	}
+	return startRelayer()
+}
+
+func startRelayer() error {
+	pid, err := teleporter.StartRelayer(app, relayerVersion)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Relayer started, pid: %d, logs at: %s", pid, app.GetAWMRelayerLogPath())
+	return nil
+}
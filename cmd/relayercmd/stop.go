@@ -0,0 +1,36 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package relayercmd
+
+import (
+	"errors"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/teleporter"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// metal relayer stop
+func newStopCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "stop",
+		Short:        "Stops the relayer",
+		Long:         `The relayer stop command stops the AWM relayer started by relayer deploy or relayer start.`,
+		SilenceUsage: true,
+		RunE:         stop,
+		Args:         cobra.ExactArgs(0),
+	}
+}
+
+func stop(*cobra.Command, []string) error {
+	err := teleporter.StopRelayer(app)
+	if errors.Is(err, teleporter.ErrRelayerNotRunning) {
+		ux.Logger.PrintToUser("Relayer is not running")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Relayer stopped")
+	return nil
+}
@@ -11,24 +11,37 @@ import (
 	"strings"
 	"time"
 
+	"github.com/MetalBlockchain/metal-cli/cmd/platformcmd"
 	"github.com/MetalBlockchain/metal-cli/cmd/primarycmd"
 
 	"github.com/MetalBlockchain/metal-cli/cmd/nodecmd"
 
 	"github.com/MetalBlockchain/metal-cli/cmd/configcmd"
+	"github.com/MetalBlockchain/metal-cli/cmd/contractcmd"
 
 	"github.com/MetalBlockchain/metal-cli/cmd/backendcmd"
+	"github.com/MetalBlockchain/metal-cli/cmd/devnetcmd"
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
+	"github.com/MetalBlockchain/metal-cli/cmd/historycmd"
 	"github.com/MetalBlockchain/metal-cli/cmd/keycmd"
 	"github.com/MetalBlockchain/metal-cli/cmd/networkcmd"
+	"github.com/MetalBlockchain/metal-cli/cmd/relayercmd"
+	"github.com/MetalBlockchain/metal-cli/cmd/servecmd"
 	"github.com/MetalBlockchain/metal-cli/cmd/subnetcmd"
+	"github.com/MetalBlockchain/metal-cli/cmd/teleportercmd"
 	"github.com/MetalBlockchain/metal-cli/cmd/transactioncmd"
 	"github.com/MetalBlockchain/metal-cli/cmd/updatecmd"
+	"github.com/MetalBlockchain/metal-cli/cmd/usecmd"
 	"github.com/MetalBlockchain/metal-cli/internal/migrations"
 	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/audit"
 	"github.com/MetalBlockchain/metal-cli/pkg/config"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
 	"github.com/MetalBlockchain/metal-cli/pkg/metrics"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/notify"
 	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
+	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
 	"github.com/MetalBlockchain/metal-cli/pkg/utils"
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
 	"github.com/MetalBlockchain/metalgo/utils/logging"
@@ -43,6 +56,10 @@ var (
 	Version   = ""
 	cfgFile   string
 	skipCheck bool
+	debug     bool
+	offline   bool
+	// per run log file, written on debug mode
+	debugLogPath string
 )
 
 func NewRootCmd() *cobra.Command {
@@ -54,10 +71,19 @@ everything Metal. This release specializes in helping developers
 build and test Subnets.
 
 To get started, look at the documentation for the subcommands or jump right
-in with metal subnet create myNewSubnet.`,
+in with metal subnet create myNewSubnet.
+
+Every flag can also be given with a METAL_ prefixed env var, eg METAL_KEY for
+--key or METAL_NODE_ID for --nodeID, and METAL_NETWORK (local, devnet, tahoe,
+mainnet or cluster) selects the network when no network flag is given. Flags
+given on the command line take precedence.
+
+Use --offline (or METAL_OFFLINE=true) to work without network access: commands use
+the previously downloaded binaries and release information, and fail instead of
+downloading anything missing. Without it, the same cached data is used whenever
+the network can't be reached.`,
 		PersistentPreRunE: createApp,
 		Version:           Version,
-		PersistentPostRun: handleTracking,
 	}
 
 	// Disable printing the completion command
@@ -65,7 +91,18 @@ in with metal subnet create myNewSubnet.`,
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.avalanche-cli/config.json)")
 	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "ERROR", "log level for the application")
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "write detailed logs of RPC requests, file writes and downloads into a per run log file")
 	rootCmd.PersistentFlags().BoolVar(&skipCheck, constants.SkipUpdateFlag, false, "skip check for new versions")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "never access the network, using the previously downloaded binaries and release information")
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		// the pre run hook selecting the JSON output doesn't run on flag errors
+		if flags.JSONOutputRequestedInArgs(cmd, os.Args[1:]) {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+			ux.SetJSONOutput(true)
+		}
+		return ux.NewCodedError(ux.ErrCodeInvalidArguments, err)
+	})
 
 	// add sub commands
 	rootCmd.AddCommand(subnetcmd.NewCmd(app))
@@ -88,10 +125,40 @@ in with metal subnet create myNewSubnet.`,
 	// add node command
 	rootCmd.AddCommand(nodecmd.NewCmd(app))
 
+	// add use command
+	rootCmd.AddCommand(usecmd.NewCmd(app))
+
+	// add platform command
+	rootCmd.AddCommand(platformcmd.NewCmd(app))
+
+	// add teleporter command
+	rootCmd.AddCommand(teleportercmd.NewCmd(app))
+
+	// add relayer command
+	rootCmd.AddCommand(relayercmd.NewCmd(app))
+
+	// add contract command
+	rootCmd.AddCommand(contractcmd.NewCmd(app))
+
+	// add serve command
+	rootCmd.AddCommand(servecmd.NewCmd(app))
+
+	// add history command
+	rootCmd.AddCommand(historycmd.NewCmd(app))
+
+	// add devnet command
+	rootCmd.AddCommand(devnetcmd.NewCmd(app))
+
+	registerCompletions(rootCmd)
+
 	return rootCmd
 }
 
 func createApp(cmd *cobra.Command, _ []string) error {
+	// METAL_OUTPUT selects the output format, it does not set --output flags
+	if err := flags.ApplyEnvOverrides(cmd.Flags(), ux.OutputEnvVarName, constants.GithubAPITokenEnvVarName); err != nil {
+		return ux.NewCodedError(ux.ErrCodeInvalidArguments, err)
+	}
 	baseDir, err := setupEnv()
 	if err != nil {
 		return err
@@ -100,11 +167,38 @@ func createApp(cmd *cobra.Command, _ []string) error {
 	if err != nil {
 		return err
 	}
+	if flags.JSONOutputRequested(cmd) {
+		// errors are printed as JSON by Execute
+		cmd.Root().SilenceErrors = true
+		ux.SetJSONOutput(true)
+	}
+	if debug {
+		utils.EnableHTTPTracing(log)
+		log.Debug("running command", zap.Strings("args", audit.RedactArgs(os.Args)))
+	}
 	cf := config.New()
 	app.Setup(baseDir, log, cf, prompts.NewPrompter(), application.NewDownloader())
+	app.SetCommand(cmd.CommandPath())
+	app.Downloader = application.NewCachingDownloader(application.NewResumingDownloader(app.GetPartialDownloadsDir()), app.GetMetadataCacheDir(), log)
+	utils.SetOffline(offline)
 
 	initConfig()
 
+	if app.Conf.GetConfigBoolValue(constants.ConfigNonInteractiveKey) {
+		app.Prompt = prompts.NewNonInteractivePrompter()
+	}
+
+	// shell completion requests only need the app dir and config
+	if !metrics.CheckCommandIsNotCompletion(cmd) {
+		return nil
+	}
+
+	// the first local network node API port is configurable
+	models.SetLocalAPIPort(subnet.GetLocalNetworkSettings(app).HTTPPort)
+	// public network API endpoints can be overridden in the config file
+	models.SetTahoeAPIEndpoint(app.Conf.GetConfigStringValue(constants.ConfigTahoeAPIEndpointKey))
+	models.SetMainnetAPIEndpoint(app.Conf.GetConfigStringValue(constants.ConfigMainnetAPIEndpointKey))
+
 	if err := migrations.RunMigrations(app); err != nil {
 		return err
 	}
@@ -114,6 +208,9 @@ func createApp(cmd *cobra.Command, _ []string) error {
 			return err
 		}
 	}
+	if utils.IsOffline() {
+		return nil
+	}
 	if err := checkForUpdates(cmd, app); err != nil {
 		return err
 	}
@@ -188,8 +285,81 @@ func checkForUpdates(cmd *cobra.Command, app *application.Avalanche) error {
 	return nil
 }
 
-func handleTracking(cmd *cobra.Command, _ []string) {
-	metrics.HandleTracking(cmd, app, nil)
+// recordAuditEntry appends the result of running [cmd] to the audit log, if it
+// changes state. Failing to record it is logged but doesn't fail the command
+func recordAuditEntry(cmd *cobra.Command, cmdErr error) {
+	if cmd == nil || app.GetBaseDir() == "" || !audit.IsStateChanging(cmd.CommandPath()) {
+		return
+	}
+	if help, _ := cmd.Flags().GetBool("help"); help {
+		return
+	}
+	entry := audit.Entry{
+		Time:    time.Now().UTC(),
+		Command: cmd.CommandPath(),
+		Args:    audit.RedactArgs(append([]string{cmd.Root().Name()}, os.Args[1:]...)),
+		TxIDs:   audit.IssuedTxs(),
+		Success: cmdErr == nil,
+	}
+	if cmdErr != nil {
+		entry.Error = utils.RedactSecrets(cmdErr.Error())
+	}
+	if usr, err := user.Current(); err == nil {
+		entry.User = usr.Username
+	}
+	if err := audit.Append(app.GetAuditLogPath(), entry); err != nil {
+		app.Log.Warn("failed recording audit log entry", zap.Error(err))
+	}
+}
+
+// sendNotification notifies the channels set in the config file about the end
+// of [cmd], if it changes state. Failing to notify is logged but doesn't
+// fail the command
+func sendNotification(cmd *cobra.Command, cmdErr error, duration time.Duration) {
+	if cmd == nil || app.Conf == nil || utils.IsOffline() || !notify.IsNotified(cmd.CommandPath()) {
+		return
+	}
+	if help, _ := cmd.Flags().GetBool("help"); help {
+		return
+	}
+	conf := notify.Config{
+		WebhookURL:   app.Conf.GetConfigStringValue(constants.ConfigNotifyWebhookURLKey),
+		SMTPServer:   app.Conf.GetConfigStringValue(constants.ConfigNotifySMTPServerKey),
+		SMTPUser:     app.Conf.GetConfigStringValue(constants.ConfigNotifySMTPUserKey),
+		SMTPPassword: os.Getenv(constants.NotifySMTPPasswordEnvVarName),
+		EmailFrom:    app.Conf.GetConfigStringValue(constants.ConfigNotifyEmailFromKey),
+	}
+	for _, to := range strings.Split(app.Conf.GetConfigStringValue(constants.ConfigNotifyEmailToKey), ",") {
+		if to = strings.TrimSpace(to); to != "" {
+			conf.EmailTo = append(conf.EmailTo, to)
+		}
+	}
+	if !conf.Enabled() {
+		return
+	}
+	notification := notify.Notification{
+		Command:  cmd.CommandPath(),
+		TxIDs:    audit.IssuedTxs(),
+		Duration: duration,
+		Time:     time.Now().UTC(),
+	}
+	if cmdErr != nil {
+		notification.Error = utils.RedactSecrets(cmdErr.Error())
+	}
+	if err := notify.Send(conf, notification); err != nil {
+		app.Log.Warn("failed sending notification", zap.Error(err))
+	}
+}
+
+// trackCommand reports the anonymous usage event of [cmd], if the user opted in
+func trackCommand(cmd *cobra.Command, cmdErr error, duration time.Duration) {
+	if cmd == nil || app.GetBaseDir() == "" {
+		return
+	}
+	if help, _ := cmd.Flags().GetBool("help"); help {
+		return
+	}
+	metrics.TrackCommand(cmd, app, Version, cmdErr, duration)
 }
 
 func setupEnv() (string, error) {
@@ -274,8 +444,16 @@ func setupLogging(baseDir string) (logging.Logger, error) {
 	config.MaxFiles = constants.MaxNumOfLogFiles
 	config.MaxAge = constants.RetainOldFiles
 
+	logName := "metal"
+	if debug {
+		// each debug run gets its own log file, so it can be shared when reporting issues
+		config.LogLevel = logging.Debug
+		logName = fmt.Sprintf("metal-debug-%s-%d", time.Now().Format("20060102-150405"), os.Getpid())
+		debugLogPath = filepath.Join(config.Directory, logName+".log")
+	}
+
 	factory := logging.NewFactory(config)
-	log, err := factory.Make("metal")
+	log, err := factory.Make(logName)
 	if err != nil {
 		factory.Close()
 		return nil, fmt.Errorf("failed setting up logging, exiting: %w", err)
@@ -303,8 +481,19 @@ func initConfig() {
 func Execute() {
 	app = application.New()
 	rootCmd := NewRootCmd()
-	err := rootCmd.Execute()
+	start := time.Now()
+	cmd, err := rootCmd.ExecuteC()
+	recordAuditEntry(cmd, err)
+	sendNotification(cmd, err, time.Since(start))
+	trackCommand(cmd, err, time.Since(start))
 	if err != nil {
+		if ux.JSONOutput() {
+			ux.PrintError(err)
+		}
+		if debugLogPath != "" {
+			app.Log.Debug("command failed", zap.Error(err))
+			fmt.Fprintf(os.Stderr, "Debug log: %s\n", debugLogPath)
+		}
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,545 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package servecmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metalgo/ids"
+)
+
+// max size of request bodies
+const maxBodySize = 1 << 20
+
+// subnet and key names are given as command args, so they are restricted to
+// plain names, that can't be taken as flags
+var nameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_]*$`)
+
+// network names accepted by the API, and the CLI flag that selects each one
+var networkFlags = map[string]string{
+	"local":   "--local",
+	"devnet":  "--devnet",
+	"tahoe":   "--tahoe",
+	"testnet": "--tahoe",
+	"mainnet": "--mainnet",
+}
+
+// runner runs a CLI command, returning its exit code, its stdout and its stderr
+type runner interface {
+	Run(ctx context.Context, args []string) (int, []byte, []byte, error)
+}
+
+// cliRunner runs the commands with the CLI executable, in JSON and non interactive mode
+type cliRunner struct {
+	executable string
+	configFile string
+}
+
+func (r *cliRunner) Run(ctx context.Context, args []string) (int, []byte, []byte, error) {
+	cmdArgs := []string{"--json", "--" + constants.SkipUpdateFlag}
+	if r.configFile != "" {
+		cmdArgs = append(cmdArgs, "--config", r.configFile)
+	}
+	cmd := exec.CommandContext(ctx, r.executable, append(cmdArgs, args...)...)
+	cmd.Env = append(os.Environ(), strings.ToUpper(constants.ConfigNonInteractiveKey)+"=true")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), stdout.Bytes(), stderr.Bytes(), nil
+	}
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	return 0, stdout.Bytes(), stderr.Bytes(), nil
+}
+
+// apiError is the JSON error of failed requests and operations
+type apiError struct {
+	Code    ux.ErrorCode `json:"code"`
+	Message string       `json:"message"`
+}
+
+// operationResponse is the response of the API requests that run a CLI operation
+type operationResponse struct {
+	Args     []string        `json:"args"`
+	ExitCode int             `json:"exitCode"`
+	Result   json.RawMessage `json:"result,omitempty"`
+	Error    *apiError       `json:"error,omitempty"`
+	// messages printed to the user by the operation
+	Log string `json:"log,omitempty"`
+}
+
+type createSubnetRequest struct {
+	Name        string `json:"name"`
+	ChainID     uint64 `json:"chainID"`
+	TokenSymbol string `json:"tokenSymbol"`
+	// Subnet-EVM version, the latest one if not given
+	VMVersion string `json:"vmVersion"`
+	Force     bool   `json:"force"`
+}
+
+type deployRequest struct {
+	Network        string   `json:"network"`
+	Key            string   `json:"key"`
+	Ewoq           bool     `json:"ewoq"`
+	SameControlKey bool     `json:"sameControlKey"`
+	ControlKeys    []string `json:"controlKeys"`
+	Threshold      uint32   `json:"threshold"`
+	SubnetAuthKeys []string `json:"subnetAuthKeys"`
+	OutputTxPath   string   `json:"outputTxPath"`
+}
+
+type addValidatorRequest struct {
+	Network string `json:"network"`
+	Key     string `json:"key"`
+	Ewoq    bool   `json:"ewoq"`
+	NodeID  string `json:"nodeID"`
+	Weight  uint64 `json:"weight"`
+	// UTC "YYYY-MM-DD HH:MM:SS" time, or relative to now (eg 10m)
+	StartTime      string   `json:"startTime"`
+	StakingPeriod  string   `json:"stakingPeriod"`
+	SubnetAuthKeys []string `json:"subnetAuthKeys"`
+	OutputTxPath   string   `json:"outputTxPath"`
+}
+
+type startNetworkRequest struct {
+	SnapshotName string `json:"snapshotName"`
+	NumNodes     uint32 `json:"numNodes"`
+}
+
+type stopNetworkRequest struct {
+	SnapshotName string `json:"snapshotName"`
+}
+
+type cleanNetworkRequest struct {
+	Hard bool `json:"hard"`
+}
+
+// route maps a method and a path, where segments like {name} are captured,
+// to a handler
+type route struct {
+	method  string
+	path    []string
+	handler func(w http.ResponseWriter, r *http.Request, params map[string]string)
+}
+
+type server struct {
+	token  string
+	runner runner
+	routes []route
+	// operations are run one at a time, as the CLI state is not safe for concurrent use
+	lock sync.Mutex
+}
+
+func newServer(token string, runner runner) *server {
+	s := &server{token: token, runner: runner}
+	s.routes = []route{
+		{http.MethodGet, []string{"v1", "subnets"}, s.listSubnets},
+		{http.MethodPost, []string{"v1", "subnets"}, s.createSubnet},
+		{http.MethodGet, []string{"v1", "subnets", "{name}"}, s.describeSubnet},
+		{http.MethodPost, []string{"v1", "subnets", "{name}", "deploy"}, s.deploySubnet},
+		{http.MethodPost, []string{"v1", "subnets", "{name}", "validators"}, s.addValidator},
+		{http.MethodGet, []string{"v1", "network", "status"}, s.networkStatus},
+		{http.MethodPost, []string{"v1", "network", "start"}, s.startNetwork},
+		{http.MethodPost, []string{"v1", "network", "stop"}, s.stopNetwork},
+		{http.MethodPost, []string{"v1", "network", "clean"}, s.cleanNetwork},
+	}
+	return s
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, ux.ErrCodeInvalidArguments, "missing or invalid API token")
+		return
+	}
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	pathFound := false
+	for _, rt := range s.routes {
+		params, ok := matchPath(rt.path, segments)
+		if !ok {
+			continue
+		}
+		pathFound = true
+		if rt.method == r.Method {
+			rt.handler(w, r, params)
+			return
+		}
+	}
+	if pathFound {
+		writeError(w, http.StatusMethodNotAllowed, ux.ErrCodeInvalidArguments, fmt.Sprintf("method %s not allowed", r.Method))
+		return
+	}
+	writeError(w, http.StatusNotFound, ux.ErrCodeNotFound, fmt.Sprintf("path %s not found", r.URL.Path))
+}
+
+func (s *server) authorized(r *http.Request) bool {
+	reqToken, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(reqToken), []byte(s.token)) == 1
+}
+
+// matchPath returns the captured params if segments match path
+func matchPath(path []string, segments []string) (map[string]string, bool) {
+	if len(path) != len(segments) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, p := range path {
+		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+			params[strings.Trim(p, "{}")] = segments[i]
+			continue
+		}
+		if p != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func (s *server) listSubnets(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	s.runOperation(w, r, []string{"subnet", "list"})
+}
+
+func (s *server) describeSubnet(w http.ResponseWriter, _ *http.Request, params map[string]string) {
+	name := params["name"]
+	if err := validateName("subnet", name); err != nil {
+		writeError(w, http.StatusBadRequest, ux.ErrCodeInvalidArguments, err.Error())
+		return
+	}
+	if !app.SidecarExists(name) {
+		writeError(w, http.StatusNotFound, ux.ErrCodeNotFound, fmt.Sprintf("subnet %s not found", name))
+		return
+	}
+	sc, err := app.LoadSidecar(name)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, ux.ErrCodeGeneric, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, sc)
+}
+
+func (s *server) createSubnet(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	req := createSubnetRequest{}
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	args, err := createSubnetArgs(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ux.ErrCodeInvalidArguments, err.Error())
+		return
+	}
+	s.runOperation(w, r, args)
+}
+
+func (s *server) deploySubnet(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	req := deployRequest{}
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	args, err := deployArgs(params["name"], req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ux.ErrCodeInvalidArguments, err.Error())
+		return
+	}
+	s.runOperation(w, r, args)
+}
+
+func (s *server) addValidator(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	req := addValidatorRequest{}
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	args, err := addValidatorArgs(params["name"], req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ux.ErrCodeInvalidArguments, err.Error())
+		return
+	}
+	s.runOperation(w, r, args)
+}
+
+func (s *server) networkStatus(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	s.runOperation(w, r, []string{"network", "status"})
+}
+
+func (s *server) startNetwork(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	req := startNetworkRequest{}
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	args := []string{"network", "start"}
+	if req.SnapshotName != "" {
+		if err := validateName("snapshot", req.SnapshotName); err != nil {
+			writeError(w, http.StatusBadRequest, ux.ErrCodeInvalidArguments, err.Error())
+			return
+		}
+		args = append(args, "--snapshot-name", req.SnapshotName)
+	}
+	if req.NumNodes > 0 {
+		args = append(args, "--num-nodes", strconv.FormatUint(uint64(req.NumNodes), 10))
+	}
+	s.runOperation(w, r, args)
+}
+
+func (s *server) stopNetwork(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	req := stopNetworkRequest{}
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	args := []string{"network", "stop"}
+	if req.SnapshotName != "" {
+		if err := validateName("snapshot", req.SnapshotName); err != nil {
+			writeError(w, http.StatusBadRequest, ux.ErrCodeInvalidArguments, err.Error())
+			return
+		}
+		args = append(args, "--snapshot-name", req.SnapshotName)
+	}
+	s.runOperation(w, r, args)
+}
+
+func (s *server) cleanNetwork(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	req := cleanNetworkRequest{}
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	args := []string{"network", "clean"}
+	if req.Hard {
+		args = append(args, "--hard")
+	}
+	s.runOperation(w, r, args)
+}
+
+// runOperation runs the CLI command given by args, and writes its outcome
+func (s *server) runOperation(w http.ResponseWriter, r *http.Request, args []string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	exitCode, stdout, stderr, err := s.runner.Run(r.Context(), args)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, ux.ErrCodeGeneric, fmt.Sprintf("failed running operation: %s", err))
+		return
+	}
+	resp := operationResponse{
+		Args:     args,
+		ExitCode: exitCode,
+		Log:      string(stderr),
+	}
+	status := http.StatusOK
+	stdout = bytes.TrimSpace(stdout)
+	if exitCode != 0 {
+		// on JSON mode, failed commands print an error object
+		errObj := struct {
+			Error *apiError `json:"error"`
+		}{}
+		if err := json.Unmarshal(stdout, &errObj); err != nil || errObj.Error == nil {
+			errObj.Error = &apiError{Code: ux.ErrCodeGeneric, Message: fmt.Sprintf("operation failed with exit code %d", exitCode)}
+		}
+		resp.Error = errObj.Error
+		status = statusForErrorCode(resp.Error.Code)
+	} else if len(stdout) > 0 {
+		if json.Valid(stdout) {
+			resp.Result = stdout
+		} else {
+			// commands without JSON support print their results as text
+			resp.Result, _ = json.Marshal(string(stdout))
+		}
+	}
+	writeJSON(w, status, resp)
+}
+
+func statusForErrorCode(code ux.ErrorCode) int {
+	switch code {
+	case ux.ErrCodeInvalidArguments:
+		return http.StatusBadRequest
+	case ux.ErrCodeNotFound:
+		return http.StatusNotFound
+	case ux.ErrCodeNetworkNotRunning, ux.ErrCodeNetworkUnhealthy, ux.ErrCodeIncompatibleVersions:
+		return http.StatusConflict
+	case ux.ErrCodeTimeout:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func createSubnetArgs(req createSubnetRequest) ([]string, error) {
+	if err := validateName("subnet", req.Name); err != nil {
+		return nil, err
+	}
+	if req.ChainID == 0 {
+		return nil, fmt.Errorf("chainID is required")
+	}
+	if !nameRegex.MatchString(req.TokenSymbol) {
+		return nil, fmt.Errorf("invalid tokenSymbol %q", req.TokenSymbol)
+	}
+	args := []string{
+		"subnet", "create", req.Name,
+		"--evm",
+		"--evm-defaults",
+		"--evm-chain-id", strconv.FormatUint(req.ChainID, 10),
+		"--evm-token", req.TokenSymbol,
+	}
+	if req.VMVersion != "" {
+		args = append(args, "--vm-version="+req.VMVersion)
+	} else {
+		args = append(args, "--latest")
+	}
+	if req.Force {
+		args = append(args, "--force")
+	}
+	return args, nil
+}
+
+func deployArgs(subnetName string, req deployRequest) ([]string, error) {
+	if err := validateName("subnet", subnetName); err != nil {
+		return nil, err
+	}
+	networkFlag, err := getNetworkFlag(req.Network)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"subnet", "deploy", subnetName, networkFlag}
+	keyArgs, err := getKeyArgs(req.Key, req.Ewoq)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, keyArgs...)
+	if req.SameControlKey {
+		args = append(args, "--same-control-key")
+	}
+	if len(req.ControlKeys) > 0 {
+		args = append(args, "--control-keys="+strings.Join(req.ControlKeys, ","))
+	}
+	if req.Threshold > 0 {
+		args = append(args, "--threshold", strconv.FormatUint(uint64(req.Threshold), 10))
+	}
+	return append(args, getMultisigArgs(req.SubnetAuthKeys, req.OutputTxPath)...), nil
+}
+
+func addValidatorArgs(subnetName string, req addValidatorRequest) ([]string, error) {
+	if err := validateName("subnet", subnetName); err != nil {
+		return nil, err
+	}
+	networkFlag, err := getNetworkFlag(req.Network)
+	if err != nil {
+		return nil, err
+	}
+	nodeID, err := ids.NodeIDFromString(req.NodeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nodeID %q: %w", req.NodeID, err)
+	}
+	if req.Weight == 0 {
+		return nil, fmt.Errorf("weight is required")
+	}
+	args := []string{
+		"subnet", "addValidator", subnetName, networkFlag,
+		"--nodeID", nodeID.String(),
+		"--weight", strconv.FormatUint(req.Weight, 10),
+	}
+	keyArgs, err := getKeyArgs(req.Key, req.Ewoq)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, keyArgs...)
+	if req.StartTime != "" {
+		args = append(args, "--start-time="+req.StartTime)
+	} else {
+		args = append(args, "--default-start-time")
+	}
+	if req.StakingPeriod != "" {
+		if _, err := time.ParseDuration(req.StakingPeriod); err != nil {
+			return nil, fmt.Errorf("invalid stakingPeriod %q: %w", req.StakingPeriod, err)
+		}
+		args = append(args, "--staking-period="+req.StakingPeriod)
+	} else {
+		args = append(args, "--default-duration")
+	}
+	return append(args, getMultisigArgs(req.SubnetAuthKeys, req.OutputTxPath)...), nil
+}
+
+func getNetworkFlag(network string) (string, error) {
+	flag, ok := networkFlags[strings.ToLower(network)]
+	if !ok {
+		return "", fmt.Errorf("invalid network %q, expected one of local, devnet, tahoe or mainnet", network)
+	}
+	return flag, nil
+}
+
+func getKeyArgs(key string, ewoq bool) ([]string, error) {
+	switch {
+	case key != "" && ewoq:
+		return nil, fmt.Errorf("key and ewoq are mutually exclusive")
+	case key != "":
+		if err := validateName("key", key); err != nil {
+			return nil, err
+		}
+		return []string{"--key", key}, nil
+	case ewoq:
+		return []string{"--ewoq"}, nil
+	}
+	return nil, nil
+}
+
+// getMultisigArgs returns the args of the subnet auth keys and tx output path. Free
+// form values are given inline, so they can't be taken as flags
+func getMultisigArgs(subnetAuthKeys []string, outputTxPath string) []string {
+	args := []string{}
+	if len(subnetAuthKeys) > 0 {
+		args = append(args, "--subnet-auth-keys="+strings.Join(subnetAuthKeys, ","))
+	}
+	if outputTxPath != "" {
+		args = append(args, "--output-tx-path="+outputTxPath)
+	}
+	return args
+}
+
+func validateName(kind string, name string) error {
+	if !nameRegex.MatchString(name) {
+		return fmt.Errorf("invalid %s name %q", kind, name)
+	}
+	return nil
+}
+
+// decodeRequest decodes the JSON body of r into req, writing an error response
+// and returning false if it is invalid. Empty bodies are accepted
+func decodeRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, ux.ErrCodeInvalidArguments, fmt.Sprintf("invalid request body: %s", err))
+		return false
+	}
+	return true
+}
+
+func writeError(w http.ResponseWriter, status int, code ux.ErrorCode, message string) {
+	writeJSON(w, status, struct {
+		Error apiError `json:"error"`
+	}{Error: apiError{Code: code, Message: message}})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
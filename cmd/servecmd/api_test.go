@@ -0,0 +1,118 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package servecmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "secret"
+
+type fakeRunner struct {
+	args     []string
+	exitCode int
+	stdout   string
+}
+
+func (f *fakeRunner) Run(_ context.Context, args []string) (int, []byte, []byte, error) {
+	f.args = args
+	return f.exitCode, []byte(f.stdout), []byte("some log"), nil
+}
+
+func doRequest(s *server, method string, path string, body string, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServerAuth(t *testing.T) {
+	require := require.New(t)
+	runner := &fakeRunner{}
+	s := newServer(testToken, runner)
+
+	rec := doRequest(s, http.MethodGet, "/v1/network/status", "", "")
+	require.Equal(http.StatusUnauthorized, rec.Code)
+	rec = doRequest(s, http.MethodGet, "/v1/network/status", "", "wrong")
+	require.Equal(http.StatusUnauthorized, rec.Code)
+	require.Nil(runner.args)
+
+	rec = doRequest(s, http.MethodGet, "/v1/network/status", "", testToken)
+	require.Equal(http.StatusOK, rec.Code)
+	require.Equal([]string{"network", "status"}, runner.args)
+}
+
+func TestServerRouting(t *testing.T) {
+	require := require.New(t)
+	runner := &fakeRunner{stdout: `{"running":true}`}
+	s := newServer(testToken, runner)
+
+	rec := doRequest(s, http.MethodPost, "/v1/subnets/mySubnet/deploy", `{"network":"local"}`, testToken)
+	require.Equal(http.StatusOK, rec.Code)
+	require.Equal([]string{"subnet", "deploy", "mySubnet", "--local"}, runner.args)
+	resp := operationResponse{}
+	require.NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.JSONEq(`{"running":true}`, string(resp.Result))
+	require.Equal("some log", resp.Log)
+
+	rec = doRequest(s, http.MethodPost, "/v1/network/clean", `{"hard":true}`, testToken)
+	require.Equal(http.StatusOK, rec.Code)
+	require.Equal([]string{"network", "clean", "--hard"}, runner.args)
+
+	rec = doRequest(s, http.MethodGet, "/v1/network/start", "", testToken)
+	require.Equal(http.StatusMethodNotAllowed, rec.Code)
+	rec = doRequest(s, http.MethodGet, "/v1/unknown", "", testToken)
+	require.Equal(http.StatusNotFound, rec.Code)
+	rec = doRequest(s, http.MethodPost, "/v1/network/start", `{"unknown":1}`, testToken)
+	require.Equal(http.StatusBadRequest, rec.Code)
+	rec = doRequest(s, http.MethodPost, "/v1/subnets/--force/deploy", `{"network":"local"}`, testToken)
+	require.Equal(http.StatusBadRequest, rec.Code)
+
+	// failed operations return the error printed by the CLI
+	runner.exitCode = 1
+	runner.stdout = `{"error":{"code":"NETWORK_NOT_RUNNING","message":"network is not running"}}`
+	rec = doRequest(s, http.MethodPost, "/v1/network/stop", "", testToken)
+	require.Equal(http.StatusConflict, rec.Code)
+	resp = operationResponse{}
+	require.NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(1, resp.ExitCode)
+	require.Equal(ux.ErrCodeNetworkNotRunning, resp.Error.Code)
+}
+
+func TestOperationArgs(t *testing.T) {
+	require := require.New(t)
+
+	args, err := createSubnetArgs(createSubnetRequest{Name: "mySubnet", ChainID: 123, TokenSymbol: "TEST"})
+	require.NoError(err)
+	require.Equal([]string{"subnet", "create", "mySubnet", "--evm", "--evm-defaults", "--evm-chain-id", "123", "--evm-token", "TEST", "--latest"}, args)
+	_, err = createSubnetArgs(createSubnetRequest{Name: "mySubnet", TokenSymbol: "TEST"})
+	require.ErrorContains(err, "chainID is required")
+
+	args, err = deployArgs("mySubnet", deployRequest{Network: "tahoe", Key: "myKey", ControlKeys: []string{"P-a", "P-b"}, Threshold: 2})
+	require.NoError(err)
+	require.Equal([]string{"subnet", "deploy", "mySubnet", "--tahoe", "--key", "myKey", "--control-keys=P-a,P-b", "--threshold", "2"}, args)
+	_, err = deployArgs("mySubnet", deployRequest{Network: "fuji"})
+	require.ErrorContains(err, "invalid network")
+	_, err = deployArgs("mySubnet", deployRequest{Network: "local", Key: "myKey", Ewoq: true})
+	require.ErrorContains(err, "mutually exclusive")
+
+	nodeID := "NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg"
+	args, err = addValidatorArgs("mySubnet", addValidatorRequest{Network: "local", Ewoq: true, NodeID: nodeID, Weight: 20, StakingPeriod: "24h"})
+	require.NoError(err)
+	require.Equal([]string{"subnet", "addValidator", "mySubnet", "--local", "--nodeID", nodeID, "--weight", "20", "--ewoq", "--default-start-time", "--staking-period=24h"}, args)
+	_, err = addValidatorArgs("mySubnet", addValidatorRequest{Network: "local", NodeID: "invalid", Weight: 20})
+	require.ErrorContains(err, "invalid nodeID")
+	_, err = addValidatorArgs("mySubnet", addValidatorRequest{Network: "local", NodeID: nodeID, Weight: 20, StakingPeriod: "a week"})
+	require.ErrorContains(err, "invalid stakingPeriod")
+}
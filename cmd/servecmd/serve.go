@@ -0,0 +1,141 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package servecmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+var (
+	app   *application.Avalanche
+	host  string
+	port  uint16
+	token string
+)
+
+// metal serve
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the CLI operations over an authenticated REST API",
+		Long: `The serve command starts a local HTTP server that exposes the subnet lifecycle
+operations (create, deploy, addValidator) and the local network control
+operations (start, stop, clean, status) as a REST API, so dashboards and
+orchestration systems can drive them without shelling out to the binary.
+
+Every request must carry an 'Authorization: Bearer <token>' header. The token
+is given with the METAL_TOKEN env var, or with --token, which is visible to
+other users in the process list. If none is given, a random one is generated,
+printed, and saved with user only permissions into the CLI run dir, for local
+clients to read it.
+
+Operations are run one at a time, in non interactive mode, so requests must
+include all the required parameters. Each response contains the JSON output of
+the operation. Endpoints:
+
+  GET  /v1/subnets                    list subnet configurations
+  GET  /v1/subnets/{name}             describe a subnet configuration
+  POST /v1/subnets                    create a Subnet-EVM subnet configuration
+  POST /v1/subnets/{name}/deploy      deploy a subnet
+  POST /v1/subnets/{name}/validators  add a validator to a deployed subnet
+  GET  /v1/network/status             local network status
+  POST /v1/network/start              start the local network
+  POST /v1/network/stop               stop the local network
+  POST /v1/network/clean              clean the local network
+
+The server listens on 127.0.0.1 by default. Listening on other interfaces
+exposes the operations, and the keys they use, to the network.`,
+		RunE:         serve,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&host, "host", constants.ServeDefaultHost, "address to listen on")
+	cmd.Flags().Uint16Var(&port, "port", constants.ServeDefaultPort, "port to listen on")
+	cmd.Flags().StringVar(&token, "token", "", "token required from clients, instead of the "+constants.ServeTokenEnvVarName+" env var (default is a random one)")
+	return cmd
+}
+
+func serve(*cobra.Command, []string) error {
+	if token == "" {
+		token = os.Getenv(constants.ServeTokenEnvVarName)
+	}
+	if token == "" {
+		var err error
+		token, err = generateToken()
+		if err != nil {
+			return err
+		}
+		// kept out of the CLI log file
+		fmt.Printf("Generated API token: %s\n", token)
+	}
+	tokenPath := app.GetServeTokenPath()
+	if err := os.MkdirAll(filepath.Dir(tokenPath), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(tokenPath, []byte(token), constants.WriteReadUserOnlyPerms); err != nil {
+		return fmt.Errorf("failed saving API token: %w", err)
+	}
+	defer os.Remove(tokenPath)
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed getting the CLI executable path: %w", err)
+	}
+	handler := newServer(token, &cliRunner{
+		executable: executable,
+		configFile: app.Conf.GetConfigPath(),
+	})
+	server := &http.Server{
+		Addr:              net.JoinHostPort(host, strconv.Itoa(int(port))),
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+	ux.Logger.PrintToUser("Serving API on http://%s (token saved at %s)", server.Addr, tokenPath)
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	ux.Logger.PrintToUser("Shutting down API server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func generateToken() (string, error) {
+	bs := make([]byte, 32)
+	if _, err := rand.Read(bs); err != nil {
+		return "", fmt.Errorf("failed generating API token: %w", err)
+	}
+	return hex.EncodeToString(bs), nil
+}
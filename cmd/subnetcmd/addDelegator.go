@@ -8,9 +8,11 @@ import (
 	"os"
 	"time"
 
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
 	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
 	"github.com/MetalBlockchain/metalgo/genesis"
 	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm"
 	"github.com/MetalBlockchain/metalgo/vms/secp256k1fx"
 
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
@@ -22,7 +24,7 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var addPermissionlessDelegatorSupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Tahoe, networkoptions.Mainnet}
+var addPermissionlessDelegatorSupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Tahoe, networkoptions.Mainnet, networkoptions.Environment}
 
 // avalanche subnet addPermissionlessDelegator
 func newAddPermissionlessDelegatorCmd() *cobra.Command {
@@ -43,21 +45,25 @@ validates the Primary Network.
 
 To add a node as a delegator, you first need to provide
 the subnetID and the validator's unique NodeID. The command then prompts
-for the validation start time, duration, and stake weight. You can bypass
-these prompts by providing the values with flags.`,
+for the validation start time, duration, stake weight and the address
+receiving the delegation rewards. You can bypass these prompts by providing
+the values with flags.`,
 		SilenceUsage: true,
-		RunE:         addPermissionlessDelegator,
-		Args:         cobra.ExactArgs(1),
+		RunE:         withActiveSubnet(addPermissionlessDelegator),
+		Args:         cobra.MaximumNArgs(1),
 	}
 
 	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, addPermissionlessDelegatorSupportedNetworkOptions)
 	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use [fuji deploy only]")
 	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on fuji)")
 	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	flags.AddSignerFlag(cmd, &signerSpec)
 	cmd.Flags().StringVar(&nodeIDStr, "nodeID", "", "set the NodeID of the validator to delegate to")
 	cmd.Flags().Uint64Var(&stakeAmount, "stake-amount", 0, "amount of tokens to stake")
-	cmd.Flags().StringVar(&startTimeStr, "start-time", "", "start time that delegator starts delegating")
+	cmd.Flags().StringVar(&startTimeStr, "start-time", "", "start time that delegator starts delegating, in 'YYYY-MM-DD HH:MM:SS' format (UTC unless --timezone is given), or RFC3339 format with offset")
+	cmd.Flags().StringVar(&startTimeZone, "timezone", "", "time zone of --start-time, and of the start time prompt (ex: Europe/Paris, local) (default UTC)")
 	cmd.Flags().DurationVar(&duration, "staking-period", 0, "how long delegator should delegate for after start time")
+	cmd.Flags().StringVar(&rewardAddressStr, "reward-address", "", "P-Chain address receiving the delegation rewards (defaults to the paying key address)")
 
 	return cmd
 }
@@ -94,12 +100,12 @@ func addPermissionlessDelegator(_ *cobra.Command, args []string) error {
 		useLedger = true
 	}
 
-	if useLedger && keyName != "" {
+	if !flags.EnsureMutuallyExclusive([]bool{useLedger, keyName != "", signerSpec != ""}) {
 		return ErrMutuallyExlusiveKeyLedger
 	}
 	subnetID := sc.Networks[network.Name()].SubnetID
 	if os.Getenv(constants.SimulatePublicNetwork) != "" {
-		subnetID = sc.Networks[models.Local.String()].SubnetID
+		subnetID = sc.Networks[models.NewLocalNetwork().Name()].SubnetID
 	}
 	if subnetID == ids.Empty {
 		return errNoSubnetID
@@ -122,8 +128,9 @@ func addPermissionlessDelegator(_ *cobra.Command, args []string) error {
 	switch network.Kind {
 	case models.Local:
 		return handleAddPermissionlessDelegatorLocal(subnetName, network, nodeID, stakedTokenAmount, start, endTime)
-	case models.Tahoe:
-		if !useLedger && keyName == "" {
+	case models.Tahoe, models.Devnet:
+		// environments of custom networks are devnets
+		if !useLedger && keyName == "" && signerSpec == "" {
 			useLedger, keyName, err = prompts.GetFujiKeyOrLedger(app.Prompt, constants.PayTxsFeesMsg, app.GetKeyDir())
 			if err != nil {
 				return err
@@ -135,19 +142,26 @@ func addPermissionlessDelegator(_ *cobra.Command, args []string) error {
 
 	// get keychain accessor
 	fee := network.GenesisParams().AddSubnetDelegatorFee
-	kc, err := keychain.GetKeychain(app, false, useLedger, ledgerAddresses, keyName, network, fee)
+	kc, err := keychain.GetKeychain(app, models.KeyRolePayer, false, useLedger, ledgerAddresses, signerSpec, keyName, network, fee)
 	if err != nil {
 		return err
 	}
 
 	network.HandlePublicNetworkSimulation()
 
-	recipientAddr := kc.Addresses().List()[0]
+	recipientAddr, err := promptRewardAddress(kc.Addresses().List()[0], network)
+	if err != nil {
+		return err
+	}
 	deployer := subnet.NewPublicDeployer(app, kc, network)
 	assetID, err := getSubnetAssetID(subnetID, network)
 	if err != nil {
 		return err
 	}
+	pClient := platformvm.NewClient(network.Endpoint)
+	if err := checkStakeableAssetBalance(pClient, kc.Addresses().List(), assetID, stakedTokenAmount); err != nil {
+		return err
+	}
 	txID, err := deployer.AddPermissionlessDelegator(subnetID, assetID, nodeID, stakedTokenAmount, uint64(start.Unix()), uint64(endTime.Unix()), recipientAddr)
 	if err != nil {
 		return err
@@ -161,8 +175,8 @@ func printAddPermissionlessDelOutput(txID ids.ID, nodeID ids.NodeID, network mod
 	ux.Logger.PrintToUser("TX ID: %s", txID.String())
 	ux.Logger.PrintToUser("NodeID: %s", nodeID.String())
 	ux.Logger.PrintToUser("Network: %s", network.Name())
-	ux.Logger.PrintToUser("Start time: %s", start.UTC().Format(constants.TimeParseLayout))
-	ux.Logger.PrintToUser("End time: %s", endTime.Format(constants.TimeParseLayout))
+	ux.Logger.PrintToUser("Start time: %s", formatTime(start))
+	ux.Logger.PrintToUser("End time: %s", formatTime(endTime))
 	ux.Logger.PrintToUser("Stake Amount: %d", stakedTokenAmount)
 }
 
@@ -183,7 +197,15 @@ func handleAddPermissionlessDelegatorLocal(subnetName string, network models.Net
 	testKey := genesis.EWOQKey
 	keyChain := secp256k1fx.NewKeychain(testKey)
 	subnetID := sc.Networks[network.Name()].SubnetID
-	txID, err := subnet.IssueAddPermissionlessDelegatorTx(keyChain, subnetID, nodeID, stakedTokenAmount, assetID, uint64(start.Unix()), uint64(endTime.Unix()))
+	rewardAddr, err := promptRewardAddress(testKey.PublicKey().Address(), network)
+	if err != nil {
+		return err
+	}
+	pClient := platformvm.NewClient(models.LocalAPIEndpoint())
+	if err := checkStakeableAssetBalance(pClient, keyChain.Addresses().List(), assetID, stakedTokenAmount); err != nil {
+		return err
+	}
+	txID, err := subnet.IssueAddPermissionlessDelegatorTx(keyChain, subnetID, nodeID, stakedTokenAmount, assetID, uint64(start.Unix()), uint64(endTime.Unix()), rewardAddr)
 	if err != nil {
 		return err
 	}
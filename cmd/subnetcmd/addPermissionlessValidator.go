@@ -0,0 +1,65 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
+	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
+	"github.com/spf13/cobra"
+)
+
+// avalanche subnet addPermissionlessValidator
+func newAddPermissionlessValidatorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "addPermissionlessValidator [subnetName]",
+		Short: "Add a validator to an elastic subnet by staking the subnet token",
+		Long: `The subnet addPermissionlessValidator command adds a node as a validator of an
+elastic subnet, staking the subnet native token through an AddPermissionlessValidatorTx.
+
+The paying key must hold enough stakeable (unlocked or locked stakeable) subnet
+tokens to cover the stake amount. Validation rewards are sent to the address given
+by --reward-address.
+
+The command prompts for the NodeID, stake amount, start time, staking period and
+reward address.
+You can bypass these prompts by providing the values with flags.`,
+		SilenceUsage: true,
+		RunE:         withActiveSubnet(addPermissionlessValidator),
+		Args:         cobra.MaximumNArgs(1),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, joinElasticSupportedNetworkOptions)
+	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use [tahoe only]")
+	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on tahoe)")
+	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	flags.AddSignerFlag(cmd, &signerSpec)
+	cmd.Flags().StringVar(&nodeIDStr, "nodeID", "", "set the NodeID of the validator to add")
+	cmd.Flags().Uint64Var(&stakeAmount, "stake-amount", 0, "amount of subnet tokens to stake")
+	cmd.Flags().StringVar(&startTimeStr, "start-time", "", "start time that validator starts validating, in 'YYYY-MM-DD HH:MM:SS' format (UTC unless --timezone is given), or RFC3339 format with offset")
+	cmd.Flags().StringVar(&startTimeZone, "timezone", "", "time zone of --start-time, and of the start time prompt (ex: Europe/Paris, local) (default UTC)")
+	cmd.Flags().DurationVar(&duration, "staking-period", 0, "how long validator validates for after start time")
+	cmd.Flags().StringVar(&rewardAddressStr, "reward-address", "", "P-Chain address receiving the validation rewards (defaults to the paying key address)")
+	return cmd
+}
+
+func addPermissionlessValidator(_ *cobra.Command, args []string) error {
+	chains, err := ValidateSubnetNameAndGetChains(args)
+	if err != nil {
+		return err
+	}
+	subnetName := chains[0]
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		globalNetworkFlags,
+		false,
+		joinElasticSupportedNetworkOptions,
+		subnetName,
+	)
+	if err != nil {
+		return err
+	}
+	return handleValidatorJoinElasticSubnet(sc, network, subnetName)
+}
@@ -5,12 +5,16 @@ package subnetcmd
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
+	"github.com/MetalBlockchain/metal-cli/pkg/apicache"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
 	"github.com/MetalBlockchain/metal-cli/pkg/keychain"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
 	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
+	"github.com/MetalBlockchain/metal-cli/pkg/node"
 	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
 	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
 	"github.com/MetalBlockchain/metal-cli/pkg/txutils"
@@ -22,23 +26,32 @@ import (
 	"github.com/spf13/cobra"
 )
 
+const (
+	customOption          = "Custom"
+	defaultDurationOption = "Until primary network validator expires"
+)
+
 var (
-	addValidatorSupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Devnet, networkoptions.Tahoe, networkoptions.Mainnet}
+	addValidatorSupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Devnet, networkoptions.Environment, networkoptions.Tahoe, networkoptions.Mainnet}
 
 	nodeIDStr              string
+	fromNode               string
 	weight                 uint64
 	startTimeStr           string
+	startTimeZone          string
 	duration               time.Duration
 	defaultValidatorParams bool
 	useDefaultStartTime    bool
 	useDefaultDuration     bool
 	useDefaultWeight       bool
 	justIssueTx            bool
+	maxWeightShare         int
 
 	errNoSubnetID                       = errors.New("failed to find the subnet ID for this subnet, has it been deployed/created on this network?")
 	errMutuallyExclusiveDurationOptions = errors.New("--use-default-duration/--use-default-validator-params and --staking-period are mutually exclusive")
 	errMutuallyExclusiveStartOptions    = errors.New("--use-default-start-time/--use-default-validator-params and --start-time are mutually exclusive")
 	errMutuallyExclusiveWeightOptions   = errors.New("--use-default-validator-params and --weight are mutually exclusive")
+	errMutuallyExclusiveNodeIDOptions   = errors.New("--nodeID and --from-node are mutually exclusive")
 )
 
 // avalanche subnet addValidator
@@ -52,22 +65,29 @@ validate the provided deployed Subnet.
 To add the validator to the Subnet's allow list, you first need to provide
 the subnetName and the validator's unique NodeID. The command then prompts
 for the validation start time, duration, and stake weight. You can bypass
-these prompts by providing the values with flags.
+these prompts by providing the values with flags. The command warns if the
+new validator would hold more than --max-weight-share percent of the subnet
+total stake weight, to avoid centralizing the subnet by accident. The --start-time flag
+accepts either an absolute UTC time ('YYYY-MM-DD HH:MM:SS') or a duration
+relative to now, such as 10m or 'in 2h'.
 
 This command currently only works on Subnets deployed to either the Tahoe
 Testnet or Mainnet.`,
 		SilenceUsage: true,
-		RunE:         addValidator,
-		Args:         cobra.ExactArgs(1),
+		RunE:         withAnswers(withActiveSubnet(addValidator)),
+		Args:         cobra.MaximumNArgs(1),
 	}
 	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, addValidatorSupportedNetworkOptions)
 
 	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use [tahoe/devnet only]")
 	cmd.Flags().StringVar(&nodeIDStr, "nodeID", "", "set the NodeID of the validator to add")
+	cmd.Flags().StringVar(&fromNode, "from-node", "", "fetch the NodeID of the validator to add from the info API of the node at this IP or URL")
 	cmd.Flags().Uint64Var(&weight, "weight", 0, "set the staking weight of the validator to add")
+	cmd.Flags().IntVar(&maxWeightShare, "max-weight-share", 0, "warn if the validator would hold more than this percentage of the subnet total weight (default as set with 'config max-weight-share', or 33)")
 
 	cmd.Flags().BoolVar(&useDefaultStartTime, "default-start-time", false, "use default start time for subnet validator (5 minutes later for tahoe & mainnet, 30 seconds later for devnet)")
-	cmd.Flags().StringVar(&startTimeStr, "start-time", "", "UTC start time when this validator starts validating, in 'YYYY-MM-DD HH:MM:SS' format")
+	cmd.Flags().StringVar(&startTimeStr, "start-time", "", "start time when this validator starts validating, in 'YYYY-MM-DD HH:MM:SS' format (UTC unless --timezone is given), RFC3339 format with offset, or relative to now (ex: 10m, 'in 2h')")
+	cmd.Flags().StringVar(&startTimeZone, "timezone", "", "time zone of --start-time, and of the start time prompt (ex: Europe/Paris, local) (default UTC)")
 
 	cmd.Flags().BoolVar(&useDefaultDuration, "default-duration", false, "set duration so as to validate until primary validator ends its period")
 	cmd.Flags().DurationVar(&duration, "staking-period", 0, "how long this validator will be staking")
@@ -79,12 +99,22 @@ Testnet or Mainnet.`,
 	cmd.Flags().BoolVarP(&useEwoq, "ewoq", "e", false, "use ewoq key [tahoe/devnet only]")
 	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on tahoe/devnet)")
 	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	flags.AddSignerFlag(cmd, &signerSpec)
 	cmd.Flags().BoolVar(&justIssueTx, "just-issue-tx", false, "just issue the add validator tx, without waiting for its acceptance")
+	addAnswersFlag(cmd)
 	return cmd
 }
 
 func addValidator(_ *cobra.Command, args []string) error {
 	subnetName := args[0]
+	if fromNode != "" && nodeIDStr != "" {
+		return errMutuallyExclusiveNodeIDOptions
+	}
+	if maxWeightShare != 0 {
+		if err := utils.ValidatePercentage(maxWeightShare); err != nil {
+			return fmt.Errorf("invalid --max-weight-share %d: %w", maxWeightShare, err)
+		}
+	}
 	network, err := networkoptions.GetNetworkFromCmdLineFlags(
 		app,
 		globalNetworkFlags,
@@ -95,15 +125,24 @@ func addValidator(_ *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if fromNode != "" {
+		nodeID, err := GetNodeIDFromNode(fromNode, network)
+		if err != nil {
+			return err
+		}
+		nodeIDStr = nodeID.String()
+	}
 	fee := network.GenesisParams().AddSubnetValidatorFee
 	kc, err := keychain.GetKeychainFromCmdLineFlags(
 		app,
 		constants.PayTxsFeesMsg,
+		models.KeyRoleValidatorManager,
 		network,
 		keyName,
 		useEwoq,
 		useLedger,
 		ledgerAddresses,
+		signerSpec,
 		fee,
 	)
 	if err != nil {
@@ -198,24 +237,39 @@ func CallAddValidator(
 	}
 	ux.Logger.PrintToUser("Your subnet auth keys for add validator tx creation: %s", subnetAuthKeys)
 
-	if nodeIDStr == "" {
-		nodeID, err = PromptNodeID()
+	if nodeIDStr != "" {
+		nodeID, err = ids.NodeIDFromString(nodeIDStr)
 		if err != nil {
 			return err
 		}
-	} else {
-		nodeID, err = ids.NodeIDFromString(nodeIDStr)
-		if err != nil {
+		if err := checkNodeIDCanValidateSubnet(network, subnetID, nodeID); err != nil {
 			return err
 		}
 	}
 
-	selectedWeight, err := getWeight()
+	// weight share is checked by the wizard when the weight is prompted for
+	checkWeightShare := weight != 0 || useDefaultWeight
+
+	limits, err := subnet.GetStakeWeightLimits(app, network, subnetID)
+	if err != nil {
+		return err
+	}
+
+	// values not given by flags are asked with a wizard, so the user can go back
+	// and review them before issuing the tx
+	if err := prompts.RunWizard(app.Prompt, getAddValidatorWizardSteps(network, subnetID, limits, &nodeID)); err != nil {
+		return err
+	}
+
+	selectedWeight, err := getWeight(limits)
 	if err != nil {
 		return err
 	}
-	if selectedWeight < constants.MinStakeWeight {
-		return fmt.Errorf("illegal weight, must be greater than or equal to %d: %d", constants.MinStakeWeight, selectedWeight)
+	if err := limits.Check(selectedWeight); err != nil {
+		return err
+	}
+	if checkWeightShare {
+		checkValidatorWeightShare(network, subnetID, ids.EmptyNodeID, selectedWeight)
 	}
 
 	start, selectedDuration, err := getTimeParameters(network, nodeID, true)
@@ -225,8 +279,8 @@ func CallAddValidator(
 
 	ux.Logger.PrintToUser("NodeID: %s", nodeID.String())
 	ux.Logger.PrintToUser("Network: %s", network.Name())
-	ux.Logger.PrintToUser("Start time: %s", start.Format(constants.TimeParseLayout))
-	ux.Logger.PrintToUser("End time: %s", start.Add(selectedDuration).Format(constants.TimeParseLayout))
+	ux.Logger.PrintToUser("Start time: %s", formatTime(start))
+	ux.Logger.PrintToUser("End time: %s", formatTime(start.Add(selectedDuration)))
 	ux.Logger.PrintToUser("Weight: %d", selectedWeight)
 	ux.Logger.PrintToUser("Inputs complete, issuing transaction to add the provided validator information...")
 
@@ -244,6 +298,9 @@ func CallAddValidator(
 	if err != nil {
 		return err
 	}
+	if err := app.UpdateSidecarTxHistory(&sc, network, deployer.IssuedTxs()); err != nil {
+		return err
+	}
 	if !isFullySigned {
 		if err := SaveNotFullySignedTx(
 			"Add Validator",
@@ -253,6 +310,11 @@ func CallAddValidator(
 			remainingSubnetAuthKeys,
 			outputTxPath,
 			false,
+			&txutils.OfflineSigningInfo{
+				SubnetID:    subnetID,
+				ControlKeys: controlKeys,
+				Threshold:   threshold,
+			},
 		); err != nil {
 			return err
 		}
@@ -261,22 +323,48 @@ func CallAddValidator(
 	return err
 }
 
+// checkNodeIDCanValidateSubnet queries the P-Chain to fail early on add validator
+// txs that are guaranteed to be rejected: the node must currently be a primary
+// network validator, and must not be already validating the subnet
+func checkNodeIDCanValidateSubnet(network models.Network, subnetID ids.ID, nodeID ids.NodeID) error {
+	isPrimaryValidator, err := subnet.IsSubnetValidator(avagoconstants.PrimaryNetworkID, nodeID, network)
+	if err != nil {
+		return err
+	}
+	if !isPrimaryValidator {
+		return fmt.Errorf(
+			"node %s is not currently a primary network validator on %s. "+
+				"A node must be validating the primary network before being added as a subnet validator",
+			nodeID,
+			network.Name(),
+		)
+	}
+	isSubnetValidator, err := subnet.IsSubnetValidator(subnetID, nodeID, network)
+	if err != nil {
+		return err
+	}
+	if isSubnetValidator {
+		return fmt.Errorf("node %s is already a validator of subnet %s on %s", nodeID, subnetID, network.Name())
+	}
+	return nil
+}
+
 func PromptDuration(start time.Time, network models.Network) (time.Duration, error) {
 	for {
 		txt := "How long should this validator be validating? Enter a duration, e.g. 8760h. Valid time units are \"ns\", \"us\" (or \"µs\"), \"ms\", \"s\", \"m\", \"h\""
 		var d time.Duration
 		var err error
 		if network.Kind == models.Tahoe {
-			d, err = app.Prompt.CaptureFujiDuration(txt)
+			d, err = app.Prompt.CaptureFujiDuration(prompts.Keyed("duration", txt))
 		} else {
-			d, err = app.Prompt.CaptureMainnetDuration(txt)
+			d, err = app.Prompt.CaptureMainnetDuration(prompts.Keyed("duration", txt))
 		}
 		if err != nil {
 			return 0, err
 		}
 		end := start.Add(d)
 		confirm := fmt.Sprintf("Your validator will finish staking by %s", end.Format(constants.TimeParseLayout))
-		yes, err := app.Prompt.CaptureYesNo(confirm)
+		yes, err := app.Prompt.CaptureYesNo(prompts.Keyed("duration-confirm", confirm))
 		if err != nil {
 			return 0, err
 		}
@@ -286,11 +374,110 @@ func PromptDuration(start time.Time, network models.Network) (time.Duration, err
 	}
 }
 
+// getAddValidatorWizardSteps returns the wizard steps for the add validator params
+// that were not given by flags
+func getAddValidatorWizardSteps(
+	network models.Network,
+	subnetID ids.ID,
+	limits subnet.StakeWeightLimits,
+	nodeID *ids.NodeID,
+) []prompts.WizardStep {
+	steps := []prompts.WizardStep{}
+	if nodeIDStr == "" {
+		steps = append(steps, prompts.WizardStep{
+			Label: "NodeID",
+			Ask: func() error {
+				selectedNodeID, err := PromptNodeID()
+				if err != nil {
+					return err
+				}
+				if err := checkNodeIDCanValidateSubnet(network, subnetID, selectedNodeID); err != nil {
+					return err
+				}
+				*nodeID = selectedNodeID
+				return nil
+			},
+			Answer: func() string {
+				return nodeID.String()
+			},
+			Default: func() string {
+				if *nodeID == ids.EmptyNodeID {
+					return ""
+				}
+				return nodeID.String()
+			},
+		})
+	}
+	if weight == 0 && !useDefaultWeight {
+		weightAnswer := func() string {
+			if useDefaultWeight {
+				return defaultWeightOption()
+			}
+			return strconv.FormatUint(weight, 10)
+		}
+		steps = append(steps, prompts.WizardStep{
+			Label: "Weight",
+			Ask: func() error {
+				weight, useDefaultWeight = 0, false
+				selectedWeight, err := getWeight(limits)
+				if err != nil {
+					return err
+				}
+				checkValidatorWeightShare(network, subnetID, ids.EmptyNodeID, selectedWeight)
+				return nil
+			},
+			Answer:  weightAnswer,
+			Default: weightAnswer,
+		})
+	}
+	if startTimeStr == "" && !useDefaultStartTime {
+		startTimeAnswer := func() string {
+			if useDefaultStartTime {
+				return defaultStartTimeOption(network)
+			}
+			return startTimeStr
+		}
+		steps = append(steps, prompts.WizardStep{
+			Label: "Start time",
+			Ask: func() error {
+				startTimeStr, useDefaultStartTime = "", false
+				return promptStartTime(network, true)
+			},
+			Answer:  startTimeAnswer,
+			Default: startTimeAnswer,
+		})
+	}
+	if duration == 0 && !useDefaultDuration {
+		durationAnswer := func() string {
+			switch {
+			case useDefaultDuration:
+				return defaultDurationOption
+			case duration != 0:
+				return duration.String()
+			}
+			return ""
+		}
+		steps = append(steps, prompts.WizardStep{
+			Label: "Duration",
+			Ask: func() error {
+				duration, useDefaultDuration = 0, false
+				start, err := getStartTime(network)
+				if err != nil {
+					return err
+				}
+				return promptDurationOption(start, network, true)
+			},
+			Answer:  durationAnswer,
+			Default: durationAnswer,
+		})
+	}
+	return steps
+}
+
 func getMaxValidationTime(network models.Network, nodeID ids.NodeID, startTime time.Time) (time.Duration, error) {
 	ctx, cancel := utils.GetAPIContext()
 	defer cancel()
-	platformCli := platformvm.NewClient(network.Endpoint)
-	vs, err := platformCli.GetCurrentValidators(ctx, avagoconstants.PrimaryNetworkID, nil)
+	vs, err := apicache.GetCurrentValidators(ctx, network.Endpoint, avagoconstants.PrimaryNetworkID, nil)
 	cancel()
 	if err != nil {
 		return 0, err
@@ -304,78 +491,30 @@ func getMaxValidationTime(network models.Network, nodeID ids.NodeID, startTime t
 }
 
 func getTimeParameters(network models.Network, nodeID ids.NodeID, isValidator bool) (time.Time, time.Duration, error) {
-	defaultStakingStartLeadTime := constants.StakingStartLeadTime
-	if network.Kind == models.Devnet {
-		defaultStakingStartLeadTime = constants.DevnetStakingStartLeadTime
-	}
-
-	const custom = "Custom"
-
 	// this sets either the global var startTimeStr or useDefaultStartTime to enable repeated execution with
 	// state keeping from node cmds
 	if startTimeStr == "" && !useDefaultStartTime {
-		if isValidator {
-			ux.Logger.PrintToUser("When should your validator start validating?\n" +
-				"If you validator is not ready by this time, subnet downtime can occur.")
-		} else {
-			ux.Logger.PrintToUser("When do you want to start delegating?\n")
-		}
-		defaultStartOption := "Start in " + ux.FormatDuration(defaultStakingStartLeadTime)
-		startTimeOptions := []string{defaultStartOption, custom}
-		startTimeOption, err := app.Prompt.CaptureList("Start time", startTimeOptions)
-		if err != nil {
+		if err := promptStartTime(network, isValidator); err != nil {
 			return time.Time{}, 0, err
 		}
-		switch startTimeOption {
-		case defaultStartOption:
-			useDefaultStartTime = true
-		default:
-			start, err := promptStart()
-			if err != nil {
-				return time.Time{}, 0, err
-			}
-			startTimeStr = start.Format(constants.TimeParseLayout)
-		}
 	}
 
-	var (
-		err   error
-		start time.Time
-	)
+	start, err := getStartTime(network)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
 	if startTimeStr != "" {
-		start, err = time.Parse(constants.TimeParseLayout, startTimeStr)
-		if err != nil {
+		if err := utils.CheckStartTimeLeadTime(start, time.Now(), constants.StakingMinimumLeadTime); err != nil {
 			return time.Time{}, 0, err
 		}
-		if start.Before(time.Now().Add(constants.StakingMinimumLeadTime)) {
-			return time.Time{}, 0, fmt.Errorf("time should be at least %s in the future ", constants.StakingMinimumLeadTime)
-		}
-	} else {
-		start = time.Now().Add(defaultStakingStartLeadTime)
 	}
 
 	// this sets either the global var duration or useDefaultDuration to enable repeated execution with
 	// state keeping from node cmds
 	if duration == 0 && !useDefaultDuration {
-		msg := "How long should your validator validate for?"
-		if !isValidator {
-			msg = "How long do you want to delegate for?"
-		}
-		const defaultDurationOption = "Until primary network validator expires"
-		durationOptions := []string{defaultDurationOption, custom}
-		durationOption, err := app.Prompt.CaptureList(msg, durationOptions)
-		if err != nil {
+		if err := promptDurationOption(start, network, isValidator); err != nil {
 			return time.Time{}, 0, err
 		}
-		switch durationOption {
-		case defaultDurationOption:
-			useDefaultDuration = true
-		default:
-			duration, err = PromptDuration(start, network)
-			if err != nil {
-				return time.Time{}, 0, err
-			}
-		}
 	}
 
 	var selectedDuration time.Duration
@@ -392,9 +531,109 @@ func getTimeParameters(network models.Network, nodeID ids.NodeID, isValidator bo
 	return start, selectedDuration, nil
 }
 
+func getDefaultStakingStartLeadTime(network models.Network) time.Duration {
+	if network.Kind == models.Devnet {
+		return constants.DevnetStakingStartLeadTime
+	}
+	return constants.StakingStartLeadTime
+}
+
+func defaultStartTimeOption(network models.Network) string {
+	return "Start in " + ux.FormatDuration(getDefaultStakingStartLeadTime(network))
+}
+
+// promptStartTime sets either startTimeStr or useDefaultStartTime
+func promptStartTime(network models.Network, isValidator bool) error {
+	if isValidator {
+		ux.Logger.PrintToUser("When should your validator start validating?\n" +
+			"If you validator is not ready by this time, subnet downtime can occur.")
+	} else {
+		ux.Logger.PrintToUser("When do you want to start delegating?\n")
+	}
+	defaultStartOption := defaultStartTimeOption(network)
+	startTimeOptions := []string{defaultStartOption, customOption}
+	startTimeOption, err := app.Prompt.CaptureList(prompts.Keyed("start-time-option", "Start time"), startTimeOptions)
+	if err != nil {
+		return err
+	}
+	switch startTimeOption {
+	case defaultStartOption:
+		useDefaultStartTime = true
+	default:
+		start, err := promptStart()
+		if err != nil {
+			return err
+		}
+		startTimeStr = start.Format(time.RFC3339)
+	}
+	return nil
+}
+
+// getStartTime returns the start time given by startTimeStr, or the default one
+func getStartTime(network models.Network) (time.Time, error) {
+	if startTimeStr != "" {
+		loc, err := utils.LoadTimeZone(startTimeZone)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return utils.ParseStartTime(startTimeStr, time.Now(), loc)
+	}
+	return time.Now().Add(getDefaultStakingStartLeadTime(network)), nil
+}
+
+// promptDurationOption sets either duration or useDefaultDuration
+func promptDurationOption(start time.Time, network models.Network, isValidator bool) error {
+	msg := "How long should your validator validate for?"
+	if !isValidator {
+		msg = "How long do you want to delegate for?"
+	}
+	durationOptions := []string{defaultDurationOption, customOption}
+	durationOption, err := app.Prompt.CaptureList(prompts.Keyed("duration-option", msg), durationOptions)
+	if err != nil {
+		return err
+	}
+	switch durationOption {
+	case defaultDurationOption:
+		useDefaultDuration = true
+	default:
+		duration, err = PromptDuration(start, network)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func promptStart() (time.Time, error) {
-	txt := "When should the validator start validating? Enter a UTC datetime in 'YYYY-MM-DD HH:MM:SS' format"
-	return app.Prompt.CaptureDate(txt)
+	loc, err := utils.LoadTimeZone(startTimeZone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	txt := fmt.Sprintf(
+		"When should the validator start validating? Enter a %s datetime in 'YYYY-MM-DD HH:MM:SS' format, or an RFC3339 one with offset",
+		loc,
+	)
+	startStr, err := app.Prompt.CaptureValidatedString(prompts.Keyed("start-time", txt), func(s string) error {
+		start, err := utils.ParseStartTime(s, time.Now(), loc)
+		if err != nil {
+			return err
+		}
+		return utils.CheckStartTimeLeadTime(start, time.Now(), constants.StakingMinimumLeadTime)
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	return utils.ParseStartTime(startStr, time.Now(), loc)
+}
+
+// formatTime formats [t] in the time zone given with --timezone, or the system one,
+// and in UTC
+func formatTime(t time.Time) string {
+	loc, err := utils.LoadTimeZone(startTimeZone)
+	if err != nil || startTimeZone == "" {
+		loc = nil
+	}
+	return utils.FormatTimeWithUTC(t, loc)
 }
 
 func PromptNodeID() (ids.NodeID, error) {
@@ -404,17 +643,74 @@ func PromptNodeID() (ids.NodeID, error) {
 	ux.Logger.PrintToUser("(Edit host IP address and port to match your deployment, if needed).")
 
 	txt := "What is the NodeID of the validator you'd like to whitelist?"
-	return app.Prompt.CaptureNodeID(txt)
+	return app.Prompt.CaptureNodeID(prompts.Keyed("node-id", txt))
 }
 
-func getWeight() (uint64, error) {
+// GetNodeIDFromNode returns the NodeID of the node whose info API is at [nodeAddr],
+// an URL, IP or host name. The node must be on [network]
+func GetNodeIDFromNode(nodeAddr string, network models.Network) (ids.NodeID, error) {
+	nodeInfo, err := node.GetInfo(nodeAddr)
+	if err != nil {
+		return ids.EmptyNodeID, err
+	}
+	if err := nodeInfo.CheckNetwork(network); err != nil {
+		return ids.EmptyNodeID, err
+	}
+	ux.Logger.PrintToUser("Using NodeID %s of the node at %s", nodeInfo.NodeID, nodeInfo.Endpoint)
+	return nodeInfo.NodeID, nil
+}
+
+// warns if a validator with [weight] would hold a share of the subnet total
+// weight over the configured maximum. The current weight of [replacedNodeID], if
+// set, is not counted, as it is replaced by [weight]. Subnets with few validators
+// are not checked, and failing to get the validators only warns
+func checkValidatorWeightShare(network models.Network, subnetID ids.ID, replacedNodeID ids.NodeID, weight uint64) {
+	var (
+		validators []platformvm.ClientPermissionlessValidator
+		err        error
+	)
+	if network.Kind == models.Local {
+		validators, err = subnet.GetSubnetValidators(subnetID)
+	} else {
+		validators, err = subnet.GetPublicSubnetValidators(subnetID, network)
+	}
+	if err != nil {
+		ux.Logger.PrintToUser("WARNING: skipping the validator weight share check, failed to get the subnet validators: %s", err)
+		return
+	}
+	if len(validators) < constants.MinValidatorsForWeightShare {
+		return
+	}
+	validators = utils.Filter(validators, func(v platformvm.ClientPermissionlessValidator) bool {
+		return v.NodeID != replacedNodeID
+	})
+	maxShare := maxWeightShare
+	if maxShare == 0 {
+		maxShare = subnet.GetMaxWeightShare(app)
+	}
+	share := subnet.GetWeightShare(validators, weight)
+	if share > float64(maxShare) {
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser(
+			"WARNING: with weight %d, the validator would hold %.1f%% of the subnet total stake weight (over %d%%)",
+			weight,
+			share,
+			maxShare,
+		)
+		ux.Logger.PrintToUser("A single validator with such a share can halt or control the subnet. Consider a lower weight.")
+		ux.Logger.PrintToUser("")
+	}
+}
+
+// getWeight returns the weight set by flags, or prompts for one within [limits]
+func getWeight(limits subnet.StakeWeightLimits) (uint64, error) {
 	// this sets either the global var weight or useDefaultWeight to enable repeated execution with
 	// state keeping from node cmds
 	if weight == 0 && !useDefaultWeight {
-		defaultWeight := fmt.Sprintf("Default (%d)", constants.DefaultStakeWeight)
+		defaultWeight := defaultWeightOption()
 		txt := "What stake weight would you like to assign to the validator?"
-		weightOptions := []string{defaultWeight, "Custom"}
-		weightOption, err := app.Prompt.CaptureList(txt, weightOptions)
+		weightOptions := []string{defaultWeight, customOption}
+		weightOption, err := app.Prompt.CaptureList(prompts.Keyed("weight-option", txt), weightOptions)
 		if err != nil {
 			return 0, err
 		}
@@ -422,7 +718,7 @@ func getWeight() (uint64, error) {
 		case defaultWeight:
 			useDefaultWeight = true
 		default:
-			weight, err = app.Prompt.CaptureWeight(txt)
+			weight, err = app.Prompt.CaptureWeight(prompts.Keyed("weight", "Enter the stake weight of the validator"), limits.Check)
 			if err != nil {
 				return 0, err
 			}
@@ -433,3 +729,7 @@ func getWeight() (uint64, error) {
 	}
 	return weight, nil
 }
+
+func defaultWeightOption() string {
+	return fmt.Sprintf("Default (%d)", constants.DefaultStakeWeight)
+}
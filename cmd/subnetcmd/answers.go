@@ -0,0 +1,28 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
+	"github.com/spf13/cobra"
+)
+
+var answersPath string
+
+func addAnswersFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&answersPath, "answers", "", "answer the prompts from this YAML file, failing on missing answers instead of prompting")
+}
+
+// withAnswers makes [runE] answer its prompts from the --answers file, if given
+func withAnswers(runE func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if answersPath != "" {
+			prompter, err := prompts.NewAnswersPrompter(answersPath)
+			if err != nil {
+				return err
+			}
+			app.Prompt = prompter
+		}
+		return runE(cmd, args)
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/keychain"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
+	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/spf13/cobra"
+)
+
+var (
+	specPath    string
+	applyDryRun bool
+)
+
+// avalanche subnet apply
+func newApplyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Reconcile a subnet with a declarative spec",
+		Long: `The subnet apply command reconciles a subnet with the declarative YAML spec
+given with -f. The spec describes the VM and genesis params of the subnet, the
+networks it is deployed to, and the validators it has on each of them:
+
+  name: mySubnet
+  vm: subnet-evm            # or custom
+  vmVersion: latest
+  evm:
+    chainID: 4321
+    token: TOK
+    defaults: true
+  networks:
+    - network: tahoe        # local, devnet, tahoe or mainnet
+      key: myKey
+      validators:
+        - nodeID: NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg
+          weight: 20
+          stakingPeriod: 720h
+    - environment: staging  # a registered environment
+
+Custom VMs take the genesis file and a customVM section with the binary, or the
+repoURL, branch and buildScript to build it from.
+
+The subnet configuration is created if missing, the subnet is deployed to the
+networks it is not deployed to yet, and validators missing on each network are
+added. Like kubectl apply, the command only adds what is missing: it doesn't
+overwrite an existing configuration, and doesn't remove validators not in the
+spec. Use --dry-run to print the actions without doing them.`,
+		SilenceUsage: true,
+		RunE:         applySpec,
+		Args:         cobra.NoArgs,
+	}
+	cmd.Flags().StringVarP(&specPath, "file", "f", "", "subnet spec file")
+	cmd.Flags().BoolVar(&applyDryRun, "dry-run", false, "print the actions needed to reconcile the subnet, without doing them")
+	return cmd
+}
+
+// applyAction is a step to reconcile a subnet with its spec
+type applyAction struct {
+	description string
+	run         func() error
+}
+
+func applySpec(cmd *cobra.Command, _ []string) error {
+	if specPath == "" {
+		return ux.NewCodedError(ux.ErrCodeInvalidArguments, errors.New("the subnet spec must be given with -f"))
+	}
+	spec, err := models.LoadSubnetSpec(specPath)
+	if err != nil {
+		return err
+	}
+	actions, err := planSpecActions(cmd, spec)
+	if err != nil {
+		return err
+	}
+	if len(actions) == 0 {
+		ux.Logger.PrintToUser("Subnet %s is up to date with %s", spec.Name, specPath)
+		return nil
+	}
+	ux.Logger.PrintToUser("Actions to reconcile subnet %s with %s:", spec.Name, specPath)
+	for i, action := range actions {
+		ux.Logger.PrintToUser("  %d. %s", i+1, action.description)
+	}
+	if applyDryRun {
+		return nil
+	}
+	for i, action := range actions {
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("[%d/%d] %s", i+1, len(actions), action.description)
+		if err := action.run(); err != nil {
+			return fmt.Errorf("failed to %s: %w", action.description, err)
+		}
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Subnet %s reconciled with %s", spec.Name, specPath)
+	return nil
+}
+
+// specVMType returns the sidecar VM type of [spec]
+func specVMType(spec *models.SubnetSpec) models.VMType {
+	if spec.VM == models.SubnetSpecVMCustom {
+		return models.CustomVM
+	}
+	return models.SubnetEvm
+}
+
+// specNetworkFlags returns the network flags selecting the network of [specNetwork]
+func specNetworkFlags(specNetwork models.SubnetSpecNetwork) networkoptions.NetworkFlags {
+	switch specNetwork.Network {
+	case models.SubnetSpecLocal:
+		return networkoptions.NetworkFlags{UseLocal: true}
+	case models.SubnetSpecDevnet:
+		return networkoptions.NetworkFlags{UseDevnet: true, Endpoint: specNetwork.Endpoint}
+	case models.SubnetSpecTahoe:
+		return networkoptions.NetworkFlags{UseTahoe: true}
+	case models.SubnetSpecMainnet:
+		return networkoptions.NetworkFlags{UseMainnet: true}
+	}
+	return networkoptions.NetworkFlags{EnvironmentName: specNetwork.Environment}
+}
+
+// planSpecActions returns the actions that reconcile the subnet with [spec]
+func planSpecActions(cmd *cobra.Command, spec *models.SubnetSpec) ([]applyAction, error) {
+	actions := []applyAction{}
+	var sc models.Sidecar
+	configExists := app.SubnetConfigExists(spec.Name)
+	if configExists {
+		var err error
+		sc, err = app.LoadSidecar(spec.Name)
+		if err != nil {
+			return nil, err
+		}
+		if sc.VM != specVMType(spec) {
+			return nil, fmt.Errorf("subnet %s exists with VM %s, but the spec has VM %s. Delete it with 'metal subnet delete' to create it again", spec.Name, sc.VM, specVMType(spec))
+		}
+		if spec.VM == models.SubnetSpecVMSubnetEvm && spec.VMVersion != "" && spec.VMVersion != latest && spec.VMVersion != preRelease && spec.VMVersion != sc.VMVersion {
+			ux.Logger.PrintToUser("Subnet %s uses Subnet-EVM %s instead of %s. Use 'metal subnet upgrade vm' to change it", spec.Name, sc.VMVersion, spec.VMVersion)
+		}
+	} else {
+		actions = append(actions, applyAction{
+			description: fmt.Sprintf("create the %s configuration of subnet %s", specVMType(spec), spec.Name),
+			run: func() error {
+				return createSpecSubnet(cmd, spec)
+			},
+		})
+	}
+	for _, specNetwork := range spec.Networks {
+		specNetwork := specNetwork
+		network, err := networkoptions.GetNetworkFromCmdLineFlags(
+			app,
+			specNetworkFlags(specNetwork),
+			false,
+			addValidatorSupportedNetworkOptions,
+			"",
+		)
+		if err != nil {
+			return nil, err
+		}
+		subnetID := ids.Empty
+		if configExists {
+			subnetID = sc.Networks[network.Name()].SubnetID
+		}
+		if subnetID == ids.Empty {
+			actions = append(actions, applyAction{
+				description: fmt.Sprintf("deploy subnet %s to %s", spec.Name, network.Name()),
+				run: func() error {
+					return deploySpecSubnet(cmd, spec.Name, specNetwork)
+				},
+			})
+		}
+		for _, specValidator := range specNetwork.Validators {
+			specValidator := specValidator
+			nodeID, err := ids.NodeIDFromString(specValidator.NodeID)
+			if err != nil {
+				return nil, err
+			}
+			if subnetID != ids.Empty {
+				isValidator, err := subnet.IsSubnetValidator(subnetID, nodeID, network)
+				if err != nil {
+					return nil, err
+				}
+				if isValidator {
+					continue
+				}
+			}
+			actions = append(actions, applyAction{
+				description: fmt.Sprintf("add validator %s to subnet %s on %s", nodeID, spec.Name, network.Name()),
+				run: func() error {
+					return addSpecValidator(spec.Name, network, specNetwork, specValidator)
+				},
+			})
+		}
+	}
+	return actions, nil
+}
+
+func createSpecSubnet(cmd *cobra.Command, spec *models.SubnetSpec) error {
+	vmVersion := spec.VMVersion
+	if spec.VM == models.SubnetSpecVMSubnetEvm && vmVersion == "" {
+		vmVersion = latest
+	}
+	vmFile = spec.CustomVM.Binary
+	useRepo = spec.CustomVM.RepoURL != ""
+	return CallCreate(
+		cmd,
+		spec.Name,
+		false,
+		spec.Genesis,
+		spec.VM == models.SubnetSpecVMSubnetEvm,
+		spec.VM == models.SubnetSpecVMCustom,
+		vmVersion,
+		spec.EVM.ChainID,
+		spec.EVM.Token,
+		spec.EVM.Defaults,
+		false,
+		false,
+		spec.CustomVM.RepoURL,
+		spec.CustomVM.Branch,
+		spec.CustomVM.BuildScript,
+	)
+}
+
+func deploySpecSubnet(cmd *cobra.Command, subnetName string, specNetwork models.SubnetSpecNetwork) error {
+	controlKeys = specNetwork.ControlKeys
+	threshold = specNetwork.Threshold
+	return CallDeploy(
+		cmd,
+		false,
+		subnetName,
+		specNetworkFlags(specNetwork),
+		specNetwork.Key,
+		specNetwork.Ledger,
+		specNetwork.Signer,
+		false,
+		// without control keys, the fee paying key controls the subnet
+		len(specNetwork.ControlKeys) == 0,
+	)
+}
+
+func addSpecValidator(
+	subnetName string,
+	network models.Network,
+	specNetwork models.SubnetSpecNetwork,
+	specValidator models.SubnetSpecValidator,
+) error {
+	fee := network.GenesisParams().AddSubnetValidatorFee
+	kc, err := keychain.GetKeychainFromCmdLineFlags(
+		app,
+		constants.PayTxsFeesMsg,
+		models.KeyRoleValidatorManager,
+		network,
+		specNetwork.Key,
+		false,
+		specNetwork.Ledger,
+		nil,
+		specNetwork.Signer,
+		fee,
+	)
+	if err != nil {
+		return err
+	}
+	network.HandlePublicNetworkSimulation()
+	if err := UpdateKeychainWithSubnetControlKeys(kc, network, subnetName); err != nil {
+		return err
+	}
+	// reset the add validator params, as they are kept between calls
+	weight, useDefaultWeight = specValidator.Weight, specValidator.Weight == 0
+	duration, useDefaultDuration = specValidator.StakingPeriod, specValidator.StakingPeriod == 0
+	startTimeStr, useDefaultStartTime = "", true
+	subnetAuthKeys = nil
+	deployer := subnet.NewPublicDeployer(app, kc, network)
+	return CallAddValidator(deployer, network, kc, specNetwork.Ledger, subnetName, specValidator.NodeID, false, false)
+}
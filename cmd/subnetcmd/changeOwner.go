@@ -5,8 +5,10 @@ package subnetcmd
 import (
 	"fmt"
 
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
 	"github.com/MetalBlockchain/metal-cli/pkg/keychain"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
 	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
 	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
 	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
@@ -17,7 +19,7 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var changeOwnerSupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Devnet, networkoptions.Tahoe, networkoptions.Mainnet}
+var changeOwnerSupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Devnet, networkoptions.Environment, networkoptions.Tahoe, networkoptions.Mainnet}
 
 // avalanche subnet changeOwner
 func newChangeOwnerCmd() *cobra.Command {
@@ -28,17 +30,18 @@ func newChangeOwnerCmd() *cobra.Command {
 
 This command currently only works on Subnets deployed to Devnet, Fuji or Mainnet.`,
 		SilenceUsage: true,
-		RunE:         changeOwner,
-		Args:         cobra.ExactArgs(1),
+		RunE:         withActiveSubnet(changeOwner),
+		Args:         cobra.MaximumNArgs(1),
 	}
 	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, changeOwnerSupportedNetworkOptions)
 	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on fuji/devnet)")
 	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	flags.AddSignerFlag(cmd, &signerSpec)
 	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use [fuji/devnet]")
 	cmd.Flags().BoolVarP(&useEwoq, "ewoq", "e", false, "use ewoq key [fuji/devnet]")
-	cmd.Flags().StringSliceVar(&subnetAuthKeys, "subnet-auth-keys", nil, "control keys that will be used to authenticate transfer subnet ownership tx")
+	cmd.Flags().StringSliceVar(&subnetAuthKeys, "subnet-auth-keys", nil, "control keys (or address book labels) that will be used to authenticate transfer subnet ownership tx")
 	cmd.Flags().BoolVarP(&sameControlKey, "same-control-key", "s", false, "use the fee-paying key as control key")
-	cmd.Flags().StringSliceVar(&controlKeys, "control-keys", nil, "addresses that may make subnet changes")
+	cmd.Flags().StringSliceVar(&controlKeys, "control-keys", nil, "addresses (or address book labels) that may make subnet changes")
 	cmd.Flags().Uint32Var(&threshold, "threshold", 0, "required number of control key signatures to make subnet changes")
 	cmd.Flags().StringVar(&outputTxPath, "output-tx-path", "", "file path of the transfer subnet ownership tx")
 	return cmd
@@ -62,11 +65,13 @@ func changeOwner(_ *cobra.Command, args []string) error {
 	kc, err := keychain.GetKeychainFromCmdLineFlags(
 		app,
 		constants.PayTxsFeesMsg,
+		models.KeyRoleDeployer,
 		network,
 		keyName,
 		useEwoq,
 		useLedger,
 		ledgerAddresses,
+		signerSpec,
 		fee,
 	)
 	if err != nil {
@@ -114,6 +119,10 @@ func changeOwner(_ *cobra.Command, args []string) error {
 
 	// get keys for add validator tx signing
 	if subnetAuthKeys != nil {
+		subnetAuthKeys, err = app.ResolveAddressBookLabels(subnetAuthKeys)
+		if err != nil {
+			return err
+		}
 		if err := prompts.CheckSubnetAuthKeys(kcKeys, subnetAuthKeys, currentControlKeys, currentThreshold); err != nil {
 			return err
 		}
@@ -157,6 +166,11 @@ func changeOwner(_ *cobra.Command, args []string) error {
 			remainingSubnetAuthKeys,
 			outputTxPath,
 			false,
+			&txutils.OfflineSigningInfo{
+				SubnetID:    subnetID,
+				ControlKeys: currentControlKeys,
+				Threshold:   currentThreshold,
+			},
 		); err != nil {
 			return err
 		}
@@ -164,6 +178,7 @@ func changeOwner(_ *cobra.Command, args []string) error {
 		networkData := sc.Networks[network.Name()]
 		networkData.TransferSubnetOwnershipTxID = tx.ID()
 		sc.Networks[network.Name()] = networkData
+		sc.AddTxRecords(network.Name(), deployer.IssuedTxs()...)
 		if err := app.UpdateSidecar(&sc); err != nil {
 			return fmt.Errorf("change of subnet owner was successful, but failed to update sidecar: %w", err)
 		}
@@ -0,0 +1,395 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
+	"github.com/MetalBlockchain/metal-cli/pkg/binutils"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/snapshot"
+	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metal-network-runner/client"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+type chainConfigKind int
+
+const (
+	chainConfigString chainConfigKind = iota
+	chainConfigStringList
+	chainConfigBool
+	chainConfigUint
+	chainConfigInt
+	chainConfigFloat
+	chainConfigDuration
+	chainConfigAddress
+	chainConfigAddressList
+)
+
+// chainConfigKeys are the Subnet-EVM chain config keys that can be tuned after deploy
+var chainConfigKeys = map[string]chainConfigKind{
+	// fees
+	"feeRecipient":   chainConfigAddress,
+	"rpc-gas-cap":    chainConfigUint,
+	"rpc-tx-fee-cap": chainConfigFloat,
+	// gossip
+	"priority-regossip-addresses":  chainConfigAddressList,
+	"regossip-frequency":           chainConfigDuration,
+	"push-gossip-percent-stake":    chainConfigFloat,
+	"push-gossip-num-validators":   chainConfigInt,
+	"push-gossip-num-peers":        chainConfigInt,
+	"push-regossip-num-validators": chainConfigInt,
+	"push-regossip-num-peers":      chainConfigInt,
+	"push-gossip-frequency":        chainConfigDuration,
+	"pull-gossip-frequency":        chainConfigDuration,
+	// tx pool
+	"local-txs-enabled":     chainConfigBool,
+	"tx-pool-price-limit":   chainConfigUint,
+	"tx-pool-price-bump":    chainConfigUint,
+	"tx-pool-account-slots": chainConfigUint,
+	"tx-pool-global-slots":  chainConfigUint,
+	"tx-pool-account-queue": chainConfigUint,
+	"tx-pool-global-queue":  chainConfigUint,
+	"tx-pool-lifetime":      chainConfigDuration,
+	// APIs
+	"eth-apis":                   chainConfigStringList,
+	"api-max-duration":           chainConfigDuration,
+	"api-max-blocks-per-request": chainConfigInt,
+	"allow-unfinalized-queries":  chainConfigBool,
+	// node
+	"pruning-enabled": chainConfigBool,
+	"log-level":       chainConfigString,
+}
+
+var (
+	forceConfigKey         bool
+	skipConfigRestart      bool
+	configLocalNetworkName string
+)
+
+// avalanche subnet config
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Tune the chain config of a subnet",
+		Long: `The subnet config command suite edits the Subnet-EVM chain config of a subnet,
+such as its fee recipient, RPC gas caps, tx pool limits or gossip settings.
+
+If the subnet is deployed on the local network, the local nodes are restarted
+with the new chain config. Use --local-network-name for subnets deployed to a
+named local network. For public networks, the updated chain config file
+must be installed on the validators, as it is done for subnet configure.`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			_ = cmd.Help()
+		},
+	}
+	// subnet config set
+	setCmd := &cobra.Command{
+		Use:   "set [subnetName] key value",
+		Short: "Set a chain config value",
+		Long: fmt.Sprintf(`The subnet config set command sets [key] to [value] in the chain config of the subnet.
+Lists are given comma separated. Supported keys:
+  %s
+
+Other keys are rejected unless --force is given.`, strings.Join(supportedChainConfigKeys(), "\n  ")),
+		RunE:         setChainConfig,
+		Args:         cobra.RangeArgs(2, 3),
+		SilenceUsage: true,
+	}
+	setCmd.Flags().BoolVar(&forceConfigKey, "force", false, "set keys the CLI doesn't know about, as raw JSON or string values")
+	setCmd.Flags().BoolVar(&skipConfigRestart, "skip-restart", false, "don't restart the local network after updating the chain config")
+	flags.AddLocalNetworkNameFlag(setCmd, &configLocalNetworkName, "restart this named local network instead of the default one")
+	cmd.AddCommand(setCmd)
+	// subnet config unset
+	unsetCmd := &cobra.Command{
+		Use:          "unset [subnetName] key",
+		Short:        "Remove a chain config value, going back to the VM default",
+		RunE:         unsetChainConfig,
+		Args:         cobra.RangeArgs(1, 2),
+		SilenceUsage: true,
+	}
+	unsetCmd.Flags().BoolVar(&skipConfigRestart, "skip-restart", false, "don't restart the local network after updating the chain config")
+	flags.AddLocalNetworkNameFlag(unsetCmd, &configLocalNetworkName, "restart this named local network instead of the default one")
+	cmd.AddCommand(unsetCmd)
+	// subnet config get
+	getCmd := &cobra.Command{
+		Use:          "get [subnetName]",
+		Short:        "Print the chain config of the subnet",
+		RunE:         withActiveSubnet(getChainConfig),
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+	}
+	flags.AddJSONOutputFlag(getCmd)
+	cmd.AddCommand(getCmd)
+	return cmd
+}
+
+func supportedChainConfigKeys() []string {
+	keys := make([]string, 0, len(chainConfigKeys))
+	for k := range chainConfigKeys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func setChainConfig(cmd *cobra.Command, args []string) error {
+	if len(args) == 2 {
+		key, value := args[0], args[1]
+		return withActiveSubnet(func(cmd *cobra.Command, args []string) error {
+			return setChainConfig(cmd, []string{args[0], key, value})
+		})(cmd, []string{})
+	}
+	subnetName, key, value := args[0], args[1], args[2]
+	return updateChainConfig(subnetName, func(conf []byte) ([]byte, error) {
+		return setChainConfigValue(conf, key, value, forceConfigKey)
+	})
+}
+
+func unsetChainConfig(cmd *cobra.Command, args []string) error {
+	if len(args) == 1 {
+		key := args[0]
+		return withActiveSubnet(func(cmd *cobra.Command, args []string) error {
+			return unsetChainConfig(cmd, []string{args[0], key})
+		})(cmd, []string{})
+	}
+	subnetName, key := args[0], args[1]
+	return updateChainConfig(subnetName, func(conf []byte) ([]byte, error) {
+		return unsetChainConfigValue(conf, key)
+	})
+}
+
+func getChainConfig(_ *cobra.Command, args []string) error {
+	subnetName := args[0]
+	if !app.SubnetConfigExists(subnetName) {
+		return ux.NewCodedError(ux.ErrCodeNotFound, fmt.Errorf("subnet %s does not exist", subnetName))
+	}
+	conf := []byte("{}")
+	if app.ChainConfigExists(subnetName) {
+		var err error
+		conf, err = app.LoadRawChainConfig(subnetName)
+		if err != nil {
+			return err
+		}
+	}
+	values := map[string]json.RawMessage{}
+	if err := json.Unmarshal(conf, &values); err != nil {
+		return fmt.Errorf("invalid chain config file %s: %w", app.GetChainConfigPath(subnetName), err)
+	}
+	if ux.JSONOutput() {
+		return ux.PrintResult(values)
+	}
+	if len(values) == 0 {
+		ux.Logger.PrintToUser("Subnet %s uses the default chain config", subnetName)
+		return nil
+	}
+	bs, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser(string(bs))
+	return nil
+}
+
+// updateChainConfig applies [update] to the chain config of [subnetName], and
+// restarts the local network with it if the subnet is deployed there
+func updateChainConfig(subnetName string, update func([]byte) ([]byte, error)) error {
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return ux.NewCodedError(ux.ErrCodeNotFound, fmt.Errorf("failed to load sidecar for subnet %s: %w", subnetName, err))
+	}
+	if sc.VM != models.SubnetEvm {
+		return ux.NewCodedError(ux.ErrCodeInvalidArguments, errors.New("chain config tuning is only supported for Subnet-EVM subnets"))
+	}
+	// as on deploy, named local networks have their own sidecar entries and backend
+	if err := binutils.SelectLocalNetwork(app, configLocalNetworkName, false); err != nil {
+		return err
+	}
+	conf := []byte("{}")
+	if app.ChainConfigExists(subnetName) {
+		conf, err = app.LoadRawChainConfig(subnetName)
+		if err != nil {
+			return err
+		}
+	}
+	conf, err = update(conf)
+	if err != nil {
+		return ux.NewCodedError(ux.ErrCodeInvalidArguments, err)
+	}
+	if err := app.WriteChainConfigFile(subnetName, conf); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Chain config of %s updated at %s", subnetName, app.GetChainConfigPath(subnetName))
+
+	localBlockchainID := sc.Networks[models.NewLocalNetwork().Name()].BlockchainID
+	if localBlockchainID != ids.Empty && !skipConfigRestart {
+		if err := restartLocalNetworkWithChainConfig(localBlockchainID, string(conf)); err != nil {
+			return err
+		}
+	}
+	for network, deployInfo := range sc.Networks {
+		if models.IsLocalNetworkName(network) || deployInfo.BlockchainID == ids.Empty {
+			continue
+		}
+		ux.Logger.PrintToUser("To apply it on %s, copy it to %s on each validator and restart them",
+			network, filepath.Join("$HOME", ".metalgo", constants.ChainConfigDir, deployInfo.BlockchainID.String(), "config.json"))
+	}
+	return nil
+}
+
+// restartLocalNetworkWithChainConfig restarts the local network from a temporary
+// snapshot, installing [chainConfig] for [blockchainID] on all nodes
+func restartLocalNetworkWithChainConfig(blockchainID ids.ID, chainConfig string) error {
+	cli, err := binutils.NewGRPCClient()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	status, err := cli.Status(ctx)
+	if err != nil {
+		// nothing running, the chain config is used on next deploy or start
+		app.Log.Debug("local network not running, skipping restart", zap.Error(err))
+		return nil
+	}
+	if _, ok := status.ClusterInfo.GetCustomChains()[blockchainID.String()]; !ok {
+		return nil
+	}
+
+	ctx, cancel = utils.GetANRContext()
+	defer cancel()
+	snapName := "chain-config-tmp-" + time.Now().Format("20060102150405")
+	snapshotSettings := snapshot.GetSettings(app)
+	if snapshotSettings.Auto {
+		snapName = snapshot.AutoName(snapshotSettings, "config", blockchainID.String())
+	}
+	ux.Logger.PrintToUser("Restarting local network with the new chain config...")
+	if _, err := cli.SaveSnapshot(ctx, snapName); err != nil {
+		return err
+	}
+	if _, err := cli.LoadSnapshot(ctx, snapName, client.WithChainConfigs(map[string]string{
+		blockchainID.String(): chainConfig,
+	})); err != nil {
+		return err
+	}
+	clusterInfo, err := subnet.WaitForHealthy(ctx, cli)
+	if err != nil {
+		return fmt.Errorf("failed waiting for network to become healthy: %w", err)
+	}
+	if snapshotSettings.Auto {
+		ux.Logger.PrintToUser("Previous network state saved as snapshot %s", snapName)
+		if _, err := snapshot.ApplyRetention(app); err != nil {
+			app.Log.Warn("failed pruning old snapshots", zap.Error(err))
+		}
+	} else if _, err := cli.RemoveSnapshot(ctx, snapName); err != nil {
+		app.Log.Warn("failed removing temporary snapshot", zap.String("snapshot-name", snapName), zap.Error(err))
+	}
+	ux.Logger.PrintToUser("Chain config installed for blockchain %s on nodes: %s", blockchainID, strings.Join(clusterInfo.NodeNames, ", "))
+	return nil
+}
+
+// setChainConfigValue sets [key] to [value] on the JSON chain config [conf],
+// validating it against the type the VM expects
+func setChainConfigValue(conf []byte, key string, value string, force bool) ([]byte, error) {
+	values := map[string]json.RawMessage{}
+	if err := json.Unmarshal(conf, &values); err != nil {
+		return nil, fmt.Errorf("invalid chain config: %w", err)
+	}
+	kind, ok := chainConfigKeys[key]
+	if !ok {
+		if !force {
+			return nil, fmt.Errorf("unknown chain config key %q, use --force to set it anyway. Supported keys: %s",
+				key, strings.Join(supportedChainConfigKeys(), ", "))
+		}
+		var raw json.RawMessage
+		if json.Unmarshal([]byte(value), &raw) == nil {
+			values[key] = raw
+			return json.MarshalIndent(values, "", "  ")
+		}
+		kind = chainConfigString
+	}
+	parsed, err := parseChainConfigValue(kind, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	bs, err := json.Marshal(parsed)
+	if err != nil {
+		return nil, err
+	}
+	values[key] = bs
+	return json.MarshalIndent(values, "", "  ")
+}
+
+// unsetChainConfigValue removes [key] from the JSON chain config [conf]
+func unsetChainConfigValue(conf []byte, key string) ([]byte, error) {
+	values := map[string]json.RawMessage{}
+	if err := json.Unmarshal(conf, &values); err != nil {
+		return nil, fmt.Errorf("invalid chain config: %w", err)
+	}
+	if _, ok := values[key]; !ok {
+		return nil, fmt.Errorf("chain config key %q is not set", key)
+	}
+	delete(values, key)
+	return json.MarshalIndent(values, "", "  ")
+}
+
+func parseChainConfigValue(kind chainConfigKind, value string) (interface{}, error) {
+	switch kind {
+	case chainConfigBool:
+		return strconv.ParseBool(value)
+	case chainConfigUint:
+		return strconv.ParseUint(value, 10, 64)
+	case chainConfigInt:
+		return strconv.ParseInt(value, 10, 64)
+	case chainConfigFloat:
+		return strconv.ParseFloat(value, 64)
+	case chainConfigDuration:
+		// subnet-evm reads durations in their string form
+		if _, err := time.ParseDuration(value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	case chainConfigAddress:
+		if !common.IsHexAddress(value) {
+			return nil, fmt.Errorf("%q is not an EVM address", value)
+		}
+		return common.HexToAddress(value).Hex(), nil
+	case chainConfigAddressList:
+		addrs := []string{}
+		for _, s := range splitConfigList(value) {
+			if !common.IsHexAddress(s) {
+				return nil, fmt.Errorf("%q is not an EVM address", s)
+			}
+			addrs = append(addrs, common.HexToAddress(s).Hex())
+		}
+		return addrs, nil
+	case chainConfigStringList:
+		return splitConfigList(value), nil
+	}
+	return value, nil
+}
+
+func splitConfigList(value string) []string {
+	elems := []string{}
+	for _, s := range strings.Split(value, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			elems = append(elems, s)
+		}
+	}
+	return elems
+}
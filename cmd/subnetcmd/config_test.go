@@ -0,0 +1,53 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetChainConfigValue(t *testing.T) {
+	require := require.New(t)
+	conf := []byte(`{"log-level":"info"}`)
+
+	conf, err := setChainConfigValue(conf, "feeRecipient", "0x8db97c7cece249c2b98bdc0226cc4c2a57bf52fc", false)
+	require.NoError(err)
+	conf, err = setChainConfigValue(conf, "rpc-gas-cap", "50000000", false)
+	require.NoError(err)
+	conf, err = setChainConfigValue(conf, "priority-regossip-addresses", "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC, 0x0000000000000000000000000000000000000001", false)
+	require.NoError(err)
+	conf, err = setChainConfigValue(conf, "push-gossip-frequency", "100ms", false)
+	require.NoError(err)
+	conf, err = setChainConfigValue(conf, "custom-key", `{"a":1}`, true)
+	require.NoError(err)
+
+	values := map[string]interface{}{}
+	require.NoError(json.Unmarshal(conf, &values))
+	require.Equal("info", values["log-level"])
+	require.Equal("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC", values["feeRecipient"])
+	require.Equal(float64(50000000), values["rpc-gas-cap"])
+	require.Equal([]interface{}{
+		"0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC",
+		"0x0000000000000000000000000000000000000001",
+	}, values["priority-regossip-addresses"])
+	require.Equal("100ms", values["push-gossip-frequency"])
+	require.Equal(map[string]interface{}{"a": float64(1)}, values["custom-key"])
+
+	_, err = setChainConfigValue(conf, "feeRecipient", "nope", false)
+	require.Error(err)
+	_, err = setChainConfigValue(conf, "rpc-gas-cap", "-1", false)
+	require.Error(err)
+	_, err = setChainConfigValue(conf, "push-gossip-frequency", "often", false)
+	require.Error(err)
+	_, err = setChainConfigValue(conf, "custom-key", "1", false)
+	require.ErrorContains(err, "--force")
+
+	conf, err = unsetChainConfigValue(conf, "rpc-gas-cap")
+	require.NoError(err)
+	require.NotContains(string(conf), "rpc-gas-cap")
+	_, err = unsetChainConfigValue(conf, "rpc-gas-cap")
+	require.Error(err)
+}
@@ -19,6 +19,7 @@ var (
 	subnetConf       string
 	chainConf        string
 	perNodeChainConf string
+	upgradeConf      string
 )
 
 // avalanche subnet configure
@@ -29,16 +30,21 @@ func newConfigureCmd() *cobra.Command {
 		Long: `AvalancheGo nodes support several different configuration files. Subnets have their own
 Subnet config which applies to all chains/VMs in the Subnet. Each chain within the Subnet
 can have its own chain config. A chain can also have special requirements for the AvalancheGo node 
-configuration itself. This command allows you to set all those files.`,
+configuration itself. A chain can also be upgraded through a network upgrade file (upgrade.json).
+This command allows you to set all those files.
+
+The files are stored alongside the subnet sidecar. Local deploys install them into the chain
+config directory of each node.`,
 		SilenceUsage: true,
-		RunE:         configure,
-		Args:         cobra.ExactArgs(1),
+		RunE:         withActiveSubnet(configure),
+		Args:         cobra.MaximumNArgs(1),
 	}
 
 	cmd.Flags().StringVar(&nodeConf, "node-config", "", "path to avalanchego node configuration")
 	cmd.Flags().StringVar(&subnetConf, "subnet-config", "", "path to the subnet configuration")
 	cmd.Flags().StringVar(&chainConf, "chain-config", "", "path to the chain configuration")
 	cmd.Flags().StringVar(&perNodeChainConf, "per-node-chain-config", "", "path to per node chain configuration for local network")
+	cmd.Flags().StringVar(&upgradeConf, "upgrade-config", "", "path to the network upgrade file (upgrade.json)")
 	return cmd
 }
 
@@ -67,6 +73,7 @@ func configure(_ *cobra.Command, args []string) error {
 		perNodeChainLabel = constants.PerNodeChainConfigFileName
 		subnetLabel       = constants.SubnetConfigFileName
 		nodeLabel         = constants.NodeConfigFileName
+		upgradeLabel      = constants.UpgradeBytesFileName
 	)
 	configsToLoad := map[string]string{}
 
@@ -82,10 +89,13 @@ func configure(_ *cobra.Command, args []string) error {
 	if perNodeChainConf != "" {
 		configsToLoad[perNodeChainLabel] = perNodeChainConf
 	}
+	if upgradeConf != "" {
+		configsToLoad[upgradeLabel] = upgradeConf
+	}
 
 	// no flags provided
 	if len(configsToLoad) == 0 {
-		options := []string{nodeLabel, chainLabel, subnetLabel, perNodeChainLabel}
+		options := []string{nodeLabel, chainLabel, subnetLabel, perNodeChainLabel, upgradeLabel}
 		selected, err := app.Prompt.CaptureList("Which configuration file would you like to provide?", options)
 		if err != nil {
 			return err
@@ -95,7 +105,7 @@ func configure(_ *cobra.Command, args []string) error {
 			return err
 		}
 		var other string
-		if selected == chainLabel || selected == perNodeChainLabel {
+		if selected == chainLabel || selected == perNodeChainLabel || selected == upgradeLabel {
 			other = subnetLabel
 		} else {
 			other = chainLabel
@@ -127,7 +137,7 @@ func updateConf(subnet, path, filename string) error {
 		fileBytes []byte
 		err       error
 	)
-	if strings.ToLower(filepath.Ext(filename)) == "json" {
+	if strings.ToLower(filepath.Ext(filename)) == ".json" {
 		fileBytes, err = utils.ValidateJSON(path)
 		if err != nil {
 			return err
@@ -0,0 +1,256 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metalgo/api/info"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/units"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	costCreateSubnet                = "create-subnet"
+	costCreateChain                 = "create-chain"
+	costDeploy                      = "deploy"
+	costAddValidator                = "add-validator"
+	costElastic                     = "elastic"
+	costAddPermissionlessValidator  = "add-permissionless-validator"
+	costAddPermissionlessDelegator  = "add-permissionless-delegator"
+	costAllOperations               = "all"
+	costDefaultNumValidatorsOrCalls = 1
+)
+
+var (
+	costOperations = []string{
+		costCreateSubnet,
+		costCreateChain,
+		costDeploy,
+		costAddValidator,
+		costElastic,
+		costAddPermissionlessValidator,
+		costAddPermissionlessDelegator,
+	}
+	costSubnetName string
+	costCount      int
+)
+
+// avalanche subnet cost
+func newCostCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cost [operation]",
+		Short: "Estimate the cost of subnet operations",
+		Long: fmt.Sprintf(`The subnet cost command estimates the %s cost of subnet operations on the
+target network, using the fee parameters the network currently reports, so
+deployments can be budgeted before starting them.
+
+Supported operations: %s. Without an operation, the cost of all of them is shown.
+
+Permissionless validators and delegators also lock a staking bond in the token
+of the elastic subnet given with --subnet, which is reported next to the fees.`,
+			constants.AVAXSymbol, strings.Join(costOperations, ", ")),
+		RunE:         estimateCostCmd,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, validatorsSupportedNetworkOptions)
+	cmd.Flags().StringVar(&costSubnetName, "subnet", "", "elastic subnet to get the staking bond of permissionless validators and delegators from")
+	cmd.Flags().IntVar(&costCount, "count", costDefaultNumValidatorsOrCalls, "number of validators or delegators to add")
+	flags.AddJSONOutputFlag(cmd)
+	return cmd
+}
+
+// costLine is one fee or staking bond of an operation
+type costLine struct {
+	Operation string `json:"operation"`
+	Item      string `json:"item"`
+	Amount    uint64 `json:"amount"`
+	// Symbol is the token of the amount. Fees are in nano units of the network token
+	Symbol string `json:"symbol"`
+	IsFee  bool   `json:"isFee"`
+}
+
+// costEstimate is the estimated cost of a set of operations, as printed by subnet cost
+type costEstimate struct {
+	Network string     `json:"network"`
+	Lines   []costLine `json:"lines"`
+	// TotalFees is the sum of all fees, in nano units of the network token
+	TotalFees uint64 `json:"totalFees"`
+}
+
+// stakeBond is the minimum stake of validators and delegators of an elastic subnet
+type stakeBond struct {
+	symbol         string
+	validatorStake uint64
+	delegatorStake uint64
+}
+
+func estimateCostCmd(_ *cobra.Command, args []string) error {
+	operation := costAllOperations
+	if len(args) > 0 {
+		operation = args[0]
+	}
+	if operation != costAllOperations && !slices.Contains(costOperations, operation) {
+		return ux.NewCodedError(ux.ErrCodeInvalidArguments,
+			fmt.Errorf("unknown operation %q. Supported operations: %s", operation, strings.Join(costOperations, ", ")))
+	}
+	if costCount <= 0 {
+		return ux.NewCodedError(ux.ErrCodeInvalidArguments, errors.New("--count must be positive"))
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		globalNetworkFlags,
+		false,
+		validatorsSupportedNetworkOptions,
+		costSubnetName,
+	)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	fees, err := info.NewClient(network.Endpoint).GetTxFee(ctx)
+	if err != nil {
+		return fmt.Errorf("failed getting fee parameters from %s: %w", network.Endpoint, err)
+	}
+
+	var bond *stakeBond
+	needsBond := operation == costAllOperations ||
+		operation == costAddPermissionlessValidator ||
+		operation == costAddPermissionlessDelegator
+	if needsBond && costSubnetName != "" {
+		sc, err := app.LoadSidecar(costSubnetName)
+		if err != nil {
+			return err
+		}
+		elasticInfo, ok := sc.ElasticSubnet[network.Name()]
+		if !ok || elasticInfo.SubnetID == ids.Empty {
+			return fmt.Errorf("subnet %s is not an elastic subnet on %s", costSubnetName, network.Name())
+		}
+		minValidatorStake, minDelegatorStake, err := platformvm.NewClient(network.Endpoint).GetMinStake(ctx, elasticInfo.SubnetID)
+		if err != nil {
+			return fmt.Errorf("failed getting staking parameters of subnet %s: %w", costSubnetName, err)
+		}
+		bond = &stakeBond{
+			symbol:         elasticInfo.TokenSymbol,
+			validatorStake: minValidatorStake,
+			delegatorStake: minDelegatorStake,
+		}
+	} else if operation != costAllOperations && needsBond {
+		ux.Logger.PrintToUser("No elastic subnet given with --subnet, the staking bond is not included")
+	}
+
+	operations := []string{operation}
+	if operation == costAllOperations {
+		// deploy is create-subnet plus create-chain, don't count it twice
+		operations = slices.DeleteFunc(slices.Clone(costOperations), func(op string) bool { return op == costDeploy })
+	}
+	estimate := costEstimate{Network: network.Name()}
+	for _, op := range operations {
+		lines, err := estimateOperationCost(op, fees, bond, costCount)
+		if err != nil {
+			return err
+		}
+		estimate.Lines = append(estimate.Lines, lines...)
+	}
+	for _, line := range estimate.Lines {
+		if line.IsFee {
+			estimate.TotalFees += line.Amount
+		}
+	}
+	if ux.JSONOutput() {
+		return ux.PrintResult(estimate)
+	}
+	printCostEstimate(estimate)
+	return nil
+}
+
+// estimateOperationCost returns the fees, and the staking bond if [bond] is given,
+// of doing [operation] [count] times, for validator and delegator operations
+func estimateOperationCost(
+	operation string,
+	fees *info.GetTxFeeResponse,
+	bond *stakeBond,
+	count int,
+) ([]costLine, error) {
+	fee := func(item string, amount uint64) costLine {
+		return costLine{Operation: operation, Item: item, Amount: amount, Symbol: constants.AVAXSymbol, IsFee: true}
+	}
+	n := uint64(count)
+	switch operation {
+	case costCreateSubnet:
+		return []costLine{fee("CreateSubnetTx fee", uint64(fees.CreateSubnetTxFee))}, nil
+	case costCreateChain:
+		return []costLine{fee("CreateChainTx fee", uint64(fees.CreateBlockchainTxFee))}, nil
+	case costDeploy:
+		return []costLine{
+			fee("CreateSubnetTx fee", uint64(fees.CreateSubnetTxFee)),
+			fee("CreateChainTx fee", uint64(fees.CreateBlockchainTxFee)),
+		}, nil
+	case costAddValidator:
+		return []costLine{fee(fmt.Sprintf("AddSubnetValidatorTx fee x%d", n), uint64(fees.AddSubnetValidatorFee)*n)}, nil
+	case costElastic:
+		return []costLine{
+			fee("CreateAssetTx fee", uint64(fees.CreateAssetTxFee)),
+			fee("TransformSubnetTx fee", uint64(fees.TransformSubnetTxFee)),
+			// one export and one import tx move the new asset to the P-Chain
+			fee("Export/ImportTx fees", uint64(fees.TxFee)*2),
+		}, nil
+	case costAddPermissionlessValidator:
+		lines := []costLine{fee(fmt.Sprintf("AddPermissionlessValidatorTx fee x%d", n), uint64(fees.AddSubnetValidatorFee)*n)}
+		if bond != nil {
+			lines = append(lines, costLine{
+				Operation: operation,
+				Item:      fmt.Sprintf("minimum stake x%d", n),
+				Amount:    bond.validatorStake * n,
+				Symbol:    bond.symbol,
+			})
+		}
+		return lines, nil
+	case costAddPermissionlessDelegator:
+		lines := []costLine{fee(fmt.Sprintf("AddPermissionlessDelegatorTx fee x%d", n), uint64(fees.AddSubnetDelegatorFee)*n)}
+		if bond != nil {
+			lines = append(lines, costLine{
+				Operation: operation,
+				Item:      fmt.Sprintf("minimum stake x%d", n),
+				Amount:    bond.delegatorStake * n,
+				Symbol:    bond.symbol,
+			})
+		}
+		return lines, nil
+	}
+	return nil, fmt.Errorf("unknown operation %q", operation)
+}
+
+func formatCostAmount(line costLine) string {
+	if line.IsFee {
+		return fmt.Sprintf("%.9f %s", float64(line.Amount)/float64(units.Avax), line.Symbol)
+	}
+	return fmt.Sprintf("%s %s", ux.ConvertToStringWithThousandSeparator(line.Amount), line.Symbol)
+}
+
+func printCostEstimate(estimate costEstimate) {
+	ux.Logger.PrintToUser("Estimated costs on %s, with current fee parameters:", estimate.Network)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"operation", "item", "cost"})
+	table.SetAutoMergeCellsByColumnIndex([]int{0})
+	table.SetRowLine(true)
+	for _, line := range estimate.Lines {
+		table.Append([]string{line.Operation, line.Item, formatCostAmount(line)})
+	}
+	table.Render()
+	ux.Logger.PrintToUser("Total fees: %.9f %s", float64(estimate.TotalFees)/float64(units.Avax), constants.AVAXSymbol)
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"testing"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metalgo/api/info"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateOperationCost(t *testing.T) {
+	require := require.New(t)
+	fees := &info.GetTxFeeResponse{
+		TxFee:                 1_000_000,
+		CreateAssetTxFee:      10_000_000,
+		CreateSubnetTxFee:     1_000_000_000,
+		TransformSubnetTxFee:  10_000_000_000,
+		CreateBlockchainTxFee: 1_000_000_000,
+		AddSubnetValidatorFee: 1_000_000,
+		AddSubnetDelegatorFee: 1_000_000,
+	}
+	bond := &stakeBond{symbol: "TST", validatorStake: 2_000, delegatorStake: 25}
+
+	lines, err := estimateOperationCost(costDeploy, fees, nil, 1)
+	require.NoError(err)
+	require.Len(lines, 2)
+	require.Equal(uint64(2_000_000_000), lines[0].Amount+lines[1].Amount)
+	require.Equal(constants.AVAXSymbol, lines[0].Symbol)
+
+	lines, err = estimateOperationCost(costAddValidator, fees, nil, 3)
+	require.NoError(err)
+	require.Equal([]costLine{{
+		Operation: costAddValidator,
+		Item:      "AddSubnetValidatorTx fee x3",
+		Amount:    3_000_000,
+		Symbol:    constants.AVAXSymbol,
+		IsFee:     true,
+	}}, lines)
+
+	lines, err = estimateOperationCost(costAddPermissionlessValidator, fees, bond, 2)
+	require.NoError(err)
+	require.Len(lines, 2)
+	require.Equal(uint64(2_000_000), lines[0].Amount)
+	require.False(lines[1].IsFee)
+	require.Equal(uint64(4_000), lines[1].Amount)
+	require.Equal("TST", lines[1].Symbol)
+
+	lines, err = estimateOperationCost(costAddPermissionlessDelegator, fees, nil, 1)
+	require.NoError(err)
+	require.Len(lines, 1)
+
+	lines, err = estimateOperationCost(costElastic, fees, nil, 1)
+	require.NoError(err)
+	require.Len(lines, 3)
+
+	_, err = estimateOperationCost("nope", fees, nil, 1)
+	require.Error(err)
+}
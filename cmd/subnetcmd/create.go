@@ -5,6 +5,8 @@ package subnetcmd
 import (
 	"errors"
 	"fmt"
+	"math/big"
+	"os"
 	"sort"
 	"strconv"
 	"strings"
@@ -15,8 +17,11 @@ import (
 	"github.com/MetalBlockchain/metal-cli/pkg/metrics"
 
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
 	"github.com/MetalBlockchain/metal-cli/pkg/vm"
+	"github.com/MetalBlockchain/subnet-evm/params"
+	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 	"golang.org/x/mod/semver"
 )
@@ -43,6 +48,7 @@ var (
 	teleporterReady                bool
 	runRelayer                     bool
 	useWarp                        bool
+	maxSupply                      uint64
 
 	errIllegalNameCharacter = errors.New(
 		"illegal name character: only letters, no special characters allowed")
@@ -65,15 +71,25 @@ the path to your genesis and VM binaries with the --genesis and --vm flags.
 
 By default, running the command with a subnetName that already exists
 causes the command to fail. If you’d like to overwrite an existing
-configuration, pass the -f flag.`,
+configuration, pass the -f flag.
+
+For Subnet-EVM genesis, the command prints a report of the initial token
+supply and its distribution among funded addresses. Use --max-supply to
+make the command fail if the genesis allocates more tokens than intended.
+
+Use --answers to answer the wizard prompts from a YAML file instead, so subnet
+definitions can be versioned and created again reproducibly. The file maps the
+key of each prompt to its answer, eg 'vm: Subnet-EVM'. A list of answers is
+taken in order by a prompt asked several times. The command fails listing all
+the answers to add when some are missing, instead of prompting.`,
 		SilenceUsage:      true,
 		Args:              cobra.ExactArgs(1),
-		RunE:              createSubnetConfig,
+		RunE:              withAnswers(createSubnetConfig),
 		PersistentPostRun: handlePostRun,
 	}
 	cmd.Flags().StringVar(&genesisFile, "genesis", "", "file path of genesis to use")
 	cmd.Flags().BoolVar(&useSubnetEvm, "evm", false, "use the Subnet-EVM as the base template")
-	cmd.Flags().StringVar(&evmVersion, "vm-version", "", "version of Subnet-EVM template to use")
+	cmd.Flags().StringVar(&evmVersion, "vm-version", "", "version of Subnet-EVM to use (ex: v0.6.3). The version is pinned in the subnet configuration and used by its deploys")
 	cmd.Flags().Uint64Var(&evmChainID, "evm-chain-id", 0, "chain ID to use with Subnet-EVM")
 	cmd.Flags().StringVar(&evmToken, "evm-token", "", "token name to use with Subnet-EVM")
 	cmd.Flags().BoolVar(&evmDefaults, "evm-defaults", false, "use default settings for fees/airdrop/precompiles/teleporter with Subnet-EVM")
@@ -90,6 +106,8 @@ configuration, pass the -f flag.`,
 	cmd.Flags().BoolVar(&useWarp, "warp", true, "generate a vm with warp support (needed for teleporter)")
 	cmd.Flags().BoolVar(&teleporterReady, "teleporter", false, "generate a teleporter-ready vm")
 	cmd.Flags().BoolVar(&runRelayer, "relayer", false, "run AWM relayer when deploying the vm")
+	cmd.Flags().Uint64Var(&maxSupply, "max-supply", 0, "fail if the Subnet-EVM genesis allocates more than this amount of tokens (10^18 units)")
+	addAnswersFlag(cmd)
 	return cmd
 }
 
@@ -187,7 +205,7 @@ func createSubnetConfig(cmd *cobra.Command, args []string) error {
 
 	if subnetType == "" {
 		subnetTypeStr, err := app.Prompt.CaptureList(
-			"Choose your VM",
+			prompts.Keyed("vm", "Choose your VM"),
 			[]string{models.SubnetEvm, models.CustomVM},
 		)
 		if err != nil {
@@ -252,6 +270,9 @@ func createSubnetConfig(cmd *cobra.Command, args []string) error {
 		if evmDefaults {
 			runRelayer = true
 		}
+		if err := checkAllocation(genesisBytes, sc.TokenSymbol); err != nil {
+			return err
+		}
 	}
 
 	if err = app.WriteGenesisFile(subnetName, genesisBytes); err != nil {
@@ -259,6 +280,8 @@ func createSubnetConfig(cmd *cobra.Command, args []string) error {
 	}
 
 	sc.ImportedFromAPM = false
+	// only an explicit --vm-version pins the version, not latest releases or prompt answers
+	sc.VMVersionPinned = subnetType == models.SubnetEvm && evmVersion != "" && evmVersion != latest && evmVersion != preRelease
 	if err = app.CreateSidecar(sc); err != nil {
 		return err
 	}
@@ -269,9 +292,75 @@ func createSubnetConfig(cmd *cobra.Command, args []string) error {
 		}
 	}
 	ux.Logger.GreenCheckmarkToUser("Successfully created subnet configuration")
+	if sc.VMVersionPinned {
+		ux.Logger.PrintToUser("Subnet-EVM version %s is pinned for %s and will be used by its deploys", sc.VMVersion, subnetName)
+	}
 	return nil
 }
 
+// checkAllocation prints the initial supply distribution of a Subnet-EVM genesis,
+// warns about suspicious allocations, and fails if the declared max supply is exceeded.
+// Supply mistakes can't be fixed after the chain is created
+func checkAllocation(genesisBytes []byte, tokenSymbol string) error {
+	var maxSupplyWei *big.Int
+	if maxSupply != 0 {
+		maxSupplyWei = new(big.Int).Mul(new(big.Int).SetUint64(maxSupply), big.NewInt(params.Ether))
+	}
+	report, err := vm.GetAllocationReport(genesisBytes, maxSupplyWei)
+	if err != nil {
+		return err
+	}
+	printAllocationReport(report, tokenSymbol)
+	for _, warning := range report.Warnings() {
+		ux.Logger.PrintToUser("WARNING: %s", warning)
+	}
+	if report.ExceedsMaxSupply() {
+		return fmt.Errorf(
+			"genesis total supply %s exceeds declared max supply %s (amounts in wei)",
+			report.TotalSupply,
+			report.MaxSupply,
+		)
+	}
+	return nil
+}
+
+func printAllocationReport(report *vm.AllocationReport, tokenSymbol string) {
+	if tokenSymbol == "" {
+		tokenSymbol = "tokens"
+	}
+	if len(report.Entries) == 0 {
+		ux.Logger.PrintToUser("Genesis has no initial token allocation")
+		return
+	}
+	ux.Logger.PrintToUser("Genesis initial supply allocation:")
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Address", "Amount (" + tokenSymbol + ")", "Amount (wei)", "Share"})
+	table.SetRowLine(true)
+	for _, entry := range report.Entries {
+		table.Append([]string{
+			entry.Address.Hex(),
+			formatTokenAmount(entry.Balance),
+			entry.Balance.String(),
+			fmt.Sprintf("%.4f%%", report.Percentage(entry.Balance)),
+		})
+	}
+	table.SetFooter([]string{
+		"Total",
+		formatTokenAmount(report.TotalSupply),
+		report.TotalSupply.String(),
+		"100%",
+	})
+	table.Render()
+	if report.MaxSupply != nil {
+		ux.Logger.PrintToUser("Declared max supply: %s %s", formatTokenAmount(report.MaxSupply), tokenSymbol)
+	}
+}
+
+// formatTokenAmount converts a wei amount to tokens (10^18 units), keeping decimals
+func formatTokenAmount(amount *big.Int) string {
+	return new(big.Rat).SetFrac(amount, big.NewInt(params.Ether)).FloatString(4)
+}
+
 func sendMetrics(cmd *cobra.Command, repoName, subnetName string) error {
 	flags := make(map[string]string)
 	flags[constants.SubnetType] = repoName
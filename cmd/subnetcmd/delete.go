@@ -68,5 +68,8 @@ func deleteSubnet(_ *cobra.Command, args []string) error {
 	if err := os.RemoveAll(subnetDir); err != nil {
 		return err
 	}
+	if app.GetActiveSubnet() == subnetName {
+		return app.SetActiveSubnet("")
+	}
 	return nil
 }
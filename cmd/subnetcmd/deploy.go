@@ -3,18 +3,23 @@
 package subnetcmd
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
 	"github.com/MetalBlockchain/metal-cli/pkg/binutils"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
 	"github.com/MetalBlockchain/metal-cli/pkg/key"
 	"github.com/MetalBlockchain/metal-cli/pkg/keychain"
+	"github.com/MetalBlockchain/metal-cli/pkg/localnetworkbackend"
 	"github.com/MetalBlockchain/metal-cli/pkg/localnetworkinterface"
 	"github.com/MetalBlockchain/metal-cli/pkg/metrics"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
@@ -31,13 +36,19 @@ import (
 	"github.com/MetalBlockchain/metalgo/utils/logging"
 	"github.com/MetalBlockchain/metalgo/vms/platformvm/txs"
 	"github.com/MetalBlockchain/subnet-evm/params"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 	"golang.org/x/mod/semver"
+	"golang.org/x/term"
 )
 
-var deploySupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Cluster, networkoptions.Tahoe, networkoptions.Mainnet, networkoptions.Devnet}
+var deploySupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Cluster, networkoptions.Tahoe, networkoptions.Mainnet, networkoptions.Devnet, networkoptions.Environment}
+
+// containerDeployTimeout bounds a deploy on a container based local network, that
+// restarts the nodes
+const containerDeployTimeout = 10 * time.Minute
 
 var (
 	sameControlKey           bool
@@ -50,15 +61,21 @@ var (
 	useLedger                bool
 	useEwoq                  bool
 	ledgerAddresses          []string
+	signerSpec               string
 	subnetIDStr              string
 	mainnetChainID           uint32
 	skipCreatePrompt         bool
 	avagoBinaryPath          string
 	skipLocalTeleporter      bool
 	subnetOnly               bool
+	deployLocalNetworkName   string
+	useCompatibleSubnetEVM   bool
+	skipRPCCheck             bool
+	acceptVMBinary           bool
+	fundKeyName              string
 
 	errMutuallyExlusiveControlKeys = errors.New("--control-keys and --same-control-key are mutually exclusive")
-	ErrMutuallyExlusiveKeyLedger   = errors.New("key source flags --key, --ledger/--ledger-addrs are mutually exclusive")
+	ErrMutuallyExlusiveKeyLedger   = errors.New("key source flags --key, --ledger/--ledger-addrs, --signer are mutually exclusive")
 	ErrStoredKeyOnMainnet          = errors.New("key --key is not available for mainnet operations")
 	errMutuallyExlusiveSubnetFlags = errors.New("--subnet-only and --subnet-id are mutually exclusive")
 )
@@ -77,28 +94,46 @@ attempts to deploy the same Subnet to the same network (local, Fuji, Mainnet) ar
 allowed. If you'd like to redeploy a Subnet locally for testing, you must first call
 avalanche network clean to reset all deployed chain state. Subsequent local deploys
 redeploy the chain with fresh state. You can deploy the same Subnet to multiple networks,
-so you can take your locally tested Subnet and deploy it on Fuji or Mainnet.`,
+so you can take your locally tested Subnet and deploy it on Fuji or Mainnet.
+
+Local deploys target the default local network. Use --local-network-name to deploy
+to a named local network (see network start --name) instead.
+
+The version, sha256 checksum and source of the VM binary are recorded in the
+Subnet configuration, and the binary is verified against them before every
+local deploy. If it changed, the deploy fails unless --accept-vm-binary is given.
+
+Fuji and Mainnet deploys use the public API endpoints, unless other ones are
+given with --endpoint, or set with metal config set TahoeAPIEndpoint and
+metal config set MainnetAPIEndpoint.`,
 		SilenceUsage:      true,
-		RunE:              deploySubnet,
+		RunE:              withAnswers(withActiveSubnet(deploySubnet)),
 		PersistentPostRun: handlePostRun,
-		Args:              cobra.ExactArgs(1),
+		Args:              cobra.MaximumNArgs(1),
 	}
 	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, deploySupportedNetworkOptions)
 	cmd.Flags().StringVar(&userProvidedAvagoVersion, "avalanchego-version", "latest", "use this version of avalanchego (ex: v1.17.12)")
 	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use [fuji/devnet deploy only]")
 	cmd.Flags().BoolVarP(&sameControlKey, "same-control-key", "s", false, "use the fee-paying key as control key")
 	cmd.Flags().Uint32Var(&threshold, "threshold", 0, "required number of control key signatures to make subnet changes")
-	cmd.Flags().StringSliceVar(&controlKeys, "control-keys", nil, "addresses that may make subnet changes")
-	cmd.Flags().StringSliceVar(&subnetAuthKeys, "subnet-auth-keys", nil, "control keys that will be used to authenticate chain creation")
+	cmd.Flags().StringSliceVar(&controlKeys, "control-keys", nil, "addresses (or address book labels) that may make subnet changes")
+	cmd.Flags().StringSliceVar(&subnetAuthKeys, "subnet-auth-keys", nil, "control keys (or address book labels) that will be used to authenticate chain creation")
 	cmd.Flags().StringVar(&outputTxPath, "output-tx-path", "", "file path of the blockchain creation tx")
 	cmd.Flags().BoolVarP(&useEwoq, "ewoq", "e", false, "use ewoq key [fuji/devnet deploy only]")
 	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on fuji/devnet)")
 	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	flags.AddSignerFlag(cmd, &signerSpec)
 	cmd.Flags().StringVarP(&subnetIDStr, "subnet-id", "u", "", "do not create a subnet, deploy the blockchain into the given subnet id")
 	cmd.Flags().Uint32Var(&mainnetChainID, "mainnet-chain-id", 0, "use different ChainID for mainnet deployment")
 	cmd.Flags().StringVar(&avagoBinaryPath, "avalanchego-path", "", "use this avalanchego binary path")
 	cmd.Flags().BoolVar(&skipLocalTeleporter, "skip-local-teleporter", false, "skip local teleporter deploy to a local network")
 	cmd.Flags().BoolVar(&subnetOnly, "subnet-only", false, "only create a subnet")
+	cmd.Flags().BoolVar(&useCompatibleSubnetEVM, "use-compatible-subnet-evm", false, "switch to the newest subnet-evm version compatible with the avalanchego to deploy to [local deploy only]")
+	cmd.Flags().BoolVar(&skipRPCCheck, "skip-rpc-check", false, "deploy even if the avalanchego rpc version doesn't match the VM one [local deploy only]")
+	cmd.Flags().BoolVar(&acceptVMBinary, "accept-vm-binary", false, "record the checksum of a VM binary that changed since the last deploy, instead of failing [local deploy only]")
+	flags.AddLocalNetworkNameFlag(cmd, &deployLocalNetworkName, "deploy to this named local network instead of the default one [local deploy only]")
+	cmd.Flags().StringVar(&fundKeyName, "fund-key", "", "fund this stored key on the genesis, with the ewoq key balance, if the genesis only funds ewoq [local deploy only]")
+	addAnswersFlag(cmd)
 	return cmd
 }
 
@@ -109,6 +144,7 @@ func CallDeploy(
 	networkFlags networkoptions.NetworkFlags,
 	keyNameParam string,
 	useLedgerParam bool,
+	signerSpecParam string,
 	useEwoqParam bool,
 	sameControlKeyParam bool,
 ) error {
@@ -117,6 +153,7 @@ func CallDeploy(
 	sameControlKey = sameControlKeyParam
 	keyName = keyNameParam
 	useLedger = useLedgerParam
+	signerSpec = signerSpecParam
 	useEwoq = useEwoqParam
 	return deploySubnet(cmd, []string{subnetName})
 }
@@ -170,6 +207,185 @@ func checkSubnetEVMDefaultAddressNotInAlloc(network models.Network, chain string
 	return nil
 }
 
+// offerLocalGenesisKeyFunding detects subnet-evm genesis that only fund the ewoq test
+// key (besides the teleporter key), and offers to fund one of the user stored keys with
+// the same balance, so local tests can use the same addresses as public deploys. The
+// ewoq key is kept funded, as local tooling pays with it. The subnet genesis is not
+// modified: the funded genesis is written to a copy under the local network run dir,
+// and its path is returned to be used for the local deploy only
+func offerLocalGenesisKeyFunding(
+	network models.Network,
+	sc models.Sidecar,
+	genesisBytes []byte,
+	genesisPath string,
+) ([]byte, string, error) {
+	genesis, err := app.LoadEvmGenesisFromJSON(genesisBytes)
+	if err != nil {
+		return nil, "", err
+	}
+	ewoqAccount, ok := genesis.Alloc[vm.PrefundedEwoqAddress]
+	if !ok {
+		return genesisBytes, genesisPath, nil
+	}
+	if fundKeyName == "" {
+		// local tooling funds the teleporter key on the genesis too
+		ignored := map[common.Address]struct{}{vm.PrefundedEwoqAddress: {}}
+		if sc.TeleporterKey != "" && app.KeyExists(sc.TeleporterKey) {
+			k, err := key.LoadSoft(network.ID, app.GetKeyPath(sc.TeleporterKey))
+			if err != nil {
+				return nil, "", err
+			}
+			ignored[common.HexToAddress(k.C())] = struct{}{}
+		}
+		for address := range genesis.Alloc {
+			if _, ok := ignored[address]; !ok {
+				// the allocation was customized
+				return genesisBytes, genesisPath, nil
+			}
+		}
+		// scripted deploys are not asked
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return genesisBytes, genesisPath, nil
+		}
+		yes, err := app.Prompt.CaptureNoYes(prompts.Keyed("fund-stored-key", fmt.Sprintf(
+			"The genesis of %s only funds the ewoq test key. Do you want to also fund one of your stored keys?", sc.Name)))
+		if errors.Is(err, prompts.ErrNonInteractive) || (err == nil && !yes) {
+			return genesisBytes, genesisPath, nil
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		fundKeyName, err = prompts.CaptureKeyName(app.Prompt, "be funded on the genesis", app.GetKeyDir())
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	if !app.KeyExists(fundKeyName) {
+		return nil, "", fmt.Errorf("key %s does not exist", fundKeyName)
+	}
+	k, err := key.LoadSoft(network.ID, app.GetKeyPath(fundKeyName))
+	if err != nil {
+		return nil, "", err
+	}
+	address := common.HexToAddress(k.C())
+	if _, ok := genesis.Alloc[address]; ok {
+		return genesisBytes, genesisPath, nil
+	}
+	genesisBytes, err = vm.AddGenesisAllocation(genesisBytes, address, ewoqAccount.Balance)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := os.MkdirAll(app.GetRunDir(), constants.DefaultPerms755); err != nil {
+		return nil, "", err
+	}
+	fundedGenesisPath := filepath.Join(app.GetRunDir(), sc.Name+"_"+constants.GenesisFileName)
+	if err := os.WriteFile(fundedGenesisPath, genesisBytes, constants.WriteReadReadPerms); err != nil {
+		return nil, "", err
+	}
+	ux.Logger.PrintToUser("Funded key %s (%s) on the local genesis of %s", fundKeyName, address.Hex(), sc.Name)
+	return genesisBytes, fundedGenesisPath, nil
+}
+
+// setupLocalVMBinary returns the path of the VM binary of [chain], first downloading
+// it if necessary, and verifies it
+func setupLocalVMBinary(sc *models.Sidecar, chain string) (string, error) {
+	var (
+		vmBin string
+		err   error
+	)
+	switch sc.VM {
+	case models.SubnetEvm:
+		_, vmBin, err = binutils.SetupSubnetEVM(app, sc.VMVersion)
+		if err != nil {
+			return "", fmt.Errorf("failed to install subnet-evm: %w", err)
+		}
+	case models.CustomVM:
+		vmBin = binutils.SetupCustomBin(app, chain)
+	default:
+		return "", fmt.Errorf("unknown vm: %s", sc.VM)
+	}
+	if err := verifyVMBinary(sc, vmBin); err != nil {
+		return "", err
+	}
+	return vmBin, nil
+}
+
+// checkLocalDeployCompatibility verifies that the VM of [sc] can run on the local network
+// checked by [nc], switching to a compatible subnet-evm if asked to, and returns the
+// metalgo version to deploy with
+func checkLocalDeployCompatibility(nc localnetworkinterface.StatusChecker, sc *models.Sidecar) (string, error) {
+	avagoVersion, err := CheckForInvalidDeployAndGetAvagoVersion(nc, sc.RPCVersion)
+	var rpcErr *incompatibleRPCError
+	if errors.As(err, &rpcErr) && sc.VM == models.SubnetEvm {
+		if useCompatibleSubnetEVM {
+			if err := switchToCompatibleSubnetEVM(sc, rpcErr.avagoRPCVersion); err != nil {
+				return "", err
+			}
+			avagoVersion, err = CheckForInvalidDeployAndGetAvagoVersion(nc, sc.RPCVersion)
+		} else {
+			err = fmt.Errorf("%w. %s", err, compatibleSubnetEVMHint(rpcErr.avagoRPCVersion))
+		}
+	}
+	return avagoVersion, err
+}
+
+// deployToContainerNetwork deploys [chain] with [chainGenesis] on the local network
+// run by the container based backend [backendName]. The ewoq key pays the txs and
+// owns the subnet, as on the network runner backend
+func deployToContainerNetwork(
+	cmd *cobra.Command,
+	backendName string,
+	network models.Network,
+	sc *models.Sidecar,
+	chain string,
+	chainGenesis []byte,
+) error {
+	if subnetIDStr != "" {
+		return fmt.Errorf("--subnet-id is not supported by the %s local network backend", backendName)
+	}
+	// the VM binary runs inside the node containers
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("deploying subnets on the %s local network backend requires a linux host", backendName)
+	}
+	backend, err := localnetworkbackend.NewContainerBackend(backendName, app.GetRunDir(), app.GetLocalNetworkName())
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), containerDeployTimeout)
+	defer cancel()
+	nodes, err := backend.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		return errors.New("local network is not running. Use 'metal network start' to start it")
+	}
+	// the nodes run the metalgo version of the backend image, that must support the VM rpc version
+	if _, err := checkLocalDeployCompatibility(localnetworkinterface.NewEndpointStatusChecker(nodes[0].URI), sc); err != nil {
+		return err
+	}
+	vmBin, err := setupLocalVMBinary(sc, chain)
+	if err != nil {
+		return err
+	}
+	ewoq, err := key.LoadEwoq(network.ID)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Deploying on the %s backend. The nodes are restarted to track the subnet, wait until healthy...", backendName)
+	deployInfo, err := localnetworkbackend.DeploySubnet(ctx, backend, ewoq.KeyChain(), chain, chainGenesis, vmBin)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Subnet ID:         %s", deployInfo.SubnetID)
+	ux.Logger.PrintToUser("Blockchain ID:     %s", deployInfo.BlockchainID)
+	ux.Logger.PrintToUser("RPC URL:           %s/ext/bc/%s/rpc", deployInfo.Nodes[0].URI, deployInfo.BlockchainID)
+	flags := make(map[string]string)
+	flags[constants.Network] = network.Kind.String()
+	metrics.HandleTracking(cmd, app, flags)
+	return app.UpdateSidecarNetworks(sc, network, deployInfo.SubnetID, ids.Empty, deployInfo.BlockchainID, "", "")
+}
+
 func runDeploy(cmd *cobra.Command, args []string, supportedNetworkOptions []networkoptions.NetworkOption) error {
 	skipCreatePrompt = true
 	deploySupportedNetworkOptions = supportedNetworkOptions
@@ -222,7 +438,7 @@ func getSubnetEVMMainnetChainID(sc *models.Sidecar, subnetName string) error {
 			err      error
 			decision string
 		)
-		decision, err = app.Prompt.CaptureList(newChainIDPrompt, listOptions)
+		decision, err = app.Prompt.CaptureList(prompts.Keyed("chain-id-option", newChainIDPrompt), listOptions)
 		if err != nil {
 			return err
 		}
@@ -231,7 +447,7 @@ func getSubnetEVMMainnetChainID(sc *models.Sidecar, subnetName string) error {
 		} else {
 			ux.Logger.PrintToUser("Enter your subnet's ChainID. It can be any positive integer != %d.", originalChainID)
 			newChainID, err := app.Prompt.CapturePositiveInt(
-				"ChainID",
+				prompts.Keyed("mainnet-chain-id", "ChainID"),
 				[]prompts.Comparator{
 					{
 						Label: "Zero",
@@ -294,6 +510,16 @@ func deploySubnet(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if deployLocalNetworkName != "" && network.Kind != models.Local {
+		return errors.New("--" + flags.LocalNetworkNameFlag + " is only supported for local deploys")
+	}
+	if network.Kind == models.Local {
+		// named local networks have their own endpoint, and their own sidecar entries
+		if err := binutils.SelectLocalNetwork(app, deployLocalNetworkName, true); err != nil {
+			return err
+		}
+		network = models.NewLocalNetwork()
+	}
 
 	isEVMGenesis, err := HasSubnetEVMGenesis(chain)
 	if err != nil {
@@ -328,28 +554,32 @@ func deploySubnet(cmd *cobra.Command, args []string) error {
 
 	ux.Logger.PrintToUser("Deploying %s to %s", chains, network.Name())
 
+	if fundKeyName != "" && network.Kind != models.Local {
+		return errors.New("--fund-key is only supported for local deploys")
+	}
+
 	if network.Kind == models.Local {
 		app.Log.Debug("Deploy local")
 
-		genesisPath := app.GetGenesisPath(chain)
+		// local deploys don't use the key, but giving one here is likely a mistake
+		if err := keychain.CheckKeyPolicy(app, keyName, models.KeyRoleDeployer, network); err != nil {
+			return err
+		}
 
-		// copy vm binary to the expected location, first downloading it if necessary
-		var vmBin string
-		switch sidecar.VM {
-		case models.SubnetEvm:
-			_, vmBin, err = binutils.SetupSubnetEVM(app, sidecar.VMVersion)
+		genesisPath := app.GetGenesisPath(chain)
+		if sidecar.VM == models.SubnetEvm {
+			chainGenesis, genesisPath, err = offerLocalGenesisKeyFunding(network, sidecar, chainGenesis, genesisPath)
 			if err != nil {
-				return fmt.Errorf("failed to install subnet-evm: %w", err)
+				return err
 			}
-		case models.CustomVM:
-			vmBin = binutils.SetupCustomBin(app, chain)
-		default:
-			return fmt.Errorf("unknown vm: %s", sidecar.VM)
+		}
+
+		if backendName := localnetworkbackend.CurrentName(app.GetRunDir()); backendName != localnetworkbackend.ANR {
+			return deployToContainerNetwork(cmd, backendName, network, &sidecar, chain, chainGenesis)
 		}
 
 		// check if selected version matches what is currently running
-		nc := localnetworkinterface.NewStatusChecker()
-		avagoVersion, err := CheckForInvalidDeployAndGetAvagoVersion(nc, sidecar.RPCVersion)
+		avagoVersion, err := checkLocalDeployCompatibility(localnetworkinterface.NewStatusChecker(), &sidecar)
 		if err != nil {
 			return err
 		}
@@ -357,6 +587,11 @@ func deploySubnet(cmd *cobra.Command, args []string) error {
 			userProvidedAvagoVersion = avagoVersion
 		}
 
+		vmBin, err := setupLocalVMBinary(&sidecar, chain)
+		if err != nil {
+			return err
+		}
+
 		deployer := subnet.NewLocalDeployer(app, userProvidedAvagoVersion, avagoBinaryPath, vmBin)
 		deployInfo, err := deployer.DeployToLocalNetwork(chain, chainGenesis, genesisPath, subnetIDStr)
 		if err != nil {
@@ -368,7 +603,7 @@ func deploySubnet(cmd *cobra.Command, args []string) error {
 			return err
 		}
 		flags := make(map[string]string)
-		flags[constants.Network] = network.Name()
+		flags[constants.Network] = network.Kind.String()
 		metrics.HandleTracking(cmd, app, flags)
 		return app.UpdateSidecarNetworks(
 			&sidecar,
@@ -416,11 +651,13 @@ func deploySubnet(cmd *cobra.Command, args []string) error {
 	kc, err := keychain.GetKeychainFromCmdLineFlags(
 		app,
 		constants.PayTxsFeesMsg,
+		models.KeyRoleDeployer,
 		network,
 		keyName,
 		useEwoq,
 		useLedger,
 		ledgerAddresses,
+		signerSpec,
 		fee,
 	)
 	if err != nil {
@@ -462,6 +699,10 @@ func deploySubnet(cmd *cobra.Command, args []string) error {
 
 	// get keys for blockchain tx signing
 	if subnetAuthKeys != nil {
+		subnetAuthKeys, err = app.ResolveAddressBookLabels(subnetAuthKeys)
+		if err != nil {
+			return err
+		}
 		if err := prompts.CheckSubnetAuthKeys(kcKeys, subnetAuthKeys, controlKeys, threshold); err != nil {
 			return err
 		}
@@ -527,6 +768,11 @@ func deploySubnet(cmd *cobra.Command, args []string) error {
 			remainingSubnetAuthKeys,
 			outputTxPath,
 			false,
+			&txutils.OfflineSigningInfo{
+				SubnetID:    subnetID,
+				ControlKeys: controlKeys,
+				Threshold:   threshold,
+			},
 		); err != nil {
 			return err
 		}
@@ -548,11 +794,12 @@ func deploySubnet(cmd *cobra.Command, args []string) error {
 	}
 
 	flags := make(map[string]string)
-	flags[constants.Network] = network.Name()
+	flags[constants.Network] = network.Kind.String()
 	metrics.HandleTracking(cmd, app, flags)
 
 	// update sidecar
 	// TODO: need to do something for backwards compatibility?
+	sidecar.AddTxRecords(network.Name(), deployer.IssuedTxs()...)
 	return app.UpdateSidecarNetworks(&sidecar, network, subnetID, transferSubnetOwnershipTxID, blockchainID, "", "")
 }
 
@@ -582,7 +829,7 @@ func getControlKeys(kc *keychain.Keychain) ([]string, bool, error) {
 		listOptions = []string{feePaying, useAll, custom}
 	}
 
-	listDecision, err := app.Prompt.CaptureList(moreKeysPrompt, listOptions)
+	listDecision, err := app.Prompt.CaptureList(prompts.Keyed("control-keys-source", moreKeysPrompt), listOptions)
 	if err != nil {
 		return nil, false, err
 	}
@@ -696,7 +943,7 @@ func getThreshold(maxLen int) (uint32, error) {
 	for i := 0; i < maxLen; i++ {
 		indexList[i] = strconv.Itoa(i + 1)
 	}
-	threshold, err := app.Prompt.CaptureList("Select required number of control key signatures to make a subnet change", indexList)
+	threshold, err := app.Prompt.CaptureList(prompts.Keyed("threshold", "Select required number of control key signatures to make a subnet change"), indexList)
 	if err != nil {
 		return 0, err
 	}
@@ -739,6 +986,7 @@ func SaveNotFullySignedTx(
 	remainingSubnetAuthKeys []string,
 	outputTxPath string,
 	forceOverwrite bool,
+	signingInfo *txutils.OfflineSigningInfo,
 ) error {
 	signedCount := len(subnetAuthKeys) - len(remainingSubnetAuthKeys)
 	ux.Logger.PrintToUser("")
@@ -753,9 +1001,9 @@ func SaveNotFullySignedTx(
 		ux.Logger.PrintToUser("")
 		var err error
 		if forceOverwrite {
-			outputTxPath, err = app.Prompt.CaptureString("Path to export partially signed tx to")
+			outputTxPath, err = app.Prompt.CaptureString(prompts.Keyed("output-tx-path", "Path to export partially signed tx to"))
 		} else {
-			outputTxPath, err = app.Prompt.CaptureNewFilepath("Path to export partially signed tx to")
+			outputTxPath, err = app.Prompt.CaptureNewFilepath(prompts.Keyed("output-tx-path", "Path to export partially signed tx to"))
 		}
 		if err != nil {
 			return err
@@ -765,7 +1013,7 @@ func SaveNotFullySignedTx(
 		ux.Logger.PrintToUser("")
 		ux.Logger.PrintToUser("Overwriting %s", outputTxPath)
 	}
-	if err := txutils.SaveToDisk(tx, outputTxPath, forceOverwrite); err != nil {
+	if err := txutils.SaveToDiskWithSigningInfo(tx, signingInfo, outputTxPath, forceOverwrite); err != nil {
 		return err
 	}
 	if signedCount == len(subnetAuthKeys) {
@@ -804,6 +1052,8 @@ func PrintRemainingToSignMsg(
 	ux.Logger.PrintToUser("Signing command:")
 	ux.Logger.PrintToUser("  avalanche transaction sign %s --input-tx-filepath %s", chain, outputTxPath)
 	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Add --air-gapped to the signing command to sign on a machine without network access.")
+	ux.Logger.PrintToUser("")
 }
 
 func PrintDeployResults(chain string, subnetID ids.ID, blockchainID ids.ID) error {
@@ -827,6 +1077,85 @@ func PrintDeployResults(chain string, subnetID ids.ID, blockchainID ids.ID) erro
 	return nil
 }
 
+// incompatibleRPCError is returned when the avalanchego version to deploy to uses
+// a different rpc version than the subnet vm
+type incompatibleRPCError struct {
+	avagoVersion    string
+	avagoRPCVersion int
+	vmRPCVersion    int
+	running         bool
+}
+
+func (e *incompatibleRPCError) Error() string {
+	if e.running {
+		return fmt.Sprintf(
+			"the current avalanchego deployment (%s) uses rpc version %d but your subnet has version %d and is not compatible",
+			e.avagoVersion,
+			e.avagoRPCVersion,
+			e.vmRPCVersion,
+		)
+	}
+	return fmt.Sprintf(
+		"avalanchego %s uses rpc version %d but your subnet has version %d and is not compatible",
+		e.avagoVersion,
+		e.avagoRPCVersion,
+		e.vmRPCVersion,
+	)
+}
+
+func (*incompatibleRPCError) ErrorCode() ux.ErrorCode {
+	return ux.ErrCodeIncompatibleVersions
+}
+
+// compatibleSubnetEVMHint suggests the subnet-evm versions that can run on an
+// avalanchego using rpcVersion
+func compatibleSubnetEVMHint(rpcVersion int) string {
+	versions, err := vm.GetSubnetEVMVersionsForRPC(app, rpcVersion)
+	if err != nil {
+		return "Run 'metal network clean' to deploy with the newest compatible avalanchego"
+	}
+	return fmt.Sprintf(
+		"Compatible subnet-evm versions: %s. Deploy with --use-compatible-subnet-evm to switch to %s, "+
+			"or run 'metal network clean' to deploy with the newest compatible avalanchego",
+		strings.Join(versions, ", "),
+		versions[0],
+	)
+}
+
+// verifyVMBinary checks [vmBin] against the checksum recorded in the sidecar, so
+// changes of a cached plugin binary are detected. The checksum is recorded if
+// there is none for the current VM version, or if the change is accepted
+func verifyVMBinary(sc *models.Sidecar, vmBin string) error {
+	recorded, err := vm.CheckVMBinary(*sc, vmBin)
+	switch {
+	case errors.Is(err, vm.ErrVMBinaryChanged) && acceptVMBinary:
+		ux.Logger.PrintToUser("%s. Recording the new checksum as requested", err)
+	case errors.Is(err, vm.ErrVMBinaryChanged):
+		return fmt.Errorf("%w. If the binary was intentionally changed, deploy with --accept-vm-binary", err)
+	case err != nil:
+		return err
+	case recorded:
+		return nil
+	}
+	if err := vm.SetVMBinary(sc, vmBin, vm.GetVMBinarySource(*sc, vmBin)); err != nil {
+		return err
+	}
+	return app.UpdateSidecar(sc)
+}
+
+// switchToCompatibleSubnetEVM updates the sidecar to use the newest subnet-evm
+// version that can run on an avalanchego using rpcVersion
+func switchToCompatibleSubnetEVM(sc *models.Sidecar, rpcVersion int) error {
+	versions, err := vm.GetSubnetEVMVersionsForRPC(app, rpcVersion)
+	if err != nil {
+		return fmt.Errorf("failed to find a subnet-evm version for rpc version %d: %w", rpcVersion, err)
+	}
+	ux.Logger.PrintToUser("Switching subnet-evm version from %s to %s, compatible with rpc version %d", sc.VMVersion, versions[0], rpcVersion)
+	sc.VMVersion = versions[0]
+	sc.RPCVersion = rpcVersion
+	return app.UpdateSidecar(sc)
+}
+
 // Determines the appropriate version of avalanchego to run with. Returns an error if
 // that version conflicts with the current deployment.
 func CheckForInvalidDeployAndGetAvagoVersion(network localnetworkinterface.StatusChecker, configuredRPCVersion int) (string, error) {
@@ -835,30 +1164,49 @@ func CheckForInvalidDeployAndGetAvagoVersion(network localnetworkinterface.Statu
 	if err != nil {
 		return "", err
 	}
+	if !networkRunning && userProvidedAvagoVersion == "latest" {
+		if version := app.Conf.GetConfigStringValue(constants.ConfigAvalancheGoVersionKey); version != "" {
+			ux.Logger.PrintToUser("Using avalanchego version %s set in the CLI config", version)
+			userProvidedAvagoVersion = version
+		}
+	}
 	desiredAvagoVersion := userProvidedAvagoVersion
 
 	// RPC Version was made available in the info API in avalanchego version v1.9.2. For prior versions,
 	// we will need to skip this check.
-	skipRPCCheck := false
-	if semver.Compare(runningAvagoVersion, constants.AvalancheGoCompatibilityVersionAdded) == -1 {
-		skipRPCCheck = true
-	}
+	runningRPCVersionUnknown := semver.Compare(runningAvagoVersion, constants.AvalancheGoCompatibilityVersionAdded) == -1
 
 	if networkRunning {
 		if userProvidedAvagoVersion == "latest" {
-			if runningRPCVersion != configuredRPCVersion && !skipRPCCheck {
-				return "", fmt.Errorf(
-					"the current avalanchego deployment uses rpc version %d but your subnet has version %d and is not compatible",
-					runningRPCVersion,
-					configuredRPCVersion,
-				)
+			if runningRPCVersion != configuredRPCVersion && !skipRPCCheck && !runningRPCVersionUnknown {
+				return "", &incompatibleRPCError{
+					avagoVersion:    runningAvagoVersion,
+					avagoRPCVersion: runningRPCVersion,
+					vmRPCVersion:    configuredRPCVersion,
+					running:         true,
+				}
 			}
 			desiredAvagoVersion = runningAvagoVersion
 		} else if runningAvagoVersion != strings.Split(userProvidedAvagoVersion, "-")[0] {
 			// user wants a specific version
 			return "", errors.New("incompatible avalanchego version selected")
 		}
-	} else if userProvidedAvagoVersion == "latest" {
+	} else if userProvidedAvagoVersion != "latest" {
+		// user wants a specific version, verify it can run the subnet vm. versions
+		// not found in the compatibility file (eg older ones) can't be checked
+		if !skipRPCCheck {
+			avagoRPCVersion, err := vm.GetAvalancheGoRPCVersion(app, userProvidedAvagoVersion, constants.AvalancheGoCompatibilityURL)
+			if err != nil {
+				app.Log.Debug("unable to determine avalanchego rpc version", zap.String("version", userProvidedAvagoVersion), zap.Error(err))
+			} else if avagoRPCVersion != configuredRPCVersion {
+				return "", &incompatibleRPCError{
+					avagoVersion:    userProvidedAvagoVersion,
+					avagoRPCVersion: avagoRPCVersion,
+					vmRPCVersion:    configuredRPCVersion,
+				}
+			}
+		}
+	} else {
 		// find latest avago version for this rpc version
 		desiredAvagoVersion, err = vm.GetLatestAvalancheGoByProtocolVersion(
 			app, configuredRPCVersion, constants.AvalancheGoCompatibilityURL)
@@ -934,6 +1282,13 @@ func promptOwners(
 		}
 		controlKeys = kcKeys[:1]
 	}
+	// control keys given by flag may reference address book labels
+	if controlKeys != nil {
+		controlKeys, err = app.ResolveAddressBookLabels(controlKeys)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
 	// prompt for control keys
 	if controlKeys == nil {
 		var cancelled bool
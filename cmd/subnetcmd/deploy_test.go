@@ -105,6 +105,7 @@ func TestCheckForInvalidDeployAndSetAvagoVersion(t *testing.T) {
 		expectError     bool
 		expectedVersion string
 		compatError     error
+		skipRPCCheck    bool
 	}
 
 	tests := []test{
@@ -167,6 +168,71 @@ func TestCheckForInvalidDeployAndSetAvagoVersion(t *testing.T) {
 			compatError:     errors.New("no compat"),
 			networkUp:       false,
 		},
+		{
+			name:            "network stopped, specific version matches",
+			networkRPC:      0,
+			networkVersion:  "",
+			networkErr:      nil,
+			desiredRPC:      18,
+			desiredVersion:  testAvagoVersion2,
+			expectError:     false,
+			expectedVersion: testAvagoVersion2,
+			compatData:      testAvagoCompat,
+			compatError:     nil,
+			networkUp:       false,
+		},
+		{
+			name:            "network stopped, specific version mismatch",
+			networkRPC:      0,
+			networkVersion:  "",
+			networkErr:      nil,
+			desiredRPC:      19,
+			desiredVersion:  testAvagoVersion2,
+			expectError:     true,
+			expectedVersion: "",
+			compatData:      testAvagoCompat,
+			compatError:     nil,
+			networkUp:       false,
+		},
+		{
+			name:            "network stopped, specific version mismatch, rpc check skipped",
+			networkRPC:      0,
+			networkVersion:  "",
+			networkErr:      nil,
+			desiredRPC:      19,
+			desiredVersion:  testAvagoVersion2,
+			expectError:     false,
+			expectedVersion: testAvagoVersion2,
+			compatData:      testAvagoCompat,
+			compatError:     nil,
+			networkUp:       false,
+			skipRPCCheck:    true,
+		},
+		{
+			name:            "network already running, rpc mismatch, rpc check skipped",
+			networkRPC:      18,
+			networkVersion:  testAvagoVersion1,
+			networkErr:      nil,
+			desiredRPC:      19,
+			desiredVersion:  testLatestAvagoVersion,
+			expectError:     false,
+			expectedVersion: testAvagoVersion1,
+			networkUp:       true,
+			skipRPCCheck:    true,
+		},
+		{
+			name:            "network stopped, specific version not in compat",
+			networkRPC:      0,
+			networkVersion:  "",
+			networkErr:      nil,
+			desiredRPC:      19,
+			desiredVersion:  "v1.7.0",
+			expectError:     false,
+			expectedVersion: "v1.7.0",
+			compatData:      testAvagoCompat,
+			compatError:     nil,
+			networkUp:       false,
+		},
 		{
 			name:            "network up, network err",
 			networkRPC:      0,
@@ -190,6 +256,7 @@ func TestCheckForInvalidDeployAndSetAvagoVersion(t *testing.T) {
 			mockSC.On("GetCurrentNetworkVersion").Return(tt.networkVersion, tt.networkRPC, tt.networkUp, tt.networkErr)
 
 			userProvidedAvagoVersion = tt.desiredVersion
+			skipRPCCheck = tt.skipRPCCheck
 
 			mockDownloader := &mocks.Downloader{}
 			mockDownloader.On("Download", mock.Anything).Return(tt.compatData, nil)
@@ -8,7 +8,9 @@ import (
 	"math"
 	"math/big"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
 	"github.com/MetalBlockchain/metal-cli/pkg/key"
@@ -33,9 +35,13 @@ import (
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
 )
 
-var printGenesisOnly bool
+var (
+	printGenesisOnly bool
+	printTxHistory   bool
+)
 
 // avalanche subnet describe
 func newDescribeCmd() *cobra.Command {
@@ -44,9 +50,10 @@ func newDescribeCmd() *cobra.Command {
 		Short: "Print a summary of the subnet’s configuration",
 		Long: `The subnet describe command prints the details of a Subnet configuration to the console.
 By default, the command prints a summary of the configuration. By providing the --genesis
-flag, the command instead prints out the raw genesis file.`,
-		RunE: readGenesis,
-		Args: cobra.ExactArgs(1),
+flag, the command instead prints out the raw genesis file. By providing the --history flag,
+the command prints the P-Chain transactions issued for the Subnet.`,
+		RunE: withActiveSubnet(readGenesis),
+		Args: cobra.MaximumNArgs(1),
 	}
 	cmd.Flags().BoolVarP(
 		&printGenesisOnly,
@@ -55,6 +62,12 @@ flag, the command instead prints out the raw genesis file.`,
 		false,
 		"Print the genesis to the console directly instead of the summary",
 	)
+	cmd.Flags().BoolVar(
+		&printTxHistory,
+		"history",
+		false,
+		"Print the P-Chain transactions issued for the subnet instead of the summary",
+	)
 	return cmd
 }
 
@@ -71,6 +84,32 @@ func printGenesis(sc models.Sidecar, subnetName string) error {
 	return nil
 }
 
+func printHistory(sc models.Sidecar) {
+	if len(sc.TxHistory) == 0 {
+		ux.Logger.PrintToUser("No transactions recorded for subnet %s", sc.Subnet)
+		return
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	header := []string{"Network", "Tx Type", "Tx ID", "Timestamp", "Issuer"}
+	table.SetHeader(header)
+	table.SetRowLine(true)
+	table.SetAutoMergeCellsByColumnIndex([]int{0})
+	networkNames := maps.Keys(sc.TxHistory)
+	sort.Strings(networkNames)
+	for _, networkName := range networkNames {
+		for _, record := range sc.TxHistory[networkName] {
+			table.Append([]string{
+				networkName,
+				record.TxType,
+				record.TxID.String(),
+				record.Timestamp.Format(constants.TimeParseLayout),
+				record.Issuer,
+			})
+		}
+	}
+	table.Render()
+}
+
 func printDetails(genesis core.Genesis, sc models.Sidecar) error {
 	const art = `
  _____       _        _ _
@@ -95,7 +134,24 @@ func printDetails(genesis core.Genesis, sc models.Sidecar) error {
 	}
 	table.Append([]string{"Token Name", app.GetTokenName(sc.Subnet)})
 	table.Append([]string{"Token Symbol", app.GetTokenSymbol(sc.Subnet)})
+	if initialSupply, ok := new(big.Int).SetString(sc.TokenInitialSupply, 10); ok {
+		table.Append([]string{"Token Initial Supply", formatTokenAmount(initialSupply)})
+	}
+	if sc.VM == models.SubnetEvm {
+		mintable := "No"
+		if sc.TokenMintable {
+			mintable = "Yes"
+			if len(sc.TokenMintAdmins) > 0 {
+				mintable += ", admins: " + strings.Join(sc.TokenMintAdmins, ", ")
+			}
+		}
+		table.Append([]string{"Token Mintable", mintable})
+	}
 	table.Append([]string{"VM Version", sc.VMVersion})
+	if sc.VMBinary.SHA256 != "" {
+		table.Append([]string{"VM Binary SHA256", sc.VMBinary.SHA256})
+		table.Append([]string{"VM Binary Source", sc.VMBinary.Source})
+	}
 	if sc.ImportedVMID != "" {
 		table.Append([]string{"VM ID", sc.ImportedVMID})
 	} else {
@@ -187,7 +243,7 @@ func printAirdropTable(genesis core.Genesis, sc models.Sidecar) error {
 	fmt.Print(logging.LightBlue.Wrap(art))
 	teleporterKeyAddress := ""
 	teleporterPrivKey := ""
-	if sc.TeleporterReady {
+	if sc.TeleporterReady && sc.TeleporterKey != "" {
 		k, err := key.LoadSoft(models.NewLocalNetwork().ID, app.GetKeyPath(sc.TeleporterKey))
 		if err != nil {
 			return err
@@ -356,6 +412,10 @@ func readGenesis(_ *cobra.Command, args []string) error {
 	if printGenesisOnly {
 		return printGenesis(sc, subnetName)
 	}
+	if printTxHistory {
+		printHistory(sc)
+		return nil
+	}
 
 	isEVM, err := HasSubnetEVMGenesis(subnetName)
 	if err != nil {
@@ -0,0 +1,362 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/subnet-evm/precompile/contracts/deployerallowlist"
+	"github.com/MetalBlockchain/subnet-evm/precompile/contracts/feemanager"
+	"github.com/MetalBlockchain/subnet-evm/precompile/contracts/nativeminter"
+	"github.com/MetalBlockchain/subnet-evm/precompile/contracts/rewardmanager"
+	"github.com/MetalBlockchain/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/MetalBlockchain/subnet-evm/precompile/contracts/warp"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	diffCategoryAllocations = "Allocations"
+	diffCategoryFeeConfig   = "Fee Config"
+	diffCategoryPrecompiles = "Precompiles"
+	diffCategoryChainParams = "Chain Params"
+	diffCategoryGenesis     = "Genesis Block"
+	diffCategoryChainConfig = "Chain Config"
+)
+
+var (
+	diffAgainstFile    string
+	diffChainConfig    bool
+	diffCategoryOrder  = []string{diffCategoryAllocations, diffCategoryFeeConfig, diffCategoryPrecompiles, diffCategoryChainParams, diffCategoryGenesis, diffCategoryChainConfig}
+	diffPrecompileKeys = []string{warp.ConfigKey, nativeminter.ConfigKey, deployerallowlist.ConfigKey, txallowlist.ConfigKey, feemanager.ConfigKey, rewardmanager.ConfigKey}
+	// genesis fields holding quantities, compared by value whatever their encoding
+	diffQuantityFields = []string{"balance", "nonce", "gasLimit", "difficulty", "timestamp", "number", "gasUsed", "baseFeePerGas"}
+)
+
+// avalanche subnet diff
+func newDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff [subnetName]",
+		Short: "Compare the subnet genesis or chain config with a file",
+		Long: `The subnet diff command prints the differences between the genesis of a Subnet
+and the genesis file given by --against, grouped by allocations, fee config,
+precompiles and other chain params. With --chain-config, the Subnet chain config
+is compared instead.
+
+Each change is flagged by whether it can be applied once the Subnet is launched.
+The genesis of a live chain can't be changed: allocations and chain params only
+take effect on a new chain, while fee config and precompile changes can be
+applied with a precompile upgrade (see subnet upgrade generate). Chain config
+changes are applied by restarting the validators.`,
+		RunE:         withActiveSubnet(diffSubnet),
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&diffAgainstFile, "against", "", "genesis or chain config file to compare the subnet with")
+	cmd.Flags().BoolVar(&diffChainConfig, "chain-config", false, "compare the chain config of the subnet instead of its genesis")
+	flags.AddJSONOutputFlag(cmd)
+	return cmd
+}
+
+// genesisChange is a difference between two genesis or chain config files
+type genesisChange struct {
+	Category string `json:"category"`
+	Field    string `json:"field"`
+	// Current and Against are empty if the field is not set in the file
+	Current string `json:"current,omitempty"`
+	Against string `json:"against,omitempty"`
+	// Safe is true if the change can be applied once the chain is launched
+	Safe bool   `json:"safe"`
+	Note string `json:"note"`
+}
+
+type subnetDiff struct {
+	Subnet   string          `json:"subnet"`
+	File     string          `json:"file"`
+	Against  string          `json:"against"`
+	Deployed []string        `json:"deployedOn"`
+	Changes  []genesisChange `json:"changes"`
+}
+
+func diffSubnet(_ *cobra.Command, args []string) error {
+	if diffAgainstFile == "" {
+		return fmt.Errorf("a file to compare with must be given with --against")
+	}
+	subnetName := args[0]
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	currentPath := app.GetGenesisPath(subnetName)
+	if diffChainConfig {
+		currentPath = app.GetChainConfigPath(subnetName)
+	}
+	current, err := os.ReadFile(currentPath)
+	switch {
+	case diffChainConfig && os.IsNotExist(err):
+		current = []byte("{}")
+	case err != nil:
+		return err
+	}
+	against, err := os.ReadFile(diffAgainstFile)
+	if err != nil {
+		return err
+	}
+	var changes []genesisChange
+	if diffChainConfig {
+		changes, err = diffChainConfigs(current, against)
+	} else {
+		changes, err = diffGenesis(current, against)
+	}
+	if err != nil {
+		return err
+	}
+	deployed := []string{}
+	for networkName, networkData := range sc.Networks {
+		if networkData.SubnetID != ids.Empty {
+			deployed = append(deployed, networkName)
+		}
+	}
+	sort.Strings(deployed)
+	diff := subnetDiff{
+		Subnet:   subnetName,
+		File:     currentPath,
+		Against:  diffAgainstFile,
+		Deployed: deployed,
+		Changes:  changes,
+	}
+	if ux.JSONOutput() {
+		return ux.PrintResult(diff)
+	}
+	printSubnetDiff(diff)
+	return nil
+}
+
+func printSubnetDiff(diff subnetDiff) {
+	ux.Logger.PrintToUser("Comparing %s with %s", diff.File, diff.Against)
+	if len(diff.Changes) == 0 {
+		ux.Logger.PrintToUser("No differences found")
+		return
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Category", "Field", "Current", "Against", "Post-launch"})
+	table.SetAutoMergeCellsByColumnIndex([]int{0})
+	table.SetRowLine(true)
+	unsafeChanges := 0
+	for _, change := range diff.Changes {
+		applicability := "ok"
+		if !change.Safe {
+			applicability = "NOT SAFE"
+			unsafeChanges++
+		}
+		table.Append([]string{
+			change.Category,
+			change.Field,
+			diffValueOrDash(change.Current),
+			diffValueOrDash(change.Against),
+			applicability + ": " + change.Note,
+		})
+	}
+	table.Render()
+	ux.Logger.PrintToUser("%d change(s), %d of them not safely applicable post-launch", len(diff.Changes), unsafeChanges)
+	if unsafeChanges > 0 {
+		if len(diff.Deployed) > 0 {
+			ux.Logger.PrintToUser("Warning: subnet %s is already deployed on %s", diff.Subnet, strings.Join(diff.Deployed, ", "))
+		} else {
+			ux.Logger.PrintToUser("Subnet %s is not deployed yet, so its genesis can still be replaced", diff.Subnet)
+		}
+	}
+}
+
+func diffValueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// diffGenesis returns the changes from the [current] genesis to the [against]
+// one, classified by whether they can be applied to a launched chain
+func diffGenesis(current, against []byte) ([]genesisChange, error) {
+	currentFields, err := flattenJSON(current)
+	if err != nil {
+		return nil, fmt.Errorf("invalid genesis: %w", err)
+	}
+	againstFields, err := flattenJSON(against)
+	if err != nil {
+		return nil, fmt.Errorf("invalid genesis to compare with: %w", err)
+	}
+	feeManagerEnabled := hasFieldWithPrefix(currentFields, "config."+feemanager.ConfigKey+".")
+	changes := diffFields(currentFields, againstFields, func(field string) (string, bool, string) {
+		return classifyGenesisField(field, feeManagerEnabled)
+	})
+	return changes, nil
+}
+
+// diffChainConfigs returns the changes from the [current] chain config to the
+// [against] one
+func diffChainConfigs(current, against []byte) ([]genesisChange, error) {
+	currentFields, err := flattenJSON(current)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chain config: %w", err)
+	}
+	againstFields, err := flattenJSON(against)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chain config to compare with: %w", err)
+	}
+	return diffFields(currentFields, againstFields, func(string) (string, bool, string) {
+		return diffCategoryChainConfig, true, "applied on validator restart"
+	}), nil
+}
+
+func diffFields(
+	currentFields map[string]string,
+	againstFields map[string]string,
+	classify func(string) (string, bool, string),
+) []genesisChange {
+	fields := map[string]struct{}{}
+	for field := range currentFields {
+		fields[field] = struct{}{}
+	}
+	for field := range againstFields {
+		fields[field] = struct{}{}
+	}
+	changes := []genesisChange{}
+	for field := range fields {
+		if currentFields[field] == againstFields[field] {
+			continue
+		}
+		category, safe, note := classify(field)
+		changes = append(changes, genesisChange{
+			Category: category,
+			Field:    field,
+			Current:  currentFields[field],
+			Against:  againstFields[field],
+			Safe:     safe,
+			Note:     note,
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		ci := slices.Index(diffCategoryOrder, changes[i].Category)
+		cj := slices.Index(diffCategoryOrder, changes[j].Category)
+		if ci != cj {
+			return ci < cj
+		}
+		return changes[i].Field < changes[j].Field
+	})
+	return changes
+}
+
+// classifyGenesisField returns the category of a genesis field, and whether,
+// and how, a change of it can be applied to a launched chain
+func classifyGenesisField(field string, feeManagerEnabled bool) (string, bool, string) {
+	switch {
+	case strings.HasPrefix(field, "alloc."):
+		return diffCategoryAllocations, false, "allocations only take effect on a new chain"
+	case strings.HasPrefix(field, "config.feeConfig."):
+		if feeManagerEnabled {
+			return diffCategoryFeeConfig, true, "set it with the fee manager precompile"
+		}
+		return diffCategoryFeeConfig, true, "needs a precompile upgrade enabling the fee manager"
+	case strings.HasPrefix(field, "config."):
+		key := strings.SplitN(strings.TrimPrefix(field, "config."), ".", 2)[0]
+		if slices.Contains(diffPrecompileKeys, key) {
+			return diffCategoryPrecompiles, true, "needs a precompile upgrade"
+		}
+		return diffCategoryChainParams, false, "chain params only take effect on a new chain"
+	default:
+		return diffCategoryGenesis, false, "the genesis block of a live chain can't change"
+	}
+}
+
+func hasFieldWithPrefix(fields map[string]string, prefix string) bool {
+	for field := range fields {
+		if strings.HasPrefix(field, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// flattenJSON returns the leaf values of a JSON object, keyed by their dot
+// separated path. Allocation addresses and quantities are normalized so that
+// equivalent encodings compare equal
+func flattenJSON(bs []byte) (map[string]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(bs))
+	decoder.UseNumber()
+	var root map[string]interface{}
+	if err := decoder.Decode(&root); err != nil {
+		return nil, err
+	}
+	fields := map[string]string{}
+	if err := flattenJSONValue(fields, "", root); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func flattenJSONValue(fields map[string]string, path string, value interface{}) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if path == "alloc" {
+				key = strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(key, "0x"), "0X"))
+			}
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			if err := flattenJSONValue(fields, childPath, child); err != nil {
+				return err
+			}
+		}
+		if len(v) == 0 && path != "" {
+			fields[path] = "{}"
+		}
+		return nil
+	case nil:
+		return nil
+	case string:
+		fields[path] = normalizeDiffValue(path, v)
+		return nil
+	case json.Number:
+		fields[path] = normalizeDiffValue(path, v.String())
+		return nil
+	default:
+		bs, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		fields[path] = string(bs)
+		return nil
+	}
+}
+
+// normalizeDiffValue returns quantity fields in decimal
+func normalizeDiffValue(path string, value string) string {
+	field := path[strings.LastIndex(path, ".")+1:]
+	if !slices.Contains(diffQuantityFields, field) {
+		return value
+	}
+	n := new(big.Int)
+	if strings.HasPrefix(value, "0x") || strings.HasPrefix(value, "0X") {
+		if _, ok := n.SetString(value[2:], 16); ok {
+			return n.String()
+		}
+		return value
+	}
+	if _, ok := n.SetString(value, 10); ok {
+		return n.String()
+	}
+	return value
+}
@@ -0,0 +1,124 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const diffTestGenesis = `{
+  "config": {
+    "chainId": 12345,
+    "feeConfig": {"gasLimit": 8000000, "minBaseFee": 25000000000},
+    "txAllowListConfig": {"blockTimestamp": 0, "adminAddresses": ["0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"]}
+  },
+  "alloc": {
+    "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC": {"balance": "0x52B7D2DCC80CD2E4000000"}
+  },
+  "gasLimit": "0x7A1200",
+  "timestamp": "0x0"
+}`
+
+func TestDiffGenesis(t *testing.T) {
+	require := require.New(t)
+
+	changes, err := diffGenesis([]byte(diffTestGenesis), []byte(diffTestGenesis))
+	require.NoError(err)
+	require.Empty(changes)
+
+	// same values with other encodings
+	changes, err = diffGenesis([]byte(diffTestGenesis), []byte(`{
+  "config": {
+    "chainId": 12345,
+    "feeConfig": {"gasLimit": 8000000, "minBaseFee": 25000000000},
+    "txAllowListConfig": {"blockTimestamp": 0, "adminAddresses": ["0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"]}
+  },
+  "alloc": {
+    "8db97c7cece249c2b98bdc0226cc4c2a57bf52fc": {"balance": "100000000000000000000000000"}
+  },
+  "gasLimit": "8000000",
+  "timestamp": 0
+}`))
+	require.NoError(err)
+	require.Empty(changes)
+
+	changes, err = diffGenesis([]byte(diffTestGenesis), []byte(`{
+  "config": {
+    "chainId": 54321,
+    "feeConfig": {"gasLimit": 15000000, "minBaseFee": 25000000000},
+    "txAllowListConfig": {"blockTimestamp": 0, "adminAddresses": ["0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"]},
+    "contractNativeMinterConfig": {"blockTimestamp": 0}
+  },
+  "alloc": {
+    "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC": {"balance": "0x52B7D2DCC80CD2E4000000"},
+    "0x0000000000000000000000000000000000000001": {"balance": "0x1"}
+  },
+  "gasLimit": "0x7A1200",
+  "timestamp": "0x0"
+}`))
+	require.NoError(err)
+	require.Equal([]genesisChange{
+		{
+			Category: diffCategoryAllocations,
+			Field:    "alloc.0000000000000000000000000000000000000001.balance",
+			Against:  "1",
+			Note:     "allocations only take effect on a new chain",
+		},
+		{
+			Category: diffCategoryFeeConfig,
+			Field:    "config.feeConfig.gasLimit",
+			Current:  "8000000",
+			Against:  "15000000",
+			Safe:     true,
+			Note:     "needs a precompile upgrade enabling the fee manager",
+		},
+		{
+			Category: diffCategoryPrecompiles,
+			Field:    "config.contractNativeMinterConfig.blockTimestamp",
+			Against:  "0",
+			Safe:     true,
+			Note:     "needs a precompile upgrade",
+		},
+		{
+			Category: diffCategoryChainParams,
+			Field:    "config.chainId",
+			Current:  "12345",
+			Against:  "54321",
+			Note:     "chain params only take effect on a new chain",
+		},
+	}, changes)
+
+	_, err = diffGenesis([]byte(diffTestGenesis), []byte("{"))
+	require.ErrorContains(err, "invalid genesis to compare with")
+}
+
+func TestClassifyGenesisField(t *testing.T) {
+	require := require.New(t)
+	category, safe, note := classifyGenesisField("config.feeConfig.minBaseFee", true)
+	require.Equal(diffCategoryFeeConfig, category)
+	require.True(safe)
+	require.Equal("set it with the fee manager precompile", note)
+
+	category, safe, _ = classifyGenesisField("config.txAllowListConfig.adminAddresses", false)
+	require.Equal(diffCategoryPrecompiles, category)
+	require.True(safe)
+
+	category, safe, _ = classifyGenesisField("difficulty", false)
+	require.Equal(diffCategoryGenesis, category)
+	require.False(safe)
+}
+
+func TestDiffChainConfigs(t *testing.T) {
+	require := require.New(t)
+	changes, err := diffChainConfigs([]byte(`{"pruning-enabled": true, "log-level": "info"}`), []byte(`{"pruning-enabled": false}`))
+	require.NoError(err)
+	require.Len(changes, 2)
+	require.Equal("log-level", changes[0].Field)
+	require.Equal("info", changes[0].Current)
+	require.Empty(changes[0].Against)
+	require.Equal("pruning-enabled", changes[1].Field)
+	require.Equal("false", changes[1].Against)
+	require.True(changes[1].Safe)
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
 	es "github.com/MetalBlockchain/metal-cli/pkg/elasticsubnet"
 	"github.com/MetalBlockchain/metal-cli/pkg/keychain"
@@ -47,6 +48,21 @@ var (
 	overrideWarning                bool
 	transformValidators            bool
 	denominationFlag               int
+	elasticConfigFlags             = es.GetDefaultElasticSubnetConfig()
+	elasticConfigFlagNames         = []string{
+		"initial-supply",
+		"max-supply",
+		"min-consumption-rate",
+		"max-consumption-rate",
+		"min-validator-stake",
+		"max-validator-stake",
+		"min-stake-duration",
+		"max-stake-duration",
+		"min-delegation-fee",
+		"min-delegator-stake",
+		"max-validator-weight-factor",
+		"uptime-requirement",
+	}
 )
 
 // avalanche subnet elastic
@@ -58,10 +74,13 @@ func newElasticCmd() *cobra.Command {
 P-Chain. When enabling Elastic Validation, the creator permanently locks the Subnet from future modification 
 (they relinquish their control keys), specifies an Avalanche Native Token (ANT) that validators must use for staking 
 and that will be distributed as staking rewards, and provides a set of parameters that govern how the Subnet’s staking 
-mechanics will work.`,
+mechanics will work.
+
+The staking parameters are prompted for, unless any of them is given by flag (eg --initial-supply,
+--min-validator-stake, --min-stake-duration), in which case the remaining ones keep their default values.`,
 		SilenceUsage:      true,
-		Args:              cobra.ExactArgs(1),
-		RunE:              transformElasticSubnet,
+		Args:              cobra.MaximumNArgs(1),
+		RunE:              withActiveSubnet(transformElasticSubnet),
 		PersistentPostRun: handlePostRun,
 	}
 	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, elasticSupportedNetworkOptions)
@@ -70,20 +89,47 @@ mechanics will work.`,
 	cmd.Flags().BoolVar(&useDefaultConfig, "default", false, "use default elastic subnet config values")
 	cmd.Flags().BoolVar(&overrideWarning, "force", false, "override transform into elastic subnet warning")
 	cmd.Flags().Uint64Var(&stakeAmount, "stake-amount", 0, "amount of tokens to stake on validator")
-	cmd.Flags().StringVar(&startTimeStr, "start-time", "", "start time that validator starts validating")
+	cmd.Flags().StringVar(&startTimeStr, "start-time", "", "start time that validator starts validating, in 'YYYY-MM-DD HH:MM:SS' format (UTC unless --timezone is given), or RFC3339 format with offset")
+	cmd.Flags().StringVar(&startTimeZone, "timezone", "", "time zone of --start-time, and of the start time prompt (ex: Europe/Paris, local) (default UTC)")
 	cmd.Flags().DurationVar(&duration, "staking-period", 0, "how long validator validates for after start time")
 	cmd.Flags().BoolVar(&transformValidators, "transform-validators", false, "transform validators to permissionless validators")
 	cmd.Flags().IntVar(&denominationFlag, "denomination", -1, "specify the token denomination")
 	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on fuji)")
 	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	flags.AddSignerFlag(cmd, &signerSpec)
 	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use [fuji only]")
 	cmd.Flags().StringSliceVar(&subnetAuthKeys, "subnet-auth-keys", nil, "control keys that will be used to authenticate the transformSubnet tx")
 	cmd.Flags().StringVar(&outputTxPath, "output-tx-path", "", "file path of the transformSubnet tx")
+	cmd.Flags().Uint64Var(&elasticConfigFlags.InitialSupply, "initial-supply", elasticConfigFlags.InitialSupply, "initial supply of the subnet token")
+	cmd.Flags().Uint64Var(&elasticConfigFlags.MaxSupply, "max-supply", elasticConfigFlags.MaxSupply, "maximum supply of the subnet token")
+	cmd.Flags().Uint64Var(&elasticConfigFlags.MinConsumptionRate, "min-consumption-rate", elasticConfigFlags.MinConsumptionRate, "minimum consumption rate, denominated in PercentDenominator (1% = 10_000)")
+	cmd.Flags().Uint64Var(&elasticConfigFlags.MaxConsumptionRate, "max-consumption-rate", elasticConfigFlags.MaxConsumptionRate, "maximum consumption rate, denominated in PercentDenominator (1% = 10_000)")
+	cmd.Flags().Uint64Var(&elasticConfigFlags.MinValidatorStake, "min-validator-stake", elasticConfigFlags.MinValidatorStake, "minimum stake of a validator")
+	cmd.Flags().Uint64Var(&elasticConfigFlags.MaxValidatorStake, "max-validator-stake", elasticConfigFlags.MaxValidatorStake, "maximum stake of a validator")
+	cmd.Flags().DurationVar(&elasticConfigFlags.MinStakeDuration, "min-stake-duration", elasticConfigFlags.MinStakeDuration, "minimum staking period")
+	cmd.Flags().DurationVar(&elasticConfigFlags.MaxStakeDuration, "max-stake-duration", elasticConfigFlags.MaxStakeDuration, "maximum staking period")
+	cmd.Flags().Uint32Var(&elasticConfigFlags.MinDelegationFee, "min-delegation-fee", elasticConfigFlags.MinDelegationFee, "minimum delegation fee, denominated in PercentDenominator (1% = 10_000)")
+	cmd.Flags().Uint64Var(&elasticConfigFlags.MinDelegatorStake, "min-delegator-stake", elasticConfigFlags.MinDelegatorStake, "minimum stake of a delegator")
+	cmd.Flags().Uint8Var(&elasticConfigFlags.MaxValidatorWeightFactor, "max-validator-weight-factor", elasticConfigFlags.MaxValidatorWeightFactor, "maximum delegated weight of a validator, as a factor of its stake")
+	cmd.Flags().Uint32Var(&elasticConfigFlags.UptimeRequirement, "uptime-requirement", elasticConfigFlags.UptimeRequirement, "uptime required to get rewards, denominated in PercentDenominator (1% = 10_000)")
 	return cmd
 }
 
+// elasticConfigFromFlags returns true if any elastic config parameter was given by flag
+func elasticConfigFromFlags(cmd *cobra.Command) bool {
+	if cmd == nil {
+		return false
+	}
+	for _, flagName := range elasticConfigFlagNames {
+		if cmd.Flags().Changed(flagName) {
+			return true
+		}
+	}
+	return false
+}
+
 func checkIfSubnetIsElasticOnLocal(sc models.Sidecar) bool {
-	if _, ok := sc.ElasticSubnet[models.Local.String()]; ok {
+	if _, ok := sc.ElasticSubnet[models.NewLocalNetwork().Name()]; ok {
 		return true
 	}
 	return false
@@ -175,13 +221,13 @@ func transformElasticSubnet(cmd *cobra.Command, args []string) error {
 		useLedger = true
 	}
 
-	if useLedger && keyName != "" {
+	if !flags.EnsureMutuallyExclusive([]bool{useLedger, keyName != "", signerSpec != ""}) {
 		return ErrMutuallyExlusiveKeyLedger
 	}
 
 	subnetID := sc.Networks[network.Name()].SubnetID
 	if os.Getenv(constants.SimulatePublicNetwork) != "" {
-		subnetID = sc.Networks[models.Local.String()].SubnetID
+		subnetID = sc.Networks[models.NewLocalNetwork().Name()].SubnetID
 	}
 	if subnetID == ids.Empty {
 		return errNoSubnetID
@@ -229,9 +275,18 @@ func transformElasticSubnet(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	elasticSubnetConfig, err := es.GetElasticSubnetConfig(app, tokenSymbol, useDefaultConfig)
-	if err != nil {
-		return err
+	var elasticSubnetConfig models.ElasticSubnetConfig
+	if elasticConfigFromFlags(cmd) {
+		// parameters not given by flag keep their default values
+		elasticSubnetConfig = elasticConfigFlags
+	} else {
+		elasticSubnetConfig, err = es.GetElasticSubnetConfig(app, tokenSymbol, useDefaultConfig)
+		if err != nil {
+			return err
+		}
+	}
+	if err := es.ValidateElasticSubnetConfig(elasticSubnetConfig); err != nil {
+		return fmt.Errorf("invalid elastic subnet config: %w", err)
 	}
 	elasticSubnetConfig.SubnetID = subnetID
 
@@ -239,7 +294,7 @@ func transformElasticSubnet(cmd *cobra.Command, args []string) error {
 	case models.Local:
 		return transformElasticSubnetLocal(sc, subnetName, tokenName, tokenSymbol, elasticSubnetConfig, cmd)
 	case models.Tahoe:
-		if !useLedger && keyName == "" {
+		if !useLedger && keyName == "" && signerSpec == "" {
 			useLedger, keyName, err = prompts.GetFujiKeyOrLedger(app.Prompt, constants.PayTxsFeesMsg, app.GetKeyDir())
 			if err != nil {
 				return err
@@ -255,7 +310,7 @@ func transformElasticSubnet(cmd *cobra.Command, args []string) error {
 	fee := network.GenesisParams().CreateAssetTxFee + network.GenesisParams().TransformSubnetTxFee + network.GenesisParams().TxFee*2
 
 	network.HandlePublicNetworkSimulation()
-	kc, err := keychain.GetKeychain(app, false, useLedger, ledgerAddresses, keyName, network, fee)
+	kc, err := keychain.GetKeychain(app, models.KeyRoleDeployer, false, useLedger, ledgerAddresses, signerSpec, keyName, network, fee)
 	if err != nil {
 		return err
 	}
@@ -353,7 +408,7 @@ func transformElasticSubnet(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	flags := make(map[string]string)
-	flags[constants.Network] = network.Name()
+	flags[constants.Network] = network.Kind.String()
 	if !isFullySigned {
 		flags[constants.MultiSig] = "multi-sig"
 	} else {
@@ -369,6 +424,11 @@ func transformElasticSubnet(cmd *cobra.Command, args []string) error {
 			remainingSubnetAuthKeys,
 			outputTxPath,
 			false,
+			&txutils.OfflineSigningInfo{
+				SubnetID:    subnetID,
+				ControlKeys: controlKeys,
+				Threshold:   threshold,
+			},
 		); err != nil {
 			return err
 		}
@@ -390,7 +450,7 @@ func transformElasticSubnetLocal(sc models.Sidecar, subnetName string, tokenName
 		return fmt.Errorf("%s is already an elastic subnet", subnetName)
 	}
 	var err error
-	subnetID := sc.Networks[models.Local.String()].SubnetID
+	subnetID := sc.Networks[models.NewLocalNetwork().Name()].SubnetID
 	if subnetID == ids.Empty {
 		return errNoSubnetID
 	}
@@ -496,7 +556,7 @@ func getTokenSymbol() (string, error) {
 }
 
 func checkAllLocalNodesAreCurrentValidators(subnetID ids.ID) error {
-	api := constants.LocalAPIEndpoint
+	api := models.LocalAPIEndpoint()
 	pClient := platformvm.NewClient(api)
 
 	ctx := context.Background()
@@ -571,8 +631,8 @@ func handleRemoveAndAddValidators(sc models.Sidecar, subnetID ids.ID, validator
 		return err
 	}
 	ux.Logger.PrintToUser(fmt.Sprintf("Validator %s removed", validator.String()))
-	assetID := sc.ElasticSubnet[models.Local.String()].AssetID
-	txID, err := subnet.IssueAddPermissionlessValidatorTx(keyChain, subnetID, validator, stakedAmount, assetID, uint64(startTime.Unix()), uint64(endTime.Unix()))
+	assetID := sc.ElasticSubnet[models.NewLocalNetwork().Name()].AssetID
+	txID, err := subnet.IssueAddPermissionlessValidatorTx(keyChain, subnetID, validator, stakedAmount, assetID, uint64(startTime.Unix()), uint64(endTime.Unix()), ids.ShortEmpty)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,126 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package subnetcmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
+	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metalgo/utils/logging"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+const defaultExpiryWindow = 7 * 24 * time.Hour
+
+var expiryWindow time.Duration
+
+// avalanche subnet expiry
+func newExpiryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "expiry [subnetName]",
+		Short: "List subnet validators by expiration time",
+		Long: `The subnet expiry command lists the validators of a subnet sorted by the
+time their validation period ends, highlighting the ones that expire within
+the window given by --within.
+
+The command exits with a non-zero status if any validator is about to
+expire, so that it can be used from cron jobs or alerting scripts.`,
+		RunE:         withActiveSubnet(checkValidatorsExpiry),
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, validatorsSupportedNetworkOptions)
+	cmd.Flags().DurationVar(&expiryWindow, "within", defaultExpiryWindow, "report validators whose validation ends within this duration")
+	return cmd
+}
+
+func checkValidatorsExpiry(_ *cobra.Command, args []string) error {
+	subnetName := args[0]
+
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		globalNetworkFlags,
+		false,
+		validatorsSupportedNetworkOptions,
+		subnetName,
+	)
+	if err != nil {
+		return err
+	}
+
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	deployInfo, ok := sc.Networks[network.Name()]
+	if !ok {
+		return errors.New("no deployment found for subnet")
+	}
+
+	var validators []platformvm.ClientPermissionlessValidator
+	if network.Kind == models.Local {
+		validators, err = subnet.GetSubnetValidators(deployInfo.SubnetID)
+	} else {
+		validators, err = subnet.GetPublicSubnetValidators(deployInfo.SubnetID, network)
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	sortValidatorsByEndTime(validators)
+	expiring := printValidatorsExpiry(validators, now, expiryWindow)
+	if expiring > 0 {
+		return fmt.Errorf("%d of %d validators of subnet %s expire within %s", expiring, len(validators), subnetName, strings.TrimSpace(ux.FormatDuration(expiryWindow)))
+	}
+	ux.Logger.PrintToUser("No validator of subnet %s expires within %s", subnetName, strings.TrimSpace(ux.FormatDuration(expiryWindow)))
+	return nil
+}
+
+func sortValidatorsByEndTime(validators []platformvm.ClientPermissionlessValidator) {
+	sort.SliceStable(validators, func(i, j int) bool {
+		return validators[i].EndTime < validators[j].EndTime
+	})
+}
+
+// isExpiringWithin returns true if [validator] stops validating before [now] + [window]
+func isExpiringWithin(validator platformvm.ClientPermissionlessValidator, now time.Time, window time.Duration) bool {
+	return time.Unix(int64(validator.EndTime), 0).Before(now.Add(window))
+}
+
+// printValidatorsExpiry prints the validators expiration table, and returns
+// the amount of validators expiring within [window]
+func printValidatorsExpiry(validators []platformvm.ClientPermissionlessValidator, now time.Time, window time.Duration) int {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"NodeID", "End Time", "Time Left", "Status"})
+	table.SetRowLine(true)
+
+	expiring := 0
+	for _, validator := range validators {
+		endTime := time.Unix(int64(validator.EndTime), 0)
+		status := "ok"
+		if isExpiringWithin(validator, now, window) {
+			status = logging.Red.Wrap("expiring")
+			expiring++
+		}
+		table.Append([]string{
+			validator.NodeID.String(),
+			formatUnixTime(validator.EndTime),
+			strings.TrimSpace(ux.FormatDuration(endTime.Sub(now))),
+			status,
+		})
+	}
+	table.Render()
+	return expiring
+}
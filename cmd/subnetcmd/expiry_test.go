@@ -0,0 +1,39 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatorsExpiry(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Now()
+	newValidator := func(endsIn time.Duration) platformvm.ClientPermissionlessValidator {
+		v := platformvm.ClientPermissionlessValidator{}
+		v.NodeID = ids.GenerateTestNodeID()
+		v.EndTime = uint64(now.Add(endsIn).Unix())
+		return v
+	}
+	late := newValidator(30 * 24 * time.Hour)
+	soon := newValidator(2 * 24 * time.Hour)
+	sooner := newValidator(time.Hour)
+	validators := []platformvm.ClientPermissionlessValidator{late, soon, sooner}
+
+	sortValidatorsByEndTime(validators)
+	require.Equal(
+		[]ids.NodeID{sooner.NodeID, soon.NodeID, late.NodeID},
+		[]ids.NodeID{validators[0].NodeID, validators[1].NodeID, validators[2].NodeID},
+	)
+
+	require.True(isExpiringWithin(sooner, now, defaultExpiryWindow))
+	require.True(isExpiringWithin(soon, now, defaultExpiryWindow))
+	require.False(isExpiringWithin(late, now, defaultExpiryWindow))
+	require.False(isExpiringWithin(soon, now, 24*time.Hour))
+}
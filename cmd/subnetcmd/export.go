@@ -30,10 +30,13 @@ func newExportCmd() *cobra.Command {
 		Long: `The subnet export command write the details of an existing Subnet deploy to a file.
 
 The command prompts for an output path. You can also provide one with
-the --output flag.`,
-		RunE:         exportSubnet,
+the --output flag.
+
+Use subnet export docker to generate the files packaging a validator of the
+Subnet as a docker image instead.`,
+		RunE:         withActiveSubnet(exportSubnet),
 		SilenceUsage: true,
-		Args:         cobra.ExactArgs(1),
+		Args:         cobra.MaximumNArgs(1),
 	}
 
 	cmd.Flags().StringVarP(
@@ -46,6 +49,8 @@ the --output flag.`,
 	cmd.Flags().StringVar(&customVMRepoURL, "custom-vm-repo-url", "", "custom vm repository url")
 	cmd.Flags().StringVar(&customVMBranch, "custom-vm-branch", "", "custom vm branch")
 	cmd.Flags().StringVar(&customVMBuildScript, "custom-vm-build-script", "", "custom vm build-script")
+	// metal subnet export docker
+	cmd.AddCommand(newExportDockerCmd())
 	return cmd
 }
 
@@ -126,50 +131,58 @@ func exportSubnet(_ *cobra.Command, args []string) error {
 		}
 	}
 
-	gen, err := app.LoadRawGenesis(subnetName)
+	exportData, err := getExportable(sc)
+	if err != nil {
+		return err
+	}
+	exportBytes, err := json.Marshal(exportData)
 	if err != nil {
 		return err
 	}
+	return os.WriteFile(exportOutput, exportBytes, constants.WriteReadReadPerms)
+}
+
+// getExportable returns the subnet configuration of [sc], together with its genesis and configs
+func getExportable(sc models.Sidecar) (models.Exportable, error) {
+	subnetName := sc.Name
+	gen, err := app.LoadRawGenesis(subnetName)
+	if err != nil {
+		return models.Exportable{}, err
+	}
 
 	var nodeConfig, chainConfig, subnetConfig, networkUpgrades []byte
 
 	if app.AvagoNodeConfigExists(subnetName) {
 		nodeConfig, err = app.LoadRawAvagoNodeConfig(subnetName)
 		if err != nil {
-			return err
+			return models.Exportable{}, err
 		}
 	}
 	if app.ChainConfigExists(subnetName) {
 		chainConfig, err = app.LoadRawChainConfig(subnetName)
 		if err != nil {
-			return err
+			return models.Exportable{}, err
 		}
 	}
 	if app.AvagoSubnetConfigExists(subnetName) {
 		subnetConfig, err = app.LoadRawAvagoSubnetConfig(subnetName)
 		if err != nil {
-			return err
+			return models.Exportable{}, err
 		}
 	}
 	if app.NetworkUpgradeExists(subnetName) {
 		networkUpgrades, err = app.LoadRawNetworkUpgrades(subnetName)
 		if err != nil {
-			return err
+			return models.Exportable{}, err
 		}
 	}
 
-	exportData := models.Exportable{
+	return models.Exportable{
 		Sidecar:         sc,
 		Genesis:         gen,
 		NodeConfig:      nodeConfig,
 		ChainConfig:     chainConfig,
 		SubnetConfig:    subnetConfig,
 		NetworkUpgrades: networkUpgrades,
-	}
-
-	exportBytes, err := json.Marshal(exportData)
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(exportOutput, exportBytes, constants.WriteReadReadPerms)
+	}, nil
 }
@@ -0,0 +1,149 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
+	"github.com/MetalBlockchain/metal-cli/pkg/plugins"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metal-cli/pkg/validatordocker"
+	"github.com/MetalBlockchain/metal-cli/pkg/vm"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportDockerSupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Tahoe, networkoptions.Mainnet}
+	exportDockerNetworkFlags            networkoptions.NetworkFlags
+	exportDockerOutputDir               string
+	exportDockerAvagoVersion            string
+	exportDockerHTTPPort                int
+	exportDockerStakingPort             int
+)
+
+// metal subnet export docker
+func newExportDockerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "docker [subnetName]",
+		Short: "Export a Dockerfile and compose file running a validator of the subnet",
+		Long: `The subnet export docker command writes a Dockerfile and a docker-compose.yml
+that build and run a metalgo validator tracking the given Subnet, deployed to
+Tahoe or Mainnet. The image gets the Subnet VM binary as a plugin, and the node,
+Subnet and chain configs of the Subnet.
+
+The files are written into --output-dir, by default <subnetName>-validator. Run
+the validator with:
+
+  docker compose up -d --build
+
+The node data, including its staking keys and thus its NodeID, is kept in a
+docker volume. Once the node is bootstrapped, add it as a Subnet validator with
+metal subnet addValidator.
+
+The base image is metalblockchain/metalgo, at the latest version compatible with
+the Subnet VM, or at --metalgo-version. The VM binary is the one the CLI uses
+on this machine, so the files must be generated on linux/amd64 to run on it.`,
+		RunE:         withActiveSubnet(exportDocker),
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(1),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &exportDockerNetworkFlags, false, exportDockerSupportedNetworkOptions)
+	cmd.Flags().StringVar(&exportDockerOutputDir, "output-dir", "", "dir to write the files into (default: <subnetName>-validator)")
+	cmd.Flags().StringVar(&exportDockerAvagoVersion, "metalgo-version", "", "metalgo image tag (default: the latest one compatible with the VM)")
+	cmd.Flags().IntVar(&exportDockerHTTPPort, "http-port", validatordocker.DefaultHTTPPort, "HTTP port of the node, published on the docker host loopback")
+	cmd.Flags().IntVar(&exportDockerStakingPort, "staking-port", validatordocker.DefaultStakingPort, "staking port of the node, published on the docker host")
+	return cmd
+}
+
+func exportDocker(_ *cobra.Command, args []string) error {
+	chains, err := ValidateSubnetNameAndGetChains(args)
+	if err != nil {
+		return err
+	}
+	subnetName := chains[0]
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		exportDockerNetworkFlags,
+		false,
+		exportDockerSupportedNetworkOptions,
+		subnetName,
+	)
+	if err != nil {
+		return err
+	}
+	subnetID := sc.Networks[network.Name()].SubnetID
+	if subnetID == ids.Empty {
+		return errNoSubnetID
+	}
+	avagoVersion := exportDockerAvagoVersion
+	if avagoVersion == "" {
+		avagoVersion, err = getExportDockerAvagoVersion(sc)
+		if err != nil {
+			return fmt.Errorf("%w. Set the metalgo version with --metalgo-version", err)
+		}
+	}
+	outputDir := exportDockerOutputDir
+	if outputDir == "" {
+		outputDir = strings.ReplaceAll(subnetName, " ", "-") + "-validator"
+	}
+
+	exportData, err := getExportable(sc)
+	if err != nil {
+		return err
+	}
+	if err := validatordocker.WriteFiles(outputDir, validatordocker.Config{
+		SubnetName:      subnetName,
+		MetalGoVersion:  avagoVersion,
+		NetworkID:       network.NetworkIDFlagValue(),
+		SubnetID:        subnetID,
+		BlockchainID:    sc.Networks[network.Name()].BlockchainID,
+		HTTPPort:        exportDockerHTTPPort,
+		StakingPort:     exportDockerStakingPort,
+		NodeConfig:      exportData.NodeConfig,
+		ChainConfig:     exportData.ChainConfig,
+		SubnetConfig:    exportData.SubnetConfig,
+		NetworkUpgrades: exportData.NetworkUpgrades,
+	}); err != nil {
+		return err
+	}
+	vmPath, err := plugins.CreatePlugin(app, subnetName, filepath.Join(outputDir, validatordocker.PluginsDir))
+	if err != nil {
+		return err
+	}
+	if runtime.GOOS != "linux" || runtime.GOARCH != "amd64" {
+		ux.Logger.PrintToUser("Warning: the VM binary %s was built for %s/%s. Replace it with a linux/amd64 build before building the image", vmPath, runtime.GOOS, runtime.GOARCH)
+	}
+	absOutputDir, err := filepath.Abs(outputDir)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Validator docker files for subnet %s on %s written to %s", subnetName, network.Name(), absOutputDir)
+	ux.Logger.PrintToUser("Base image: %s:%s", validatordocker.Image, avagoVersion)
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Start the validator with:")
+	ux.Logger.PrintToUser("  cd %s && docker compose up -d --build", absOutputDir)
+	ux.Logger.PrintToUser("Get its NodeID with:")
+	ux.Logger.PrintToUser(`  curl -s -X POST -H 'content-type:application/json' --data '{"jsonrpc":"2.0","id":1,"method":"info.getNodeID"}' 127.0.0.1:%d/ext/info`, exportDockerHTTPPort)
+	ux.Logger.PrintToUser("and add it as a validator with metal subnet addValidator %s", subnetName)
+	return nil
+}
+
+// getExportDockerAvagoVersion returns the latest metalgo version compatible with
+// the VM of [sc]
+func getExportDockerAvagoVersion(sc models.Sidecar) (string, error) {
+	if sc.VM == models.CustomVM || sc.RPCVersion == 0 {
+		return "", fmt.Errorf("unknown RPC version of the %s VM", sc.Name)
+	}
+	return vm.GetLatestAvalancheGoByProtocolVersion(app, sc.RPCVersion, constants.AvalancheGoCompatibilityURL)
+}
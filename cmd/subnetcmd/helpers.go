@@ -8,6 +8,7 @@ import (
 	"github.com/MetalBlockchain/metal-cli/pkg/keychain"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
 	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
+	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
 	"github.com/MetalBlockchain/metal-cli/pkg/txutils"
 	"github.com/MetalBlockchain/metalgo/ids"
 	"github.com/spf13/cobra"
@@ -18,7 +19,7 @@ var globalNetworkFlags networkoptions.NetworkFlags
 func CreateSubnetFirst(cmd *cobra.Command, subnetName string, skipPrompt bool) error {
 	if !app.SubnetConfigExists(subnetName) {
 		if !skipPrompt {
-			yes, err := app.Prompt.CaptureNoYes(fmt.Sprintf("Subnet %s is not created yet. Do you want to create it first?", subnetName))
+			yes, err := app.Prompt.CaptureNoYes(prompts.Keyed("create-subnet-first", fmt.Sprintf("Subnet %s is not created yet. Do you want to create it first?", subnetName)))
 			if err != nil {
 				return err
 			}
@@ -54,7 +55,7 @@ func DeploySubnetFirst(cmd *cobra.Command, subnetName string, skipPrompt bool, s
 	}
 	if doDeploy {
 		if !skipPrompt {
-			yes, err := app.Prompt.CaptureNoYes(msg)
+			yes, err := app.Prompt.CaptureNoYes(prompts.Keyed("deploy-subnet-first", msg))
 			if err != nil {
 				return err
 			}
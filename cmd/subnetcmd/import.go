@@ -3,31 +3,75 @@
 package subnetcmd
 
 import (
-	"fmt"
-
+	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
+	"github.com/MetalBlockchain/metalgo/ids"
 	"github.com/spf13/cobra"
 )
 
+var subnetIDstr string
+
 // avalanche subnet
 func newImportCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "import",
+		Use:   "import [--from-registry subnetName | --subnet-id subnetID]",
 		Short: "Import subnets into metal-cli",
 		Long: `Import subnet configurations into metal-cli.
 
 This command supports importing from a file created on another computer,
 or importing from subnets running public networks
-(e.g. created manually or with the deprecated subnet-cli)`,
-		Run: func(cmd *cobra.Command, _ []string) {
-			err := cmd.Help()
-			if err != nil {
-				fmt.Println(err)
+(e.g. created manually or with the deprecated subnet-cli)
+
+With --from-registry, it imports a subnet published with subnet publish --registry,
+after verifying its descriptor signature. Give the publishers you trust with
+--trusted-signer to reject descriptors signed by anyone else.
+
+With --subnet-id, it imports a subnet deployed with other tooling from the given network,
+rebuilding its configuration from the on-chain data, so its validators can be managed
+with metal-cli.`,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			switch {
+			case fromRegistry != "":
+				return importFromRegistry(fromRegistry)
+			case subnetIDstr != "":
+				return importSubnetFromNetwork()
 			}
+			return cmd.Help()
 		},
 	}
+	cmd.Flags().StringVar(&fromRegistry, "from-registry", "", "import the subnet with this name from the subnet registry")
+	cmd.Flags().StringVar(&registryURL, "registry", "", "URL of the subnet registry to import from")
+	cmd.Flags().StringSliceVar(&trustedSignerStrs, "trusted-signer", nil,
+		"only accept descriptors signed by these P-Chain addresses (or address book labels)")
+	cmd.Flags().BoolVar(&overwriteImport, forceFlag, false, "overwrite the existing configuration if one exists")
+	cmd.Flags().StringVar(&subnetIDstr, "subnet-id", "", "import the subnet with this ID from the network")
+	cmd.Flags().StringVar(&blockchainIDstr, "blockchain-id", "", "blockchain of the --subnet-id subnet to import, if it has several")
+	cmd.Flags().StringVar(&nodeURL, "node-url", "", "[optional] URL of a subnet validator to query the VM version from")
+	cmd.Flags().BoolVar(&useSubnetEvm, "evm", false, "import a subnet-evm")
+	cmd.Flags().BoolVar(&useCustom, "custom", false, "import a custom VM")
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, importPublicSupportedNetworkOptions)
 	// subnet import file
 	cmd.AddCommand(newImportFileCmd())
 	// subnet import public
 	cmd.AddCommand(newImportPublicCmd())
 	return cmd
 }
+
+func importSubnetFromNetwork() error {
+	subnetID, err := ids.FromString(subnetIDstr)
+	if err != nil {
+		return err
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		globalNetworkFlags,
+		false,
+		importPublicSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	return importSubnetByID(network, subnetID)
+}
@@ -115,7 +115,11 @@ func importFromFile(importPath string) error {
 	if err != nil {
 		return err
 	}
+	return importExportable(importable)
+}
 
+// importExportable creates the subnet configuration given by [importable]
+func importExportable(importable models.Exportable) error {
 	subnetName := importable.Sidecar.Name
 	if subnetName == "" {
 		return errors.New("export data is malformed: missing subnet name")
@@ -125,6 +129,9 @@ func importFromFile(importPath string) error {
 		return errors.New("subnet already exists. Use --" + forceFlag + " parameter to overwrite")
 	}
 
+	// the VM binary is obtained again on this machine, possibly for another platform
+	importable.Sidecar.VMBinary = models.VMBinary{}
+
 	if importable.Sidecar.VM == models.CustomVM {
 		if importable.Sidecar.CustomVMRepoURL == "" {
 			return fmt.Errorf("repository url must be defined for custom vm import")
@@ -148,6 +155,9 @@ func importFromFile(importPath string) error {
 		if rpcVersion != importable.Sidecar.RPCVersion {
 			return fmt.Errorf("RPC version mismatch between sidecar and vm binary (%d vs %d)", importable.Sidecar.RPCVersion, rpcVersion)
 		}
+		if err := vm.SetVMBinary(&importable.Sidecar, vmPath, vm.GetVMBinarySource(importable.Sidecar, vmPath)); err != nil {
+			return err
+		}
 	}
 
 	if err := app.WriteGenesisFile(subnetName, importable.Genesis); err != nil {
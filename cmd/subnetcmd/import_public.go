@@ -4,8 +4,10 @@ package subnetcmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"sort"
 
 	"github.com/MetalBlockchain/coreth/core"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
@@ -20,6 +22,8 @@ import (
 	"github.com/MetalBlockchain/metalgo/vms/platformvm"
 	"github.com/MetalBlockchain/metalgo/vms/platformvm/txs"
 	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 var (
@@ -39,7 +43,8 @@ func newImportPublicCmd() *cobra.Command {
 		Args:         cobra.MaximumNArgs(1),
 		Long: `The subnet import public command imports a Subnet configuration from a running network.
 
-The genesis file should be available from the disk for this to work. By default, an imported Subnet
+The genesis is taken from the transaction that created the blockchain, unless a genesis
+file is given with --genesis-file-path. By default, an imported Subnet
 doesn't overwrite an existing Subnet with the same name. To allow overwrites, provide the --force
 flag.`,
 	}
@@ -60,7 +65,7 @@ flag.`,
 		&genesisFilePath,
 		"genesis-file-path",
 		"",
-		"path to the genesis file",
+		"path to the genesis file, instead of the one the blockchain was created with",
 	)
 	cmd.Flags().StringVar(
 		&blockchainIDstr,
@@ -83,13 +88,26 @@ func importPublic(*cobra.Command, []string) error {
 		return err
 	}
 
-	if genesisFilePath == "" {
-		genesisFilePath, err = app.Prompt.CaptureExistingFilepath("Provide the path to the genesis file")
+	var blockchainID ids.ID
+	if blockchainIDstr == "" {
+		blockchainID, err = app.Prompt.CaptureID("What is the ID of the blockchain?")
+		if err != nil {
+			return err
+		}
+	} else {
+		blockchainID, err = ids.FromString(blockchainIDstr)
 		if err != nil {
 			return err
 		}
 	}
 
+	return importBlockchain(network, blockchainID)
+}
+
+// importBlockchain creates the subnet configuration of the blockchain [blockchainID]
+// running on [network], from its CreateChainTx. The genesis is taken from the
+// --genesis-file-path file if given, or from the tx otherwise
+func importBlockchain(network models.Network, blockchainID ids.ID) error {
 	var reply *info.GetNodeVersionReply
 
 	if nodeURL == "" {
@@ -103,27 +121,17 @@ func importPublic(*cobra.Command, []string) error {
 			if err != nil {
 				return err
 			}
-			ctx, cancel := utils.GetAPIContext()
-			defer cancel()
-			infoAPI := info.NewClient(nodeURL)
-			options := []rpc.Option{}
-			reply, err = infoAPI.GetNodeVersion(ctx, options...)
-			if err != nil {
-				return fmt.Errorf("failed to query node - is it running and reachable? %w", err)
-			}
 		}
 	}
-
-	var blockchainID ids.ID
-	if blockchainIDstr == "" {
-		blockchainID, err = app.Prompt.CaptureID("What is the ID of the blockchain?")
+	if nodeURL != "" {
+		ctx, cancel := utils.GetAPIContext()
+		defer cancel()
+		infoAPI := info.NewClient(nodeURL)
+		options := []rpc.Option{}
+		var err error
+		reply, err = infoAPI.GetNodeVersion(ctx, options...)
 		if err != nil {
-			return err
-		}
-	} else {
-		blockchainID, err = ids.FromString(blockchainIDstr)
-		if err != nil {
-			return err
+			return fmt.Errorf("failed to query node - is it running and reachable? %w", err)
 		}
 	}
 
@@ -167,10 +175,16 @@ func importPublic(*cobra.Command, []string) error {
 	)
 	// TODO: it's probably possible to deploy VMs with the same name on a public network
 	// In this case, an import could clash because the tool supports unique names only
+	if app.GenesisExists(subnetName) && !overwriteImport {
+		return errors.New("subnet " + subnetName + " already exists. Use --" + forceFlag + " parameter to overwrite")
+	}
 
-	genBytes, err := os.ReadFile(genesisFilePath)
-	if err != nil {
-		return err
+	genBytes := createChainTx.GenesisData
+	if genesisFilePath != "" {
+		genBytes, err = os.ReadFile(genesisFilePath)
+		if err != nil {
+			return err
+		}
 	}
 
 	if err = app.WriteGenesisFile(subnetName, genBytes); err != nil {
@@ -178,6 +192,10 @@ func importPublic(*cobra.Command, []string) error {
 	}
 
 	vmType := getVMFromFlag()
+	if vmType == "" && isSubnetEvmGenesis(genBytes) {
+		ux.Logger.PrintToUser("Detected a Subnet-EVM genesis")
+		vmType = models.SubnetEvm
+	}
 	if vmType == "" {
 		subnetTypeStr, err := app.Prompt.CaptureList(
 			"What's this VM's type?",
@@ -258,3 +276,63 @@ func importPublic(*cobra.Command, []string) error {
 
 	return nil
 }
+
+// isSubnetEvmGenesis tells if [genBytes] looks like a Subnet-EVM genesis
+func isSubnetEvmGenesis(genBytes []byte) bool {
+	var genesis struct {
+		Config struct {
+			ChainID   *json.Number    `json:"chainId"`
+			FeeConfig json.RawMessage `json:"feeConfig"`
+		} `json:"config"`
+		Alloc json.RawMessage `json:"alloc"`
+	}
+	if err := json.Unmarshal(genBytes, &genesis); err != nil {
+		return false
+	}
+	return genesis.Config.ChainID != nil && len(genesis.Config.FeeConfig) > 0 && len(genesis.Alloc) > 0
+}
+
+// importSubnetByID imports the blockchain validated by the subnet [subnetID] on
+// [network]. If the subnet has several blockchains, --blockchain-id or a prompt
+// selects the one to import
+func importSubnetByID(network models.Network, subnetID ids.ID) error {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	blockchains, err := platformvm.NewClient(network.Endpoint).GetBlockchains(ctx)
+	if err != nil {
+		return fmt.Errorf("failed getting blockchains from %s: %w", network.Name(), err)
+	}
+	subnetBlockchains := map[string]ids.ID{}
+	names := []string{}
+	for _, blockchain := range blockchains {
+		if blockchain.SubnetID != subnetID {
+			continue
+		}
+		option := fmt.Sprintf("%s (%s)", blockchain.Name, blockchain.ID)
+		subnetBlockchains[option] = blockchain.ID
+		names = append(names, option)
+	}
+	var blockchainID ids.ID
+	switch {
+	case blockchainIDstr != "":
+		blockchainID, err = ids.FromString(blockchainIDstr)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(maps.Values(subnetBlockchains), blockchainID) {
+			return fmt.Errorf("blockchain %s is not validated by subnet %s", blockchainID, subnetID)
+		}
+	case len(names) == 0:
+		return ux.NewCodedError(ux.ErrCodeNotFound, fmt.Errorf("no blockchain found for subnet %s on %s", subnetID, network.Name()))
+	case len(names) == 1:
+		blockchainID = subnetBlockchains[names[0]]
+	default:
+		sort.Strings(names)
+		choice, err := app.Prompt.CaptureList("Subnet has several blockchains. Which one do you want to import?", names)
+		if err != nil {
+			return err
+		}
+		blockchainID = subnetBlockchains[choice]
+	}
+	return importBlockchain(network, blockchainID)
+}
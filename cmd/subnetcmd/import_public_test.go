@@ -0,0 +1,18 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsSubnetEvmGenesis(t *testing.T) {
+	require := require.New(t)
+	require.True(isSubnetEvmGenesis([]byte(`{"config":{"chainId":99999,"feeConfig":{"gasLimit":8000000}},"alloc":{}}`)))
+	// coreth style genesis, without fee config
+	require.False(isSubnetEvmGenesis([]byte(`{"config":{"chainId":43112},"alloc":{}}`)))
+	require.False(isSubnetEvmGenesis([]byte(`{"timestamp":0}`)))
+	require.False(isSubnetEvmGenesis([]byte{0x00, 0x01}))
+}
@@ -0,0 +1,287 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/binutils"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
+	"github.com/MetalBlockchain/metal-cli/pkg/plugins"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/spf13/cobra"
+)
+
+const defaultInvitePlatform = "linux/amd64"
+
+var (
+	inviteSupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Tahoe, networkoptions.Mainnet, networkoptions.Devnet, networkoptions.Environment, networkoptions.Cluster}
+
+	// URL the custom VM binary was uploaded to
+	inviteVMURL string
+	// os/arch of the invited validator
+	invitePlatform string
+	// file path to write the invite bundle to
+	inviteOutputPath string
+)
+
+// avalanche subnet invite
+func newInviteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "invite [subnetName]",
+		Short: "Create an invite for a validator to join a subnet",
+		Long: `The subnet invite command creates an invite for the validator with the given
+NodeID to join a deployed Subnet. The invite holds the Subnet and Blockchain IDs,
+the URL and SHA256 of the VM binary, and the chain config, subnet config and
+upgrade.json of the Subnet, so the validator operator doesn't need a copy of the
+Subnet configuration.
+
+The invite is printed as a single line that can be pasted into chat or a URL. With
+--output, it is also written as a JSON bundle. The operator joins with
+'metal subnet join --invite <invite or bundle file>', which downloads and verifies
+the VM binary, and writes the node configuration.
+
+For Subnet-EVM, the invite points to the release archive for the platform given with
+--platform. Custom VM binaries must be uploaded by you, and their URL given with
+--vm-url.`,
+		SilenceUsage: true,
+		RunE:         withActiveSubnet(inviteCmd),
+		Args:         cobra.MaximumNArgs(1),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, inviteSupportedNetworkOptions)
+	cmd.Flags().StringVar(&nodeIDStr, "nodeID", "", "NodeID of the invited validator")
+	cmd.Flags().StringVar(&inviteVMURL, "vm-url", "", "URL the custom VM binary was uploaded to")
+	cmd.Flags().StringVar(&invitePlatform, "platform", defaultInvitePlatform, "os/arch of the invited validator")
+	cmd.Flags().StringVarP(&inviteOutputPath, "output", "o", "", "also write the invite as a JSON bundle to this file")
+	return cmd
+}
+
+func inviteCmd(_ *cobra.Command, args []string) error {
+	subnetName := args[0]
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		globalNetworkFlags,
+		false,
+		inviteSupportedNetworkOptions,
+		subnetName,
+	)
+	if err != nil {
+		return err
+	}
+	subnetID := sc.Networks[network.Name()].SubnetID
+	if subnetID == ids.Empty {
+		return errNoSubnetID
+	}
+
+	var nodeID ids.NodeID
+	if nodeIDStr == "" {
+		nodeID, err = app.Prompt.CaptureNodeID("What is the NodeID of the validator you'd like to invite?")
+	} else {
+		nodeID, err = ids.NodeIDFromString(nodeIDStr)
+	}
+	if err != nil {
+		return err
+	}
+
+	invite, err := newSubnetInvite(subnetName, sc, network)
+	if err != nil {
+		return err
+	}
+	invite.NodeID = nodeID
+
+	encoded, err := invite.Encode()
+	if err != nil {
+		return err
+	}
+	if inviteOutputPath != "" {
+		bundle, err := json.MarshalIndent(invite, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(inviteOutputPath, bundle, constants.WriteReadReadPerms); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Invite bundle written to %s", inviteOutputPath)
+	}
+	ux.Logger.PrintToUser("Invite for validator %s to join subnet %s on %s:", nodeID, subnetName, network.Name())
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser(encoded)
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("The validator joins with 'metal subnet join --invite <invite>'")
+	ux.Logger.PrintToUser("Once the node tracks the subnet, add it with 'metal subnet addValidator %s --nodeID %s'", subnetName, nodeID)
+	return nil
+}
+
+// newSubnetInvite fills an invite with the IDs, VM binary and configuration of
+// [subnetName] deployed on [network]
+func newSubnetInvite(subnetName string, sc models.Sidecar, network models.Network) (models.SubnetInvite, error) {
+	vmID, err := sc.GetVMID()
+	if err != nil {
+		return models.SubnetInvite{}, err
+	}
+	goos, goarch, found := strings.Cut(invitePlatform, "/")
+	if !found || goos == "" || goarch == "" {
+		return models.SubnetInvite{}, fmt.Errorf("invalid platform %q, expected os/arch, eg %s", invitePlatform, defaultInvitePlatform)
+	}
+	invite := models.SubnetInvite{
+		Version:      models.SubnetInviteVersion,
+		SubnetName:   subnetName,
+		NetworkID:    network.ID,
+		SubnetID:     sc.Networks[network.Name()].SubnetID,
+		BlockchainID: sc.Networks[network.Name()].BlockchainID,
+		VM:           sc.VM,
+		VMVersion:    sc.VMVersion,
+		VMID:         vmID,
+		VMPlatform:   invitePlatform,
+	}
+	if network.Kind == models.Devnet {
+		invite.Endpoint = network.Endpoint
+	}
+
+	switch {
+	case inviteVMURL != "":
+		vmPath := binutils.SetupCustomBin(app, subnetName)
+		if sc.ImportedFromAPM {
+			vmPath = binutils.SetupAPMBin(app, sc.ImportedVMID)
+		} else if sc.VM != models.CustomVM {
+			return models.SubnetInvite{}, fmt.Errorf("--vm-url is only used for custom VMs, %s binaries are taken from their releases", sc.VM)
+		}
+		// joiners check what was actually published, which may be an archive
+		ux.Logger.PrintToUser("Getting the checksum of the VM binary published at %s...", inviteVMURL)
+		invite.VMBinaryURL = inviteVMURL
+		invite.VMBinarySHA256, err = binutils.GetURLSHA256(app, inviteVMURL)
+		if err != nil {
+			return models.SubnetInvite{}, err
+		}
+		if !binutils.IsArchiveURL(inviteVMURL) {
+			localSHA256, err := binutils.FileSHA256(vmPath)
+			if err != nil {
+				return models.SubnetInvite{}, fmt.Errorf("failed to compute the checksum of the VM binary %s: %w", vmPath, err)
+			}
+			if localSHA256 != invite.VMBinarySHA256 {
+				return models.SubnetInvite{}, fmt.Errorf("the file at %s is not the VM binary of %s at %s. Upload it again", inviteVMURL, subnetName, vmPath)
+			}
+		}
+	case sc.VM == models.SubnetEvm && !sc.ImportedFromAPM:
+		ux.Logger.PrintToUser("Getting the checksum of subnet-evm %s for %s...", sc.VMVersion, invitePlatform)
+		invite.VMBinaryURL, invite.VMBinarySHA256, err = binutils.GetSubnetEVMRelease(app, sc.VMVersion, goos, goarch)
+		if err != nil {
+			return models.SubnetInvite{}, err
+		}
+	default:
+		return models.SubnetInvite{}, errors.New("custom VM binaries must be uploaded, and their URL given with --vm-url")
+	}
+
+	if app.ChainConfigExists(subnetName) {
+		invite.ChainConfig, err = app.LoadRawChainConfig(subnetName)
+		if err != nil {
+			return models.SubnetInvite{}, err
+		}
+	}
+	if app.AvagoSubnetConfigExists(subnetName) {
+		invite.SubnetConfig, err = app.LoadRawAvagoSubnetConfig(subnetName)
+		if err != nil {
+			return models.SubnetInvite{}, err
+		}
+	}
+	if app.NetworkUpgradeExists(subnetName) {
+		invite.NetworkUpgrades, err = app.LoadRawNetworkUpgrades(subnetName)
+		if err != nil {
+			return models.SubnetInvite{}, err
+		}
+	}
+	return invite, nil
+}
+
+// loadSubnetInvite decodes [inviteStr], that is either an invite or the path of
+// an invite bundle file
+func loadSubnetInvite(inviteStr string) (models.SubnetInvite, error) {
+	if utils.FileExists(inviteStr) {
+		bs, err := os.ReadFile(inviteStr)
+		if err != nil {
+			return models.SubnetInvite{}, err
+		}
+		inviteStr = string(bs)
+	}
+	return models.DecodeSubnetInvite(inviteStr)
+}
+
+// joinWithInvite configures the validator node to track the subnet of the invite
+// given with --invite, installing its VM binary and chain configs
+func joinWithInvite() error {
+	invite, err := loadSubnetInvite(inviteStr)
+	if err != nil {
+		return err
+	}
+	network := invite.Network()
+	if network.Kind == models.Undefined {
+		return fmt.Errorf("unknown network ID %d in invite", invite.NetworkID)
+	}
+	ux.Logger.PrintToUser("Joining subnet %s (%s) on %s", invite.SubnetName, invite.SubnetID, network.Name())
+	if nodeIDStr != "" && nodeIDStr != invite.NodeID.String() {
+		ux.Logger.PrintToUser("Warning: the invite was made for validator %s, not %s", invite.NodeID, nodeIDStr)
+	}
+	if platform := runtime.GOOS + "/" + runtime.GOARCH; invite.VMPlatform != "" && invite.VMPlatform != platform {
+		ux.Logger.PrintToUser("Warning: the VM binary of the invite is built for %s, make sure your node runs on it", invite.VMPlatform)
+	}
+
+	binName := invite.VMID
+	if invite.VM == models.SubnetEvm {
+		binName = constants.SubnetEVMBin
+	}
+	if printManual {
+		pluginDir = app.GetTmpPluginDir()
+		vmPath := filepath.Join(pluginDir, invite.VMID)
+		if err := binutils.InstallVMBinaryFromURL(app, invite.VMBinaryURL, invite.VMBinarySHA256, binName, vmPath); err != nil {
+			return err
+		}
+		printJoinCmd(invite.SubnetID.String(), network, vmPath)
+		return nil
+	}
+
+	if err := setAvagoConfigPath(); err != nil {
+		return err
+	}
+	if err := setPluginDir(); err != nil {
+		return err
+	}
+	vmPath := filepath.Join(pluginDir, invite.VMID)
+	if err := binutils.InstallVMBinaryFromURL(app, invite.VMBinaryURL, invite.VMBinarySHA256, binName, vmPath); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("VM binary verified and written to %s", vmPath)
+
+	if err := writeChainConfigFiles(
+		dataDir,
+		invite.SubnetID,
+		invite.BlockchainID,
+		invite.SubnetConfig,
+		invite.ChainConfig,
+		invite.NetworkUpgrades,
+	); err != nil {
+		return err
+	}
+
+	return plugins.EditConfigFile(
+		app,
+		invite.SubnetID.String(),
+		network,
+		avagoConfigPath,
+		forceWrite,
+		"",
+	)
+}
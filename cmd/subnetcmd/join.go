@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
 	"github.com/MetalBlockchain/metal-cli/pkg/application"
 	"github.com/MetalBlockchain/metal-cli/pkg/binutils"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
@@ -34,8 +35,8 @@ import (
 const ewoqPChainAddr = "P-custom18jma8ppw3nhx5r4ap8clazz0dps7rv5u9xde7p"
 
 var (
-	joinAllSupportedNetworkOptions        = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Tahoe, networkoptions.Mainnet, networkoptions.Devnet, networkoptions.Cluster}
-	joinNonElasticSupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Tahoe, networkoptions.Mainnet, networkoptions.Devnet, networkoptions.Cluster}
+	joinAllSupportedNetworkOptions        = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Tahoe, networkoptions.Mainnet, networkoptions.Devnet, networkoptions.Environment, networkoptions.Cluster}
+	joinNonElasticSupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Tahoe, networkoptions.Mainnet, networkoptions.Devnet, networkoptions.Environment, networkoptions.Cluster}
 	joinElasticSupportedNetworkOptions    = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Tahoe}
 
 	// path to avalanchego config file
@@ -52,6 +53,10 @@ var (
 	joinElastic bool
 	// for permissionless subnet only: how much subnet native token will be staked in the validator
 	stakeAmount uint64
+	// for permissionless subnet only: P-Chain address receiving the validation rewards
+	rewardAddressStr string
+	// invite created with 'subnet invite', or path of its bundle file
+	inviteStr string
 )
 
 // avalanche subnet join
@@ -72,25 +77,41 @@ After you update your validator's config, you need to restart your validator man
 you provide the --avalanchego-config flag, this command attempts to edit the config file
 at that path.
 
+With --invite, the Subnet is taken from an invite created with 'metal subnet invite',
+instead of the local Subnet configuration. The VM binary is downloaded from the URL
+of the invite, and verified against its checksum.
+
 This command currently only supports Subnets deployed on the Tahoe Testnet and Mainnet.`,
 		SilenceUsage: true,
-		RunE:         joinCmd,
-		Args:         cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if inviteStr != "" {
+				if len(args) > 0 {
+					return errors.New("subnetName cannot be given with --invite")
+				}
+				return joinWithInvite()
+			}
+			return withActiveSubnet(joinCmd)(cmd, args)
+		},
+		Args: cobra.MaximumNArgs(1),
 	}
 	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, joinAllSupportedNetworkOptions)
 	cmd.Flags().StringVar(&avagoConfigPath, "avalanchego-config", "", "file path of the avalanchego config file")
 	cmd.Flags().StringVar(&pluginDir, "plugin-dir", "", "file path of avalanchego's plugin directory")
 	cmd.Flags().StringVar(&dataDir, "data-dir", "", "path of avalanchego's data dir directory")
 	cmd.Flags().BoolVar(&printManual, "print", false, "if true, print the manual config without prompting")
+	cmd.Flags().StringVar(&inviteStr, "invite", "", "join using an invite created with 'subnet invite', or the path of its bundle file")
 	cmd.Flags().StringVar(&nodeIDStr, "nodeID", "", "set the NodeID of the validator to check")
 	cmd.Flags().BoolVar(&forceWrite, "force-write", false, "if true, skip to prompt to overwrite the config file")
 	cmd.Flags().BoolVar(&joinElastic, "elastic", false, "set flag as true if joining elastic subnet")
 	cmd.Flags().Uint64Var(&stakeAmount, "stake-amount", 0, "amount of tokens to stake on validator")
-	cmd.Flags().StringVar(&startTimeStr, "start-time", "", "start time that validator starts validating")
+	cmd.Flags().StringVar(&startTimeStr, "start-time", "", "start time that validator starts validating, in 'YYYY-MM-DD HH:MM:SS' format (UTC unless --timezone is given), or RFC3339 format with offset")
+	cmd.Flags().StringVar(&startTimeZone, "timezone", "", "time zone of --start-time, and of the start time prompt (ex: Europe/Paris, local) (default UTC)")
 	cmd.Flags().DurationVar(&duration, "staking-period", 0, "how long validator validates for after start time")
+	cmd.Flags().StringVar(&rewardAddressStr, "reward-address", "", "P-Chain address receiving the validation rewards (defaults to the paying key address)")
 	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use [tahoe only]")
 	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on tahoe)")
 	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	flags.AddSignerFlag(cmd, &signerSpec)
 	return cmd
 }
 
@@ -179,6 +200,49 @@ func joinCmd(_ *cobra.Command, args []string) error {
 	// if choice is automatic, we just pass through this block
 	// or, pluginDir was set but not avagoConfigPath
 	// if **both** flags were set, this will be skipped...
+	if err := setAvagoConfigPath(); err != nil {
+		return err
+	}
+	if err := setPluginDir(); err != nil {
+		return err
+	}
+
+	vmPath, err := plugins.CreatePlugin(app, sc.Name, pluginDir)
+	if err != nil {
+		return err
+	}
+
+	ux.Logger.PrintToUser("VM binary written to %s", vmPath)
+
+	if forceWrite {
+		if err := writeAvagoChainConfigFiles(app, dataDir, subnetName, sc, network); err != nil {
+			return err
+		}
+	}
+
+	subnetAvagoConfigFile := ""
+	if app.AvagoNodeConfigExists(subnetName) {
+		subnetAvagoConfigFile = app.GetAvagoNodeConfigPath(subnetName)
+	}
+
+	if err := plugins.EditConfigFile(
+		app,
+		subnetIDStr,
+		network,
+		avagoConfigPath,
+		forceWrite,
+		subnetAvagoConfigFile,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// setAvagoConfigPath prompts for the avalanchego config file to update, if not
+// given with --avalanchego-config
+func setAvagoConfigPath() error {
+	var err error
 	if avagoConfigPath == "" {
 		avagoConfigPath, err = plugins.FindAvagoConfigPath()
 		if err != nil {
@@ -203,15 +267,14 @@ func joinCmd(_ *cobra.Command, args []string) error {
 			}
 		}
 	}
+	avagoConfigPath, err = plugins.SanitizePath(avagoConfigPath)
+	return err
+}
 
-	// ...but not this
-	avagoConfigPath, err := plugins.SanitizePath(avagoConfigPath)
-	if err != nil {
-		return err
-	}
-
-	// avagoConfigPath was set but not pluginDir
-	// if **both** flags were set, this will be skipped...
+// setPluginDir prompts for the avalanchego plugin dir to install the VM into, if not
+// given with --plugin-dir
+func setPluginDir() error {
+	var err error
 	if pluginDir == "" {
 		pluginDir, err = plugins.FindPluginDir()
 		if err != nil {
@@ -236,75 +299,72 @@ func joinCmd(_ *cobra.Command, args []string) error {
 			}
 		}
 	}
+	pluginDir, err = plugins.SanitizePath(pluginDir)
+	return err
+}
 
-	// ...but not this
-	pluginDir, err := plugins.SanitizePath(pluginDir)
-	if err != nil {
-		return err
-	}
-
-	vmPath, err := plugins.CreatePlugin(app, sc.Name, pluginDir)
-	if err != nil {
-		return err
+func writeAvagoChainConfigFiles(
+	app *application.Avalanche,
+	dataDir string,
+	subnetName string,
+	sc models.Sidecar,
+	network models.Network,
+) error {
+	subnetID := sc.Networks[network.Name()].SubnetID
+	if subnetID == ids.Empty {
+		return errNoSubnetID
 	}
+	blockchainID := sc.Networks[network.Name()].BlockchainID
 
-	ux.Logger.PrintToUser("VM binary written to %s", vmPath)
-
-	if forceWrite {
-		if err := writeAvagoChainConfigFiles(app, dataDir, subnetName, sc, network); err != nil {
+	var (
+		subnetConfig    []byte
+		chainConfig     []byte
+		networkUpgrades []byte
+		err             error
+	)
+	if app.AvagoSubnetConfigExists(subnetName) {
+		subnetConfig, err = app.LoadRawAvagoSubnetConfig(subnetName)
+		if err != nil {
 			return err
 		}
 	}
-
-	subnetAvagoConfigFile := ""
-	if app.AvagoNodeConfigExists(subnetName) {
-		subnetAvagoConfigFile = app.GetAvagoNodeConfigPath(subnetName)
+	if app.ChainConfigExists(subnetName) {
+		chainConfig, err = app.LoadRawChainConfig(subnetName)
+		if err != nil {
+			return err
+		}
 	}
-
-	if err := plugins.EditConfigFile(
-		app,
-		subnetIDStr,
-		network,
-		avagoConfigPath,
-		forceWrite,
-		subnetAvagoConfigFile,
-	); err != nil {
-		return err
+	if app.NetworkUpgradeExists(subnetName) {
+		networkUpgrades, err = app.LoadRawNetworkUpgrades(subnetName)
+		if err != nil {
+			return err
+		}
 	}
-
-	return nil
+	return writeChainConfigFiles(dataDir, subnetID, blockchainID, subnetConfig, chainConfig, networkUpgrades)
 }
 
-func writeAvagoChainConfigFiles(
-	app *application.Avalanche,
+// writeChainConfigFiles writes the subnet config, chain config and network upgrades
+// of a subnet into the avalanchego [dataDir], removing the ones that are not given
+func writeChainConfigFiles(
 	dataDir string,
-	subnetName string,
-	sc models.Sidecar,
-	network models.Network,
+	subnetID ids.ID,
+	blockchainID ids.ID,
+	subnetConfig []byte,
+	chainConfig []byte,
+	networkUpgrades []byte,
 ) error {
 	if dataDir == "" {
 		dataDir = utils.UserHomePath(".avalanchego")
 	}
 
-	subnetID := sc.Networks[network.Name()].SubnetID
-	if subnetID == ids.Empty {
-		return errNoSubnetID
-	}
-	subnetIDStr := subnetID.String()
-	blockchainID := sc.Networks[network.Name()].BlockchainID
-
 	configsPath := filepath.Join(dataDir, "configs")
 
 	subnetConfigsPath := filepath.Join(configsPath, "subnets")
-	subnetConfigPath := filepath.Join(subnetConfigsPath, subnetIDStr+".json")
-	if app.AvagoSubnetConfigExists(subnetName) {
+	subnetConfigPath := filepath.Join(subnetConfigsPath, subnetID.String()+".json")
+	if subnetConfig != nil {
 		if err := os.MkdirAll(subnetConfigsPath, constants.DefaultPerms755); err != nil {
 			return err
 		}
-		subnetConfig, err := app.LoadRawAvagoSubnetConfig(subnetName)
-		if err != nil {
-			return err
-		}
 		if err := os.WriteFile(subnetConfigPath, subnetConfig, constants.DefaultPerms755); err != nil {
 			return err
 		}
@@ -312,17 +372,13 @@ func writeAvagoChainConfigFiles(
 		_ = os.RemoveAll(subnetConfigPath)
 	}
 
-	if blockchainID != ids.Empty && app.ChainConfigExists(subnetName) || app.NetworkUpgradeExists(subnetName) {
+	if blockchainID != ids.Empty && chainConfig != nil || networkUpgrades != nil {
 		chainConfigsPath := filepath.Join(configsPath, "chains", blockchainID.String())
 		if err := os.MkdirAll(chainConfigsPath, constants.DefaultPerms755); err != nil {
 			return err
 		}
 		chainConfigPath := filepath.Join(chainConfigsPath, "config.json")
-		if app.ChainConfigExists(subnetName) {
-			chainConfig, err := app.LoadRawChainConfig(subnetName)
-			if err != nil {
-				return err
-			}
+		if chainConfig != nil {
 			if err := os.WriteFile(chainConfigPath, chainConfig, constants.DefaultPerms755); err != nil {
 				return err
 			}
@@ -330,11 +386,7 @@ func writeAvagoChainConfigFiles(
 			_ = os.RemoveAll(chainConfigPath)
 		}
 		networkUpgradesPath := filepath.Join(chainConfigsPath, "upgrade.json")
-		if app.NetworkUpgradeExists(subnetName) {
-			networkUpgrades, err := app.LoadRawNetworkUpgrades(subnetName)
-			if err != nil {
-				return err
-			}
+		if networkUpgrades != nil {
 			if err := os.WriteFile(networkUpgradesPath, networkUpgrades, constants.DefaultPerms755); err != nil {
 				return err
 			}
@@ -352,13 +404,13 @@ func handleValidatorJoinElasticSubnet(sc models.Sidecar, network models.Network,
 		useLedger = true
 	}
 
-	if useLedger && keyName != "" {
+	if !flags.EnsureMutuallyExclusive([]bool{useLedger, keyName != "", signerSpec != ""}) {
 		return ErrMutuallyExlusiveKeyLedger
 	}
 
 	subnetID := sc.Networks[network.Name()].SubnetID
 	if os.Getenv(constants.SimulatePublicNetwork) != "" {
-		subnetID = sc.Networks[models.Local.String()].SubnetID
+		subnetID = sc.Networks[models.NewLocalNetwork().Name()].SubnetID
 	}
 	if subnetID == ids.Empty {
 		return errNoSubnetID
@@ -383,7 +435,7 @@ func handleValidatorJoinElasticSubnet(sc models.Sidecar, network models.Network,
 	case models.Local:
 		return handleValidatorJoinElasticSubnetLocal(sc, network, subnetName, nodeID, stakedTokenAmount, start, endTime)
 	case models.Tahoe:
-		if !useLedger && keyName == "" {
+		if !useLedger && keyName == "" && signerSpec == "" {
 			useLedger, keyName, err = prompts.GetFujiKeyOrLedger(app.Prompt, constants.PayTxsFeesMsg, app.GetKeyDir())
 			if err != nil {
 				return err
@@ -397,19 +449,26 @@ func handleValidatorJoinElasticSubnet(sc models.Sidecar, network models.Network,
 
 	// get keychain accessor
 	fee := network.GenesisParams().AddSubnetValidatorFee
-	kc, err := keychain.GetKeychain(app, false, useLedger, ledgerAddresses, keyName, network, fee)
+	kc, err := keychain.GetKeychain(app, models.KeyRoleValidatorManager, false, useLedger, ledgerAddresses, signerSpec, keyName, network, fee)
 	if err != nil {
 		return err
 	}
 
 	network.HandlePublicNetworkSimulation()
 
-	recipientAddr := kc.Addresses().List()[0]
+	recipientAddr, err := promptRewardAddress(kc.Addresses().List()[0], network)
+	if err != nil {
+		return err
+	}
 	deployer := subnet.NewPublicDeployer(app, kc, network)
 	assetID, err := getSubnetAssetID(subnetID, network)
 	if err != nil {
 		return err
 	}
+	pClient := platformvm.NewClient(network.Endpoint)
+	if err := checkStakeableAssetBalance(pClient, kc.Addresses().List(), assetID, stakedTokenAmount); err != nil {
+		return err
+	}
 	delegationFee := network.GenesisParams().MinDelegationFee
 	txID, err := deployer.AddPermissionlessValidator(subnetID, assetID, nodeID, stakedTokenAmount, uint64(start.Unix()), uint64(endTime.Unix()), recipientAddr, delegationFee, nil, nil)
 	if err != nil {
@@ -437,8 +496,8 @@ func printAddPermissionlessValOutput(txID ids.ID, nodeID ids.NodeID, network mod
 	ux.Logger.PrintToUser("TX ID: %s", txID.String())
 	ux.Logger.PrintToUser("NodeID: %s", nodeID.String())
 	ux.Logger.PrintToUser("Network: %s", network.Name())
-	ux.Logger.PrintToUser("Start time: %s", start.UTC().Format(constants.TimeParseLayout))
-	ux.Logger.PrintToUser("End time: %s", endTime.Format(constants.TimeParseLayout))
+	ux.Logger.PrintToUser("Start time: %s", formatTime(start))
+	ux.Logger.PrintToUser("End time: %s", formatTime(endTime))
 	ux.Logger.PrintToUser("Stake Amount: %d", stakedTokenAmount)
 }
 
@@ -451,11 +510,19 @@ func handleValidatorJoinElasticSubnetLocal(sc models.Sidecar, network models.Net
 	if !checkIfSubnetIsElasticOnLocal(sc) {
 		return fmt.Errorf("%s is not an elastic subnet", subnetName)
 	}
-	assetID := sc.ElasticSubnet[models.Local.String()].AssetID
+	assetID := sc.ElasticSubnet[models.NewLocalNetwork().Name()].AssetID
 	testKey := genesis.EWOQKey
 	keyChain := secp256k1fx.NewKeychain(testKey)
-	subnetID := sc.Networks[models.Local.String()].SubnetID
-	txID, err := subnet.IssueAddPermissionlessValidatorTx(keyChain, subnetID, nodeID, stakedTokenAmount, assetID, uint64(start.Unix()), uint64(endTime.Unix()))
+	subnetID := sc.Networks[models.NewLocalNetwork().Name()].SubnetID
+	rewardAddr, err := promptRewardAddress(testKey.PublicKey().Address(), network)
+	if err != nil {
+		return err
+	}
+	pClient := platformvm.NewClient(models.LocalAPIEndpoint())
+	if err := checkStakeableAssetBalance(pClient, keyChain.Addresses().List(), assetID, stakedTokenAmount); err != nil {
+		return err
+	}
+	txID, err := subnet.IssueAddPermissionlessValidatorTx(keyChain, subnetID, nodeID, stakedTokenAmount, assetID, uint64(start.Unix()), uint64(endTime.Unix()), rewardAddr)
 	if err != nil {
 		return err
 	}
@@ -583,7 +650,7 @@ func promptStakeAmount(subnetName string, isValidator bool, network models.Netwo
 		if err != nil {
 			return 0, err
 		}
-		pClient := platformvm.NewClient(constants.LocalAPIEndpoint)
+		pClient := platformvm.NewClient(models.LocalAPIEndpoint())
 		walletBalance, err := getAssetBalance(pClient, ewoqPChainAddr, esc.AssetID)
 		if err != nil {
 			return 0, err
@@ -679,3 +746,53 @@ func getAssetBalance(pClient platformvm.Client, addr string, assetID ids.ID) (ui
 	assetIDBalance := resp.Balances[assetID]
 	return uint64(assetIDBalance), nil
 }
+
+// checkStakeableAssetBalance verifies that [addrs] hold at least [amount] of [assetID]
+// that can be used for staking, ie unlocked or locked stakeable
+func checkStakeableAssetBalance(pClient platformvm.Client, addrs []ids.ShortID, assetID ids.ID, amount uint64) error {
+	ctx, cancel := utils.GetAPIContext()
+	resp, err := pClient.GetBalance(ctx, addrs)
+	cancel()
+	if err != nil {
+		return err
+	}
+	stakeable := uint64(resp.Unlockeds[assetID]) + uint64(resp.LockedStakeables[assetID])
+	if stakeable < amount {
+		return fmt.Errorf("%w: stake amount %d exceeds the stakeable balance %d of asset %s", subnet.ErrInsufficientFunds, amount, stakeable, assetID)
+	}
+	return nil
+}
+
+// promptRewardAddress returns the address given by --reward-address. If not given,
+// the user is asked to choose between [defaultAddr] and a custom address. On local
+// networks [defaultAddr] is used without prompting
+func promptRewardAddress(defaultAddr ids.ShortID, network models.Network) (ids.ShortID, error) {
+	if rewardAddressStr == "" {
+		if network.Kind == models.Local {
+			return defaultAddr, nil
+		}
+		const (
+			payingKeyOption = "Use the paying key address"
+			customOption    = "Custom P-Chain address"
+		)
+		option, err := app.Prompt.CaptureList(
+			"Which address should receive the staking rewards?",
+			[]string{payingKeyOption, customOption},
+		)
+		if err != nil {
+			return ids.ShortEmpty, err
+		}
+		if option == payingKeyOption {
+			return defaultAddr, nil
+		}
+		rewardAddressStr, err = app.Prompt.CapturePChainAddress("Reward address", network)
+		if err != nil {
+			return ids.ShortEmpty, err
+		}
+	}
+	rewardAddr, err := address.ParseToID(rewardAddressStr)
+	if err != nil {
+		return ids.ShortEmpty, fmt.Errorf("invalid reward address %q: %w", rewardAddressStr, err)
+	}
+	return rewardAddr, nil
+}
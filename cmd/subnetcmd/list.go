@@ -9,10 +9,12 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
 	"github.com/MetalBlockchain/metal-cli/pkg/application"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
 	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
 	"github.com/MetalBlockchain/metal-network-runner/utils"
 	"github.com/MetalBlockchain/metalgo/ids"
 	"github.com/olekukonko/tablewriter"
@@ -33,6 +35,7 @@ shows additional information including the VMID, BlockchainID and SubnetID.`,
 		SilenceUsage: true,
 	}
 	cmd.Flags().BoolVar(&deployed, "deployed", false, "show additional deploy information")
+	flags.AddJSONOutputFlag(cmd)
 	return cmd
 }
 
@@ -100,6 +103,21 @@ func listSubnets(cmd *cobra.Command, args []string) error {
 		})
 	}
 	sort.Sort(rows)
+	if ux.JSONOutput() {
+		entries := []subnetListEntry{}
+		for _, row := range rows {
+			entries = append(entries, subnetListEntry{
+				Subnet:    row[0],
+				Chain:     row[1],
+				ChainID:   row[2],
+				VMID:      row[3],
+				VM:        row[4],
+				VMVersion: row[5],
+				FromRepo:  row[6] == strconv.FormatBool(true),
+			})
+		}
+		return ux.PrintResult(entries)
+	}
 	for _, row := range rows {
 		table.Append(row)
 	}
@@ -107,6 +125,17 @@ func listSubnets(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// subnetListEntry is a row of subnet list, as printed with --json
+type subnetListEntry struct {
+	Subnet    string `json:"subnet"`
+	Chain     string `json:"chain"`
+	ChainID   string `json:"chainID"`
+	VMID      string `json:"vmID"`
+	VM        string `json:"vm"`
+	VMVersion string `json:"vmVersion"`
+	FromRepo  bool   `json:"fromRepo"`
+}
+
 func getSidecars(app *application.Avalanche) ([]*models.Sidecar, error) {
 	subnets, err := os.ReadDir(filepath.Join(app.GetBaseDir(), constants.SubnetDir))
 	if err != nil {
@@ -149,7 +178,7 @@ func listDeployInfo(*cobra.Command, []string) error {
 
 	rows := subnetMatrix{}
 
-	deployedNames, err := subnet.GetLocallyDeployedSubnets()
+	deployedNames, err := subnet.GetLocallyDeployedSubnets(app)
 	if err != nil {
 		// if the server can not be contacted, or there is a problem with the query,
 		// DO NOT FAIL, just print No for deployed status
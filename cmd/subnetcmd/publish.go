@@ -42,12 +42,19 @@ type newPublisherFunc func(string, string, string) subnet.Publisher
 // avalanche subnet publish
 func newPublishCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:          "publish [subnetName]",
-		Short:        "Publish the subnet's VM to a repository",
-		Long:         `The subnet publish command publishes the Subnet's VM to a repository.`,
+		Use:   "publish [subnetName]",
+		Short: "Publish the subnet's VM to a repository",
+		Long: `The subnet publish command publishes the Subnet's VM to a repository.
+
+With --registry, it instead packages the Subnet genesis, configs and VM details
+into a descriptor signed with --key, and pushes it to a subnet registry, from
+where validators can get it with subnet import --from-registry. Registries can be
+git repositories (URLs ending in .git, or ssh URLs), HTTP servers accepting PUT
+requests (authenticated with the METAL_REGISTRY_TOKEN env var), or directories
+given as file:// URLs.`,
 		SilenceUsage: true,
-		RunE:         publish,
-		Args:         cobra.ExactArgs(1),
+		RunE:         withActiveSubnet(publish),
+		Args:         cobra.MaximumNArgs(1),
 	}
 	cmd.Flags().StringVar(&alias, "alias", "",
 		"We publish to a remote repo, but identify the repo locally under a user-provided alias (e.g. myrepo).")
@@ -60,6 +67,8 @@ func newPublishCmd() *cobra.Command {
 		"Do not let the tool manage file publishing, but have it only generate the files and put them in the location given by this flag.")
 	cmd.Flags().BoolVar(&forceWrite, forceFlag, false,
 		"If true, ignores if the subnet has been published in the past, and attempts a forced publish.")
+	cmd.Flags().StringVar(&registryURL, "registry", "", "publish a signed subnet descriptor to the subnet registry at this URL")
+	cmd.Flags().StringVar(&registryKeyName, "key", "", "name of the key to sign the subnet descriptor with")
 	return cmd
 }
 
@@ -76,6 +85,9 @@ func publish(_ *cobra.Command, args []string) error {
 	if !isReadyToPublish(&sc) {
 		return errSubnetNotDeployed
 	}
+	if registryURL != "" {
+		return publishToRegistry(sc)
+	}
 	return doPublish(&sc, subnetName, subnet.NewPublisher)
 }
 
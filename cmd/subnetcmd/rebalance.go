@@ -0,0 +1,390 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/keychain"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
+	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
+	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
+	"github.com/MetalBlockchain/metal-cli/pkg/txutils"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	rebalanceTargetWeight uint64
+	rebalanceWeightsFile  string
+	executeRebalance      bool
+)
+
+// avalanche subnet rebalance
+func newRebalanceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rebalance [subnetName]",
+		Short: "Propose and apply changes to the stake weight distribution",
+		Long: `The subnet rebalance command shows the stake weight distribution of the subnet
+validators, and the operations needed to reach a target distribution.
+
+By default the target gives every validator the same weight, the current total
+weight divided equally, or --target-weight if given. A custom distribution can
+be given with --weights-file, a JSON file mapping NodeIDs to weights. Validators
+not in the file keep their weight.
+
+The weight of a validator can't be changed in place, so each validator off
+target is removed and added again with the target weight, keeping its current
+end time. With --execute, the operations are issued one validator at a time
+after confirmation. Each validator is out of the validator set for a short time
+while its operations are processed.`,
+		SilenceUsage: true,
+		RunE:         withActiveSubnet(rebalanceSubnet),
+		Args:         cobra.MaximumNArgs(1),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, addValidatorSupportedNetworkOptions)
+	cmd.Flags().Uint64Var(&rebalanceTargetWeight, "target-weight", 0, "weight to give to every validator (default is the current total weight divided equally)")
+	cmd.Flags().StringVar(&rebalanceWeightsFile, "weights-file", "", "JSON file mapping NodeIDs to target weights")
+	cmd.Flags().BoolVar(&executeRebalance, "execute", false, "issue the proposed operations, after confirmation")
+
+	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use [tahoe/devnet only]")
+	cmd.Flags().StringSliceVar(&subnetAuthKeys, "subnet-auth-keys", nil, "control keys that will be used to authenticate the validator txs")
+	cmd.Flags().BoolVarP(&useEwoq, "ewoq", "e", false, "use ewoq key [tahoe/devnet only]")
+	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on tahoe/devnet)")
+	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	flags.AddSignerFlag(cmd, &signerSpec)
+	flags.AddJSONOutputFlag(cmd)
+	return cmd
+}
+
+// rebalanceOperation changes the weight of a validator, by removing it and
+// adding it again
+type rebalanceOperation struct {
+	NodeID        ids.NodeID `json:"nodeID"`
+	CurrentWeight uint64     `json:"currentWeight"`
+	TargetWeight  uint64     `json:"targetWeight"`
+	EndTime       time.Time  `json:"endTime"`
+}
+
+// rebalancePlan is the current distribution of a subnet and the operations to
+// reach the target one, as printed by subnet rebalance
+type rebalancePlan struct {
+	Subnet     string               `json:"subnet"`
+	Network    string               `json:"network"`
+	Current    map[string]uint64    `json:"current"`
+	Target     map[string]uint64    `json:"target"`
+	Operations []rebalanceOperation `json:"operations"`
+}
+
+func rebalanceSubnet(_ *cobra.Command, args []string) error {
+	subnetName := args[0]
+	if rebalanceTargetWeight != 0 && rebalanceWeightsFile != "" {
+		return ux.NewCodedError(ux.ErrCodeInvalidArguments, fmt.Errorf("--target-weight and --weights-file are mutually exclusive"))
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		globalNetworkFlags,
+		true,
+		addValidatorSupportedNetworkOptions,
+		subnetName,
+	)
+	if err != nil {
+		return err
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	subnetID := sc.Networks[network.Name()].SubnetID
+	if subnetID == ids.Empty {
+		return errNoSubnetID
+	}
+	if sc.ElasticSubnet[network.Name()].SubnetID != ids.Empty {
+		return fmt.Errorf("subnet %s is elastic on %s, its validators stake weight can't be set by the subnet owner", subnetName, network.Name())
+	}
+
+	var validators []platformvm.ClientPermissionlessValidator
+	if network.Kind == models.Local {
+		validators, err = subnet.GetSubnetValidators(subnetID)
+	} else {
+		validators, err = subnet.GetPublicSubnetValidators(subnetID, network)
+	}
+	if err != nil {
+		return err
+	}
+	if len(validators) == 0 {
+		return fmt.Errorf("subnet %s has no validators on %s", subnetName, network.Name())
+	}
+
+	limits, err := subnet.GetStakeWeightLimits(app, network, subnetID)
+	if err != nil {
+		return err
+	}
+	var targets map[ids.NodeID]uint64
+	if rebalanceWeightsFile != "" {
+		targets, err = loadRebalanceWeights(rebalanceWeightsFile, validators, limits)
+	} else {
+		targets, err = getEqualTargetWeights(validators, rebalanceTargetWeight, limits)
+	}
+	if err != nil {
+		return err
+	}
+	plan := rebalancePlan{
+		Subnet:     subnetName,
+		Network:    network.Name(),
+		Current:    map[string]uint64{},
+		Target:     map[string]uint64{},
+		Operations: planRebalance(validators, targets),
+	}
+	for _, v := range validators {
+		plan.Current[v.NodeID.String()] = v.Weight
+		plan.Target[v.NodeID.String()] = targets[v.NodeID]
+	}
+
+	if ux.JSONOutput() && !executeRebalance {
+		return ux.PrintResult(plan)
+	}
+	printRebalancePlan(validators, targets)
+	if len(plan.Operations) == 0 {
+		ux.Logger.PrintToUser("The subnet weights already match the target distribution")
+		return nil
+	}
+	ux.Logger.PrintToUser("Proposed operations:")
+	for i, op := range plan.Operations {
+		ux.Logger.PrintToUser("  %d. remove %s, and add it again with weight %d until %s",
+			i+1, op.NodeID, op.TargetWeight, op.EndTime.UTC().Format(constants.TimeParseLayout))
+	}
+	if !executeRebalance {
+		ux.Logger.PrintToUser("Use --execute to issue them")
+		return nil
+	}
+	return executeRebalancePlan(network, subnetName, subnetID, sc.Networks[network.Name()].TransferSubnetOwnershipTxID, len(validators), plan.Operations)
+}
+
+// getEqualTargetWeights gives all [validators] [targetWeight], or the current
+// total weight divided equally if it is 0. The weight must be within [limits]
+func getEqualTargetWeights(validators []platformvm.ClientPermissionlessValidator, targetWeight uint64, limits subnet.StakeWeightLimits) (map[ids.NodeID]uint64, error) {
+	if targetWeight == 0 {
+		total := uint64(0)
+		for _, v := range validators {
+			total += v.Weight
+		}
+		targetWeight = total / uint64(len(validators))
+	}
+	if err := limits.Check(targetWeight); err != nil {
+		return nil, err
+	}
+	targets := map[ids.NodeID]uint64{}
+	for _, v := range validators {
+		targets[v.NodeID] = targetWeight
+	}
+	return targets, nil
+}
+
+// loadRebalanceWeights reads the target weights of [validators] from the JSON
+// file at [path]. Validators not in the file keep their weight. The weights in
+// the file must be within [limits]
+func loadRebalanceWeights(path string, validators []platformvm.ClientPermissionlessValidator, limits subnet.StakeWeightLimits) (map[ids.NodeID]uint64, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fileWeights := map[string]uint64{}
+	if err := json.Unmarshal(bs, &fileWeights); err != nil {
+		return nil, fmt.Errorf("invalid weights file %s, expected a JSON object mapping NodeIDs to weights: %w", path, err)
+	}
+	targets := map[ids.NodeID]uint64{}
+	for _, v := range validators {
+		targets[v.NodeID] = v.Weight
+	}
+	for nodeIDStr, w := range fileWeights {
+		nodeID, err := ids.NodeIDFromString(nodeIDStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid NodeID %q in weights file %s: %w", nodeIDStr, path, err)
+		}
+		if _, ok := targets[nodeID]; !ok {
+			return nil, fmt.Errorf("node %s in weights file %s is not a validator of the subnet. Use 'metal subnet addValidator' to add it", nodeID, path)
+		}
+		if err := limits.Check(w); err != nil {
+			return nil, fmt.Errorf("%s: %w", nodeID, err)
+		}
+		targets[nodeID] = w
+	}
+	return targets, nil
+}
+
+// planRebalance returns the operations to change the weights of [validators] to
+// [targets], sorted by NodeID
+func planRebalance(validators []platformvm.ClientPermissionlessValidator, targets map[ids.NodeID]uint64) []rebalanceOperation {
+	operations := []rebalanceOperation{}
+	for _, v := range validators {
+		target, ok := targets[v.NodeID]
+		if !ok || target == v.Weight {
+			continue
+		}
+		operations = append(operations, rebalanceOperation{
+			NodeID:        v.NodeID,
+			CurrentWeight: v.Weight,
+			TargetWeight:  target,
+			EndTime:       time.Unix(int64(v.EndTime), 0),
+		})
+	}
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].NodeID.Compare(operations[j].NodeID) < 0
+	})
+	return operations
+}
+
+func formatWeightShare(weight uint64, total uint64) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", float64(weight)*100/float64(total))
+}
+
+func printRebalancePlan(validators []platformvm.ClientPermissionlessValidator, targets map[ids.NodeID]uint64) {
+	currentTotal, targetTotal := uint64(0), uint64(0)
+	for _, v := range validators {
+		currentTotal += v.Weight
+		targetTotal += targets[v.NodeID]
+	}
+	sortedValidators := slices.Clone(validators)
+	sort.Slice(sortedValidators, func(i, j int) bool {
+		return sortedValidators[i].NodeID.Compare(sortedValidators[j].NodeID) < 0
+	})
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"NodeID", "Weight", "Share", "Target Weight", "Target Share"})
+	table.SetRowLine(true)
+	for _, v := range sortedValidators {
+		table.Append([]string{
+			v.NodeID.String(),
+			strconv.FormatUint(v.Weight, 10),
+			formatWeightShare(v.Weight, currentTotal),
+			strconv.FormatUint(targets[v.NodeID], 10),
+			formatWeightShare(targets[v.NodeID], targetTotal),
+		})
+	}
+	table.Render()
+}
+
+func executeRebalancePlan(
+	network models.Network,
+	subnetName string,
+	subnetID ids.ID,
+	transferSubnetOwnershipTxID ids.ID,
+	numValidators int,
+	operations []rebalanceOperation,
+) error {
+	if numValidators == 1 {
+		ux.Logger.PrintToUser("WARNING: the subnet has a single validator, it will halt while the validator is added again")
+	}
+	yes, err := app.Prompt.CaptureNoYes(fmt.Sprintf("Issue the %d remove and %d add validator txs above?", len(operations), len(operations)))
+	if err != nil {
+		return err
+	}
+	if !yes {
+		ux.Logger.PrintToUser("Rebalance cancelled")
+		return nil
+	}
+
+	fee := network.GenesisParams().AddSubnetValidatorFee * uint64(2*len(operations))
+	kc, err := keychain.GetKeychainFromCmdLineFlags(
+		app,
+		constants.PayTxsFeesMsg,
+		models.KeyRoleValidatorManager,
+		network,
+		keyName,
+		useEwoq,
+		useLedger,
+		ledgerAddresses,
+		signerSpec,
+		fee,
+	)
+	if err != nil {
+		return err
+	}
+	network.HandlePublicNetworkSimulation()
+	if err := UpdateKeychainWithSubnetControlKeys(kc, network, subnetName); err != nil {
+		return err
+	}
+	controlKeys, threshold, err := txutils.GetOwners(network, subnetID, transferSubnetOwnershipTxID)
+	if err != nil {
+		return err
+	}
+	kcKeys, err := kc.PChainFormattedStrAddresses()
+	if err != nil {
+		return err
+	}
+	if subnetAuthKeys != nil {
+		if err := prompts.CheckSubnetAuthKeys(kcKeys, subnetAuthKeys, controlKeys, threshold); err != nil {
+			return err
+		}
+	} else {
+		subnetAuthKeys, err = prompts.GetSubnetAuthKeys(app.Prompt, kcKeys, controlKeys, threshold)
+		if err != nil {
+			return err
+		}
+	}
+	// a batch can't be left half signed, as validators would be left removed
+	for _, authKey := range subnetAuthKeys {
+		if !slices.Contains(kcKeys, authKey) {
+			return fmt.Errorf("subnet auth key %s is not available. Executing a rebalance needs all the subnet auth keys, remove and add the validators one by one otherwise", authKey)
+		}
+	}
+
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	deployer := subnet.NewPublicDeployer(app, kc, network)
+	for i, op := range operations {
+		ux.Logger.PrintToUser("[%d/%d] Removing validator %s", i+1, len(operations), op.NodeID)
+		if _, _, _, err := deployer.RemoveValidator(
+			controlKeys,
+			subnetAuthKeys,
+			subnetID,
+			transferSubnetOwnershipTxID,
+			op.NodeID,
+		); err != nil {
+			return fmt.Errorf("failed removing validator %s: %w", op.NodeID, err)
+		}
+		// as on renewals, the validator is added back as soon as possible
+		start := time.Now().Add(renewalStartLeadTime)
+		if !start.Before(op.EndTime) {
+			ux.Logger.PrintToUser("Validator %s period ended meanwhile, it is not added again", op.NodeID)
+			continue
+		}
+		ux.Logger.PrintToUser("[%d/%d] Adding validator %s with weight %d", i+1, len(operations), op.NodeID, op.TargetWeight)
+		if _, _, _, err := deployer.AddValidator(
+			false,
+			controlKeys,
+			subnetAuthKeys,
+			subnetID,
+			transferSubnetOwnershipTxID,
+			op.NodeID,
+			op.TargetWeight,
+			start,
+			op.EndTime.Sub(start),
+		); err != nil {
+			_ = app.UpdateSidecarTxHistory(&sc, network, deployer.IssuedTxs())
+			return fmt.Errorf("failed adding validator %s again, add it with 'metal subnet addValidator': %w", op.NodeID, err)
+		}
+	}
+	if err := app.UpdateSidecarTxHistory(&sc, network, deployer.IssuedTxs()); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Subnet %s rebalanced", subnetName)
+	return nil
+}
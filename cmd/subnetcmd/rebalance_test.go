@@ -0,0 +1,61 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestValidator(nodeID ids.NodeID, weight uint64, endTime uint64) platformvm.ClientPermissionlessValidator {
+	return platformvm.ClientPermissionlessValidator{
+		ClientStaker: platformvm.ClientStaker{NodeID: nodeID, Weight: weight, EndTime: endTime},
+	}
+}
+
+func TestPlanRebalance(t *testing.T) {
+	require := require.New(t)
+	nodeID1, nodeID2, nodeID3 := ids.GenerateTestNodeID(), ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	validators := []platformvm.ClientPermissionlessValidator{
+		newTestValidator(nodeID1, 60, 1000),
+		newTestValidator(nodeID2, 20, 2000),
+		newTestValidator(nodeID3, 20, 3000),
+	}
+	limits := subnet.StakeWeightLimits{Min: 1, Max: 50}
+
+	targets, err := getEqualTargetWeights(validators, 0, limits)
+	require.NoError(err)
+	require.Equal(map[ids.NodeID]uint64{nodeID1: 33, nodeID2: 33, nodeID3: 33}, targets)
+	operations := planRebalance(validators, targets)
+	require.Len(operations, 3)
+
+	targets, err = getEqualTargetWeights(validators, 20, limits)
+	require.NoError(err)
+	operations = planRebalance(validators, targets)
+	require.Equal([]rebalanceOperation{
+		{NodeID: nodeID1, CurrentWeight: 60, TargetWeight: 20, EndTime: time.Unix(1000, 0)},
+	}, operations)
+
+	weightsPath := filepath.Join(t.TempDir(), "weights.json")
+	require.NoError(os.WriteFile(weightsPath, []byte(`{"`+nodeID2.String()+`": 40}`), 0o600))
+	targets, err = loadRebalanceWeights(weightsPath, validators, limits)
+	require.NoError(err)
+	require.Equal(map[ids.NodeID]uint64{nodeID1: 60, nodeID2: 40, nodeID3: 20}, targets)
+
+	require.NoError(os.WriteFile(weightsPath, []byte(`{"`+ids.GenerateTestNodeID().String()+`": 40}`), 0o600))
+	_, err = loadRebalanceWeights(weightsPath, validators, limits)
+	require.ErrorContains(err, "is not a validator")
+
+	require.NoError(os.WriteFile(weightsPath, []byte(`{"`+nodeID2.String()+`": 51}`), 0o600))
+	_, err = loadRebalanceWeights(weightsPath, validators, limits)
+	require.ErrorContains(err, "must be between 1 and 50")
+	_, err = getEqualTargetWeights(validators, 0, subnet.StakeWeightLimits{Min: 40, Max: 50})
+	require.ErrorContains(err, "must be between 40 and 50")
+}
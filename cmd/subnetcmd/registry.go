@@ -0,0 +1,146 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/key"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
+	"github.com/MetalBlockchain/metal-cli/pkg/registry"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/formatting/address"
+	"golang.org/x/exp/maps"
+)
+
+var (
+	registryURL       string
+	registryKeyName   string
+	fromRegistry      string
+	trustedSignerStrs []string
+)
+
+// publishToRegistry signs the subnet configuration of [sc] and pushes it to the registry
+func publishToRegistry(sc models.Sidecar) error {
+	if sc.VM == models.CustomVM && sc.CustomVMRepoURL == "" {
+		return errors.New("only custom VMs built from a repository can be published to a registry, as importers build them again")
+	}
+	exportable, err := getExportable(sc)
+	if err != nil {
+		return err
+	}
+	// local deploys are of no use to importers
+	exportable.Sidecar.Networks = maps.Clone(sc.Networks)
+	maps.DeleteFunc(exportable.Sidecar.Networks, func(network string, _ models.NetworkData) bool {
+		return models.IsLocalNetworkName(network)
+	})
+	exportable.Sidecar.TxHistory = maps.Clone(sc.TxHistory)
+	maps.DeleteFunc(exportable.Sidecar.TxHistory, func(network string, _ []models.TxRecord) bool {
+		return models.IsLocalNetworkName(network)
+	})
+	vmID, err := sc.GetVMID()
+	if err != nil {
+		return err
+	}
+	if registryKeyName == "" {
+		registryKeyName, err = prompts.CaptureKeyName(app.Prompt, "sign the subnet descriptor", app.GetKeyDir())
+		if err != nil {
+			return err
+		}
+	}
+	if !app.KeyExists(registryKeyName) {
+		return fmt.Errorf("key %s does not exist", registryKeyName)
+	}
+	sk, err := key.LoadSoft(models.NewMainnetNetwork().ID, app.GetKeyPath(registryKeyName))
+	if err != nil {
+		return err
+	}
+	descriptor, err := registry.NewDescriptor(registry.Contents{
+		Name:        sc.Name,
+		VMID:        vmID,
+		PublishedAt: time.Now().UTC(),
+		Subnet:      exportable,
+	}, sk)
+	if err != nil {
+		return err
+	}
+	reg, err := registry.New(registryURL, app.GetReposDir())
+	if err != nil {
+		return err
+	}
+	if err := reg.Push(sc.Name, descriptor); err != nil {
+		return fmt.Errorf("failed publishing to registry %s: %w", registryURL, err)
+	}
+	ux.Logger.PrintToUser("Subnet %s published to %s", sc.Name, registryURL)
+	ux.Logger.PrintToUser("Signed by %s. Importers can require it with --trusted-signer %s", sk.P()[0], sk.P()[0])
+	return nil
+}
+
+// importFromRegistry pulls the descriptor of subnet [name] from the registry,
+// verifies it, and imports its configuration
+func importFromRegistry(name string) error {
+	if registryURL == "" {
+		return errors.New("the registry to import from must be given with --registry")
+	}
+	trustedSigners := map[ids.ShortID]struct{}{}
+	if len(trustedSignerStrs) > 0 {
+		resolved, err := app.ResolveAddressBookLabels(trustedSignerStrs)
+		if err != nil {
+			return err
+		}
+		for _, s := range resolved {
+			signer, err := registry.ParseSigner(s)
+			if err != nil {
+				return err
+			}
+			trustedSigners[signer] = struct{}{}
+		}
+	}
+	reg, err := registry.New(registryURL, app.GetReposDir())
+	if err != nil {
+		return err
+	}
+	descriptor, err := reg.Pull(name)
+	if err != nil {
+		return fmt.Errorf("failed getting subnet %s from registry %s: %w", name, registryURL, err)
+	}
+	contents, err := descriptor.Verify()
+	if err != nil {
+		return err
+	}
+	signer, err := ids.ShortFromString(descriptor.Signer)
+	if err != nil {
+		return fmt.Errorf("%w: %w", registry.ErrInvalidSignature, err)
+	}
+	signerAddr, err := address.Format("P", key.GetHRP(models.NewMainnetNetwork().ID), signer.Bytes())
+	if err != nil {
+		return err
+	}
+	if len(trustedSigners) > 0 {
+		if _, ok := trustedSigners[signer]; !ok {
+			return fmt.Errorf("subnet %s was signed by %s, which is not a trusted signer", name, signerAddr)
+		}
+	} else {
+		ux.Logger.PrintToUser("Warning: no --trusted-signer given, the publisher %s is not being checked", signerAddr)
+	}
+	if contents.Name != name || contents.Subnet.Sidecar.Name != name {
+		return fmt.Errorf("registry descriptor for %s describes subnet %s instead", name, contents.Subnet.Sidecar.Name)
+	}
+	vmID, err := contents.Subnet.Sidecar.GetVMID()
+	if err != nil {
+		return err
+	}
+	if vmID != contents.VMID {
+		return fmt.Errorf("registry descriptor VM ID %s doesn't match the subnet VM ID %s", contents.VMID, vmID)
+	}
+	if err := importExportable(contents.Subnet); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Subnet %s published by %s at %s, deployed to %v",
+		name, signerAddr, contents.PublishedAt.Format(time.RFC3339), maps.Keys(contents.Subnet.Sidecar.Networks))
+	return nil
+}
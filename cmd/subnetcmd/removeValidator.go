@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
 	"github.com/MetalBlockchain/metal-cli/pkg/keychain"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
@@ -21,7 +22,7 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var removeValidatorSupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Tahoe, networkoptions.Mainnet}
+var removeValidatorSupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Tahoe, networkoptions.Mainnet, networkoptions.Environment}
 
 // avalanche subnet removeValidator
 func newRemoveValidatorCmd() *cobra.Command {
@@ -34,8 +35,8 @@ validating your deployed Subnet.
 To remove the validator from the Subnet's allow list, provide the validator's unique NodeID. You can bypass
 these prompts by providing the values with flags.`,
 		SilenceUsage: true,
-		RunE:         removeValidator,
-		Args:         cobra.ExactArgs(1),
+		RunE:         withActiveSubnet(removeValidator),
+		Args:         cobra.MaximumNArgs(1),
 	}
 	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, removeValidatorSupportedNetworkOptions)
 	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use [fuji deploy only]")
@@ -44,6 +45,7 @@ these prompts by providing the values with flags.`,
 	cmd.Flags().StringVar(&outputTxPath, "output-tx-path", "", "file path of the removeValidator tx")
 	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on fuji)")
 	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	flags.AddSignerFlag(cmd, &signerSpec)
 	return cmd
 }
 
@@ -74,7 +76,7 @@ func removeValidator(_ *cobra.Command, args []string) error {
 		useLedger = true
 	}
 
-	if useLedger && keyName != "" {
+	if !flags.EnsureMutuallyExclusive([]bool{useLedger, keyName != "", signerSpec != ""}) {
 		return ErrMutuallyExlusiveKeyLedger
 	}
 
@@ -87,15 +89,16 @@ func removeValidator(_ *cobra.Command, args []string) error {
 	switch network.Kind {
 	case models.Local:
 		return removeFromLocal(subnetName)
-	case models.Tahoe:
-		if !useLedger && keyName == "" {
+	case models.Tahoe, models.Devnet:
+		// environments of custom networks are devnets
+		if !useLedger && keyName == "" && signerSpec == "" {
 			useLedger, keyName, err = prompts.GetFujiKeyOrLedger(app.Prompt, constants.PayTxsFeesMsg, app.GetKeyDir())
 			if err != nil {
 				return err
 			}
 		}
 	case models.Mainnet:
-		useLedger = true
+		useLedger = signerSpec == ""
 		if keyName != "" {
 			return ErrStoredKeyOnMainnet
 		}
@@ -105,7 +108,7 @@ func removeValidator(_ *cobra.Command, args []string) error {
 
 	// get keychain accesor
 	fee := network.GenesisParams().TxFee
-	kc, err := keychain.GetKeychain(app, false, useLedger, ledgerAddresses, keyName, network, fee)
+	kc, err := keychain.GetKeychain(app, models.KeyRoleValidatorManager, false, useLedger, ledgerAddresses, signerSpec, keyName, network, fee)
 	if err != nil {
 		return err
 	}
@@ -188,6 +191,9 @@ func removeValidator(_ *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if err := app.UpdateSidecarTxHistory(&sc, network, deployer.IssuedTxs()); err != nil {
+		return err
+	}
 	if !isFullySigned {
 		if err := SaveNotFullySignedTx(
 			"Remove Validator",
@@ -197,6 +203,11 @@ func removeValidator(_ *cobra.Command, args []string) error {
 			remainingSubnetAuthKeys,
 			outputTxPath,
 			false,
+			&txutils.OfflineSigningInfo{
+				SubnetID:    subnetID,
+				ControlKeys: controlKeys,
+				Threshold:   threshold,
+			},
 		); err != nil {
 			return err
 		}
@@ -211,7 +222,7 @@ func removeFromLocal(subnetName string) error {
 		return err
 	}
 
-	subnetID := sc.Networks[models.Local.String()].SubnetID
+	subnetID := sc.Networks[models.NewLocalNetwork().Name()].SubnetID
 	if subnetID == ids.Empty {
 		return errNoSubnetID
 	}
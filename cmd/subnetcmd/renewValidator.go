@@ -0,0 +1,362 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
+	"github.com/MetalBlockchain/metal-cli/pkg/apicache"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/keychain"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
+	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
+	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
+	"github.com/MetalBlockchain/metal-cli/pkg/txutils"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultRenewalMaxWait = 24 * time.Hour
+	// the renewal tx starts right after the node leaves the validator set, the
+	// lead time only leaves room for issuing it
+	renewalStartLeadTime     = constants.StakingMinimumLeadTime + 5*time.Second
+	renewalRemovalTimeout    = 5 * time.Minute
+	renewalRemovalPollPeriod = 5 * time.Second
+)
+
+var renewalMaxWait time.Duration
+
+// avalanche subnet renewValidator
+func newRenewValidatorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "renewValidator [subnetName]",
+		Short: "Renew the validation period of a subnet validator",
+		Long: `The subnet renewValidator command adds a subnet validator again for a new period
+that begins when its current one ends, so the subnet doesn't lose the validator.
+
+The command looks up the end time of the current period of --nodeID, and prompts
+for the new period and weight, defaulting to the current weight. The P-Chain
+doesn't accept a second add validator tx for a node that is still validating, so
+the command waits until the current period ends, and then issues the add
+validator tx right away. Use --max-wait to bound how long the command waits,
+eg when running it from a cron job shortly before validators expire.
+
+If the subnet needs more signatures than the given keys provide, the partially
+signed tx is saved to be signed and committed with 'metal transaction', and must
+be committed before its start time.`,
+		SilenceUsage: true,
+		RunE:         withActiveSubnet(renewValidator),
+		Args:         cobra.MaximumNArgs(1),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, addValidatorSupportedNetworkOptions)
+
+	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use [tahoe/devnet only]")
+	cmd.Flags().StringVar(&nodeIDStr, "nodeID", "", "set the NodeID of the validator to renew")
+	cmd.Flags().Uint64Var(&weight, "weight", 0, "set the staking weight of the new period (default is the current weight)")
+	cmd.Flags().BoolVar(&useDefaultDuration, "default-duration", false, "set duration so as to validate until primary validator ends its period")
+	cmd.Flags().DurationVar(&duration, "staking-period", 0, "how long the new period lasts")
+	cmd.Flags().DurationVar(&renewalMaxWait, "max-wait", defaultRenewalMaxWait, "fail instead of waiting if the current period ends later than this")
+
+	cmd.Flags().StringSliceVar(&subnetAuthKeys, "subnet-auth-keys", nil, "control keys that will be used to authenticate add validator tx")
+	cmd.Flags().StringVar(&outputTxPath, "output-tx-path", "", "file path of the add validator tx")
+	cmd.Flags().BoolVarP(&useEwoq, "ewoq", "e", false, "use ewoq key [tahoe/devnet only]")
+	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on tahoe/devnet)")
+	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	flags.AddSignerFlag(cmd, &signerSpec)
+	return cmd
+}
+
+func renewValidator(_ *cobra.Command, args []string) error {
+	subnetName := args[0]
+	if useDefaultDuration && duration != 0 {
+		return errMutuallyExclusiveDurationOptions
+	}
+	if outputTxPath != "" && utils.FileExists(outputTxPath) {
+		return fmt.Errorf("outputTxPath %q already exists", outputTxPath)
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		globalNetworkFlags,
+		true,
+		addValidatorSupportedNetworkOptions,
+		subnetName,
+	)
+	if err != nil {
+		return err
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	subnetID := sc.Networks[network.Name()].SubnetID
+	if subnetID == ids.Empty {
+		return errNoSubnetID
+	}
+	transferSubnetOwnershipTxID := sc.Networks[network.Name()].TransferSubnetOwnershipTxID
+
+	var nodeID ids.NodeID
+	if nodeIDStr != "" {
+		nodeID, err = ids.NodeIDFromString(nodeIDStr)
+	} else {
+		nodeID, err = app.Prompt.CaptureNodeID("What is the NodeID of the validator you'd like to renew?")
+	}
+	if err != nil {
+		return err
+	}
+
+	current, err := getCurrentSubnetValidator(network, subnetID, nodeID)
+	if err != nil {
+		return err
+	}
+	currentStart := time.Unix(int64(current.StartTime), 0)
+	currentEnd := time.Unix(int64(current.EndTime), 0)
+	if wait := time.Until(currentEnd); wait > renewalMaxWait {
+		return fmt.Errorf(
+			"the current period of %s ends at %s, in %s, later than --max-wait %s. Run the command again closer to the end time",
+			nodeID,
+			currentEnd.UTC().Format(constants.TimeParseLayout),
+			strings.TrimSpace(ux.FormatDuration(wait)),
+			strings.TrimSpace(ux.FormatDuration(renewalMaxWait)),
+		)
+	}
+	ux.Logger.PrintToUser("The current period of %s ends at %s", nodeID, currentEnd.UTC().Format(constants.TimeParseLayout))
+
+	selectedWeight := weight
+	if selectedWeight == 0 {
+		selectedWeight = current.Weight
+	}
+	limits, err := subnet.GetStakeWeightLimits(app, network, subnetID)
+	if err != nil {
+		return err
+	}
+	if err := limits.Check(selectedWeight); err != nil {
+		return err
+	}
+	if weight != 0 && weight != current.Weight {
+		checkValidatorWeightShare(network, subnetID, nodeID, weight)
+	}
+
+	// the start time is estimated until the node actually leaves the validator set
+	start := getRenewalStartTime(currentEnd, time.Now())
+	if duration == 0 && !useDefaultDuration {
+		if err := promptRenewalDuration(start, currentEnd.Sub(currentStart), network); err != nil {
+			return err
+		}
+	}
+	if _, err := getRenewalDuration(network, nodeID, start); err != nil {
+		return err
+	}
+
+	fee := network.GenesisParams().AddSubnetValidatorFee
+	kc, err := keychain.GetKeychainFromCmdLineFlags(
+		app,
+		constants.PayTxsFeesMsg,
+		models.KeyRoleValidatorManager,
+		network,
+		keyName,
+		useEwoq,
+		useLedger,
+		ledgerAddresses,
+		signerSpec,
+		fee,
+	)
+	if err != nil {
+		return err
+	}
+	network.HandlePublicNetworkSimulation()
+	if err := UpdateKeychainWithSubnetControlKeys(kc, network, subnetName); err != nil {
+		return err
+	}
+	controlKeys, threshold, err := txutils.GetOwners(network, subnetID, transferSubnetOwnershipTxID)
+	if err != nil {
+		return err
+	}
+	kcKeys, err := kc.PChainFormattedStrAddresses()
+	if err != nil {
+		return err
+	}
+	if subnetAuthKeys != nil {
+		if err := prompts.CheckSubnetAuthKeys(kcKeys, subnetAuthKeys, controlKeys, threshold); err != nil {
+			return err
+		}
+	} else {
+		subnetAuthKeys, err = prompts.GetSubnetAuthKeys(app.Prompt, kcKeys, controlKeys, threshold)
+		if err != nil {
+			return err
+		}
+	}
+	ux.Logger.PrintToUser("Your subnet auth keys for add validator tx creation: %s", subnetAuthKeys)
+
+	if err := waitForValidationEnd(network, subnetID, nodeID, currentEnd); err != nil {
+		return err
+	}
+	start = getRenewalStartTime(currentEnd, time.Now())
+	selectedDuration, err := getRenewalDuration(network, nodeID, start)
+	if err != nil {
+		return err
+	}
+
+	ux.Logger.PrintToUser("NodeID: %s", nodeID.String())
+	ux.Logger.PrintToUser("Network: %s", network.Name())
+	ux.Logger.PrintToUser("Start time: %s", formatTime(start))
+	ux.Logger.PrintToUser("End time: %s", formatTime(start.Add(selectedDuration)))
+	ux.Logger.PrintToUser("Weight: %d", selectedWeight)
+	ux.Logger.PrintToUser("Issuing transaction to renew the validator...")
+
+	// the deployer is created after waiting, so the wallet has up to date UTXOs
+	deployer := subnet.NewPublicDeployer(app, kc, network)
+	isFullySigned, tx, remainingSubnetAuthKeys, err := deployer.AddValidator(
+		false,
+		controlKeys,
+		subnetAuthKeys,
+		subnetID,
+		transferSubnetOwnershipTxID,
+		nodeID,
+		selectedWeight,
+		start,
+		selectedDuration,
+	)
+	if err != nil {
+		return err
+	}
+	if err := app.UpdateSidecarTxHistory(&sc, network, deployer.IssuedTxs()); err != nil {
+		return err
+	}
+	if !isFullySigned {
+		ux.Logger.PrintToUser("The tx must be committed before %s, or it will be rejected", start.UTC().Format(constants.TimeParseLayout))
+		return SaveNotFullySignedTx(
+			"Add Validator",
+			tx,
+			subnetName,
+			subnetAuthKeys,
+			remainingSubnetAuthKeys,
+			outputTxPath,
+			false,
+			&txutils.OfflineSigningInfo{
+				SubnetID:    subnetID,
+				ControlKeys: controlKeys,
+				Threshold:   threshold,
+			},
+		)
+	}
+	return nil
+}
+
+// getCurrentSubnetValidator returns the current validation of [nodeID] on [subnetID]
+func getCurrentSubnetValidator(network models.Network, subnetID ids.ID, nodeID ids.NodeID) (platformvm.ClientPermissionlessValidator, error) {
+	var (
+		validators []platformvm.ClientPermissionlessValidator
+		err        error
+	)
+	if network.Kind == models.Local {
+		validators, err = subnet.GetSubnetValidators(subnetID)
+	} else {
+		validators, err = subnet.GetPublicSubnetValidators(subnetID, network)
+	}
+	if err != nil {
+		return platformvm.ClientPermissionlessValidator{}, err
+	}
+	for _, v := range validators {
+		if v.NodeID == nodeID {
+			return v, nil
+		}
+	}
+	return platformvm.ClientPermissionlessValidator{}, fmt.Errorf(
+		"node %s is not currently a validator of subnet %s on %s. Use 'metal subnet addValidator' to add it",
+		nodeID,
+		subnetID,
+		network.Name(),
+	)
+}
+
+// getRenewalStartTime returns the start time of a renewal issued at [now], of a
+// validation that ends at [currentEnd]
+func getRenewalStartTime(currentEnd time.Time, now time.Time) time.Time {
+	if now.Before(currentEnd) {
+		now = currentEnd
+	}
+	return now.Add(renewalStartLeadTime)
+}
+
+// promptRenewalDuration sets either duration or useDefaultDuration, offering to
+// keep the duration of the current period
+func promptRenewalDuration(start time.Time, currentDuration time.Duration, network models.Network) error {
+	sameDurationOption := "Same as the current period (" + strings.TrimSpace(ux.FormatDuration(currentDuration)) + ")"
+	durationOption, err := app.Prompt.CaptureList(
+		"How long should the new validation period last?",
+		[]string{sameDurationOption, defaultDurationOption, customOption},
+	)
+	if err != nil {
+		return err
+	}
+	switch durationOption {
+	case sameDurationOption:
+		duration = currentDuration
+	case defaultDurationOption:
+		useDefaultDuration = true
+	default:
+		duration, err = PromptDuration(start, network)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getRenewalDuration returns the selected duration of the new period starting at
+// [start], checking it ends before the primary network validation of [nodeID]
+func getRenewalDuration(network models.Network, nodeID ids.NodeID, start time.Time) (time.Duration, error) {
+	maxDuration, err := getMaxValidationTime(network, nodeID, start)
+	if err != nil {
+		return 0, err
+	}
+	if useDefaultDuration {
+		return maxDuration, nil
+	}
+	if duration > maxDuration {
+		return 0, fmt.Errorf(
+			"the new period would end at %s, after node %s stops validating the primary network at %s. Renew its primary network validation first, or use a shorter --staking-period",
+			start.Add(duration).UTC().Format(constants.TimeParseLayout),
+			nodeID,
+			start.Add(maxDuration).UTC().Format(constants.TimeParseLayout),
+		)
+	}
+	return duration, nil
+}
+
+// waitForValidationEnd waits until [nodeID], whose validation ends at [end], is
+// no longer a validator of [subnetID]
+func waitForValidationEnd(network models.Network, subnetID ids.ID, nodeID ids.NodeID, end time.Time) error {
+	if wait := time.Until(end); wait > 0 {
+		ux.Logger.PrintToUser(
+			"Waiting %s for the current period to end. Keep this command running to issue the renewal at %s",
+			strings.TrimSpace(ux.FormatDuration(wait)),
+			end.UTC().Format(constants.TimeParseLayout),
+		)
+		time.Sleep(wait)
+	}
+	deadline := time.Now().Add(renewalRemovalTimeout)
+	for {
+		// the validator set is cached, and must be queried again on each poll
+		apicache.Purge()
+		isValidator, err := subnet.IsSubnetValidator(subnetID, nodeID, network)
+		if err != nil {
+			return err
+		}
+		if !isValidator {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("node %s is still a validator of subnet %s %s after its period ended", nodeID, subnetID, ux.FormatDuration(renewalRemovalTimeout))
+		}
+		time.Sleep(renewalRemovalPollPeriod)
+	}
+}
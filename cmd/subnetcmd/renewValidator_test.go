@@ -0,0 +1,19 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRenewalStartTime(t *testing.T) {
+	require := require.New(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// renewals prepared before the current period ends start right after it
+	require.Equal(now.Add(time.Hour+renewalStartLeadTime), getRenewalStartTime(now.Add(time.Hour), now))
+	// expired periods are renewed as soon as possible
+	require.Equal(now.Add(renewalStartLeadTime), getRenewalStartTime(now.Add(-time.Hour), now))
+}
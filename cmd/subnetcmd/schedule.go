@@ -0,0 +1,135 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package subnetcmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm"
+)
+
+const (
+	scheduleFormatICS  = "ics"
+	scheduleFormatCron = "cron"
+
+	icsTimeLayout = "20060102T150405Z"
+)
+
+// scheduleEvent is a point in time an operator has to act upon for a validator
+type scheduleEvent struct {
+	uid     string
+	time    time.Time
+	summary string
+}
+
+// getValidatorsScheduleEvents returns the upcoming start and end of validation
+// events for [validators], in chronological order. Events already past at [now]
+// are skipped
+func getValidatorsScheduleEvents(subnetName string, validators []platformvm.ClientPermissionlessValidator, now time.Time) []scheduleEvent {
+	events := []scheduleEvent{}
+	for _, validator := range validators {
+		startTime := time.Unix(int64(validator.StartTime), 0).UTC()
+		endTime := time.Unix(int64(validator.EndTime), 0).UTC()
+		if startTime.After(now) {
+			events = append(events, scheduleEvent{
+				uid:     fmt.Sprintf("%s-%s-start-%d", subnetName, validator.NodeID, validator.StartTime),
+				time:    startTime,
+				summary: fmt.Sprintf("%s: node %s must be online to start validating", subnetName, validator.NodeID),
+			})
+		}
+		if endTime.After(now) {
+			events = append(events, scheduleEvent{
+				uid:     fmt.Sprintf("%s-%s-end-%d", subnetName, validator.NodeID, validator.EndTime),
+				time:    endTime,
+				summary: fmt.Sprintf("%s: validation period of node %s ends", subnetName, validator.NodeID),
+			})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].time.Before(events[j].time)
+	})
+	return events
+}
+
+// icsEscape escapes text values as required by RFC 5545
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// buildScheduleICS renders [events] as an iCalendar file
+func buildScheduleICS(events []scheduleEvent, now time.Time) string {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//" + constants.CliRepoName + "//subnet validators//EN",
+		"CALSCALE:GREGORIAN",
+	}
+	for _, event := range events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+icsEscape(event.uid),
+			"DTSTAMP:"+now.UTC().Format(icsTimeLayout),
+			"DTSTART:"+event.time.Format(icsTimeLayout),
+			"DTEND:"+event.time.Format(icsTimeLayout),
+			"SUMMARY:"+icsEscape(event.summary),
+			"END:VEVENT",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+// buildScheduleCron renders [events] as a crontab. Cron has no year field, so
+// each entry is meant to be removed once it has fired
+func buildScheduleCron(events []scheduleEvent) string {
+	lines := []string{
+		"# validator schedule generated by " + constants.CliRepoName,
+		"# cron has no year field: remove each entry once it has fired",
+		"# replace the echo commands with your own notification or automation",
+		"CRON_TZ=UTC",
+	}
+	for _, event := range events {
+		lines = append(lines, fmt.Sprintf("%d %d %d %d * echo %q # %s",
+			event.time.Minute(),
+			event.time.Hour(),
+			event.time.Day(),
+			int(event.time.Month()),
+			event.summary,
+			event.time.Format(time.RFC3339),
+		))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// exportValidatorsSchedule writes the upcoming validation events of [validators]
+// into [outputPath], formatted as [format]
+func exportValidatorsSchedule(
+	subnetName string,
+	validators []platformvm.ClientPermissionlessValidator,
+	outputPath string,
+	format string,
+) error {
+	now := time.Now()
+	events := getValidatorsScheduleEvents(subnetName, validators, now)
+	var content string
+	switch format {
+	case scheduleFormatICS:
+		content = buildScheduleICS(events, now)
+	case scheduleFormatCron:
+		content = buildScheduleCron(events)
+	default:
+		return fmt.Errorf("invalid schedule format %q, expected %q or %q", format, scheduleFormatICS, scheduleFormatCron)
+	}
+	if err := os.WriteFile(outputPath, []byte(content), constants.WriteReadReadPerms); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Exported %d validator schedule events to %s", len(events), outputPath)
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package subnetcmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatorsSchedule(t *testing.T) {
+	require := require.New(t)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	nodeID := ids.GenerateTestNodeID()
+	validators := []platformvm.ClientPermissionlessValidator{
+		{
+			ClientStaker: platformvm.ClientStaker{
+				NodeID:    nodeID,
+				StartTime: uint64(now.Add(time.Hour).Unix()),
+				EndTime:   uint64(now.Add(48 * time.Hour).Unix()),
+			},
+		},
+		{
+			ClientStaker: platformvm.ClientStaker{
+				NodeID:    ids.GenerateTestNodeID(),
+				StartTime: uint64(now.Add(-time.Hour).Unix()),
+				EndTime:   uint64(now.Add(24 * time.Hour).Unix()),
+			},
+		},
+		{
+			ClientStaker: platformvm.ClientStaker{
+				NodeID:    ids.GenerateTestNodeID(),
+				StartTime: uint64(now.Add(-48 * time.Hour).Unix()),
+				EndTime:   uint64(now.Add(-time.Hour).Unix()),
+			},
+		},
+	}
+
+	events := getValidatorsScheduleEvents("mySubnet", validators, now)
+	require.Len(events, 3)
+	require.Equal(now.Add(time.Hour), events[0].time)
+	require.Contains(events[0].summary, "must be online")
+	require.Equal(now.Add(24*time.Hour), events[1].time)
+	require.Equal(now.Add(48*time.Hour), events[2].time)
+
+	ics := buildScheduleICS(events, now)
+	require.True(strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\n"))
+	require.True(strings.HasSuffix(ics, "END:VCALENDAR\r\n"))
+	require.Equal(3, strings.Count(ics, "BEGIN:VEVENT"))
+	require.Contains(ics, "DTSTART:20240301T130000Z")
+
+	cron := buildScheduleCron(events)
+	require.Contains(cron, "CRON_TZ=UTC")
+	require.Contains(cron, "0 13 1 3 * echo ")
+	require.Contains(cron, "0 12 3 3 * echo ")
+}
+
+func TestICSEscape(t *testing.T) {
+	require.Equal(t, `a\, b\; c\\d\ne`, icsEscape("a, b; c\\d\ne"))
+}
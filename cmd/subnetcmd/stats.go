@@ -6,11 +6,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
 	"time"
 
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/evm"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
 	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
@@ -19,25 +24,109 @@ import (
 	"github.com/MetalBlockchain/metalgo/vms/platformvm"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
 )
 
-var statsSupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Tahoe, networkoptions.Mainnet}
+const (
+	defaultStatsBlocks   = 50
+	defaultStatsInterval = 10 * time.Second
+	// statsBlockFetchers bounds the number of blocks fetched at the same time
+	statsBlockFetchers = 8
+)
+
+var (
+	statsSupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Tahoe, networkoptions.Mainnet, networkoptions.Environment}
+
+	statsBlocks   uint64
+	statsWatch    bool
+	statsInterval time.Duration
+)
 
 // avalanche subnet stats
 func newStatsCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:          "stats [subnetName]",
-		Short:        "Show validator statistics for the given subnet",
-		Long:         `The subnet stats command prints validator statistics for the given Subnet.`,
-		Args:         cobra.ExactArgs(1),
-		RunE:         stats,
+		Use:   "stats [subnetName]",
+		Short: "Show chain and validator statistics for the given subnet",
+		Long: `The subnet stats command prints statistics for the given Subnet.
+
+For Subnet-EVM based subnets, it shows the chain height, the average block time
+and transaction throughput over the last --blocks blocks, and the current gas
+price, as given by the blockchain RPC.
+
+For all subnets, it shows the current validators, with their stake and share
+of the total stake, as given by the P-Chain.
+
+With --json, the statistics are printed as JSON. With --watch, they are
+refreshed every --interval until interrupted.`,
+		Args:         cobra.MaximumNArgs(1),
+		RunE:         withActiveSubnet(stats),
 		SilenceUsage: true,
 	}
-	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, statsSupportedNetworkOptions)
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, statsSupportedNetworkOptions)
+	cmd.Flags().Uint64Var(&statsBlocks, "blocks", defaultStatsBlocks, "number of recent blocks to compute block time and throughput over")
+	cmd.Flags().BoolVar(&statsWatch, "watch", false, "refresh the statistics until interrupted")
+	cmd.Flags().DurationVar(&statsInterval, "interval", defaultStatsInterval, "time between refreshes on --watch")
+	flags.AddJSONOutputFlag(cmd)
 	return cmd
 }
 
+// subnetStats are the statistics printed by subnet stats
+type subnetStats struct {
+	Subnet       string           `json:"subnet"`
+	Network      string           `json:"network"`
+	SubnetID     string           `json:"subnetID"`
+	BlockchainID string           `json:"blockchainID,omitempty"`
+	Token        *tokenStats      `json:"token,omitempty"`
+	Chain        *chainStats      `json:"chain,omitempty"`
+	TotalWeight  uint64           `json:"totalWeight"`
+	Validators   []validatorStats `json:"validators"`
+	Time         time.Time        `json:"time"`
+}
+
+// tokenStats is the native token metadata recorded on the sidecar
+type tokenStats struct {
+	Name          string   `json:"name"`
+	Symbol        string   `json:"symbol"`
+	InitialSupply string   `json:"initialSupplyWei,omitempty"`
+	Mintable      bool     `json:"mintable"`
+	MintAdmins    []string `json:"mintAdmins,omitempty"`
+}
+
+type chainStats struct {
+	Height uint64 `json:"height"`
+	// SampledBlocks is the number of recent blocks the averages were computed over
+	SampledBlocks    uint64  `json:"sampledBlocks"`
+	AverageBlockTime float64 `json:"averageBlockTimeSeconds"`
+	TxCount          uint64  `json:"txCount"`
+	TxThroughput     float64 `json:"txsPerSecond"`
+	GasPrice         string  `json:"gasPriceWei"`
+}
+
+type validatorStats struct {
+	NodeID    ids.NodeID `json:"nodeID"`
+	Connected *bool      `json:"connected,omitempty"`
+	// Weight includes the weight of the delegators
+	Weight     uint64        `json:"weight"`
+	StakeShare float64       `json:"stakeShare"`
+	StartTime  time.Time     `json:"startTime"`
+	EndTime    time.Time     `json:"endTime"`
+	Period     time.Duration `json:"-"`
+	VMVersions string        `json:"vmVersions,omitempty"`
+}
+
+// blockSample is the data of a block used for the chain statistics
+type blockSample struct {
+	Time    uint64
+	TxCount int
+}
+
 func stats(_ *cobra.Command, args []string) error {
+	if statsBlocks == 0 {
+		return errors.New("--blocks must be positive")
+	}
+	if statsWatch && statsInterval <= 0 {
+		return errors.New("--interval must be positive")
+	}
 	network, err := networkoptions.GetNetworkFromCmdLineFlags(
 		app,
 		globalNetworkFlags,
@@ -60,30 +149,182 @@ func stats(_ *cobra.Command, args []string) error {
 		return err
 	}
 
-	subnetID := sc.Networks[network.Name()].SubnetID
+	deployInfo := sc.Networks[network.Name()]
+	subnetID := deployInfo.SubnetID
 	if subnetID == ids.Empty {
 		return errors.New("no subnetID found for the provided subnet name; has this subnet actually been deployed to this network?")
 	}
+	rpcURL := ""
+	if sc.VM == models.SubnetEvm && deployInfo.BlockchainID != ids.Empty {
+		rpcURL = network.BlockchainEndpoint(deployInfo.BlockchainID.String())
+	}
 
 	pClient, infoClient := findAPIEndpoint(network)
 	if pClient == nil {
 		return errors.New("failed to create a client to an API endpoint")
 	}
 
-	table := tablewriter.NewWriter(os.Stdout)
-	rows, err := buildCurrentValidatorStats(pClient, infoClient, table, subnetID)
-	if err != nil {
-		return err
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	for {
+		subnetStats := subnetStats{
+			Subnet:   subnetName,
+			Network:  network.Name(),
+			SubnetID: subnetID.String(),
+			Time:     time.Now().UTC(),
+		}
+		if deployInfo.BlockchainID != ids.Empty {
+			subnetStats.BlockchainID = deployInfo.BlockchainID.String()
+		}
+		if sc.VM == models.SubnetEvm {
+			subnetStats.Token = &tokenStats{
+				Name:          sc.TokenName,
+				Symbol:        sc.TokenSymbol,
+				InitialSupply: sc.TokenInitialSupply,
+				Mintable:      sc.TokenMintable,
+				MintAdmins:    sc.TokenMintAdmins,
+			}
+		}
+		if rpcURL != "" {
+			subnetStats.Chain, err = getChainStats(rpcURL, statsBlocks)
+			if err != nil {
+				ux.Logger.RedXToUser("failed to get chain statistics from %s: %s", rpcURL, err)
+			}
+		}
+		subnetStats.Validators, err = getValidatorStats(pClient, infoClient, subnetID)
+		if err != nil {
+			return err
+		}
+		for _, v := range subnetStats.Validators {
+			subnetStats.TotalWeight += v.Weight
+		}
+		if ux.JSONOutput() {
+			if err := ux.PrintResult(subnetStats); err != nil {
+				return err
+			}
+		} else {
+			printSubnetStats(subnetStats)
+		}
+		if !statsWatch {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(statsInterval):
+		}
 	}
-	for _, row := range rows {
+}
+
+func printSubnetStats(subnetStats subnetStats) {
+	if token := subnetStats.Token; token != nil {
+		supply := ""
+		if initialSupply, ok := new(big.Int).SetString(token.InitialSupply, 10); ok {
+			supply = fmt.Sprintf(", initial supply %s %s", formatTokenAmount(initialSupply), token.Symbol)
+		}
+		mintable := "fixed supply"
+		if token.Mintable {
+			mintable = fmt.Sprintf("mintable by %d admins", len(token.MintAdmins))
+		}
+		ux.Logger.PrintToUser("Native token: %s (%s)%s, %s", token.Name, token.Symbol, supply, mintable)
+		ux.Logger.PrintToUser("")
+	}
+	if subnetStats.Chain != nil {
+		ux.Logger.PrintToUser("Chain (last %d blocks, as of %s)", subnetStats.Chain.SampledBlocks, subnetStats.Time.Format(time.RFC3339))
+		ux.Logger.PrintToUser("==================================================")
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetRowLine(true)
+		table.Append([]string{"Height", strconv.FormatUint(subnetStats.Chain.Height, 10)})
+		table.Append([]string{"Average Block Time", fmt.Sprintf("%.2fs", subnetStats.Chain.AverageBlockTime)})
+		table.Append([]string{"Transactions", strconv.FormatUint(subnetStats.Chain.TxCount, 10)})
+		table.Append([]string{"Throughput", fmt.Sprintf("%.2f tx/s", subnetStats.Chain.TxThroughput)})
+		table.Append([]string{"Gas Price", subnetStats.Chain.GasPrice + " wei"})
+		table.Render()
+		ux.Logger.PrintToUser("")
+	}
+
+	ux.Logger.PrintToUser("Current validators (already validating the subnet)")
+	ux.Logger.PrintToUser("==================================================")
+	table := tablewriter.NewWriter(os.Stdout)
+	for _, row := range buildValidatorStatsRows(table, subnetStats.Validators) {
 		table.Append(row)
 	}
 	table.Render()
+	ux.Logger.PrintToUser("%d validators, total weight %d", len(subnetStats.Validators), subnetStats.TotalWeight)
+}
+
+// getChainStats computes the chain statistics over the last [numBlocks] blocks
+func getChainStats(rpcURL string, numBlocks uint64) (*chainStats, error) {
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), constants.APIRequestTimeout)
+	defer cancel()
+	height, err := client.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// the genesis timestamp is not related to block production
+	first := uint64(1)
+	if height > numBlocks {
+		first = height - numBlocks
+	}
+	// blocks are fetched in parallel, each one with its own timeout, so that
+	// sampling many blocks doesn't time out
+	samples := []blockSample{}
+	if height >= first {
+		samples = make([]blockSample, height-first+1)
+	}
+	eg := &errgroup.Group{}
+	eg.SetLimit(statsBlockFetchers)
+	for number := first; number <= height; number++ {
+		number := number
+		eg.Go(func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), constants.APIRequestTimeout)
+			defer cancel()
+			block, err := client.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+			if err != nil {
+				return fmt.Errorf("failed to get block %d: %w", number, err)
+			}
+			samples[number-first] = blockSample{Time: block.Time(), TxCount: len(block.Transactions())}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	chainStats := computeChainStats(samples)
+	chainStats.Height = height
+	chainStats.GasPrice = gasPrice.String()
+	return &chainStats, nil
+}
 
-	return nil
+// computeChainStats computes the block time and throughput over consecutive
+// [samples], the first one being the base the rest are measured from
+func computeChainStats(samples []blockSample) chainStats {
+	stats := chainStats{}
+	if len(samples) < 2 {
+		return stats
+	}
+	stats.SampledBlocks = uint64(len(samples) - 1)
+	for _, sample := range samples[1:] {
+		stats.TxCount += uint64(sample.TxCount)
+	}
+	elapsed := float64(samples[len(samples)-1].Time - samples[0].Time)
+	stats.AverageBlockTime = elapsed / float64(stats.SampledBlocks)
+	if elapsed > 0 {
+		stats.TxThroughput = float64(stats.TxCount) / elapsed
+	}
+	return stats
 }
 
-func buildCurrentValidatorStats(pClient platformvm.Client, infoClient info.Client, table *tablewriter.Table, subnetID ids.ID) ([][]string, error) {
+func getValidatorStats(pClient platformvm.Client, infoClient info.Client, subnetID ids.ID) ([]validatorStats, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -92,21 +333,9 @@ func buildCurrentValidatorStats(pClient platformvm.Client, infoClient info.Clien
 		return nil, fmt.Errorf("failed to query the API endpoint for the current validators: %w", err)
 	}
 
-	ux.Logger.PrintToUser("Current validators (already validating the subnet)")
-	ux.Logger.PrintToUser("==================================================")
-
-	header := []string{"nodeID", "connected", "weight", "remaining", "vmversion"}
-	table.SetHeader(header)
-	table.SetAutoMergeCellsByColumnIndex([]int{0})
-	table.SetAutoMergeCells(true)
-	table.SetRowLine(true)
-	rows := [][]string{}
-
 	var (
-		startTime, endTime           time.Time
-		localNodeID                  ids.NodeID
-		remaining, connected, weight string
-		localVersionStr, versionStr  string
+		localNodeID     ids.NodeID
+		localVersionStr string
 	)
 
 	// try querying the local node for its node version
@@ -119,42 +348,66 @@ func buildCurrentValidatorStats(pClient platformvm.Client, infoClient info.Clien
 		}
 	}
 
+	validators := []validatorStats{}
+	var totalWeight uint64
 	for _, v := range currValidators {
-		startTime = time.Unix(int64(v.StartTime), 0)
-		endTime = time.Unix(int64(v.EndTime), 0)
-		remaining = ux.FormatDuration(endTime.Sub(startTime))
-
-		// some members of the returned object are pointers
-		// so we need to check the pointer is actually valid
-		if v.Connected != nil {
-			connected = strconv.FormatBool(*v.Connected)
-		} else {
-			connected = constants.NotAvailableLabel
+		startTime := time.Unix(int64(v.StartTime), 0)
+		endTime := time.Unix(int64(v.EndTime), 0)
+		weight := v.Weight
+		for _, d := range v.Delegators {
+			weight += d.Weight
 		}
-
-		uint64Weight := v.Weight
-		delegators := v.Delegators
-		for _, d := range delegators {
-			uint64Weight += d.Weight
+		totalWeight += weight
+		validator := validatorStats{
+			NodeID:    v.NodeID,
+			Connected: v.Connected,
+			Weight:    weight,
+			StartTime: startTime.UTC(),
+			EndTime:   endTime.UTC(),
+			Period:    endTime.Sub(startTime),
 		}
-		weight = strconv.FormatUint(uint64Weight, 10)
-
 		// if retrieval of localNodeID failed, it will be empty,
 		// and this comparison fails
 		if v.NodeID == localNodeID {
-			versionStr = localVersionStr
+			validator.VMVersions = localVersionStr
+		}
+		validators = append(validators, validator)
+	}
+	for i := range validators {
+		if totalWeight > 0 {
+			validators[i].StakeShare = float64(validators[i].Weight) / float64(totalWeight)
+		}
+	}
+	return validators, nil
+}
+
+func buildValidatorStatsRows(table *tablewriter.Table, validators []validatorStats) [][]string {
+	header := []string{"nodeID", "connected", "weight", "remaining", "vmversion", "stake share"}
+	table.SetHeader(header)
+	table.SetAutoMergeCellsByColumnIndex([]int{0})
+	table.SetAutoMergeCells(true)
+	table.SetRowLine(true)
+	rows := [][]string{}
+
+	for _, v := range validators {
+		// some members of the returned object are pointers
+		// so we need to check the pointer is actually valid
+		connected := constants.NotAvailableLabel
+		if v.Connected != nil {
+			connected = strconv.FormatBool(*v.Connected)
 		}
 		// query peers for IP address of this NodeID...
 		rows = append(rows, []string{
 			v.NodeID.String(),
 			connected,
-			weight,
-			remaining,
-			versionStr,
+			strconv.FormatUint(v.Weight, 10),
+			ux.FormatDuration(v.Period),
+			v.VMVersions,
+			fmt.Sprintf("%.2f%%", v.StakeShare*100),
 		})
 	}
 
-	return rows, nil
+	return rows
 }
 
 // findAPIEndpoint tries first to create a client to a local node
@@ -164,10 +417,10 @@ func findAPIEndpoint(network models.Network) (platformvm.Client, info.Client) {
 
 	// first try local node
 	ctx := context.Background()
-	c := platformvm.NewClient(constants.LocalAPIEndpoint)
+	c := platformvm.NewClient(models.LocalAPIEndpoint())
 	_, err := c.GetHeight(ctx)
 	if err == nil {
-		i = info.NewClient(constants.LocalAPIEndpoint)
+		i = info.NewClient(models.LocalAPIEndpoint())
 		// try calling it to make sure it actually worked
 		_, _, err := i.GetNodeID(ctx)
 		if err == nil {
@@ -181,7 +434,7 @@ func findAPIEndpoint(network models.Network) (platformvm.Client, info.Client) {
 	_, err = c.GetHeight(ctx)
 	if err == nil {
 		// also try to get a local client
-		i = info.NewClient(constants.LocalAPIEndpoint)
+		i = info.NewClient(models.LocalAPIEndpoint())
 	}
 	return c, i
 }
@@ -60,14 +60,37 @@ func TestStats(t *testing.T) {
 
 	expectedVerStr := subnetID.String() + ": 0.1.23\n"
 
-	rows, err := buildCurrentValidatorStats(pClient, iClient, table, subnetID)
+	validators, err := getValidatorStats(pClient, iClient, subnetID)
+	require.NoError(err)
+	rows := buildValidatorStatsRows(table, validators)
 	table.Append(rows[0])
 
-	require.NoError(err)
 	require.Equal(1, table.NumLines())
 	require.Equal(localNodeID.String(), rows[0][0])
 	require.Equal("true", rows[0][1])
 	require.Equal("42", rows[0][2])
 	require.Equal(remaining, rows[0][3])
 	require.Equal(expectedVerStr, rows[0][4])
+	require.Equal("100.00%", rows[0][5])
+}
+
+func TestComputeChainStats(t *testing.T) {
+	require := require.New(t)
+	require.Equal(chainStats{}, computeChainStats([]blockSample{{Time: 100, TxCount: 3}}))
+
+	stats := computeChainStats([]blockSample{
+		{Time: 100, TxCount: 7},
+		{Time: 102, TxCount: 4},
+		{Time: 104, TxCount: 0},
+		{Time: 108, TxCount: 8},
+	})
+	require.Equal(uint64(3), stats.SampledBlocks)
+	require.Equal(uint64(12), stats.TxCount)
+	require.InDelta(8.0/3, stats.AverageBlockTime, 0.0001)
+	require.InDelta(1.5, stats.TxThroughput, 0.0001)
+
+	// blocks produced within the same second
+	stats = computeChainStats([]blockSample{{Time: 100}, {Time: 100, TxCount: 2}})
+	require.Zero(stats.AverageBlockTime)
+	require.Zero(stats.TxThroughput)
 }
@@ -3,10 +3,12 @@
 package subnetcmd
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/MetalBlockchain/metal-cli/cmd/subnetcmd/upgradecmd"
 	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
 	"github.com/spf13/cobra"
 )
 
@@ -36,10 +38,14 @@ manage your Subnet configurations and live deployments.`,
 	cmd.AddCommand(newCreateCmd())
 	// subnet delete
 	cmd.AddCommand(newDeleteCmd())
+	// subnet undeploy
+	cmd.AddCommand(newUndeployCmd())
 	// subnet deploy
 	cmd.AddCommand(newDeployCmd())
 	// subnet describe
 	cmd.AddCommand(newDescribeCmd())
+	// subnet diff
+	cmd.AddCommand(newDiffCmd())
 	// subnet list
 	cmd.AddCommand(newListCmd())
 	// subnet join
@@ -62,13 +68,49 @@ manage your Subnet configurations and live deployments.`,
 	cmd.AddCommand(vmidCmd())
 	// subnet removeValidator
 	cmd.AddCommand(newRemoveValidatorCmd())
+	// subnet renewValidator
+	cmd.AddCommand(newRenewValidatorCmd())
+	// subnet rebalance
+	cmd.AddCommand(newRebalanceCmd())
 	// subnet elastic
 	cmd.AddCommand(newElasticCmd())
 	// subnet validators
 	cmd.AddCommand(newValidatorsCmd())
+	// subnet expiry
+	cmd.AddCommand(newExpiryCmd())
+	// subnet addPermissionlessValidator
+	cmd.AddCommand(newAddPermissionlessValidatorCmd())
 	// subnet addPermissionlessDelegator
 	cmd.AddCommand(newAddPermissionlessDelegatorCmd())
 	// subnet changeOwner
 	cmd.AddCommand(newChangeOwnerCmd())
+	// subnet watch
+	cmd.AddCommand(newWatchCmd())
+	// subnet wait
+	cmd.AddCommand(newWaitCmd())
+	// subnet config
+	cmd.AddCommand(newConfigCmd())
+	// subnet cost
+	cmd.AddCommand(newCostCmd())
+	// subnet apply
+	cmd.AddCommand(newApplyCmd())
+	// subnet invite
+	cmd.AddCommand(newInviteCmd())
 	return cmd
 }
+
+// withActiveSubnet makes the subnetName argument of [runE] optional, defaulting
+// to the active subnet set with 'metal use subnet'
+func withActiveSubnet(runE func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			subnetName := app.GetActiveSubnet()
+			if subnetName == "" {
+				return errors.New("subnetName not provided and no active subnet set. Provide it or set one with 'metal use subnet [subnetName]'")
+			}
+			ux.Logger.PrintToUser("Using active subnet %s", subnetName)
+			args = []string{subnetName}
+		}
+		return runE(cmd, args)
+	}
+}
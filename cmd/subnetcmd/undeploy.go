@@ -0,0 +1,81 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
+	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/spf13/cobra"
+)
+
+var undeploySupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Local}
+
+// avalanche subnet undeploy
+func newUndeployCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "undeploy [subnetName]",
+		Short: "Remove a subnet from the local network",
+		Long: `The subnet undeploy command removes a single Subnet from the running local
+network, keeping the other locally deployed Subnets and the rest of the network
+state, as opposed to network clean.
+
+The network is stopped into a snapshot, where the nodes stop tracking the Subnet
+and the blockchain data is removed from their databases, and it is started again
+from it. The VM plugin is removed as well. The blockchain stays registered on the
+local P-Chain, but it is no longer listed, and the Subnet can be deployed again
+with subnet deploy.
+
+Only local deploys can be undeployed.`,
+		RunE:         withActiveSubnet(undeploySubnet),
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, undeploySupportedNetworkOptions)
+	return cmd
+}
+
+func undeploySubnet(_ *cobra.Command, args []string) error {
+	subnetName := args[0]
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		globalNetworkFlags,
+		false,
+		undeploySupportedNetworkOptions,
+		subnetName,
+	)
+	if err != nil {
+		return err
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	networkData, ok := sc.Networks[network.Name()]
+	if !ok || networkData.BlockchainID == ids.Empty {
+		return fmt.Errorf("subnet %s is not deployed on the %s", subnetName, network.Name())
+	}
+
+	deployer := subnet.NewLocalDeployer(app, "", "", "")
+	if err := deployer.UndeployFromLocalNetwork(subnetName, networkData.SubnetID, networkData.BlockchainID); err != nil {
+		if errors.Is(err, subnet.ErrLocalNetworkNotRunning) {
+			return fmt.Errorf("%w. Start it with metal network start", err)
+		}
+		return err
+	}
+
+	if _, err := app.EditSidecar(subnetName, func(sc *models.Sidecar) error {
+		delete(sc.Networks, network.Name())
+		delete(sc.ElasticSubnet, network.Name())
+		return nil
+	}); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Subnet %s (blockchain %s) removed from the %s", subnetName, networkData.BlockchainID, network.Name())
+	return nil
+}
@@ -11,11 +11,13 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/MetalBlockchain/metal-cli/pkg/binutils"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/snapshot"
 	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
 	"github.com/MetalBlockchain/metal-cli/pkg/utils"
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
@@ -61,6 +63,10 @@ func newUpgradeApplyCmd() *cobra.Command {
 		Short: "Apply upgrade bytes onto subnet nodes",
 		Long: `Apply generated upgrade bytes to running Subnet nodes to trigger a network upgrade.
 
+For the local network (--local), the upgrade file is installed into the chain config dir of
+every node, and the nodes are restarted from a snapshot of the current network state, so that
+upgrade activation can be rehearsed before touching public networks.
+
 For public networks (Tahoe Testnet or Mainnet), to complete this process,
 you must have access to the machine running your validator.
 If the CLI is running on the same machine as your validator, it can manipulate your node's
@@ -106,7 +112,7 @@ func applyCmd(_ *cobra.Command, args []string) error {
 	switch networkToUpgrade {
 	// update a locally running network
 	case localDeployment:
-		return applyLocalNetworkUpgrade(subnetName, models.Local.String(), &sc)
+		return applyLocalNetworkUpgrade(subnetName, models.NewLocalNetwork().Name(), &sc)
 	case fujiDeployment:
 		return applyPublicNetworkUpgrade(subnetName, models.Tahoe.String(), &sc)
 	case mainnetDeployment:
@@ -183,6 +189,10 @@ func applyLocalNetworkUpgrade(subnetName, networkKey string, sc *models.Sidecar)
 
 	// save a temporary snapshot
 	snapName := subnetName + tmpSnapshotInfix + time.Now().Format(timestampFormat)
+	snapshotSettings := snapshot.GetSettings(app)
+	if snapshotSettings.Auto {
+		snapName = snapshot.AutoName(snapshotSettings, "upgrade", subnetName)
+	}
 	app.Log.Debug("saving temporary snapshot for upgrade bytes", zap.String("snapshot-name", snapName))
 	_, err = cli.SaveSnapshot(ctx, snapName)
 	if err != nil {
@@ -206,9 +216,20 @@ func applyLocalNetworkUpgrade(subnetName, networkKey string, sc *models.Sidecar)
 		return fmt.Errorf("failed waiting for network to become healthy: %w", err)
 	}
 
+	if snapshotSettings.Auto {
+		ux.Logger.PrintToUser("Pre-upgrade network state saved as snapshot %s", snapName)
+		if _, err := snapshot.ApplyRetention(app); err != nil {
+			app.Log.Warn("failed pruning old snapshots", zap.Error(err))
+		}
+	} else if _, err := cli.RemoveSnapshot(ctx, snapName); err != nil {
+		// the network runs from its own copy of the snapshot, so the temporary one is no longer needed
+		app.Log.Warn("failed removing temporary upgrade snapshot", zap.String("snapshot-name", snapName), zap.Error(err))
+	}
+
 	fmt.Println()
 	if subnet.HasEndpoints(clusterInfo) {
 		ux.Logger.PrintToUser("Network restarted and ready to use. Upgrade bytes have been applied to running nodes at these endpoints.")
+		ux.Logger.PrintToUser("Upgrade file installed for blockchain %s on nodes: %s", blockchainID, strings.Join(clusterInfo.NodeNames, ", "))
 
 		nextUpgrade, err := getEarliestUpcomingTimestamp(precmpUpgrades)
 		// this should not happen anymore at this point...
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 	"time"
 
 	"github.com/MetalBlockchain/coreth/ethclient"
@@ -27,6 +28,7 @@ import (
 	"github.com/MetalBlockchain/subnet-evm/precompile/contracts/nativeminter"
 	"github.com/MetalBlockchain/subnet-evm/precompile/contracts/rewardmanager"
 	"github.com/MetalBlockchain/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/MetalBlockchain/subnet-evm/precompile/precompileconfig"
 	subnetevmutils "github.com/MetalBlockchain/subnet-evm/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
@@ -44,6 +46,9 @@ const (
 	enabledLabel = "enabled"
 	managerLabel = "manager"
 	adminLabel   = "admin"
+
+	activateAction   = "activated"
+	deactivateAction = "deactivated"
 )
 
 var subnetName string
@@ -54,7 +59,11 @@ func newUpgradeGenerateCmd() *cobra.Command {
 		Use:   "generate [subnetName]",
 		Short: "Generate the configuration file to upgrade subnet nodes",
 		Long: `The subnet upgrade generate command builds a new upgrade.json file to customize your Subnet. It
-guides the user through the process using an interactive wizard.`,
+guides the user through the process using an interactive wizard.
+
+Each upgrade activates or deactivates a precompile at a future timestamp. If an upgrade file
+already exists, the new upgrades can be appended to it. The resulting file is checked against
+the Subnet-EVM ordering rules before being stored with the subnet configuration.`,
 		RunE: upgradeGenerateCmd,
 		Args: cobra.ExactArgs(1),
 	}
@@ -67,6 +76,13 @@ func upgradeGenerateCmd(_ *cobra.Command, args []string) error {
 		ux.Logger.PrintToUser("The provided subnet name %q does not exist", subnetName)
 		return nil
 	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	if sc.VM != models.SubnetEvm {
+		return fmt.Errorf("upgrade files can only be generated for %s subnets", models.SubnetEvm)
+	}
 	// print some warning/info message
 	ux.Logger.PrintToUser(logging.Bold.Wrap(logging.Yellow.Wrap(
 		"Performing a network upgrade requires coordinating the upgrade network-wide.")))
@@ -106,6 +122,11 @@ func upgradeGenerateCmd(_ *cobra.Command, args []string) error {
 			"However, we suggest to only configure one per upgrade."))
 	fmt.Println()
 
+	existingUpgrades, err := promptExistingUpgrades(subnetName)
+	if err != nil {
+		return err
+	}
+
 	// use the correct data types from subnet-evm right away
 	precompiles := params.UpgradeConfig{
 		PrecompileUpgrades: make([]params.PrecompileUpgrade, 0),
@@ -117,10 +138,23 @@ func upgradeGenerateCmd(_ *cobra.Command, args []string) error {
 			return err
 		}
 
-		ux.Logger.PrintToUser(fmt.Sprintf("Set parameters for the %q precompile", precomp))
-		if err := promptParams(precomp, &precompiles.PrecompileUpgrades); err != nil {
+		action, err := app.Prompt.CaptureList(
+			fmt.Sprintf("Should the %q precompile be activated or deactivated?", precomp),
+			[]string{activateAction, deactivateAction},
+		)
+		if err != nil {
 			return err
 		}
+		if action == deactivateAction {
+			if err := promptDisableParams(precomp, &precompiles.PrecompileUpgrades); err != nil {
+				return err
+			}
+		} else {
+			ux.Logger.PrintToUser(fmt.Sprintf("Set parameters for the %q precompile", precomp))
+			if err := promptParams(precomp, &precompiles.PrecompileUpgrades); err != nil {
+				return err
+			}
+		}
 
 		if len(allPreComps) > 1 {
 			yes, err := app.Prompt.CaptureNoYes("Should we configure another precompile?")
@@ -140,15 +174,108 @@ func upgradeGenerateCmd(_ *cobra.Command, args []string) error {
 		}
 	}
 
+	// upgrades must be listed in activation order
+	sort.SliceStable(precompiles.PrecompileUpgrades, func(i, j int) bool {
+		return *precompiles.PrecompileUpgrades[i].Timestamp() < *precompiles.PrecompileUpgrades[j].Timestamp()
+	})
+	precompiles.PrecompileUpgrades = append(existingUpgrades, precompiles.PrecompileUpgrades...)
+
+	genesis, err := app.LoadEvmGenesis(subnetName)
+	if err != nil {
+		return err
+	}
+	var genesisPrecompiles params.Precompiles
+	if genesis.Config != nil {
+		genesisPrecompiles = genesis.Config.GenesisPrecompiles
+	}
+	if err := validatePrecompileUpgradesOrder(genesisPrecompiles, precompiles.PrecompileUpgrades); err != nil {
+		return fmt.Errorf("invalid upgrade file: %w", err)
+	}
+
 	jsonBytes, err := json.Marshal(&precompiles)
 	if err != nil {
 		return err
 	}
 
-	return app.WriteUpgradeFile(subnetName, jsonBytes)
+	if err := app.WriteUpgradeFile(subnetName, jsonBytes); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Upgrade file written to %s", app.GetUpgradeBytesFilePath(subnetName))
+	return nil
 }
 
-func queryActivationTimestamp() (time.Time, error) {
+// promptExistingUpgrades asks whether to extend the upgrade file of [subnetName],
+// if any, and returns the upgrades to keep
+func promptExistingUpgrades(subnetName string) ([]params.PrecompileUpgrade, error) {
+	if !app.NetworkUpgradeExists(subnetName) {
+		return nil, nil
+	}
+	const (
+		appendOption  = "Append the new upgrades to it"
+		replaceOption = "Replace it"
+	)
+	choice, err := app.Prompt.CaptureList(
+		"An upgrade file already exists for this subnet. What would you like to do?",
+		[]string{appendOption, replaceOption},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if choice == replaceOption {
+		return nil, nil
+	}
+	fileBytes, err := app.ReadUpgradeFile(subnetName)
+	if err != nil {
+		return nil, err
+	}
+	return getAllUpgrades(fileBytes)
+}
+
+// validatePrecompileUpgradesOrder checks the ordering rules Subnet-EVM enforces on
+// upgrade files: timestamps must not decrease, the upgrades of a given precompile must
+// have strictly increasing timestamps, and each one must toggle the precompile
+// between enabled and disabled, starting from its genesis state
+func validatePrecompileUpgradesOrder(genesisPrecompiles params.Precompiles, upgrades []params.PrecompileUpgrade) error {
+	type precompileState struct {
+		timestamp uint64
+		disabled  bool
+	}
+	states := map[string]precompileState{}
+	for key, config := range genesisPrecompiles {
+		if config.Timestamp() != nil {
+			states[key] = precompileState{timestamp: *config.Timestamp()}
+		}
+	}
+	var previousTimestamp uint64
+	for i, upgrade := range upgrades {
+		key := upgrade.Key()
+		timestamp, err := validateTimestamp(upgrade.Timestamp())
+		if err != nil {
+			return fmt.Errorf("upgrade %d (%s): %w", i, key, err)
+		}
+		if uint64(timestamp) < previousTimestamp {
+			return fmt.Errorf("upgrade %d (%s): timestamp %d is before the previous upgrade timestamp %d", i, key, timestamp, previousTimestamp)
+		}
+		state, ok := states[key]
+		if !ok {
+			state.disabled = true
+		}
+		if state.disabled == upgrade.IsDisabled() {
+			if upgrade.IsDisabled() {
+				return fmt.Errorf("upgrade %d (%s): precompile is not active, it can't be deactivated", i, key)
+			}
+			return fmt.Errorf("upgrade %d (%s): precompile is already active, it must be deactivated first", i, key)
+		}
+		if ok && uint64(timestamp) <= state.timestamp {
+			return fmt.Errorf("upgrade %d (%s): timestamp %d must be after the previous upgrade of the same precompile at %d", i, key, timestamp, state.timestamp)
+		}
+		states[key] = precompileState{timestamp: uint64(timestamp), disabled: upgrade.IsDisabled()}
+		previousTimestamp = uint64(timestamp)
+	}
+	return nil
+}
+
+func queryActivationTimestamp(action string) (time.Time, error) {
 	const (
 		in5min   = "In 5 minutes"
 		in1day   = "In 1 day"
@@ -157,7 +284,7 @@ func queryActivationTimestamp() (time.Time, error) {
 		custom   = "Custom"
 	)
 	options := []string{in5min, in1day, in1week, in2weeks, custom}
-	choice, err := app.Prompt.CaptureList("When should the precompile be activated?", options)
+	choice, err := app.Prompt.CaptureList(fmt.Sprintf("When should the precompile be %s?", action), options)
 	if err != nil {
 		return time.Time{}, err
 	}
@@ -186,8 +313,34 @@ func queryActivationTimestamp() (time.Time, error) {
 	return date, nil
 }
 
+// promptDisableParams adds an upgrade deactivating [precomp] at a prompted timestamp
+func promptDisableParams(precomp string, precompiles *[]params.PrecompileUpgrade) error {
+	date, err := queryActivationTimestamp(deactivateAction)
+	if err != nil {
+		return err
+	}
+	timestamp := subnetevmutils.NewUint64(uint64(date.Unix()))
+	var config precompileconfig.Config
+	switch precomp {
+	case vm.ContractAllowList:
+		config = deployerallowlist.NewDisableConfig(timestamp)
+	case vm.TxAllowList:
+		config = txallowlist.NewDisableConfig(timestamp)
+	case vm.NativeMint:
+		config = nativeminter.NewDisableConfig(timestamp)
+	case vm.FeeManager:
+		config = feemanager.NewDisableConfig(timestamp)
+	case vm.RewardManager:
+		config = rewardmanager.NewDisableConfig(timestamp)
+	default:
+		return fmt.Errorf("unexpected precompile identifier: %q", precomp)
+	}
+	*precompiles = append(*precompiles, params.PrecompileUpgrade{Config: config})
+	return nil
+}
+
 func promptParams(precomp string, precompiles *[]params.PrecompileUpgrade) error {
-	date, err := queryActivationTimestamp()
+	date, err := queryActivationTimestamp(activateAction)
 	if err != nil {
 		return err
 	}
@@ -377,7 +530,7 @@ func getCClient(apiEndpoint string, blockchainID string) (ethclient.Client, erro
 }
 
 func ensureHaveBalanceLocalNetwork(which string, addresses []common.Address, blockchainID string) error {
-	cClient, err := getCClient(constants.LocalAPIEndpoint, blockchainID)
+	cClient, err := getCClient(models.LocalAPIEndpoint(), blockchainID)
 	if err != nil {
 		return err
 	}
@@ -7,6 +7,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/MetalBlockchain/subnet-evm/params"
+	"github.com/MetalBlockchain/subnet-evm/precompile/contracts/feemanager"
+	"github.com/MetalBlockchain/subnet-evm/precompile/contracts/txallowlist"
+	subnetevmutils "github.com/MetalBlockchain/subnet-evm/utils"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/require"
 )
 
@@ -261,3 +266,73 @@ func TestLockFile(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatePrecompileUpgradesOrder(t *testing.T) {
+	admin := []common.Address{common.HexToAddress("0xb794F5eA0ba39494cE839613fffBA74279579268")}
+	enable := func(ts uint64) params.PrecompileUpgrade {
+		return params.PrecompileUpgrade{Config: txallowlist.NewConfig(subnetevmutils.NewUint64(ts), admin, nil, nil)}
+	}
+	disable := func(ts uint64) params.PrecompileUpgrade {
+		return params.PrecompileUpgrade{Config: txallowlist.NewDisableConfig(subnetevmutils.NewUint64(ts))}
+	}
+	enableFeeManager := func(ts uint64) params.PrecompileUpgrade {
+		return params.PrecompileUpgrade{Config: feemanager.NewConfig(subnetevmutils.NewUint64(ts), admin, nil, nil, nil)}
+	}
+
+	tests := []struct {
+		name        string
+		genesis     params.Precompiles
+		upgrades    []params.PrecompileUpgrade
+		expectedErr bool
+	}{
+		{
+			name:     "enable then disable",
+			upgrades: []params.PrecompileUpgrade{enable(100), disable(200)},
+		},
+		{
+			name:     "different precompiles at same time",
+			upgrades: []params.PrecompileUpgrade{enable(100), enableFeeManager(100)},
+		},
+		{
+			name:        "decreasing timestamps",
+			upgrades:    []params.PrecompileUpgrade{enableFeeManager(200), enable(100)},
+			expectedErr: true,
+		},
+		{
+			name:        "disable never enabled",
+			upgrades:    []params.PrecompileUpgrade{disable(100)},
+			expectedErr: true,
+		},
+		{
+			name:        "enable twice",
+			upgrades:    []params.PrecompileUpgrade{enable(100), enable(200)},
+			expectedErr: true,
+		},
+		{
+			name:        "same precompile at same time",
+			upgrades:    []params.PrecompileUpgrade{enable(100), disable(100)},
+			expectedErr: true,
+		},
+		{
+			name:     "disable enabled at genesis",
+			genesis:  params.Precompiles{txallowlist.ConfigKey: txallowlist.NewConfig(subnetevmutils.NewUint64(0), admin, nil, nil)},
+			upgrades: []params.PrecompileUpgrade{disable(100)},
+		},
+		{
+			name:        "enable enabled at genesis",
+			genesis:     params.Precompiles{txallowlist.ConfigKey: txallowlist.NewConfig(subnetevmutils.NewUint64(0), admin, nil, nil)},
+			upgrades:    []params.PrecompileUpgrade{enable(100)},
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePrecompileUpgradesOrder(tt.genesis, tt.upgrades)
+			if tt.expectedErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
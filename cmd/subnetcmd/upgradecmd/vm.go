@@ -6,16 +6,19 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
 	"github.com/MetalBlockchain/metal-cli/pkg/binutils"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
 	"github.com/MetalBlockchain/metal-cli/pkg/plugins"
+	"github.com/MetalBlockchain/metal-cli/pkg/snapshot"
 	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
 	"github.com/MetalBlockchain/metal-cli/pkg/utils"
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
 	"github.com/MetalBlockchain/metal-cli/pkg/vm"
 	"github.com/MetalBlockchain/metal-network-runner/server"
 	anrutils "github.com/MetalBlockchain/metal-network-runner/utils"
+	"github.com/MetalBlockchain/metalgo/ids"
 	"github.com/spf13/cobra"
 )
 
@@ -37,6 +40,12 @@ var (
 	useLatest     bool
 	targetVersion string
 	binaryPathArg string
+
+	binaryVersionArg string
+	compatScriptArg  string
+	snapshotNameArg  string
+
+	localNetworkNameArg string
 )
 
 // avalanche subnet update vm
@@ -47,6 +56,13 @@ func newUpgradeVMCmd() *cobra.Command {
 		Long: `The subnet upgrade vm command enables the user to upgrade their Subnet's VM binary. The command
 can upgrade both local Subnets and publicly deployed Subnets on Fuji and Mainnet.
 
+For Custom VMs, the new binary given with --binary is checksummed and recorded into the subnet
+configuration, together with a new VM version: the one given with --binary-version, or else the
+current version with its patch number increased. Before the binary is swapped, the script given with
+--compat-script can check that the new binary is compatible with the existing chain data. It runs
+against a copy of the db of the stopped local network, receiving the new binary and the db dir as
+arguments. Use --local-network-name for Subnets deployed to a named local network.
+
 The command walks the user through an interactive wizard. The user can skip the wizard by providing
 command line flags.`,
 		RunE:         upgradeVM,
@@ -66,6 +82,10 @@ command line flags.`,
 	cmd.Flags().BoolVar(&useLatest, "latest", false, "upgrade to latest version")
 	cmd.Flags().StringVar(&targetVersion, "version", "", "Upgrade to custom version")
 	cmd.Flags().StringVar(&binaryPathArg, "binary", "", "Upgrade to custom binary")
+	cmd.Flags().StringVar(&binaryVersionArg, "binary-version", "", "VM version to record for the custom binary (default: increase the patch number of the current one)")
+	cmd.Flags().StringVar(&compatScriptArg, "compat-script", "", "script checking that the custom binary is compatible with the local chain data")
+	cmd.Flags().StringVar(&snapshotNameArg, "snapshot-name", constants.DefaultSnapshotName, "local network snapshot with the chain data to check compatibility against")
+	flags.AddLocalNetworkNameFlag(cmd, &localNetworkNameArg, "upgrade the deployment on this named local network instead of the default one")
 
 	return cmd
 }
@@ -103,6 +123,11 @@ func upgradeVM(_ *cobra.Command, args []string) error {
 		return errors.New("subnet does not exist")
 	}
 
+	// the local deployment, snapshots and backend are the ones of the selected local network
+	if err := binutils.SelectLocalNetwork(app, localNetworkNameArg, false); err != nil {
+		return err
+	}
+
 	sc, err := app.LoadSidecar(subnetName)
 	if err != nil {
 		return fmt.Errorf("unable to load sidecar: %w", err)
@@ -271,9 +296,9 @@ func updateVMByNetwork(sc models.Sidecar, targetVersion string, networkToUpgrade
 	case localDeployment:
 		return updateExistingLocalVM(sc, targetVersion)
 	case fujiDeployment:
-		return chooseManualOrAutomatic(sc, targetVersion)
+		return chooseManualOrAutomatic(sc, targetVersion, models.NewTahoeNetwork())
 	case mainnetDeployment:
-		return chooseManualOrAutomatic(sc, targetVersion)
+		return chooseManualOrAutomatic(sc, targetVersion, models.NewMainnetNetwork())
 	default:
 		return errors.New("unknown deployment")
 	}
@@ -288,22 +313,74 @@ func updateToCustomBin(sc models.Sidecar, networkToUpgrade, binaryPath string, u
 		}
 	}
 
+	targetVersion := ""
+	if sc.VM == models.CustomVM || binaryVersionArg != "" {
+		targetVersion, err = vm.NextCustomVMVersion(sc.VMVersion, binaryVersionArg)
+		if err != nil {
+			return err
+		}
+	}
+
+	if compatScriptArg != "" {
+		if err := checkCustomBinCompatibility(sc, binaryPath); err != nil {
+			return err
+		}
+	}
+
 	if err := app.CopyVMBinary(binaryPath, sc.Name); err != nil {
 		return err
 	}
 
 	sc.VM = models.CustomVM
+	sc.VMVersion = targetVersion
+	if err := vm.SetVMBinary(&sc, app.GetCustomVMPath(sc.Name), binaryPath); err != nil {
+		return err
+	}
+	if targetVersion != "" {
+		ux.Logger.PrintToUser("Custom VM binary version %s has sha256 %s", targetVersion, sc.VMBinary.SHA256)
+	} else {
+		ux.Logger.PrintToUser("Custom VM binary has sha256 %s", sc.VMBinary.SHA256)
+	}
 	if updateVMBinaryProtocolVersion {
 		sc.RPCVersion, err = vm.GetVMBinaryProtocolVersion(binaryPath)
 		if err != nil {
 			return fmt.Errorf("unable to get RPC version: %w", err)
 		}
 	}
-	targetVersion := ""
 
 	return updateVMByNetwork(sc, targetVersion, networkToUpgrade)
 }
 
+// checkCustomBinCompatibility runs the user provided compatibility script for
+// [binaryPath] against the chain data of the local network snapshot
+func checkCustomBinCompatibility(sc models.Sidecar, binaryPath string) error {
+	vmid, err := sc.GetVMID()
+	if err != nil {
+		return err
+	}
+	oldVMBinary := ""
+	if sc.VM == models.CustomVM && utils.FileExists(app.GetCustomVMPath(sc.Name)) {
+		oldVMBinary = app.GetCustomVMPath(sc.Name)
+	}
+	blockchainID := ""
+	if networkData, ok := sc.Networks[models.NewLocalNetwork().Name()]; ok && networkData.BlockchainID != ids.Empty {
+		blockchainID = networkData.BlockchainID.String()
+	}
+	check := vm.CompatibilityCheck{
+		ScriptPath:   compatScriptArg,
+		OldVMBinary:  oldVMBinary,
+		NewVMBinary:  binaryPath,
+		SnapshotDir:  snapshot.Dir(app.GetSnapshotsDir(), snapshotNameArg),
+		VMID:         vmid,
+		BlockchainID: blockchainID,
+	}
+	if err := check.Run(); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Compatibility check passed")
+	return nil
+}
+
 func updateFutureVM(sc models.Sidecar, targetVersion string) error {
 	// to switch to new version, just need to update sidecar
 	sc.VMVersion = targetVersion
@@ -345,7 +422,10 @@ func updateExistingLocalVM(sc models.Sidecar, targetVersion string) error {
 		return err
 	}
 
-	// Update the sidecar with new RPC version
+	// Update the sidecar with new VM and RPC version
+	if targetVersion != "" {
+		sc.VMVersion = targetVersion
+	}
 	if err = binutils.UpdateLocalSidecarRPC(app, sc, rpcVersion); err != nil {
 		return fmt.Errorf("unable to set RPC version: %w", err)
 	}
@@ -355,30 +435,81 @@ func updateExistingLocalVM(sc models.Sidecar, targetVersion string) error {
 	return nil
 }
 
-func chooseManualOrAutomatic(sc models.Sidecar, targetVersion string) error {
+func chooseManualOrAutomatic(sc models.Sidecar, targetVersion string, network models.Network) error {
+	var (
+		vmPath string
+		err    error
+	)
 	switch {
 	case useManual:
-		return plugins.ManualUpgrade(app, sc, targetVersion)
+		vmPath, err = plugins.ManualUpgrade(app, sc, targetVersion)
 	case pluginDir != "":
-		return plugins.AutomatedUpgrade(app, sc, targetVersion, pluginDir)
+		vmPath, err = plugins.AutomatedUpgrade(app, sc, targetVersion, pluginDir)
+	default:
+		const (
+			choiceManual    = "Manual"
+			choiceAutomatic = "Automatic (Make sure your node isn't running)"
+		)
+		var choice string
+		choice, err = app.Prompt.CaptureList(
+			"How would you like to update the avalanchego config?",
+			[]string{choiceAutomatic, choiceManual},
+		)
+		if err != nil {
+			return err
+		}
+		if choice == choiceManual {
+			vmPath, err = plugins.ManualUpgrade(app, sc, targetVersion)
+		} else {
+			vmPath, err = plugins.AutomatedUpgrade(app, sc, targetVersion, pluginDir)
+		}
 	}
-
-	const (
-		choiceManual    = "Manual"
-		choiceAutomatic = "Automatic (Make sure your node isn't running)"
-	)
-	choice, err := app.Prompt.CaptureList(
-		"How would you like to update the avalanchego config?",
-		[]string{choiceAutomatic, choiceManual},
-	)
 	if err != nil {
 		return err
 	}
+	return updatePublicSidecar(sc, targetVersion, vmPath, network)
+}
 
-	if choice == choiceManual {
-		return plugins.ManualUpgrade(app, sc, targetVersion)
+// updatePublicSidecar records the upgraded VM version into the sidecar, and prints
+// the information validator operators need to verify the upgrade artifact
+func updatePublicSidecar(sc models.Sidecar, targetVersion string, vmPath string, network models.Network) error {
+	rpcVersion, err := vm.GetVMBinaryProtocolVersion(vmPath)
+	if err != nil {
+		return fmt.Errorf("unable to get RPC version: %w", err)
 	}
-	return plugins.AutomatedUpgrade(app, sc, targetVersion, pluginDir)
+	checksum, err := utils.GetSHA256FromDisk(vmPath)
+	if err != nil {
+		return err
+	}
+	vmid, err := sc.GetVMID()
+	if err != nil {
+		return err
+	}
+	if targetVersion != "" {
+		sc.VMVersion = targetVersion
+	}
+	if networkData, ok := sc.Networks[network.Name()]; ok {
+		networkData.RPCVersion = rpcVersion
+		sc.Networks[network.Name()] = networkData
+	}
+	if err := app.UpdateSidecar(&sc); err != nil {
+		return fmt.Errorf("failed to update sidecar: %w", err)
+	}
+
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Upgrade artifact for %s validators:", network.Name())
+	ux.Logger.PrintToUser("  VM ID: %s", vmid)
+	if targetVersion != "" {
+		ux.Logger.PrintToUser("  VM version: %s", targetVersion)
+	}
+	ux.Logger.PrintToUser("  RPC protocol version: %d", rpcVersion)
+	ux.Logger.PrintToUser("  Binary: %s", vmPath)
+	ux.Logger.PrintToUser("  SHA256: %s", checksum)
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Share these with your validators. Every validator must run a metalgo version")
+	ux.Logger.PrintToUser("compatible with RPC protocol version %d, and replace the plugin binary named %s", rpcVersion, vmid)
+	ux.Logger.PrintToUser("in its plugin directory before restarting.")
+	return nil
 }
 
 func isServerRunning() (bool, error) {
@@ -4,6 +4,7 @@
 package upgradecmd
 
 import (
+	"io"
 	"os"
 	"testing"
 
@@ -341,3 +342,47 @@ func TestUpdateToCustomBin(t *testing.T) {
 	assert.Equal(models.VMTypeFromString(models.CustomVM), diskSC.VM)
 	assert.Empty(diskSC.VMVersion)
 }
+
+func TestUpdateToCustomBinCustomVM(t *testing.T) {
+	assert := require.New(t)
+	testDir := t.TempDir()
+	defer func() {
+		binaryVersionArg = ""
+	}()
+
+	subnetName := "testSubnet"
+	sc := models.Sidecar{
+		Name:       subnetName,
+		VM:         models.CustomVM,
+		VMVersion:  "v1.0.0",
+		RPCVersion: 20,
+		Subnet:     subnetName,
+	}
+
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+	app = &application.Avalanche{}
+	app.Setup(testDir, logging.NoLog{}, config.New(), prompts.NewPrompter(), application.NewDownloader())
+	assert.NoError(os.MkdirAll(app.GetSubnetDir(), constants.DefaultPerms755))
+	assert.NoError(app.CreateSidecar(&sc))
+	assert.NoError(os.MkdirAll(app.GetCustomVMDir(), constants.DefaultPerms755))
+
+	binaryPath := "../../../tests/assets/dummyVmBinary.bin"
+	expectedHash, err := utils.GetSHA256FromDisk(binaryPath)
+	assert.NoError(err)
+
+	// the version is increased
+	assert.NoError(updateToCustomBin(sc, futureDeployment, binaryPath, false))
+	diskSC, err := app.LoadSidecar(subnetName)
+	assert.NoError(err)
+	assert.Equal("v1.0.1", diskSC.VMVersion)
+	assert.Equal(models.VMBinary{Version: "v1.0.1", SHA256: expectedHash, Source: binaryPath}, diskSC.VMBinary)
+
+	// the given version must be greater than the current one
+	binaryVersionArg = "v1.0.1"
+	assert.ErrorContains(updateToCustomBin(diskSC, futureDeployment, binaryPath, false), "must be greater")
+	binaryVersionArg = "v2.0.0"
+	assert.NoError(updateToCustomBin(diskSC, futureDeployment, binaryPath, false))
+	diskSC, err = app.LoadSidecar(subnetName)
+	assert.NoError(err)
+	assert.Equal("v2.0.0", diskSC.VMVersion)
+}
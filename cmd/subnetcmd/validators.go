@@ -12,13 +12,17 @@ import (
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
 	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
 	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
-	"github.com/MetalBlockchain/metalgo/ids"
 	"github.com/MetalBlockchain/metalgo/vms/platformvm"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
 
-var validatorsSupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Tahoe, networkoptions.Mainnet, networkoptions.Cluster, networkoptions.Devnet}
+var (
+	scheduleOutputPath string
+	scheduleFormat     string
+)
+
+var validatorsSupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Tahoe, networkoptions.Mainnet, networkoptions.Cluster, networkoptions.Devnet, networkoptions.Environment}
 
 // avalanche subnet validators
 func newValidatorsCmd() *cobra.Command {
@@ -26,12 +30,18 @@ func newValidatorsCmd() *cobra.Command {
 		Use:   "validators [subnetName]",
 		Short: "List a subnet's validators",
 		Long: `The subnet validators command lists the validators of a subnet and provides
-severarl statistics about them.`,
-		RunE:         printValidators,
-		Args:         cobra.ExactArgs(1),
+severarl statistics about them.
+
+With --export-schedule, the upcoming validation start and end times are also
+written into a calendar (ICS) file, or a crontab if --schedule-format=cron is
+given, so that operators can track when each node must be online.`,
+		RunE:         withActiveSubnet(printValidators),
+		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 	}
-	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, validatorsSupportedNetworkOptions)
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, validatorsSupportedNetworkOptions)
+	cmd.Flags().StringVar(&scheduleOutputPath, "export-schedule", "", "export upcoming validation start/end times into this file")
+	cmd.Flags().StringVar(&scheduleFormat, "schedule-format", scheduleFormatICS, "format of the exported schedule (ics or cron)")
 	return cmd
 }
 
@@ -62,29 +72,28 @@ func printValidators(_ *cobra.Command, args []string) error {
 
 	subnetID := deployInfo.SubnetID
 
+	var validators []platformvm.ClientPermissionlessValidator
 	if network.Kind == models.Local {
-		return printLocalValidators(subnetID)
+		validators, err = subnet.GetSubnetValidators(subnetID)
 	} else {
-		return printPublicValidators(subnetID, network)
+		validators, err = subnet.GetPublicSubnetValidators(subnetID, network)
 	}
-}
-
-func printLocalValidators(subnetID ids.ID) error {
-	validators, err := subnet.GetSubnetValidators(subnetID)
 	if err != nil {
 		return err
 	}
 
-	return printValidatorsFromList(validators)
-}
-
-func printPublicValidators(subnetID ids.ID, network models.Network) error {
-	validators, err := subnet.GetPublicSubnetValidators(subnetID, network)
-	if err != nil {
+	if err := printValidatorsFromList(validators); err != nil {
 		return err
 	}
-
-	return printValidatorsFromList(validators)
+	if scheduleOutputPath != "" {
+		// validators added with a future start time are not current yet
+		pendingValidators, err := subnet.GetPendingValidators(subnetID, network)
+		if err != nil {
+			return err
+		}
+		return exportValidatorsSchedule(subnetName, append(validators, pendingValidators...), scheduleOutputPath, scheduleFormat)
+	}
+	return nil
 }
 
 func printValidatorsFromList(validators []platformvm.ClientPermissionlessValidator) error {
@@ -5,17 +5,26 @@ package subnetcmd
 import (
 	"fmt"
 
+	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
 	"github.com/MetalBlockchain/metal-network-runner/utils"
+	"github.com/MetalBlockchain/metalgo/ids"
 	"github.com/spf13/cobra"
 )
 
-// avalanche subnet create
+// avalanche subnet vmid
 func vmidCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:          "vmid [vmName]",
-		Short:        "Prints the VMID of a VM",
-		Long:         `The subnet vmid command prints the virtual machine ID (VMID) for the given Subnet.`,
+		Use:   "vmid [vmName|vmID]",
+		Short: "Prints the VMID of a VM, or the VM name of a VMID",
+		Long: `The subnet vmid command prints the virtual machine ID (VMID) for the given Subnet
+or VM name. Subnets imported from a repository use the VMID they were published with.
+
+Given a VMID instead, it prints the VM name it was derived from, if it was
+derived from one.
+
+Local deploys make the local network nodes know each VM by its name too, so the
+VM API and plugin can be referred to by name after the network is restarted.`,
 		SilenceUsage: true,
 		Args:         cobra.ExactArgs(1),
 		RunE:         printVMID,
@@ -24,17 +33,31 @@ func vmidCmd() *cobra.Command {
 }
 
 func printVMID(_ *cobra.Command, args []string) error {
-	chains, err := ValidateSubnetNameAndGetChains(args)
-	if err != nil {
-		return err
+	name := args[0]
+	if app.SidecarExists(name) {
+		sc, err := app.LoadSidecar(name)
+		if err != nil {
+			return err
+		}
+		vmID, err := sc.GetVMID()
+		if err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser(fmt.Sprintf("VM ID : %s", vmID))
+		return nil
 	}
-
-	chain := chains[0]
-	vmID, err := utils.VMID(chain)
+	if vmID, err := ids.FromString(name); err == nil {
+		vmName, ok := subnet.VMIDToName(vmID)
+		if !ok {
+			return fmt.Errorf("VM ID %s is not derived from a VM name", vmID)
+		}
+		ux.Logger.PrintToUser(fmt.Sprintf("VM Name : %s", vmName))
+		return nil
+	}
+	vmID, err := utils.VMID(name)
 	if err != nil {
 		return err
 	}
-
 	ux.Logger.PrintToUser(fmt.Sprintf("VM ID : %s", vmID.String()))
 	return nil
 }
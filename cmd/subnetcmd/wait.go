@@ -0,0 +1,229 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/evm"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metalgo/api/info"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultWaitTimeout  = 10 * time.Minute
+	defaultWaitInterval = 5 * time.Second
+	minHeightFlag       = "min-height"
+)
+
+var (
+	waitTimeout   time.Duration
+	waitInterval  time.Duration
+	waitMinHeight uint64
+)
+
+// avalanche subnet wait
+func newWaitCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wait [subnetName]",
+		Short: "Wait until a deployed subnet blockchain is live",
+		Long: `The subnet wait command polls a deployed Subnet blockchain until it is live,
+for up to --timeout. A blockchain is live once the network endpoint reports it
+as bootstrapped and, for Subnet-EVM based subnets, its RPC answers with a chain
+height of at least --min-height. --min-height is required for Subnet-EVM based
+subnets: use 1 to wait for a first block to be produced after genesis, or the
+height the chain must reach.
+
+The command prints the blockchain status once live, as JSON if --json is given,
+and exits with a non-zero status if the timeout is reached, so it can be used
+from CI pipelines right after a deploy.`,
+		RunE:         withActiveSubnet(waitForSubnet),
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, validatorsSupportedNetworkOptions)
+	cmd.Flags().DurationVar(&waitTimeout, "timeout", defaultWaitTimeout, "maximum time to wait for the blockchain to be live")
+	cmd.Flags().DurationVar(&waitInterval, "interval", defaultWaitInterval, "time between checks")
+	cmd.Flags().Uint64Var(&waitMinHeight, minHeightFlag, 0, "chain height the blockchain must reach to be considered live [required for Subnet-EVM]")
+	flags.AddJSONOutputFlag(cmd)
+	return cmd
+}
+
+// blockchainStatus is the status of a deployed blockchain, as printed by subnet wait
+type blockchainStatus struct {
+	Subnet       string  `json:"subnet"`
+	Network      string  `json:"network"`
+	BlockchainID string  `json:"blockchainID"`
+	RPCURL       string  `json:"rpcURL,omitempty"`
+	Bootstrapped bool    `json:"bootstrapped"`
+	Height       *uint64 `json:"height,omitempty"`
+	Live         bool    `json:"live"`
+	Elapsed      string  `json:"elapsed"`
+	// Problem describes why the blockchain is not live yet
+	Problem string `json:"problem,omitempty"`
+}
+
+// blockchainProber queries the current state of a blockchain
+type blockchainProber interface {
+	IsBootstrapped(ctx context.Context) (bool, error)
+	// Height returns the chain height, or false if the VM offers no way to get it
+	Height(ctx context.Context) (uint64, bool, error)
+}
+
+type apiBlockchainProber struct {
+	endpoint     string
+	blockchainID ids.ID
+	rpcURL       string
+}
+
+func (p apiBlockchainProber) IsBootstrapped(ctx context.Context) (bool, error) {
+	return info.NewClient(p.endpoint).IsBootstrapped(ctx, p.blockchainID.String())
+}
+
+func (p apiBlockchainProber) Height(ctx context.Context) (uint64, bool, error) {
+	if p.rpcURL == "" {
+		return 0, false, nil
+	}
+	client, err := evm.GetClient(p.rpcURL)
+	if err != nil {
+		return 0, true, err
+	}
+	defer client.Close()
+	height, err := client.BlockNumber(ctx)
+	return height, true, err
+}
+
+func waitForSubnet(cmd *cobra.Command, args []string) error {
+	if waitInterval <= 0 {
+		return errors.New("--interval must be positive")
+	}
+	subnetName := args[0]
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		globalNetworkFlags,
+		false,
+		validatorsSupportedNetworkOptions,
+		subnetName,
+	)
+	if err != nil {
+		return err
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	deployInfo, ok := sc.Networks[network.Name()]
+	if !ok || deployInfo.BlockchainID == ids.Empty {
+		return ux.NewCodedError(ux.ErrCodeNotFound, fmt.Errorf("no blockchain deployment found for subnet %s on %s", subnetName, network.Name()))
+	}
+	status := blockchainStatus{
+		Subnet:       subnetName,
+		Network:      network.Name(),
+		BlockchainID: deployInfo.BlockchainID.String(),
+	}
+	prober := apiBlockchainProber{
+		endpoint:     network.Endpoint,
+		blockchainID: deployInfo.BlockchainID,
+	}
+	if sc.VM == models.SubnetEvm {
+		// any height is reached right after genesis, which doesn't prove the chain is live
+		if !cmd.Flags().Changed(minHeightFlag) {
+			return ux.NewCodedError(
+				ux.ErrCodeInvalidArguments,
+				fmt.Errorf("--%s is required for Subnet-EVM blockchains. Use 1 to wait for a first block after genesis", minHeightFlag),
+			)
+		}
+		prober.rpcURL = network.BlockchainEndpoint(deployInfo.BlockchainID.String())
+		status.RPCURL = prober.rpcURL
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, waitTimeout)
+	defer cancel()
+
+	ux.Logger.PrintToUser("Waiting up to %s for %s to be live on %s", waitTimeout, subnetName, network.Name())
+	status, err = waitUntilLive(ctx, prober, status, waitMinHeight, waitInterval)
+	if err != nil {
+		return err
+	}
+	if ux.JSONOutput() {
+		return ux.PrintResult(status)
+	}
+	ux.Logger.GreenCheckmarkToUser("Blockchain %s is live after %s", status.BlockchainID, status.Elapsed)
+	if status.Height != nil {
+		ux.Logger.PrintToUser("Chain height: %d", *status.Height)
+	}
+	return nil
+}
+
+// waitUntilLive checks the blockchain every [interval] until it is live, or [ctx] is done
+func waitUntilLive(
+	ctx context.Context,
+	prober blockchainProber,
+	status blockchainStatus,
+	minHeight uint64,
+	interval time.Duration,
+) (blockchainStatus, error) {
+	start := time.Now()
+	for {
+		status = probeBlockchain(ctx, prober, status, minHeight)
+		status.Elapsed = time.Since(start).Round(time.Second).String()
+		if status.Live {
+			return status, nil
+		}
+		select {
+		case <-ctx.Done():
+			return status, ux.NewCodedError(
+				ux.ErrCodeTimeout,
+				fmt.Errorf("blockchain %s is not live after %s: %s", status.BlockchainID, status.Elapsed, status.Problem),
+			)
+		case <-time.After(interval):
+		}
+	}
+}
+
+func probeBlockchain(ctx context.Context, prober blockchainProber, status blockchainStatus, minHeight uint64) blockchainStatus {
+	status.Live = false
+	status.Bootstrapped = false
+	status.Problem = ""
+	status.Height = nil
+	reqCtx, cancel := context.WithTimeout(ctx, constants.APIRequestTimeout)
+	defer cancel()
+	bootstrapped, err := prober.IsBootstrapped(reqCtx)
+	switch {
+	case err != nil:
+		status.Problem = fmt.Sprintf("failed to get bootstrap status: %s", err)
+		return status
+	case !bootstrapped:
+		status.Problem = "not bootstrapped"
+		return status
+	}
+	status.Bootstrapped = true
+	height, ok, err := prober.Height(reqCtx)
+	switch {
+	case err != nil:
+		status.Problem = fmt.Sprintf("RPC endpoint is not responding: %s", err)
+		return status
+	case ok:
+		status.Height = &height
+		if height < minHeight {
+			status.Problem = fmt.Sprintf("chain height %d is below %d", height, minHeight)
+			return status
+		}
+	}
+	status.Live = true
+	return status
+}
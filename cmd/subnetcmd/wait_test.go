@@ -0,0 +1,68 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProber reports a chain that bootstraps after [bootstrapAfter] checks, and
+// then grows one block per check
+type fakeProber struct {
+	checks         int
+	bootstrapAfter int
+	hasHeight      bool
+	rpcErr         error
+}
+
+func (p *fakeProber) IsBootstrapped(context.Context) (bool, error) {
+	p.checks++
+	return p.checks > p.bootstrapAfter, nil
+}
+
+func (p *fakeProber) Height(context.Context) (uint64, bool, error) {
+	if !p.hasHeight {
+		return 0, false, nil
+	}
+	if p.rpcErr != nil {
+		return 0, true, p.rpcErr
+	}
+	return uint64(p.checks - p.bootstrapAfter - 1), true, nil
+}
+
+func TestWaitUntilLive(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	status, err := waitUntilLive(ctx, &fakeProber{bootstrapAfter: 2}, blockchainStatus{}, 0, time.Millisecond)
+	require.NoError(err)
+	require.True(status.Live)
+	require.True(status.Bootstrapped)
+	require.Nil(status.Height)
+
+	prober := &fakeProber{bootstrapAfter: 1, hasHeight: true}
+	status, err = waitUntilLive(ctx, prober, blockchainStatus{}, 3, time.Millisecond)
+	require.NoError(err)
+	require.True(status.Live)
+	require.Equal(uint64(3), *status.Height)
+	require.Equal(5, prober.checks)
+}
+
+func TestWaitUntilLiveTimeout(t *testing.T) {
+	require := require.New(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	prober := &fakeProber{hasHeight: true, rpcErr: errors.New("connection refused")}
+	status, err := waitUntilLive(ctx, prober, blockchainStatus{BlockchainID: "chain"}, 0, time.Millisecond)
+	require.Error(err)
+	require.Equal(ux.ErrCodeTimeout, ux.GetErrorCode(err))
+	require.Contains(err.Error(), "connection refused")
+	require.False(status.Live)
+	require.True(status.Bootstrapped)
+}
@@ -0,0 +1,313 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package subnetcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/evm"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
+	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metal-cli/pkg/webhook"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultWatchInterval = time.Minute
+
+	watchAlertKind    = "alert"
+	watchResolvedKind = "resolved"
+	watchEventKind    = "event"
+)
+
+var (
+	watchInterval      time.Duration
+	watchExpiryWindow  time.Duration
+	watchWebhookURL    string
+	watchOnce          bool
+	watchStalledHeight bool
+)
+
+// avalanche subnet watch
+func newWatchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch [subnetName...]",
+		Short: "Continuously run health checks against deployed subnets",
+		Long: `The subnet watch command periodically checks one or more deployed subnets
+and reports any problem found:
+
+- RPC liveness of the subnet blockchain
+- chain height progress between checks, with --alert-stalled-height
+- validators joining or leaving the subnet validator set
+- validators whose validation period ends within --expiry-window
+
+RPC and height checks are only run for Subnet-EVM based subnets. Subnet-EVM
+only produces blocks when there are transactions to include, so the height of
+an idle chain doesn't progress. Only enable --alert-stalled-height for chains
+expected to have transactions between checks.
+
+Alerts are printed, and also posted as JSON to --webhook-url if given.
+A condition is only alerted when it starts, and again when it is resolved.
+
+With --once, a single round of checks is run, and the command exits with a
+non-zero status if any alert was raised.`,
+		RunE:         withActiveSubnet(watchSubnets),
+		Args:         cobra.ArbitraryArgs,
+		SilenceUsage: true,
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, validatorsSupportedNetworkOptions)
+	cmd.Flags().DurationVar(&watchInterval, "interval", defaultWatchInterval, "time between check rounds")
+	cmd.Flags().DurationVar(&watchExpiryWindow, "expiry-window", defaultExpiryWindow, "alert on validators whose validation ends within this duration")
+	cmd.Flags().StringVar(&watchWebhookURL, "webhook-url", "", "post alerts as JSON to this URL")
+	cmd.Flags().BoolVar(&watchOnce, "once", false, "run a single round of checks and exit")
+	cmd.Flags().BoolVar(&watchStalledHeight, "alert-stalled-height", false, "alert when the chain height doesn't progress between checks")
+	return cmd
+}
+
+// subnetWatcher keeps the state of the checks for a single subnet between rounds
+type subnetWatcher struct {
+	subnetName string
+	network    models.Network
+	subnetID   ids.ID
+	rpcURL     string
+	// validators seen on the previous round, nil before the first one
+	validators map[ids.NodeID]struct{}
+	height     uint64
+	// active alert conditions, by key
+	alerts map[string]string
+}
+
+func watchSubnets(_ *cobra.Command, args []string) error {
+	if watchInterval <= 0 {
+		return errors.New("--interval must be positive")
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		globalNetworkFlags,
+		false,
+		validatorsSupportedNetworkOptions,
+		args[0],
+	)
+	if err != nil {
+		return err
+	}
+
+	watchers := []*subnetWatcher{}
+	for _, subnetName := range args {
+		sc, err := app.LoadSidecar(subnetName)
+		if err != nil {
+			return err
+		}
+		deployInfo, ok := sc.Networks[network.Name()]
+		if !ok {
+			return fmt.Errorf("no deployment found for subnet %s on %s", subnetName, network.Name())
+		}
+		watcher := &subnetWatcher{
+			subnetName: subnetName,
+			network:    network,
+			subnetID:   deployInfo.SubnetID,
+			alerts:     map[string]string{},
+		}
+		if sc.VM == models.SubnetEvm && deployInfo.BlockchainID != ids.Empty {
+			watcher.rpcURL = network.BlockchainEndpoint(deployInfo.BlockchainID.String())
+		}
+		watchers = append(watchers, watcher)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ux.Logger.PrintToUser("Watching %s on %s every %s", strings.Join(args, ", "), network.Name(), strings.TrimSpace(ux.FormatDuration(watchInterval)))
+	for {
+		alerts := 0
+		for _, watcher := range watchers {
+			alerts += watcher.runChecks(time.Now())
+		}
+		if watchOnce {
+			if alerts > 0 {
+				return fmt.Errorf("%d alerts raised", alerts)
+			}
+			ux.Logger.PrintToUser("All checks passed")
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(watchInterval):
+		}
+	}
+}
+
+// runChecks runs a round of checks, notifying about changes, and returns the
+// amount of currently active alerts
+func (w *subnetWatcher) runChecks(now time.Time) int {
+	conditions := map[string]string{}
+
+	if w.rpcURL != "" {
+		height, err := getChainHeight(w.rpcURL)
+		switch {
+		case err != nil:
+			conditions["rpc"] = fmt.Sprintf("RPC endpoint %s is not responding: %s", w.rpcURL, err)
+		case watchStalledHeight && w.height != 0 && height <= w.height:
+			conditions["height"] = fmt.Sprintf("chain height stuck at %d since the previous check", height)
+		}
+		if err == nil {
+			w.height = height
+		}
+	}
+
+	var (
+		validators []platformvm.ClientPermissionlessValidator
+		err        error
+	)
+	if w.network.Kind == models.Local {
+		validators, err = subnet.GetSubnetValidators(w.subnetID)
+	} else {
+		validators, err = subnet.GetPublicSubnetValidators(w.subnetID, w.network)
+	}
+	if err != nil {
+		conditions["validators"] = fmt.Sprintf("failed to get validators: %s", err)
+	} else {
+		if w.validators == nil {
+			// report the initial set, as the baseline of the join and leave events
+			ux.Logger.PrintToUser("[%s] %d validators: %s", w.subnetName, len(validators), formatValidatorSet(validators))
+		}
+		added, removed := diffValidatorSet(w.validators, validators)
+		for _, nodeID := range added {
+			w.notify(watchEventKind, fmt.Sprintf("validator %s joined the validator set", nodeID))
+		}
+		for _, nodeID := range removed {
+			w.notify(watchEventKind, fmt.Sprintf("validator %s left the validator set", nodeID))
+		}
+		w.validators = map[ids.NodeID]struct{}{}
+		for _, validator := range validators {
+			w.validators[validator.NodeID] = struct{}{}
+			if isExpiringWithin(validator, now, watchExpiryWindow) {
+				conditions["expiry-"+validator.NodeID.String()] = fmt.Sprintf(
+					"validator %s expires on %s",
+					validator.NodeID,
+					formatUnixTime(validator.EndTime),
+				)
+			}
+		}
+	}
+
+	raised, resolved := diffWatchConditions(w.alerts, conditions)
+	for _, key := range raised {
+		w.notify(watchAlertKind, conditions[key])
+	}
+	for _, key := range resolved {
+		w.notify(watchResolvedKind, "resolved: "+w.alerts[key])
+	}
+	w.alerts = conditions
+	return len(conditions)
+}
+
+func (w *subnetWatcher) notify(kind string, message string) {
+	text := fmt.Sprintf("[%s] %s: %s", w.subnetName, kind, message)
+	if kind == watchAlertKind {
+		ux.Logger.RedXToUser("%s", text)
+	} else {
+		ux.Logger.PrintToUser("%s", text)
+	}
+	if watchWebhookURL == "" {
+		return
+	}
+	if err := webhook.Send(watchWebhookURL, webhook.Event{
+		Text:    text,
+		Kind:    kind,
+		Subnet:  w.subnetName,
+		Network: w.network.Name(),
+		Time:    time.Now().UTC(),
+	}); err != nil {
+		ux.Logger.RedXToUser("failed to notify webhook: %s", err)
+	}
+}
+
+func getChainHeight(rpcURL string) (uint64, error) {
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return 0, err
+	}
+	defer client.Close()
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	return client.BlockNumber(ctx)
+}
+
+// diffValidatorSet returns the node IDs present in [current] but not in [previous],
+// and the ones present in [previous] but not in [current]. A nil [previous] means
+// there is no previous round to compare against
+func diffValidatorSet(
+	previous map[ids.NodeID]struct{},
+	current []platformvm.ClientPermissionlessValidator,
+) ([]ids.NodeID, []ids.NodeID) {
+	if previous == nil {
+		return nil, nil
+	}
+	added := []ids.NodeID{}
+	currentSet := map[ids.NodeID]struct{}{}
+	for _, validator := range current {
+		currentSet[validator.NodeID] = struct{}{}
+		if _, ok := previous[validator.NodeID]; !ok {
+			added = append(added, validator.NodeID)
+		}
+	}
+	removed := []ids.NodeID{}
+	for nodeID := range previous {
+		if _, ok := currentSet[nodeID]; !ok {
+			removed = append(removed, nodeID)
+		}
+	}
+	sort.Slice(removed, func(i, j int) bool {
+		return removed[i].Compare(removed[j]) < 0
+	})
+	return added, removed
+}
+
+// formatValidatorSet returns the sorted node IDs of [validators], comma separated
+func formatValidatorSet(validators []platformvm.ClientPermissionlessValidator) string {
+	nodeIDs := make([]string, 0, len(validators))
+	for _, validator := range validators {
+		nodeIDs = append(nodeIDs, validator.NodeID.String())
+	}
+	if len(nodeIDs) == 0 {
+		return "none"
+	}
+	sort.Strings(nodeIDs)
+	return strings.Join(nodeIDs, ", ")
+}
+
+// diffWatchConditions returns the sorted keys of the conditions that started
+// and of the ones that ended between [previous] and [current]
+func diffWatchConditions(previous map[string]string, current map[string]string) ([]string, []string) {
+	raised := []string{}
+	for key := range current {
+		if _, ok := previous[key]; !ok {
+			raised = append(raised, key)
+		}
+	}
+	resolved := []string{}
+	for key := range previous {
+		if _, ok := current[key]; !ok {
+			resolved = append(resolved, key)
+		}
+	}
+	sort.Strings(raised)
+	sort.Strings(resolved)
+	return raised, resolved
+}
@@ -0,0 +1,56 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package subnetcmd
+
+import (
+	"testing"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffValidatorSet(t *testing.T) {
+	require := require.New(t)
+	kept := ids.GenerateTestNodeID()
+	left := ids.GenerateTestNodeID()
+	joined := ids.GenerateTestNodeID()
+	current := []platformvm.ClientPermissionlessValidator{
+		{ClientStaker: platformvm.ClientStaker{NodeID: kept}},
+		{ClientStaker: platformvm.ClientStaker{NodeID: joined}},
+	}
+
+	added, removed := diffValidatorSet(nil, current)
+	require.Empty(added)
+	require.Empty(removed)
+
+	previous := map[ids.NodeID]struct{}{kept: {}, left: {}}
+	added, removed = diffValidatorSet(previous, current)
+	require.Equal([]ids.NodeID{joined}, added)
+	require.Equal([]ids.NodeID{left}, removed)
+}
+
+func TestDiffWatchConditions(t *testing.T) {
+	require := require.New(t)
+	previous := map[string]string{"rpc": "down", "expiry-a": "expiring"}
+	current := map[string]string{"expiry-a": "expiring", "height": "stuck"}
+	raised, resolved := diffWatchConditions(previous, current)
+	require.Equal([]string{"height"}, raised)
+	require.Equal([]string{"rpc"}, resolved)
+}
+
+func TestFormatValidatorSet(t *testing.T) {
+	require := require.New(t)
+	require.Equal("none", formatValidatorSet(nil))
+	a := ids.GenerateTestNodeID()
+	b := ids.GenerateTestNodeID()
+	expected := []string{a.String(), b.String()}
+	if expected[0] > expected[1] {
+		expected[0], expected[1] = expected[1], expected[0]
+	}
+	require.Equal(expected[0]+", "+expected[1], formatValidatorSet([]platformvm.ClientPermissionlessValidator{
+		{ClientStaker: platformvm.ClientStaker{NodeID: b}},
+		{ClientStaker: platformvm.ClientStaker{NodeID: a}},
+	}))
+}
@@ -0,0 +1,169 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package teleportercmd
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/evm"
+	"github.com/MetalBlockchain/metal-cli/pkg/key"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
+	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
+	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
+	"github.com/MetalBlockchain/metal-cli/pkg/teleporter"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/spf13/cobra"
+)
+
+var (
+	deploySupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Tahoe}
+
+	globalNetworkFlags networkoptions.NetworkFlags
+	keyName            string
+	teleporterVersion  string
+	skipCChain         bool
+)
+
+// metal teleporter deploy
+func newDeployCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deploy [subnetName]",
+		Short: "Deploys Teleporter into a subnet",
+		Long: `The teleporter deploy command deploys the Teleporter Messenger and Registry
+contracts into the blockchain of the given Subnet, so cross-chain messages can be
+sent and received by it. The contract addresses are recorded in the Subnet
+configuration, and shown by subnet describe.
+
+The Messenger is deployed with the deploy transaction of the Teleporter release,
+so it has the same address on every chain. On local networks, Teleporter is also
+deployed into the C-Chain if it is not already there, unless --skip-c-chain is given.
+
+On local networks the deploys are paid by the ewoq key. On Tahoe, a stored key
+funded on the Subnet blockchain is used.`,
+		SilenceUsage: true,
+		RunE:         deploy,
+		Args:         cobra.ExactArgs(1),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, deploySupportedNetworkOptions)
+	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to pay for the deploys [tahoe only]")
+	cmd.Flags().StringVar(&teleporterVersion, "version", "latest", "teleporter release to deploy")
+	cmd.Flags().BoolVar(&skipCChain, "skip-c-chain", false, "do not deploy teleporter into the C-Chain [local only]")
+	return cmd
+}
+
+func deploy(_ *cobra.Command, args []string) error {
+	subnetName := args[0]
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	if sc.VM != models.SubnetEvm {
+		return fmt.Errorf("teleporter can only be deployed to Subnet-EVM based subnets")
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		globalNetworkFlags,
+		false,
+		deploySupportedNetworkOptions,
+		subnetName,
+	)
+	if err != nil {
+		return err
+	}
+	networkData := sc.Networks[network.Name()]
+	if networkData.BlockchainID == ids.Empty {
+		return fmt.Errorf("subnet %s is not deployed to %s", subnetName, network.Name())
+	}
+	privateKey, err := getPrivateKey(network)
+	if err != nil {
+		return err
+	}
+	if teleporterVersion == "" || teleporterVersion == "latest" {
+		teleporterVersion, err = teleporter.GetLatestVersion(app)
+		if err != nil {
+			return err
+		}
+	}
+	deployer, err := teleporter.NewDeployer(app, teleporterVersion)
+	if err != nil {
+		return err
+	}
+	messengerAddress, registryAddress, err := deployer.Deploy(
+		subnetName,
+		network.BlockchainEndpoint(networkData.BlockchainID.String()),
+		privateKey,
+	)
+	if err != nil {
+		return err
+	}
+	networkData.TeleporterMessengerAddress = messengerAddress
+	networkData.TeleporterRegistryAddress = registryAddress
+	sc.Networks[network.Name()] = networkData
+	sc.TeleporterReady = true
+	sc.TeleporterVersion = teleporterVersion
+	if err := app.UpdateSidecar(&sc); err != nil {
+		return err
+	}
+	if network.Kind == models.Local && !skipCChain {
+		return deployToLocalCChain(deployer, network)
+	}
+	return nil
+}
+
+// deployToLocalCChain deploys teleporter into the C-Chain of the local network,
+// if it was not already deployed there
+func deployToLocalCChain(deployer *teleporter.Deployer, network models.Network) error {
+	extraLocalNetworkData, err := subnet.GetExtraLocalNetworkData(app)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if extraLocalNetworkData != nil && extraLocalNetworkData.CChainTeleporterRegistryAddress != "" {
+		client, err := evm.GetClient(network.CChainEndpoint())
+		if err != nil {
+			return err
+		}
+		deployed, err := evm.ContractAlreadyDeployed(client, extraLocalNetworkData.CChainTeleporterRegistryAddress)
+		if err != nil {
+			return err
+		}
+		if deployed {
+			return nil
+		}
+	}
+	k, err := key.LoadEwoq(network.ID)
+	if err != nil {
+		return err
+	}
+	messengerAddress, registryAddress, err := deployer.Deploy("C-Chain", network.CChainEndpoint(), hex.EncodeToString(k.Raw()))
+	if err != nil {
+		return err
+	}
+	return subnet.WriteExtraLocalNetworkData(app, messengerAddress, registryAddress)
+}
+
+// getPrivateKey returns the hex encoded private key that pays for the deploys
+func getPrivateKey(network models.Network) (string, error) {
+	var (
+		k   *key.SoftKey
+		err error
+	)
+	if network.Kind == models.Local {
+		k, err = key.LoadEwoq(network.ID)
+	} else {
+		if keyName == "" {
+			keyName, err = prompts.CaptureKeyName(app.Prompt, "pay for the teleporter deploys", app.GetKeyDir())
+			if err != nil {
+				return "", err
+			}
+		}
+		k, err = key.LoadSoft(network.ID, app.GetKeyPath(keyName))
+	}
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(k.Raw()), nil
+}
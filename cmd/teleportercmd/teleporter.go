@@ -0,0 +1,32 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package teleportercmd
+
+import (
+	"fmt"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/spf13/cobra"
+)
+
+var app *application.Avalanche
+
+// metal teleporter
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "teleporter",
+		Short: "Interact with Teleporter cross-chain messaging",
+		Long: `The teleporter command suite provides a collection of tools for deploying
+and using Teleporter, the cross-chain messaging protocol, on your Subnets.`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+	}
+	app = injectedApp
+	// teleporter deploy
+	cmd.AddCommand(newDeployCmd())
+	return cmd
+}
@@ -4,6 +4,7 @@ package transactioncmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/MetalBlockchain/metal-cli/cmd/subnetcmd"
 	"github.com/MetalBlockchain/metal-cli/pkg/keychain"
@@ -27,6 +28,7 @@ func newTransactionCommitCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&inputTxPath, inputTxPathFlag, "", "Path to the transaction signed by all signatories")
+	cmd.Flags().StringVar(&inputTxPath, "input", "", "alias for --"+inputTxPathFlag)
 	return cmd
 }
 
@@ -87,6 +89,12 @@ func commitTx(_ *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	// the committing keychain is a throwaway one, so record the tx signers as issuers
+	txRecords := deployer.IssuedTxs()
+	for i := range txRecords {
+		txRecords[i].Issuer = strings.Join(subnetAuthKeys, ",")
+	}
+	sc.AddTxRecords(network.Name(), txRecords...)
 
 	if txutils.IsCreateChainTx(tx) {
 		// TODO: teleporter for multisig
@@ -103,5 +111,5 @@ func commitTx(_ *cobra.Command, args []string) error {
 	}
 	ux.Logger.PrintToUser("Transaction successful, transaction ID: %s", txID)
 
-	return nil
+	return app.UpdateSidecar(&sc)
 }
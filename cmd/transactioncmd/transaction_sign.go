@@ -6,14 +6,17 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/MetalBlockchain/metal-cli/cmd/flags"
 	"github.com/MetalBlockchain/metal-cli/cmd/subnetcmd"
 	"github.com/MetalBlockchain/metal-cli/pkg/keychain"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
 	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
 	"github.com/MetalBlockchain/metal-cli/pkg/subnet"
 	"github.com/MetalBlockchain/metal-cli/pkg/txutils"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
 	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/txs"
 	"github.com/spf13/cobra"
 )
 
@@ -24,25 +27,37 @@ var (
 	keyName         string
 	useLedger       bool
 	ledgerAddresses []string
+	signerSpec      string
+	airGapped       bool
 
-	errNoSubnetID = errors.New("failed to find the subnet ID for this subnet, has it been deployed/created on this network?")
+	errNoSubnetID           = errors.New("failed to find the subnet ID for this subnet, has it been deployed/created on this network?")
+	errNoOfflineSigningInfo = errors.New("the tx file does not contain offline signing info. " +
+		"Sign it once without --air-gapped on a connected machine, or export it again with an updated CLI")
 )
 
 // avalanche transaction sign
 func newTransactionSignCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:          "sign [subnetName]",
-		Short:        "sign a transaction",
-		Long:         "The transaction sign command signs a multisig transaction.",
+		Use:   "sign [subnetName]",
+		Short: "sign a transaction",
+		Long: `The transaction sign command signs a multisig transaction.
+
+With --air-gapped, no network access is needed: the subnet owner info is read from the tx
+file, as exported on a connected machine, so that signing can be done on an air-gapped
+machine. The global --offline flag also implies it. The signed file can then be
+broadcasted with the transaction commit command.`,
 		RunE:         signTx,
 		Args:         cobra.ExactArgs(1),
 		SilenceUsage: true,
 	}
 
 	cmd.Flags().StringVar(&inputTxPath, inputTxPathFlag, "", "Path to the transaction file for signing")
+	cmd.Flags().StringVar(&inputTxPath, "input", "", "alias for --"+inputTxPathFlag)
 	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use [fuji only]")
 	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on fuji)")
 	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	flags.AddSignerFlag(cmd, &signerSpec)
+	cmd.Flags().BoolVar(&airGapped, "air-gapped", false, "sign without network access, using the subnet owner info saved in the tx file")
 	return cmd
 }
 
@@ -54,7 +69,7 @@ func signTx(_ *cobra.Command, args []string) error {
 			return err
 		}
 	}
-	tx, err := txutils.LoadFromDisk(inputTxPath)
+	tx, signingInfo, err := txutils.LoadFromDiskWithSigningInfo(inputTxPath)
 	if err != nil {
 		return err
 	}
@@ -63,7 +78,7 @@ func signTx(_ *cobra.Command, args []string) error {
 		useLedger = true
 	}
 
-	if useLedger && keyName != "" {
+	if !flags.EnsureMutuallyExclusive([]bool{useLedger, keyName != "", signerSpec != ""}) {
 		return subnetcmd.ErrMutuallyExlusiveKeyLedger
 	}
 
@@ -74,14 +89,14 @@ func signTx(_ *cobra.Command, args []string) error {
 	}
 	switch network.Kind {
 	case models.Tahoe, models.Local:
-		if !useLedger && keyName == "" {
+		if !useLedger && keyName == "" && signerSpec == "" {
 			useLedger, keyName, err = prompts.GetFujiKeyOrLedger(app.Prompt, "sign transaction", app.GetKeyDir())
 			if err != nil {
 				return err
 			}
 		}
 	case models.Mainnet:
-		useLedger = true
+		useLedger = signerSpec == ""
 		if keyName != "" {
 			return subnetcmd.ErrStoredKeyOnMainnet
 		}
@@ -89,30 +104,21 @@ func signTx(_ *cobra.Command, args []string) error {
 		return errors.New("unsupported network")
 	}
 
-	// we need subnet wallet signing validation + process
 	subnetName := args[0]
-	sc, err := app.LoadSidecar(subnetName)
-	if err != nil {
-		return err
-	}
-	subnetID := sc.Networks[network.Name()].SubnetID
-	if subnetID == ids.Empty {
-		return errNoSubnetID
-	}
-	transferSubnetOwnershipTxID := sc.Networks[network.Name()].TransferSubnetOwnershipTxID
-
-	subnetIDFromTX, err := txutils.GetSubnetID(tx)
-	if err != nil {
-		return err
-	}
-	if subnetIDFromTX != ids.Empty {
-		subnetID = subnetIDFromTX
-	}
-
-	controlKeys, _, err := txutils.GetOwners(network, subnetID, transferSubnetOwnershipTxID)
-	if err != nil {
-		return err
+	airGapped = airGapped || utils.IsOffline()
+	var transferSubnetOwnershipTxID ids.ID
+	if airGapped {
+		if signingInfo == nil {
+			return errNoOfflineSigningInfo
+		}
+	} else {
+		signingInfo, transferSubnetOwnershipTxID, err = getSigningInfo(tx, subnetName, network)
+		if err != nil {
+			return err
+		}
 	}
+	subnetID := signingInfo.SubnetID
+	controlKeys := signingInfo.ControlKeys
 
 	// get the remaining tx signers so as to check that the wallet does contain an expected signer
 	subnetAuthKeys, remainingSubnetAuthKeys, err := txutils.GetRemainingSigners(tx, controlKeys)
@@ -127,7 +133,7 @@ func signTx(_ *cobra.Command, args []string) error {
 	}
 
 	// get keychain accessor
-	kc, err := keychain.GetKeychain(app, false, useLedger, ledgerAddresses, keyName, network, 0)
+	kc, err := keychain.GetKeychain(app, txutils.GetKeyRole(tx), false, useLedger, ledgerAddresses, signerSpec, keyName, network, 0)
 	if err != nil {
 		return err
 	}
@@ -138,12 +144,17 @@ func signTx(_ *cobra.Command, args []string) error {
 	}
 
 	deployer := subnet.NewPublicDeployer(app, kc, network)
-	if err := deployer.Sign(
-		tx,
-		remainingSubnetAuthKeys,
-		subnetID,
-		transferSubnetOwnershipTxID,
-	); err != nil {
+	if airGapped {
+		err = deployer.SignOffline(tx, remainingSubnetAuthKeys, signingInfo)
+	} else {
+		err = deployer.Sign(
+			tx,
+			remainingSubnetAuthKeys,
+			subnetID,
+			transferSubnetOwnershipTxID,
+		)
+	}
+	if err != nil {
 		if errors.Is(err, subnet.ErrNoSubnetAuthKeysInWallet) {
 			ux.Logger.PrintToUser("There are no required subnet auth keys present in the wallet")
 			ux.Logger.PrintToUser("")
@@ -171,9 +182,46 @@ func signTx(_ *cobra.Command, args []string) error {
 		remainingSubnetAuthKeys,
 		inputTxPath,
 		true,
+		signingInfo,
 	); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// gets the subnet owner info needed to sign [tx] from the P-Chain, using the
+// subnet ID of [subnetName] sidecar unless the tx specifies one
+func getSigningInfo(
+	tx *txs.Tx,
+	subnetName string,
+	network models.Network,
+) (*txutils.OfflineSigningInfo, ids.ID, error) {
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return nil, ids.Empty, err
+	}
+	subnetID := sc.Networks[network.Name()].SubnetID
+	if subnetID == ids.Empty {
+		return nil, ids.Empty, errNoSubnetID
+	}
+	transferSubnetOwnershipTxID := sc.Networks[network.Name()].TransferSubnetOwnershipTxID
+
+	subnetIDFromTX, err := txutils.GetSubnetID(tx)
+	if err != nil {
+		return nil, ids.Empty, err
+	}
+	if subnetIDFromTX != ids.Empty {
+		subnetID = subnetIDFromTX
+	}
+
+	controlKeys, threshold, err := txutils.GetOwners(network, subnetID, transferSubnetOwnershipTxID)
+	if err != nil {
+		return nil, ids.Empty, err
+	}
+	return &txutils.OfflineSigningInfo{
+		SubnetID:    subnetID,
+		ControlKeys: controlKeys,
+		Threshold:   threshold,
+	}, transferSubnetOwnershipTxID, nil
+}
@@ -4,6 +4,7 @@ package updatecmd
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -13,6 +14,7 @@ import (
 	"github.com/MetalBlockchain/metal-cli/pkg/application"
 	"github.com/MetalBlockchain/metal-cli/pkg/binutils"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -48,6 +50,9 @@ func runUpdate(cmd *cobra.Command, _ []string) error {
 }
 
 func Update(cmd *cobra.Command, isUserCalled bool, version string, lastActs *application.LastActions) error {
+	if utils.IsOffline() {
+		return fmt.Errorf("%w: can't check for updates", utils.ErrOffline)
+	}
 	// first check if there is a new version exists
 	url := binutils.GetGithubLatestReleaseURL(constants.AvaLabsOrg, constants.CliRepoName)
 	latest, err := app.Downloader.GetLatestReleaseVersion(url)
@@ -0,0 +1,31 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package usecmd
+
+import (
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// metal use clear
+func newClearCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "clear",
+		Short:        "Unset the active subnet and network",
+		Long:         "The use clear command unsets the active subnet and network, restoring the default prompts.",
+		RunE:         clearContext,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+	}
+}
+
+func clearContext(_ *cobra.Command, _ []string) error {
+	if err := app.SetActiveSubnet(""); err != nil {
+		return err
+	}
+	if err := app.SetActiveNetwork("", ""); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser(activeContextStatus())
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package usecmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// metal use network
+func newNetworkCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "network [local | tahoe | testnet | mainnet | cluster [clusterName]]",
+		Short: "Set the active network",
+		Long: `The use network command sets the network used by network aware commands
+when no network flag is given.`,
+		RunE:         useNetwork,
+		Args:         cobra.RangeArgs(1, 2),
+		SilenceUsage: true,
+	}
+}
+
+func useNetwork(_ *cobra.Command, args []string) error {
+	var networkOption networkoptions.NetworkOption
+	switch strings.ToLower(args[0]) {
+	case "local":
+		networkOption = networkoptions.Local
+	case "tahoe", "testnet":
+		networkOption = networkoptions.Tahoe
+	case "mainnet":
+		networkOption = networkoptions.Mainnet
+	case "cluster":
+		networkOption = networkoptions.Cluster
+	default:
+		return fmt.Errorf("invalid network %q", args[0])
+	}
+	clusterName := ""
+	if networkOption == networkoptions.Cluster {
+		if len(args) != 2 {
+			return errors.New("cluster name must be provided for cluster networks")
+		}
+		clusterName = args[1]
+		exists, err := app.ClusterExists(clusterName)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("cluster %s does not exist", clusterName)
+		}
+	} else if len(args) != 1 {
+		return fmt.Errorf("unexpected argument %q for network %s", args[1], args[0])
+	}
+	if err := app.SetActiveNetwork(networkOption.String(), clusterName); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser(activeContextStatus())
+	return nil
+}
@@ -0,0 +1,34 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package usecmd
+
+import (
+	"fmt"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// metal use subnet
+func newSubnetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "subnet [subnetName]",
+		Short:        "Set the active subnet",
+		Long:         "The use subnet command sets the subnet used by subnet commands when no subnetName is given.",
+		RunE:         useSubnet,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+}
+
+func useSubnet(_ *cobra.Command, args []string) error {
+	subnetName := args[0]
+	if !app.SidecarExists(subnetName) {
+		return fmt.Errorf("subnet %s does not exist", subnetName)
+	}
+	if err := app.SetActiveSubnet(subnetName); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser(activeContextStatus())
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package usecmd
+
+import (
+	"fmt"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/networkoptions"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var app *application.Avalanche
+
+// metal use
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "use",
+		Short: "Set the active subnet and network",
+		Long: `The use command suite sets a default subnet and network for subsequent
+commands.
+
+Once a subnet is active, subnet commands such as addValidator or deploy
+can be called without the subnetName argument. Once a network is active,
+network aware commands use it instead of prompting, if it is supported by
+the command (network flags still take precedence).
+
+Call it without subcommands to print the active subnet and network.`,
+		RunE:         printActiveContext,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+	}
+	// metal use subnet
+	cmd.AddCommand(newSubnetCmd())
+	// metal use network
+	cmd.AddCommand(newNetworkCmd())
+	// metal use clear
+	cmd.AddCommand(newClearCmd())
+	return cmd
+}
+
+func printActiveContext(_ *cobra.Command, _ []string) error {
+	ux.Logger.PrintToUser(activeContextStatus())
+	return nil
+}
+
+// activeContextStatus returns a one line description of the active subnet and network
+func activeContextStatus() string {
+	subnetName := app.GetActiveSubnet()
+	if subnetName == "" {
+		subnetName = "<none>"
+	}
+	networkName, clusterName := app.GetActiveNetwork()
+	networkDesc := "<none>"
+	if networkName != "" {
+		networkDesc = networkoptions.ActiveNetworkDescription(networkName, clusterName)
+	}
+	return fmt.Sprintf("Active subnet: %s | Active network: %s", subnetName, networkDesc)
+}
@@ -874,9 +874,9 @@ func (_m *Prompter) CaptureVersion(promptStr string) (string, error) {
 	return r0, r1
 }
 
-// CaptureWeight provides a mock function with given fields: promptStr
-func (_m *Prompter) CaptureWeight(promptStr string) (uint64, error) {
-	ret := _m.Called(promptStr)
+// CaptureWeight provides a mock function with given fields: promptStr, validator
+func (_m *Prompter) CaptureWeight(promptStr string, validator func(uint64) error) (uint64, error) {
+	ret := _m.Called(promptStr, validator)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CaptureWeight")
@@ -884,17 +884,17 @@ func (_m *Prompter) CaptureWeight(promptStr string) (uint64, error) {
 
 	var r0 uint64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (uint64, error)); ok {
-		return rf(promptStr)
+	if rf, ok := ret.Get(0).(func(string, func(uint64) error) (uint64, error)); ok {
+		return rf(promptStr, validator)
 	}
-	if rf, ok := ret.Get(0).(func(string) uint64); ok {
-		r0 = rf(promptStr)
+	if rf, ok := ret.Get(0).(func(string, func(uint64) error) uint64); ok {
+		r0 = rf(promptStr, validator)
 	} else {
 		r0 = ret.Get(0).(uint64)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(promptStr)
+	if rf, ok := ret.Get(1).(func(string, func(uint64) error) error); ok {
+		r1 = rf(promptStr, validator)
 	} else {
 		r1 = ret.Error(1)
 	}
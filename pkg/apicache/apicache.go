@@ -0,0 +1,99 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package apicache
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm"
+)
+
+// PChainTTL is how long P-Chain query results are reused
+const PChainTTL = 30 * time.Second
+
+var currentValidators = New[[]platformvm.ClientPermissionlessValidator](PChainTTL)
+
+type entry[T any] struct {
+	value   T
+	expires time.Time
+}
+
+// Cache keeps query results for [ttl], so flows that repeat the same queries,
+// eg interactive prompts, don't wait for the network each time. Failed queries
+// are not cached
+type Cache[T any] struct {
+	lock    sync.Mutex
+	ttl     time.Duration
+	entries map[string]entry[T]
+	now     func() time.Time
+}
+
+func New[T any](ttl time.Duration) *Cache[T] {
+	return &Cache[T]{
+		ttl:     ttl,
+		entries: map[string]entry[T]{},
+		now:     time.Now,
+	}
+}
+
+// Get returns the cached value for [key] if it did not expire, or the one
+// obtained by calling [fetch] otherwise
+func (c *Cache[T]) Get(key string, fetch func() (T, error)) (T, error) {
+	c.lock.Lock()
+	e, ok := c.entries[key]
+	c.lock.Unlock()
+	if ok && c.now().Before(e.expires) {
+		return e.value, nil
+	}
+	value, err := fetch()
+	if err != nil {
+		return value, err
+	}
+	c.lock.Lock()
+	c.entries[key] = entry[T]{value: value, expires: c.now().Add(c.ttl)}
+	c.lock.Unlock()
+	return value, nil
+}
+
+// Purge drops all the cached values
+func (c *Cache[T]) Purge() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.entries = map[string]entry[T]{}
+}
+
+// GetCurrentValidators returns the current validators of [subnetID] known by the
+// P-Chain at [endpoint], optionally filtered by [nodeIDs].
+// It is meant for interactive prompt flows only: other processes may issue txs
+// meanwhile, so checks that need the current validator set query the P-Chain
+// directly, eg with subnet.GetPublicSubnetValidators
+func GetCurrentValidators(
+	ctx context.Context,
+	endpoint string,
+	subnetID ids.ID,
+	nodeIDs []ids.NodeID,
+) ([]platformvm.ClientPermissionlessValidator, error) {
+	nodeIDStrs := make([]string, len(nodeIDs))
+	for i, nodeID := range nodeIDs {
+		nodeIDStrs[i] = nodeID.String()
+	}
+	slices.Sort(nodeIDStrs)
+	key := fmt.Sprintf("%s|%s|%s", endpoint, subnetID, strings.Join(nodeIDStrs, ","))
+	validators, err := currentValidators.Get(key, func() ([]platformvm.ClientPermissionlessValidator, error) {
+		return platformvm.NewClient(endpoint).GetCurrentValidators(ctx, subnetID, nodeIDs)
+	})
+	// callers may modify the result
+	return slices.Clone(validators), err
+}
+
+// Purge drops all the cached P-Chain query results. It must be called after
+// issuing txs that change them, and before polling for changes
+func Purge() {
+	currentValidators.Purge()
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package apicache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	require := require.New(t)
+	now := time.Now()
+	c := New[int](time.Minute)
+	c.now = func() time.Time { return now }
+	calls := 0
+	fetch := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	value, err := c.Get("a", fetch)
+	require.NoError(err)
+	require.Equal(1, value)
+	// cached
+	value, err = c.Get("a", fetch)
+	require.NoError(err)
+	require.Equal(1, value)
+	// other keys are fetched separately
+	value, err = c.Get("b", fetch)
+	require.NoError(err)
+	require.Equal(2, value)
+	// expired
+	now = now.Add(time.Minute)
+	value, err = c.Get("a", fetch)
+	require.NoError(err)
+	require.Equal(3, value)
+	// purged
+	c.Purge()
+	value, err = c.Get("a", fetch)
+	require.NoError(err)
+	require.Equal(4, value)
+
+	// errors are not cached
+	errFetch := errors.New("fetch failed")
+	_, err = c.Get("c", func() (int, error) { return 0, errFetch })
+	require.ErrorIs(err, errFetch)
+	value, err = c.Get("c", fetch)
+	require.NoError(err)
+	require.Equal(5, value)
+}
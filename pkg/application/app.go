@@ -4,13 +4,17 @@ package application
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"sync"
 
 	"github.com/MetalBlockchain/apm/apm"
 	"github.com/MetalBlockchain/metal-cli/pkg/config"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/filelock"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
 	"github.com/MetalBlockchain/metal-cli/pkg/monitoring"
 	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
@@ -19,6 +23,7 @@ import (
 	"github.com/MetalBlockchain/metalgo/utils/logging"
 	"github.com/MetalBlockchain/subnet-evm/core"
 
+	"go.uber.org/zap"
 	"golang.org/x/exp/maps"
 )
 
@@ -30,6 +35,17 @@ type Avalanche struct {
 	Apm        *apm.APM
 	ApmDir     string
 	Downloader Downloader
+	// name of the selected local network, empty for the default one
+	localNetworkName string
+	// index of the selected local network, 0 for the default one
+	localNetworkIndex int
+	// command path of the running command, recorded as owner of the app
+	// directory lock
+	command string
+	// app directory lock, see Lock
+	lockMu    sync.Mutex
+	lock      *filelock.Lock
+	lockDepth int
 }
 
 func New() *Avalanche {
@@ -44,12 +60,18 @@ func (app *Avalanche) Setup(baseDir string, log logging.Logger, conf *config.Con
 	app.Downloader = downloader
 }
 
+// SetCommand sets the path of the running command, eg "metal subnet deploy",
+// shown to other processes waiting for the app directory lock
+func (app *Avalanche) SetCommand(command string) {
+	app.command = command
+}
+
 func (app *Avalanche) GetRunFile() string {
 	return filepath.Join(app.GetRunDir(), constants.ServerRunFile)
 }
 
 func (app *Avalanche) GetSnapshotsDir() string {
-	return filepath.Join(app.baseDir, constants.SnapshotsDirName)
+	return filepath.Join(app.getLocalNetworkBaseDir(), constants.SnapshotsDirName)
 }
 
 func (app *Avalanche) GetBaseDir() string {
@@ -68,8 +90,12 @@ func (app *Avalanche) GetReposDir() string {
 	return filepath.Join(app.baseDir, constants.ReposDir)
 }
 
+func (app *Avalanche) GetDevnetsDir() string {
+	return filepath.Join(app.baseDir, constants.DevnetsDir)
+}
+
 func (app *Avalanche) GetRunDir() string {
-	return filepath.Join(app.baseDir, constants.RunDir)
+	return filepath.Join(app.getLocalNetworkBaseDir(), constants.RunDir)
 }
 
 func (app *Avalanche) GetServicesDir(baseDir string) string {
@@ -84,7 +110,7 @@ func (app *Avalanche) GetCustomVMDir() string {
 }
 
 func (app *Avalanche) GetPluginsDir() string {
-	return filepath.Join(app.baseDir, constants.PluginDir)
+	return filepath.Join(app.getLocalNetworkBaseDir(), constants.PluginDir)
 }
 
 // Remove all plugins from plugin dir
@@ -106,6 +132,18 @@ func (app *Avalanche) GetAvalanchegoBinDir() string {
 	return filepath.Join(app.baseDir, constants.AvalancheCliBinDir, constants.AvalancheGoInstallDir)
 }
 
+func (app *Avalanche) GetDownloadCacheDir() string {
+	return filepath.Join(app.baseDir, constants.AvalancheCliBinDir, constants.DownloadCacheDir)
+}
+
+func (app *Avalanche) GetPartialDownloadsDir() string {
+	return filepath.Join(app.GetDownloadCacheDir(), constants.PartialDownloadsDir)
+}
+
+func (app *Avalanche) GetMetadataCacheDir() string {
+	return filepath.Join(app.GetDownloadCacheDir(), constants.MetadataCacheDir)
+}
+
 func (app *Avalanche) GetTeleporterBinDir() string {
 	return filepath.Join(app.baseDir, constants.AvalancheCliBinDir, constants.TeleporterInstallDir)
 }
@@ -130,6 +168,10 @@ func (app *Avalanche) GetAWMRelayerRunPath() string {
 	return filepath.Join(app.GetRunDir(), constants.AWMRelayerRunFilename)
 }
 
+func (app *Avalanche) GetServeTokenPath() string {
+	return filepath.Join(app.GetRunDir(), constants.ServeTokenFilename)
+}
+
 func (app *Avalanche) GetAWMRelayerSnapshotConfsDir() string {
 	return filepath.Join(app.GetSnapshotsDir(), constants.AWMRelayerSnapshotConfsDir)
 }
@@ -150,6 +192,11 @@ func (app *Avalanche) GetExtraLocalNetworkDataPath() string {
 	return filepath.Join(app.GetRunDir(), constants.ExtraLocalNetworkDataFilename)
 }
 
+// GetVMAliasesPath returns the VM aliases file given to the local network nodes
+func (app *Avalanche) GetVMAliasesPath() string {
+	return filepath.Join(app.GetRunDir(), constants.VMAliasesFilename)
+}
+
 func (app *Avalanche) GetExtraLocalNetworkSnapshotsDir() string {
 	return filepath.Join(app.GetSnapshotsDir(), constants.ExtraLocalNetworkDataSnapshotsDir)
 }
@@ -206,6 +253,16 @@ func (app *Avalanche) GetAnsibleDir() string {
 	return filepath.Join(app.GetNodesDir(), constants.AnsibleDir)
 }
 
+// GetLocalMonitoringDir returns the dir of the monitoring stack of the local network
+func (app *Avalanche) GetLocalMonitoringDir() string {
+	return filepath.Join(app.GetRunDir(), constants.MonitoringDir)
+}
+
+// GetLocalExplorerDir returns the dir of the block explorer of [subnetName] on the local network
+func (app *Avalanche) GetLocalExplorerDir(subnetName string) string {
+	return filepath.Join(app.GetRunDir(), constants.ExplorerDir, subnetName)
+}
+
 func (app *Avalanche) GetMonitoringDir() string {
 	return filepath.Join(app.GetNodesDir(), constants.MonitoringDir)
 }
@@ -400,7 +457,7 @@ func (app *Avalanche) CopyGenesisFile(inputFilename string, subnetName string) e
 		return err
 	}
 
-	return os.WriteFile(genesisPath, genesisBytes, constants.WriteReadReadPerms)
+	return app.writeFileLocked(genesisPath, genesisBytes, constants.WriteReadReadPerms)
 }
 
 func (app *Avalanche) CopyVMBinary(inputFilename string, subnetName string) error {
@@ -409,7 +466,7 @@ func (app *Avalanche) CopyVMBinary(inputFilename string, subnetName string) erro
 		return err
 	}
 	vmPath := app.GetCustomVMPath(subnetName)
-	return os.WriteFile(vmPath, vmBytes, constants.DefaultPerms755)
+	return app.writeFileLocked(vmPath, vmBytes, constants.DefaultPerms755)
 }
 
 func (app *Avalanche) CopyKeyFile(inputFilename string, keyName string) error {
@@ -418,7 +475,7 @@ func (app *Avalanche) CopyKeyFile(inputFilename string, keyName string) error {
 		return err
 	}
 	keyPath := app.GetKeyPath(keyName)
-	return os.WriteFile(keyPath, keyBytes, constants.WriteReadReadPerms)
+	return app.writeFileLocked(keyPath, keyBytes, constants.WriteReadReadPerms)
 }
 
 func (app *Avalanche) LoadEvmGenesis(subnetName string) (core.Genesis, error) {
@@ -475,7 +532,7 @@ func (app *Avalanche) CreateSidecar(sc *models.Sidecar) error {
 		return err
 	}
 
-	return os.WriteFile(sidecarPath, scBytes, constants.WriteReadReadPerms)
+	return app.writeFileLocked(sidecarPath, scBytes, constants.WriteReadReadPerms)
 }
 
 func (app *Avalanche) LoadSidecar(subnetName string) (models.Sidecar, error) {
@@ -504,7 +561,37 @@ func (app *Avalanche) UpdateSidecar(sc *models.Sidecar) error {
 	}
 
 	sidecarPath := app.GetSidecarPath(sc.Name)
-	return os.WriteFile(sidecarPath, scBytes, constants.WriteReadReadPerms)
+	return app.writeFileLocked(sidecarPath, scBytes, constants.WriteReadReadPerms)
+}
+
+// EditSidecar applies [edit] to the sidecar of [subnetName] and saves it, holding
+// the app directory lock from load to save, so that concurrent changes by other
+// processes are not lost. Returns the saved sidecar
+func (app *Avalanche) EditSidecar(subnetName string, edit func(sc *models.Sidecar) error) (models.Sidecar, error) {
+	release, err := app.Lock()
+	if err != nil {
+		return models.Sidecar{}, err
+	}
+	defer release()
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return models.Sidecar{}, err
+	}
+	if err := edit(&sc); err != nil {
+		return models.Sidecar{}, err
+	}
+	return sc, app.UpdateSidecar(&sc)
+}
+
+// editSidecarCopy applies [edit] to [sc], and saves it applied to the current
+// sidecar file instead, so that only the edited fields are written
+func (app *Avalanche) editSidecarCopy(sc *models.Sidecar, edit func(sc *models.Sidecar)) error {
+	edit(sc)
+	_, err := app.EditSidecar(sc.Name, func(current *models.Sidecar) error {
+		edit(current)
+		return nil
+	})
+	return err
 }
 
 func (app *Avalanche) UpdateSidecarNetworks(
@@ -516,10 +603,7 @@ func (app *Avalanche) UpdateSidecarNetworks(
 	teleporterMessengerAddress string,
 	teleporterRegistryAddress string,
 ) error {
-	if sc.Networks == nil {
-		sc.Networks = make(map[string]models.NetworkData)
-	}
-	sc.Networks[network.Name()] = models.NetworkData{
+	networkData := models.NetworkData{
 		SubnetID:                    subnetID,
 		TransferSubnetOwnershipTxID: transferSubnetOwnershipTxID,
 		BlockchainID:                blockchainID,
@@ -527,12 +611,31 @@ func (app *Avalanche) UpdateSidecarNetworks(
 		TeleporterMessengerAddress:  teleporterMessengerAddress,
 		TeleporterRegistryAddress:   teleporterRegistryAddress,
 	}
-	if err := app.UpdateSidecar(sc); err != nil {
+	if err := app.editSidecarCopy(sc, func(sc *models.Sidecar) {
+		if sc.Networks == nil {
+			sc.Networks = make(map[string]models.NetworkData)
+		}
+		sc.Networks[network.Name()] = networkData
+	}); err != nil {
 		return fmt.Errorf("creation of chains and subnet was successful, but failed to update sidecar: %w", err)
 	}
 	return nil
 }
 
+// UpdateSidecarTxHistory appends the given tx [records] to the sidecar history for [network]
+func (app *Avalanche) UpdateSidecarTxHistory(
+	sc *models.Sidecar,
+	network models.Network,
+	records []models.TxRecord,
+) error {
+	if len(records) == 0 {
+		return nil
+	}
+	return app.editSidecarCopy(sc, func(sc *models.Sidecar) {
+		sc.AddTxRecords(network.Name(), records...)
+	})
+}
+
 func (app *Avalanche) UpdateSidecarElasticSubnet(
 	sc *models.Sidecar,
 	network models.Network,
@@ -542,22 +645,20 @@ func (app *Avalanche) UpdateSidecarElasticSubnet(
 	tokenName string,
 	tokenSymbol string,
 ) error {
-	if sc.ElasticSubnet == nil {
-		sc.ElasticSubnet = make(map[string]models.ElasticSubnet)
-	}
-	partialTxs := sc.ElasticSubnet[network.Name()].Txs
-	sc.ElasticSubnet[network.Name()] = models.ElasticSubnet{
-		SubnetID:    subnetID,
-		AssetID:     assetID,
-		PChainTXID:  pchainTXID,
-		TokenName:   tokenName,
-		TokenSymbol: tokenSymbol,
-		Txs:         partialTxs,
-	}
-	if err := app.UpdateSidecar(sc); err != nil {
-		return err
-	}
-	return nil
+	return app.editSidecarCopy(sc, func(sc *models.Sidecar) {
+		if sc.ElasticSubnet == nil {
+			sc.ElasticSubnet = make(map[string]models.ElasticSubnet)
+		}
+		partialTxs := sc.ElasticSubnet[network.Name()].Txs
+		sc.ElasticSubnet[network.Name()] = models.ElasticSubnet{
+			SubnetID:    subnetID,
+			AssetID:     assetID,
+			PChainTXID:  pchainTXID,
+			TokenName:   tokenName,
+			TokenSymbol: tokenSymbol,
+			Txs:         partialTxs,
+		}
+	})
 }
 
 func (app *Avalanche) UpdateSidecarPermissionlessValidator(
@@ -566,16 +667,17 @@ func (app *Avalanche) UpdateSidecarPermissionlessValidator(
 	nodeID string,
 	txID ids.ID,
 ) error {
-	elasticSubnet := sc.ElasticSubnet[network.Name()]
-	if elasticSubnet.Validators == nil {
-		elasticSubnet.Validators = make(map[string]models.PermissionlessValidators)
-	}
-	elasticSubnet.Validators[nodeID] = models.PermissionlessValidators{TxID: txID}
-	sc.ElasticSubnet[network.Name()] = elasticSubnet
-	if err := app.UpdateSidecar(sc); err != nil {
-		return err
-	}
-	return nil
+	return app.editSidecarCopy(sc, func(sc *models.Sidecar) {
+		if sc.ElasticSubnet == nil {
+			sc.ElasticSubnet = make(map[string]models.ElasticSubnet)
+		}
+		elasticSubnet := sc.ElasticSubnet[network.Name()]
+		if elasticSubnet.Validators == nil {
+			elasticSubnet.Validators = make(map[string]models.PermissionlessValidators)
+		}
+		elasticSubnet.Validators[nodeID] = models.PermissionlessValidators{TxID: txID}
+		sc.ElasticSubnet[network.Name()] = elasticSubnet
+	})
 }
 
 func (app *Avalanche) UpdateSidecarElasticSubnetPartialTx(
@@ -584,18 +686,19 @@ func (app *Avalanche) UpdateSidecarElasticSubnetPartialTx(
 	txName string,
 	txID ids.ID,
 ) error {
-	if sc.ElasticSubnet == nil {
-		sc.ElasticSubnet = make(map[string]models.ElasticSubnet)
-	}
-	partialTxs := make(map[string]ids.ID)
-	if sc.ElasticSubnet[network.Name()].Txs != nil {
-		partialTxs = sc.ElasticSubnet[network.Name()].Txs
-	}
-	partialTxs[txName] = txID
-	sc.ElasticSubnet[network.Name()] = models.ElasticSubnet{
-		Txs: partialTxs,
-	}
-	return app.UpdateSidecar(sc)
+	return app.editSidecarCopy(sc, func(sc *models.Sidecar) {
+		if sc.ElasticSubnet == nil {
+			sc.ElasticSubnet = make(map[string]models.ElasticSubnet)
+		}
+		partialTxs := make(map[string]ids.ID)
+		if sc.ElasticSubnet[network.Name()].Txs != nil {
+			partialTxs = sc.ElasticSubnet[network.Name()].Txs
+		}
+		partialTxs[txName] = txID
+		sc.ElasticSubnet[network.Name()] = models.ElasticSubnet{
+			Txs: partialTxs,
+		}
+	})
 }
 
 func (app *Avalanche) GetTokenName(subnetName string) string {
@@ -641,12 +744,15 @@ func (*Avalanche) readFile(path string) ([]byte, error) {
 	return os.ReadFile(path)
 }
 
-func (*Avalanche) writeFile(path string, bytes []byte) error {
+func (app *Avalanche) writeFile(path string, bytes []byte) error {
 	if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
 		return err
 	}
+	if app.Log != nil {
+		app.Log.Debug("writing file", zap.String("path", path), zap.Int("size", len(bytes)))
+	}
 
-	return os.WriteFile(path, bytes, constants.WriteReadReadPerms)
+	return app.writeFileLocked(path, bytes, constants.WriteReadReadPerms)
 }
 
 func (app *Avalanche) CreateNodeCloudConfigFile(nodeName string, nodeConfig *models.NodeConfig) error {
@@ -660,7 +766,7 @@ func (app *Avalanche) CreateNodeCloudConfigFile(nodeName string, nodeConfig *mod
 		return err
 	}
 
-	return os.WriteFile(nodeConfigPath, esBytes, constants.WriteReadReadPerms)
+	return app.writeFileLocked(nodeConfigPath, esBytes, constants.WriteReadReadPerms)
 }
 
 func (app *Avalanche) CreateElasticSubnetConfig(subnetName string, es *models.ElasticSubnetConfig) error {
@@ -674,7 +780,7 @@ func (app *Avalanche) CreateElasticSubnetConfig(subnetName string, es *models.El
 		return err
 	}
 
-	return os.WriteFile(elasticSubetConfigPath, esBytes, constants.WriteReadReadPerms)
+	return app.writeFileLocked(elasticSubetConfigPath, esBytes, constants.WriteReadReadPerms)
 }
 
 func (app *Avalanche) LoadElasticSubnetConfig(subnetName string) (models.ElasticSubnetConfig, error) {
@@ -752,7 +858,139 @@ func (app *Avalanche) WriteClustersConfigFile(clustersConfig *models.ClustersCon
 		return err
 	}
 
-	return os.WriteFile(clustersConfigPath, clustersConfigBytes, constants.WriteReadReadPerms)
+	return app.writeFileLocked(clustersConfigPath, clustersConfigBytes, constants.WriteReadReadPerms)
+}
+
+func (app *Avalanche) GetAddressBookPath() string {
+	return filepath.Join(app.baseDir, constants.AddressBookFileName)
+}
+
+// LoadAddressBook returns the stored address book, or an empty one if
+// no entry was added yet
+func (app *Avalanche) LoadAddressBook() (models.AddressBook, error) {
+	jsonBytes, err := os.ReadFile(app.GetAddressBookPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return models.AddressBook{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	addressBook := models.AddressBook{}
+	if err := json.Unmarshal(jsonBytes, &addressBook); err != nil {
+		return nil, fmt.Errorf("failed parsing address book %s: %w", app.GetAddressBookPath(), err)
+	}
+	return addressBook, nil
+}
+
+func (app *Avalanche) WriteAddressBook(addressBook models.AddressBook) error {
+	addressBookPath := app.GetAddressBookPath()
+	if err := os.MkdirAll(filepath.Dir(addressBookPath), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	addressBookBytes, err := json.MarshalIndent(addressBook, "", "    ")
+	if err != nil {
+		return err
+	}
+	return app.writeFileLocked(addressBookPath, addressBookBytes, constants.WriteReadReadPerms)
+}
+
+func (app *Avalanche) GetKeyPolicyPath() string {
+	return filepath.Join(app.baseDir, constants.KeyPolicyFileName)
+}
+
+// LoadKeyPolicy returns the stored key policy, or an empty one if no key was
+// tagged with roles yet
+func (app *Avalanche) LoadKeyPolicy() (models.KeyPolicy, error) {
+	jsonBytes, err := os.ReadFile(app.GetKeyPolicyPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return models.KeyPolicy{Keys: map[string]models.KeyPolicyEntry{}}, nil
+	}
+	if err != nil {
+		return models.KeyPolicy{}, err
+	}
+	keyPolicy := models.KeyPolicy{}
+	if err := json.Unmarshal(jsonBytes, &keyPolicy); err != nil {
+		return models.KeyPolicy{}, fmt.Errorf("failed parsing key policy %s: %w", app.GetKeyPolicyPath(), err)
+	}
+	if keyPolicy.Keys == nil {
+		keyPolicy.Keys = map[string]models.KeyPolicyEntry{}
+	}
+	return keyPolicy, nil
+}
+
+func (app *Avalanche) WriteKeyPolicy(keyPolicy models.KeyPolicy) error {
+	keyPolicyPath := app.GetKeyPolicyPath()
+	if err := os.MkdirAll(filepath.Dir(keyPolicyPath), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	keyPolicyBytes, err := json.MarshalIndent(keyPolicy, "", "    ")
+	if err != nil {
+		return err
+	}
+	return app.writeFileLocked(keyPolicyPath, keyPolicyBytes, constants.WriteReadReadPerms)
+}
+
+func (app *Avalanche) GetAuditLogPath() string {
+	return filepath.Join(app.baseDir, constants.AuditLogFileName)
+}
+
+func (app *Avalanche) GetEnvironmentsPath() string {
+	return filepath.Join(app.baseDir, constants.EnvironmentsFileName)
+}
+
+// LoadEnvironments returns the registered environments, or none if no
+// environment was added yet
+func (app *Avalanche) LoadEnvironments() (models.Environments, error) {
+	jsonBytes, err := os.ReadFile(app.GetEnvironmentsPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return models.Environments{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	environments := models.Environments{}
+	if err := json.Unmarshal(jsonBytes, &environments); err != nil {
+		return nil, fmt.Errorf("failed parsing environments %s: %w", app.GetEnvironmentsPath(), err)
+	}
+	return environments, nil
+}
+
+func (app *Avalanche) WriteEnvironments(environments models.Environments) error {
+	environmentsPath := app.GetEnvironmentsPath()
+	if err := os.MkdirAll(filepath.Dir(environmentsPath), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	environmentsBytes, err := json.MarshalIndent(environments, "", "    ")
+	if err != nil {
+		return err
+	}
+	return app.writeFileLocked(environmentsPath, environmentsBytes, constants.WriteReadReadPerms)
+}
+
+// GetEnvironmentNetwork returns the network of the registered environment [name]
+func (app *Avalanche) GetEnvironmentNetwork(name string) (models.Network, error) {
+	environments, err := app.LoadEnvironments()
+	if err != nil {
+		return models.UndefinedNetwork, err
+	}
+	env, ok := environments[name]
+	if !ok {
+		return models.UndefinedNetwork, fmt.Errorf("environment %q is not registered", name)
+	}
+	return models.NewEnvironmentNetwork(name, env), nil
+}
+
+// ResolveAddressBookLabels replaces the entries of [addrs] that match an address book
+// label with the stored address. Other entries are returned unchanged
+func (app *Avalanche) ResolveAddressBookLabels(addrs []string) ([]string, error) {
+	if len(addrs) == 0 {
+		return addrs, nil
+	}
+	addressBook, err := app.LoadAddressBook()
+	if err != nil {
+		return nil, err
+	}
+	return addressBook.Resolve(addrs), nil
 }
 
 func (*Avalanche) GetSSHCertFilePath(certName string) (string, error) {
@@ -894,3 +1132,54 @@ func (app *Avalanche) ListClusterNames() ([]string, error) {
 	}
 	return maps.Keys(clustersConfig.Clusters), nil
 }
+
+// GetActiveSubnet returns the subnet selected as default for subnet commands, if any
+func (app *Avalanche) GetActiveSubnet() string {
+	if app.Conf == nil {
+		return ""
+	}
+	return app.Conf.GetConfigStringValue(constants.ConfigActiveSubnetKey)
+}
+
+// GetActiveNetwork returns the network (and cluster for cluster networks) selected
+// as default for network aware commands, if any
+func (app *Avalanche) GetActiveNetwork() (string, string) {
+	if app.Conf == nil {
+		return "", ""
+	}
+	return app.Conf.GetConfigStringValue(constants.ConfigActiveNetworkKey),
+		app.Conf.GetConfigStringValue(constants.ConfigActiveClusterKey)
+}
+
+// GetLedgerIndex returns the ledger index selected with key ledger use, if any
+func (app *Avalanche) GetLedgerIndex() (uint32, bool, error) {
+	if app.Conf == nil {
+		return 0, false, nil
+	}
+	indexStr := app.Conf.GetConfigStringValue(constants.ConfigLedgerIndexKey)
+	if indexStr == "" {
+		return 0, false, nil
+	}
+	index, err := strconv.ParseUint(indexStr, 10, 32)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid %s %q in the config file: %w", constants.ConfigLedgerIndexKey, indexStr, err)
+	}
+	return uint32(index), true, nil
+}
+
+// SetLedgerIndex selects the ledger index to use on ledger operations. An empty
+// [indexStr] clears it
+func (app *Avalanche) SetLedgerIndex(indexStr string) error {
+	return app.Conf.SetConfigValue(constants.ConfigLedgerIndexKey, indexStr)
+}
+
+func (app *Avalanche) SetActiveSubnet(subnetName string) error {
+	return app.Conf.SetConfigValue(constants.ConfigActiveSubnetKey, subnetName)
+}
+
+func (app *Avalanche) SetActiveNetwork(networkName string, clusterName string) error {
+	if err := app.Conf.SetConfigValue(constants.ConfigActiveNetworkKey, networkName); err != nil {
+		return err
+	}
+	return app.Conf.SetConfigValue(constants.ConfigActiveClusterKey, clusterName)
+}
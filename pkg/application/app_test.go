@@ -7,10 +7,12 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/MetalBlockchain/metal-cli/pkg/config"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
 	"github.com/MetalBlockchain/metalgo/ids"
 	"github.com/MetalBlockchain/metalgo/utils/logging"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
 )
 
@@ -48,6 +50,29 @@ func TestUpdateSideCar(t *testing.T) {
 	require.Equal(*sc, control)
 }
 
+func TestUpdateSidecarNetworksKeepsConcurrentChanges(t *testing.T) {
+	require := require.New(t)
+	ap := newTestApp(t)
+	sc := &models.Sidecar{Name: "TEST", VM: models.SubnetEvm, TokenName: "Test Token"}
+	require.NoError(ap.CreateSidecar(sc))
+
+	// another process deploys the subnet to another network meanwhile
+	tahoeBlockchainID := ids.GenerateTestID()
+	_, err := ap.EditSidecar(sc.Name, func(sc *models.Sidecar) error {
+		sc.Networks = map[string]models.NetworkData{"Tahoe": {BlockchainID: tahoeBlockchainID}}
+		return nil
+	})
+	require.NoError(err)
+
+	localBlockchainID := ids.GenerateTestID()
+	require.NoError(ap.UpdateSidecarNetworks(sc, models.NewLocalNetwork(), ids.GenerateTestID(), ids.Empty, localBlockchainID, "", ""))
+	require.Equal(localBlockchainID, sc.Networks[models.NewLocalNetwork().Name()].BlockchainID)
+	control, err := ap.LoadSidecar(sc.Name)
+	require.NoError(err)
+	require.Equal(tahoeBlockchainID, control.Networks["Tahoe"].BlockchainID)
+	require.Equal(localBlockchainID, control.Networks[models.NewLocalNetwork().Name()].BlockchainID)
+}
+
 func Test_writeGenesisFile_success(t *testing.T) {
 	require := require.New(t)
 	genesisBytes := []byte("genesis")
@@ -276,6 +301,30 @@ func Test_genesisExists(t *testing.T) {
 	require.NoError(err)
 }
 
+func TestActiveContext(t *testing.T) {
+	require := require.New(t)
+	ap := newTestApp(t)
+
+	require.Empty(ap.GetActiveSubnet())
+	networkName, clusterName := ap.GetActiveNetwork()
+	require.Empty(networkName)
+	require.Empty(clusterName)
+
+	ap.Conf = config.New()
+	ap.Conf.SetConfig(ap.Log, filepath.Join(ap.GetBaseDir(), "config.json"))
+	t.Cleanup(viper.Reset)
+
+	require.NoError(ap.SetActiveSubnet(subnetName1))
+	require.NoError(ap.SetActiveNetwork("Cluster", "myCluster"))
+	require.Equal(subnetName1, ap.GetActiveSubnet())
+	networkName, clusterName = ap.GetActiveNetwork()
+	require.Equal("Cluster", networkName)
+	require.Equal("myCluster", clusterName)
+
+	require.NoError(ap.SetActiveSubnet(""))
+	require.Empty(ap.GetActiveSubnet())
+}
+
 func newTestApp(t *testing.T) *Avalanche {
 	tempDir := t.TempDir()
 	return &Avalanche{
@@ -283,3 +332,32 @@ func newTestApp(t *testing.T) *Avalanche {
 		Log:     logging.NoLog{},
 	}
 }
+
+func TestGetLocalNetworkIndex(t *testing.T) {
+	require := require.New(t)
+	ap := newTestApp(t)
+
+	_, err := ap.GetLocalNetworkIndex("staging", false)
+	require.ErrorIs(err, ErrLocalNetworkNotFound)
+	_, err = ap.GetLocalNetworkIndex("bad name", true)
+	require.Error(err)
+
+	index, err := ap.GetLocalNetworkIndex("staging", true)
+	require.NoError(err)
+	require.Equal(1, index)
+	index, err = ap.GetLocalNetworkIndex("qa", true)
+	require.NoError(err)
+	require.Equal(2, index)
+	index, err = ap.GetLocalNetworkIndex("staging", false)
+	require.NoError(err)
+	require.Equal(1, index)
+
+	// freed indexes are reused
+	require.NoError(ap.RemoveLocalNetwork("staging"))
+	index, err = ap.GetLocalNetworkIndex("dev", true)
+	require.NoError(err)
+	require.Equal(1, index)
+
+	ap.SetLocalNetwork("dev", index)
+	require.Equal(filepath.Join(ap.GetLocalNetworkDir("dev"), constants.RunDir), ap.GetRunDir())
+}
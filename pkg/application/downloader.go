@@ -4,17 +4,31 @@
 package application
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metalgo/utils/logging"
+	"go.uber.org/zap"
 	"golang.org/x/mod/semver"
 )
 
-const githubVersionTagName = "tag_name"
+const (
+	githubVersionTagName = "tag_name"
+	// suffix of the file keeping the ETag or Last-Modified of a partial download
+	partialValidatorSuffix = ".validator"
+)
+
+var ErrDownloadNotFound = errors.New("download not found")
 
 // This is a generic interface for performing highly testable downloads. All methods here involve
 // external http requests. To write tests using these functions, provide a mocked version of this
@@ -26,23 +40,180 @@ type Downloader interface {
 	GetAllReleasesForRepo(org, repo string) ([]string, error)
 }
 
-type downloader struct{}
+type downloader struct {
+	// dir where interrupted downloads are kept to be resumed, if any
+	partialDir string
+}
 
 func NewDownloader() Downloader {
 	return &downloader{}
 }
 
-func (downloader) Download(url string) ([]byte, error) {
-	resp, err := http.Get(url)
+// NewResumingDownloader returns a downloader that keeps interrupted downloads under
+// [partialDir], readable only by the user, and resumes them on the next download of
+// the same URL
+func NewResumingDownloader(partialDir string) Downloader {
+	return &downloader{partialDir: partialDir}
+}
+
+// Download returns the contents at [url]. file:// URLs are read from disk. For a
+// resuming downloader, an interrupted download is resumed if the server identifies
+// the file with an ETag or Last-Modified, so it is checked not to have changed
+func (d downloader) Download(url string) ([]byte, error) {
+	if path, ok := strings.CutPrefix(url, "file://"); ok {
+		bs, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrDownloadNotFound, url)
+		}
+		return bs, err
+	}
+	if d.partialDir == "" {
+		resp, err := getRange(url, 0, "")
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if err := checkDownloadStatus(url, resp.StatusCode); err != nil {
+			return nil, err
+		}
+		return io.ReadAll(resp.Body)
+	}
+	return d.resumableDownload(url)
+}
+
+func (d downloader) resumableDownload(url string) ([]byte, error) {
+	if err := os.MkdirAll(d.partialDir, constants.UserOnlyDirPerms); err != nil {
+		return nil, err
+	}
+	partialPath := d.getPartialDownloadPath(url)
+	offset, validator := getPartialDownload(partialPath)
+	resp, err := getRange(url, offset, validator)
 	if err != nil {
 		return nil, err
 	}
+	if resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0 {
+		// the partial download does not match the file at url, start again
+		resp.Body.Close()
+		if err := removePartialDownload(partialPath); err != nil {
+			return nil, err
+		}
+		offset = 0
+		resp, err = getRange(url, 0, "")
+		if err != nil {
+			return nil, err
+		}
+	}
 	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
+	flags := os.O_CREATE | os.O_WRONLY
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		if !strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)) {
+			return nil, errors.Join(
+				fmt.Errorf("unexpected content range %q resuming %s", resp.Header.Get("Content-Range"), url),
+				removePartialDownload(partialPath),
+			)
+		}
+		flags |= os.O_APPEND
+	case resp.StatusCode == http.StatusOK:
+		// nothing to resume, the file changed, or the server does not support ranges
+		flags |= os.O_TRUNC
+		validator = getResponseValidator(resp.Header)
+		if err := removePartialDownload(partialPath); err != nil {
+			return nil, err
+		}
+		if validator != "" {
+			if err := os.WriteFile(partialPath+partialValidatorSuffix, []byte(validator), constants.WriteReadUserOnlyPerms); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		if err := checkDownloadStatus(url, resp.StatusCode); err != nil {
+			return nil, err
+		}
 		return nil, fmt.Errorf("unexpected http status code: %d", resp.StatusCode)
 	}
+	f, err := os.OpenFile(partialPath, flags, constants.WriteReadUserOnlyPerms)
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.Copy(f, resp.Body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		if validator == "" {
+			// can't be checked to be the same file later on, so it is not resumed
+			return nil, errors.Join(fmt.Errorf("download of %s interrupted: %w", url, err), removePartialDownload(partialPath))
+		}
+		return nil, fmt.Errorf("download of %s interrupted, it will be resumed on the next attempt: %w", url, err)
+	}
+	bs, err := os.ReadFile(partialPath)
+	if err != nil {
+		return nil, err
+	}
+	return bs, removePartialDownload(partialPath)
+}
 
-	return io.ReadAll(resp.Body)
+// getRange requests [url] from byte [offset] on, if the file is still the one
+// identified by [validator]
+func getRange(url string, offset int64, validator string) (*http.Response, error) {
+	request, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		request.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		request.Header.Set("If-Range", validator)
+	}
+	return http.DefaultClient.Do(request)
+}
+
+func checkDownloadStatus(url string, statusCode int) error {
+	switch statusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", ErrDownloadNotFound, url)
+	default:
+		return fmt.Errorf("unexpected http status code: %d", statusCode)
+	}
+}
+
+// getResponseValidator returns the strong ETag of a response, or else its
+// Last-Modified date, to be used on If-Range. Weak ETags can't be used on If-Range
+func getResponseValidator(header http.Header) string {
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return header.Get("Last-Modified")
+}
+
+func (d downloader) getPartialDownloadPath(url string) string {
+	hash := sha256.Sum256([]byte(url))
+	return filepath.Join(d.partialDir, hex.EncodeToString(hash[:]))
+}
+
+// getPartialDownload returns the size and validator of the partial download at
+// [partialPath], or 0 if there is nothing that can be resumed
+func getPartialDownload(partialPath string) (int64, string) {
+	info, err := os.Stat(partialPath)
+	if err != nil {
+		return 0, ""
+	}
+	validator, err := os.ReadFile(partialPath + partialValidatorSuffix)
+	if err != nil || len(validator) == 0 {
+		return 0, ""
+	}
+	return info.Size(), string(validator)
+}
+
+func removePartialDownload(partialPath string) error {
+	for _, path := range []string{partialPath, partialPath + partialValidatorSuffix} {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
 }
 
 // GetLatestPreReleaseVersion returns the latest available pre release version from github
@@ -135,3 +306,115 @@ func (d downloader) GetLatestReleaseVersion(releaseURL string) (string, error) {
 
 	return version, nil
 }
+
+// cachingDownloader keeps the release information and metadata files, like the
+// compatibility and checksums ones, obtained by [Downloader], so they are used
+// on offline mode or when the network is not reachable. On offline mode, other
+// downloads fail with utils.ErrOffline
+type cachingDownloader struct {
+	Downloader
+	cacheDir string
+	log      logging.Logger
+}
+
+// NewCachingDownloader returns a downloader that keeps, under [cacheDir], the
+// release information and metadata files obtained by [downloader]
+func NewCachingDownloader(downloader Downloader, cacheDir string, log logging.Logger) Downloader {
+	return &cachingDownloader{
+		Downloader: downloader,
+		cacheDir:   cacheDir,
+		log:        log,
+	}
+}
+
+func (d cachingDownloader) Download(url string) ([]byte, error) {
+	if strings.HasPrefix(url, "file://") {
+		return d.Downloader.Download(url)
+	}
+	if !isMetadataURL(url) {
+		if utils.IsOffline() {
+			return nil, fmt.Errorf("%w: can't download %s", utils.ErrOffline, url)
+		}
+		return d.Downloader.Download(url)
+	}
+	return d.getCached(url, func() ([]byte, error) {
+		return d.Downloader.Download(url)
+	})
+}
+
+func (d cachingDownloader) GetLatestReleaseVersion(releaseURL string) (string, error) {
+	bs, err := d.getCached(releaseURL, func() ([]byte, error) {
+		version, err := d.Downloader.GetLatestReleaseVersion(releaseURL)
+		return []byte(version), err
+	})
+	return string(bs), err
+}
+
+func (d cachingDownloader) GetLatestPreReleaseVersion(org, repo string) (string, error) {
+	releases, err := d.GetAllReleasesForRepo(org, repo)
+	if err != nil {
+		return "", err
+	}
+	if len(releases) == 0 {
+		return "", fmt.Errorf("no releases found for org %s repo %s", org, repo)
+	}
+	return releases[0], nil
+}
+
+func (d cachingDownloader) GetAllReleasesForRepo(org, repo string) ([]string, error) {
+	bs, err := d.getCached(fmt.Sprintf("releases:%s/%s", org, repo), func() ([]byte, error) {
+		releases, err := d.Downloader.GetAllReleasesForRepo(org, repo)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(releases)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var releases []string
+	if err := json.Unmarshal(bs, &releases); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling cached releases of %s/%s: %w", org, repo, err)
+	}
+	return releases, nil
+}
+
+// getCached returns the result of [get], keeping it in the cache under [key]. The
+// cached result is returned instead on offline mode, or if [get] fails on
+// anything else than a missing download
+func (d cachingDownloader) getCached(key string, get func() ([]byte, error)) ([]byte, error) {
+	hash := sha256.Sum256([]byte(key))
+	cachePath := filepath.Join(d.cacheDir, hex.EncodeToString(hash[:]))
+	if utils.IsOffline() {
+		bs, err := os.ReadFile(cachePath)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s was not obtained before", utils.ErrOffline, key)
+		}
+		return bs, nil
+	}
+	bs, err := get()
+	if err != nil {
+		if errors.Is(err, ErrDownloadNotFound) {
+			return nil, err
+		}
+		cached, cacheErr := os.ReadFile(cachePath)
+		if cacheErr != nil {
+			return nil, err
+		}
+		d.log.Warn("using previously obtained data", zap.String("key", key), zap.Error(err))
+		return cached, nil
+	}
+	if err := os.MkdirAll(d.cacheDir, constants.DefaultPerms755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(cachePath, bs, constants.WriteReadReadPerms); err != nil {
+		return nil, err
+	}
+	return bs, nil
+}
+
+// isMetadataURL returns true if [url] points to a small metadata file, like the
+// compatibility and checksums ones, rather than to a release archive
+func isMetadataURL(url string) bool {
+	return strings.HasSuffix(url, ".json") || strings.HasSuffix(url, ".txt")
+}
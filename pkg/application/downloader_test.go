@@ -0,0 +1,158 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package application
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metalgo/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testCompatibilityURL = "https://raw.githubusercontent.com/MetalBlockchain/metalgo/master/version/compatibility.json"
+	testArchiveURL       = "https://github.com/MetalBlockchain/metalgo/releases/download/v1.11.3/metalgo-linux-amd64-v1.11.3.tar.gz"
+	testReleaseURL       = "https://api.github.com/repos/MetalBlockchain/metalgo/releases/latest"
+)
+
+var errTestNetwork = errors.New("network is unreachable")
+
+// testDownloader serves fixed contents, or fails as if the network is unreachable
+type testDownloader struct {
+	unreachable bool
+	calls       int
+}
+
+func (d *testDownloader) Download(url string) ([]byte, error) {
+	d.calls++
+	if d.unreachable {
+		return nil, errTestNetwork
+	}
+	return []byte("contents of " + url), nil
+}
+
+func (d *testDownloader) GetLatestReleaseVersion(string) (string, error) {
+	d.calls++
+	if d.unreachable {
+		return "", errTestNetwork
+	}
+	return "v1.11.3", nil
+}
+
+func (d *testDownloader) GetLatestPreReleaseVersion(string, string) (string, error) {
+	d.calls++
+	return "", errTestNetwork
+}
+
+func (d *testDownloader) GetAllReleasesForRepo(string, string) ([]string, error) {
+	d.calls++
+	if d.unreachable {
+		return nil, errTestNetwork
+	}
+	return []string{"v1.11.4-rc.0", "v1.11.3"}, nil
+}
+
+func TestCachingDownloader(t *testing.T) {
+	require := require.New(t)
+	defer utils.SetOffline(false)
+
+	inner := &testDownloader{}
+	downloader := NewCachingDownloader(inner, t.TempDir(), logging.NoLog{})
+
+	// nothing obtained yet
+	utils.SetOffline(true)
+	_, err := downloader.GetLatestReleaseVersion(testReleaseURL)
+	require.ErrorIs(err, utils.ErrOffline)
+	_, err = downloader.Download(testArchiveURL)
+	require.ErrorIs(err, utils.ErrOffline)
+	require.Zero(inner.calls)
+
+	utils.SetOffline(false)
+	version, err := downloader.GetLatestReleaseVersion(testReleaseURL)
+	require.NoError(err)
+	require.Equal("v1.11.3", version)
+	compatibility, err := downloader.Download(testCompatibilityURL)
+	require.NoError(err)
+	preRelease, err := downloader.GetLatestPreReleaseVersion("MetalBlockchain", "metalgo")
+	require.NoError(err)
+	require.Equal("v1.11.4-rc.0", preRelease)
+	_, err = downloader.Download(testArchiveURL)
+	require.NoError(err)
+	require.Equal(4, inner.calls)
+
+	// the network is not reachable, previously obtained data is used
+	inner.unreachable = true
+	version, err = downloader.GetLatestReleaseVersion(testReleaseURL)
+	require.NoError(err)
+	require.Equal("v1.11.3", version)
+	_, err = downloader.Download(testArchiveURL)
+	require.ErrorIs(err, errTestNetwork)
+	require.Equal(6, inner.calls)
+
+	// offline, previously obtained data is used without network calls
+	utils.SetOffline(true)
+	bs, err := downloader.Download(testCompatibilityURL)
+	require.NoError(err)
+	require.Equal(compatibility, bs)
+	releases, err := downloader.GetAllReleasesForRepo("MetalBlockchain", "metalgo")
+	require.NoError(err)
+	require.Equal([]string{"v1.11.4-rc.0", "v1.11.3"}, releases)
+	_, err = downloader.Download(testArchiveURL)
+	require.ErrorIs(err, utils.ErrOffline)
+	require.Equal(6, inner.calls)
+}
+
+func TestResumingDownloader(t *testing.T) {
+	require := require.New(t)
+	contents := []byte("contents of the release archive")
+	etag := `"v1"`
+	ranges := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		w.Header().Set("ETag", etag)
+		http.ServeContent(w, r, "archive", time.Time{}, bytes.NewReader(contents))
+	}))
+	defer server.Close()
+	partialDir := t.TempDir()
+	d := NewResumingDownloader(partialDir).(*downloader)
+	partialPath := d.getPartialDownloadPath(server.URL)
+	plant := func(partial []byte, validator string) {
+		require.NoError(os.WriteFile(partialPath, partial, constants.WriteReadUserOnlyPerms))
+		if validator != "" {
+			require.NoError(os.WriteFile(partialPath+partialValidatorSuffix, []byte(validator), constants.WriteReadUserOnlyPerms))
+		}
+	}
+
+	// a partial download with the file validator is resumed
+	plant(contents[:10], etag)
+	bs, err := d.Download(server.URL)
+	require.NoError(err)
+	require.Equal(contents, bs)
+	require.NoFileExists(partialPath)
+	require.NoFileExists(partialPath + partialValidatorSuffix)
+	require.Equal([]string{"bytes=10-"}, ranges)
+
+	// partial downloads without a validator, or of a changed file, are discarded
+	for _, validator := range []string{"", `"v0"`} {
+		plant([]byte("planted"), validator)
+		bs, err = d.Download(server.URL)
+		require.NoError(err)
+		require.Equal(contents, bs)
+	}
+
+	// a partial download not matching the file size is started again
+	plant(append(contents, []byte("planted")...), etag)
+	bs, err = d.Download(server.URL)
+	require.NoError(err)
+	require.Equal(contents, bs)
+	require.NoFileExists(partialPath)
+	require.Equal([]string{"bytes=10-", "", "bytes=7-", "bytes=38-", ""}, ranges)
+}
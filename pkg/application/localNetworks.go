@@ -0,0 +1,139 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+)
+
+// max number of named local networks, bounded by the backend ports reserved for them
+const MaxLocalNetworks = 20
+
+var (
+	localNetworkNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+	ErrLocalNetworkNotFound = errors.New("local network not found")
+)
+
+// localNetworks maps the name of each named local network to its index,
+// which identifies its backend ports. Index 0 belongs to the default network
+type localNetworks map[string]int
+
+// SetLocalNetwork selects the local network the network state (runs, snapshots,
+// plugins) is read from and written to, and its [index], as given by
+// GetLocalNetworkIndex. An empty name selects the default network
+func (app *Avalanche) SetLocalNetwork(name string, index int) {
+	app.localNetworkName = name
+	app.localNetworkIndex = index
+}
+
+func (app *Avalanche) GetLocalNetworkName() string {
+	return app.localNetworkName
+}
+
+// GetSelectedLocalNetworkIndex returns the index of the selected local network,
+// 0 for the default one
+func (app *Avalanche) GetSelectedLocalNetworkIndex() int {
+	return app.localNetworkIndex
+}
+
+func (app *Avalanche) GetLocalNetworkDir(name string) string {
+	return filepath.Join(app.baseDir, constants.LocalNetworksDir, name)
+}
+
+func (app *Avalanche) getLocalNetworkBaseDir() string {
+	if app.localNetworkName == "" {
+		return app.baseDir
+	}
+	return app.GetLocalNetworkDir(app.localNetworkName)
+}
+
+func (app *Avalanche) getLocalNetworksPath() string {
+	return filepath.Join(app.baseDir, constants.LocalNetworksFileName)
+}
+
+// LoadLocalNetworks returns the named local networks, by name
+func (app *Avalanche) LoadLocalNetworks() (map[string]int, error) {
+	networks := localNetworks{}
+	jsonBytes, err := os.ReadFile(app.getLocalNetworksPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return networks, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(jsonBytes, &networks); err != nil {
+		return nil, fmt.Errorf("failed parsing local networks file %s: %w", app.getLocalNetworksPath(), err)
+	}
+	return networks, nil
+}
+
+func (app *Avalanche) writeLocalNetworks(networks localNetworks) error {
+	jsonBytes, err := json.MarshalIndent(networks, "", "    ")
+	if err != nil {
+		return err
+	}
+	return app.writeFileLocked(app.getLocalNetworksPath(), jsonBytes, constants.WriteReadReadPerms)
+}
+
+// GetLocalNetworkIndex returns the index of the named local network [name]. If
+// [register] is true, an unknown network is registered with the lowest free index
+func (app *Avalanche) GetLocalNetworkIndex(name string, register bool) (int, error) {
+	if !localNetworkNameRegex.MatchString(name) {
+		return 0, fmt.Errorf("invalid local network name %q: only letters, digits, '_' and '-' are allowed", name)
+	}
+	networks, err := app.LoadLocalNetworks()
+	if err != nil {
+		return 0, err
+	}
+	if index, ok := networks[name]; ok {
+		return index, nil
+	}
+	if !register {
+		return 0, fmt.Errorf("%w: %s", ErrLocalNetworkNotFound, name)
+	}
+	// another process may be registering a network too
+	release, err := app.Lock()
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+	networks, err = app.LoadLocalNetworks()
+	if err != nil {
+		return 0, err
+	}
+	if index, ok := networks[name]; ok {
+		return index, nil
+	}
+	used := map[int]bool{}
+	for _, index := range networks {
+		used[index] = true
+	}
+	for index := 1; index <= MaxLocalNetworks; index++ {
+		if !used[index] {
+			networks[name] = index
+			return index, app.writeLocalNetworks(networks)
+		}
+	}
+	return 0, fmt.Errorf("can't create more than %d named local networks. Remove some with 'metal network clean --name'", MaxLocalNetworks)
+}
+
+// RemoveLocalNetwork unregisters the named local network [name] and removes its state
+func (app *Avalanche) RemoveLocalNetwork(name string) error {
+	networks, err := app.LoadLocalNetworks()
+	if err != nil {
+		return err
+	}
+	delete(networks, name)
+	if err := app.writeLocalNetworks(networks); err != nil {
+		return err
+	}
+	return os.RemoveAll(app.GetLocalNetworkDir(name))
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/filelock"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"go.uber.org/zap"
+)
+
+const appLockTimeout = 2 * time.Minute
+
+func (app *Avalanche) GetLockPath() string {
+	return filepath.Join(app.baseDir, constants.AppLockFileName)
+}
+
+// Lock takes the app directory lock, so that other CLI processes don't write
+// sidecars, genesis or network state at the same time. The lock is reentrant
+// within the process. The returned function releases it
+func (app *Avalanche) Lock() (func(), error) {
+	app.lockMu.Lock()
+	defer app.lockMu.Unlock()
+	if app.lockDepth == 0 {
+		lock, err := filelock.Acquire(app.GetLockPath(), app.command, appLockTimeout, func(owner filelock.Owner) {
+			if ux.Logger != nil {
+				ux.Logger.PrintToUser("Waiting for the app directory lock, held by %s...", owner)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+		app.lock = lock
+	}
+	app.lockDepth++
+	return app.unlock, nil
+}
+
+func (app *Avalanche) unlock() {
+	app.lockMu.Lock()
+	defer app.lockMu.Unlock()
+	app.lockDepth--
+	if app.lockDepth > 0 {
+		return
+	}
+	if err := app.lock.Release(); err != nil && app.Log != nil {
+		app.Log.Warn("failed releasing app directory lock", zap.Error(err))
+	}
+	app.lock = nil
+}
+
+// writeFileLocked writes [bytes] to [path] holding the app directory lock. The
+// file is replaced atomically, so readers never see a partial write
+func (app *Avalanche) writeFileLocked(path string, bytes []byte, perm os.FileMode) error {
+	release, err := app.Lock()
+	if err != nil {
+		return err
+	}
+	defer release()
+	if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.Write(bytes); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpFile.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), path)
+}
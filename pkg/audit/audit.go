@@ -0,0 +1,212 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+)
+
+const redacted = "REDACTED"
+
+// stateChangingCommands are the command paths, without the root command, that
+// are recorded. A path also matches its subcommands
+var stateChangingCommands = []string{
+	"subnet apply",
+	"subnet create",
+	"subnet delete",
+	"subnet deploy",
+	"subnet addValidator",
+	"subnet removeValidator",
+	"subnet renewValidator",
+	"subnet rebalance",
+	"subnet addPermissionlessValidator",
+	"subnet addPermissionlessDelegator",
+	"subnet changeOwner",
+	"subnet elastic",
+	"subnet join",
+	"subnet import",
+	"subnet publish",
+	"subnet configure",
+	"subnet config set",
+	"subnet config unset",
+	"subnet upgrade apply",
+	"subnet upgrade import",
+	"subnet upgrade generate",
+	"subnet upgrade vm",
+	"primary addValidator",
+	"key create",
+	"key delete",
+	"key import",
+	"key transfer",
+	"key fund",
+	"key addressbook add",
+	"key addressbook remove",
+	"key ledger use",
+	"network start",
+	"network stop",
+	"network restart",
+	"network clean",
+	"network snapshot save",
+	"network snapshot load",
+	"network snapshot delete",
+	"network snapshot prune",
+	"node create",
+	"node destroy",
+	"node resize",
+	"node sync",
+	"node update",
+	"node upgrade",
+	"node whitelist",
+	"node validate",
+	"node devnet",
+	"node deploy",
+	"node wiz",
+	"transaction sign",
+	"transaction commit",
+	"teleporter deploy",
+	"contract deploy",
+	"relayer deploy",
+	"relayer start",
+	"relayer stop",
+	"config environment add",
+	"config environment remove",
+}
+
+// secretFlagRegex matches the names of flags whose values are secrets
+var secretFlagRegex = regexp.MustCompile(`(?i)^--?[\w-]*(private-?key|password|secret|token|mnemonic|seed)[\w-]*$`)
+
+// privateKeyValueRegex matches values that look like private keys, either raw
+// hex encoded or in the PrivateKey- format
+var privateKeyValueRegex = regexp.MustCompile(`^((0x)?[0-9a-fA-F]{64}|PrivateKey-.*)$`)
+
+// Entry is one recorded CLI action
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`
+	Args    []string  `json:"args"`
+	User    string    `json:"user,omitempty"`
+	TxIDs   []string  `json:"txIDs,omitempty"`
+	Success bool      `json:"success"`
+	Error   string    `json:"error,omitempty"`
+}
+
+var (
+	txIDsLock sync.Mutex
+	txIDs     []string
+)
+
+// RecordTx adds [txID] to the txs issued by the running command
+func RecordTx(txID fmt.Stringer) {
+	txIDsLock.Lock()
+	defer txIDsLock.Unlock()
+	txIDs = append(txIDs, txID.String())
+}
+
+// IssuedTxs returns the IDs of the txs issued by the running command
+func IssuedTxs() []string {
+	txIDsLock.Lock()
+	defer txIDsLock.Unlock()
+	return append([]string{}, txIDs...)
+}
+
+// IsStateChanging tells if the command with path [commandPath] (eg "metal subnet
+// deploy") changes state, and is recorded
+func IsStateChanging(commandPath string) bool {
+	fields := strings.Fields(commandPath)
+	if len(fields) < 2 {
+		return false
+	}
+	path := strings.Join(fields[1:], " ")
+	for _, c := range stateChangingCommands {
+		if path == c || strings.HasPrefix(path, c+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactArgs returns [args] with the values of secret flags, values that look
+// like private keys whatever their position, and secret fields anywhere, replaced
+func RedactArgs(args []string) []string {
+	redactedArgs := make([]string, 0, len(args))
+	redactNext := false
+	for _, arg := range args {
+		switch {
+		case redactNext, privateKeyValueRegex.MatchString(arg):
+			arg = redacted
+			redactNext = false
+		case strings.HasPrefix(arg, "-"):
+			name, value, hasValue := strings.Cut(arg, "=")
+			if hasValue && privateKeyValueRegex.MatchString(value) {
+				arg = name + "=" + redacted
+			} else if secretFlagRegex.MatchString(name) {
+				if hasValue {
+					arg = name + "=" + redacted
+				} else {
+					redactNext = true
+				}
+			}
+		}
+		redactedArgs = append(redactedArgs, utils.RedactSecrets(arg))
+	}
+	return redactedArgs
+}
+
+// Append adds [entry] to the log at [path]. The log is only ever appended to
+func Append(path string, entry Entry) error {
+	bs, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, constants.WriteReadUserOnlyPerms)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(bs, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read returns the entries of the log at [path], oldest first. A missing log
+// has no entries
+func Read(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	entries := []Entry{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		entry := Entry{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("malformed audit log entry at %s:%d: %w", path, line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,64 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsStateChanging(t *testing.T) {
+	require := require.New(t)
+	require.True(IsStateChanging("metal subnet deploy"))
+	require.True(IsStateChanging("metal network snapshot save"))
+	require.True(IsStateChanging("metal subnet config set"))
+	require.False(IsStateChanging("metal subnet config get"))
+	require.False(IsStateChanging("metal subnet describe"))
+	require.False(IsStateChanging("metal subnet deployments"))
+	require.False(IsStateChanging("metal"))
+}
+
+func TestRedactArgs(t *testing.T) {
+	require := require.New(t)
+	require.Equal(
+		[]string{"metal", "key", "create", "k", "--file", "k.pk", "--private-key", "REDACTED", "--mnemonic=REDACTED", "--key", "ewoq"},
+		RedactArgs([]string{"metal", "key", "create", "k", "--file", "k.pk", "--private-key", "PrivateKey-abc", "--mnemonic=a b c", "--key", "ewoq"}),
+	)
+	require.Equal(
+		[]string{"--github-token", "REDACTED", "--config", "c.json"},
+		RedactArgs([]string{"--github-token", "ghp_xxx", "--config", "c.json"}),
+	)
+	// private keys are redacted whatever flag or position they are given in
+	hexKey := "56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d8027"
+	require.Equal(
+		[]string{"metal", "key", "create", "REDACTED", "--from", "REDACTED", "--input=REDACTED", "REDACTED", "--blockchain-id", "2oYMBNV4eNHyqk2fjjV5nVQLDbtmNJzq5s3qs3Lo6ftnC6FByM"},
+		RedactArgs([]string{"metal", "key", "create", hexKey, "--from", "0x" + hexKey, "--input=" + hexKey, "PrivateKey-ewoqjP7PxY4yr3iLTpLisriqt94hdyDFNgchSxGGztUrTXtNN", "--blockchain-id", "2oYMBNV4eNHyqk2fjjV5nVQLDbtmNJzq5s3qs3Lo6ftnC6FByM"}),
+	)
+}
+
+func TestAppendRead(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	entries, err := Read(path)
+	require.NoError(err)
+	require.Empty(entries)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	first := Entry{Time: now, Command: "metal subnet deploy", Args: []string{"metal", "subnet", "deploy", "s"}, TxIDs: []string{"tx1", "tx2"}, Success: true}
+	second := Entry{Time: now.Add(time.Minute), Command: "metal key delete", Args: []string{"metal", "key", "delete", "k"}, Error: "key not found"}
+	require.NoError(Append(path, first))
+	require.NoError(Append(path, second))
+
+	entries, err = Read(path)
+	require.NoError(err)
+	require.Equal([]Entry{first, second}, entries)
+
+	info, err := os.Stat(path)
+	require.NoError(err)
+	require.Equal(os.FileMode(0o600), info.Mode().Perm())
+}
@@ -0,0 +1,33 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package binutils
+
+import (
+	"path/filepath"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+)
+
+// SetupAWMRelayer installs the given AWM relayer version if needed, and returns
+// the installed version together with the relayer binary path
+func SetupAWMRelayer(app *application.Avalanche, awmRelayerVersion string) (string, string, error) {
+	binDir := app.GetAWMRelayerBinDir()
+	subDir := filepath.Join(binDir, awmRelayerBinPrefix+awmRelayerVersion)
+
+	installer := NewInstaller()
+	downloader := NewAWMRelayerDownloader()
+	version, vmDir, err := InstallBinary(
+		app,
+		awmRelayerVersion,
+		binDir,
+		subDir,
+		awmRelayerBinPrefix,
+		constants.AvaLabsOrg,
+		constants.AWMRelayerRepoName,
+		downloader,
+		installer,
+	)
+	return version, filepath.Join(vmDir, constants.AWMRelayerBin), err
+}
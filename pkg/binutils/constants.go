@@ -6,12 +6,14 @@ import "time"
 
 const (
 	gRPCClientLogLevel = "error"
-	gRPCServerPort     = ":8097"
-	gRPCGatewayPort    = ":8098"
-	gRPCServerEndpoint = "localhost" + gRPCServerPort
+	gRPCServerPort     = 8097
+	gRPCGatewayPort    = 8098
 	gRPCDialTimeout    = 10 * time.Second
+	// each named local network uses its own backend ports, this far from the previous ones
+	gRPCLocalNetworkPortsStep = 10
 
 	avalanchegoBinPrefix = "metalgo-"
 	subnetEVMBinPrefix   = "subnet-evm-"
+	awmRelayerBinPrefix  = "awm-relayer-"
 	maxCopy              = 2147483648 // 2 GB
 )
@@ -0,0 +1,186 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package binutils
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	githubURLPrefix = "https://github.com/"
+	// goreleaser names it <project>_<version>_checksums.txt
+	checksumsFileName = "checksums.txt"
+)
+
+var (
+	ErrChecksumMismatch     = errors.New("checksum mismatch")
+	ErrChecksumNotPublished = errors.New("release checksum not published")
+)
+
+// downloadArchive returns the release archive at [url]. Archives are kept in a cache
+// addressed by their SHA256, shared by all the installs, so they are downloaded only
+// once. Downloads use the configured mirror, if any, and are verified against the
+// checksums published with the release at the origin
+func downloadArchive(app *application.Avalanche, url string) ([]byte, error) {
+	index, err := loadDownloadCacheIndex(app)
+	if err != nil {
+		return nil, err
+	}
+	checksum, err := getPublishedChecksum(app, url)
+	if err != nil {
+		// the cached archive was verified when downloaded, so it is used if the
+		// checksums can't be obtained, eg on offline mode
+		if cachedChecksum := index[url]; cachedChecksum != "" && !errors.Is(err, ErrChecksumNotPublished) {
+			if archive, ok := readCachedArchive(app, cachedChecksum); ok {
+				app.Log.Debug("using cached archive", zap.String("url", url), zap.Error(err))
+				return archive, nil
+			}
+		}
+		return nil, err
+	}
+	if archive, ok := readCachedArchive(app, checksum); ok {
+		app.Log.Debug("using cached archive", zap.String("url", url), zap.String("sha256", checksum))
+		return archive, nil
+	}
+	downloadURL := getMirrorURL(app, url)
+	app.Log.Debug("starting download...", zap.String("download-url", downloadURL))
+	archive, err := app.Downloader.Download(downloadURL)
+	if err != nil {
+		return nil, err
+	}
+	if archiveChecksum := sha256Hex(archive); archiveChecksum != checksum {
+		return nil, fmt.Errorf("%w for %s: expected %s, got %s", ErrChecksumMismatch, url, checksum, archiveChecksum)
+	}
+	if err := os.MkdirAll(app.GetDownloadCacheDir(), constants.DefaultPerms755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(app.GetDownloadCacheDir(), checksum), archive, constants.WriteReadReadPerms); err != nil {
+		return nil, err
+	}
+	index[url] = checksum
+	return archive, saveDownloadCacheIndex(app, index)
+}
+
+// getMirrorURL returns the URL at the configured mirror for the github [url]
+func getMirrorURL(app *application.Avalanche, url string) string {
+	mirror := ""
+	if app.Conf != nil {
+		mirror = app.Conf.GetConfigStringValue(constants.ConfigDownloadMirrorKey)
+	}
+	if mirror == "" || !strings.HasPrefix(url, githubURLPrefix) {
+		return url
+	}
+	return strings.TrimSuffix(mirror, "/") + "/" + strings.TrimPrefix(url, githubURLPrefix)
+}
+
+// getPublishedChecksum returns the SHA256 of the archive at [url] listed in the
+// checksums file of its release. The checksums file is always obtained from the
+// origin, not from the mirror, so a mirror can't serve unverified archives
+func getPublishedChecksum(app *application.Avalanche, url string) (string, error) {
+	releaseURL, archiveName, found := cutLast(url, "/")
+	if !found {
+		return "", fmt.Errorf("%w: %s is not a release archive URL", ErrChecksumNotPublished, url)
+	}
+	_, tag, _ := cutLast(releaseURL, "/")
+	checksumsURL := releaseURL + "/" + getChecksumsFileName(tag, archiveName)
+	checksums, err := app.Downloader.Download(checksumsURL)
+	if errors.Is(err, application.ErrDownloadNotFound) {
+		return "", fmt.Errorf("%w: %s not found", ErrChecksumNotPublished, checksumsURL)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to download release checksums: %w", err)
+	}
+	checksum := parseChecksum(checksums, archiveName)
+	if checksum == "" {
+		return "", fmt.Errorf("%w: %s is not listed in %s", ErrChecksumNotPublished, archiveName, checksumsURL)
+	}
+	return checksum, nil
+}
+
+// getChecksumsFileName returns the name of the checksums file of release [tag]
+// listing [archiveName]. Archives named by goreleaser as <project>_<version>_<os>_<arch>
+// are listed in <project>_<version>_checksums.txt, the other ones in checksums.txt
+func getChecksumsFileName(tag string, archiveName string) string {
+	marker := "_" + strings.TrimPrefix(tag, "v") + "_"
+	if i := strings.Index(archiveName, marker); i > 0 {
+		return archiveName[:i+len(marker)] + checksumsFileName
+	}
+	return checksumsFileName
+}
+
+// parseChecksum returns the SHA256 of [fileName] in [checksums], written with the
+// "<sha256>  <file name>" format of sha256sum, or "" if not found
+func parseChecksum(checksums []byte, fileName string) string {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != fileName {
+			continue
+		}
+		if checksum, err := hex.DecodeString(fields[0]); err == nil && len(checksum) == sha256.Size {
+			return strings.ToLower(fields[0])
+		}
+	}
+	return ""
+}
+
+func readCachedArchive(app *application.Avalanche, checksum string) ([]byte, bool) {
+	archive, err := os.ReadFile(filepath.Join(app.GetDownloadCacheDir(), checksum))
+	if err != nil || sha256Hex(archive) != checksum {
+		return nil, false
+	}
+	return archive, true
+}
+
+// loadDownloadCacheIndex returns the checksums of the cached archives by URL
+func loadDownloadCacheIndex(app *application.Avalanche) (map[string]string, error) {
+	index := map[string]string{}
+	indexPath := filepath.Join(app.GetDownloadCacheDir(), constants.DownloadCacheIndexFilename)
+	if !utils.FileExists(indexPath) {
+		return index, nil
+	}
+	bs, err := os.ReadFile(indexPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(bs, &index); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling download cache index %s: %w", indexPath, err)
+	}
+	return index, nil
+}
+
+func saveDownloadCacheIndex(app *application.Avalanche, index map[string]string) error {
+	bs, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	indexPath := filepath.Join(app.GetDownloadCacheDir(), constants.DownloadCacheIndexFilename)
+	return os.WriteFile(indexPath, bs, constants.WriteReadReadPerms)
+}
+
+func sha256Hex(bs []byte) string {
+	hash := sha256.Sum256(bs)
+	return hex.EncodeToString(hash[:])
+}
+
+// cutLast slices [s] around the last instance of [sep]
+func cutLast(s, sep string) (string, string, bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
@@ -25,11 +25,13 @@ type GithubDownloader interface {
 type (
 	subnetEVMDownloader   struct{}
 	avalancheGoDownloader struct{}
+	awmRelayerDownloader  struct{}
 )
 
 var (
 	_ GithubDownloader = (*subnetEVMDownloader)(nil)
 	_ GithubDownloader = (*avalancheGoDownloader)(nil)
+	_ GithubDownloader = (*awmRelayerDownloader)(nil)
 )
 
 func GetGithubLatestReleaseURL(org, repo string) string {
@@ -122,3 +124,26 @@ func (subnetEVMDownloader) GetDownloadURL(version string, installer Installer) (
 
 	return subnetEVMURL, ext, nil
 }
+
+func NewAWMRelayerDownloader() GithubDownloader {
+	return &awmRelayerDownloader{}
+}
+
+func (awmRelayerDownloader) GetDownloadURL(version string, installer Installer) (string, string, error) {
+	goarch, goos := installer.GetArch()
+	if goos != linux && goos != darwin {
+		return "", "", fmt.Errorf("OS not supported: %s", goos)
+	}
+	// as with subnet-evm, the release file names omit the v of the version
+	awmRelayerURL := fmt.Sprintf(
+		"https://github.com/%s/%s/releases/download/%s/%s_%s_%s_%s.tar.gz",
+		constants.AvaLabsOrg,
+		constants.AWMRelayerRepoName,
+		version,
+		constants.AWMRelayerRepoName,
+		version[1:],
+		goos,
+		goarch,
+	)
+	return awmRelayerURL, tarExtension, nil
+}
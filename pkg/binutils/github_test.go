@@ -119,3 +119,45 @@ func TestGetDownloadURL_SubnetEVM(t *testing.T) {
 		require.Equal(tt.expectedErr, err)
 	}
 }
+
+func TestGetDownloadURL_AWMRelayer(t *testing.T) {
+	tests := []urlTest{
+		{
+			version:     "v1.1.0",
+			goarch:      "amd64",
+			goos:        "linux",
+			expectedURL: "https://github.com/MetalBlockchain/awm-relayer/releases/download/v1.1.0/awm-relayer_1.1.0_linux_amd64.tar.gz",
+			expectedExt: tarExtension,
+			expectedErr: nil,
+		},
+		{
+			version:     "v1.1.0",
+			goarch:      "arm64",
+			goos:        "darwin",
+			expectedURL: "https://github.com/MetalBlockchain/awm-relayer/releases/download/v1.1.0/awm-relayer_1.1.0_darwin_arm64.tar.gz",
+			expectedExt: tarExtension,
+			expectedErr: nil,
+		},
+		{
+			version:     "v1.1.0",
+			goarch:      "amd64",
+			goos:        "windows",
+			expectedURL: "",
+			expectedExt: "",
+			expectedErr: errors.New("OS not supported: windows"),
+		},
+	}
+
+	for _, tt := range tests {
+		require := require.New(t)
+		mockInstaller := &mocks.Installer{}
+		mockInstaller.On("GetArch").Return(tt.goarch, tt.goos)
+
+		downloader := NewAWMRelayerDownloader()
+
+		url, ext, err := downloader.GetDownloadURL(tt.version, mockInstaller)
+		require.Equal(tt.expectedURL, url)
+		require.Equal(tt.expectedExt, ext)
+		require.Equal(tt.expectedErr, err)
+	}
+}
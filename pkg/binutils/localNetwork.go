@@ -0,0 +1,43 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package binutils
+
+import (
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+)
+
+// BackendLocalNetworkFlag is given to the backend process to select the local network it serves
+const BackendLocalNetworkFlag = "local-network-name"
+
+// index of the selected local network, 0 for the default one
+var localNetworkIndex int
+
+// SelectLocalNetwork makes the CLI use the backend and state of the named local
+// network [name], or of the default one if [name] is empty. Unless [create] is
+// true, [name] must have already been started once.
+// The subnets deployed to a named local network are recorded in the sidecars apart
+// from the default network ones, and its nodes use their own ports, see
+// subnet.GetLocalNetworkSettings
+func SelectLocalNetwork(app *application.Avalanche, name string, create bool) error {
+	index := 0
+	if name != "" {
+		var err error
+		index, err = app.GetLocalNetworkIndex(name, create)
+		if err != nil {
+			return err
+		}
+	}
+	app.SetLocalNetwork(name, index)
+	localNetworkIndex = index
+	models.SetLocalNetwork(name, index)
+	return nil
+}
+
+func getGRPCServerPort() int {
+	return gRPCServerPort + localNetworkIndex*gRPCLocalNetworkPortsStep
+}
+
+func getGRPCGatewayPort() int {
+	return gRPCGatewayPort + localNetworkIndex*gRPCLocalNetworkPortsStep
+}
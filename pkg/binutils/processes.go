@@ -89,7 +89,7 @@ func NewGRPCClient(opts ...GRPCClientOpOption) (client.Client, error) {
 		return nil, err
 	}
 	client, err := client.New(client.Config{
-		Endpoint:    gRPCServerEndpoint,
+		Endpoint:    fmt.Sprintf("localhost:%d", getGRPCServerPort()),
 		DialTimeout: op.dialTimeout,
 	}, log)
 	if errors.Is(err, context.DeadlineExceeded) {
@@ -126,8 +126,8 @@ func NewGRPCServer(snapshotsDir string) (server.Server, error) {
 		return nil, err
 	}
 	return server.New(server.Config{
-		Port:                gRPCServerPort,
-		GwPort:              gRPCGatewayPort,
+		Port:                fmt.Sprintf(":%d", getGRPCServerPort()),
+		GwPort:              fmt.Sprintf(":%d", getGRPCGatewayPort()),
 		DialTimeout:         gRPCDialTimeout,
 		SnapshotsDir:        snapshotsDir,
 		RedirectNodesOutput: false,
@@ -204,6 +204,9 @@ func StartServerProcess(app *application.Avalanche) error {
 	thisBin := reexec.Self()
 
 	args := []string{constants.BackendCmd}
+	if name := app.GetLocalNetworkName(); name != "" {
+		args = append(args, "--"+BackendLocalNetworkFlag, name)
+	}
 	cmd := exec.Command(thisBin, args...)
 
 	outputDirPrefix := path.Join(app.GetRunDir(), "server")
@@ -236,6 +239,11 @@ func StartServerProcess(app *application.Avalanche) error {
 		return err
 	}
 
+	release, err := app.Lock()
+	if err != nil {
+		return err
+	}
+	defer release()
 	if err := os.WriteFile(app.GetRunFile(), rfBytes, perms.ReadWrite); err != nil {
 		app.Log.Warn("could not write gRPC process info to file", zap.Error(err))
 	}
@@ -29,13 +29,12 @@ func installBinaryWithVersion(
 		return "", fmt.Errorf("unable to determine binary install URL: %w", err)
 	}
 
-	app.Log.Debug("starting download...", zap.String("download-url", installURL))
-	archive, err := app.Downloader.Download(installURL)
+	archive, err := downloadArchive(app, installURL)
 	if err != nil {
 		return "", fmt.Errorf("unable to download binary: %w", err)
 	}
 
-	app.Log.Debug("download successful. installing archive...")
+	app.Log.Debug("download successful. installing archive...", zap.Int("size", len(archive)), zap.String("install-dir", binDir))
 	if err := InstallArchive(ext, archive, binDir); err != nil {
 		return "", err
 	}
@@ -74,7 +73,14 @@ func InstallBinary(
 			repo,
 		))
 		if err != nil {
-			return "", "", err
+			// fall back to the latest installed version, so the network is not needed
+			installedVersion := getLatestInstalledVersion(baseBinDir, binPrefix)
+			if installedVersion == "" {
+				return "", "", err
+			}
+			app.Log.Warn("failed to get latest release version", zap.String("repo", repo), zap.Error(err))
+			ux.Logger.PrintToUser("Unable to check for the latest %s release, using the installed %s", repo, installedVersion)
+			version = installedVersion
 		}
 	} else if !semver.IsValid(version) {
 		return "", "", fmt.Errorf(
@@ -98,3 +104,20 @@ func InstallBinary(
 
 	return version, binDir, err
 }
+
+// getLatestInstalledVersion returns the highest version of the binaries installed
+// with [binPrefix] under [binDir], or "" if there is none
+func getLatestInstalledVersion(binDir string, binPrefix string) string {
+	matches, err := filepath.Glob(filepath.Join(binDir, binPrefix) + "v*")
+	if err != nil {
+		return ""
+	}
+	latestVersion := ""
+	for _, match := range matches {
+		version := strings.TrimPrefix(filepath.Base(match), binPrefix)
+		if semver.IsValid(version) && (latestVersion == "" || semver.Compare(version, latestVersion) > 0) {
+			latestVersion = version
+		}
+	}
+	return latestVersion
+}
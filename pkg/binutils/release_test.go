@@ -4,8 +4,11 @@
 package binutils
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/MetalBlockchain/metal-cli/internal/mocks"
@@ -14,7 +17,9 @@ import (
 	"github.com/MetalBlockchain/metal-cli/pkg/config"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
 	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
 	"github.com/MetalBlockchain/metalgo/utils/logging"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -51,9 +56,11 @@ func Test_installAvalancheGoWithVersion_Zip(t *testing.T) {
 	mockInstaller.On("GetArch").Return("amd64", "darwin")
 
 	githubDownloader := NewAvagoDownloader()
+	url, _, err := githubDownloader.GetDownloadURL(version1, mockInstaller)
+	require.NoError(err)
 
 	mockAppDownloader := mocks.Downloader{}
-	mockAppDownloader.On("Download", mock.Anything).Return(zipBytes, nil)
+	onDownloadRelease(&mockAppDownloader, url, zipBytes)
 	app.Downloader = &mockAppDownloader
 
 	expectedDir := filepath.Join(app.GetAvalanchegoBinDir(), avalanchegoBinPrefix+version1)
@@ -79,9 +86,11 @@ func Test_installAvalancheGoWithVersion_Tar(t *testing.T) {
 	mockInstaller.On("GetArch").Return("amd64", "linux")
 
 	downloader := NewAvagoDownloader()
+	url, _, err := downloader.GetDownloadURL(version1, mockInstaller)
+	require.NoError(err)
 
 	mockAppDownloader := mocks.Downloader{}
-	mockAppDownloader.On("Download", mock.Anything).Return(tarBytes, nil)
+	onDownloadRelease(&mockAppDownloader, url, tarBytes)
 	app.Downloader = &mockAppDownloader
 
 	expectedDir := filepath.Join(app.GetAvalanchegoBinDir(), avalanchegoBinPrefix+version1)
@@ -115,8 +124,8 @@ func Test_installAvalancheGoWithVersion_MultipleCoinstalls(t *testing.T) {
 	mockInstaller.On("DownloadRelease", url2).Return(zipBytes2, nil)
 
 	mockAppDownloader := mocks.Downloader{}
-	mockAppDownloader.On("Download", url1).Return(zipBytes1, nil)
-	mockAppDownloader.On("Download", url2).Return(zipBytes2, nil)
+	onDownloadRelease(&mockAppDownloader, url1, zipBytes1)
+	onDownloadRelease(&mockAppDownloader, url2, zipBytes2)
 	app.Downloader = &mockAppDownloader
 
 	expectedDir1 := filepath.Join(app.GetAvalanchegoBinDir(), avalanchegoBinPrefix+version1)
@@ -152,9 +161,11 @@ func Test_installSubnetEVMWithVersion(t *testing.T) {
 	mockInstaller.On("GetArch").Return("amd64", "darwin")
 
 	downloader := NewSubnetEVMDownloader()
+	url, _, err := downloader.GetDownloadURL(version1, mockInstaller)
+	require.NoError(err)
 
 	mockAppDownloader := mocks.Downloader{}
-	mockAppDownloader.On("Download", mock.Anything).Return(tarBytes, nil)
+	onDownloadRelease(&mockAppDownloader, url, tarBytes)
 	app.Downloader = &mockAppDownloader
 
 	expectedDir := filepath.Join(app.GetSubnetEVMBinDir(), subnetEVMBinPrefix+version1)
@@ -188,8 +199,8 @@ func Test_installSubnetEVMWithVersion_MultipleCoinstalls(t *testing.T) {
 	require.NoError(err)
 
 	mockAppDownloader := mocks.Downloader{}
-	mockAppDownloader.On("Download", url1).Return(tarBytes1, nil)
-	mockAppDownloader.On("Download", url2).Return(tarBytes2, nil)
+	onDownloadRelease(&mockAppDownloader, url1, tarBytes1)
+	onDownloadRelease(&mockAppDownloader, url2, tarBytes2)
 	app.Downloader = &mockAppDownloader
 
 	expectedDir1 := filepath.Join(app.GetSubnetEVMBinDir(), subnetEVMBinPrefix+version1)
@@ -217,3 +228,156 @@ func Test_installSubnetEVMWithVersion_MultipleCoinstalls(t *testing.T) {
 	require.NoError(err)
 	require.Equal(binary2, installedBin2)
 }
+
+func isChecksumsURL(url string) bool {
+	return strings.HasSuffix(url, checksumsFileName)
+}
+
+// onDownloadRelease makes [downloader] serve [archive] at [url], listed with its
+// SHA256 in the checksums file of its release
+func onDownloadRelease(downloader *mocks.Downloader, url string, archive []byte) {
+	releaseURL, archiveName, _ := cutLast(url, "/")
+	_, tag, _ := cutLast(releaseURL, "/")
+	checksums := fmt.Sprintf("%s  %s\n", sha256Hex(archive), archiveName)
+	downloader.On("Download", releaseURL+"/"+getChecksumsFileName(tag, archiveName)).Return([]byte(checksums), nil)
+	downloader.On("Download", url).Return(archive, nil)
+}
+
+func Test_downloadArchive(t *testing.T) {
+	require := testutils.SetupTest(t)
+	app := setupInstallDir(require)
+
+	releaseURL := "https://github.com/MetalBlockchain/subnet-evm/releases/download/" + version1
+	url := releaseURL + "/subnet-evm_1.17.1_linux_amd64.tar.gz"
+	checksums := []byte(fmt.Sprintf("%s  other.tar.gz\n%s  subnet-evm_1.17.1_linux_amd64.tar.gz\n", sha256Hex(binary2), sha256Hex(binary1)))
+
+	// checksum mismatch
+	mockAppDownloader := mocks.Downloader{}
+	mockAppDownloader.On("Download", releaseURL+"/subnet-evm_1.17.1_checksums.txt").Return(checksums, nil)
+	mockAppDownloader.On("Download", url).Return(binary2, nil).Once()
+	app.Downloader = &mockAppDownloader
+	_, err := downloadArchive(app, url)
+	require.ErrorIs(err, ErrChecksumMismatch)
+
+	// verified download
+	mockAppDownloader.On("Download", url).Return(binary1, nil).Once()
+	archive, err := downloadArchive(app, url)
+	require.NoError(err)
+	require.Equal(binary1, archive)
+
+	// cached, so the archive is not downloaded again
+	archive, err = downloadArchive(app, url)
+	require.NoError(err)
+	require.Equal(binary1, archive)
+	mockAppDownloader.AssertNumberOfCalls(t, "Download", 5)
+}
+
+func Test_getChecksumsFileName(t *testing.T) {
+	require.Equal(t, "subnet-evm_0.6.0_checksums.txt", getChecksumsFileName("v0.6.0", "subnet-evm_0.6.0_linux_amd64.tar.gz"))
+	require.Equal(t, "awm-relayer_1.3.0_checksums.txt", getChecksumsFileName("v1.3.0", "awm-relayer_1.3.0_darwin_arm64.tar.gz"))
+	require.Equal(t, checksumsFileName, getChecksumsFileName("v1.11.3", "metalgo-linux-amd64-v1.11.3.tar.gz"))
+}
+
+func Test_downloadArchive_ChecksumsError(t *testing.T) {
+	require := testutils.SetupTest(t)
+	app := setupInstallDir(require)
+	url := "https://github.com/MetalBlockchain/subnet-evm/releases/download/" + version1 + "/subnet-evm_1.17.1_linux_amd64.tar.gz"
+
+	// archives are not installed without verification
+	mockAppDownloader := mocks.Downloader{}
+	mockAppDownloader.On("Download", mock.MatchedBy(isChecksumsURL)).Return(nil, application.ErrDownloadNotFound).Once()
+	app.Downloader = &mockAppDownloader
+	_, err := downloadArchive(app, url)
+	require.ErrorIs(err, ErrChecksumNotPublished)
+	mockAppDownloader.On("Download", mock.MatchedBy(isChecksumsURL)).Return([]byte(sha256Hex(binary1)+"  other.tar.gz\n"), nil).Once()
+	_, err = downloadArchive(app, url)
+	require.ErrorIs(err, ErrChecksumNotPublished)
+
+	mockAppDownloader = mocks.Downloader{}
+	mockAppDownloader.On("Download", mock.MatchedBy(isChecksumsURL)).Return(nil, errors.New("unexpected http status code: 403"))
+	mockAppDownloader.On("Download", url).Return(binary1, nil)
+	app.Downloader = &mockAppDownloader
+	_, err = downloadArchive(app, url)
+	require.ErrorContains(err, "403")
+	mockAppDownloader.AssertNotCalled(t, "Download", url)
+}
+
+func Test_downloadArchive_Mirror(t *testing.T) {
+	require := testutils.SetupTest(t)
+	app := setupInstallDir(require)
+	viper.Set(constants.ConfigDownloadMirrorKey, "file:///srv/mirror")
+	defer viper.Set(constants.ConfigDownloadMirrorKey, "")
+
+	// archives come from the mirror, checksums from the origin
+	releaseURL := "https://github.com/MetalBlockchain/subnet-evm/releases/download/" + version1
+	url := releaseURL + "/subnet-evm_1.17.1_linux_amd64.tar.gz"
+	mockAppDownloader := mocks.Downloader{}
+	mockAppDownloader.On("Download", "file:///srv/mirror/MetalBlockchain/subnet-evm/releases/download/"+version1+"/subnet-evm_1.17.1_linux_amd64.tar.gz").Return(binary1, nil)
+	mockAppDownloader.On("Download", releaseURL+"/subnet-evm_1.17.1_checksums.txt").Return([]byte(sha256Hex(binary1)+"  subnet-evm_1.17.1_linux_amd64.tar.gz\n"), nil)
+	app.Downloader = &mockAppDownloader
+	archive, err := downloadArchive(app, url)
+	require.NoError(err)
+	require.Equal(binary1, archive)
+	mockAppDownloader.AssertNumberOfCalls(t, "Download", 2)
+	require.NoError(os.RemoveAll(app.GetBaseDir()))
+}
+
+func Test_getMirrorURL(t *testing.T) {
+	require := testutils.SetupTest(t)
+	app := setupInstallDir(require)
+	url := "https://github.com/MetalBlockchain/metalgo/releases/download/v1.11.3/metalgo-linux-amd64-v1.11.3.tar.gz"
+	require.Equal(url, getMirrorURL(app, url))
+
+	viper.Set(constants.ConfigDownloadMirrorKey, "file:///srv/mirror/")
+	defer viper.Set(constants.ConfigDownloadMirrorKey, "")
+	require.Equal("file:///srv/mirror/MetalBlockchain/metalgo/releases/download/v1.11.3/metalgo-linux-amd64-v1.11.3.tar.gz", getMirrorURL(app, url))
+	require.Equal("https://example.com/file", getMirrorURL(app, "https://example.com/file"))
+}
+
+func Test_InstallBinary_LatestFallback(t *testing.T) {
+	require := testutils.SetupTest(t)
+	app := setupInstallDir(require)
+	binDir := app.GetAvalanchegoBinDir()
+	require.Empty(getLatestInstalledVersion(binDir, avalanchegoBinPrefix))
+
+	mockAppDownloader := mocks.Downloader{}
+	mockAppDownloader.On("GetLatestReleaseVersion", mock.Anything).Return("", utils.ErrOffline)
+	app.Downloader = &mockAppDownloader
+
+	// nothing installed to fall back to
+	_, _, err := SetupAvalanchego(app, "latest")
+	require.ErrorIs(err, utils.ErrOffline)
+
+	for _, version := range []string{version1, version2, "v1.9.0", "latest"} {
+		require.NoError(os.MkdirAll(filepath.Join(binDir, avalanchegoBinPrefix+version), constants.DefaultPerms755))
+	}
+	require.Equal(version2, getLatestInstalledVersion(binDir, avalanchegoBinPrefix))
+
+	version, dir, err := SetupAvalanchego(app, "latest")
+	require.NoError(err)
+	require.Equal(version2, version)
+	require.Equal(filepath.Join(binDir, avalanchegoBinPrefix+version2), dir)
+	require.NoError(os.RemoveAll(app.GetBaseDir()))
+}
+
+func Test_downloadArchive_Offline(t *testing.T) {
+	require := testutils.SetupTest(t)
+	app := setupInstallDir(require)
+
+	url := "https://github.com/MetalBlockchain/subnet-evm/releases/download/" + version1 + "/subnet-evm_1.17.1_linux_amd64.tar.gz"
+	mockAppDownloader := mocks.Downloader{}
+	mockAppDownloader.On("Download", mock.Anything).Return(nil, utils.ErrOffline)
+	app.Downloader = &mockAppDownloader
+
+	_, err := downloadArchive(app, url)
+	require.ErrorIs(err, utils.ErrOffline)
+
+	// previously downloaded archives are used
+	require.NoError(os.MkdirAll(app.GetDownloadCacheDir(), constants.DefaultPerms755))
+	require.NoError(os.WriteFile(filepath.Join(app.GetDownloadCacheDir(), sha256Hex(binary1)), binary1, constants.WriteReadReadPerms))
+	require.NoError(saveDownloadCacheIndex(app, map[string]string{url: sha256Hex(binary1)}))
+	archive, err := downloadArchive(app, url)
+	require.NoError(err)
+	require.Equal(binary1, archive)
+	require.NoError(os.RemoveAll(app.GetBaseDir()))
+}
@@ -20,17 +20,16 @@ func UpgradeVM(app *application.Avalanche, vmID string, vmBinPath string) error
 
 // update the RPC version of the VM in the sidecar file
 func UpdateLocalSidecarRPC(app *application.Avalanche, sc models.Sidecar, rpcVersion int) error {
-	// find local network deployment info in sidecar
-	networkData, ok := sc.Networks[models.Local.String()]
-	if !ok {
-		return fmt.Errorf("failed to find local network in sidecar")
-	}
-
-	networkData.RPCVersion = rpcVersion
-
-	sc.Networks[models.Local.String()] = networkData
-
-	if err := app.UpdateSidecar(&sc); err != nil {
+	if _, err := app.EditSidecar(sc.Name, func(sc *models.Sidecar) error {
+		// find local network deployment info in sidecar
+		networkData, ok := sc.Networks[models.NewLocalNetwork().Name()]
+		if !ok {
+			return fmt.Errorf("failed to find local network in sidecar")
+		}
+		networkData.RPCVersion = rpcVersion
+		sc.Networks[models.NewLocalNetwork().Name()] = networkData
+		return nil
+	}); err != nil {
 		return fmt.Errorf("failed to update sidecar: %w", err)
 	}
 
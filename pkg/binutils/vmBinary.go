@@ -0,0 +1,102 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package binutils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"go.uber.org/zap"
+)
+
+// fixedInstaller is an Installer for a given platform, instead of the running one
+type fixedInstaller struct {
+	goarch string
+	goos   string
+}
+
+func (i fixedInstaller) GetArch() (string, string) {
+	return i.goarch, i.goos
+}
+
+// GetSubnetEVMRelease returns the URL of the Subnet-EVM [version] release archive
+// for [goos]/[goarch], and its SHA256. The archive is downloaded, and verified
+// against the published checksums, to compute it
+func GetSubnetEVMRelease(app *application.Avalanche, version string, goos string, goarch string) (string, string, error) {
+	url, _, err := NewSubnetEVMDownloader().GetDownloadURL(version, fixedInstaller{goarch: goarch, goos: goos})
+	if err != nil {
+		return "", "", err
+	}
+	archive, err := downloadArchive(app, url)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to download subnet-evm %s: %w", version, err)
+	}
+	return url, sha256Hex(archive), nil
+}
+
+// GetURLSHA256 downloads the file at [url], and returns its hex SHA256, as checked
+// by InstallVMBinaryFromURL
+func GetURLSHA256(app *application.Avalanche, url string) (string, error) {
+	downloadURL := getMirrorURL(app, url)
+	app.Log.Debug("starting download...", zap.String("download-url", downloadURL))
+	bs, err := app.Downloader.Download(downloadURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	return sha256Hex(bs), nil
+}
+
+// IsArchiveURL tells if [url] is a .tar.gz or .zip archive, as installed by
+// InstallVMBinaryFromURL
+func IsArchiveURL(url string) bool {
+	return strings.HasSuffix(url, "."+tarExtension) || strings.HasSuffix(url, "."+zipExtension)
+}
+
+// FileSHA256 returns the hex SHA256 of the file at [path]
+func FileSHA256(path string) (string, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return sha256Hex(bs), nil
+}
+
+// InstallVMBinaryFromURL downloads the VM binary at [url], checks that its SHA256 is
+// [checksum], and copies it to [vmPath]. If [url] is a .tar.gz or .zip archive, the
+// binary named [binName] is taken from it
+func InstallVMBinaryFromURL(app *application.Avalanche, url string, checksum string, binName string, vmPath string) error {
+	downloadURL := getMirrorURL(app, url)
+	app.Log.Debug("starting download...", zap.String("download-url", downloadURL))
+	bs, err := app.Downloader.Download(downloadURL)
+	if err != nil {
+		return fmt.Errorf("failed to download VM binary: %w", err)
+	}
+	if bsChecksum := sha256Hex(bs); bsChecksum != strings.ToLower(checksum) {
+		return fmt.Errorf("%w for %s: expected %s, got %s", ErrChecksumMismatch, url, checksum, bsChecksum)
+	}
+	tmpDir, err := os.MkdirTemp("", "vm-binary")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	binPath := filepath.Join(tmpDir, binName)
+	switch {
+	case strings.HasSuffix(url, "."+tarExtension):
+		err = InstallArchive(tarExtension, bs, tmpDir)
+	case strings.HasSuffix(url, "."+zipExtension):
+		err = InstallArchive(zipExtension, bs, tmpDir)
+	default:
+		err = os.WriteFile(binPath, bs, constants.DefaultPerms755)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(binPath); err != nil {
+		return fmt.Errorf("VM binary %s not found in %s: %w", binName, url, err)
+	}
+	return CopyFile(binPath, vmPath)
+}
@@ -0,0 +1,46 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package binutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MetalBlockchain/metal-cli/internal/mocks"
+	"github.com/MetalBlockchain/metal-cli/internal/testutils"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+)
+
+func TestInstallVMBinaryFromURL(t *testing.T) {
+	require := testutils.SetupTest(t)
+	app := setupInstallDir(require)
+	tarBytes := testutils.CreateDummySubnetEVMTar(require, binary1)
+	mockAppDownloader := mocks.Downloader{}
+	mockAppDownloader.On("Download", "https://example.com/subnet-evm.tar.gz").Return(tarBytes, nil)
+	mockAppDownloader.On("Download", "https://example.com/customvm").Return(binary2, nil)
+	app.Downloader = &mockAppDownloader
+
+	vmPath := filepath.Join(t.TempDir(), "vmid")
+	err := InstallVMBinaryFromURL(app, "https://example.com/subnet-evm.tar.gz", sha256Hex(tarBytes), constants.SubnetEVMBin, vmPath)
+	require.NoError(err)
+	installedBin, err := os.ReadFile(vmPath)
+	require.NoError(err)
+	require.Equal(binary1, installedBin)
+
+	err = InstallVMBinaryFromURL(app, "https://example.com/customvm", sha256Hex(binary2), constants.SubnetEVMBin, vmPath)
+	require.NoError(err)
+	installedBin, err = os.ReadFile(vmPath)
+	require.NoError(err)
+	require.Equal(binary2, installedBin)
+
+	err = InstallVMBinaryFromURL(app, "https://example.com/customvm", sha256Hex(binary1), constants.SubnetEVMBin, vmPath)
+	require.ErrorIs(err, ErrChecksumMismatch)
+
+	// the checksum given to joiners is the one of the published archive
+	checksum, err := GetURLSHA256(app, "https://example.com/subnet-evm.tar.gz")
+	require.NoError(err)
+	require.NoError(InstallVMBinaryFromURL(app, "https://example.com/subnet-evm.tar.gz", checksum, constants.SubnetEVMBin, vmPath))
+	require.True(IsArchiveURL("https://example.com/subnet-evm.tar.gz"))
+	require.False(IsArchiveURL("https://example.com/customvm"))
+}
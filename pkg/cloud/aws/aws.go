@@ -736,3 +736,69 @@ func (c *AwsCloud) ChangeInstanceType(instanceID, instanceType string) error {
 	}
 	return nil
 }
+
+// InstanceSpec describes the resources of an instance not kept in the node config
+type InstanceSpec struct {
+	InstanceType     string
+	VolumeSize       int32
+	VolumeType       string
+	IOPS             int32
+	Throughput       int32
+	EIPAllocationID  string
+	EIPAssociationID string
+	Tags             map[string]string
+}
+
+// GetInstanceSpec returns the type, root volume and elastic IP of the given instance
+func (c *AwsCloud) GetInstanceSpec(instanceID string) (InstanceSpec, error) {
+	spec := InstanceSpec{}
+	resp, err := c.ec2Client.DescribeInstances(c.ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: []string{instanceID},
+	})
+	if err != nil {
+		return spec, err
+	}
+	if len(resp.Reservations) == 0 || len(resp.Reservations[0].Instances) == 0 {
+		return spec, fmt.Errorf("instance with ID %s not found", instanceID)
+	}
+	instance := resp.Reservations[0].Instances[0]
+	spec.InstanceType = string(instance.InstanceType)
+	spec.Tags = map[string]string{}
+	for _, tag := range instance.Tags {
+		spec.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	volumeID, err := c.GetRootVolumeID(instanceID)
+	if err != nil {
+		return spec, err
+	}
+	volumeOutput, err := c.ec2Client.DescribeVolumes(c.ctx, &ec2.DescribeVolumesInput{
+		VolumeIds: []string{volumeID},
+	})
+	if err != nil {
+		return spec, err
+	}
+	if len(volumeOutput.Volumes) == 0 {
+		return spec, fmt.Errorf("volume with ID %s not found", volumeID)
+	}
+	volume := volumeOutput.Volumes[0]
+	spec.VolumeSize = aws.ToInt32(volume.Size)
+	spec.VolumeType = string(volume.VolumeType)
+	spec.IOPS = aws.ToInt32(volume.Iops)
+	spec.Throughput = aws.ToInt32(volume.Throughput)
+	addressOutput, err := c.ec2Client.DescribeAddresses(c.ctx, &ec2.DescribeAddressesInput{
+		Filters: []types.Filter{
+			{
+				Name:   aws.String("instance-id"),
+				Values: []string{instanceID},
+			},
+		},
+	})
+	if err != nil {
+		return spec, err
+	}
+	if len(addressOutput.Addresses) > 0 {
+		spec.EIPAllocationID = aws.ToString(addressOutput.Addresses[0].AllocationId)
+		spec.EIPAssociationID = aws.ToString(addressOutput.Addresses[0].AssociationId)
+	}
+	return spec, nil
+}
@@ -614,3 +614,70 @@ func (c *GcpCloud) IsInstanceTypeSupported(machineType string, zone string) (boo
 	})
 	return slices.Contains(supportedMachineTypes, machineType), nil
 }
+
+// InstanceSpec describes the resources of an instance not kept in the node config
+type InstanceSpec struct {
+	MachineType  string
+	Image        string
+	DiskSizeGb   int64
+	DiskType     string
+	SSHKeys      string
+	StaticIPName string
+	Labels       map[string]string
+}
+
+// GetInstanceSpec returns the machine type, boot disk and static IP of the given instance
+func (c *GcpCloud) GetInstanceSpec(instanceID string, zone string) (InstanceSpec, error) {
+	spec := InstanceSpec{}
+	instance, err := c.gcpClient.Instances.Get(c.projectID, zone, instanceID).Do()
+	if err != nil {
+		return spec, err
+	}
+	spec.MachineType = getNameFromURL(instance.MachineType)
+	spec.Labels = instance.Labels
+	if instance.Metadata != nil {
+		for _, item := range instance.Metadata.Items {
+			if item.Key == "ssh-keys" && item.Value != nil {
+				spec.SSHKeys = *item.Value
+			}
+		}
+	}
+	for _, attachedDisk := range instance.Disks {
+		if !attachedDisk.Boot {
+			continue
+		}
+		disk, err := c.gcpClient.Disks.Get(c.projectID, zone, extractDiskIDFromURL(attachedDisk.Source)).Do()
+		if err != nil {
+			return spec, err
+		}
+		spec.DiskSizeGb = disk.SizeGb
+		spec.DiskType = getNameFromURL(disk.Type)
+		spec.Image = strings.TrimPrefix(disk.SourceImage, "https://www.googleapis.com/compute/v1/")
+	}
+	for _, networkInterface := range instance.NetworkInterfaces {
+		for _, accessConfig := range networkInterface.AccessConfigs {
+			if accessConfig.NatIP == "" {
+				continue
+			}
+			addresses, err := c.gcpClient.Addresses.List(c.projectID, zoneToRegion(zone)).
+				Filter(fmt.Sprintf("address=%q", accessConfig.NatIP)).Do()
+			if err != nil {
+				return spec, err
+			}
+			if len(addresses.Items) > 0 {
+				spec.StaticIPName = addresses.Items[0].Name
+			}
+		}
+	}
+	return spec, nil
+}
+
+// GetNetworkFirewalls returns the firewall rules of the given network
+func (c *GcpCloud) GetNetworkFirewalls(networkName string) ([]*compute.Firewall, error) {
+	firewalls, err := c.gcpClient.Firewalls.List(c.projectID).
+		Filter(fmt.Sprintf("network=%q", fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/%s/global/networks/%s", c.projectID, networkName))).Do()
+	if err != nil {
+		return nil, err
+	}
+	return firewalls.Items, nil
+}
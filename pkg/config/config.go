@@ -72,6 +72,10 @@ func (*Config) GetConfigStringValue(key string) string {
 	return viper.GetString(key)
 }
 
+func (*Config) GetConfigIntValue(key string) int {
+	return viper.GetInt(key)
+}
+
 func (*Config) LoadNodeConfig() (string, error) {
 	globalConfigs := viper.GetStringMap(constants.ConfigNodeConfigKey)
 	if len(globalConfigs) == 0 {
@@ -10,6 +10,7 @@ const (
 	DefaultPerms755        = 0o755
 	WriteReadReadPerms     = 0o644
 	WriteReadUserOnlyPerms = 0o600
+	UserOnlyDirPerms       = 0o700
 
 	UbuntuVersionLTS = "20.04"
 
@@ -20,6 +21,8 @@ const (
 	AvalancheCliBinDir = "bin"
 	RunDir             = "runs"
 	ServicesDir        = "services"
+	LocalNetworksDir   = "local-networks"
+	DevnetsDir         = "devnets"
 
 	SuffixSeparator              = "_"
 	SidecarFileName              = "sidecar.json"
@@ -29,6 +32,8 @@ const (
 	GenesisSuffix                = SuffixSeparator + GenesisFileName
 	NodeFileName                 = "node.json"
 	NodePrometheusConfigFileName = "prometheus.yml"
+	MonitoringComposeFileName    = "docker-compose.yml"
+	ValidatorComposeFileName     = "docker-compose.yml"
 	NodeCloudConfigFileName      = "node_cloud_config.json"
 	AnsibleDir                   = "ansible"
 	AnsibleHostInventoryFileName = "hosts"
@@ -37,6 +42,11 @@ const (
 	GetAWSNodeIP                 = "get-aws-node-ip"
 	ClustersConfigFileName       = "cluster_config.json"
 	ClustersConfigVersion        = "1"
+	AddressBookFileName          = "addressbook.json"
+	EnvironmentsFileName         = "environments.json"
+	KeyPolicyFileName            = "key_policy.json"
+	AuditLogFileName             = "audit.jsonl"
+	LocalNetworksFileName        = "local_networks.json"
 	StakerCertFileName           = "staker.crt"
 	StakerKeyFileName            = "staker.key"
 	BLSKeyFileName               = "signer.key"
@@ -51,6 +61,8 @@ const (
 	ANRRequestTimeout      = 3 * time.Minute
 	APIRequestTimeout      = 30 * time.Second
 	APIRequestLargeTimeout = 2 * time.Minute
+	MetricsRequestTimeout  = 2 * time.Second
+	SMTPTimeout            = 10 * time.Second
 	FastGRPCDialTimeout    = 100 * time.Millisecond
 
 	SSHServerStartTimeout       = 1 * time.Minute
@@ -70,26 +82,46 @@ const (
 
 	TahoeAPIEndpoint   = "https://tahoe.metalblockchain.org"
 	MainnetAPIEndpoint = "https://api.metalblockchain.org"
+	TahoeFaucetURL     = "https://faucet.metalblockchain.org/api/sendToken"
 
 	// this depends on bootstrap snapshot
 	LocalAPIEndpoint = "http://127.0.0.1:9650"
 	LocalNetworkID   = 1337
+	// ports of the first local network node, the others use the following even/odd ports
+	LocalNetworkBaseHTTPPort    = 9650
+	LocalNetworkBaseStakingPort = 9651
+	// node ports of the named local networks are shifted by this step times their index
+	LocalNetworkPortsStep = 100
 
 	DevnetAPIEndpoint = ""
 	DevnetNetworkID   = 1338
 
+	// ports of the first node of the first docker devnet. Each docker devnet
+	// uses its own port range, two ports by node
+	DevnetBaseHTTPPort  = 19650
+	DevnetPortRangeSize = 100
+	// default number of nodes and lifetime of the devnets created with devnet create
+	DevnetNumNodes   = 3
+	DefaultDevnetTTL = 2 * time.Hour
+
 	DefaultTokenName = "Test Token"
 
 	DefaultTokenSymbol = "TEST"
 
 	HealthCheckInterval = 100 * time.Millisecond
 
+	// how often the startup progress of the local network nodes is polled
+	LocalNetworkStartupPollInterval = 500 * time.Millisecond
+
 	// it's unlikely anyone would want to name a snapshot `default`
 	// but let's add some more entropy
 	SnapshotsDirName = "snapshots"
 
 	DefaultSnapshotName = "default-1654102510"
 
+	// number of nodes of the default (non single node) local network snapshot
+	LocalNetworkNumNodes = 5
+
 	Cortina17Version = "v1.10.17"
 
 	BootstrapSnapshotRawBranch = "https://github.com/MetalBlockchain/metal-cli/raw/main/"
@@ -119,6 +151,7 @@ const (
 	BootstrapSnapshotSingleNodePreCortina17SHA256URL   = BootstrapSnapshotRawBranch + AssetsDir + "sha256sumSingleNode.PreCortina17.txt"
 
 	ExtraLocalNetworkDataFilename     = "extra-local-network-data.json"
+	VMAliasesFilename                 = "vm-aliases.json"
 	ExtraLocalNetworkDataSnapshotsDir = "extra-local-network-data"
 
 	CliInstallationURL         = "https://raw.githubusercontent.com/MetalBlockchain/metal-cli/main/scripts/install.sh"
@@ -135,9 +168,14 @@ const (
 
 	Disable = "disable"
 
+	// subnets with fewer validators are not checked for the weight share of a
+	// new validator, as any validator holds a big share on them
+	MinValidatorsForWeightShare = 3
+
 	TimeParseLayout             = "2006-01-02 15:04:05"
 	MinStakeWeight              = 1
 	DefaultStakeWeight          = 20
+	DefaultMaxWeightShare       = 33
 	AVAXSymbol                  = "METAL"
 	DefaultFujiStakeDuration    = "48h"
 	DefaultMainnetStakeDuration = "336h"
@@ -188,8 +226,34 @@ const (
 	ConfigAPMAdminAPIEndpointKey  = "admin-api-endpoint"
 	ConfigNodeConfigKey           = "node-config"
 	ConfigMetricsEnabledKey       = "MetricsEnabled"
+	ConfigMetricsEndpointKey      = "MetricsEndpoint"
 	ConfigAuthorizeCloudAccessKey = "AuthorizeCloudAccess"
 	ConfigSingleNodeEnabledKey    = "SingleNodeEnabled"
+	ConfigActiveSubnetKey         = "ActiveSubnet"
+	ConfigActiveNetworkKey        = "ActiveNetwork"
+	ConfigActiveClusterKey        = "ActiveCluster"
+	ConfigSnapshotKeepKey         = "SnapshotKeep"
+	ConfigSnapshotAutoKey         = "SnapshotAuto"
+	ConfigSnapshotNameTemplateKey = "SnapshotNameTemplate"
+	ConfigMaxWeightShareKey       = "MaxValidatorWeightShare"
+	ConfigMinStakeWeightKey       = "MinStakeWeight"
+	ConfigMaxStakeWeightKey       = "MaxStakeWeight"
+	ConfigFaucetURLKey            = "FaucetURL"
+	ConfigLocalHTTPPortKey        = "LocalNetworkHTTPPort"
+	ConfigLocalStakingPortKey     = "LocalNetworkStakingPort"
+	ConfigLocalHTTPHostKey        = "LocalNetworkHTTPHost"
+	ConfigDefaultKeyKey           = "DefaultKey"
+	ConfigLedgerIndexKey          = "LedgerIndex"
+	ConfigAvalancheGoVersionKey   = "AvalancheGoVersion"
+	ConfigNonInteractiveKey       = "NonInteractive"
+	ConfigTahoeAPIEndpointKey     = "TahoeAPIEndpoint"
+	ConfigMainnetAPIEndpointKey   = "MainnetAPIEndpoint"
+	ConfigDownloadMirrorKey       = "DownloadMirror"
+	ConfigNotifyWebhookURLKey     = "NotifyWebhookURL"
+	ConfigNotifySMTPServerKey     = "NotifySMTPServer"
+	ConfigNotifySMTPUserKey       = "NotifySMTPUser"
+	ConfigNotifyEmailFromKey      = "NotifyEmailFrom"
+	ConfigNotifyEmailToKey        = "NotifyEmailTo"
 	OldConfigFileName             = ".metal-cli.json"
 	OldMetricsConfigFileName      = ".metal-cli/config"
 	DefaultConfigFileName         = ".metal-cli/config.json"
@@ -221,6 +285,8 @@ const (
 	AvalanchegoMonitoringPort     = 9090
 	AvalanchegoMachineMetricsPort = 9100
 	MonitoringDir                 = "monitoring"
+	ExplorerDir                   = "explorer"
+	LocalExplorerPort             = 4000
 	LoadTestDir                   = "loadtest"
 	DashboardsDir                 = "dashboards"
 	NodeConfigJSONFile            = "node.json"
@@ -229,6 +295,10 @@ const (
 	SubnetEVMInstallDir           = "subnet-evm"
 	AWMRelayerInstallDir          = "awm-relayer"
 	TeleporterInstallDir          = "teleporter"
+	DownloadCacheDir              = "downloads"
+	DownloadCacheIndexFilename    = "index.json"
+	MetadataCacheDir              = "metadata"
+	PartialDownloadsDir           = "partial"
 	AWMRelayerBin                 = "awm-relayer"
 	AWMRelayerConfigFilename      = "awm-relayer-config.json"
 	AWMRelayerStorageDir          = "awm-relayer-storage"
@@ -242,6 +312,10 @@ const (
 
 	AWMRelayerMetricsPort = 9091
 
+	ServeDefaultHost   = "127.0.0.1"
+	ServeDefaultPort   = 9080
+	ServeTokenFilename = "serve-token"
+
 	SubnetEVMBin = "subnet-evm"
 
 	DefaultNodeRunURL = "http://127.0.0.1:9650"
@@ -253,6 +327,12 @@ const (
 
 	// #nosec G101
 	GithubAPITokenEnvVarName = "METAL_CLI_GITHUB_TOKEN"
+	// #nosec G101
+	RegistryTokenEnvVarName = "METAL_REGISTRY_TOKEN"
+	// #nosec G101
+	NotifySMTPPasswordEnvVarName = "METAL_NOTIFY_SMTP_PASSWORD"
+	// #nosec G101
+	ServeTokenEnvVarName = "METAL_TOKEN"
 
 	ReposDir                   = "repos"
 	SubnetDir                  = "subnets"
@@ -297,6 +377,7 @@ const (
 	MultiSig                     = "multi-sig"
 	SkipUpdateFlag               = "skip-update-check"
 	LastFileName                 = ".last_actions.json"
+	AppLockFileName              = ".lock"
 	APIRole                      = "API"
 	ValidatorRole                = "Validator"
 	MonitorRole                  = "Monitor"
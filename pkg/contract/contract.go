@@ -0,0 +1,282 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package contract
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/evm"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/subnet-evm/accounts/abi"
+	"github.com/MetalBlockchain/subnet-evm/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// LoadABI reads a contract ABI from [abiPath]. Both plain ABI files and compiler
+// artifacts with an "abi" field (eg from Hardhat or Foundry) are accepted
+func LoadABI(abiPath string) (abi.ABI, error) {
+	bs, err := os.ReadFile(abiPath)
+	if err != nil {
+		return abi.ABI{}, err
+	}
+	var artifact struct {
+		ABI json.RawMessage `json:"abi"`
+	}
+	if err := json.Unmarshal(bs, &artifact); err == nil && len(artifact.ABI) > 0 {
+		bs = artifact.ABI
+	}
+	contractABI, err := abi.JSON(bytes.NewReader(bs))
+	if err != nil {
+		return abi.ABI{}, fmt.Errorf("invalid ABI file %s: %w", abiPath, err)
+	}
+	return contractABI, nil
+}
+
+// LoadBytecode reads hex encoded contract bytecode from [bytecodePath]. Compiler
+// artifacts with a "bytecode" field are also accepted
+func LoadBytecode(bytecodePath string) ([]byte, error) {
+	bs, err := os.ReadFile(bytecodePath)
+	if err != nil {
+		return nil, err
+	}
+	bytecodeStr := strings.TrimSpace(string(bs))
+	var artifact struct {
+		Bytecode json.RawMessage `json:"bytecode"`
+	}
+	if err := json.Unmarshal(bs, &artifact); err == nil && len(artifact.Bytecode) > 0 {
+		// hardhat uses a string, foundry an object with the string as "object"
+		var bytecodeObj struct {
+			Object string `json:"object"`
+		}
+		if err := json.Unmarshal(artifact.Bytecode, &bytecodeStr); err != nil {
+			if err := json.Unmarshal(artifact.Bytecode, &bytecodeObj); err != nil {
+				return nil, fmt.Errorf("invalid bytecode field in %s: %w", bytecodePath, err)
+			}
+			bytecodeStr = bytecodeObj.Object
+		}
+	}
+	bytecode, err := hex.DecodeString(strings.TrimPrefix(bytecodeStr, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex bytecode in %s: %w", bytecodePath, err)
+	}
+	if len(bytecode) == 0 {
+		return nil, fmt.Errorf("empty bytecode in %s", bytecodePath)
+	}
+	return bytecode, nil
+}
+
+// Deploy deploys a contract with [bytecode] and [contractABI] into the chain at
+// [rpcURL], paying with [privateKey], and returns its address and the deploy tx hash
+func Deploy(
+	rpcURL string,
+	privateKey string,
+	contractABI abi.ABI,
+	bytecode []byte,
+	args []string,
+) (common.Address, common.Hash, error) {
+	params, err := ParseArgs(contractABI.Constructor.Inputs, args)
+	if err != nil {
+		return common.Address{}, common.Hash{}, fmt.Errorf("invalid constructor args: %w", err)
+	}
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return common.Address{}, common.Hash{}, err
+	}
+	txOpts, err := evm.GetTxOptsWithSigner(client, privateKey)
+	if err != nil {
+		return common.Address{}, common.Hash{}, err
+	}
+	address, tx, _, err := bind.DeployContract(txOpts, contractABI, bytecode, client, params...)
+	if err != nil {
+		return common.Address{}, common.Hash{}, err
+	}
+	if _, success, err := evm.WaitForTransaction(client, tx); err != nil {
+		return common.Address{}, common.Hash{}, err
+	} else if !success {
+		return common.Address{}, common.Hash{}, fmt.Errorf("deploy tx %s failed", tx.Hash())
+	}
+	return address, tx.Hash(), nil
+}
+
+// Call calls [methodName] of the contract at [address] with [args]. Read only
+// methods are evaluated and their outputs returned, while the other ones are
+// issued as a tx paid by [privateKey], returning the tx hash
+func Call(
+	rpcURL string,
+	privateKey string,
+	address common.Address,
+	contractABI abi.ABI,
+	methodName string,
+	args []string,
+) ([]interface{}, common.Hash, error) {
+	method, ok := contractABI.Methods[methodName]
+	if !ok {
+		return nil, common.Hash{}, fmt.Errorf("method %q not found in ABI", methodName)
+	}
+	params, err := ParseArgs(method.Inputs, args)
+	if err != nil {
+		return nil, common.Hash{}, fmt.Errorf("invalid args for %s: %w", methodName, err)
+	}
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	contract := bind.NewBoundContract(address, contractABI, client, client, client)
+	if method.IsConstant() {
+		ctx, cancel := utils.GetAPIContext()
+		defer cancel()
+		outputs := []interface{}{}
+		if err := contract.Call(&bind.CallOpts{Context: ctx}, &outputs, methodName, params...); err != nil {
+			return nil, common.Hash{}, err
+		}
+		return outputs, common.Hash{}, nil
+	}
+	txOpts, err := evm.GetTxOptsWithSigner(client, privateKey)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	tx, err := contract.Transact(txOpts, methodName, params...)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	if _, success, err := evm.WaitForTransaction(client, tx); err != nil {
+		return nil, common.Hash{}, err
+	} else if !success {
+		return nil, common.Hash{}, fmt.Errorf("tx %s failed", tx.Hash())
+	}
+	return nil, tx.Hash(), nil
+}
+
+// ParseArgs converts the string [args] into the Go values expected by the ABI
+// encoder for [inputs]. Arrays are given as JSON arrays, eg ["0x1...", "0x2..."]
+func ParseArgs(inputs abi.Arguments, args []string) ([]interface{}, error) {
+	if len(args) != len(inputs) {
+		return nil, fmt.Errorf("expected %d args, got %d", len(inputs), len(args))
+	}
+	params := make([]interface{}, len(args))
+	for i, input := range inputs {
+		value, err := parseArg(input.Type, args[i])
+		if err != nil {
+			return nil, fmt.Errorf("arg %d (%s %s): %w", i+1, input.Type, input.Name, err)
+		}
+		params[i] = value.Interface()
+	}
+	return params, nil
+}
+
+func parseArg(t abi.Type, arg string) (reflect.Value, error) {
+	switch t.T {
+	case abi.AddressTy:
+		if !common.IsHexAddress(arg) {
+			return reflect.Value{}, fmt.Errorf("invalid address %q", arg)
+		}
+		return reflect.ValueOf(common.HexToAddress(arg)), nil
+	case abi.BoolTy:
+		b, err := strconv.ParseBool(arg)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid bool %q", arg)
+		}
+		return reflect.ValueOf(b), nil
+	case abi.StringTy:
+		return reflect.ValueOf(arg), nil
+	case abi.IntTy, abi.UintTy:
+		return parseInt(t, arg)
+	case abi.BytesTy:
+		bs, err := hex.DecodeString(strings.TrimPrefix(arg, "0x"))
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid hex bytes %q", arg)
+		}
+		return reflect.ValueOf(bs), nil
+	case abi.FixedBytesTy:
+		bs, err := hex.DecodeString(strings.TrimPrefix(arg, "0x"))
+		if err != nil || len(bs) != t.Size {
+			return reflect.Value{}, fmt.Errorf("invalid hex bytes%d %q", t.Size, arg)
+		}
+		value := reflect.New(t.GetType()).Elem()
+		reflect.Copy(value, reflect.ValueOf(bs))
+		return value, nil
+	case abi.SliceTy, abi.ArrayTy:
+		var elems []json.RawMessage
+		if err := json.Unmarshal([]byte(arg), &elems); err != nil {
+			return reflect.Value{}, fmt.Errorf("expected a JSON array, got %q", arg)
+		}
+		if t.T == abi.ArrayTy && len(elems) != t.Size {
+			return reflect.Value{}, fmt.Errorf("expected %d elements, got %d", t.Size, len(elems))
+		}
+		value := reflect.New(t.GetType()).Elem()
+		if t.T == abi.SliceTy {
+			value = reflect.MakeSlice(t.GetType(), len(elems), len(elems))
+		}
+		for i, elem := range elems {
+			// elements can be given either as JSON strings or as raw JSON values
+			elemStr := string(elem)
+			if err := json.Unmarshal(elem, &elemStr); err != nil {
+				elemStr = string(elem)
+			}
+			elemValue, err := parseArg(*t.Elem, elemStr)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("element %d: %w", i, err)
+			}
+			value.Index(i).Set(elemValue)
+		}
+		return value, nil
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+func parseInt(t abi.Type, arg string) (reflect.Value, error) {
+	n, ok := new(big.Int).SetString(arg, 0)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("invalid integer %q", arg)
+	}
+	if t.T == abi.UintTy && (n.Sign() < 0 || n.BitLen() > t.Size) {
+		return reflect.Value{}, fmt.Errorf("%s out of range for %s", arg, t)
+	}
+	if t.T == abi.IntTy && n.BitLen() > t.Size-1 && !(n.Sign() < 0 && isMinInt(n, t.Size)) {
+		return reflect.Value{}, fmt.Errorf("%s out of range for %s", arg, t)
+	}
+	if t.Size > 64 {
+		return reflect.ValueOf(n), nil
+	}
+	value := reflect.New(t.GetType()).Elem()
+	if t.T == abi.UintTy {
+		value.SetUint(n.Uint64())
+	} else {
+		value.SetInt(n.Int64())
+	}
+	return value, nil
+}
+
+// isMinInt returns true if [n] is the min value of a signed int of [size] bits
+func isMinInt(n *big.Int, size int) bool {
+	minInt := new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), uint(size-1)))
+	return n.Cmp(minInt) == 0
+}
+
+// FormatOutput returns a readable representation of a method output value
+func FormatOutput(output interface{}) string {
+	switch v := output.(type) {
+	case common.Address:
+		return v.Hex()
+	case []byte:
+		return "0x" + hex.EncodeToString(v)
+	case *big.Int:
+		return v.String()
+	}
+	value := reflect.ValueOf(output)
+	if value.Kind() == reflect.Array && value.Type().Elem().Kind() == reflect.Uint8 {
+		bs := make([]byte, value.Len())
+		reflect.Copy(reflect.ValueOf(bs), value)
+		return "0x" + hex.EncodeToString(bs)
+	}
+	return fmt.Sprint(output)
+}
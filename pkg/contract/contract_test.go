@@ -0,0 +1,91 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package contract
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+const testABI = `[{"type":"function","name":"set","stateMutability":"nonpayable","inputs":[
+	{"name":"owner","type":"address"},
+	{"name":"amount","type":"uint256"},
+	{"name":"count","type":"uint64"},
+	{"name":"delta","type":"int8"},
+	{"name":"enabled","type":"bool"},
+	{"name":"id","type":"bytes32"},
+	{"name":"data","type":"bytes"},
+	{"name":"values","type":"uint256[]"}
+],"outputs":[]}]`
+
+func TestParseArgs(t *testing.T) {
+	require := require.New(t)
+	abiPath := filepath.Join(t.TempDir(), "abi.json")
+	require.NoError(os.WriteFile(abiPath, []byte(testABI), 0o600))
+	contractABI, err := LoadABI(abiPath)
+	require.NoError(err)
+	inputs := contractABI.Methods["set"].Inputs
+
+	owner := "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"
+	id := "0x0102030000000000000000000000000000000000000000000000000000000000"
+	args := []string{owner, "1000000000000000000000", "0x10", "-128", "true", id, "0xabcd", `["1", 2]`}
+	params, err := ParseArgs(inputs, args)
+	require.NoError(err)
+	require.Equal(common.HexToAddress(owner), params[0])
+	amount, _ := new(big.Int).SetString("1000000000000000000000", 10)
+	require.Equal(amount, params[1])
+	require.Equal(uint64(16), params[2])
+	require.Equal(int8(-128), params[3])
+	require.Equal(true, params[4])
+	require.Equal([32]byte{1, 2, 3}, params[5])
+	require.Equal([]byte{0xab, 0xcd}, params[6])
+	require.Equal([]*big.Int{big.NewInt(1), big.NewInt(2)}, params[7])
+	// params must be accepted by the encoder
+	_, err = contractABI.Pack("set", params...)
+	require.NoError(err)
+
+	_, err = ParseArgs(inputs, args[:1])
+	require.ErrorContains(err, "expected 8 args")
+
+	invalid := []struct {
+		index int
+		arg   string
+	}{
+		{0, "0x1234"},
+		{1, "-1"},
+		{2, "18446744073709551616"},
+		{3, "128"},
+		{4, "yes"},
+		{5, "0xabcd"},
+		{6, "xyz"},
+		{7, "1,2"},
+	}
+	for _, tc := range invalid {
+		badArgs := append([]string{}, args...)
+		badArgs[tc.index] = tc.arg
+		_, err := ParseArgs(inputs, badArgs)
+		require.Error(err, tc.arg)
+	}
+}
+
+func TestLoadBytecode(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"plain.bin":     "0x6080\n",
+		"hardhat.json":  `{"abi":[],"bytecode":"0x6080"}`,
+		"foundry.json":  `{"abi":[],"bytecode":{"object":"0x6080"}}`,
+		"noprefix.json": `{"bytecode":"6080"}`,
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(os.WriteFile(path, []byte(content), 0o600))
+		bytecode, err := LoadBytecode(path)
+		require.NoError(err, name)
+		require.Equal([]byte{0x60, 0x80}, bytecode, name)
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package devnet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	avagoconstants "github.com/MetalBlockchain/metalgo/utils/constants"
+	"golang.org/x/exp/slices"
+)
+
+// Backends a devnet can run on
+const (
+	Docker = "docker"
+	AWS    = "aws"
+	GCP    = "gcp"
+)
+
+const (
+	devnetFileName = "devnet.json"
+	// networkDirName keeps the network files of a docker devnet
+	networkDirName = "network"
+	pluginsDirName = "plugins"
+
+	// network IDs of new devnets are taken from this range, away from the
+	// standard and local network IDs
+	minNetworkID = 100000
+	maxNetworkID = 1000000
+)
+
+var (
+	validName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+	ErrNotFound = errors.New("devnet not found")
+)
+
+// Backends returns the backends a devnet can run on
+func Backends() []string {
+	return []string{Docker, AWS, GCP}
+}
+
+// Devnet is a short lived network fully owned by the CLI, with its own network ID,
+// genesis and validators
+type Devnet struct {
+	Name      string
+	Backend   string
+	NetworkID uint32
+	NumNodes  uint32
+	// Endpoint is the API endpoint of the first node
+	Endpoint string
+	// ports of the first node of a docker devnet
+	HTTPPort    int `json:",omitempty"`
+	StakingPort int `json:",omitempty"`
+	CreatedAt   time.Time
+	// ExpiresAt is when the devnet is automatically destroyed. Zero means never
+	ExpiresAt time.Time
+}
+
+// IsCloud returns true if the devnet runs on cloud nodes, managed as a node cluster
+// with the same name
+func (d Devnet) IsCloud() bool {
+	return d.Backend == AWS || d.Backend == GCP
+}
+
+// Expired returns true if the TTL of the devnet is over at [now]
+func (d Devnet) Expired(now time.Time) bool {
+	return !d.ExpiresAt.IsZero() && !now.Before(d.ExpiresAt)
+}
+
+// Dir is where the files of devnet [name] are kept
+func Dir(devnetsDir string, name string) string {
+	return filepath.Join(devnetsDir, name)
+}
+
+// NetworkDir is where the network files of docker devnet [name] are kept
+func NetworkDir(devnetsDir string, name string) string {
+	return filepath.Join(Dir(devnetsDir, name), networkDirName)
+}
+
+// PluginsDir holds the VM binaries of docker devnet [name]
+func PluginsDir(devnetsDir string, name string) string {
+	return filepath.Join(Dir(devnetsDir, name), pluginsDirName)
+}
+
+// ValidateName checks that [name] can be used as devnet name, which is also the
+// name of its docker compose project or node cluster
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid devnet name %q: only lowercase letters, digits and '-' are allowed", name)
+	}
+	return nil
+}
+
+// Save writes the record of devnet [d] under [devnetsDir]
+func Save(devnetsDir string, d Devnet) error {
+	if err := os.MkdirAll(Dir(devnetsDir, d.Name), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	bs, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(Dir(devnetsDir, d.Name), devnetFileName), bs, constants.WriteReadReadPerms)
+}
+
+// Load reads the record of devnet [name] from [devnetsDir]
+func Load(devnetsDir string, name string) (Devnet, error) {
+	bs, err := os.ReadFile(filepath.Join(Dir(devnetsDir, name), devnetFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return Devnet{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return Devnet{}, err
+	}
+	d := Devnet{}
+	if err := json.Unmarshal(bs, &d); err != nil {
+		return Devnet{}, fmt.Errorf("invalid record for devnet %s: %w", name, err)
+	}
+	return d, nil
+}
+
+// List returns the devnets recorded under [devnetsDir], oldest first
+func List(devnetsDir string) ([]Devnet, error) {
+	devnets := []Devnet{}
+	if !utils.DirectoryExists(devnetsDir) {
+		return devnets, nil
+	}
+	entries, err := os.ReadDir(devnetsDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || !utils.FileExists(filepath.Join(devnetsDir, entry.Name(), devnetFileName)) {
+			continue
+		}
+		d, err := Load(devnetsDir, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		devnets = append(devnets, d)
+	}
+	sort.SliceStable(devnets, func(i, j int) bool {
+		return devnets[i].CreatedAt.Before(devnets[j].CreatedAt)
+	})
+	return devnets, nil
+}
+
+// Remove removes the files of devnet [name]
+func Remove(devnetsDir string, name string) error {
+	return os.RemoveAll(Dir(devnetsDir, name))
+}
+
+// NewNetworkID returns a random network ID not used by [devnets], nor by the
+// standard networks
+func NewNetworkID(devnets []Devnet) uint32 {
+	for {
+		// #nosec G404
+		networkID := uint32(minNetworkID + rand.Intn(maxNetworkID-minNetworkID))
+		if !IsReservedNetworkID(networkID) && !slices.ContainsFunc(devnets, func(d Devnet) bool { return d.NetworkID == networkID }) {
+			return networkID
+		}
+	}
+}
+
+// IsReservedNetworkID returns true if [networkID] can't be used by a devnet
+func IsReservedNetworkID(networkID uint32) bool {
+	switch networkID {
+	case avagoconstants.MainnetID, avagoconstants.TahoeID, avagoconstants.LocalID, avagoconstants.UnitTestID,
+		constants.LocalNetworkID, constants.DevnetNetworkID:
+		return true
+	}
+	return false
+}
+
+// NewPorts returns the HTTP and staking ports for the first node of a new docker
+// devnet, in the first port range not used by the docker devnets of [devnets]
+func NewPorts(devnets []Devnet) (int, int) {
+	for httpPort := constants.DevnetBaseHTTPPort; ; httpPort += constants.DevnetPortRangeSize {
+		if !slices.ContainsFunc(devnets, func(d Devnet) bool { return d.HTTPPort == httpPort }) {
+			return httpPort, httpPort + 1
+		}
+	}
+}
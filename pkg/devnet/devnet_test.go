@@ -0,0 +1,96 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package devnet
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveLoadListRemove(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+
+	devnets, err := List(dir)
+	require.NoError(err)
+	require.Empty(devnets)
+
+	now := time.Now().Round(0)
+	newer := Devnet{Name: "newer", Backend: Docker, NetworkID: 100001, NumNodes: 3, CreatedAt: now}
+	older := Devnet{Name: "older", Backend: AWS, NetworkID: 100002, NumNodes: 1, CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)}
+	require.NoError(Save(dir, newer))
+	require.NoError(Save(dir, older))
+
+	d, err := Load(dir, "newer")
+	require.NoError(err)
+	require.Equal(newer.Name, d.Name)
+	require.Equal(newer.NetworkID, d.NetworkID)
+	require.True(newer.CreatedAt.Equal(d.CreatedAt))
+
+	devnets, err = List(dir)
+	require.NoError(err)
+	require.Len(devnets, 2)
+	require.Equal("older", devnets[0].Name)
+	require.Equal("newer", devnets[1].Name)
+
+	require.NoError(Remove(dir, "older"))
+	_, err = Load(dir, "older")
+	require.True(errors.Is(err, ErrNotFound))
+	devnets, err = List(dir)
+	require.NoError(err)
+	require.Len(devnets, 1)
+}
+
+func TestExpired(t *testing.T) {
+	require := require.New(t)
+	now := time.Now()
+	require.False(Devnet{}.Expired(now))
+	require.False(Devnet{ExpiresAt: now.Add(time.Minute)}.Expired(now))
+	require.True(Devnet{ExpiresAt: now}.Expired(now))
+	require.True(Devnet{ExpiresAt: now.Add(-time.Minute)}.Expired(now))
+}
+
+func TestValidateName(t *testing.T) {
+	require := require.New(t)
+	require.NoError(ValidateName("my-devnet-1"))
+	require.Error(ValidateName(""))
+	require.Error(ValidateName("-devnet"))
+	require.Error(ValidateName("MyDevnet"))
+	require.Error(ValidateName("my_devnet"))
+	require.Error(ValidateName("../devnet"))
+}
+
+func TestNewNetworkID(t *testing.T) {
+	require := require.New(t)
+	for i := 0; i < 100; i++ {
+		networkID := NewNetworkID(nil)
+		require.GreaterOrEqual(networkID, uint32(minNetworkID))
+		require.Less(networkID, uint32(maxNetworkID))
+		require.False(IsReservedNetworkID(networkID))
+	}
+	require.True(IsReservedNetworkID(constants.LocalNetworkID))
+}
+
+func TestNewPorts(t *testing.T) {
+	require := require.New(t)
+	httpPort, stakingPort := NewPorts(nil)
+	require.Equal(constants.DevnetBaseHTTPPort, httpPort)
+	require.Equal(constants.DevnetBaseHTTPPort+1, stakingPort)
+
+	// the first range is reused once its devnet is gone
+	devnets := []Devnet{
+		{Name: "a", HTTPPort: constants.DevnetBaseHTTPPort + constants.DevnetPortRangeSize},
+		{Name: "b", Backend: AWS},
+	}
+	httpPort, _ = NewPorts(devnets)
+	require.Equal(constants.DevnetBaseHTTPPort, httpPort)
+
+	devnets = append(devnets, Devnet{Name: "c", HTTPPort: constants.DevnetBaseHTTPPort})
+	httpPort, stakingPort = NewPorts(devnets)
+	require.Equal(constants.DevnetBaseHTTPPort+2*constants.DevnetPortRangeSize, httpPort)
+	require.Equal(httpPort+1, stakingPort)
+}
@@ -4,6 +4,7 @@
 package elasticsubnet
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"time"
@@ -36,12 +37,10 @@ const (
 	defaultUptimeRequirement           = 0.8
 )
 
-func GetElasticSubnetConfig(app *application.Avalanche, tokenSymbol string, useDefaultConfig bool) (models.ElasticSubnetConfig, error) {
-	const (
-		defaultConfig   = "Use default elastic subnet config"
-		customizeConfig = "Customize elastic subnet config"
-	)
-	elasticSubnetConfig := models.ElasticSubnetConfig{
+// GetDefaultElasticSubnetConfig returns the elastic config parameters used by
+// the primary network on mainnet
+func GetDefaultElasticSubnetConfig() models.ElasticSubnetConfig {
+	return models.ElasticSubnetConfig{
 		InitialSupply:            defaultInitialSupply,
 		MaxSupply:                defaultMaximumSupply,
 		MinConsumptionRate:       defaultMinConsumptionRate * reward.PercentDenominator,
@@ -55,6 +54,50 @@ func GetElasticSubnetConfig(app *application.Avalanche, tokenSymbol string, useD
 		MaxValidatorWeightFactor: defaultMaxValidatorWeightFactor,
 		UptimeRequirement:        defaultUptimeRequirement * reward.PercentDenominator,
 	}
+}
+
+// ValidateElasticSubnetConfig checks the constraints the P-Chain enforces on
+// TransformSubnetTx parameters, so that invalid configs fail before any tx is issued
+func ValidateElasticSubnetConfig(config models.ElasticSubnetConfig) error {
+	switch {
+	case config.InitialSupply == 0:
+		return errors.New("initial supply must be greater than 0")
+	case config.InitialSupply > config.MaxSupply:
+		return fmt.Errorf("initial supply %d exceeds maximum supply %d", config.InitialSupply, config.MaxSupply)
+	case config.MinConsumptionRate > config.MaxConsumptionRate:
+		return fmt.Errorf("minimum consumption rate %d exceeds maximum consumption rate %d", config.MinConsumptionRate, config.MaxConsumptionRate)
+	case config.MaxConsumptionRate > reward.PercentDenominator:
+		return fmt.Errorf("maximum consumption rate %d exceeds percent denominator %d", config.MaxConsumptionRate, reward.PercentDenominator)
+	case config.MinValidatorStake == 0:
+		return errors.New("minimum validator stake must be greater than 0")
+	case config.MinValidatorStake > config.InitialSupply:
+		return fmt.Errorf("minimum validator stake %d exceeds initial supply %d", config.MinValidatorStake, config.InitialSupply)
+	case config.MinValidatorStake > config.MaxValidatorStake:
+		return fmt.Errorf("minimum validator stake %d exceeds maximum validator stake %d", config.MinValidatorStake, config.MaxValidatorStake)
+	case config.MaxValidatorStake > config.MaxSupply:
+		return fmt.Errorf("maximum validator stake %d exceeds maximum supply %d", config.MaxValidatorStake, config.MaxSupply)
+	case config.MinStakeDuration == 0:
+		return errors.New("minimum stake duration must be greater than 0")
+	case config.MinStakeDuration > config.MaxStakeDuration:
+		return fmt.Errorf("minimum stake duration %s exceeds maximum stake duration %s", config.MinStakeDuration, config.MaxStakeDuration)
+	case config.MinDelegationFee > reward.PercentDenominator:
+		return fmt.Errorf("minimum delegation fee %d exceeds percent denominator %d", config.MinDelegationFee, reward.PercentDenominator)
+	case config.MinDelegatorStake == 0:
+		return errors.New("minimum delegator stake must be greater than 0")
+	case config.MaxValidatorWeightFactor == 0:
+		return errors.New("maximum validator weight factor must be greater than 0")
+	case config.UptimeRequirement > reward.PercentDenominator:
+		return fmt.Errorf("uptime requirement %d exceeds percent denominator %d", config.UptimeRequirement, reward.PercentDenominator)
+	}
+	return nil
+}
+
+func GetElasticSubnetConfig(app *application.Avalanche, tokenSymbol string, useDefaultConfig bool) (models.ElasticSubnetConfig, error) {
+	const (
+		defaultConfig   = "Use default elastic subnet config"
+		customizeConfig = "Customize elastic subnet config"
+	)
+	elasticSubnetConfig := GetDefaultElasticSubnetConfig()
 	if useDefaultConfig {
 		return elasticSubnetConfig, nil
 	}
@@ -0,0 +1,80 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package elasticsubnet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/reward"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateElasticSubnetConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(*models.ElasticSubnetConfig)
+		expectedErr bool
+	}{
+		{
+			name:   "default config",
+			modify: func(*models.ElasticSubnetConfig) {},
+		},
+		{
+			name:        "zero initial supply",
+			modify:      func(c *models.ElasticSubnetConfig) { c.InitialSupply = 0 },
+			expectedErr: true,
+		},
+		{
+			name:        "initial supply over max supply",
+			modify:      func(c *models.ElasticSubnetConfig) { c.InitialSupply = c.MaxSupply + 1 },
+			expectedErr: true,
+		},
+		{
+			name:        "min consumption rate over max",
+			modify:      func(c *models.ElasticSubnetConfig) { c.MinConsumptionRate = c.MaxConsumptionRate + 1 },
+			expectedErr: true,
+		},
+		{
+			name: "max consumption rate over denominator",
+			modify: func(c *models.ElasticSubnetConfig) {
+				c.MaxConsumptionRate = reward.PercentDenominator + 1
+			},
+			expectedErr: true,
+		},
+		{
+			name:        "min validator stake over max",
+			modify:      func(c *models.ElasticSubnetConfig) { c.MinValidatorStake = c.MaxValidatorStake + 1 },
+			expectedErr: true,
+		},
+		{
+			name:        "min stake duration over max",
+			modify:      func(c *models.ElasticSubnetConfig) { c.MinStakeDuration = c.MaxStakeDuration + time.Hour },
+			expectedErr: true,
+		},
+		{
+			name:        "zero max validator weight factor",
+			modify:      func(c *models.ElasticSubnetConfig) { c.MaxValidatorWeightFactor = 0 },
+			expectedErr: true,
+		},
+		{
+			name:        "uptime requirement over denominator",
+			modify:      func(c *models.ElasticSubnetConfig) { c.UptimeRequirement = reward.PercentDenominator + 1 },
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultElasticSubnetConfig()
+			tt.modify(&config)
+			err := ValidateElasticSubnetConfig(config)
+			if tt.expectedErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
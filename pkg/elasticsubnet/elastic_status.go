@@ -36,7 +36,7 @@ func GetLocalElasticSubnetsFromFile(app *application.Avalanche) ([]string, error
 
 		// check if sidecar contains local elastic subnets info in Elastic Subnets map
 		// if so, add to list of elastic subnets
-		if _, ok := sc.ElasticSubnet[models.Local.String()]; ok {
+		if _, ok := sc.ElasticSubnet[models.NewLocalNetwork().Name()]; ok {
 			elasticSubnets = append(elasticSubnets, sc.Name)
 		}
 	}
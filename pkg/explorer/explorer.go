@@ -0,0 +1,82 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package explorer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"embed"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+)
+
+// blockscout release run by local explorers. Releases up to v5 include the web UI
+const blockscoutVersion = "5.2.3"
+
+//go:embed configs/*
+var configs embed.FS
+
+// Config describes the blockchain indexed by a local explorer
+type Config struct {
+	// compose project name, must be lowercase
+	ProjectName string
+	SubnetName  string
+	ChainID     string
+	TokenSymbol string
+	// blockchain RPC and websocket endpoints, as seen from the explorer container
+	RPCURL string
+	WSURL  string
+	// host port of the explorer UI
+	Port int
+}
+
+type composeInputs struct {
+	Config
+	BlockscoutVersion string
+	SecretKeyBase     string
+}
+
+// ProjectName returns a valid compose project name for the explorer of [subnetName]
+// on the local network [localNetworkName] (empty for the default one)
+func ProjectName(subnetName string, localNetworkName string) string {
+	name := "metal-explorer-" + subnetName
+	if localNetworkName != "" {
+		name += "-" + localNetworkName
+	}
+	return strings.ReplaceAll(strings.ToLower(name), " ", "-")
+}
+
+// WriteLocalExplorerFiles writes into [explorerDir] a docker compose file running a
+// Blockscout explorer, together with its database, that indexes the blockchain
+// described by [config]
+func WriteLocalExplorerFiles(explorerDir string, config Config) error {
+	if err := os.MkdirAll(explorerDir, constants.DefaultPerms755); err != nil {
+		return err
+	}
+	secret := make([]byte, 64)
+	if _, err := rand.Read(secret); err != nil {
+		return err
+	}
+	composeTemplate, err := configs.ReadFile("configs/blockscoutCompose.yml")
+	if err != nil {
+		return err
+	}
+	t, err := template.New("Local Explorer Compose").Parse(string(composeTemplate))
+	if err != nil {
+		return err
+	}
+	var compose bytes.Buffer
+	if err := t.Execute(&compose, composeInputs{
+		Config:            config,
+		BlockscoutVersion: blockscoutVersion,
+		SecretKeyBase:     hex.EncodeToString(secret),
+	}); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(explorerDir, constants.MonitoringComposeFileName), compose.Bytes(), constants.WriteReadUserOnlyPerms)
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package explorer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectName(t *testing.T) {
+	require := require.New(t)
+	require.Equal("metal-explorer-mysubnet", ProjectName("MySubnet", ""))
+	require.Equal("metal-explorer-my-subnet-dev", ProjectName("My Subnet", "dev"))
+}
+
+func TestWriteLocalExplorerFiles(t *testing.T) {
+	require := require.New(t)
+	dir := filepath.Join(t.TempDir(), "explorer")
+	err := WriteLocalExplorerFiles(dir, Config{
+		ProjectName: "metal-explorer-test",
+		SubnetName:  "test",
+		ChainID:     "12345",
+		TokenSymbol: "TST",
+		RPCURL:      "http://host.docker.internal:9650/ext/bc/abc/rpc",
+		WSURL:       "ws://host.docker.internal:9650/ext/bc/abc/ws",
+		Port:        4001,
+	})
+	require.NoError(err)
+	compose, err := os.ReadFile(filepath.Join(dir, constants.MonitoringComposeFileName))
+	require.NoError(err)
+	require.Contains(string(compose), "name: metal-explorer-test")
+	require.Contains(string(compose), "ETHEREUM_JSONRPC_HTTP_URL=http://host.docker.internal:9650/ext/bc/abc/rpc")
+	require.Contains(string(compose), "ETHEREUM_JSONRPC_WS_URL=ws://host.docker.internal:9650/ext/bc/abc/ws")
+	require.Contains(string(compose), "CHAIN_ID=12345")
+	require.Contains(string(compose), "COIN=TST")
+	require.Contains(string(compose), `"127.0.0.1:4001:4000"`)
+	require.Contains(string(compose), "blockscout/blockscout:"+blockscoutVersion)
+}
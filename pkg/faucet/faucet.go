@@ -0,0 +1,70 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package faucet
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+)
+
+const (
+	PChain = "P"
+	CChain = "C"
+)
+
+type request struct {
+	Address string `json:"address"`
+	Chain   string `json:"chain"`
+}
+
+type response struct {
+	Message string `json:"message"`
+	TxHash  string `json:"txHash"`
+}
+
+// RequestFunds asks the faucet at [url] to send test tokens to [address] on [chain].
+// Returns the tx hash, if the faucet reports it
+func RequestFunds(url string, chain string, address string) (string, error) {
+	body, err := json.Marshal(request{
+		Address: address,
+		Chain:   chain,
+	})
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create faucet request for %s: %w", url, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed requesting funds from faucet %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed reading faucet %s response: %w", url, err)
+	}
+	var faucetResp response
+	// the body is not always JSON on failures, just keep it as the message then
+	if err := json.Unmarshal(respBody, &faucetResp); err != nil {
+		faucetResp.Message = strings.TrimSpace(string(respBody))
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if faucetResp.Message != "" {
+			return "", fmt.Errorf("faucet %s refused the request: %s", url, faucetResp.Message)
+		}
+		return "", fmt.Errorf("faucet %s refused the request: unexpected http status code: %d", url, resp.StatusCode)
+	}
+	return faucetResp.TxHash, nil
+}
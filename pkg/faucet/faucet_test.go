@@ -0,0 +1,44 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package faucet
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestFunds(t *testing.T) {
+	require := require.New(t)
+
+	var received request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(json.NewDecoder(r.Body).Decode(&received))
+		switch received.Address {
+		case "rate-limited":
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"message":"Too many requests. Please try again after 60 minutes"}`))
+		case "plain-error":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("bad request\n"))
+		default:
+			_, _ = w.Write([]byte(`{"message":"Transaction successful","txHash":"0x1234"}`))
+		}
+	}))
+	defer server.Close()
+
+	txHash, err := RequestFunds(server.URL, CChain, "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	require.NoError(err)
+	require.Equal("0x1234", txHash)
+	require.Equal(request{Address: "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC", Chain: CChain}, received)
+
+	_, err = RequestFunds(server.URL, PChain, "rate-limited")
+	require.ErrorContains(err, "Too many requests")
+
+	_, err = RequestFunds(server.URL, PChain, "plain-error")
+	require.ErrorContains(err, "bad request")
+}
@@ -0,0 +1,122 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package filelock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const retryInterval = 100 * time.Millisecond
+
+var (
+	ErrTimeout = errors.New("timeout waiting for lock")
+	// errLocked is returned by tryLock when another process holds the lock
+	errLocked = errors.New("locked")
+)
+
+// Owner identifies the process holding a lock
+type Owner struct {
+	PID     int
+	Command string
+	Since   time.Time
+}
+
+func (o Owner) String() string {
+	if o.PID == 0 {
+		return "an unknown process"
+	}
+	if o.Command == "" {
+		return fmt.Sprintf("process %d since %s", o.PID, o.Since.Local().Format(time.Kitchen))
+	}
+	return fmt.Sprintf("process %d (%s) since %s", o.PID, o.Command, o.Since.Local().Format(time.Kitchen))
+}
+
+// Lock is an advisory lock on a file, held until released. Locks are only
+// enforced between processes that use this package
+type Lock struct {
+	file *os.File
+}
+
+// Acquire locks the file at [path], creating it if needed, recording [command]
+// as the one of the owner. If another process holds the lock, [onWait] is called
+// with its owner, and the lock is retried for up to [timeout]
+func Acquire(path string, command string, timeout time.Duration, onWait func(Owner)) (*Lock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(timeout)
+	waiting := false
+	for {
+		err := tryLock(file)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, errLocked) {
+			_ = file.Close()
+			return nil, err
+		}
+		if !waiting && onWait != nil {
+			onWait(readOwner(file))
+		}
+		waiting = true
+		if time.Now().After(deadline) {
+			owner := readOwner(file)
+			_ = file.Close()
+			return nil, fmt.Errorf("%w %s, held by %s", ErrTimeout, path, owner)
+		}
+		time.Sleep(retryInterval)
+	}
+	lock := &Lock{file: file}
+	if err := lock.writeOwner(command); err != nil {
+		_ = lock.Release()
+		return nil, err
+	}
+	return lock, nil
+}
+
+// Release frees the lock for other processes
+func (l *Lock) Release() error {
+	// the owner is cleared first, as it can't be once the lock is given up
+	_ = l.file.Truncate(0)
+	err := unlock(l.file)
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (l *Lock) writeOwner(command string) error {
+	bs, err := json.Marshal(Owner{
+		PID:     os.Getpid(),
+		Command: command,
+		Since:   time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if err := l.file.Truncate(0); err != nil {
+		return err
+	}
+	_, err = l.file.WriteAt(bs, 0)
+	return err
+}
+
+// readOwner returns the owner recorded on the lock file, if any
+func readOwner(file *os.File) Owner {
+	owner := Owner{}
+	bs, err := io.ReadAll(io.NewSectionReader(file, 0, 1<<16))
+	if err == nil {
+		_ = json.Unmarshal(bs, &owner)
+	}
+	return owner
+}
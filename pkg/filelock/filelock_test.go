@@ -0,0 +1,37 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build !windows
+
+package filelock
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquire(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), "dir", ".lock")
+
+	lock, err := Acquire(path, "metal subnet deploy", time.Second, nil)
+	require.NoError(err)
+
+	// flock locks are per open file, so a second acquire in this process waits
+	var waitedOn *Owner
+	_, err = Acquire(path, "metal subnet create", 300*time.Millisecond, func(o Owner) { waitedOn = &o })
+	require.ErrorIs(err, ErrTimeout)
+	require.NotNil(waitedOn)
+	require.Equal(os.Getpid(), waitedOn.PID)
+	require.Equal("metal subnet deploy", waitedOn.Command)
+	require.Contains(err.Error(), waitedOn.String())
+
+	require.NoError(lock.Release())
+	lock, err = Acquire(path, "", time.Second, func(Owner) { require.FailNow("lock should be free") })
+	require.NoError(err)
+	require.NoError(lock.Release())
+}
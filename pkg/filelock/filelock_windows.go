@@ -0,0 +1,18 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build windows
+
+package filelock
+
+import "os"
+
+// advisory locks are not supported on windows, where the lock is always acquired
+
+func tryLock(*os.File) error {
+	return nil
+}
+
+func unlock(*os.File) error {
+	return nil
+}
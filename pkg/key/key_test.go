@@ -5,6 +5,7 @@ package key
 
 import (
 	"bytes"
+	"encoding/hex"
 	"errors"
 	"path/filepath"
 	"testing"
@@ -113,3 +114,68 @@ func TestNewKey(t *testing.T) {
 		}
 	}
 }
+
+func TestLoadSoftFromString(t *testing.T) {
+	t.Parallel()
+
+	ewoq, err := LoadEwoq(fallbackNetworkID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hexKey := hex.EncodeToString(ewoq.Raw())
+
+	for _, keyStr := range []string{
+		EwoqPrivateKey,
+		hexKey,
+		"0x" + hexKey,
+		"  " + hexKey + "\n",
+	} {
+		k, err := LoadSoftFromString(fallbackNetworkID, keyStr)
+		if err != nil {
+			t.Fatalf("%q: unexpected error %v", keyStr, err)
+		}
+		if !bytes.Equal(k.Raw(), ewoq.Raw()) {
+			t.Fatalf("%q: loaded key unexpected %v, expected %v", keyStr, k.Raw(), ewoq.Raw())
+		}
+	}
+
+	for _, keyStr := range []string{"", "0x1234", "PrivateKey-invalid", hexKey + "zz"} {
+		if _, err := LoadSoftFromString(fallbackNetworkID, keyStr); err == nil {
+			t.Fatalf("%q: expected error", keyStr)
+		}
+	}
+}
+
+func TestSignMessage(t *testing.T) {
+	t.Parallel()
+
+	m, err := LoadEwoq(fallbackNetworkID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("control key proof")
+	sig, err := m.SignMessage(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := VerifyMessage(msg, sig, m.Addresses()[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected signature to verify")
+	}
+
+	ok, err = VerifyMessage([]byte("another message"), sig, m.Addresses()[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected signature of another message to not verify")
+	}
+
+	if _, err := VerifyMessage(msg, "notcb58", m.Addresses()[0]); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("unexpected error %v, expected %v", err, ErrInvalidSignature)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package key
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/cb58"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/secp256k1"
+	"github.com/MetalBlockchain/metalgo/utils/hashing"
+)
+
+// prefix used by wallets when signing arbitrary messages with X/P chain keys,
+// so that a signed message can never be a valid tx signature
+const signedMessagePrefix = "\x1AAvalanche Signed Message:\n"
+
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// SignedMessageHash returns the hash actually signed for [msg]:
+// sha256(prefix || uint32 big endian len(msg) || msg)
+func SignedMessageHash(msg []byte) []byte {
+	buf := make([]byte, 0, len(signedMessagePrefix)+4+len(msg))
+	buf = append(buf, signedMessagePrefix...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(msg)))
+	buf = append(buf, msg...)
+	return hashing.ComputeHash256(buf)
+}
+
+// SignMessage signs [msg] with the key, returning the CB58 encoded
+// 65 bytes recoverable signature, as wallets do
+func (m *SoftKey) SignMessage(msg []byte) (string, error) {
+	sig, err := m.privKey.SignHash(SignedMessageHash(msg))
+	if err != nil {
+		return "", err
+	}
+	return cb58.Encode(sig)
+}
+
+// RecoverMessageSigner returns the public key that produced [signature] for [msg].
+// [signature] is expected to be CB58 encoded, as returned by SignMessage
+func RecoverMessageSigner(msg []byte, signature string) (*secp256k1.PublicKey, error) {
+	sig, err := cb58.Decode(signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	if len(sig) != secp256k1.SignatureLen {
+		return nil, fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidSignature, secp256k1.SignatureLen, len(sig))
+	}
+	pubKey, err := secp256k1.RecoverPublicKeyFromHash(SignedMessageHash(msg), sig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	return pubKey, nil
+}
+
+// VerifyMessage checks that [signature] of [msg] was produced by the key of [addr]
+func VerifyMessage(msg []byte, signature string, addr ids.ShortID) (bool, error) {
+	pubKey, err := RecoverMessageSigner(msg, signature)
+	if err != nil {
+		return false, err
+	}
+	return pubKey.Address() == addr, nil
+}
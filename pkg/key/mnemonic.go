@@ -0,0 +1,101 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package key
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MetalBlockchain/metalgo/utils/crypto/secp256k1"
+	dsecp256k1 "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/tyler-smith/go-bip39"
+)
+
+const (
+	// 256 bits of entropy give a 24 words mnemonic
+	mnemonicEntropyBits = 256
+	hardenedKeyStart    = uint32(0x80000000)
+)
+
+var (
+	// derivation path used by wallets for the X/P chains: m/44'/9000'/0'/0/index
+	walletDerivationPath = []uint32{
+		hardenedKeyStart + 44,
+		hardenedKeyStart + 9000,
+		hardenedKeyStart + 0,
+		0,
+	}
+	masterKeySeed = []byte("Bitcoin seed")
+
+	ErrInvalidMnemonic   = errors.New("invalid mnemonic phrase")
+	errInvalidDerivation = errors.New("invalid derived key, try the next index")
+)
+
+// NewMnemonic generates a random 24 words BIP39 mnemonic phrase
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(mnemonicEntropyBits)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// LoadSoftFromMnemonic derives the private key at [index] of the BIP39 [mnemonic],
+// following the same BIP44 path as wallets do (m/44'/9000'/0'/0/index)
+func LoadSoftFromMnemonic(networkID uint32, mnemonic string, index uint32) (*SoftKey, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, "")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMnemonic, err)
+	}
+	privKeyBytes, err := deriveBIP32PrivateKey(seed, append(walletDerivationPath, index))
+	if err != nil {
+		return nil, err
+	}
+	privKey, err := secp256k1.ToPrivateKey(privKeyBytes)
+	if err != nil {
+		return nil, err
+	}
+	return NewSoft(networkID, WithPrivateKey(privKey))
+}
+
+// deriveBIP32PrivateKey derives the private key found at [path] from
+// the master key of [seed], as specified by BIP32
+func deriveBIP32PrivateKey(seed []byte, path []uint32) ([]byte, error) {
+	mac := hmac.New(sha512.New, masterKeySeed)
+	_, _ = mac.Write(seed)
+	sum := mac.Sum(nil)
+	key, chainCode := sum[:32], sum[32:]
+	var keyScalar dsecp256k1.ModNScalar
+	if overflow := keyScalar.SetByteSlice(key); overflow || keyScalar.IsZero() {
+		return nil, errInvalidDerivation
+	}
+	for _, childIndex := range path {
+		data := make([]byte, 0, 37)
+		if childIndex >= hardenedKeyStart {
+			data = append(data, 0)
+			data = append(data, key...)
+		} else {
+			data = append(data, dsecp256k1.PrivKeyFromBytes(key).PubKey().SerializeCompressed()...)
+		}
+		data = binary.BigEndian.AppendUint32(data, childIndex)
+		mac := hmac.New(sha512.New, chainCode)
+		_, _ = mac.Write(data)
+		sum := mac.Sum(nil)
+		var tweak dsecp256k1.ModNScalar
+		if overflow := tweak.SetByteSlice(sum[:32]); overflow {
+			return nil, errInvalidDerivation
+		}
+		keyScalar.Add(&tweak)
+		if keyScalar.IsZero() {
+			return nil, errInvalidDerivation
+		}
+		keyBytes := keyScalar.Bytes()
+		key, chainCode = keyBytes[:], sum[32:]
+	}
+	return key, nil
+}
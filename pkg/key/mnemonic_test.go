@@ -0,0 +1,87 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package key
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// BIP32 test vector 1
+func TestDeriveBIP32PrivateKey(t *testing.T) {
+	t.Parallel()
+
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tt := []struct {
+		path        []uint32
+		expectedKey string
+	}{
+		{
+			path:        []uint32{},
+			expectedKey: "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35",
+		},
+		{
+			path:        []uint32{hardenedKeyStart},
+			expectedKey: "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea",
+		},
+		{
+			path:        []uint32{hardenedKeyStart, 1},
+			expectedKey: "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368",
+		},
+		{
+			path:        []uint32{hardenedKeyStart, 1, hardenedKeyStart + 2, 2, 1000000000},
+			expectedKey: "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8",
+		},
+	}
+	for i, tv := range tt {
+		key, err := deriveBIP32PrivateKey(seed, tv.path)
+		if err != nil {
+			t.Fatalf("#%d: unexpected error %v", i, err)
+		}
+		if hex.EncodeToString(key) != tv.expectedKey {
+			t.Fatalf("#%d: unexpected key %x, expected %s", i, key, tv.expectedKey)
+		}
+	}
+}
+
+func TestLoadSoftFromMnemonic(t *testing.T) {
+	t.Parallel()
+
+	mnemonic, err := NewMnemonic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if words := len(strings.Fields(mnemonic)); words != 24 {
+		t.Fatalf("unexpected mnemonic length %d, expected 24", words)
+	}
+
+	k0, err := LoadSoftFromMnemonic(fallbackNetworkID, mnemonic, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// extra whitespace is ignored
+	k0b, err := LoadSoftFromMnemonic(fallbackNetworkID, " "+strings.ReplaceAll(mnemonic, " ", "  ")+"\n", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(k0.Raw(), k0b.Raw()) {
+		t.Fatalf("unexpected key %v, expected %v", k0b.Raw(), k0.Raw())
+	}
+	k1, err := LoadSoftFromMnemonic(fallbackNetworkID, mnemonic, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(k0.Raw(), k1.Raw()) {
+		t.Fatal("expected different keys for different indices")
+	}
+
+	if _, err := LoadSoftFromMnemonic(fallbackNetworkID, "not a mnemonic", 0); !errors.Is(err, ErrInvalidMnemonic) {
+		t.Fatalf("unexpected error %v, expected %v", err, ErrInvalidMnemonic)
+	}
+}
@@ -192,6 +192,18 @@ func LoadSoftFromBytes(networkID uint32, kb []byte) (*SoftKey, error) {
 	return NewSoft(networkID, WithPrivateKey(privKey))
 }
 
+// LoadSoftFromString loads a private key given either in the encoded
+// "PrivateKey-" format or in hex, optionally prefixed with "0x".
+func LoadSoftFromString(networkID uint32, keyStr string) (*SoftKey, error) {
+	keyStr = strings.TrimSpace(keyStr)
+	keyStr = strings.TrimPrefix(keyStr, "0x")
+	if keyStr == "" {
+		// an empty encoded key would make NewSoft generate a new one
+		return nil, ErrInvalidPrivateKeyLen
+	}
+	return LoadSoftFromBytes(networkID, []byte(keyStr))
+}
+
 // readASCII reads into 'buf', stopping when the buffer is full or
 // when a non-printable control character is encountered.
 func readASCII(buf []byte, r io.ByteReader) (n int, err error) {
@@ -0,0 +1,51 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package key
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MetalBlockchain/metalgo/utils/crypto/secp256k1"
+	eth_crypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// MaxVanityPrefixLen bounds the vanity search, as each hex char of the prefix
+// makes it 16 times longer
+const MaxVanityPrefixLen = 6
+
+// NormalizeVanityPrefix validates [prefix] as a C-Chain address prefix, with
+// or without 0x, and returns it lowercased without 0x
+func NormalizeVanityPrefix(prefix string) (string, error) {
+	prefix = strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(prefix, "0x"), "0X"))
+	if prefix == "" {
+		return "", errors.New("empty vanity prefix")
+	}
+	if len(prefix) > MaxVanityPrefixLen {
+		return "", fmt.Errorf("vanity prefix %q is longer than %d hex chars", prefix, MaxVanityPrefixLen)
+	}
+	// pad to decode odd lengths
+	if _, err := hex.DecodeString(prefix + strings.Repeat("0", len(prefix)%2)); err != nil {
+		return "", fmt.Errorf("vanity prefix %q is not hex encoded", prefix)
+	}
+	return prefix, nil
+}
+
+// NewSoftWithCPrefix generates keys until one has a C-Chain address starting
+// with the normalized [prefix], and returns it together with the amount of
+// keys generated
+func NewSoftWithCPrefix(networkID uint32, prefix string) (*SoftKey, int, error) {
+	for attempts := 1; ; attempts++ {
+		privKey, err := secp256k1.NewPrivateKey()
+		if err != nil {
+			return nil, attempts, err
+		}
+		addr := eth_crypto.PubkeyToAddress(privKey.ToECDSA().PublicKey)
+		if strings.HasPrefix(hex.EncodeToString(addr.Bytes()), prefix) {
+			k, err := NewSoft(networkID, WithPrivateKey(privKey))
+			return k, attempts, err
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package key
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeVanityPrefix(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		prefix   string
+		expected string
+		err      bool
+	}{
+		{prefix: "0xABC", expected: "abc"},
+		{prefix: "dead", expected: "dead"},
+		{prefix: "0x", err: true},
+		{prefix: "xyz", err: true},
+		{prefix: "1234567", err: true},
+	}
+	for _, tv := range tt {
+		prefix, err := NormalizeVanityPrefix(tv.prefix)
+		if tv.err {
+			if err == nil {
+				t.Fatalf("%q: expected error", tv.prefix)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%q: unexpected error %v", tv.prefix, err)
+		}
+		if prefix != tv.expected {
+			t.Fatalf("%q: expected %q, got %q", tv.prefix, tv.expected, prefix)
+		}
+	}
+}
+
+func TestNewSoftWithCPrefix(t *testing.T) {
+	t.Parallel()
+
+	k, attempts, err := NewSoftWithCPrefix(fallbackNetworkID, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempts < 1 {
+		t.Fatalf("unexpected attempts %d", attempts)
+	}
+	if !strings.HasPrefix(strings.ToLower(k.C()), "0xa") {
+		t.Fatalf("address %s doesn't match the prefix", k.C())
+	}
+}
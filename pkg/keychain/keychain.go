@@ -8,7 +8,9 @@ import (
 
 	"github.com/MetalBlockchain/metal-cli/cmd/flags"
 	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
 	"github.com/MetalBlockchain/metal-cli/pkg/key"
+	"github.com/MetalBlockchain/metal-cli/pkg/kms"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
 	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
 	"github.com/MetalBlockchain/metal-cli/pkg/utils"
@@ -29,10 +31,11 @@ const (
 )
 
 var (
-	ErrMutuallyExlusiveKeySource = errors.New("key source flags --key, --ewoq, --ledger/--ledger-addrs are mutually exclusive")
+	ErrMutuallyExlusiveKeySource = errors.New("key source flags --key, --ewoq, --ledger/--ledger-addrs, --signer are mutually exclusive")
 	ErrStoredKeyOrEwoqOnMainnet  = errors.New("key sources --key, --ewoq are not available for mainnet operations")
 	ErrNonEwoqKeyOnDevnet        = errors.New("key source --ewoq is the only one available for devnet operations")
 	ErrEwoqKeyOnFuji             = errors.New("key source --ewoq is not available for fuji operations")
+	ErrKeyPolicyViolation        = errors.New("key used outside its policy")
 )
 
 type Keychain struct {
@@ -109,11 +112,13 @@ func (kc *Keychain) AddAddresses(addresses []string) error {
 func GetKeychainFromCmdLineFlags(
 	app *application.Avalanche,
 	keychainGoal string,
+	role string,
 	network models.Network,
 	keyName string,
 	useEwoq bool,
 	useLedger bool,
 	ledgerAddresses []string,
+	signerSpec string,
 	requiredFunds uint64,
 ) (*Keychain, error) {
 	// set ledger usage flag if ledger addresses are given
@@ -122,7 +127,7 @@ func GetKeychainFromCmdLineFlags(
 	}
 
 	// check mutually exclusive flags
-	if !flags.EnsureMutuallyExclusive([]bool{useLedger, useEwoq, keyName != ""}) {
+	if !flags.EnsureMutuallyExclusive([]bool{useLedger, useEwoq, keyName != "", signerSpec != ""}) {
 		return nil, ErrMutuallyExlusiveKeySource
 	}
 
@@ -130,15 +135,23 @@ func GetKeychainFromCmdLineFlags(
 	case network.Kind == models.Devnet:
 		// going to just use ewoq atm
 		useEwoq = true
-		if keyName != "" || useLedger {
+		if keyName != "" || useLedger || signerSpec != "" {
 			return nil, ErrNonEwoqKeyOnDevnet
 		}
 	case network.Kind == models.Tahoe:
 		if useEwoq {
 			return nil, ErrEwoqKeyOnFuji
 		}
+		// use the default key of the CLI config, if any, when no key source was provided
+		// by flag or env
+		if !useLedger && keyName == "" && signerSpec == "" && !keySourceInEnv() {
+			if defaultKey := app.Conf.GetConfigStringValue(constants.ConfigDefaultKeyKey); defaultKey != "" {
+				ux.Logger.PrintToUser("Using default key %s", defaultKey)
+				keyName = defaultKey
+			}
+		}
 		// prompt the user if no key source was provided
-		if !useLedger && keyName == "" {
+		if !useLedger && keyName == "" && signerSpec == "" {
 			var err error
 			useLedger, keyName, err = prompts.GetFujiKeyOrLedger(app.Prompt, keychainGoal, app.GetKeyDir())
 			if err != nil {
@@ -146,37 +159,68 @@ func GetKeychainFromCmdLineFlags(
 			}
 		}
 	case network.Kind == models.Mainnet:
-		// mainnet requires ledger usage
+		// mainnet requires ledger or KMS usage
 		if keyName != "" || useEwoq {
 			return nil, ErrStoredKeyOrEwoqOnMainnet
 		}
-		useLedger = true
+		useLedger = signerSpec == ""
 	}
 
 	network.HandlePublicNetworkSimulation()
 
 	// get keychain accessor
-	return GetKeychain(app, useEwoq, useLedger, ledgerAddresses, keyName, network, requiredFunds)
+	return GetKeychain(app, role, useEwoq, useLedger, ledgerAddresses, signerSpec, keyName, network, requiredFunds)
 }
 
 func GetKeychain(
 	app *application.Avalanche,
+	role string,
 	useEwoq bool,
 	useLedger bool,
 	ledgerAddresses []string,
+	signerSpec string,
 	keyName string,
 	network models.Network,
 	requiredFunds uint64,
 ) (*Keychain, error) {
 	// get keychain accessor
+	if signerSpec != "" {
+		if err := CheckKeyPolicy(app, signerSpec, role, network); err != nil {
+			return nil, err
+		}
+		signer, err := kms.NewSignerFromSpec(signerSpec)
+		if err != nil {
+			return nil, err
+		}
+		addr, err := address.Format("P", key.GetHRP(network.ID), signer.Address().Bytes())
+		if err != nil {
+			return nil, err
+		}
+		ux.Logger.PrintToUser("Using KMS signer %s with address %s", signerSpec, addr)
+		return NewKeychain(network, signer, nil, nil), nil
+	}
 	if useLedger {
 		ledgerDevice, err := ledger.New()
 		if err != nil {
 			return nil, err
 		}
+		selectedIndex, selected, err := app.GetLedgerIndex()
+		if err != nil {
+			return nil, err
+		}
 		// always have index 0, for change
 		ledgerIndices := []uint32{0}
-		if requiredFunds > 0 {
+		switch {
+		case selected:
+			// the selected index pays, and gets the change, alone
+			ux.Logger.PrintToUser("Using selected ledger index %d", selectedIndex)
+			ledgerIndices = []uint32{selectedIndex}
+			if requiredFunds > 0 {
+				if err := checkLedgerIndexFunds(network, ledgerDevice, selectedIndex, requiredFunds); err != nil {
+					return nil, err
+				}
+			}
+		case requiredFunds > 0:
 			ledgerIndicesAux, err := searchForFundedLedgerIndices(network, ledgerDevice, requiredFunds)
 			if err != nil {
 				return nil, err
@@ -211,6 +255,9 @@ func GetKeychain(
 		kc := sf.KeyChain()
 		return NewKeychain(network, kc, nil, nil), nil
 	}
+	if err := CheckKeyPolicy(app, keyName, role, network); err != nil {
+		return nil, err
+	}
 	sf, err := key.LoadSoft(network.ID, app.GetKeyPath(keyName))
 	if err != nil {
 		return nil, err
@@ -219,6 +266,28 @@ func GetKeychain(
 	return NewKeychain(network, kc, nil, nil), nil
 }
 
+// CheckKeyPolicy warns when stored key or KMS signer [keyName] is used for [role]
+// on [network] outside the roles and networks given to it with 'metal key role'.
+// If the policy mode is refuse, it fails instead
+func CheckKeyPolicy(app *application.Avalanche, keyName string, role string, network models.Network) error {
+	if keyName == "" || role == "" {
+		return nil
+	}
+	keyPolicy, err := app.LoadKeyPolicy()
+	if err != nil {
+		return err
+	}
+	violation := keyPolicy.Violation(keyName, role, network)
+	if violation == "" {
+		return nil
+	}
+	if keyPolicy.Refuses() {
+		return fmt.Errorf("%w: %s. Use 'metal key role set %s' to change its policy", ErrKeyPolicyViolation, violation, keyName)
+	}
+	ux.Logger.PrintToUser(logging.Yellow.Wrap("Warning: %s"), violation)
+	return nil
+}
+
 func getLedgerIndices(ledgerDevice keychain.Ledger, addressesStr []string) ([]uint32, error) {
 	addresses, err := address.ParseToIDs(addressesStr)
 	if err != nil {
@@ -289,6 +358,30 @@ func searchForFundedLedgerIndices(network models.Network, ledgerDevice keychain.
 	return ledgerIndices, nil
 }
 
+// checkLedgerIndexFunds fails if the P-Chain balance of [ledgerIndex] is less than [amount]
+func checkLedgerIndexFunds(network models.Network, ledgerDevice keychain.Ledger, ledgerIndex uint32, amount uint64) error {
+	ledgerAddress, err := ledgerDevice.Addresses([]uint32{ledgerIndex})
+	if err != nil {
+		return err
+	}
+	pClient := platformvm.NewClient(network.Endpoint)
+	ctx, cancel := utils.GetAPIContext()
+	resp, err := pClient.GetBalance(ctx, ledgerAddress)
+	cancel()
+	if err != nil {
+		return err
+	}
+	if uint64(resp.Balance) < amount {
+		return fmt.Errorf(
+			"ledger index %d has %.9f AVAX, less than the required %.9f AVAX. Select another index with 'metal key ledger use'",
+			ledgerIndex,
+			float64(resp.Balance)/float64(units.Avax),
+			float64(amount)/float64(units.Avax),
+		)
+	}
+	return nil
+}
+
 func showLedgerAddresses(network models.Network, ledgerDevice keychain.Ledger, ledgerIndices []uint32) error {
 	// get formatted addresses for ux
 	addresses, err := ledgerDevice.Addresses(ledgerIndices)
@@ -309,3 +402,9 @@ func showLedgerAddresses(network models.Network, ledgerDevice keychain.Ledger, l
 	}
 	return nil
 }
+
+func keySourceInEnv() bool {
+	_, keyInEnv := prompts.EnvValue(prompts.KeyEnvVarName)
+	_, ledgerInEnv := prompts.EnvValue(prompts.LedgerEnvVarName)
+	return keyInEnv || ledgerInEnv
+}
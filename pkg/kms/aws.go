@@ -0,0 +1,75 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package kms
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	awskms "github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/kms/types"
+)
+
+// AWSBackend signs with an AWS KMS key of spec ECC_SECG_P256K1
+type AWSBackend struct {
+	client *awskms.Client
+	keyID  string
+}
+
+// NewAWSBackend returns the backend for AWS KMS key [keyID], that may be a key
+// ID, a key ARN, an alias name (alias/...) or an alias ARN. Credentials, profile
+// and region are taken from the standard AWS env and config files, except that
+// the region of an ARN takes precedence
+func NewAWSBackend(ctx context.Context, keyID string) (*AWSBackend, error) {
+	opts := []func(*config.LoadOptions) error{}
+	if region := arnRegion(keyID); region != "" {
+		opts = append(opts, config.WithRegion(region))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &AWSBackend{
+		client: awskms.NewFromConfig(cfg),
+		keyID:  keyID,
+	}, nil
+}
+
+func (b *AWSBackend) PublicKey(ctx context.Context) ([]byte, error) {
+	out, err := b.client.GetPublicKey(ctx, &awskms.GetPublicKeyInput{
+		KeyId: aws.String(b.keyID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out.KeySpec != types.KeySpecEccSecgP256k1 {
+		return nil, fmt.Errorf("AWS KMS key %s has spec %s, expected %s", b.keyID, out.KeySpec, types.KeySpecEccSecgP256k1)
+	}
+	return out.PublicKey, nil
+}
+
+func (b *AWSBackend) SignDigest(ctx context.Context, digest []byte) ([]byte, error) {
+	out, err := b.client.Sign(ctx, &awskms.SignInput{
+		KeyId:            aws.String(b.keyID),
+		Message:          digest,
+		MessageType:      types.MessageTypeDigest,
+		SigningAlgorithm: types.SigningAlgorithmSpecEcdsaSha256,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Signature, nil
+}
+
+// arnRegion returns the region of [keyID] if it is an ARN, as in
+// arn:aws:kms:us-east-1:111122223333:alias/subnet-owner
+func arnRegion(keyID string) string {
+	parts := strings.Split(keyID, ":")
+	if len(parts) < 6 || parts[0] != "arn" {
+		return ""
+	}
+	return parts[3]
+}
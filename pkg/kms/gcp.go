@@ -0,0 +1,57 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package kms
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"google.golang.org/api/cloudkms/v1"
+)
+
+const gcpSecp256k1Algorithm = "EC_SIGN_SECP256K1_SHA256"
+
+// GCPBackend signs with a GCP Cloud KMS key version of algorithm
+// EC_SIGN_SECP256K1_SHA256
+type GCPBackend struct {
+	versions *cloudkms.ProjectsLocationsKeyRingsCryptoKeysCryptoKeyVersionsService
+	name     string
+}
+
+// NewGCPBackend returns the backend for GCP key version [name], as in
+// projects/p/locations/l/keyRings/r/cryptoKeys/k/cryptoKeyVersions/1. Credentials
+// are taken from the application default credentials
+func NewGCPBackend(ctx context.Context, name string) (*GCPBackend, error) {
+	service, err := cloudkms.NewService(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &GCPBackend{
+		versions: service.Projects.Locations.KeyRings.CryptoKeys.CryptoKeyVersions,
+		name:     name,
+	}, nil
+}
+
+func (b *GCPBackend) PublicKey(ctx context.Context) ([]byte, error) {
+	pubKey, err := b.versions.GetPublicKey(b.name).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	if pubKey.Algorithm != gcpSecp256k1Algorithm {
+		return nil, fmt.Errorf("GCP KMS key %s has algorithm %s, expected %s", b.name, pubKey.Algorithm, gcpSecp256k1Algorithm)
+	}
+	return pemToDER(pubKey.Pem)
+}
+
+func (b *GCPBackend) SignDigest(ctx context.Context, digest []byte) ([]byte, error) {
+	resp, err := b.versions.AsymmetricSign(b.name, &cloudkms.AsymmetricSignRequest{
+		Digest: &cloudkms.Digest{
+			Sha256: base64.StdEncoding.EncodeToString(digest),
+		},
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Signature)
+}
@@ -0,0 +1,214 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package kms
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/keychain"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/secp256k1"
+	"github.com/MetalBlockchain/metalgo/utils/hashing"
+	"github.com/MetalBlockchain/metalgo/utils/set"
+	decredsecp256k1 "github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// Signer spec schemes, as in kms:alias/subnet-owner
+const (
+	// AWSScheme selects an AWS KMS key, given by key ID, ARN or alias
+	AWSScheme = "kms"
+	// GCPScheme selects a GCP Cloud KMS key version, given by resource name
+	GCPScheme = "gcpkms"
+	// VaultScheme selects a HashiCorp Vault transit key, given as <mount>/<key>
+	VaultScheme = "vault"
+)
+
+var (
+	_ keychain.Keychain = (*Signer)(nil)
+	_ keychain.Signer   = (*Signer)(nil)
+
+	oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidSecp256k1      = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+
+	ErrInvalidSignerSpec = errors.New("invalid signer")
+	ErrNotSecp256k1Key   = errors.New("not a secp256k1 key")
+)
+
+// Backend is a key kept by a key management service, that signs digests without
+// ever exposing the private key
+type Backend interface {
+	// PublicKey returns the DER encoded SubjectPublicKeyInfo of the key
+	PublicKey(ctx context.Context) ([]byte, error)
+	// SignDigest returns the DER encoded ECDSA signature of the sha256 [digest]
+	SignDigest(ctx context.Context, digest []byte) ([]byte, error)
+}
+
+// Schemes returns the signer spec schemes
+func Schemes() []string {
+	return []string{AWSScheme, GCPScheme, VaultScheme}
+}
+
+// NewBackend returns the backend for signer [spec], given as <scheme>:<key>
+func NewBackend(ctx context.Context, spec string) (Backend, error) {
+	scheme, keyID, found := strings.Cut(spec, ":")
+	if !found || keyID == "" {
+		return nil, fmt.Errorf("%w %q: expected <scheme>:<key>, with scheme one of %s", ErrInvalidSignerSpec, spec, strings.Join(Schemes(), ", "))
+	}
+	switch scheme {
+	case AWSScheme:
+		return NewAWSBackend(ctx, keyID)
+	case GCPScheme:
+		return NewGCPBackend(ctx, keyID)
+	case VaultScheme:
+		return NewVaultBackend(keyID)
+	}
+	return nil, fmt.Errorf("%w %q: unknown scheme %q, use one of %s", ErrInvalidSignerSpec, spec, scheme, strings.Join(Schemes(), ", "))
+}
+
+// Signer signs P-Chain and X-Chain transactions with a secp256k1 key kept by a
+// key management service. It is a keychain holding that single key
+type Signer struct {
+	backend Backend
+	pubKey  *secp256k1.PublicKey
+}
+
+// NewSigner returns a signer for the key of [backend], failing if it is not a
+// secp256k1 key
+func NewSigner(ctx context.Context, backend Backend) (*Signer, error) {
+	der, err := backend.PublicKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the KMS public key: %w", err)
+	}
+	pubKey, err := parsePublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{
+		backend: backend,
+		pubKey:  pubKey,
+	}, nil
+}
+
+// NewSignerFromSpec returns a signer for signer [spec], given as <scheme>:<key>
+func NewSignerFromSpec(spec string) (*Signer, error) {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	backend, err := NewBackend(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+	return NewSigner(ctx, backend)
+}
+
+func (s *Signer) PublicKey() *secp256k1.PublicKey {
+	return s.pubKey
+}
+
+func (s *Signer) Address() ids.ShortID {
+	return s.pubKey.Address()
+}
+
+func (s *Signer) Sign(msg []byte) ([]byte, error) {
+	return s.SignHash(hashing.ComputeHash256(msg))
+}
+
+// SignHash returns the recoverable [r || s || v] signature of [hash], as created
+// by a local secp256k1 key
+func (s *Signer) SignHash(hash []byte) ([]byte, error) {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	der, err := s.backend.SignDigest(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("KMS signing failed: %w", err)
+	}
+	return toRecoverableSignature(s.pubKey, hash, der)
+}
+
+func (s *Signer) Get(addr ids.ShortID) (keychain.Signer, bool) {
+	if addr != s.Address() {
+		return nil, false
+	}
+	return s, true
+}
+
+func (s *Signer) Addresses() set.Set[ids.ShortID] {
+	return set.Of(s.Address())
+}
+
+// parsePublicKey parses a DER encoded SubjectPublicKeyInfo holding a secp256k1 key.
+// x509.ParsePKIXPublicKey can't be used, as it doesn't support the curve
+func parsePublicKey(der []byte) (*secp256k1.PublicKey, error) {
+	var info struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if rest, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	} else if len(rest) != 0 {
+		return nil, errors.New("invalid public key: trailing data")
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPublicKeyECDSA) {
+		return nil, ErrNotSecp256k1Key
+	}
+	var curve asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &curve); err != nil || !curve.Equal(oidSecp256k1) {
+		return nil, ErrNotSecp256k1Key
+	}
+	pubKey, err := decredsecp256k1.ParsePubKey(info.PublicKey.RightAlign())
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	return secp256k1.ToPublicKey(pubKey.SerializeCompressed())
+}
+
+// pemToDER returns the DER content of PEM block [pemStr]
+func pemToDER(pemStr string) ([]byte, error) {
+	block, _ := pem.Decode([]byte(pemStr))
+	if block == nil {
+		return nil, errors.New("invalid PEM public key")
+	}
+	return block.Bytes, nil
+}
+
+// toRecoverableSignature converts the DER encoded ECDSA signature [der] of [hash]
+// into the [r || s || v] format, with low S, used by transaction credentials.
+// KMS services don't return the recovery ID, so it is found by checking which
+// one recovers [pubKey]
+func toRecoverableSignature(pubKey *secp256k1.PublicKey, hash []byte, der []byte) ([]byte, error) {
+	var sig struct {
+		R, S *big.Int
+	}
+	if rest, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, fmt.Errorf("invalid KMS signature: %w", err)
+	} else if len(rest) != 0 {
+		return nil, errors.New("invalid KMS signature: trailing data")
+	}
+	var r, s decredsecp256k1.ModNScalar
+	if sig.R.Sign() <= 0 || sig.S.Sign() <= 0 || sig.R.BitLen() > 256 || sig.S.BitLen() > 256 ||
+		r.SetByteSlice(sig.R.Bytes()) || s.SetByteSlice(sig.S.Bytes()) {
+		return nil, errors.New("invalid KMS signature: values out of range")
+	}
+	// both S and N-S are valid, but only the low one is accepted
+	if s.IsOverHalfOrder() {
+		s.Negate()
+	}
+	recoverable := make([]byte, secp256k1.SignatureLen)
+	r.PutBytesUnchecked(recoverable[:32])
+	s.PutBytesUnchecked(recoverable[32:64])
+	for v := byte(0); v < 2; v++ {
+		recoverable[secp256k1.SignatureLen-1] = v
+		recovered, err := secp256k1.RecoverPublicKeyFromHash(hash, recoverable)
+		if err == nil && recovered.Address() == pubKey.Address() {
+			return recoverable, nil
+		}
+	}
+	return nil, errors.New("KMS signature doesn't match the KMS public key")
+}
@@ -0,0 +1,199 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package kms
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/secp256k1"
+	decredsecp256k1 "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	decredecdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/stretchr/testify/require"
+)
+
+// localBackend is a KMS backend holding its key in memory
+type localBackend struct {
+	key *decredsecp256k1.PrivateKey
+	// highS makes signatures use the high S value, as KMS services may do
+	highS bool
+}
+
+func newLocalBackend(t *testing.T, highS bool) (*localBackend, *secp256k1.PrivateKey) {
+	key, err := secp256k1.NewPrivateKey()
+	require.NoError(t, err)
+	return &localBackend{key: decredsecp256k1.PrivKeyFromBytes(key.Bytes()), highS: highS}, key
+}
+
+func (b *localBackend) PublicKey(context.Context) ([]byte, error) {
+	return marshalPublicKey(b.key.PubKey())
+}
+
+func (b *localBackend) SignDigest(_ context.Context, digest []byte) ([]byte, error) {
+	der := decredecdsa.Sign(b.key, digest).Serialize()
+	if !b.highS {
+		return der, nil
+	}
+	var sig struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, err
+	}
+	sig.S.Sub(decredsecp256k1.S256().N, sig.S)
+	return asn1.Marshal(sig)
+}
+
+func marshalPublicKey(pubKey *decredsecp256k1.PublicKey) ([]byte, error) {
+	curve, err := asn1.Marshal(oidSecp256k1)
+	if err != nil {
+		return nil, err
+	}
+	bs := pubKey.SerializeUncompressed()
+	return asn1.Marshal(struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  oidPublicKeyECDSA,
+			Parameters: asn1.RawValue{FullBytes: curve},
+		},
+		PublicKey: asn1.BitString{Bytes: bs, BitLength: 8 * len(bs)},
+	})
+}
+
+func TestSignerSign(t *testing.T) {
+	for _, highS := range []bool{false, true} {
+		require := require.New(t)
+		backend, key := newLocalBackend(t, highS)
+		signer, err := NewSigner(context.Background(), backend)
+		require.NoError(err)
+		require.Equal(key.Address(), signer.Address())
+
+		msg := []byte("unsigned tx bytes")
+		sig, err := signer.Sign(msg)
+		require.NoError(err)
+		// the same signature a local key gives
+		expectedSig, err := key.Sign(msg)
+		require.NoError(err)
+		require.Equal(expectedSig, sig)
+		recovered, err := secp256k1.RecoverPublicKey(msg, sig)
+		require.NoError(err)
+		require.Equal(key.Address(), recovered.Address())
+	}
+}
+
+func TestSignerKeychain(t *testing.T) {
+	require := require.New(t)
+	backend, key := newLocalBackend(t, false)
+	signer, err := NewSigner(context.Background(), backend)
+	require.NoError(err)
+	require.Equal([]ids.ShortID{key.Address()}, signer.Addresses().List())
+	kcSigner, ok := signer.Get(key.Address())
+	require.True(ok)
+	require.Equal(key.Address(), kcSigner.Address())
+	_, ok = signer.Get(ids.GenerateTestShortID())
+	require.False(ok)
+}
+
+type p256Backend struct {
+	localBackend
+}
+
+func (*p256Backend) PublicKey(context.Context) ([]byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return x509.MarshalPKIXPublicKey(&key.PublicKey)
+}
+
+func TestNewSignerNotSecp256k1(t *testing.T) {
+	_, err := NewSigner(context.Background(), &p256Backend{})
+	require.ErrorIs(t, err, ErrNotSecp256k1Key)
+}
+
+func TestNewBackendInvalidSpec(t *testing.T) {
+	for _, spec := range []string{"alias/subnet-owner", "kms:", "hsm:key", "vault:key"} {
+		_, err := NewBackend(context.Background(), spec)
+		require.ErrorIs(t, err, ErrInvalidSignerSpec, spec)
+	}
+}
+
+func TestVaultBackend(t *testing.T) {
+	require := require.New(t)
+	backend, key := newLocalBackend(t, true)
+	der, err := backend.PublicKey(context.Background())
+	require.NoError(err)
+	pubKeyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		var resp interface{}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/transit/keys/subnet-owner":
+			resp = map[string]interface{}{
+				"data": map[string]interface{}{
+					"latest_version": 2,
+					"keys": map[string]interface{}{
+						"2": map[string]string{"public_key": pubKeyPEM},
+					},
+				},
+			}
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/transit/sign/subnet-owner/sha2-256":
+			var req struct {
+				Input     string `json:"input"`
+				Prehashed bool   `json:"prehashed"`
+			}
+			require.NoError(json.NewDecoder(r.Body).Decode(&req))
+			require.True(req.Prehashed)
+			digest, err := base64.StdEncoding.DecodeString(req.Input)
+			require.NoError(err)
+			sig, err := backend.SignDigest(r.Context(), digest)
+			require.NoError(err)
+			resp = map[string]interface{}{
+				"data": map[string]string{"signature": "vault:v2:" + base64.StdEncoding.EncodeToString(sig)},
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		require.NoError(json.NewEncoder(w).Encode(resp))
+	}))
+	defer server.Close()
+
+	t.Setenv(VaultAddrEnvVarName, server.URL)
+	t.Setenv(VaultTokenEnvVarName, "token")
+	vaultBackend, err := NewBackend(context.Background(), "vault:transit/subnet-owner")
+	require.NoError(err)
+	signer, err := NewSigner(context.Background(), vaultBackend)
+	require.NoError(err)
+	require.Equal(key.Address(), signer.Address())
+	msg := []byte("unsigned tx bytes")
+	sig, err := signer.Sign(msg)
+	require.NoError(err)
+	recovered, err := secp256k1.RecoverPublicKey(msg, sig)
+	require.NoError(err)
+	require.Equal(key.Address(), recovered.Address())
+
+	t.Setenv(VaultTokenEnvVarName, "other")
+	vaultBackend, err = NewBackend(context.Background(), "vault:transit/subnet-owner")
+	require.NoError(err)
+	_, err = NewSigner(context.Background(), vaultBackend)
+	require.ErrorContains(err, "permission denied")
+}
@@ -0,0 +1,143 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package kms
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	VaultAddrEnvVarName      = "VAULT_ADDR"
+	VaultTokenEnvVarName     = "VAULT_TOKEN"
+	VaultNamespaceEnvVarName = "VAULT_NAMESPACE"
+)
+
+// VaultBackend signs with a secp256k1 key of a HashiCorp Vault transit secrets
+// engine, or of a compatible one, through the Vault HTTP API
+type VaultBackend struct {
+	addr      string
+	token     string
+	namespace string
+	mount     string
+	key       string
+}
+
+// NewVaultBackend returns the backend for transit key [mountAndKey], given as
+// <mount>/<key>. The Vault address and token are taken from VAULT_ADDR and
+// VAULT_TOKEN, and the namespace, if any, from VAULT_NAMESPACE
+func NewVaultBackend(mountAndKey string) (*VaultBackend, error) {
+	i := strings.LastIndex(mountAndKey, "/")
+	if i <= 0 || i == len(mountAndKey)-1 {
+		return nil, fmt.Errorf("%w %q: expected vault:<mount>/<key>", ErrInvalidSignerSpec, mountAndKey)
+	}
+	addr := os.Getenv(VaultAddrEnvVarName)
+	if addr == "" {
+		return nil, fmt.Errorf("%s must be set to use a vault signer", VaultAddrEnvVarName)
+	}
+	token := os.Getenv(VaultTokenEnvVarName)
+	if token == "" {
+		return nil, fmt.Errorf("%s must be set to use a vault signer", VaultTokenEnvVarName)
+	}
+	return &VaultBackend{
+		addr:      strings.TrimSuffix(addr, "/"),
+		token:     token,
+		namespace: os.Getenv(VaultNamespaceEnvVarName),
+		mount:     mountAndKey[:i],
+		key:       mountAndKey[i+1:],
+	}, nil
+}
+
+func (b *VaultBackend) PublicKey(ctx context.Context) ([]byte, error) {
+	var resp struct {
+		Data struct {
+			LatestVersion int `json:"latest_version"`
+			Keys          map[string]struct {
+				PublicKey string `json:"public_key"`
+			} `json:"keys"`
+		} `json:"data"`
+	}
+	if err := b.do(ctx, http.MethodGet, fmt.Sprintf("%s/keys/%s", b.mount, b.key), nil, &resp); err != nil {
+		return nil, err
+	}
+	version, ok := resp.Data.Keys[strconv.Itoa(resp.Data.LatestVersion)]
+	if !ok || version.PublicKey == "" {
+		return nil, fmt.Errorf("vault key %s/%s has no public key", b.mount, b.key)
+	}
+	return pemToDER(version.PublicKey)
+}
+
+func (b *VaultBackend) SignDigest(ctx context.Context, digest []byte) ([]byte, error) {
+	req := map[string]interface{}{
+		"input":                base64.StdEncoding.EncodeToString(digest),
+		"prehashed":            true,
+		"marshaling_algorithm": "asn1",
+	}
+	var resp struct {
+		Data struct {
+			Signature string `json:"signature"`
+		} `json:"data"`
+	}
+	if err := b.do(ctx, http.MethodPost, fmt.Sprintf("%s/sign/%s/sha2-256", b.mount, b.key), req, &resp); err != nil {
+		return nil, err
+	}
+	// signatures are given as vault:v<version>:<base64 signature>
+	parts := strings.Split(resp.Data.Signature, ":")
+	if len(parts) != 3 || parts[0] != "vault" {
+		return nil, fmt.Errorf("unexpected vault signature format %q", resp.Data.Signature)
+	}
+	return base64.StdEncoding.DecodeString(parts[2])
+}
+
+// do calls Vault API [path] with JSON body [req], decoding the JSON response into [resp]
+func (b *VaultBackend) do(ctx context.Context, method string, path string, req interface{}, resp interface{}) error {
+	var body io.Reader
+	if req != nil {
+		bs, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(bs)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/v1/%s", b.addr, path), body)
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("X-Vault-Token", b.token)
+	if b.namespace != "" {
+		httpReq.Header.Set("X-Vault-Namespace", b.namespace)
+	}
+	if req != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	httpResp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+	bs, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return err
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		var vaultErr struct {
+			Errors []string `json:"errors"`
+		}
+		if json.Unmarshal(bs, &vaultErr) == nil && len(vaultErr.Errors) > 0 {
+			return fmt.Errorf("vault request failed with status %d: %s", httpResp.StatusCode, strings.Join(vaultErr.Errors, "; "))
+		}
+		return fmt.Errorf("vault request failed with status %d", httpResp.StatusCode)
+	}
+	if err := json.Unmarshal(bs, resp); err != nil {
+		return fmt.Errorf("invalid vault response: %w", err)
+	}
+	return nil
+}
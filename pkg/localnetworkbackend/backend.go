@@ -0,0 +1,429 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package localnetworkbackend
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metal-network-runner/local"
+	anrutils "github.com/MetalBlockchain/metal-network-runner/utils"
+	"github.com/MetalBlockchain/metalgo/api/health"
+	"github.com/MetalBlockchain/metalgo/ids"
+)
+
+// Backend names, as given to network start --backend
+const (
+	ANR    = "anr"
+	Docker = "docker"
+	K8s    = "k8s"
+)
+
+const (
+	// MetalGoImage is the docker image run by the container based backends
+	MetalGoImage = "metalblockchain/metalgo"
+	// metalGoBinary is the metalgo binary path, relative to the image workdir
+	metalGoBinary = "./metalgo"
+
+	genesisFileName    = "genesis.json"
+	cChainConfigPath   = "chainConfigs/C/config.json"
+	stakingKeyFileName = "staking.key"
+	stakingCrtFileName = "staking.crt"
+	signerKeyFileName  = "signer.key"
+	nodeDirPrefix      = "node"
+	healthPollInterval = constants.LocalNetworkStartupPollInterval
+	healthQueryTimeout = 5 * time.Second
+)
+
+// currentFileName records, into the run dir of a local network, the backend
+// running it
+const currentFileName = "backend"
+
+// Names returns the names of the supported backends
+func Names() []string {
+	return []string{ANR, Docker, K8s}
+}
+
+// CurrentName returns the backend the local network with run dir [runDir] was
+// last started with, or the default one
+func CurrentName(runDir string) string {
+	bs, err := os.ReadFile(filepath.Join(runDir, currentFileName))
+	if err != nil {
+		return ANR
+	}
+	return strings.TrimSpace(string(bs))
+}
+
+// SetCurrentName records [name] as the backend of the local network with run
+// dir [runDir], or removes the record for the default one
+func SetCurrentName(runDir string, name string) error {
+	path := filepath.Join(runDir, currentFileName)
+	if name == ANR {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(runDir, constants.DefaultPerms755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(name), constants.WriteReadReadPerms)
+}
+
+// Config describes the local network a backend runs
+type Config struct {
+	// NumNodes of the network. 0 means the amount of nodes of a previous start,
+	// or the default amount
+	NumNodes uint32
+	// MetalGoVersion is the image tag run by the container based backends
+	MetalGoVersion string
+	// ports of the first node, the next ones use the following ports, two by node
+	HTTPPort    int
+	StakingPort int
+	// HTTPHost is the address the node APIs listen on
+	HTTPHost string
+	// PluginDir holds the VM binaries
+	PluginDir string
+	// NetworkID of a new network. 0 means the network of the network runner
+	// default local network. Otherwise, a genesis with this network ID and new
+	// validators is generated
+	NetworkID uint32 `json:",omitempty"`
+	// TrackSubnets are the IDs of the subnets deployed on the network. Nil means
+	// the subnets tracked on the previous start
+	TrackSubnets []string `json:",omitempty"`
+}
+
+// NodeInfo describes a node of a local network
+type NodeInfo struct {
+	Name    string `json:"name"`
+	NodeID  string `json:"nodeID"`
+	URI     string `json:"uri"`
+	Healthy bool   `json:"healthy"`
+}
+
+// Backend runs the nodes of a local network. The network state is kept
+// between Stop and Start, and removed by Clean
+type Backend interface {
+	// Start starts the network, or just returns its nodes if already running,
+	// and waits until they are healthy
+	Start(ctx context.Context, config Config) ([]NodeInfo, error)
+	// Stop stops the network, keeping its state
+	Stop(ctx context.Context) error
+	// Status returns the nodes of the network, or none if it is not running
+	Status(ctx context.Context) ([]NodeInfo, error)
+	// Clean stops the network and removes its state
+	Clean(ctx context.Context) error
+}
+
+// ContainerBackend is a backend that runs the nodes in containers, and that
+// deploys subnets by restarting them with the VM installed and the subnet tracked
+type ContainerBackend interface {
+	Backend
+	// TrackSubnet installs [vmBin] as the VM [vmID] into the plugin dir, and
+	// restarts the nodes so they also track [subnetID]
+	TrackSubnet(ctx context.Context, subnetID ids.ID, vmID ids.ID, vmBin string) ([]NodeInfo, error)
+}
+
+// NewContainerBackend returns the container based backend [name] of the local
+// network [networkName], whose state is kept under the run dir [runDir]
+func NewContainerBackend(name string, runDir string, networkName string) (ContainerBackend, error) {
+	// container names must be unique across local networks
+	containerName := "metal-local"
+	if networkName != "" {
+		containerName += "-" + networkName
+	}
+	containerName = strings.ReplaceAll(strings.ToLower(containerName), " ", "-")
+	switch name {
+	case Docker:
+		return &dockerBackend{dir: filepath.Join(runDir, name), projectName: containerName}, nil
+	case K8s:
+		return &k8sBackend{dir: filepath.Join(runDir, name), clusterName: containerName}, nil
+	}
+	return nil, fmt.Errorf("%q is not a container based local network backend. Use one of %s, %s", name, Docker, K8s)
+}
+
+// node is a node of a local network run by a container based backend
+type node struct {
+	Name        string
+	NodeID      ids.NodeID
+	HTTPPort    int
+	StakingPort int
+	// HasSigner is false if the node has no BLS key file, so metalgo
+	// generates one into its data dir
+	HasSigner bool
+}
+
+// network is the static setup of a local network run by a container based
+// backend, persisted in its dir so that its state can be reused
+type network struct {
+	NetworkID uint32
+	Nodes     []node
+}
+
+// loadOrCreateNetwork reads the network setup from [dir], or writes a new one
+// based on the default local network of the network runner: same genesis,
+// staking keys and thus validators. Extra nodes get new staking keys
+func loadOrCreateNetwork(dir string, config Config) (network, error) {
+	nodeDirs, err := filepath.Glob(filepath.Join(dir, nodeDirPrefix+"*"))
+	if err != nil {
+		return network{}, err
+	}
+	numNodes := config.NumNodes
+	switch {
+	case len(nodeDirs) > 0 && numNodes != 0 && int(numNodes) != len(nodeDirs):
+		return network{}, fmt.Errorf(
+			"local network already has %d nodes, can't start it with %d. Use 'metal network clean' to start from scratch",
+			len(nodeDirs),
+			numNodes,
+		)
+	case len(nodeDirs) > 0:
+		numNodes = uint32(len(nodeDirs))
+	case numNodes == 0:
+		numNodes = constants.LocalNetworkNumNodes
+	}
+	if len(nodeDirs) == 0 {
+		if err := writeNetworkFiles(dir, numNodes, config.NetworkID); err != nil {
+			return network{}, err
+		}
+	}
+	genesis, err := os.ReadFile(filepath.Join(dir, genesisFileName))
+	if err != nil {
+		return network{}, err
+	}
+	networkID, err := anrutils.NetworkIDFromGenesis(genesis)
+	if err != nil {
+		return network{}, err
+	}
+	net := network{NetworkID: networkID}
+	for i := 0; i < int(numNodes); i++ {
+		nodeDir := filepath.Join(dir, nodeName(i))
+		stakingKey, err := os.ReadFile(filepath.Join(nodeDir, stakingKeyFileName))
+		if err != nil {
+			return network{}, err
+		}
+		stakingCrt, err := os.ReadFile(filepath.Join(nodeDir, stakingCrtFileName))
+		if err != nil {
+			return network{}, err
+		}
+		nodeID, err := anrutils.ToNodeID(stakingKey, stakingCrt)
+		if err != nil {
+			return network{}, err
+		}
+		net.Nodes = append(net.Nodes, node{
+			Name:        nodeName(i),
+			NodeID:      nodeID,
+			HTTPPort:    config.HTTPPort + 2*i,
+			StakingPort: config.StakingPort + 2*i,
+			HasSigner:   utils.FileExists(filepath.Join(nodeDir, signerKeyFileName)),
+		})
+	}
+	return net, nil
+}
+
+// writeNetworkFiles writes into [dir] the genesis, C-Chain config and node
+// staking files of a new network with [numNodes] nodes, and with network ID
+// [networkID] if not 0
+func writeNetworkFiles(dir string, numNodes uint32, networkID uint32) error {
+	netConfig, err := local.NewDefaultConfigNNodes("", numNodes)
+	if err != nil {
+		return err
+	}
+	if networkID != 0 {
+		if err := setNewValidators(&netConfig, networkID); err != nil {
+			return err
+		}
+	}
+	files := map[string][]byte{
+		genesisFileName:  []byte(netConfig.Genesis),
+		cChainConfigPath: []byte(netConfig.ChainConfigFiles["C"]),
+	}
+	for i, nodeConfig := range netConfig.NodeConfigs {
+		files[filepath.Join(nodeName(i), stakingKeyFileName)] = []byte(nodeConfig.StakingKey)
+		files[filepath.Join(nodeName(i), stakingCrtFileName)] = []byte(nodeConfig.StakingCert)
+		if nodeConfig.StakingSigningKey != "" {
+			signerKey, err := base64.StdEncoding.DecodeString(nodeConfig.StakingSigningKey)
+			if err != nil {
+				return err
+			}
+			files[filepath.Join(nodeName(i), signerKeyFileName)] = signerKey
+		}
+	}
+	for path, content := range files {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
+			return err
+		}
+		// readable by the container user
+		if err := os.WriteFile(path, content, constants.WriteReadReadPerms); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func nodeName(i int) string {
+	return fmt.Sprintf("%s%d", nodeDirPrefix, i+1)
+}
+
+// args returns the metalgo arguments of [n], given the dirs where its files
+// are mounted on its container
+func (n node) args(net network, config Config, configDir string, nodeDir string, dataDir string, pluginDir string) []string {
+	args := []string{
+		metalGoBinary,
+		fmt.Sprintf("--network-id=%d", net.NetworkID),
+		"--genesis-file=" + filepath.Join(configDir, genesisFileName),
+		"--chain-config-dir=" + filepath.Join(configDir, filepath.Dir(filepath.Dir(cChainConfigPath))),
+		"--public-ip=127.0.0.1",
+		fmt.Sprintf("--http-port=%d", n.HTTPPort),
+		"--http-host=" + config.HTTPHost,
+		fmt.Sprintf("--staking-port=%d", n.StakingPort),
+		"--staking-tls-key-file=" + filepath.Join(nodeDir, stakingKeyFileName),
+		"--staking-tls-cert-file=" + filepath.Join(nodeDir, stakingCrtFileName),
+		"--db-dir=" + filepath.Join(dataDir, "db"),
+		"--log-dir=" + filepath.Join(dataDir, "logs"),
+		"--plugin-dir=" + pluginDir,
+		"--health-check-frequency=2s",
+		"--network-max-reconnect-delay=1s",
+		"--api-admin-enabled=true",
+		"--index-enabled=true",
+		"--log-display-level=ERROR",
+	}
+	if len(config.TrackSubnets) > 0 {
+		args = append(args, "--track-subnets="+strings.Join(config.TrackSubnets, ","))
+	}
+	if n.HasSigner {
+		args = append(args, "--staking-signer-key-file="+filepath.Join(nodeDir, signerKeyFileName))
+	} else {
+		args = append(args, "--staking-signer-key-file="+filepath.Join(dataDir, signerKeyFileName))
+	}
+	// the default validators bootstrap from the first node, as the network runner does
+	if n.Name != net.Nodes[0].Name {
+		bootstrapper := net.Nodes[0]
+		args = append(args,
+			fmt.Sprintf("--bootstrap-ips=127.0.0.1:%d", bootstrapper.StakingPort),
+			"--bootstrap-ids="+bootstrapper.NodeID.String(),
+		)
+	}
+	return args
+}
+
+// uri returns the API endpoint of [n], as reached from the host
+func (n node) uri(config Config) string {
+	host := config.HTTPHost
+	if host == "" || host == "0.0.0.0" {
+		host = "127.0.0.1"
+	}
+	return fmt.Sprintf("http://%s:%d", host, n.HTTPPort)
+}
+
+// nodeInfos returns the info of the nodes of [net], querying their health in
+// parallel, so that a node still starting up doesn't delay the others
+func nodeInfos(ctx context.Context, net network, config Config) []NodeInfo {
+	infos := make([]NodeInfo, len(net.Nodes))
+	var wg sync.WaitGroup
+	for i, n := range net.Nodes {
+		infos[i] = NodeInfo{Name: n.Name, NodeID: n.NodeID.String(), URI: n.uri(config)}
+		wg.Add(1)
+		go func(info *NodeInfo) {
+			defer wg.Done()
+			resp, err := health.NewClient(info.URI).Health(ctx, nil)
+			info.Healthy = err == nil && resp.Healthy
+		}(&infos[i])
+	}
+	wg.Wait()
+	return infos
+}
+
+// waitForHealthy polls the nodes of [net] until all of them are healthy. Each
+// poll is bounded by healthQueryTimeout, so a node that doesn't answer yet
+// doesn't hold back the next poll
+func waitForHealthy(ctx context.Context, net network, config Config) ([]NodeInfo, error) {
+	for {
+		pollCtx, cancel := context.WithTimeout(ctx, healthQueryTimeout)
+		infos := nodeInfos(pollCtx, net, config)
+		cancel()
+		unhealthy := []string{}
+		for _, info := range infos {
+			if !info.Healthy {
+				unhealthy = append(unhealthy, info.Name)
+			}
+		}
+		if len(unhealthy) == 0 {
+			return infos, nil
+		}
+		select {
+		case <-ctx.Done():
+			sort.Strings(unhealthy)
+			return nil, fmt.Errorf("nodes %s not healthy: %w", strings.Join(unhealthy, ", "), ctx.Err())
+		case <-time.After(healthPollInterval):
+		}
+	}
+}
+
+// configFileName keeps the config of the last start, so that Status and Stop
+// know the node ports
+const configFileName = "config.json"
+
+func saveConfig(dir string, config Config) error {
+	bs, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, configFileName), bs, constants.WriteReadReadPerms)
+}
+
+// keepTrackedSubnets returns [config] tracking the subnets of the previous start
+// at [dir], unless it sets its own, so that restarts keep running the deployed subnets
+func keepTrackedSubnets(dir string, config Config) Config {
+	if config.TrackSubnets != nil {
+		return config
+	}
+	if prevConfig, err := loadConfig(dir); err == nil {
+		config.TrackSubnets = prevConfig.TrackSubnets
+	}
+	return config
+}
+
+func loadConfig(dir string) (Config, error) {
+	config := Config{}
+	bs, err := os.ReadFile(filepath.Join(dir, configFileName))
+	if err != nil {
+		return config, err
+	}
+	return config, json.Unmarshal(bs, &config)
+}
+
+// installed tells if command [name] is available. Without it, a backend can't
+// have started the network
+func installed(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+// runCommand runs [name] with [args], returning its output on failure
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if _, err := exec.LookPath(name); err != nil {
+		return nil, fmt.Errorf("%s is required by this local network backend: %w", name, err)
+	}
+	cmd := exec.CommandContext(ctx, name, args...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return out, fmt.Errorf("%s %s failed: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return out, nil
+}
+
+// ErrNotStarted is returned when the network was never started by the backend
+var ErrNotStarted = errors.New("local network was not started with this backend")
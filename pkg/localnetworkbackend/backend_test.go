@@ -0,0 +1,221 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package localnetworkbackend
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MetalBlockchain/metalgo/genesis"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+var testConfig = Config{
+	HTTPPort:       9650,
+	StakingPort:    9651,
+	HTTPHost:       "127.0.0.1",
+	PluginDir:      "/home/user/.metal-cli/plugins",
+	MetalGoVersion: "v1.11.0",
+}
+
+func TestLoadOrCreateNetwork(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+
+	config := testConfig
+	config.NumNodes = 6
+	net, err := loadOrCreateNetwork(dir, config)
+	require.NoError(err)
+	require.Len(net.Nodes, 6)
+	require.Equal("node1", net.Nodes[0].Name)
+	require.Equal(9650, net.Nodes[0].HTTPPort)
+	require.Equal(9661, net.Nodes[5].StakingPort)
+	require.True(net.Nodes[0].HasSigner)
+
+	// the same network is loaded on the next starts
+	config.NumNodes = 0
+	reloaded, err := loadOrCreateNetwork(dir, config)
+	require.NoError(err)
+	require.Equal(net, reloaded)
+
+	config.NumNodes = 3
+	_, err = loadOrCreateNetwork(dir, config)
+	require.ErrorContains(err, "already has 6 nodes")
+}
+
+func TestLoadOrCreateNetworkWithNetworkID(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+
+	config := testConfig
+	config.NumNodes = 2
+	config.NetworkID = 123456
+	net, err := loadOrCreateNetwork(dir, config)
+	require.NoError(err)
+	require.Equal(uint32(123456), net.NetworkID)
+	require.Len(net.Nodes, 2)
+	require.True(net.Nodes[1].HasSigner)
+
+	// the nodes are the validators of a valid genesis
+	stakingConfig := genesis.GetStakingConfig(net.NetworkID)
+	_, _, err = genesis.FromFile(net.NetworkID, filepath.Join(dir, genesisFileName), &stakingConfig)
+	require.NoError(err)
+	genesisConfig, err := genesis.GetConfigFile(filepath.Join(dir, genesisFileName))
+	require.NoError(err)
+	require.Len(genesisConfig.InitialStakers, 2)
+	require.Equal(net.Nodes[0].NodeID, genesisConfig.InitialStakers[0].NodeID)
+
+	// other networks get other validators
+	otherNet, err := loadOrCreateNetwork(t.TempDir(), config)
+	require.NoError(err)
+	require.NotEqual(net.Nodes[0].NodeID, otherNet.Nodes[0].NodeID)
+}
+
+func TestComposeFile(t *testing.T) {
+	require := require.New(t)
+	config := testConfig
+	net, err := loadOrCreateNetwork(t.TempDir(), config)
+	require.NoError(err)
+	require.Len(net.Nodes, 5)
+
+	compose, err := composeFile("Metal-Local", net, config)
+	require.NoError(err)
+	parsed := struct {
+		Name     string
+		Services map[string]struct {
+			Image       string
+			NetworkMode string `yaml:"network_mode"`
+			Command     []string
+			Volumes     []string
+		}
+	}{}
+	require.NoError(yaml.Unmarshal(compose, &parsed))
+	require.Equal("metal-local", parsed.Name)
+	require.Len(parsed.Services, 5)
+	node1 := parsed.Services["node1"]
+	require.Equal("metalblockchain/metalgo:v1.11.0", node1.Image)
+	require.Equal("host", node1.NetworkMode)
+	require.Contains(node1.Command, "--http-port=9650")
+	require.Contains(node1.Command, "--staking-signer-key-file=/staking/signer.key")
+	require.Contains(node1.Volumes, "/home/user/.metal-cli/plugins:/plugins:ro")
+	node2 := parsed.Services["node2"]
+	require.Contains(node2.Command, "--http-port=9652")
+	require.Contains(node2.Command, "--bootstrap-ips=127.0.0.1:9651")
+	require.Contains(node2.Command, "--bootstrap-ids="+net.Nodes[0].NodeID.String())
+}
+
+func TestK8sFiles(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	config := testConfig
+	config.NumNodes = 2
+	net, err := loadOrCreateNetwork(dir, config)
+	require.NoError(err)
+
+	kindConfig, manifests, err := k8sFiles(dir, net, config)
+	require.NoError(err)
+	cluster := struct {
+		Nodes []struct {
+			ExtraPortMappings []struct {
+				ContainerPort int    `yaml:"containerPort"`
+				HostPort      int    `yaml:"hostPort"`
+				ListenAddress string `yaml:"listenAddress"`
+			} `yaml:"extraPortMappings"`
+			ExtraMounts []struct {
+				HostPath string `yaml:"hostPath"`
+			} `yaml:"extraMounts"`
+		}
+	}{}
+	require.NoError(yaml.Unmarshal(kindConfig, &cluster))
+	require.Len(cluster.Nodes, 1)
+	require.Len(cluster.Nodes[0].ExtraPortMappings, 2)
+	require.Equal(9652, cluster.Nodes[0].ExtraPortMappings[1].HostPort)
+	require.Equal("127.0.0.1", cluster.Nodes[0].ExtraPortMappings[1].ListenAddress)
+	require.Equal(dir, cluster.Nodes[0].ExtraMounts[0].HostPath)
+
+	deployments := strings.Split(string(manifests), "\n---\n")[1:]
+	require.Len(deployments, 2)
+	deployment := struct {
+		Metadata struct{ Name string }
+		Spec     struct {
+			Template struct {
+				Spec struct {
+					HostNetwork bool `yaml:"hostNetwork"`
+					Containers  []struct {
+						Image   string
+						Command []string
+					}
+				}
+			}
+		}
+	}{}
+	require.NoError(yaml.Unmarshal([]byte(deployments[1]), &deployment))
+	require.Equal("node2", deployment.Metadata.Name)
+	require.True(deployment.Spec.Template.Spec.HostNetwork)
+	container := deployment.Spec.Template.Spec.Containers[0]
+	require.Equal("metalblockchain/metalgo:v1.11.0", container.Image)
+	// node APIs listen on the kind node address the host ports are mapped to
+	require.Contains(container.Command, "--http-host=0.0.0.0")
+	require.Contains(container.Command, "--http-port=9652")
+}
+
+func TestCurrentName(t *testing.T) {
+	require := require.New(t)
+	runDir := t.TempDir()
+	require.Equal(ANR, CurrentName(runDir))
+	require.NoError(SetCurrentName(runDir, K8s))
+	require.Equal(K8s, CurrentName(runDir))
+	require.NoError(SetCurrentName(runDir, ANR))
+	require.Equal(ANR, CurrentName(runDir))
+	// nothing to remove
+	require.NoError(SetCurrentName(runDir, ANR))
+}
+
+// healthServer serves the health API of a node, answering after [delay]
+func healthServer(t *testing.T, healthy bool, delay time.Duration) int {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(delay)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","result":{"healthy":%t,"checks":{}},"id":1}`, healthy)
+	}))
+	t.Cleanup(server.Close)
+	port, err := strconv.Atoi(server.URL[strings.LastIndex(server.URL, ":")+1:])
+	require.NoError(t, err)
+	return port
+}
+
+func TestWaitForHealthy(t *testing.T) {
+	require := require.New(t)
+	const delay = 300 * time.Millisecond
+	net := network{Nodes: []node{
+		{Name: "node1", HTTPPort: healthServer(t, true, delay)},
+		{Name: "node2", HTTPPort: healthServer(t, true, delay)},
+		{Name: "node3", HTTPPort: healthServer(t, true, delay)},
+	}}
+
+	// the nodes are queried in parallel
+	start := time.Now()
+	infos, err := waitForHealthy(context.Background(), net, testConfig)
+	require.NoError(err)
+	require.Less(time.Since(start), 3*delay)
+	require.Len(infos, 3)
+	for i, info := range infos {
+		require.Equal(net.Nodes[i].Name, info.Name)
+		require.True(info.Healthy)
+	}
+
+	net.Nodes = append(net.Nodes, node{Name: "node4", HTTPPort: healthServer(t, false, 0)})
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err = waitForHealthy(ctx, net, testConfig)
+	require.ErrorIs(err, context.DeadlineExceeded)
+	require.ErrorContains(err, "nodes node4 not healthy")
+}
@@ -0,0 +1,164 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package localnetworkbackend
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metalgo/ids"
+)
+
+const (
+	dockerCompose = "docker-compose"
+	// composeFileName is the compose file running the nodes, kept into the
+	// backend dir
+	composeFileName = "compose.yml"
+)
+
+//go:embed configs/*
+var configs embed.FS
+
+// dockerBackend runs each node in a docker-compose service. Services use the
+// host network, so nodes get the same ports they get with the network runner
+type dockerBackend struct {
+	dir         string
+	projectName string
+}
+
+// NewDockerBackend returns a backend that keeps the network files and the
+// compose file into [dir], and runs them as compose project [projectName]
+func NewDockerBackend(dir string, projectName string) ContainerBackend {
+	return &dockerBackend{dir: dir, projectName: projectName}
+}
+
+type composeNode struct {
+	Name string
+	Args []string
+}
+
+type composeInputs struct {
+	ProjectName string
+	Image       string
+	PluginDir   string
+	Nodes       []composeNode
+}
+
+// composeFile returns the compose file running the nodes of [net]
+func composeFile(projectName string, net network, config Config) ([]byte, error) {
+	composeTemplate, err := configs.ReadFile("configs/compose.yml")
+	if err != nil {
+		return nil, err
+	}
+	t, err := template.New("Local Network Compose").
+		Funcs(template.FuncMap{"genesisFile": func() string { return genesisFileName }}).
+		Parse(string(composeTemplate))
+	if err != nil {
+		return nil, err
+	}
+	inputs := composeInputs{
+		ProjectName: strings.ToLower(projectName),
+		Image:       image(config),
+		PluginDir:   config.PluginDir,
+	}
+	for _, n := range net.Nodes {
+		inputs.Nodes = append(inputs.Nodes, composeNode{
+			Name: n.Name,
+			Args: n.args(net, config, "/config", "/staking", "/data", "/plugins"),
+		})
+	}
+	var compose bytes.Buffer
+	if err := t.Execute(&compose, inputs); err != nil {
+		return nil, err
+	}
+	return compose.Bytes(), nil
+}
+
+// image returns the metalgo image of [config]
+func image(config Config) string {
+	version := config.MetalGoVersion
+	if version == "" {
+		version = "latest"
+	}
+	return MetalGoImage + ":" + version
+}
+
+func (d *dockerBackend) composeFilePath() string {
+	return filepath.Join(d.dir, composeFileName)
+}
+
+func (d *dockerBackend) compose(ctx context.Context, args ...string) ([]byte, error) {
+	return runCommand(ctx, dockerCompose, append([]string{"-f", d.composeFilePath(), "-p", d.projectName}, args...)...)
+}
+
+func (d *dockerBackend) Start(ctx context.Context, config Config) ([]NodeInfo, error) {
+	config = keepTrackedSubnets(d.dir, config)
+	net, err := loadOrCreateNetwork(d.dir, config)
+	if err != nil {
+		return nil, err
+	}
+	compose, err := composeFile(d.projectName, net, config)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(d.composeFilePath(), compose, constants.WriteReadReadPerms); err != nil {
+		return nil, err
+	}
+	if err := saveConfig(d.dir, config); err != nil {
+		return nil, err
+	}
+	if _, err := d.compose(ctx, "up", "--detach", "--remove-orphans"); err != nil {
+		return nil, err
+	}
+	return waitForHealthy(ctx, net, config)
+}
+
+func (d *dockerBackend) TrackSubnet(ctx context.Context, subnetID ids.ID, vmID ids.ID, vmBin string) ([]NodeInfo, error) {
+	return trackSubnet(ctx, d, d.dir, subnetID, vmID, vmBin)
+}
+
+func (d *dockerBackend) Stop(ctx context.Context) error {
+	if !utils.FileExists(d.composeFilePath()) || !installed(dockerCompose) {
+		return ErrNotStarted
+	}
+	_, err := d.compose(ctx, "stop")
+	return err
+}
+
+func (d *dockerBackend) Status(ctx context.Context) ([]NodeInfo, error) {
+	if !utils.FileExists(d.composeFilePath()) || !installed(dockerCompose) {
+		return nil, nil
+	}
+	out, err := d.compose(ctx, "ps", "--quiet")
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(string(out)) == "" {
+		return nil, nil
+	}
+	config, err := loadConfig(d.dir)
+	if err != nil {
+		return nil, err
+	}
+	net, err := loadOrCreateNetwork(d.dir, config)
+	if err != nil {
+		return nil, err
+	}
+	return nodeInfos(ctx, net, config), nil
+}
+
+func (d *dockerBackend) Clean(ctx context.Context) error {
+	if utils.FileExists(d.composeFilePath()) && installed(dockerCompose) {
+		if _, err := d.compose(ctx, "down", "--volumes", "--remove-orphans"); err != nil {
+			return err
+		}
+	}
+	return os.RemoveAll(d.dir)
+}
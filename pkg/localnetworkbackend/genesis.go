@@ -0,0 +1,82 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package localnetworkbackend
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	anrnetwork "github.com/MetalBlockchain/metal-network-runner/network"
+	anrutils "github.com/MetalBlockchain/metal-network-runner/utils"
+	"github.com/MetalBlockchain/metalgo/staking"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/bls"
+	"github.com/MetalBlockchain/metalgo/utils/formatting"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/signer"
+)
+
+// setNewValidators turns [netConfig] into the config of a separate network with ID
+// [networkID]: all its nodes get new staking and BLS keys, and become the initial
+// validators of its genesis, which starts now. The genesis allocations are kept,
+// so the well known local network keys are funded
+func setNewValidators(netConfig *anrnetwork.Config, networkID uint32) error {
+	genesis := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(netConfig.Genesis), &genesis); err != nil {
+		return err
+	}
+	stakers, ok := genesis["initialStakers"].([]interface{})
+	if !ok || len(stakers) == 0 {
+		return errors.New("genesis has no initial stakers")
+	}
+	stakerTemplate, ok := stakers[0].(map[string]interface{})
+	if !ok {
+		return errors.New("unexpected genesis initial staker format")
+	}
+	newStakers := []interface{}{}
+	for i := range netConfig.NodeConfigs {
+		stakingCert, stakingKey, err := staking.NewCertAndKeyBytes()
+		if err != nil {
+			return fmt.Errorf("couldn't generate staking cert/key: %w", err)
+		}
+		nodeID, err := anrutils.ToNodeID(stakingKey, stakingCert)
+		if err != nil {
+			return err
+		}
+		blsKey, err := bls.NewSecretKey()
+		if err != nil {
+			return fmt.Errorf("couldn't generate BLS key: %w", err)
+		}
+		pop := signer.NewProofOfPossession(blsKey)
+		publicKey, err := formatting.Encode(formatting.HexNC, pop.PublicKey[:])
+		if err != nil {
+			return err
+		}
+		proofOfPossession, err := formatting.Encode(formatting.HexNC, pop.ProofOfPossession[:])
+		if err != nil {
+			return err
+		}
+		netConfig.NodeConfigs[i].StakingKey = string(stakingKey)
+		netConfig.NodeConfigs[i].StakingCert = string(stakingCert)
+		netConfig.NodeConfigs[i].StakingSigningKey = base64.StdEncoding.EncodeToString(bls.SecretKeyToBytes(blsKey))
+		newStakers = append(newStakers, map[string]interface{}{
+			"nodeID":        nodeID.String(),
+			"rewardAddress": stakerTemplate["rewardAddress"],
+			"delegationFee": stakerTemplate["delegationFee"],
+			"signer": map[string]interface{}{
+				"publicKey":         publicKey,
+				"proofOfPossession": proofOfPossession,
+			},
+		})
+	}
+	genesis["networkID"] = networkID
+	genesis["startTime"] = time.Now().Unix()
+	genesis["initialStakers"] = newStakers
+	genesisBytes, err := json.MarshalIndent(genesis, "", "  ")
+	if err != nil {
+		return err
+	}
+	netConfig.Genesis = string(genesisBytes)
+	return nil
+}
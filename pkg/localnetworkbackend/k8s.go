@@ -0,0 +1,217 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package localnetworkbackend
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	kind               = "kind"
+	kubectl            = "kubectl"
+	kindConfigFileName = "kind.yml"
+	manifestsFileName  = "nodes.yml"
+	// k8sPartOf labels the deployments of the local network nodes
+	k8sPartOf = "metal-local-network"
+)
+
+// k8sBackend runs each node in a Deployment of a single node kind cluster.
+// Pods use the host network of the kind node, and the node API ports are
+// mapped to the same host ports they get with the network runner
+type k8sBackend struct {
+	dir         string
+	clusterName string
+}
+
+// NewK8sBackend returns a backend that keeps the network files, cluster config
+// and manifests into [dir], and runs the nodes in kind cluster [clusterName]
+func NewK8sBackend(dir string, clusterName string) ContainerBackend {
+	return &k8sBackend{dir: dir, clusterName: strings.ToLower(clusterName)}
+}
+
+type k8sNode struct {
+	Name     string
+	HTTPPort int
+	Args     []string
+}
+
+type k8sInputs struct {
+	Dir       string
+	PluginDir string
+	HTTPHost  string
+	Image     string
+	PartOf    string
+	Nodes     []k8sNode
+}
+
+// k8sFiles returns the kind cluster config and the node manifests of [net],
+// whose files are kept at [dir]
+func k8sFiles(dir string, net network, config Config) ([]byte, []byte, error) {
+	// inside the kind node, the node APIs must listen on the address the host
+	// ports are forwarded to
+	nodeConfig := config
+	nodeConfig.HTTPHost = "0.0.0.0"
+	httpHost := config.HTTPHost
+	if httpHost == "" {
+		httpHost = "127.0.0.1"
+	}
+	inputs := k8sInputs{
+		Dir:       dir,
+		PluginDir: config.PluginDir,
+		HTTPHost:  httpHost,
+		Image:     image(config),
+		PartOf:    k8sPartOf,
+	}
+	for _, n := range net.Nodes {
+		inputs.Nodes = append(inputs.Nodes, k8sNode{
+			Name:     n.Name,
+			HTTPPort: n.HTTPPort,
+			Args:     n.args(net, nodeConfig, "/config", "/staking", "/data", "/plugins"),
+		})
+	}
+	files := [][]byte{}
+	for _, name := range []string{"configs/kindCluster.yml", "configs/k8sNodes.yml"} {
+		fileTemplate, err := configs.ReadFile(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		t, err := template.New(name).Parse(string(fileTemplate))
+		if err != nil {
+			return nil, nil, err
+		}
+		var file bytes.Buffer
+		if err := t.Execute(&file, inputs); err != nil {
+			return nil, nil, err
+		}
+		files = append(files, file.Bytes())
+	}
+	return files[0], files[1], nil
+}
+
+func (k *k8sBackend) kubectl(ctx context.Context, args ...string) ([]byte, error) {
+	return runCommand(ctx, kubectl, append([]string{"--context", "kind-" + k.clusterName}, args...)...)
+}
+
+func (k *k8sBackend) clusterExists(ctx context.Context) (bool, error) {
+	if !installed(kind) {
+		return false, nil
+	}
+	out, err := runCommand(ctx, kind, "get", "clusters")
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(strings.Fields(string(out)), k.clusterName), nil
+}
+
+func (k *k8sBackend) Start(ctx context.Context, config Config) ([]NodeInfo, error) {
+	config = keepTrackedSubnets(k.dir, config)
+	net, err := loadOrCreateNetwork(k.dir, config)
+	if err != nil {
+		return nil, err
+	}
+	kindConfig, manifests, err := k8sFiles(k.dir, net, config)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := k.clusterExists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	kindConfigPath := filepath.Join(k.dir, kindConfigFileName)
+	if exists {
+		// port mappings and mounts are fixed at cluster creation
+		prevKindConfig, err := os.ReadFile(kindConfigPath)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(prevKindConfig, kindConfig) {
+			return nil, fmt.Errorf("kind cluster %s was created with other ports or dirs. Use 'metal network clean' to start from scratch", k.clusterName)
+		}
+	} else {
+		if err := os.WriteFile(kindConfigPath, kindConfig, constants.WriteReadReadPerms); err != nil {
+			return nil, err
+		}
+		if _, err := runCommand(ctx, kind, "create", "cluster", "--name", k.clusterName, "--config", kindConfigPath); err != nil {
+			return nil, err
+		}
+	}
+	manifestsPath := filepath.Join(k.dir, manifestsFileName)
+	if err := os.WriteFile(manifestsPath, manifests, constants.WriteReadReadPerms); err != nil {
+		return nil, err
+	}
+	if err := saveConfig(k.dir, config); err != nil {
+		return nil, err
+	}
+	if _, err := k.kubectl(ctx, "apply", "-f", manifestsPath); err != nil {
+		return nil, err
+	}
+	return waitForHealthy(ctx, net, config)
+}
+
+func (k *k8sBackend) TrackSubnet(ctx context.Context, subnetID ids.ID, vmID ids.ID, vmBin string) ([]NodeInfo, error) {
+	return trackSubnet(ctx, k, k.dir, subnetID, vmID, vmBin)
+}
+
+func (k *k8sBackend) Stop(ctx context.Context) error {
+	exists, err := k.clusterExists(ctx)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNotStarted
+	}
+	_, err = k.kubectl(ctx, "scale", "deployment", "--selector", "app.kubernetes.io/part-of="+k8sPartOf, "--replicas=0")
+	return err
+}
+
+func (k *k8sBackend) Status(ctx context.Context) ([]NodeInfo, error) {
+	if !utils.FileExists(filepath.Join(k.dir, configFileName)) {
+		return nil, nil
+	}
+	exists, err := k.clusterExists(ctx)
+	if err != nil || !exists {
+		return nil, err
+	}
+	out, err := k.kubectl(ctx, "get", "deployment", "--selector", "app.kubernetes.io/part-of="+k8sPartOf, "-o", "jsonpath={.items[*].spec.replicas}")
+	if err != nil {
+		return nil, err
+	}
+	if strings.Trim(string(out), "0 \n") == "" {
+		return nil, nil
+	}
+	config, err := loadConfig(k.dir)
+	if err != nil {
+		return nil, err
+	}
+	net, err := loadOrCreateNetwork(k.dir, config)
+	if err != nil {
+		return nil, err
+	}
+	return nodeInfos(ctx, net, config), nil
+}
+
+func (k *k8sBackend) Clean(ctx context.Context) error {
+	if utils.FileExists(filepath.Join(k.dir, kindConfigFileName)) {
+		exists, err := k.clusterExists(ctx)
+		if err != nil {
+			return err
+		}
+		if exists {
+			if _, err := runCommand(ctx, kind, "delete", "cluster", "--name", k.clusterName); err != nil {
+				return err
+			}
+		}
+	}
+	return os.RemoveAll(k.dir)
+}
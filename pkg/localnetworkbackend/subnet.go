@@ -0,0 +1,147 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package localnetworkbackend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/sdk"
+	anrutils "github.com/MetalBlockchain/metal-network-runner/utils"
+	"github.com/MetalBlockchain/metalgo/ids"
+	avagoconstants "github.com/MetalBlockchain/metalgo/utils/constants"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/keychain"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm"
+	"golang.org/x/exp/slices"
+)
+
+// subnetValidationStartOffset delays the start of the subnet validations, as the
+// network runner does, so that the txs are still valid when issued
+const subnetValidationStartOffset = 20 * time.Second
+
+// DeployInfo describes a subnet deployed on a container based local network
+type DeployInfo struct {
+	SubnetID     ids.ID
+	BlockchainID ids.ID
+	Nodes        []NodeInfo
+}
+
+// DeploySubnet creates a subnet with the blockchain [chainName], that runs the VM
+// binary [vmBin] with [genesis], on the local network run by [backend]. The txs are
+// paid by [kc], that also owns the subnet. All the primary network validators of
+// the local network validate the subnet, and the nodes are restarted to track it
+func DeploySubnet(
+	ctx context.Context,
+	backend ContainerBackend,
+	kc keychain.Keychain,
+	chainName string,
+	genesis []byte,
+	vmBin string,
+) (*DeployInfo, error) {
+	nodes, err := backend.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, errors.New("local network is not running. Use 'metal network start' to start it")
+	}
+	endpoint := nodes[0].URI
+	vmID, err := anrutils.VMID(chainName)
+	if err != nil {
+		return nil, err
+	}
+	wallet, err := sdk.NewWallet(ctx, endpoint, kc)
+	if err != nil {
+		return nil, err
+	}
+	subnetID, err := sdk.CreateSubnet(ctx, wallet, []ids.ShortID{kc.Addresses().List()[0]}, 1)
+	if err != nil {
+		return nil, err
+	}
+	// reload the wallet so it knows about the new subnet
+	wallet, err = sdk.NewWallet(ctx, endpoint, kc, subnetID)
+	if err != nil {
+		return nil, err
+	}
+	blockchainID, err := sdk.CreateBlockchain(ctx, wallet, subnetID, chainName, chainName, genesis)
+	if err != nil {
+		return nil, err
+	}
+	primaryValidators, err := platformvm.NewClient(endpoint).GetCurrentValidators(ctx, avagoconstants.PrimaryNetworkID, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodes {
+		nodeID, err := ids.NodeIDFromString(node.NodeID)
+		if err != nil {
+			return nil, err
+		}
+		// nodes added on top of the default ones are not validators
+		i := slices.IndexFunc(primaryValidators, func(v platformvm.ClientPermissionlessValidator) bool {
+			return v.NodeID == nodeID
+		})
+		if i == -1 {
+			continue
+		}
+		startTime := time.Now().Add(subnetValidationStartOffset)
+		endTime := time.Unix(int64(primaryValidators[i].EndTime), 0)
+		if _, err := sdk.AddValidator(
+			ctx,
+			wallet,
+			subnetID,
+			nodeID,
+			constants.DefaultStakeWeight,
+			startTime,
+			endTime.Sub(startTime),
+		); err != nil {
+			return nil, fmt.Errorf("failure adding %s as subnet validator: %w", node.Name, err)
+		}
+	}
+	nodes, err = backend.TrackSubnet(ctx, subnetID, vmID, vmBin)
+	if err != nil {
+		return nil, err
+	}
+	return &DeployInfo{SubnetID: subnetID, BlockchainID: blockchainID, Nodes: nodes}, nil
+}
+
+// trackSubnet installs [vmBin] as the VM [vmID] into the plugin dir of the
+// network at [dir], and restarts its nodes with [backend] to also track [subnetID]
+func trackSubnet(ctx context.Context, backend Backend, dir string, subnetID ids.ID, vmID ids.ID, vmBin string) ([]NodeInfo, error) {
+	config, err := loadConfig(dir)
+	if os.IsNotExist(err) {
+		return nil, ErrNotStarted
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := installVM(config.PluginDir, vmID, vmBin); err != nil {
+		return nil, err
+	}
+	if !slices.Contains(config.TrackSubnets, subnetID.String()) {
+		config.TrackSubnets = append(config.TrackSubnets, subnetID.String())
+	}
+	return backend.Start(ctx, config)
+}
+
+// installVM copies [vmBin] as the binary of the VM [vmID] into [pluginDir],
+// that is mounted into the node containers
+func installVM(pluginDir string, vmID ids.ID, vmBin string) error {
+	bs, err := os.ReadFile(vmBin)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(pluginDir, constants.DefaultPerms755); err != nil {
+		return err
+	}
+	pluginPath := filepath.Join(pluginDir, vmID.String())
+	if err := os.WriteFile(pluginPath, bs, constants.DefaultPerms755); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of a previous binary
+	return os.Chmod(pluginPath, constants.DefaultPerms755)
+}
@@ -0,0 +1,71 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package localnetworkbackend
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// startRecorder is a backend that saves the config it is started with, as the
+// container based backends do
+type startRecorder struct {
+	Backend
+	dir string
+}
+
+func (s startRecorder) Start(_ context.Context, config Config) ([]NodeInfo, error) {
+	config = keepTrackedSubnets(s.dir, config)
+	return nil, saveConfig(s.dir, config)
+}
+
+func TestTrackSubnet(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	vmBin := filepath.Join(t.TempDir(), "vm")
+	require.NoError(os.WriteFile(vmBin, []byte("vm binary"), 0o600))
+	backend := startRecorder{dir: dir}
+	subnetID := ids.GenerateTestID()
+	vmID := ids.GenerateTestID()
+
+	_, err := trackSubnet(context.Background(), backend, dir, subnetID, vmID, vmBin)
+	require.ErrorIs(err, ErrNotStarted)
+
+	config := testConfig
+	config.PluginDir = t.TempDir()
+	_, err = backend.Start(context.Background(), config)
+	require.NoError(err)
+	_, err = trackSubnet(context.Background(), backend, dir, subnetID, vmID, vmBin)
+	require.NoError(err)
+	// the VM is installed as an executable into the plugin dir
+	info, err := os.Stat(filepath.Join(config.PluginDir, vmID.String()))
+	require.NoError(err)
+	require.NotZero(info.Mode() & 0o100)
+	config, err = loadConfig(dir)
+	require.NoError(err)
+	require.Equal([]string{subnetID.String()}, config.TrackSubnets)
+
+	// deploying again doesn't duplicate the subnet
+	_, err = trackSubnet(context.Background(), backend, dir, subnetID, vmID, vmBin)
+	require.NoError(err)
+	config, err = loadConfig(dir)
+	require.NoError(err)
+	require.Equal([]string{subnetID.String()}, config.TrackSubnets)
+
+	// restarts keep tracking the subnet
+	restartConfig := testConfig
+	_, err = backend.Start(context.Background(), restartConfig)
+	require.NoError(err)
+	config, err = loadConfig(dir)
+	require.NoError(err)
+	require.Equal([]string{subnetID.String()}, config.TrackSubnets)
+
+	net, err := loadOrCreateNetwork(t.TempDir(), config)
+	require.NoError(err)
+	require.Contains(net.Nodes[0].args(net, config, "/config", "/staking", "/data", "/plugins"), "--track-subnets="+subnetID.String())
+}
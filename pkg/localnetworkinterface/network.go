@@ -8,7 +8,7 @@ import (
 	"errors"
 	"strings"
 
-	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
 	"github.com/MetalBlockchain/metalgo/api/info"
 )
 
@@ -16,15 +16,28 @@ type StatusChecker interface {
 	GetCurrentNetworkVersion() (string, int, bool, error)
 }
 
-type networkStatusChecker struct{}
+type networkStatusChecker struct {
+	// endpoint of the node queried, the local network one if empty
+	endpoint string
+}
 
 func NewStatusChecker() StatusChecker {
 	return networkStatusChecker{}
 }
 
-func (networkStatusChecker) GetCurrentNetworkVersion() (string, int, bool, error) {
+// NewEndpointStatusChecker returns a StatusChecker that queries the node at [endpoint],
+// for local networks not run by the network runner
+func NewEndpointStatusChecker(endpoint string) StatusChecker {
+	return networkStatusChecker{endpoint: endpoint}
+}
+
+func (c networkStatusChecker) GetCurrentNetworkVersion() (string, int, bool, error) {
 	ctx := context.Background()
-	infoClient := info.NewClient(constants.LocalAPIEndpoint)
+	endpoint := c.endpoint
+	if endpoint == "" {
+		endpoint = models.LocalAPIEndpoint()
+	}
+	infoClient := info.NewClient(endpoint)
 	versionResponse, err := infoClient.GetNodeVersion(ctx)
 	if err != nil {
 		// not actually an error, network just not running
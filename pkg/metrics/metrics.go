@@ -3,14 +3,22 @@
 package metrics
 
 import (
+	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
 	"os/user"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/MetalBlockchain/metal-cli/pkg/application"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
@@ -18,8 +26,11 @@ import (
 
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
 
+	"github.com/manifoldco/promptui"
 	"github.com/posthog/posthog-go"
 	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
 )
 
 // telemetryToken value is set at build and install scripts using ldflags
@@ -83,44 +94,168 @@ func userIsOptedIn(app *application.Avalanche) bool {
 	return app.Conf.GetConfigBoolValue(constants.ConfigMetricsEnabledKey)
 }
 
-func HandleTracking(cmd *cobra.Command, app *application.Avalanche, flags map[string]string) {
-	if userIsOptedIn(app) {
-		if !cmd.HasSubCommands() && CheckCommandIsNotCompletion(cmd) {
-			TrackMetrics(cmd, flags)
-		}
+// Event is the anonymous usage report sent for each command run by an opted in user.
+// It never includes args, flag values other than the ones in [Flags], error
+// messages, or any other data identifying the user or their subnets
+type Event struct {
+	Event         string            `json:"event"`
+	UserID        string            `json:"userID"`
+	Command       string            `json:"command"`
+	Version       string            `json:"version"`
+	OS            string            `json:"os"`
+	Arch          string            `json:"arch"`
+	Success       bool              `json:"success"`
+	ErrorCategory string            `json:"errorCategory,omitempty"`
+	DurationMs    int64             `json:"durationMs"`
+	Flags         map[string]string `json:"flags,omitempty"`
+}
+
+var (
+	commandFlagsLock sync.Mutex
+	commandFlags     = map[string]string{}
+)
+
+// HandleTracking records [flags] of [cmd], eg the network it deploys to, to be
+// reported with the usage event of the command
+func HandleTracking(_ *cobra.Command, _ *application.Avalanche, flags map[string]string) {
+	commandFlagsLock.Lock()
+	defer commandFlagsLock.Unlock()
+	for k, v := range flags {
+		commandFlags[k] = v
 	}
 }
 
 func CheckCommandIsNotCompletion(cmd *cobra.Command) bool {
 	result := strings.Fields(cmd.CommandPath())
-	if len(result) >= 2 && result[1] == "completion" {
+	if len(result) >= 2 && (result[1] == "completion" || result[1] == cobra.ShellCompRequestCmd || result[1] == cobra.ShellCompNoDescRequestCmd) {
 		return false
 	}
 	return true
 }
 
-func TrackMetrics(command *cobra.Command, flags map[string]string) {
-	if telemetryToken == "" || utils.IsE2E() {
+// TrackCommand reports the usage event of [cmd], that ended with [cmdErr] after [duration],
+// if the user is opted in. Events are sent to the configured metrics endpoint, if any.
+// Failing to report is logged but doesn't fail the command
+func TrackCommand(cmd *cobra.Command, app *application.Avalanche, cliVersion string, cmdErr error, duration time.Duration) {
+	if cmd == nil || app.Conf == nil || !userIsOptedIn(app) || utils.IsE2E() || utils.IsOffline() {
+		return
+	}
+	if cmd.HasSubCommands() || !CheckCommandIsNotCompletion(cmd) {
 		return
 	}
+	commandFlagsLock.Lock()
+	flags := maps.Clone(commandFlags)
+	commandFlagsLock.Unlock()
+	event := NewEvent(cmd.CommandPath(), cliVersion, cmdErr, duration, flags)
+	app.Log.Debug("sending usage metrics", zap.Any("event", event))
+	if err := sendEvent(app.Conf.GetConfigStringValue(constants.ConfigMetricsEndpointKey), event); err != nil {
+		app.Log.Debug("failed sending usage metrics", zap.Error(err))
+	}
+}
 
-	client, _ := posthog.NewWithConfig(telemetryToken, posthog.Config{Endpoint: telemetryInstance})
+// NewEvent returns the usage event of the command with path [commandPath]
+func NewEvent(commandPath string, cliVersion string, cmdErr error, duration time.Duration, flags map[string]string) Event {
+	if cliVersion == "" {
+		cliVersion = GetCLIVersion()
+	}
+	event := Event{
+		Event:      "cli-command",
+		UserID:     getUserID(),
+		Command:    commandPath,
+		Version:    strings.TrimSpace(cliVersion),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		Success:    cmdErr == nil,
+		DurationMs: duration.Milliseconds(),
+		Flags:      flags,
+	}
+	if cmdErr != nil {
+		event.ErrorCategory = GetErrorCategory(cmdErr)
+	}
+	return event
+}
 
-	defer client.Close()
+// GetErrorCategory returns the kind of [err], as reported on usage events, so
+// that the error message, that may contain user data, is never sent
+func GetErrorCategory(err error) string {
+	if code := ux.GetErrorCode(err); code != ux.ErrCodeGeneric {
+		return string(code)
+	}
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return string(ux.ErrCodeTimeout)
+	case errors.Is(err, context.Canceled), errors.Is(err, promptui.ErrInterrupt), errors.Is(err, promptui.ErrAbort):
+		return "CANCELED"
+	case errors.Is(err, utils.ErrOffline):
+		return "OFFLINE"
+	case errors.Is(err, os.ErrNotExist), errors.Is(err, application.ErrDownloadNotFound):
+		return string(ux.ErrCodeNotFound)
+	case errors.As(err, &netErr):
+		return "NETWORK"
+	}
+	return string(ux.ErrCodeGeneric)
+}
 
+// getUserID returns an anonymous, stable ID of the local user
+func getUserID() string {
 	usr, _ := user.Current() // use empty string if err
+	if usr == nil {
+		usr = &user.User{}
+	}
 	hash := sha256.Sum256([]byte(fmt.Sprintf("%s%s", usr.Username, usr.Uid)))
-	userID := base64.StdEncoding.EncodeToString(hash[:])
-	telemetryProperties := make(map[string]interface{})
-	telemetryProperties["command"] = command.CommandPath()
-	telemetryProperties["version"] = GetCLIVersion()
-	telemetryProperties["os"] = runtime.GOOS
-	for propertyKey, propertyValue := range flags {
-		telemetryProperties[propertyKey] = propertyValue
-	}
-	_ = client.Enqueue(posthog.Capture{
-		DistinctId: userID,
-		Event:      "cli-command",
-		Properties: telemetryProperties,
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// sendEvent posts [event] as JSON to [endpoint], or sends it to the default telemetry
+// instance if no endpoint is given
+func sendEvent(endpoint string, event Event) error {
+	if endpoint != "" {
+		body, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), constants.MetricsRequestTimeout)
+		defer cancel()
+		request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to create metrics request for %s: %w", endpoint, err)
+		}
+		request.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(request)
+		if err != nil {
+			return fmt.Errorf("failed posting metrics to %s: %w", endpoint, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("failed posting metrics to %s: unexpected http status code: %d", endpoint, resp.StatusCode)
+		}
+		return nil
+	}
+	if telemetryToken == "" {
+		return nil
+	}
+	client, err := posthog.NewWithConfig(telemetryToken, posthog.Config{Endpoint: telemetryInstance})
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	properties := posthog.NewProperties().
+		Set("command", event.Command).
+		Set("version", event.Version).
+		Set("os", event.OS).
+		Set("arch", event.Arch).
+		Set("success", event.Success).
+		Set("durationMs", event.DurationMs)
+	if event.ErrorCategory != "" {
+		properties.Set("errorCategory", event.ErrorCategory)
+	}
+	for k, v := range event.Flags {
+		properties.Set(k, v)
+	}
+	return client.Enqueue(posthog.Capture{
+		DistinctId: event.UserID,
+		Event:      event.Event,
+		Properties: properties,
 	})
 }
@@ -0,0 +1,79 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetErrorCategory(t *testing.T) {
+	require := require.New(t)
+	require.Equal("INVALID_ARGUMENTS", GetErrorCategory(fmt.Errorf("wrapped: %w", ux.NewCodedError(ux.ErrCodeInvalidArguments, errors.New("bad flag")))))
+	require.Equal("TIMEOUT", GetErrorCategory(fmt.Errorf("failed: %w", context.DeadlineExceeded)))
+	require.Equal("CANCELED", GetErrorCategory(context.Canceled))
+	require.Equal("OFFLINE", GetErrorCategory(fmt.Errorf("%w: can't download", utils.ErrOffline)))
+	require.Equal("NOT_FOUND", GetErrorCategory(fmt.Errorf("failed: %w", os.ErrNotExist)))
+	require.Equal("ERROR", GetErrorCategory(errors.New("key /home/user/secret.pk is invalid")))
+}
+
+func TestNewEvent(t *testing.T) {
+	require := require.New(t)
+	event := NewEvent("metal subnet deploy", "1.4.2\n", errors.New("user data"), 1500*time.Millisecond, map[string]string{"network": "Local Network"})
+	require.Equal("cli-command", event.Event)
+	require.Equal("metal subnet deploy", event.Command)
+	require.Equal("1.4.2", event.Version)
+	require.False(event.Success)
+	require.Equal("ERROR", event.ErrorCategory)
+	require.Equal(int64(1500), event.DurationMs)
+	require.NotEmpty(event.UserID)
+
+	// the error message is never reported
+	eventBytes, err := json.Marshal(event)
+	require.NoError(err)
+	require.NotContains(string(eventBytes), "user data")
+
+	event = NewEvent("metal subnet list", "1.4.2", nil, 0, nil)
+	require.True(event.Success)
+	require.Empty(event.ErrorCategory)
+}
+
+func TestSendEvent(t *testing.T) {
+	require := require.New(t)
+	var received Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(http.MethodPost, r.Method)
+		require.NoError(json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	event := NewEvent("metal subnet list", "1.4.2", nil, time.Second, nil)
+	require.NoError(sendEvent(server.URL, event))
+	require.Equal(event, received)
+
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	require.ErrorContains(sendEvent(server.URL, event), "unexpected http status code: 500")
+}
+
+func TestHandleTracking(t *testing.T) {
+	require := require.New(t)
+	defer func() {
+		commandFlags = map[string]string{}
+	}()
+	HandleTracking(nil, nil, map[string]string{"network": "Tahoe"})
+	HandleTracking(nil, nil, map[string]string{"multiSig": "multi-sig"})
+	require.Equal(map[string]string{"network": "Tahoe", "multiSig": "multi-sig"}, commandFlags)
+}
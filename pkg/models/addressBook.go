@@ -0,0 +1,49 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import (
+	"sort"
+	"strings"
+)
+
+type AddressBookEntry struct {
+	Address     string
+	Description string
+}
+
+// AddressBook maps a user given label to a known address (eg a teammate's control key)
+type AddressBook map[string]AddressBookEntry
+
+// Labels returns the address book labels in alphabetical order
+func (ab AddressBook) Labels() []string {
+	labels := make([]string, 0, len(ab))
+	for label := range ab {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// Resolve returns [addrs] with every entry that matches a label replaced by
+// the stored address. Entries that are not labels are kept as given
+func (ab AddressBook) Resolve(addrs []string) []string {
+	resolved := make([]string, len(addrs))
+	for i, addr := range addrs {
+		resolved[i] = addr
+		if entry, ok := ab[strings.TrimSpace(addr)]; ok {
+			resolved[i] = entry.Address
+		}
+	}
+	return resolved
+}
+
+// LabelOf returns the label stored for [addr], if any
+func (ab AddressBook) LabelOf(addr string) (string, bool) {
+	for _, label := range ab.Labels() {
+		if ab[label].Address == addr {
+			return label, true
+		}
+	}
+	return "", false
+}
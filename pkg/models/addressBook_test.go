@@ -0,0 +1,53 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressBookResolve(t *testing.T) {
+	ab := AddressBook{
+		"alice": {Address: "P-tahoe1alice"},
+		"bob":   {Address: "P-tahoe1bob", Description: "ops"},
+	}
+	tests := []struct {
+		name     string
+		addrs    []string
+		expected []string
+	}{
+		{
+			name:     "empty",
+			addrs:    []string{},
+			expected: []string{},
+		},
+		{
+			name:     "labels only",
+			addrs:    []string{"alice", "bob"},
+			expected: []string{"P-tahoe1alice", "P-tahoe1bob"},
+		},
+		{
+			name:     "mixed labels and addresses",
+			addrs:    []string{"P-tahoe1carol", " bob "},
+			expected: []string{"P-tahoe1carol", "P-tahoe1bob"},
+		},
+		{
+			name:     "unknown label kept",
+			addrs:    []string{"dave"},
+			expected: []string{"dave"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, ab.Resolve(tt.addrs))
+		})
+	}
+	require.Equal(t, []string{"alice", "bob"}, ab.Labels())
+	label, ok := ab.LabelOf("P-tahoe1bob")
+	require.True(t, ok)
+	require.Equal(t, "bob", label)
+	_, ok = ab.LabelOf("P-tahoe1carol")
+	require.False(t, ok)
+}
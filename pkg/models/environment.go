@@ -0,0 +1,50 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import (
+	"sort"
+	"strings"
+
+	avagoconstants "github.com/MetalBlockchain/metalgo/utils/constants"
+)
+
+// EnvironmentNetworkPrefix prefixes the sidecar network names of environments
+const EnvironmentNetworkPrefix = "Environment"
+
+// Environment is a user registered network, such as a staging devnet or an
+// internal testnet, that subnets can be deployed to and tracked on by name
+type Environment struct {
+	NetworkID   uint32
+	Endpoint    string
+	Description string
+}
+
+// Environments maps a user given name to a registered environment
+type Environments map[string]Environment
+
+// Names returns the environment names in alphabetical order
+func (e Environments) Names() []string {
+	names := make([]string, 0, len(e))
+	for name := range e {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewEnvironmentNetwork returns the network of environment [name]. Environments
+// running with the Mainnet or Tahoe network IDs behave as those networks, with
+// their own endpoint, and any other one as a devnet
+func NewEnvironmentNetwork(name string, env Environment) Network {
+	kind := Devnet
+	switch env.NetworkID {
+	case avagoconstants.MainnetID:
+		kind = Mainnet
+	case avagoconstants.TahoeID:
+		kind = Tahoe
+	}
+	network := NewNetwork(kind, env.NetworkID, strings.TrimSuffix(env.Endpoint, "/"), "")
+	network.EnvironmentName = name
+	return network
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// Key roles, describing what a key is meant to be used for
+const (
+	// KeyRoleDeployer creates subnets and blockchains, and manages their ownership
+	KeyRoleDeployer = "deployer"
+	// KeyRoleValidatorManager adds, removes and renews subnet validators
+	KeyRoleValidatorManager = "validator-manager"
+	// KeyRolePayer pays fees and stakes, and transfers funds
+	KeyRolePayer = "payer"
+)
+
+// Key policy modes, telling what to do when a key is used outside its policy
+const (
+	KeyPolicyWarn   = "warn"
+	KeyPolicyRefuse = "refuse"
+)
+
+// Network names used by key policies
+const (
+	KeyPolicyLocal   = "local"
+	KeyPolicyDevnet  = "devnet"
+	KeyPolicyTahoe   = "tahoe"
+	KeyPolicyMainnet = "mainnet"
+)
+
+// KeyRoles returns the roles a key can be tagged with
+func KeyRoles() []string {
+	return []string{KeyRoleDeployer, KeyRoleValidatorManager, KeyRolePayer}
+}
+
+// KeyPolicyNetworks returns the network names a key can be restricted to
+func KeyPolicyNetworks() []string {
+	return []string{KeyPolicyLocal, KeyPolicyDevnet, KeyPolicyTahoe, KeyPolicyMainnet}
+}
+
+// KeyPolicyNetworkName returns the key policy name of [network]
+func KeyPolicyNetworkName(network Network) string {
+	switch network.Kind {
+	case Local:
+		return KeyPolicyLocal
+	case Devnet:
+		return KeyPolicyDevnet
+	case Tahoe:
+		return KeyPolicyTahoe
+	case Mainnet:
+		return KeyPolicyMainnet
+	}
+	return ""
+}
+
+// KeyPolicyEntry restricts the usage of a key to some roles and networks
+type KeyPolicyEntry struct {
+	Roles []string
+	// Networks the key can be used on. Any network if empty
+	Networks []string `json:",omitempty"`
+}
+
+// KeyPolicy maps the names of stored keys, or KMS signers (eg kms:alias/subnet-owner),
+// to the roles they can be used for. Keys without entry can be used for anything
+type KeyPolicy struct {
+	// Mode is one of KeyPolicyWarn (default) or KeyPolicyRefuse
+	Mode string `json:",omitempty"`
+	Keys map[string]KeyPolicyEntry
+}
+
+// Refuses returns true if usages outside the policy must fail, instead of
+// just being warned about
+func (p KeyPolicy) Refuses() bool {
+	return p.Mode == KeyPolicyRefuse
+}
+
+// KeyNames returns the keys that have a policy entry, in alphabetical order
+func (p KeyPolicy) KeyNames() []string {
+	names := make([]string, 0, len(p.Keys))
+	for name := range p.Keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Violation describes how using [keyName] for [role] on [network] falls outside the
+// policy, or returns an empty string if the usage is allowed
+func (p KeyPolicy) Violation(keyName string, role string, network Network) string {
+	entry, ok := p.Keys[keyName]
+	if !ok {
+		return ""
+	}
+	if !slices.Contains(entry.Roles, role) {
+		return fmt.Sprintf("key %s is used as %s, but it is tagged with role(s) %s", keyName, role, strings.Join(entry.Roles, ", "))
+	}
+	networkName := KeyPolicyNetworkName(network)
+	if len(entry.Networks) > 0 && !slices.Contains(entry.Networks, networkName) {
+		return fmt.Sprintf("key %s is used on %s, but it is restricted to %s", keyName, networkName, strings.Join(entry.Networks, ", "))
+	}
+	return ""
+}
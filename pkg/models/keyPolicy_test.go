@@ -0,0 +1,69 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyPolicyViolation(t *testing.T) {
+	policy := KeyPolicy{
+		Keys: map[string]KeyPolicyEntry{
+			"owner": {Roles: []string{KeyRoleDeployer, KeyRoleValidatorManager}, Networks: []string{KeyPolicyMainnet}},
+			"fees":  {Roles: []string{KeyRolePayer}},
+		},
+	}
+	tests := []struct {
+		name      string
+		keyName   string
+		role      string
+		network   Network
+		violation string
+	}{
+		{
+			name:    "untagged key",
+			keyName: "test",
+			role:    KeyRoleDeployer,
+			network: NewLocalNetwork(),
+		},
+		{
+			name:    "allowed role and network",
+			keyName: "owner",
+			role:    KeyRoleValidatorManager,
+			network: NewMainnetNetwork(),
+		},
+		{
+			name:      "owner key on a local deploy",
+			keyName:   "owner",
+			role:      KeyRoleDeployer,
+			network:   NewLocalNetwork(),
+			violation: "key owner is used on local, but it is restricted to mainnet",
+		},
+		{
+			name:      "owner key paying",
+			keyName:   "owner",
+			role:      KeyRolePayer,
+			network:   NewMainnetNetwork(),
+			violation: "key owner is used as payer, but it is tagged with role(s) deployer, validator-manager",
+		},
+		{
+			name:    "key allowed on any network",
+			keyName: "fees",
+			role:    KeyRolePayer,
+			network: NewTahoeNetwork(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.violation, policy.Violation(tt.keyName, tt.role, tt.network))
+		})
+	}
+}
+
+func TestKeyPolicyMode(t *testing.T) {
+	require.False(t, KeyPolicy{}.Refuses())
+	require.False(t, KeyPolicy{Mode: KeyPolicyWarn}.Refuses())
+	require.True(t, KeyPolicy{Mode: KeyPolicyRefuse}.Refuses())
+}
@@ -41,10 +41,66 @@ type Network struct {
 	ID          uint32
 	Endpoint    string
 	ClusterName string
+	// LocalNetworkName is set for named local networks
+	LocalNetworkName string
+	// EnvironmentName is set for networks registered as environments
+	EnvironmentName string
 }
 
 var UndefinedNetwork = Network{}
 
+// HTTP port of the first default local network node, changed if it is configured
+var localAPIPort = constants.LocalNetworkBaseHTTPPort
+
+// selected named local network, and its index, see SetLocalNetwork
+var (
+	localNetworkName  string
+	localNetworkIndex int
+)
+
+// public network endpoints, changed if custom ones are configured
+var (
+	tahoeAPIEndpoint   = constants.TahoeAPIEndpoint
+	mainnetAPIEndpoint = constants.MainnetAPIEndpoint
+)
+
+// SetLocalAPIPort makes the selected local network endpoint use [port], the HTTP
+// port of its first node. The other local networks are shifted accordingly
+func SetLocalAPIPort(port int) {
+	localAPIPort = port - localNetworkIndex*constants.LocalNetworkPortsStep
+}
+
+// SetLocalNetwork makes the local network the named local network [name] with
+// index [index], or the default one if [name] is empty. A named local network has
+// its own API endpoint, and its own name, so it is recorded apart in the sidecars
+func SetLocalNetwork(name string, index int) {
+	localNetworkName = name
+	localNetworkIndex = index
+}
+
+// LocalAPIEndpoint returns the API endpoint of the selected local network
+func LocalAPIEndpoint() string {
+	return fmt.Sprintf("http://127.0.0.1:%d", localAPIPort+localNetworkIndex*constants.LocalNetworkPortsStep)
+}
+
+// SetTahoeAPIEndpoint makes Tahoe use [endpoint] as API endpoint. An empty
+// endpoint restores the default one
+func SetTahoeAPIEndpoint(endpoint string) {
+	tahoeAPIEndpoint = constants.TahoeAPIEndpoint
+	if endpoint != "" {
+		tahoeAPIEndpoint = strings.TrimSuffix(endpoint, "/")
+	}
+}
+
+// SetMainnetAPIEndpoint makes Mainnet use [endpoint] as API endpoint. An empty
+// endpoint restores the default one
+func SetMainnetAPIEndpoint(endpoint string) {
+	mainnetAPIEndpoint = constants.MainnetAPIEndpoint
+	if endpoint != "" {
+		mainnetAPIEndpoint = strings.TrimSuffix(endpoint, "/")
+	}
+}
+
 func NewNetwork(kind NetworkKind, id uint32, endpoint string, clusterName string) Network {
 	return Network{
 		Kind:        kind,
@@ -55,7 +111,9 @@ func NewNetwork(kind NetworkKind, id uint32, endpoint string, clusterName string
 }
 
 func NewLocalNetwork() Network {
-	return NewNetwork(Local, constants.LocalNetworkID, constants.LocalAPIEndpoint, "")
+	network := NewNetwork(Local, constants.LocalNetworkID, LocalAPIEndpoint(), "")
+	network.LocalNetworkName = localNetworkName
+	return network
 }
 
 func NewDevnetNetwork(endpoint string, id uint32) Network {
@@ -69,11 +127,11 @@ func NewDevnetNetwork(endpoint string, id uint32) Network {
 }
 
 func NewTahoeNetwork() Network {
-	return NewNetwork(Tahoe, avagoconstants.TahoeID, constants.TahoeAPIEndpoint, "")
+	return NewNetwork(Tahoe, avagoconstants.TahoeID, tahoeAPIEndpoint, "")
 }
 
 func NewMainnetNetwork() Network {
-	return NewNetwork(Mainnet, avagoconstants.MainnetID, constants.MainnetAPIEndpoint, "")
+	return NewNetwork(Mainnet, avagoconstants.MainnetID, mainnetAPIEndpoint, "")
 }
 
 func NewNetworkFromCluster(n Network, clusterName string) Network {
@@ -96,13 +154,24 @@ func (n Network) Name() string {
 	if n.ClusterName != "" {
 		return "Cluster " + n.ClusterName
 	}
+	if n.EnvironmentName != "" {
+		return EnvironmentNetworkPrefix + " " + n.EnvironmentName
+	}
 	name := n.Kind.String()
 	if n.Kind == Devnet {
 		name += " " + n.Endpoint
 	}
+	if n.Kind == Local && n.LocalNetworkName != "" {
+		name += " " + n.LocalNetworkName
+	}
 	return name
 }
 
+// IsLocalNetworkName tells if [name] is the name of the default or of a named local network
+func IsLocalNetworkName(name string) bool {
+	return name == Local.String() || strings.HasPrefix(name, Local.String()+" ")
+}
+
 func (n Network) CChainEndpoint() string {
 	return n.BlockchainEndpoint("C")
 }
@@ -155,6 +224,6 @@ func (n *Network) HandlePublicNetworkSimulation() {
 	if os.Getenv(constants.SimulatePublicNetwork) != "" {
 		n.Kind = Local
 		n.ID = constants.LocalNetworkID
-		n.Endpoint = constants.LocalAPIEndpoint
+		n.Endpoint = LocalAPIEndpoint()
 	}
 }
@@ -0,0 +1,64 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import (
+	"testing"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetPublicAPIEndpoints(t *testing.T) {
+	require := require.New(t)
+	defer SetTahoeAPIEndpoint("")
+	defer SetMainnetAPIEndpoint("")
+
+	SetTahoeAPIEndpoint("https://tahoe.example.org/")
+	SetMainnetAPIEndpoint("https://mainnet.example.org")
+	require.Equal("https://tahoe.example.org", NewTahoeNetwork().Endpoint)
+	require.Equal("https://tahoe.example.org/ext/bc/C/rpc", NetworkFromNetworkID(NewTahoeNetwork().ID).CChainEndpoint())
+	require.Equal("https://mainnet.example.org", NewMainnetNetwork().Endpoint)
+
+	SetTahoeAPIEndpoint("")
+	SetMainnetAPIEndpoint("")
+	require.Equal(constants.TahoeAPIEndpoint, NewTahoeNetwork().Endpoint)
+	require.Equal(constants.MainnetAPIEndpoint, NewMainnetNetwork().Endpoint)
+}
+
+func TestEnvironmentNetwork(t *testing.T) {
+	require := require.New(t)
+	staging := NewEnvironmentNetwork("staging-devnet", Environment{NetworkID: 12345, Endpoint: "https://staging.example.org/"})
+	require.Equal(Devnet, staging.Kind)
+	require.Equal(uint32(12345), staging.ID)
+	require.Equal("https://staging.example.org", staging.Endpoint)
+	require.Equal("Environment staging-devnet", staging.Name())
+
+	internal := NewEnvironmentNetwork("internal-testnet", Environment{NetworkID: NewTahoeNetwork().ID, Endpoint: "http://10.0.0.1:9650"})
+	require.Equal(Tahoe, internal.Kind)
+	require.Equal("Environment internal-testnet", internal.Name())
+	require.Equal(NewTahoeNetwork().GenesisParams(), internal.GenesisParams())
+
+	require.Equal([]string{"a", "b"}, Environments{"b": {}, "a": {}}.Names())
+}
+
+func TestNamedLocalNetwork(t *testing.T) {
+	require := require.New(t)
+	defer SetLocalNetwork("", 0)
+	defer SetLocalAPIPort(constants.LocalNetworkBaseHTTPPort)
+
+	require.Equal(constants.LocalAPIEndpoint, NewLocalNetwork().Endpoint)
+	require.Equal("Local Network", NewLocalNetwork().Name())
+
+	SetLocalNetwork("dev", 2)
+	require.Equal("http://127.0.0.1:9850", NewLocalNetwork().Endpoint)
+	require.Equal("Local Network dev", NewLocalNetwork().Name())
+	require.True(IsLocalNetworkName(NewLocalNetwork().Name()))
+	require.False(IsLocalNetworkName(NewTahoeNetwork().Name()))
+
+	// the configured port is the one of the selected network
+	SetLocalAPIPort(9900)
+	require.Equal("http://127.0.0.1:9900", NewLocalNetwork().Endpoint)
+	SetLocalNetwork("", 0)
+	require.Equal("http://127.0.0.1:9700", NewLocalNetwork().Endpoint)
+}
@@ -3,6 +3,8 @@
 package models
 
 import (
+	"time"
+
 	"github.com/MetalBlockchain/metal-network-runner/utils"
 	"github.com/MetalBlockchain/metalgo/ids"
 )
@@ -16,6 +18,24 @@ type NetworkData struct {
 	TeleporterRegistryAddress   string
 }
 
+// TxRecord keeps track of a P-Chain tx issued by the CLI on behalf of the subnet
+type TxRecord struct {
+	TxID      ids.ID
+	TxType    string
+	Timestamp time.Time
+	Issuer    string
+}
+
+// VMBinary identifies the VM binary a subnet was created or deployed with, so
+// changes of the local copy can be detected
+type VMBinary struct {
+	// VM version the binary was recorded for
+	Version string
+	SHA256  string
+	// download URL, source repository or path the binary was taken from
+	Source string
+}
+
 type PermissionlessValidators struct {
 	TxID ids.ID
 }
@@ -46,6 +66,7 @@ type Sidecar struct {
 	CustomVMRepoURL     string
 	CustomVMBranch      string
 	CustomVMBuildScript string
+	VMBinary            VMBinary
 	// Teleporter related
 	TeleporterReady   bool
 	TeleporterKey     string
@@ -53,6 +74,27 @@ type Sidecar struct {
 	RunRelayer        bool
 	// SubnetEVM based VM's only
 	SubnetEVMMainnetChainID uint
+	// Genesis supply of the native token, in wei, and whether it can be minted
+	// after genesis with the native minter precompile
+	TokenInitialSupply string
+	TokenMintable      bool
+	TokenMintAdmins    []string
+	// VMVersionPinned is set when VMVersion was explicitly given with --vm-version at
+	// creation time, so node upgrades keep using it instead of the latest release
+	VMVersionPinned bool
+	// P-Chain txs issued for this subnet, per network name
+	TxHistory map[string][]TxRecord
+}
+
+// AddTxRecords appends [records] to the tx history of network [networkName]
+func (sc *Sidecar) AddTxRecords(networkName string, records ...TxRecord) {
+	if len(records) == 0 {
+		return
+	}
+	if sc.TxHistory == nil {
+		sc.TxHistory = make(map[string][]TxRecord)
+	}
+	sc.TxHistory[networkName] = append(sc.TxHistory[networkName], records...)
 }
 
 func (sc Sidecar) GetVMID() (string, error) {
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/MetalBlockchain/metal-network-runner/utils"
+	"github.com/MetalBlockchain/metalgo/ids"
 	"github.com/stretchr/testify/require"
 )
 
@@ -38,3 +39,18 @@ func TestGetVMID_derived(t *testing.T) {
 	assert.NoError(err)
 	assert.Equal(expectedVMID.String(), vmid)
 }
+
+func TestAddTxRecords(t *testing.T) {
+	assert := require.New(t)
+	sc := Sidecar{}
+	sc.AddTxRecords("Tahoe")
+	assert.Nil(sc.TxHistory)
+
+	first := TxRecord{TxID: ids.GenerateTestID(), TxType: "CreateSubnet"}
+	second := TxRecord{TxID: ids.GenerateTestID(), TxType: "CreateChain"}
+	sc.AddTxRecords("Tahoe", first)
+	sc.AddTxRecords("Tahoe", second)
+	sc.AddTxRecords("Mainnet", first)
+	assert.Equal([]TxRecord{first, second}, sc.TxHistory["Tahoe"])
+	assert.Equal([]TxRecord{first}, sc.TxHistory["Mainnet"])
+}
@@ -0,0 +1,113 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+)
+
+const (
+	// SubnetInviteVersion is the version of the invite format written by this CLI
+	SubnetInviteVersion = 1
+	// SubnetInvitePrefix starts encoded invites, so they can be told apart from file paths
+	SubnetInvitePrefix = "metal-invite:"
+)
+
+var ErrInvalidSubnetInvite = errors.New("invalid subnet invite")
+
+// SubnetInvite holds everything a validator needs to join a subnet, without
+// access to the subnet configuration of its creator
+type SubnetInvite struct {
+	Version    int
+	SubnetName string
+	NetworkID  uint32
+	// Endpoint of the network, for devnets
+	Endpoint     string `json:",omitempty"`
+	SubnetID     ids.ID
+	BlockchainID ids.ID
+	// NodeID of the validator the invite was made for
+	NodeID    ids.NodeID
+	VM        VMType
+	VMVersion string `json:",omitempty"`
+	VMID      string
+	// VMBinaryURL is the VM binary, or a .tar.gz or .zip archive containing it
+	VMBinaryURL string
+	// VMBinarySHA256 is the hex SHA256 of the file at VMBinaryURL
+	VMBinarySHA256 string
+	// VMPlatform is the os/arch the binary at VMBinaryURL was built for
+	VMPlatform      string          `json:",omitempty"`
+	ChainConfig     json.RawMessage `json:",omitempty"`
+	SubnetConfig    json.RawMessage `json:",omitempty"`
+	NetworkUpgrades json.RawMessage `json:",omitempty"`
+}
+
+// Network returns the network the invited validator joins
+func (i SubnetInvite) Network() Network {
+	network := NetworkFromNetworkID(i.NetworkID)
+	if network.Kind == Undefined && i.Endpoint != "" {
+		network = NewDevnetNetwork(i.Endpoint, i.NetworkID)
+	}
+	return network
+}
+
+// Encode returns the invite as a single line string, safe to paste in URLs
+func (i SubnetInvite) Encode() (string, error) {
+	bs, err := json.Marshal(i)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(bs); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return SubnetInvitePrefix + base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// DecodeSubnetInvite parses an invite, either encoded by Encode or given as
+// its JSON bundle
+func DecodeSubnetInvite(s string) (SubnetInvite, error) {
+	s = strings.TrimSpace(s)
+	bs := []byte(s)
+	if strings.HasPrefix(s, SubnetInvitePrefix) {
+		compressed, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(s, SubnetInvitePrefix))
+		if err != nil {
+			return SubnetInvite{}, fmt.Errorf("%w: %w", ErrInvalidSubnetInvite, err)
+		}
+		r, err := gzip.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return SubnetInvite{}, fmt.Errorf("%w: %w", ErrInvalidSubnetInvite, err)
+		}
+		bs, err = io.ReadAll(r)
+		if err != nil {
+			return SubnetInvite{}, fmt.Errorf("%w: %w", ErrInvalidSubnetInvite, err)
+		}
+	}
+	var invite SubnetInvite
+	if err := json.Unmarshal(bs, &invite); err != nil {
+		return SubnetInvite{}, fmt.Errorf("%w: %w", ErrInvalidSubnetInvite, err)
+	}
+	if invite.Version > SubnetInviteVersion {
+		return SubnetInvite{}, fmt.Errorf("%w: version %d is not supported, update the CLI", ErrInvalidSubnetInvite, invite.Version)
+	}
+	if invite.SubnetID == ids.Empty || invite.VMID == "" || invite.VMBinaryURL == "" || invite.VMBinarySHA256 == "" {
+		return SubnetInvite{}, fmt.Errorf("%w: missing subnet or VM information", ErrInvalidSubnetInvite)
+	}
+	// the VM ID names the plugin binary, so it must not be able to point elsewhere
+	if _, err := ids.FromString(invite.VMID); err != nil {
+		return SubnetInvite{}, fmt.Errorf("%w: invalid VM ID %q: %w", ErrInvalidSubnetInvite, invite.VMID, err)
+	}
+	return invite, nil
+}
@@ -0,0 +1,75 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSubnetInvite() SubnetInvite {
+	return SubnetInvite{
+		Version:         SubnetInviteVersion,
+		SubnetName:      "testSubnet",
+		NetworkID:       NewTahoeNetwork().ID,
+		SubnetID:        ids.GenerateTestID(),
+		BlockchainID:    ids.GenerateTestID(),
+		NodeID:          ids.GenerateTestNodeID(),
+		VM:              SubnetEvm,
+		VMVersion:       "v0.6.0",
+		VMID:            ids.GenerateTestID().String(),
+		VMBinaryURL:     "https://github.com/MetalBlockchain/subnet-evm/releases/download/v0.6.0/subnet-evm_0.6.0_linux_amd64.tar.gz",
+		VMBinarySHA256:  "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		VMPlatform:      "linux/amd64",
+		ChainConfig:     json.RawMessage(`{"pruning-enabled":true}`),
+		NetworkUpgrades: json.RawMessage(`{"precompileUpgrades":[]}`),
+	}
+}
+
+func TestSubnetInviteEncodeDecode(t *testing.T) {
+	require := require.New(t)
+	invite := newTestSubnetInvite()
+	encoded, err := invite.Encode()
+	require.NoError(err)
+	require.NotContains(encoded, "\n")
+	decoded, err := DecodeSubnetInvite(encoded)
+	require.NoError(err)
+	require.Equal(invite, decoded)
+	require.Equal(NewTahoeNetwork(), decoded.Network())
+
+	// the JSON bundle is accepted as well
+	bundle, err := json.Marshal(invite)
+	require.NoError(err)
+	decoded, err = DecodeSubnetInvite(string(bundle))
+	require.NoError(err)
+	require.Equal(invite, decoded)
+}
+
+func TestSubnetInviteDevnet(t *testing.T) {
+	invite := newTestSubnetInvite()
+	invite.NetworkID = 4242
+	invite.Endpoint = "http://10.0.0.1:9650"
+	require.Equal(t, NewDevnetNetwork("http://10.0.0.1:9650", 4242), invite.Network())
+}
+
+func TestDecodeSubnetInviteInvalid(t *testing.T) {
+	invite := newTestSubnetInvite()
+	invite.VMBinarySHA256 = ""
+	incomplete, err := invite.Encode()
+	require.NoError(t, err)
+	invite = newTestSubnetInvite()
+	invite.Version = SubnetInviteVersion + 1
+	newer, err := invite.Encode()
+	require.NoError(t, err)
+	invite = newTestSubnetInvite()
+	invite.VMID = "../../bin/metalgo"
+	pathVMID, err := invite.Encode()
+	require.NoError(t, err)
+	for _, s := range []string{"", "{}", SubnetInvitePrefix + "not base64!", SubnetInvitePrefix + "aGVsbG8", incomplete, newer, pathVMID} {
+		_, err := DecodeSubnetInvite(s)
+		require.ErrorIs(t, err, ErrInvalidSubnetInvite, s)
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	"gopkg.in/yaml.v3"
+)
+
+// VM names of subnet specs
+const (
+	SubnetSpecVMSubnetEvm = "subnet-evm"
+	SubnetSpecVMCustom    = "custom"
+)
+
+// Network names of subnet specs
+const (
+	SubnetSpecLocal   = "local"
+	SubnetSpecDevnet  = "devnet"
+	SubnetSpecTahoe   = "tahoe"
+	SubnetSpecMainnet = "mainnet"
+)
+
+// SubnetSpec declares a subnet, its target networks and its validators, for
+// subnet apply to reconcile the actual state against
+type SubnetSpec struct {
+	Name string `yaml:"name"`
+	// VM is subnet-evm or custom
+	VM string `yaml:"vm"`
+	// VMVersion is the Subnet-EVM version, latest or pre-release
+	VMVersion string `yaml:"vmVersion"`
+	// Genesis is an optional genesis file, used instead of the EVM params
+	Genesis  string              `yaml:"genesis"`
+	EVM      SubnetSpecEVM       `yaml:"evm"`
+	CustomVM SubnetSpecCustomVM  `yaml:"customVM"`
+	Networks []SubnetSpecNetwork `yaml:"networks"`
+}
+
+type SubnetSpecEVM struct {
+	ChainID uint64 `yaml:"chainID"`
+	Token   string `yaml:"token"`
+	// Defaults uses the default fees, airdrop and precompiles
+	Defaults bool `yaml:"defaults"`
+}
+
+type SubnetSpecCustomVM struct {
+	Binary      string `yaml:"binary"`
+	RepoURL     string `yaml:"repoURL"`
+	Branch      string `yaml:"branch"`
+	BuildScript string `yaml:"buildScript"`
+}
+
+// SubnetSpecNetwork is a network the subnet is deployed to. It is one of local,
+// devnet, tahoe or mainnet, or a registered environment
+type SubnetSpecNetwork struct {
+	Network     string `yaml:"network"`
+	Environment string `yaml:"environment"`
+	// Endpoint of devnets
+	Endpoint string `yaml:"endpoint"`
+	// Key pays the fees, unless Ledger or Signer is set
+	Key    string `yaml:"key"`
+	Ledger bool   `yaml:"ledger"`
+	// Signer is a KMS key, as in kms:alias/subnet-owner
+	Signer string `yaml:"signer"`
+	// ControlKeys default to the fee paying key
+	ControlKeys []string              `yaml:"controlKeys"`
+	Threshold   uint32                `yaml:"threshold"`
+	Validators  []SubnetSpecValidator `yaml:"validators"`
+}
+
+type SubnetSpecValidator struct {
+	NodeID string `yaml:"nodeID"`
+	// Weight defaults to constants.DefaultStakeWeight
+	Weight uint64 `yaml:"weight"`
+	// StakingPeriod defaults to the remaining primary network validation
+	StakingPeriod time.Duration `yaml:"stakingPeriod"`
+}
+
+// Name returns the network or environment name of [n], for messages
+func (n SubnetSpecNetwork) Name() string {
+	if n.Environment != "" {
+		return "environment " + n.Environment
+	}
+	return n.Network
+}
+
+// LoadSubnetSpec reads and validates the subnet spec at [path]. Relative file
+// paths of the spec are resolved against its directory
+func LoadSubnetSpec(path string) (*SubnetSpec, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	spec := &SubnetSpec{}
+	decoder := yaml.NewDecoder(bytes.NewReader(bs))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(spec); err != nil {
+		return nil, fmt.Errorf("invalid subnet spec %s: %w", path, err)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid subnet spec %s: %w", path, err)
+	}
+	dir := filepath.Dir(path)
+	for _, p := range []*string{&spec.Genesis, &spec.CustomVM.Binary} {
+		if *p != "" && !filepath.IsAbs(*p) {
+			*p = filepath.Join(dir, *p)
+		}
+	}
+	return spec, nil
+}
+
+// Validate checks the spec is complete and consistent
+func (spec *SubnetSpec) Validate() error {
+	if spec.Name == "" {
+		return errors.New("name is required")
+	}
+	switch spec.VM {
+	case SubnetSpecVMSubnetEvm:
+		if spec.CustomVM != (SubnetSpecCustomVM{}) {
+			return errors.New("customVM can only be given with vm custom")
+		}
+		if spec.Genesis != "" && spec.EVM != (SubnetSpecEVM{}) {
+			return errors.New("genesis and evm are mutually exclusive")
+		}
+	case SubnetSpecVMCustom:
+		if spec.EVM != (SubnetSpecEVM{}) || spec.VMVersion != "" {
+			return errors.New("evm and vmVersion can only be given with vm subnet-evm")
+		}
+		if spec.Genesis == "" {
+			return errors.New("genesis is required with vm custom")
+		}
+		if (spec.CustomVM.Binary == "") == (spec.CustomVM.RepoURL == "") {
+			return errors.New("one of customVM binary or repoURL is required with vm custom")
+		}
+	default:
+		return fmt.Errorf("vm must be %s or %s, got %q", SubnetSpecVMSubnetEvm, SubnetSpecVMCustom, spec.VM)
+	}
+	seen := map[string]bool{}
+	for i, n := range spec.Networks {
+		switch {
+		case n.Network == "" && n.Environment == "":
+			return fmt.Errorf("networks[%d]: one of network or environment is required", i)
+		case n.Network != "" && n.Environment != "":
+			return fmt.Errorf("networks[%d]: network and environment are mutually exclusive", i)
+		case n.Network != "" && !isSubnetSpecNetwork(n.Network):
+			return fmt.Errorf("networks[%d]: network must be one of %s, %s, %s or %s, got %q",
+				i, SubnetSpecLocal, SubnetSpecDevnet, SubnetSpecTahoe, SubnetSpecMainnet, n.Network)
+		case n.Endpoint != "" && n.Network != SubnetSpecDevnet:
+			return fmt.Errorf("networks[%d]: endpoint can only be given for devnets", i)
+		case n.Key != "" && n.Ledger:
+			return fmt.Errorf("networks[%d]: key and ledger are mutually exclusive", i)
+		case n.Signer != "" && (n.Key != "" || n.Ledger):
+			return fmt.Errorf("networks[%d]: signer is mutually exclusive with key and ledger", i)
+		case n.Threshold > uint32(len(n.ControlKeys)) && len(n.ControlKeys) > 0:
+			return fmt.Errorf("networks[%d]: threshold %d is bigger than the number of control keys", i, n.Threshold)
+		}
+		if seen[n.Name()] {
+			return fmt.Errorf("networks[%d]: %s is given more than once", i, n.Name())
+		}
+		seen[n.Name()] = true
+		nodeIDs := map[string]bool{}
+		for j, v := range n.Validators {
+			if _, err := ids.NodeIDFromString(v.NodeID); err != nil {
+				return fmt.Errorf("networks[%d].validators[%d]: invalid nodeID %q: %w", i, j, v.NodeID, err)
+			}
+			if nodeIDs[v.NodeID] {
+				return fmt.Errorf("networks[%d].validators[%d]: %s is given more than once", i, j, v.NodeID)
+			}
+			nodeIDs[v.NodeID] = true
+		}
+	}
+	return nil
+}
+
+func isSubnetSpecNetwork(network string) bool {
+	switch network {
+	case SubnetSpecLocal, SubnetSpecDevnet, SubnetSpecTahoe, SubnetSpecMainnet:
+		return true
+	}
+	return false
+}
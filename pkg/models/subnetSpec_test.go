@@ -0,0 +1,161 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testSpecNodeID = "NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg"
+
+func TestLoadSubnetSpec(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "spec.yaml")
+	err := os.WriteFile(path, []byte(`
+name: mySubnet
+vm: custom
+genesis: genesis.json
+customVM:
+  binary: /opt/vm
+networks:
+  - network: tahoe
+    key: myKey
+    validators:
+      - nodeID: `+testSpecNodeID+`
+        weight: 30
+        stakingPeriod: 720h
+  - environment: staging
+`), 0o600)
+	require.NoError(err)
+
+	spec, err := LoadSubnetSpec(path)
+	require.NoError(err)
+	require.Equal("mySubnet", spec.Name)
+	require.Equal(filepath.Join(dir, "genesis.json"), spec.Genesis)
+	require.Equal("/opt/vm", spec.CustomVM.Binary)
+	require.Len(spec.Networks, 2)
+	require.Equal("tahoe", spec.Networks[0].Name())
+	require.Equal("environment staging", spec.Networks[1].Name())
+	require.Equal([]SubnetSpecValidator{
+		{NodeID: testSpecNodeID, Weight: 30, StakingPeriod: 720 * time.Hour},
+	}, spec.Networks[0].Validators)
+
+	err = os.WriteFile(path, []byte("name: mySubnet\nvm: subnet-evm\nvalidators: []\n"), 0o600)
+	require.NoError(err)
+	_, err = LoadSubnetSpec(path)
+	require.ErrorContains(err, "field validators not found")
+}
+
+func TestSubnetSpecValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		spec SubnetSpec
+		err  string
+	}{
+		{
+			name: "valid subnet-evm",
+			spec: SubnetSpec{
+				Name: "s",
+				VM:   SubnetSpecVMSubnetEvm,
+				EVM:  SubnetSpecEVM{ChainID: 1, Token: "T", Defaults: true},
+				Networks: []SubnetSpecNetwork{
+					{Network: SubnetSpecLocal, Validators: []SubnetSpecValidator{{NodeID: testSpecNodeID}}},
+				},
+			},
+		},
+		{
+			name: "missing name",
+			spec: SubnetSpec{VM: SubnetSpecVMSubnetEvm},
+			err:  "name is required",
+		},
+		{
+			name: "unknown vm",
+			spec: SubnetSpec{Name: "s", VM: "other"},
+			err:  "vm must be",
+		},
+		{
+			name: "custom without binary",
+			spec: SubnetSpec{Name: "s", VM: SubnetSpecVMCustom, Genesis: "g.json"},
+			err:  "one of customVM binary or repoURL is required",
+		},
+		{
+			name: "custom with evm",
+			spec: SubnetSpec{Name: "s", VM: SubnetSpecVMCustom, EVM: SubnetSpecEVM{ChainID: 1}},
+			err:  "evm and vmVersion can only be given",
+		},
+		{
+			name: "network and environment",
+			spec: SubnetSpec{
+				Name:     "s",
+				VM:       SubnetSpecVMSubnetEvm,
+				Networks: []SubnetSpecNetwork{{Network: SubnetSpecTahoe, Environment: "e"}},
+			},
+			err: "mutually exclusive",
+		},
+		{
+			name: "endpoint outside devnet",
+			spec: SubnetSpec{
+				Name:     "s",
+				VM:       SubnetSpecVMSubnetEvm,
+				Networks: []SubnetSpecNetwork{{Network: SubnetSpecTahoe, Endpoint: "http://localhost"}},
+			},
+			err: "endpoint can only be given for devnets",
+		},
+		{
+			name: "signer and ledger",
+			spec: SubnetSpec{
+				Name:     "s",
+				VM:       SubnetSpecVMSubnetEvm,
+				Networks: []SubnetSpecNetwork{{Network: SubnetSpecMainnet, Ledger: true, Signer: "kms:alias/subnet-owner"}},
+			},
+			err: "signer is mutually exclusive with key and ledger",
+		},
+		{
+			name: "duplicated network",
+			spec: SubnetSpec{
+				Name:     "s",
+				VM:       SubnetSpecVMSubnetEvm,
+				Networks: []SubnetSpecNetwork{{Network: SubnetSpecTahoe}, {Network: SubnetSpecTahoe}},
+			},
+			err: "tahoe is given more than once",
+		},
+		{
+			name: "invalid node id",
+			spec: SubnetSpec{
+				Name: "s",
+				VM:   SubnetSpecVMSubnetEvm,
+				Networks: []SubnetSpecNetwork{
+					{Network: SubnetSpecLocal, Validators: []SubnetSpecValidator{{NodeID: "bad"}}},
+				},
+			},
+			err: "invalid nodeID",
+		},
+		{
+			name: "duplicated validator",
+			spec: SubnetSpec{
+				Name: "s",
+				VM:   SubnetSpecVMSubnetEvm,
+				Networks: []SubnetSpecNetwork{
+					{Network: SubnetSpecLocal, Validators: []SubnetSpecValidator{{NodeID: testSpecNodeID}, {NodeID: testSpecNodeID}}},
+				},
+			},
+			err: "is given more than once",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.Validate()
+			if tt.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -25,6 +26,10 @@ type configInputs struct {
 	Host             string
 	NodeID           string
 	ChainID          string
+	ProjectName      string
+	Dir              string
+	PrometheusPort   string
+	GrafanaPort      string
 }
 
 //go:embed dashboards/*
@@ -131,3 +136,33 @@ func WritePromtailConfigSubnet(filePath string, ip string, port string, host str
 	}
 	return os.WriteFile(filePath, []byte(config), constants.WriteReadReadPerms)
 }
+
+// WriteLocalMonitoringFiles writes into [monitoringDir] a docker compose file running
+// Prometheus and Grafana, along with the Grafana provisioning files and dashboards
+func WriteLocalMonitoringFiles(monitoringDir string, projectName string, prometheusPort int, grafanaPort int) error {
+	if err := os.MkdirAll(filepath.Join(monitoringDir, constants.DashboardsDir), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	if err := WriteMonitoringJSONFiles(monitoringDir); err != nil {
+		return err
+	}
+	for _, staticConfig := range []string{"datasources.yml", "dashboardProviders.yml"} {
+		config, err := configs.ReadFile(filepath.Join("configs", staticConfig))
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(monitoringDir, staticConfig), config, constants.WriteReadReadPerms); err != nil {
+			return err
+		}
+	}
+	config, err := GenerateConfig("configs/localCompose.yml", "Local Monitoring Compose", configInputs{
+		ProjectName:    projectName,
+		Dir:            monitoringDir,
+		PrometheusPort: strconv.Itoa(prometheusPort),
+		GrafanaPort:    strconv.Itoa(grafanaPort),
+	})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(monitoringDir, constants.MonitoringComposeFileName), []byte(config), constants.WriteReadReadPerms)
+}
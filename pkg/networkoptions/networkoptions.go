@@ -5,12 +5,14 @@ package networkoptions
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/MetalBlockchain/metal-cli/cmd/flags"
 	"github.com/MetalBlockchain/metal-cli/pkg/application"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
 	"github.com/MetalBlockchain/metal-cli/pkg/utils"
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
 	"github.com/MetalBlockchain/metalgo/api/info"
@@ -27,6 +29,7 @@ const (
 	Local
 	Devnet
 	Cluster
+	Environment
 )
 
 func (n NetworkOption) String() string {
@@ -41,6 +44,8 @@ func (n NetworkOption) String() string {
 		return "Devnet"
 	case Cluster:
 		return "Cluster"
+	case Environment:
+		return models.EnvironmentNetworkPrefix
 	}
 	return "invalid network"
 }
@@ -57,17 +62,58 @@ func networkOptionFromString(s string) NetworkOption {
 		return Devnet
 	case "Cluster":
 		return Cluster
+	case models.EnvironmentNetworkPrefix:
+		return Environment
 	}
 	return Undefined
 }
 
+// networkOptionFromEnv returns the network option given by the METAL_NETWORK env
+// var, matched case insensitively, or Undefined if not set
+func networkOptionFromEnv(supportedNetworkOptions []NetworkOption) (NetworkOption, error) {
+	value, ok := prompts.EnvValue(prompts.NetworkEnvVarName)
+	if !ok {
+		return Undefined, nil
+	}
+	var networkOption NetworkOption
+	switch strings.ToLower(value) {
+	case "local", "local network":
+		networkOption = Local
+	case "devnet":
+		networkOption = Devnet
+	case "tahoe", "testnet", "fuji":
+		networkOption = Tahoe
+	case "mainnet":
+		networkOption = Mainnet
+	case "cluster":
+		networkOption = Cluster
+	case "environment":
+		networkOption = Environment
+	default:
+		return Undefined, fmt.Errorf("invalid network %q given by %s. use one of local, devnet, tahoe, mainnet, cluster or environment", value, prompts.NetworkEnvVarName)
+	}
+	if !slices.Contains(supportedNetworkOptions, networkOption) {
+		return Undefined, fmt.Errorf("network %s given by %s is not supported by this command", networkOption, prompts.NetworkEnvVarName)
+	}
+	return networkOption, nil
+}
+
+// ActiveNetworkDescription returns a user facing description of the active network
+func ActiveNetworkDescription(networkName string, clusterName string) string {
+	if networkOptionFromString(networkName) == Cluster {
+		return fmt.Sprintf("%s %s", networkName, clusterName)
+	}
+	return networkName
+}
+
 type NetworkFlags struct {
-	UseLocal    bool
-	UseDevnet   bool
-	UseTahoe    bool
-	UseMainnet  bool
-	Endpoint    string
-	ClusterName string
+	UseLocal        bool
+	UseDevnet       bool
+	UseTahoe        bool
+	UseMainnet      bool
+	Endpoint        string
+	ClusterName     string
+	EnvironmentName string
 }
 
 func AddNetworkFlagsToCmd(cmd *cobra.Command, networkFlags *NetworkFlags, alwaysAddEndpoint bool, supportedNetworkOptions []NetworkOption) {
@@ -86,10 +132,12 @@ func AddNetworkFlagsToCmd(cmd *cobra.Command, networkFlags *NetworkFlags, always
 			cmd.Flags().BoolVarP(&networkFlags.UseMainnet, "mainnet", "m", false, "operate on mainnet")
 		case Cluster:
 			cmd.Flags().StringVar(&networkFlags.ClusterName, "cluster", "", "operate on the given cluster")
+		case Environment:
+			cmd.Flags().StringVar(&networkFlags.EnvironmentName, "environment", "", "operate on the given registered environment")
 		}
 	}
 	if addEndpoint {
-		cmd.Flags().StringVar(&networkFlags.Endpoint, "endpoint", "", "use the given endpoint for network operations")
+		cmd.Flags().StringVar(&networkFlags.Endpoint, "endpoint", "", "use the given endpoint for network operations, instead of the default or configured one")
 	}
 }
 
@@ -106,6 +154,8 @@ func GetNetworkFromSidecarNetworkName(
 			return models.UndefinedNetwork, fmt.Errorf("expected 'Cluster clusterName' on network name %s", networkName)
 		}
 		return app.GetClusterNetwork(parts[1])
+	case strings.HasPrefix(networkName, Environment.String()+" "):
+		return app.GetEnvironmentNetwork(strings.TrimPrefix(networkName, Environment.String()+" "))
 	case strings.HasPrefix(networkName, Tahoe.String()):
 		return models.NewTahoeNetwork(), nil
 	case strings.HasPrefix(networkName, Mainnet.String()):
@@ -174,6 +224,22 @@ func GetSupportedNetworkOptionsForSubnet(
 	return filteredSupportedNetworkOptions, clusterNames, devnetEndpoints, nil
 }
 
+// getEnvironmentNamesForSubnet returns the environments [subnetName] was deployed to
+func getEnvironmentNamesForSubnet(app *application.Avalanche, subnetName string) ([]string, error) {
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for networkName := range sc.Networks {
+		if name, ok := strings.CutPrefix(networkName, Environment.String()+" "); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
 func GetNetworkFromCmdLineFlags(
 	app *application.Avalanche,
 	networkFlags NetworkFlags,
@@ -186,6 +252,7 @@ func GetNetworkFromCmdLineFlags(
 	filteredSupportedNetworkOptionsStrs := ""
 	scClusterNames := []string{}
 	scDevnetEndpoints := []string{}
+	scEnvironmentNames := []string{}
 	if subnetName != "" {
 		var filteredSupportedNetworkOptions []NetworkOption
 		filteredSupportedNetworkOptions, scClusterNames, scDevnetEndpoints, err = GetSupportedNetworkOptionsForSubnet(app, subnetName, supportedNetworkOptions)
@@ -198,14 +265,19 @@ func GetNetworkFromCmdLineFlags(
 			return models.UndefinedNetwork, fmt.Errorf("no supported deployed networks available on subnet %q. please deploy to one of: [%s]", subnetName, supportedNetworkOptionsStrs)
 		}
 		supportedNetworkOptions = filteredSupportedNetworkOptions
+		scEnvironmentNames, err = getEnvironmentNamesForSubnet(app, subnetName)
+		if err != nil {
+			return models.UndefinedNetwork, err
+		}
 	}
 	// supported flags
 	networkFlagsMap := map[NetworkOption]string{
-		Local:   "--local",
-		Devnet:  "--devnet",
-		Tahoe:   "--tahoe/--testnet",
-		Mainnet: "--mainnet",
-		Cluster: "--cluster",
+		Local:       "--local",
+		Devnet:      "--devnet",
+		Tahoe:       "--tahoe/--testnet",
+		Mainnet:     "--mainnet",
+		Cluster:     "--cluster",
+		Environment: "--environment",
 	}
 	supportedNetworksFlags := strings.Join(utils.Map(supportedNetworkOptions, func(n NetworkOption) string { return networkFlagsMap[n] }), ", ")
 	// received option
@@ -221,6 +293,8 @@ func GetNetworkFromCmdLineFlags(
 		networkOption = Mainnet
 	case networkFlags.ClusterName != "":
 		networkOption = Cluster
+	case networkFlags.EnvironmentName != "":
+		networkOption = Environment
 	}
 	// unsupported option
 	if networkOption != Undefined && !slices.Contains(supportedNetworkOptions, networkOption) {
@@ -234,15 +308,43 @@ func GetNetworkFromCmdLineFlags(
 			if len(scDevnetEndpoints) != 0 {
 				endpointsMsg = fmt.Sprintf(". valid devnet endpoints: [%s]", strings.Join(scDevnetEndpoints, ", "))
 			}
+			if len(scEnvironmentNames) != 0 {
+				endpointsMsg += fmt.Sprintf(". valid environments: [%s]", strings.Join(scEnvironmentNames, ", "))
+			}
 			errMsg = fmt.Errorf("network flag %s is not available on subnet %s. use one of %s or made a deploy for that network%s%s", networkFlagsMap[networkOption], subnetName, supportedNetworksFlags, clustersMsg, endpointsMsg)
 		}
 		return models.UndefinedNetwork, errMsg
 	}
 	// mutual exclusion
-	if !flags.EnsureMutuallyExclusive([]bool{networkFlags.UseLocal, networkFlags.UseDevnet, networkFlags.UseTahoe, networkFlags.UseMainnet, networkFlags.ClusterName != ""}) {
+	if !flags.EnsureMutuallyExclusive([]bool{networkFlags.UseLocal, networkFlags.UseDevnet, networkFlags.UseTahoe, networkFlags.UseMainnet, networkFlags.ClusterName != "", networkFlags.EnvironmentName != ""}) {
 		return models.UndefinedNetwork, fmt.Errorf("network flags %s are mutually exclusive", supportedNetworksFlags)
 	}
 
+	// then to the network given by env
+	if networkOption == Undefined {
+		networkOption, err = networkOptionFromEnv(supportedNetworkOptions)
+		if err != nil {
+			return models.UndefinedNetwork, err
+		}
+		if networkOption == Cluster {
+			return models.UndefinedNetwork, fmt.Errorf("%s=cluster requires the cluster name to be given by --cluster or %s", prompts.NetworkEnvVarName, prompts.EnvVarName("cluster"))
+		}
+		if networkOption == Environment {
+			return models.UndefinedNetwork, fmt.Errorf("%s=environment requires the environment name to be given by --environment or %s", prompts.NetworkEnvVarName, prompts.EnvVarName("environment"))
+		}
+	}
+
+	// default to the active network, if it is usable for this command
+	if networkOption == Undefined {
+		activeNetwork, activeCluster := app.GetActiveNetwork()
+		activeOption := networkOptionFromString(activeNetwork)
+		if activeOption != Undefined && slices.Contains(supportedNetworkOptions, activeOption) {
+			networkOption = activeOption
+			networkFlags.ClusterName = activeCluster
+			ux.Logger.PrintToUser("Using active network %s", ActiveNetworkDescription(activeNetwork, activeCluster))
+		}
+	}
+
 	if networkOption == Undefined {
 		if subnetName != "" && supportedNetworkOptionsStrs != filteredSupportedNetworkOptionsStrs {
 			ux.Logger.PrintToUser("currently supported deployed networks on %q for this command: [%s]", subnetName, filteredSupportedNetworkOptionsStrs)
@@ -262,8 +364,21 @@ func GetNetworkFromCmdLineFlags(
 				supportedNetworkOptions = append(supportedNetworkOptions[:index], supportedNetworkOptions[index+1:]...)
 			}
 		}
+		environments, err := app.LoadEnvironments()
+		if err != nil {
+			return models.UndefinedNetwork, err
+		}
+		environmentNames := environments.Names()
+		if subnetName != "" {
+			environmentNames = scEnvironmentNames
+		}
+		if len(environmentNames) == 0 {
+			if index, err := utils.GetIndexInSlice(supportedNetworkOptions, Environment); err == nil {
+				supportedNetworkOptions = append(supportedNetworkOptions[:index], supportedNetworkOptions[index+1:]...)
+			}
+		}
 		networkOptionStr, err := app.Prompt.CaptureList(
-			"Choose a network for the operation",
+			prompts.Keyed("network", "Choose a network for the operation"),
 			utils.Map(supportedNetworkOptions, func(n NetworkOption) string { return n.String() }),
 		)
 		if err != nil {
@@ -272,26 +387,35 @@ func GetNetworkFromCmdLineFlags(
 		networkOption = networkOptionFromString(networkOptionStr)
 		if networkOption == Cluster {
 			networkFlags.ClusterName, err = app.Prompt.CaptureList(
-				"Choose a cluster",
+				prompts.Keyed("cluster", "Choose a cluster"),
 				clusterNames,
 			)
 			if err != nil {
 				return models.UndefinedNetwork, err
 			}
 		}
+		if networkOption == Environment {
+			networkFlags.EnvironmentName, err = app.Prompt.CaptureList(
+				prompts.Keyed("environment", "Choose an environment"),
+				environmentNames,
+			)
+			if err != nil {
+				return models.UndefinedNetwork, err
+			}
+		}
 	}
 
 	if networkOption == Devnet && networkFlags.Endpoint == "" && requireDevnetEndpointSpecification {
 		if len(scDevnetEndpoints) != 0 {
 			networkFlags.Endpoint, err = app.Prompt.CaptureList(
-				"Choose an endpoint",
+				prompts.Keyed("endpoint", "Choose an endpoint"),
 				scDevnetEndpoints,
 			)
 			if err != nil {
 				return models.UndefinedNetwork, err
 			}
 		} else {
-			networkFlags.Endpoint, err = app.Prompt.CaptureURL(fmt.Sprintf("%s Network Endpoint", networkOption.String()), false)
+			networkFlags.Endpoint, err = app.Prompt.CaptureURL(prompts.Keyed("endpoint", fmt.Sprintf("%s Network Endpoint", networkOption.String())), false)
 			if err != nil {
 				return models.UndefinedNetwork, err
 			}
@@ -303,6 +427,9 @@ func GetNetworkFromCmdLineFlags(
 			return models.UndefinedNetwork, fmt.Errorf("subnet %s has not been deployed to cluster %s", subnetName, networkFlags.ClusterName)
 		}
 	}
+	if subnetName != "" && networkFlags.EnvironmentName != "" && !slices.Contains(scEnvironmentNames, networkFlags.EnvironmentName) {
+		return models.UndefinedNetwork, fmt.Errorf("subnet %s has not been deployed to environment %s", subnetName, networkFlags.EnvironmentName)
+	}
 
 	network := models.UndefinedNetwork
 	switch networkOption {
@@ -329,6 +456,11 @@ func GetNetworkFromCmdLineFlags(
 		if err != nil {
 			return models.UndefinedNetwork, err
 		}
+	case Environment:
+		network, err = app.GetEnvironmentNetwork(networkFlags.EnvironmentName)
+		if err != nil {
+			return models.UndefinedNetwork, err
+		}
 	}
 	// on all cases, enable user setting specific endpoint
 	if networkFlags.Endpoint != "" {
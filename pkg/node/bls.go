@@ -0,0 +1,89 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package node
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/bls"
+	"github.com/MetalBlockchain/metalgo/utils/formatting"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/signer"
+)
+
+var (
+	ErrInvalidProofOfPossession = errors.New("invalid BLS proof of possession")
+	ErrNoProofOfPossession      = errors.New("node has no BLS key")
+)
+
+// ParseProofOfPossession decodes the hex [publicKey] and [proofOfPossession] of a node's
+// BLS key, as given by the info.getNodeID API, and verifies that the proof is signed
+// by the key
+func ParseProofOfPossession(publicKey string, proofOfPossession string) (*signer.ProofOfPossession, error) {
+	publicKeyBytes, err := formatting.Decode(formatting.HexNC, publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: public key: %w", ErrInvalidProofOfPossession, err)
+	}
+	if len(publicKeyBytes) != bls.PublicKeyLen {
+		return nil, fmt.Errorf("%w: public key is %d bytes long, expected %d", ErrInvalidProofOfPossession, len(publicKeyBytes), bls.PublicKeyLen)
+	}
+	proofOfPossessionBytes, err := formatting.Decode(formatting.HexNC, proofOfPossession)
+	if err != nil {
+		return nil, fmt.Errorf("%w: proof of possession: %w", ErrInvalidProofOfPossession, err)
+	}
+	if len(proofOfPossessionBytes) != bls.SignatureLen {
+		return nil, fmt.Errorf("%w: proof of possession is %d bytes long, expected %d", ErrInvalidProofOfPossession, len(proofOfPossessionBytes), bls.SignatureLen)
+	}
+	pop := &signer.ProofOfPossession{}
+	copy(pop.PublicKey[:], publicKeyBytes)
+	copy(pop.ProofOfPossession[:], proofOfPossessionBytes)
+	if err := pop.Verify(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProofOfPossession, err)
+	}
+	return pop, nil
+}
+
+// GetAPIEndpoint returns the API endpoint of a node given either by URL, or by IP or
+// host name, with the default API port if none is given
+func GetAPIEndpoint(nodeAddr string) (string, error) {
+	nodeAddr = strings.TrimSuffix(strings.TrimSpace(nodeAddr), "/")
+	if nodeAddr == "" {
+		return "", errors.New("empty node address")
+	}
+	if !strings.Contains(nodeAddr, "://") {
+		if _, _, err := net.SplitHostPort(nodeAddr); err != nil {
+			nodeAddr = net.JoinHostPort(strings.Trim(nodeAddr, "[]"), fmt.Sprint(constants.AvalanchegoAPIPort))
+		}
+		nodeAddr = "http://" + nodeAddr
+	}
+	u, err := url.Parse(nodeAddr)
+	if err != nil {
+		return "", fmt.Errorf("invalid node address %q: %w", nodeAddr, err)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid node address %q: no host", nodeAddr)
+	}
+	return nodeAddr, nil
+}
+
+// GetProofOfPossession queries the info API of the node at [nodeAddr], that must be on
+// [network], for its NodeID and the verified proof of possession of its BLS key
+func GetProofOfPossession(nodeAddr string, network models.Network) (ids.NodeID, *signer.ProofOfPossession, error) {
+	nodeInfo, err := GetInfo(nodeAddr)
+	if err != nil {
+		return ids.EmptyNodeID, nil, err
+	}
+	if err := nodeInfo.CheckNetwork(network); err != nil {
+		return ids.EmptyNodeID, nil, err
+	}
+	if nodeInfo.ProofOfPossession == nil {
+		return ids.EmptyNodeID, nil, fmt.Errorf("%w: node %s at %s has no staking signer key", ErrNoProofOfPossession, nodeInfo.NodeID, nodeInfo.Endpoint)
+	}
+	return nodeInfo.NodeID, nodeInfo.ProofOfPossession, nil
+}
@@ -0,0 +1,84 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package node
+
+import (
+	"testing"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/bls"
+	"github.com/MetalBlockchain/metalgo/utils/formatting"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/signer"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProofOfPossession(t *testing.T) (*signer.ProofOfPossession, string, string) {
+	sk, err := bls.NewSecretKey()
+	require.NoError(t, err)
+	pop := signer.NewProofOfPossession(sk)
+	publicKey, err := formatting.Encode(formatting.HexNC, pop.PublicKey[:])
+	require.NoError(t, err)
+	proofOfPossession, err := formatting.Encode(formatting.HexNC, pop.ProofOfPossession[:])
+	require.NoError(t, err)
+	return pop, publicKey, proofOfPossession
+}
+
+func TestParseProofOfPossession(t *testing.T) {
+	require := require.New(t)
+	expected, publicKey, proofOfPossession := newTestProofOfPossession(t)
+	pop, err := ParseProofOfPossession(publicKey, proofOfPossession)
+	require.NoError(err)
+	require.Equal(expected.PublicKey, pop.PublicKey)
+	require.Equal(expected.ProofOfPossession, pop.ProofOfPossession)
+
+	_, otherPublicKey, otherProofOfPossession := newTestProofOfPossession(t)
+	for _, tc := range []struct {
+		publicKey         string
+		proofOfPossession string
+	}{
+		{publicKey: otherPublicKey, proofOfPossession: proofOfPossession},
+		{publicKey: publicKey, proofOfPossession: otherProofOfPossession},
+		{publicKey: publicKey[:len(publicKey)-2], proofOfPossession: proofOfPossession},
+		{publicKey: publicKey, proofOfPossession: proofOfPossession + "00"},
+		{publicKey: "0xzz", proofOfPossession: proofOfPossession},
+	} {
+		_, err := ParseProofOfPossession(tc.publicKey, tc.proofOfPossession)
+		require.ErrorIs(err, ErrInvalidProofOfPossession)
+	}
+}
+
+func TestGetAPIEndpoint(t *testing.T) {
+	for nodeAddr, expected := range map[string]string{
+		"10.0.0.1":                  "http://10.0.0.1:9650",
+		"10.0.0.1:9652":             "http://10.0.0.1:9652",
+		"node.example.com":          "http://node.example.com:9650",
+		"::1":                       "http://[::1]:9650",
+		"https://node.example.com/": "https://node.example.com",
+	} {
+		endpoint, err := GetAPIEndpoint(nodeAddr)
+		require.NoError(t, err)
+		require.Equal(t, expected, endpoint, nodeAddr)
+	}
+	_, err := GetAPIEndpoint("")
+	require.Error(t, err)
+}
+
+func TestGetProofOfPossession(t *testing.T) {
+	require := require.New(t)
+	expected, _, _ := newTestProofOfPossession(t)
+	nodeID := ids.GenerateTestNodeID()
+	server := newTestInfoServer(t, nodeID, expected)
+
+	gotNodeID, pop, err := GetProofOfPossession(server.URL, models.NewTahoeNetwork())
+	require.NoError(err)
+	require.Equal(nodeID, gotNodeID)
+	require.Equal(expected.PublicKey, pop.PublicKey)
+
+	// the node must be on the network the validator is added to
+	_, _, err = GetProofOfPossession(server.URL, models.NewMainnetNetwork())
+	require.ErrorContains(err, "is on network ID 5")
+
+	_, _, err = GetProofOfPossession(newTestInfoServer(t, nodeID, nil).URL, models.NewTahoeNetwork())
+	require.ErrorIs(err, ErrNoProofOfPossession)
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package node
+
+import (
+	"fmt"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metalgo/api/info"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/signer"
+)
+
+// Info is what the info API of a running node tells about it
+type Info struct {
+	Endpoint string
+	// NodeID is derived from the staking certificate of the node
+	NodeID ids.NodeID
+	// ProofOfPossession of the node's BLS key, nil if it has none
+	ProofOfPossession  *signer.ProofOfPossession `json:",omitempty"`
+	NetworkID          uint32
+	Version            string
+	DatabaseVersion    string
+	RPCProtocolVersion uint32
+	GitCommit          string
+	VMVersions         map[string]string
+}
+
+// GetInfo queries the info API of the node at [nodeAddr], given by URL, IP or host name,
+// for its NodeID, BLS key, network and versions. The BLS proof of possession is verified
+func GetInfo(nodeAddr string) (Info, error) {
+	endpoint, err := GetAPIEndpoint(nodeAddr)
+	if err != nil {
+		return Info{}, err
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	client := info.NewClient(endpoint)
+	nodeID, pop, err := client.GetNodeID(ctx)
+	if err != nil {
+		return Info{}, fmt.Errorf("failed to query node at %s - is it running and reachable? %w", endpoint, err)
+	}
+	if pop != nil {
+		if err := pop.Verify(); err != nil {
+			return Info{}, fmt.Errorf("%w: %w", ErrInvalidProofOfPossession, err)
+		}
+	}
+	networkID, err := client.GetNetworkID(ctx)
+	if err != nil {
+		return Info{}, fmt.Errorf("failed to get the network ID of node %s: %w", nodeID, err)
+	}
+	version, err := client.GetNodeVersion(ctx)
+	if err != nil {
+		return Info{}, fmt.Errorf("failed to get the version of node %s: %w", nodeID, err)
+	}
+	return Info{
+		Endpoint:           endpoint,
+		NodeID:             nodeID,
+		ProofOfPossession:  pop,
+		NetworkID:          networkID,
+		Version:            version.Version,
+		DatabaseVersion:    version.DatabaseVersion,
+		RPCProtocolVersion: uint32(version.RPCProtocolVersion),
+		GitCommit:          version.GitCommit,
+		VMVersions:         version.VMVersions,
+	}, nil
+}
+
+// CheckNetwork returns an error if the node is not on [network]
+func (i Info) CheckNetwork(network models.Network) error {
+	if i.NetworkID != network.ID {
+		return fmt.Errorf(
+			"node %s at %s is on network ID %d, not on %s (network ID %d)",
+			i.NodeID,
+			i.Endpoint,
+			i.NetworkID,
+			network.Name(),
+			network.ID,
+		)
+	}
+	return nil
+}
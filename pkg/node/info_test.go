@@ -0,0 +1,80 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package node
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/signer"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestInfoServer serves the info API of node [nodeID] on network ID 5, with the
+// BLS proof of possession [pop], if any
+func newTestInfoServer(t *testing.T, nodeID ids.NodeID, pop *signer.ProofOfPossession) *httptest.Server {
+	require := require.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		require.NoError(json.NewDecoder(r.Body).Decode(&req))
+		var result interface{}
+		switch req.Method {
+		case "info.getNodeID":
+			nodeIDResult := map[string]interface{}{"nodeID": nodeID}
+			if pop != nil {
+				nodeIDResult["nodePOP"] = pop
+			}
+			result = nodeIDResult
+		case "info.getNetworkID":
+			result = map[string]interface{}{"networkID": "5"}
+		case "info.getNodeVersion":
+			result = map[string]interface{}{
+				"version":            "metalgo/1.11.3",
+				"databaseVersion":    "v1.4.5",
+				"rpcProtocolVersion": "35",
+				"gitCommit":          "abcdef",
+				"vmVersions":         map[string]string{"platform": "v1.11.3"},
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		require.NoError(json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"result":  result,
+		}))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetInfo(t *testing.T) {
+	require := require.New(t)
+	pop, _, _ := newTestProofOfPossession(t)
+	nodeID := ids.GenerateTestNodeID()
+	server := newTestInfoServer(t, nodeID, pop)
+
+	info, err := GetInfo(server.URL)
+	require.NoError(err)
+	require.Equal(server.URL, info.Endpoint)
+	require.Equal(nodeID, info.NodeID)
+	require.Equal(pop.PublicKey, info.ProofOfPossession.PublicKey)
+	require.Equal(uint32(5), info.NetworkID)
+	require.Equal("metalgo/1.11.3", info.Version)
+	require.Equal(uint32(35), info.RPCProtocolVersion)
+	require.Equal(map[string]string{"platform": "v1.11.3"}, info.VMVersions)
+}
+
+func TestCheckNetwork(t *testing.T) {
+	require := require.New(t)
+	info := Info{NodeID: ids.GenerateTestNodeID(), NetworkID: 5}
+	require.NoError(info.CheckNetwork(models.NewTahoeNetwork()))
+	require.ErrorContains(info.CheckNetwork(models.NewMainnetNetwork()), "is on network ID 5")
+}
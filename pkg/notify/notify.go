@@ -0,0 +1,193 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package notify
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/audit"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/webhook"
+)
+
+const (
+	succeededKind = "succeeded"
+	failedKind    = "failed"
+)
+
+// Config tells where notifications are sent. Each channel is used if set
+type Config struct {
+	// WebhookURL receives a JSON webhook.Event, usable by Slack incoming webhooks
+	WebhookURL string
+	// SMTPServer is the host:port of the server used to email EmailTo
+	SMTPServer string
+	SMTPUser   string
+	// SMTPPassword is only used together with SMTPUser
+	SMTPPassword string
+	EmailFrom    string
+	EmailTo      []string
+}
+
+// Notification is the result of a command run
+type Notification struct {
+	Command  string
+	Error    string
+	TxIDs    []string
+	Duration time.Duration
+	Time     time.Time
+}
+
+// Enabled tells if any notification channel is set
+func (c Config) Enabled() bool {
+	return c.WebhookURL != "" || c.emailEnabled()
+}
+
+func (c Config) emailEnabled() bool {
+	return c.SMTPServer != "" && len(c.EmailTo) > 0
+}
+
+// IsNotified tells if the command with path [commandPath] (eg "metal subnet
+// deploy") is notified on completion. The state changing commands recorded on
+// the audit log are notified
+func IsNotified(commandPath string) bool {
+	return audit.IsStateChanging(commandPath)
+}
+
+func (n Notification) kind() string {
+	if n.Error != "" {
+		return failedKind
+	}
+	return succeededKind
+}
+
+// Text returns a one line summary of [n]
+func (n Notification) Text() string {
+	text := fmt.Sprintf("%s %s after %s", n.Command, n.kind(), n.Duration.Round(time.Second))
+	if n.Error != "" {
+		text += ": " + n.Error
+	}
+	return text
+}
+
+// Send sends [n] to every channel of [c], failing if any of them fails
+func Send(c Config, n Notification) error {
+	errs := []error{}
+	if c.WebhookURL != "" {
+		if err := webhook.Send(c.WebhookURL, webhook.Event{
+			Text:    n.Text(),
+			Kind:    n.kind(),
+			Command: n.Command,
+			TxIDs:   n.TxIDs,
+			Time:    n.Time,
+		}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if c.emailEnabled() {
+		if err := sendEmail(c, n); err != nil {
+			errs = append(errs, fmt.Errorf("failed emailing %s: %w", strings.Join(c.EmailTo, ", "), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func sendEmail(c Config, n Notification) error {
+	from := c.EmailFrom
+	if from == "" {
+		from = c.SMTPUser
+	}
+	if from == "" {
+		return errors.New("no sender address set")
+	}
+	var auth smtp.Auth
+	if c.SMTPUser != "" {
+		host, _, err := net.SplitHostPort(c.SMTPServer)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", c.SMTPUser, c.SMTPPassword, host)
+	}
+	return sendMail(c.SMTPServer, auth, from, c.EmailTo, emailMessage(from, c.EmailTo, n), constants.SMTPTimeout)
+}
+
+// sendMail works as smtp.SendMail, but the whole exchange with the server at [addr]
+// must be done within [timeout], so an unresponsive server can't block the CLI
+func sendMail(addr string, auth smtp.Auth, from string, to []string, msg []byte, timeout time.Duration) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		conn.Close()
+		return err
+	}
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("the server does not support authentication")
+		}
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, recipient := range to {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// emailMessage returns the RFC 5322 message for [n]
+func emailMessage(from string, to []string, n Notification) []byte {
+	body := strings.Builder{}
+	body.WriteString(n.Text() + "\r\n")
+	if len(n.TxIDs) > 0 {
+		body.WriteString("\r\nIssued txs:\r\n")
+		for _, txID := range n.TxIDs {
+			body.WriteString("  " + txID + "\r\n")
+		}
+	}
+	return []byte(fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: %s %s\r\nDate: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s",
+		from,
+		strings.Join(to, ", "),
+		n.Command,
+		n.kind(),
+		n.Time.Format(time.RFC1123Z),
+		body.String(),
+	))
+}
@@ -0,0 +1,102 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package notify
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/webhook"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsNotified(t *testing.T) {
+	require := require.New(t)
+	require.True(IsNotified("metal subnet deploy"))
+	require.True(IsNotified("metal node validate subnet"))
+	require.False(IsNotified("metal subnet describe"))
+	require.False(IsNotified("metal subnet deployments"))
+	require.False(IsNotified("metal"))
+}
+
+func TestNotificationText(t *testing.T) {
+	require := require.New(t)
+	n := Notification{Command: "metal subnet deploy", Duration: 90*time.Second + 300*time.Millisecond}
+	require.Equal("metal subnet deploy succeeded after 1m30s", n.Text())
+	n.Error = "insufficient funds"
+	require.Equal("metal subnet deploy failed after 1m30s: insufficient funds", n.Text())
+}
+
+func TestSendWebhook(t *testing.T) {
+	require := require.New(t)
+	events := []webhook.Event{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event := webhook.Event{}
+		require.NoError(json.NewDecoder(r.Body).Decode(&event))
+		events = append(events, event)
+	}))
+	defer server.Close()
+
+	conf := Config{WebhookURL: server.URL}
+	require.True(conf.Enabled())
+	err := Send(conf, Notification{
+		Command: "metal subnet addValidator",
+		TxIDs:   []string{"tx1"},
+		Error:   "rejected",
+	})
+	require.NoError(err)
+	require.Len(events, 1)
+	require.Equal(failedKind, events[0].Kind)
+	require.Equal("metal subnet addValidator", events[0].Command)
+	require.Equal([]string{"tx1"}, events[0].TxIDs)
+	require.Contains(events[0].Text, "rejected")
+}
+
+func TestSendErrors(t *testing.T) {
+	require := require.New(t)
+	require.False(Config{SMTPServer: "localhost:25"}.Enabled())
+	err := Send(Config{SMTPServer: "localhost:25", EmailTo: []string{"ops@example.com"}}, Notification{})
+	require.ErrorContains(err, "no sender address set")
+}
+
+func TestSendMailTimeout(t *testing.T) {
+	require := require.New(t)
+	// accepts connections, but never answers
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	defer listener.Close()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		<-done
+		conn.Close()
+	}()
+	start := time.Now()
+	err = sendMail(listener.Addr().String(), nil, "cli@example.com", []string{"ops@example.com"}, []byte("msg"), 100*time.Millisecond)
+	require.Error(err)
+	require.Less(time.Since(start), 5*time.Second)
+}
+
+func TestEmailMessage(t *testing.T) {
+	require := require.New(t)
+	msg := string(emailMessage("cli@example.com", []string{"a@example.com", "b@example.com"}, Notification{
+		Command: "metal node create",
+		TxIDs:   []string{"tx1", "tx2"},
+		Time:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}))
+	headers, body, found := strings.Cut(msg, "\r\n\r\n")
+	require.True(found)
+	require.Contains(headers, "To: a@example.com, b@example.com\r\n")
+	require.Contains(headers, "Subject: metal node create succeeded\r\n")
+	require.Contains(headers, "Date: Tue, 02 Jan 2024 03:04:05 +0000")
+	require.Contains(body, "Issued txs:\r\n  tx1\r\n  tx2\r\n")
+}
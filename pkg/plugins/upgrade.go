@@ -10,33 +10,36 @@ import (
 	"github.com/MetalBlockchain/metalgo/utils/logging"
 )
 
-func ManualUpgrade(app *application.Avalanche, sc models.Sidecar, targetVersion string) error {
+// ManualUpgrade creates the upgraded VM binary in a temporary plugin dir, prints the
+// instructions to install it on a node, and returns the binary path
+func ManualUpgrade(app *application.Avalanche, sc models.Sidecar, targetVersion string) (string, error) {
 	vmid, err := sc.GetVMID()
 	if err != nil {
-		return err
+		return "", err
 	}
 	pluginDir := app.GetTmpPluginDir()
 	vmPath, err := CreatePluginFromVersion(app, sc.Name, sc.VM, targetVersion, vmid, pluginDir)
 	if err != nil {
-		return err
+		return "", err
 	}
 	printUpgradeCmd(vmPath)
-	return nil
+	return vmPath, nil
 }
 
-func AutomatedUpgrade(app *application.Avalanche, sc models.Sidecar, targetVersion string, pluginDir string) error {
+// AutomatedUpgrade writes the upgraded VM binary into the node plugin dir, and returns the binary path
+func AutomatedUpgrade(app *application.Avalanche, sc models.Sidecar, targetVersion string, pluginDir string) (string, error) {
 	// Attempt an automated update
 	var err error
 	if pluginDir == "" {
 		pluginDir, err = FindPluginDir()
 		if err != nil {
-			return err
+			return "", err
 		}
 		if pluginDir != "" {
 			ux.Logger.PrintToUser(logging.Bold.Wrap(logging.Green.Wrap("Found the VM plugin directory at %s")), pluginDir)
 			yes, err := app.Prompt.CaptureYesNo("Is this where we should upgrade the VM?")
 			if err != nil {
-				return err
+				return "", err
 			}
 			if yes {
 				ux.Logger.PrintToUser("Will use plugin directory at %s to upgrade the VM", pluginDir)
@@ -47,28 +50,28 @@ func AutomatedUpgrade(app *application.Avalanche, sc models.Sidecar, targetVersi
 		if pluginDir == "" {
 			pluginDir, err = app.Prompt.CaptureString("Path to your metalgo plugin dir (likely ~/.metalgo/build/plugins)")
 			if err != nil {
-				return err
+				return "", err
 			}
 		}
 	}
 
 	pluginDir, err = SanitizePath(pluginDir)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	vmid, err := sc.GetVMID()
 	if err != nil {
-		return err
+		return "", err
 	}
 	vmPath, err := CreatePluginFromVersion(app, sc.Name, sc.VM, targetVersion, vmid, pluginDir)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	ux.Logger.PrintToUser("VM binary written to %s", vmPath)
 
-	return nil
+	return vmPath, nil
 }
 
 func printUpgradeCmd(vmPath string) {
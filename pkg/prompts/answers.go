@@ -0,0 +1,521 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package prompts
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrMissingAnswer is returned when the answers file has no answer for a prompt
+var ErrMissingAnswer = errors.New("missing answer")
+
+var (
+	answerKeysLock sync.Mutex
+	// explicit answer keys by prompt text, see Keyed
+	answerKeys = map[string]string{}
+)
+
+// Keyed returns [promptStr], setting [key] as its answer key on answers files.
+// The prompts of commands accepting answers files are keyed, so that the files
+// keep working when the prompt text changes
+func Keyed(key string, promptStr string) string {
+	answerKeysLock.Lock()
+	defer answerKeysLock.Unlock()
+	answerKeys[promptStr] = key
+	return promptStr
+}
+
+// missingAnswer is a prompt the answers file has no answer for
+type missingAnswer struct {
+	key    string
+	prompt string
+	// hint describes the expected answer, if given
+	hint string
+}
+
+// missingAnswerError lists the answers missing from an answers file. It is not a
+// non-interactive error: a missing answer fails even for optional prompts, that
+// are skipped on non-interactive mode
+type missingAnswerError struct {
+	path    string
+	missing []missingAnswer
+	// keys of the file not used so far
+	unused []string
+}
+
+func (e *missingAnswerError) Error() string {
+	msg := fmt.Sprintf("%s for %d prompts. Add them to %s as:", ErrMissingAnswer, len(e.missing), e.path)
+	if len(e.missing) == 1 {
+		msg = fmt.Sprintf("%s for prompt %q. Add it to %s as:", ErrMissingAnswer, e.missing[0].prompt, e.path)
+	}
+	for _, missing := range e.missing {
+		msg += fmt.Sprintf("\n  %s: <answer>", missing.key)
+		comments := []string{}
+		if len(e.missing) > 1 {
+			comments = append(comments, missing.prompt)
+		}
+		if missing.hint != "" {
+			comments = append(comments, missing.hint)
+		}
+		if len(comments) > 0 {
+			msg += " # " + strings.Join(comments, ", ")
+		}
+	}
+	if len(e.unused) > 0 {
+		msg += "\nAnswers not used so far: " + strings.Join(e.unused, ", ")
+	}
+	return msg
+}
+
+func (*missingAnswerError) Is(target error) bool {
+	return target == ErrMissingAnswer
+}
+
+// joinMissingAnswers returns an error listing the missing answers of all [errs],
+// that are missing answer errors of the same prompter, in order
+func joinMissingAnswers(errs []error) error {
+	joined := &missingAnswerError{}
+	for _, err := range errs {
+		var missingErr *missingAnswerError
+		if !errors.As(err, &missingErr) {
+			continue
+		}
+		joined.path = missingErr.path
+		joined.missing = append(joined.missing, missingErr.missing...)
+		joined.unused = missingErr.unused
+	}
+	return joined
+}
+
+var (
+	sentenceEndRegex = regexp.MustCompile(`[?.:!](\s|$)`)
+	nonWordRegex     = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// answersPrompter answers prompts from an answers file, so wizard commands can
+// run without user input from a versioned definition
+type answersPrompter struct {
+	path string
+	// pending answers by key. A key asked several times, eg on list decisions,
+	// takes the answers of a YAML sequence in order
+	answers map[string][]string
+}
+
+// NewAnswersPrompter creates a prompter that answers from the YAML file at
+// [path], mapping answer keys to answers. The key of a prompt is the one given
+// to it with Keyed, or else, its first sentence lower cased, with words
+// separated by dashes (see AnswerKey)
+func NewAnswersPrompter(path string) (Prompter, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	nodes := map[string]yaml.Node{}
+	if err := yaml.Unmarshal(bs, &nodes); err != nil {
+		return nil, fmt.Errorf("invalid answers file %s: %w", path, err)
+	}
+	answers := map[string][]string{}
+	for key, node := range nodes {
+		switch node.Kind {
+		case yaml.ScalarNode:
+			answers[key] = []string{node.Value}
+		case yaml.SequenceNode:
+			values := []string{}
+			for _, item := range node.Content {
+				if item.Kind != yaml.ScalarNode {
+					return nil, fmt.Errorf("invalid answers file %s: answer %q must be a value or a list of values", path, key)
+				}
+				values = append(values, item.Value)
+			}
+			answers[key] = values
+		default:
+			return nil, fmt.Errorf("invalid answers file %s: answer %q must be a value or a list of values", path, key)
+		}
+	}
+	return &answersPrompter{path: path, answers: answers}, nil
+}
+
+// AnswerKey returns the key of [promptStr] on answers files: the one given to it
+// with Keyed, or else, its first sentence lower cased, with words separated by
+// dashes, eg "What is the NodeID of the validator?" has key
+// what-is-the-nodeid-of-the-validator
+func AnswerKey(promptStr string) string {
+	answerKeysLock.Lock()
+	key, ok := answerKeys[promptStr]
+	answerKeysLock.Unlock()
+	if ok {
+		return key
+	}
+	if loc := sentenceEndRegex.FindStringIndex(promptStr); loc != nil {
+		promptStr = promptStr[:loc[0]]
+	}
+	return strings.Trim(nonWordRegex.ReplaceAllString(strings.ToLower(promptStr), "-"), "-")
+}
+
+// next returns the next answer for [promptStr], checked with [validate] if given
+func (p *answersPrompter) next(promptStr string, validate func(string) error) (string, error) {
+	key := AnswerKey(promptStr)
+	values := p.answers[key]
+	if len(values) == 0 {
+		return "", p.missingAnswerErr(promptStr, "")
+	}
+	value := values[0]
+	p.answers[key] = values[1:]
+	if validate != nil {
+		if err := validate(value); err != nil {
+			return "", fmt.Errorf("invalid answer %q for %s in %s: %w", value, key, p.path, err)
+		}
+	}
+	return value, nil
+}
+
+func (p *answersPrompter) missingAnswerErr(promptStr string, hint string) error {
+	unused := []string{}
+	for key, values := range p.answers {
+		if len(values) > 0 {
+			unused = append(unused, key)
+		}
+	}
+	sort.Strings(unused)
+	return &missingAnswerError{
+		path:    p.path,
+		missing: []missingAnswer{{key: AnswerKey(promptStr), prompt: promptStr, hint: hint}},
+		unused:  unused,
+	}
+}
+
+// nextOption returns the index of the next answer for [promptStr] in [options]
+func (p *answersPrompter) nextOption(promptStr string, options []string) (int, error) {
+	if len(p.answers[AnswerKey(promptStr)]) == 0 {
+		return 0, p.missingAnswerErr(promptStr, "one of: "+strings.Join(options, ", "))
+	}
+	index := -1
+	_, err := p.next(promptStr, func(value string) error {
+		for i, option := range options {
+			if strings.EqualFold(strings.TrimSpace(value), option) {
+				index = i
+				return nil
+			}
+		}
+		return fmt.Errorf("expected one of: %s", strings.Join(options, ", "))
+	})
+	return index, err
+}
+
+func (p *answersPrompter) CapturePositiveBigInt(promptStr string) (*big.Int, error) {
+	value, err := p.next(promptStr, validatePositiveBigInt)
+	if err != nil {
+		return nil, err
+	}
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return nil, errors.New("SetString: error")
+	}
+	return amount, nil
+}
+
+func (p *answersPrompter) CaptureAddress(promptStr string) (common.Address, error) {
+	value, err := p.next(promptStr, validateAddress)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return common.HexToAddress(value), nil
+}
+
+func (p *answersPrompter) CaptureNewFilepath(promptStr string) (string, error) {
+	return p.next(promptStr, validateNewFilepath)
+}
+
+func (p *answersPrompter) CaptureExistingFilepath(promptStr string) (string, error) {
+	return p.next(promptStr, validateExistingFilepath)
+}
+
+func (p *answersPrompter) CaptureYesNo(promptStr string) (bool, error) {
+	value, err := p.next(promptStr, validateYesNoAnswer)
+	if err != nil {
+		return false, err
+	}
+	return isYesAnswer(value), nil
+}
+
+func (p *answersPrompter) CaptureNoYes(promptStr string) (bool, error) {
+	return p.CaptureYesNo(promptStr)
+}
+
+func (p *answersPrompter) CaptureList(promptStr string, options []string) (string, error) {
+	index, err := p.nextOption(promptStr, options)
+	if err != nil {
+		return "", err
+	}
+	return options[index], nil
+}
+
+func (p *answersPrompter) CaptureListWithSize(promptStr string, options []string, _ int) (string, error) {
+	return p.CaptureList(promptStr, options)
+}
+
+func (p *answersPrompter) CaptureString(promptStr string) (string, error) {
+	return p.next(promptStr, validateNonEmpty)
+}
+
+func (p *answersPrompter) CaptureValidatedString(promptStr string, validator func(string) error) (string, error) {
+	return p.next(promptStr, validator)
+}
+
+func (p *answersPrompter) CaptureURL(promptStr string, validateConnection bool) (string, error) {
+	return p.next(promptStr, func(value string) error {
+		if err := validateURLFormat(value); err != nil {
+			return err
+		}
+		if validateConnection {
+			return ValidateURL(value)
+		}
+		return nil
+	})
+}
+
+func (p *answersPrompter) CaptureRepoBranch(promptStr string, repo string) (string, error) {
+	return p.next(promptStr, func(value string) error {
+		return ValidateRepoBranch(repo, value)
+	})
+}
+
+func (p *answersPrompter) CaptureRepoFile(promptStr string, repo string, branch string) (string, error) {
+	return p.next(promptStr, func(value string) error {
+		return ValidateRepoFile(repo, branch, value)
+	})
+}
+
+func (p *answersPrompter) CaptureGitURL(promptStr string) (*url.URL, error) {
+	value, err := p.next(promptStr, validateURLFormat)
+	if err != nil {
+		return nil, err
+	}
+	return url.ParseRequestURI(value)
+}
+
+func (p *answersPrompter) CaptureStringAllowEmpty(promptStr string) (string, error) {
+	return p.next(promptStr, nil)
+}
+
+func (p *answersPrompter) CaptureEmail(promptStr string) (string, error) {
+	return p.next(promptStr, validateEmail)
+}
+
+func (p *answersPrompter) CaptureIndex(promptStr string, options []any) (int, error) {
+	strOptions := make([]string, len(options))
+	for i, option := range options {
+		strOptions[i] = fmt.Sprint(option)
+	}
+	return p.nextOption(promptStr, strOptions)
+}
+
+func (p *answersPrompter) CaptureVersion(promptStr string) (string, error) {
+	return p.next(promptStr, func(value string) error {
+		if !semver.IsValid(value) {
+			return errors.New("version must be a legal semantic version (ex: v1.1.1)")
+		}
+		return nil
+	})
+}
+
+func (p *answersPrompter) CaptureFujiDuration(promptStr string) (time.Duration, error) {
+	value, err := p.next(promptStr, validateTahoeStakingDuration)
+	if err != nil {
+		return 0, err
+	}
+	return time.ParseDuration(value)
+}
+
+func (p *answersPrompter) CaptureMainnetDuration(promptStr string) (time.Duration, error) {
+	value, err := p.next(promptStr, validateMainnetStakingDuration)
+	if err != nil {
+		return 0, err
+	}
+	return time.ParseDuration(value)
+}
+
+func (p *answersPrompter) CaptureDate(promptStr string) (time.Time, error) {
+	value, err := p.next(promptStr, validateTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(constants.TimeParseLayout, value)
+}
+
+func (p *answersPrompter) CaptureNodeID(promptStr string) (ids.NodeID, error) {
+	value, err := p.next(promptStr, validateNodeID)
+	if err != nil {
+		return ids.EmptyNodeID, err
+	}
+	return ids.NodeIDFromString(value)
+}
+
+func (p *answersPrompter) CaptureID(promptStr string) (ids.ID, error) {
+	value, err := p.next(promptStr, validateID)
+	if err != nil {
+		return ids.Empty, err
+	}
+	return ids.FromString(value)
+}
+
+func (p *answersPrompter) CaptureWeight(promptStr string, validator func(uint64) error) (uint64, error) {
+	value, err := p.next(promptStr, func(input string) error {
+		return validateWeight(input, validator)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
+
+func (p *answersPrompter) CapturePositiveInt(promptStr string, comparators []Comparator) (int, error) {
+	value, err := p.next(promptStr, func(input string) error {
+		val, err := strconv.Atoi(input)
+		if err != nil {
+			return err
+		}
+		if val < 0 {
+			return errors.New("input is less than 0")
+		}
+		for _, comparator := range comparators {
+			if err := comparator.Validate(uint64(val)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(value)
+}
+
+func (p *answersPrompter) CaptureInt(promptStr string) (int, error) {
+	value, err := p.next(promptStr, func(input string) error {
+		_, err := strconv.Atoi(input)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(value)
+}
+
+func (p *answersPrompter) CaptureUint32(promptStr string) (uint32, error) {
+	value, err := p.next(promptStr, func(input string) error {
+		_, err := strconv.ParseUint(input, 0, 32)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	val, err := strconv.ParseUint(value, 0, 32)
+	return uint32(val), err
+}
+
+func (p *answersPrompter) CaptureUint64(promptStr string) (uint64, error) {
+	value, err := p.next(promptStr, validateBiggerThanZero)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(value, 0, 64)
+}
+
+func (p *answersPrompter) CaptureFloat(promptStr string, validator func(float64) error) (float64, error) {
+	value, err := p.next(promptStr, func(input string) error {
+		val, err := strconv.ParseFloat(input, 64)
+		if err != nil {
+			return err
+		}
+		return validator(val)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+func (p *answersPrompter) CaptureUint64Compare(promptStr string, comparators []Comparator) (uint64, error) {
+	value, err := p.next(promptStr, func(input string) error {
+		val, err := strconv.ParseUint(input, 0, 64)
+		if err != nil {
+			return err
+		}
+		for _, comparator := range comparators {
+			if err := comparator.Validate(val); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(value, 0, 64)
+}
+
+func (p *answersPrompter) CapturePChainAddress(promptStr string, network models.Network) (string, error) {
+	return p.next(promptStr, getPChainValidationFunc(network))
+}
+
+func (p *answersPrompter) CaptureXChainAddress(promptStr string, network models.Network) (string, error) {
+	return p.next(promptStr, getXChainValidationFunc(network))
+}
+
+func (p *answersPrompter) CaptureFutureDate(promptStr string, minDate time.Time) (time.Time, error) {
+	value, err := p.next(promptStr, func(s string) error {
+		t, err := time.Parse(constants.TimeParseLayout, s)
+		if err != nil {
+			return err
+		}
+		if minDate == (time.Time{}) {
+			minDate = time.Now()
+		}
+		if t.Before(minDate.UTC()) {
+			return fmt.Errorf("the provided date is before %s UTC", minDate.Format(constants.TimeParseLayout))
+		}
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(constants.TimeParseLayout, value)
+}
+
+func (p *answersPrompter) ChooseKeyOrLedger(goal string) (bool, error) {
+	return chooseKeyOrLedger(p, goal)
+}
+
+func validateYesNoAnswer(value string) error {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "yes", "y", "true", "no", "n", "false":
+		return nil
+	}
+	return errors.New("expected yes or no")
+}
+
+func isYesAnswer(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "yes", "y", "true":
+		return true
+	}
+	return false
+}
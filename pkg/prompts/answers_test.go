@@ -0,0 +1,106 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package prompts
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnswerKey(t *testing.T) {
+	require := require.New(t)
+	require.Equal("choose-your-vm", AnswerKey("Choose your VM"))
+	require.Equal("what-is-the-nodeid-of-the-validator-you-d-like-to-whitelist", AnswerKey("What is the NodeID of the validator you'd like to whitelist?"))
+	require.Equal("how-long-should-this-validator-be-validating", AnswerKey("How long should this validator be validating? Enter a duration, e.g. 8760h."))
+	require.Equal("set-version-v1-2-3", AnswerKey("Set version v1.2.3"))
+	require.Equal("Pick a duration", Keyed("duration", "Pick a duration"))
+	require.Equal("duration", AnswerKey("Pick a duration"))
+}
+
+func TestAnswersPrompter(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), "answers.yaml")
+	require.NoError(os.WriteFile(path, []byte(`
+choose-your-vm: subnet-evm
+duration: 48h
+do-you-want-to-continue: yes
+add-an-address:
+  - Add
+  - Done
+`), 0o600))
+	p, err := NewAnswersPrompter(path)
+	require.NoError(err)
+
+	vm, err := p.CaptureList("Choose your VM", []string{"Subnet-EVM", "Custom"})
+	require.NoError(err)
+	require.Equal("Subnet-EVM", vm)
+	d, err := p.CaptureFujiDuration(Keyed("duration", "How long should this validator be validating? Enter a duration"))
+	require.NoError(err)
+	require.Equal(48*time.Hour, d)
+	yes, err := p.CaptureNoYes("Do you want to continue?")
+	require.NoError(err)
+	require.True(yes)
+	for _, expected := range []string{"Add", "Done"} {
+		option, err := p.CaptureList("Add an address", []string{"Add", "Done"})
+		require.NoError(err)
+		require.Equal(expected, option)
+	}
+
+	_, err = p.CaptureWeight("What stake weight would you like to assign to the validator?", nil)
+	require.ErrorIs(err, ErrMissingAnswer)
+	require.False(errors.Is(err, ErrNonInteractive))
+	require.ErrorContains(err, "what-stake-weight-would-you-like-to-assign-to-the-validator: <answer>")
+
+	require.NoError(os.WriteFile(path, []byte("weight: 20\n"), 0o600))
+	p, err = NewAnswersPrompter(path)
+	require.NoError(err)
+	_, err = p.CaptureWeight(Keyed("weight", "Stake weight"), func(weight uint64) error {
+		if weight < 2000 {
+			return errors.New("weight below the subnet minimum stake")
+		}
+		return nil
+	})
+	require.ErrorContains(err, "weight below the subnet minimum stake")
+
+	require.NoError(os.WriteFile(path, []byte("choose-your-vm: other\n"), 0o600))
+	p, err = NewAnswersPrompter(path)
+	require.NoError(err)
+	_, err = p.CaptureList("Choose your VM", []string{"Subnet-EVM", "Custom"})
+	require.ErrorContains(err, "expected one of: Subnet-EVM, Custom")
+}
+
+func TestRunWizardMissingAnswers(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), "answers.yaml")
+	require.NoError(os.WriteFile(path, []byte("token-symbol: TEST\n"), 0o600))
+	p, err := NewAnswersPrompter(path)
+	require.NoError(err)
+
+	symbol := ""
+	steps := []WizardStep{
+		{Label: "ChainID", Ask: func() error {
+			_, err := p.CapturePositiveBigInt(Keyed("chain-id", "ChainId"))
+			return err
+		}},
+		{Label: "Token symbol", Ask: func() error {
+			symbol, err = p.CaptureString(Keyed("token-symbol", "Token symbol"))
+			return err
+		}},
+		{Label: "Mintable", Ask: func() error {
+			_, err := p.CaptureNoYes(Keyed("mintable", "Should it be possible to mint more tokens?"))
+			return err
+		}},
+	}
+	err = RunWizard(p, steps)
+	require.ErrorIs(err, ErrMissingAnswer)
+	require.ErrorContains(err, "missing answer for 2 prompts")
+	require.ErrorContains(err, "chain-id: <answer>")
+	require.ErrorContains(err, "mintable: <answer>")
+	require.NotContains(err.Error(), "token-symbol: <answer>")
+	require.Equal("TEST", symbol)
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package prompts
+
+import (
+	"os"
+	"strings"
+	"unicode"
+)
+
+// EnvVarPrefix is the prefix of the env vars that provide values for flags and
+// prompts, eg METAL_KEY for --key, or METAL_NODE_ID for --nodeID
+const EnvVarPrefix = "METAL_"
+
+// Names of the env vars resolved by prompts that have no flag equivalent
+const (
+	NetworkEnvVarName = EnvVarPrefix + "NETWORK"
+	KeyEnvVarName     = EnvVarPrefix + "KEY"
+	LedgerEnvVarName  = EnvVarPrefix + "LEDGER"
+)
+
+// EnvVarName returns the env var associated to a flag or value name, by
+// upper casing it and separating words with underscores, eg node-id and
+// nodeID both become METAL_NODE_ID
+func EnvVarName(name string) string {
+	var sb strings.Builder
+	sb.WriteString(EnvVarPrefix)
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case r == '-' || r == '.':
+			sb.WriteRune('_')
+		case unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]):
+			sb.WriteRune('_')
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	return sb.String()
+}
+
+// EnvValue returns the value of the env var envVarName, and whether it is set
+// to a non empty value
+func EnvValue(envVarName string) (string, bool) {
+	value := strings.TrimSpace(os.Getenv(envVarName))
+	return value, value != ""
+}
@@ -0,0 +1,167 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package prompts
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ErrNonInteractive = errors.New("input required in non-interactive mode")
+
+// nonInteractivePrompter fails on every prompt, so commands relying on user
+// input error out instead of blocking, eg in scripts and CI
+type nonInteractivePrompter struct{}
+
+// NewNonInteractivePrompter creates a prompter that never asks the user
+func NewNonInteractivePrompter() Prompter {
+	return &nonInteractivePrompter{}
+}
+
+func nonInteractiveErr(promptStr string) error {
+	return fmt.Errorf("%w: %q. Provide it with a flag, or disable non-interactive mode with 'metal config set NonInteractive false'",
+		ErrNonInteractive, promptStr)
+}
+
+func (*nonInteractivePrompter) CapturePositiveBigInt(promptStr string) (*big.Int, error) {
+	return nil, nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureAddress(promptStr string) (common.Address, error) {
+	return common.Address{}, nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureNewFilepath(promptStr string) (string, error) {
+	return "", nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureExistingFilepath(promptStr string) (string, error) {
+	return "", nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureYesNo(promptStr string) (bool, error) {
+	return false, nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureNoYes(promptStr string) (bool, error) {
+	return false, nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureList(promptStr string, _ []string) (string, error) {
+	return "", nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureListWithSize(promptStr string, _ []string, _ int) (string, error) {
+	return "", nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureString(promptStr string) (string, error) {
+	return "", nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureValidatedString(promptStr string, _ func(string) error) (string, error) {
+	return "", nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureURL(promptStr string, _ bool) (string, error) {
+	return "", nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureRepoBranch(promptStr string, _ string) (string, error) {
+	return "", nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureRepoFile(promptStr string, _ string, _ string) (string, error) {
+	return "", nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureGitURL(promptStr string) (*url.URL, error) {
+	return nil, nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureStringAllowEmpty(promptStr string) (string, error) {
+	return "", nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureEmail(promptStr string) (string, error) {
+	return "", nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureIndex(promptStr string, _ []any) (int, error) {
+	return 0, nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureVersion(promptStr string) (string, error) {
+	return "", nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureFujiDuration(promptStr string) (time.Duration, error) {
+	return 0, nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureMainnetDuration(promptStr string) (time.Duration, error) {
+	return 0, nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureDate(promptStr string) (time.Time, error) {
+	return time.Time{}, nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureNodeID(promptStr string) (ids.NodeID, error) {
+	return ids.EmptyNodeID, nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureID(promptStr string) (ids.ID, error) {
+	return ids.Empty, nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureWeight(promptStr string, _ func(uint64) error) (uint64, error) {
+	return 0, nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CapturePositiveInt(promptStr string, _ []Comparator) (int, error) {
+	return 0, nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureInt(promptStr string) (int, error) {
+	return 0, nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureUint32(promptStr string) (uint32, error) {
+	return 0, nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureUint64(promptStr string) (uint64, error) {
+	return 0, nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureFloat(promptStr string, _ func(float64) error) (float64, error) {
+	return 0, nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureUint64Compare(promptStr string, _ []Comparator) (uint64, error) {
+	return 0, nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CapturePChainAddress(promptStr string, _ models.Network) (string, error) {
+	return "", nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureXChainAddress(promptStr string, _ models.Network) (string, error) {
+	return "", nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) CaptureFutureDate(promptStr string, _ time.Time) (time.Time, error) {
+	return time.Time{}, nonInteractiveErr(promptStr)
+}
+
+func (*nonInteractivePrompter) ChooseKeyOrLedger(goal string) (bool, error) {
+	return false, nonInteractiveErr("choose a key or ledger to " + goal)
+}
@@ -8,6 +8,7 @@ import (
 	"math/big"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -94,7 +95,7 @@ type Prompter interface {
 	CaptureDate(promptStr string) (time.Time, error)
 	CaptureNodeID(promptStr string) (ids.NodeID, error)
 	CaptureID(promptStr string) (ids.ID, error)
-	CaptureWeight(promptStr string) (uint64, error)
+	CaptureWeight(promptStr string, validator func(uint64) error) (uint64, error)
 	CapturePositiveInt(promptStr string, comparators []Comparator) (int, error)
 	CaptureInt(promptStr string) (int, error)
 	CaptureUint32(promptStr string) (uint32, error)
@@ -107,7 +108,10 @@ type Prompter interface {
 	ChooseKeyOrLedger(goal string) (bool, error)
 }
 
-type realPrompter struct{}
+type realPrompter struct {
+	// set while asking a wizard step, see RunWizard
+	wizard *wizardState
+}
 
 // NewProcessChecker creates a new process checker which can respond if the server is running
 func NewPrompter() Prompter {
@@ -188,13 +192,13 @@ func CaptureListDecision[T comparable](
 	}
 }
 
-func (*realPrompter) CaptureFujiDuration(promptStr string) (time.Duration, error) {
+func (p *realPrompter) CaptureFujiDuration(promptStr string) (time.Duration, error) {
 	prompt := promptui.Prompt{
 		Label:    promptStr,
 		Validate: validateTahoeStakingDuration,
 	}
 
-	durationStr, err := prompt.Run()
+	durationStr, err := p.runPrompt(prompt)
 	if err != nil {
 		return 0, err
 	}
@@ -202,13 +206,13 @@ func (*realPrompter) CaptureFujiDuration(promptStr string) (time.Duration, error
 	return time.ParseDuration(durationStr)
 }
 
-func (*realPrompter) CaptureMainnetDuration(promptStr string) (time.Duration, error) {
+func (p *realPrompter) CaptureMainnetDuration(promptStr string) (time.Duration, error) {
 	prompt := promptui.Prompt{
 		Label:    promptStr,
 		Validate: validateMainnetStakingDuration,
 	}
 
-	durationStr, err := prompt.Run()
+	durationStr, err := p.runPrompt(prompt)
 	if err != nil {
 		return 0, err
 	}
@@ -216,13 +220,13 @@ func (*realPrompter) CaptureMainnetDuration(promptStr string) (time.Duration, er
 	return time.ParseDuration(durationStr)
 }
 
-func (*realPrompter) CaptureDate(promptStr string) (time.Time, error) {
+func (p *realPrompter) CaptureDate(promptStr string) (time.Time, error) {
 	prompt := promptui.Prompt{
 		Label:    promptStr,
 		Validate: validateTime,
 	}
 
-	timeStr, err := prompt.Run()
+	timeStr, err := p.runPrompt(prompt)
 	if err != nil {
 		return time.Time{}, err
 	}
@@ -230,39 +234,41 @@ func (*realPrompter) CaptureDate(promptStr string) (time.Time, error) {
 	return time.Parse(constants.TimeParseLayout, timeStr)
 }
 
-func (*realPrompter) CaptureID(promptStr string) (ids.ID, error) {
+func (p *realPrompter) CaptureID(promptStr string) (ids.ID, error) {
 	prompt := promptui.Prompt{
 		Label:    promptStr,
 		Validate: validateID,
 	}
 
-	idStr, err := prompt.Run()
+	idStr, err := p.runPrompt(prompt)
 	if err != nil {
 		return ids.Empty, err
 	}
 	return ids.FromString(idStr)
 }
 
-func (*realPrompter) CaptureNodeID(promptStr string) (ids.NodeID, error) {
+func (p *realPrompter) CaptureNodeID(promptStr string) (ids.NodeID, error) {
 	prompt := promptui.Prompt{
 		Label:    promptStr,
 		Validate: validateNodeID,
 	}
 
-	nodeIDStr, err := prompt.Run()
+	nodeIDStr, err := p.runPrompt(prompt)
 	if err != nil {
 		return ids.EmptyNodeID, err
 	}
 	return ids.NodeIDFromString(nodeIDStr)
 }
 
-func (*realPrompter) CaptureWeight(promptStr string) (uint64, error) {
+func (p *realPrompter) CaptureWeight(promptStr string, validator func(uint64) error) (uint64, error) {
 	prompt := promptui.Prompt{
-		Label:    promptStr,
-		Validate: validateWeight,
+		Label: promptStr,
+		Validate: func(input string) error {
+			return validateWeight(input, validator)
+		},
 	}
 
-	amountStr, err := prompt.Run()
+	amountStr, err := p.runPrompt(prompt)
 	if err != nil {
 		return 0, err
 	}
@@ -270,7 +276,7 @@ func (*realPrompter) CaptureWeight(promptStr string) (uint64, error) {
 	return strconv.ParseUint(amountStr, 10, 64)
 }
 
-func (*realPrompter) CaptureInt(promptStr string) (int, error) {
+func (p *realPrompter) CaptureInt(promptStr string) (int, error) {
 	prompt := promptui.Prompt{
 		Label: promptStr,
 		Validate: func(input string) error {
@@ -281,7 +287,7 @@ func (*realPrompter) CaptureInt(promptStr string) (int, error) {
 			return nil
 		},
 	}
-	input, err := prompt.Run()
+	input, err := p.runPrompt(prompt)
 	if err != nil {
 		return 0, err
 	}
@@ -292,7 +298,7 @@ func (*realPrompter) CaptureInt(promptStr string) (int, error) {
 	return val, nil
 }
 
-func (*realPrompter) CaptureUint32(promptStr string) (uint32, error) {
+func (p *realPrompter) CaptureUint32(promptStr string) (uint32, error) {
 	prompt := promptui.Prompt{
 		Label: promptStr,
 		Validate: func(input string) error {
@@ -303,7 +309,7 @@ func (*realPrompter) CaptureUint32(promptStr string) (uint32, error) {
 			return nil
 		},
 	}
-	input, err := prompt.Run()
+	input, err := p.runPrompt(prompt)
 	if err != nil {
 		return 0, err
 	}
@@ -314,20 +320,20 @@ func (*realPrompter) CaptureUint32(promptStr string) (uint32, error) {
 	return uint32(val), nil
 }
 
-func (*realPrompter) CaptureUint64(promptStr string) (uint64, error) {
+func (p *realPrompter) CaptureUint64(promptStr string) (uint64, error) {
 	prompt := promptui.Prompt{
 		Label:    promptStr,
 		Validate: validateBiggerThanZero,
 	}
 
-	amountStr, err := prompt.Run()
+	amountStr, err := p.runPrompt(prompt)
 	if err != nil {
 		return 0, err
 	}
 	return strconv.ParseUint(amountStr, 0, 64)
 }
 
-func (*realPrompter) CaptureFloat(promptStr string, validator func(float64) error) (float64, error) {
+func (p *realPrompter) CaptureFloat(promptStr string, validator func(float64) error) (float64, error) {
 	prompt := promptui.Prompt{
 		Label: promptStr,
 		Validate: func(input string) error {
@@ -339,14 +345,14 @@ func (*realPrompter) CaptureFloat(promptStr string, validator func(float64) erro
 		},
 	}
 
-	amountStr, err := prompt.Run()
+	amountStr, err := p.runPrompt(prompt)
 	if err != nil {
 		return 0, err
 	}
 	return strconv.ParseFloat(amountStr, 64)
 }
 
-func (*realPrompter) CapturePositiveInt(promptStr string, comparators []Comparator) (int, error) {
+func (p *realPrompter) CapturePositiveInt(promptStr string, comparators []Comparator) (int, error) {
 	prompt := promptui.Prompt{
 		Label: promptStr,
 		Validate: func(input string) error {
@@ -366,14 +372,14 @@ func (*realPrompter) CapturePositiveInt(promptStr string, comparators []Comparat
 		},
 	}
 
-	amountStr, err := prompt.Run()
+	amountStr, err := p.runPrompt(prompt)
 	if err != nil {
 		return 0, err
 	}
 	return strconv.Atoi(amountStr)
 }
 
-func (*realPrompter) CaptureUint64Compare(promptStr string, comparators []Comparator) (uint64, error) {
+func (p *realPrompter) CaptureUint64Compare(promptStr string, comparators []Comparator) (uint64, error) {
 	prompt := promptui.Prompt{
 		Label: promptStr,
 		Validate: func(input string) error {
@@ -390,7 +396,7 @@ func (*realPrompter) CaptureUint64Compare(promptStr string, comparators []Compar
 		},
 	}
 
-	amountStr, err := prompt.Run()
+	amountStr, err := p.runPrompt(prompt)
 	if err != nil {
 		return 0, err
 	}
@@ -398,13 +404,13 @@ func (*realPrompter) CaptureUint64Compare(promptStr string, comparators []Compar
 	return strconv.ParseUint(amountStr, 0, 64)
 }
 
-func (*realPrompter) CapturePositiveBigInt(promptStr string) (*big.Int, error) {
+func (p *realPrompter) CapturePositiveBigInt(promptStr string) (*big.Int, error) {
 	prompt := promptui.Prompt{
 		Label:    promptStr,
 		Validate: validatePositiveBigInt,
 	}
 
-	amountStr, err := prompt.Run()
+	amountStr, err := p.runPrompt(prompt)
 	if err != nil {
 		return nil, err
 	}
@@ -417,31 +423,31 @@ func (*realPrompter) CapturePositiveBigInt(promptStr string) (*big.Int, error) {
 	return amountInt, nil
 }
 
-func (*realPrompter) CapturePChainAddress(promptStr string, network models.Network) (string, error) {
+func (p *realPrompter) CapturePChainAddress(promptStr string, network models.Network) (string, error) {
 	prompt := promptui.Prompt{
 		Label:    promptStr,
 		Validate: getPChainValidationFunc(network),
 	}
 
-	return prompt.Run()
+	return p.runPrompt(prompt)
 }
 
-func (*realPrompter) CaptureXChainAddress(promptStr string, network models.Network) (string, error) {
+func (p *realPrompter) CaptureXChainAddress(promptStr string, network models.Network) (string, error) {
 	prompt := promptui.Prompt{
 		Label:    promptStr,
 		Validate: getXChainValidationFunc(network),
 	}
 
-	return prompt.Run()
+	return p.runPrompt(prompt)
 }
 
-func (*realPrompter) CaptureAddress(promptStr string) (common.Address, error) {
+func (p *realPrompter) CaptureAddress(promptStr string) (common.Address, error) {
 	prompt := promptui.Prompt{
 		Label:    promptStr,
 		Validate: validateAddress,
 	}
 
-	addressStr, err := prompt.Run()
+	addressStr, err := p.runPrompt(prompt)
 	if err != nil {
 		return common.Address{}, err
 	}
@@ -450,13 +456,13 @@ func (*realPrompter) CaptureAddress(promptStr string) (common.Address, error) {
 	return addressHex, nil
 }
 
-func (*realPrompter) CaptureExistingFilepath(promptStr string) (string, error) {
+func (p *realPrompter) CaptureExistingFilepath(promptStr string) (string, error) {
 	prompt := promptui.Prompt{
 		Label:    promptStr,
 		Validate: validateExistingFilepath,
 	}
 
-	pathStr, err := prompt.Run()
+	pathStr, err := p.runPrompt(prompt)
 	if err != nil {
 		return "", err
 	}
@@ -464,13 +470,13 @@ func (*realPrompter) CaptureExistingFilepath(promptStr string) (string, error) {
 	return pathStr, nil
 }
 
-func (*realPrompter) CaptureNewFilepath(promptStr string) (string, error) {
+func (p *realPrompter) CaptureNewFilepath(promptStr string) (string, error) {
 	prompt := promptui.Prompt{
 		Label:    promptStr,
 		Validate: validateNewFilepath,
 	}
 
-	pathStr, err := prompt.Run()
+	pathStr, err := p.runPrompt(prompt)
 	if err != nil {
 		return "", err
 	}
@@ -478,59 +484,59 @@ func (*realPrompter) CaptureNewFilepath(promptStr string) (string, error) {
 	return pathStr, nil
 }
 
-func yesNoBase(promptStr string, orderedOptions []string) (bool, error) {
+func (p *realPrompter) yesNoBase(promptStr string, orderedOptions []string) (bool, error) {
 	prompt := promptui.Select{
 		Label: promptStr,
 		Items: orderedOptions,
 	}
 
-	_, decision, err := prompt.Run()
+	_, decision, err := p.runSelect(prompt)
 	if err != nil {
 		return false, err
 	}
 	return decision == Yes, nil
 }
 
-func (*realPrompter) CaptureYesNo(promptStr string) (bool, error) {
-	return yesNoBase(promptStr, []string{Yes, No})
+func (p *realPrompter) CaptureYesNo(promptStr string) (bool, error) {
+	return p.yesNoBase(promptStr, []string{Yes, No})
 }
 
-func (*realPrompter) CaptureNoYes(promptStr string) (bool, error) {
-	return yesNoBase(promptStr, []string{No, Yes})
+func (p *realPrompter) CaptureNoYes(promptStr string) (bool, error) {
+	return p.yesNoBase(promptStr, []string{No, Yes})
 }
 
-func (*realPrompter) CaptureList(promptStr string, options []string) (string, error) {
+func (p *realPrompter) CaptureList(promptStr string, options []string) (string, error) {
 	prompt := promptui.Select{
 		Label: promptStr,
 		Items: options,
 	}
-	_, listDecision, err := prompt.Run()
+	_, listDecision, err := p.runSelect(prompt)
 	if err != nil {
 		return "", err
 	}
 	return listDecision, nil
 }
 
-func (*realPrompter) CaptureListWithSize(promptStr string, options []string, size int) (string, error) {
+func (p *realPrompter) CaptureListWithSize(promptStr string, options []string, size int) (string, error) {
 	prompt := promptui.Select{
 		Label: promptStr,
 		Items: options,
 		Size:  size,
 	}
-	_, listDecision, err := prompt.Run()
+	_, listDecision, err := p.runSelect(prompt)
 	if err != nil {
 		return "", err
 	}
 	return listDecision, nil
 }
 
-func (*realPrompter) CaptureEmail(promptStr string) (string, error) {
+func (p *realPrompter) CaptureEmail(promptStr string) (string, error) {
 	prompt := promptui.Prompt{
 		Label:    promptStr,
 		Validate: validateEmail,
 	}
 
-	str, err := prompt.Run()
+	str, err := p.runPrompt(prompt)
 	if err != nil {
 		return "", err
 	}
@@ -538,12 +544,12 @@ func (*realPrompter) CaptureEmail(promptStr string) (string, error) {
 	return str, nil
 }
 
-func (*realPrompter) CaptureStringAllowEmpty(promptStr string) (string, error) {
+func (p *realPrompter) CaptureStringAllowEmpty(promptStr string) (string, error) {
 	prompt := promptui.Prompt{
 		Label: promptStr,
 	}
 
-	str, err := prompt.Run()
+	str, err := p.runPrompt(prompt)
 	if err != nil {
 		return "", err
 	}
@@ -551,13 +557,13 @@ func (*realPrompter) CaptureStringAllowEmpty(promptStr string) (string, error) {
 	return str, nil
 }
 
-func (*realPrompter) CaptureURL(promptStr string, validateConnection bool) (string, error) {
+func (p *realPrompter) CaptureURL(promptStr string, validateConnection bool) (string, error) {
 	for {
 		prompt := promptui.Prompt{
 			Label:    promptStr,
 			Validate: validateURLFormat,
 		}
-		str, err := prompt.Run()
+		str, err := p.runPrompt(prompt)
 		if err != nil {
 			return "", err
 		}
@@ -571,14 +577,14 @@ func (*realPrompter) CaptureURL(promptStr string, validateConnection bool) (stri
 	}
 }
 
-func (*realPrompter) CaptureRepoBranch(promptStr string, repo string) (string, error) {
+func (p *realPrompter) CaptureRepoBranch(promptStr string, repo string) (string, error) {
 	for {
 		var err error
 		prompt := promptui.Prompt{
 			Label:    promptStr,
 			Validate: validateNonEmpty,
 		}
-		str, err := prompt.Run()
+		str, err := p.runPrompt(prompt)
 		if err != nil {
 			return "", err
 		}
@@ -589,14 +595,14 @@ func (*realPrompter) CaptureRepoBranch(promptStr string, repo string) (string, e
 	}
 }
 
-func (*realPrompter) CaptureRepoFile(promptStr string, repo string, branch string) (string, error) {
+func (p *realPrompter) CaptureRepoFile(promptStr string, repo string, branch string) (string, error) {
 	for {
 		var err error
 		prompt := promptui.Prompt{
 			Label:    promptStr,
 			Validate: validateNonEmpty,
 		}
-		str, err := prompt.Run()
+		str, err := p.runPrompt(prompt)
 		if err != nil {
 			return "", err
 		}
@@ -607,13 +613,13 @@ func (*realPrompter) CaptureRepoFile(promptStr string, repo string, branch strin
 	}
 }
 
-func (*realPrompter) CaptureString(promptStr string) (string, error) {
+func (p *realPrompter) CaptureString(promptStr string) (string, error) {
 	prompt := promptui.Prompt{
 		Label:    promptStr,
 		Validate: validateNonEmpty,
 	}
 
-	str, err := prompt.Run()
+	str, err := p.runPrompt(prompt)
 	if err != nil {
 		return "", err
 	}
@@ -621,13 +627,13 @@ func (*realPrompter) CaptureString(promptStr string) (string, error) {
 	return str, nil
 }
 
-func (*realPrompter) CaptureValidatedString(promptStr string, validator func(string) error) (string, error) {
+func (p *realPrompter) CaptureValidatedString(promptStr string, validator func(string) error) (string, error) {
 	prompt := promptui.Prompt{
 		Label:    promptStr,
 		Validate: validator,
 	}
 
-	str, err := prompt.Run()
+	str, err := p.runPrompt(prompt)
 	if err != nil {
 		return "", err
 	}
@@ -635,13 +641,13 @@ func (*realPrompter) CaptureValidatedString(promptStr string, validator func(str
 	return str, nil
 }
 
-func (*realPrompter) CaptureGitURL(promptStr string) (*url.URL, error) {
+func (p *realPrompter) CaptureGitURL(promptStr string) (*url.URL, error) {
 	prompt := promptui.Prompt{
 		Label:    promptStr,
 		Validate: validateURLFormat,
 	}
 
-	str, err := prompt.Run()
+	str, err := p.runPrompt(prompt)
 	if err != nil {
 		return nil, err
 	}
@@ -654,7 +660,7 @@ func (*realPrompter) CaptureGitURL(promptStr string) (*url.URL, error) {
 	return parsedURL, nil
 }
 
-func (*realPrompter) CaptureVersion(promptStr string) (string, error) {
+func (p *realPrompter) CaptureVersion(promptStr string) (string, error) {
 	prompt := promptui.Prompt{
 		Label: promptStr,
 		Validate: func(input string) error {
@@ -665,7 +671,7 @@ func (*realPrompter) CaptureVersion(promptStr string) (string, error) {
 		},
 	}
 
-	str, err := prompt.Run()
+	str, err := p.runPrompt(prompt)
 	if err != nil {
 		return "", err
 	}
@@ -673,13 +679,13 @@ func (*realPrompter) CaptureVersion(promptStr string) (string, error) {
 	return str, nil
 }
 
-func (*realPrompter) CaptureIndex(promptStr string, options []any) (int, error) {
+func (p *realPrompter) CaptureIndex(promptStr string, options []any) (int, error) {
 	prompt := promptui.Select{
 		Label: promptStr,
 		Items: options,
 	}
 
-	listIndex, _, err := prompt.Run()
+	listIndex, _, err := p.runSelect(prompt)
 	if err != nil {
 		return 0, err
 	}
@@ -689,7 +695,7 @@ func (*realPrompter) CaptureIndex(promptStr string, options []any) (int, error)
 // CaptureFutureDate requires from the user a date input which is in the future.
 // If `minDate` is not empty, the minimum time in the future from the provided date is required
 // Otherwise, time from time.Now() is chosen.
-func (*realPrompter) CaptureFutureDate(promptStr string, minDate time.Time) (time.Time, error) {
+func (p *realPrompter) CaptureFutureDate(promptStr string, minDate time.Time) (time.Time, error) {
 	prompt := promptui.Prompt{
 		Label: promptStr,
 		Validate: func(s string) error {
@@ -707,7 +713,7 @@ func (*realPrompter) CaptureFutureDate(promptStr string, minDate time.Time) (tim
 		},
 	}
 
-	timestampStr, err := prompt.Run()
+	timestampStr, err := p.runPrompt(prompt)
 	if err != nil {
 		return time.Time{}, err
 	}
@@ -717,6 +723,10 @@ func (*realPrompter) CaptureFutureDate(promptStr string, minDate time.Time) (tim
 
 // returns true [resp. false] if user chooses stored key [resp. ledger] option
 func (prompter *realPrompter) ChooseKeyOrLedger(goal string) (bool, error) {
+	return chooseKeyOrLedger(prompter, goal)
+}
+
+func chooseKeyOrLedger(prompter Prompter, goal string) (bool, error) {
 	const (
 		keyOption    = "Use stored key"
 		ledgerOption = "Use ledger"
@@ -789,7 +799,7 @@ func GetSubnetAuthKeys(prompt Prompter, walletKeys []string, controlKeys []strin
 	}
 	for len(subnetAuthKeys) != int(threshold) {
 		subnetAuthKey, err := prompt.CaptureList(
-			"Choose a subnet auth key",
+			Keyed("subnet-auth-key", "Choose a subnet auth key"),
 			filteredControlKeys,
 		)
 		if err != nil {
@@ -806,6 +816,17 @@ func GetSubnetAuthKeys(prompt Prompter, walletKeys []string, controlKeys []strin
 }
 
 func GetFujiKeyOrLedger(prompt Prompter, goal string, keyDir string) (bool, string, error) {
+	if keyName, ok := EnvValue(KeyEnvVarName); ok {
+		if !utils.FileExists(filepath.Join(keyDir, keyName+constants.KeySuffix)) {
+			return false, "", fmt.Errorf("key %s given by %s does not exist", keyName, KeyEnvVarName)
+		}
+		return false, keyName, nil
+	}
+	if useLedger, ok := EnvValue(LedgerEnvVarName); ok {
+		if b, err := strconv.ParseBool(useLedger); err == nil && b {
+			return true, "", nil
+		}
+	}
 	useStoredKey, err := prompt.ChooseKeyOrLedger(goal)
 	if err != nil {
 		return false, "", err
@@ -813,7 +834,7 @@ func GetFujiKeyOrLedger(prompt Prompter, goal string, keyDir string) (bool, stri
 	if !useStoredKey {
 		return true, "", nil
 	}
-	keyName, err := captureKeyName(prompt, goal, keyDir)
+	keyName, err := CaptureKeyName(prompt, goal, keyDir)
 	if err != nil {
 		if errors.Is(err, errNoKeys) {
 			ux.Logger.PrintToUser("No private keys have been found. Create a new one with `avalanche key create`")
@@ -823,7 +844,8 @@ func GetFujiKeyOrLedger(prompt Prompter, goal string, keyDir string) (bool, stri
 	return false, keyName, nil
 }
 
-func captureKeyName(prompt Prompter, goal string, keyDir string) (string, error) {
+// CaptureKeyName asks the user to choose one of the keys stored at [keyDir]
+func CaptureKeyName(prompt Prompter, goal string, keyDir string) (string, error) {
 	files, err := os.ReadDir(keyDir)
 	if err != nil {
 		return "", err
@@ -840,7 +862,7 @@ func captureKeyName(prompt Prompter, goal string, keyDir string) (string, error)
 		}
 	}
 
-	keyName, err := prompt.CaptureList(fmt.Sprintf("Which stored key should be used to %s?", goal), keys)
+	keyName, err := prompt.CaptureList(Keyed("key-name", fmt.Sprintf("Which stored key should be used to %s?", goal)), keys)
 	if err != nil {
 		return "", err
 	}
@@ -36,3 +36,41 @@ func TestContains(t *testing.T) {
 	require.True(contains(addrList, addr2))
 	require.False(contains(addrList, addr3))
 }
+
+func TestRunWizardGoBack(t *testing.T) {
+	require := require.New(t)
+	asked := []string{}
+	wentBack := false
+	steps := []WizardStep{
+		{
+			Label:  "first",
+			Ask:    func() error { asked = append(asked, "first"); return nil },
+			Answer: func() string { return "" },
+		},
+		{
+			Label: "second",
+			Ask: func() error {
+				asked = append(asked, "second")
+				if !wentBack {
+					wentBack = true
+					return ErrGoBack
+				}
+				return nil
+			},
+			Answer: func() string { return "" },
+		},
+	}
+	// non interactive prompters have no review
+	require.NoError(RunWizard(NewNonInteractivePrompter(), steps))
+	require.Equal([]string{"first", "second", "first", "second"}, asked)
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	require := require.New(t)
+	require.True(FuzzyMatch("", "mySubnet"))
+	require.True(FuzzyMatch("msn", "mySubnet"))
+	require.True(FuzzyMatch("SUB", "mySubnet"))
+	require.True(FuzzyMatch("my net", "mySubnet"))
+	require.False(FuzzyMatch("tenbus", "mySubnet"))
+	require.False(FuzzyMatch("mySubnets", "mySubnet"))
+}
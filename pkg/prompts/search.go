@@ -0,0 +1,63 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package prompts
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/manifoldco/promptui"
+)
+
+// list prompts with more options than this can be filtered by typing
+const searchableListMinSize = 8
+
+// FuzzyMatch reports if all the chars of [input] appear in [option] in the same
+// order, ignoring case and spaces. An empty input matches every option
+func FuzzyMatch(input string, option string) bool {
+	option = strings.ToLower(option)
+	for _, r := range strings.ToLower(input) {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		i := strings.IndexRune(option, r)
+		if i == -1 {
+			return false
+		}
+		option = option[i+len(string(r)):]
+	}
+	return true
+}
+
+// withSearch makes [prompt] start in search mode, filtering its options with
+// FuzzyMatch as the user types, if it has too many options to browse them
+func withSearch(prompt *promptui.Select) *promptui.Select {
+	if prompt.Searcher != nil {
+		return prompt
+	}
+	labels := itemLabels(prompt.Items)
+	if len(labels) <= searchableListMinSize {
+		return prompt
+	}
+	prompt.Searcher = func(input string, index int) bool {
+		return FuzzyMatch(input, labels[index])
+	}
+	prompt.StartInSearchMode = true
+	return prompt
+}
+
+func itemLabels(items interface{}) []string {
+	switch items := items.(type) {
+	case []string:
+		return items
+	case []any:
+		labels := make([]string, len(items))
+		for i, item := range items {
+			labels[i] = fmt.Sprint(item)
+		}
+		return labels
+	default:
+		return nil
+	}
+}
@@ -101,13 +101,18 @@ func validateExistingFilepath(input string) error {
 	return errors.New("file doesn't exist")
 }
 
-func validateWeight(input string) error {
+// validateWeight checks that [input] is a weight accepted by [validator], or a non
+// zero one if not given
+func validateWeight(input string, validator func(uint64) error) error {
 	val, err := strconv.ParseUint(input, 10, 64)
 	if err != nil {
 		return err
 	}
+	if validator != nil {
+		return validator(val)
+	}
 	if val < constants.MinStakeWeight {
-		return errors.New("the weight must be an integer between 1 and 100")
+		return fmt.Errorf("the weight must be an integer greater than or equal to %d", constants.MinStakeWeight)
 	}
 	return nil
 }
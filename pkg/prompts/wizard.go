@@ -0,0 +1,208 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package prompts
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/manifoldco/promptui"
+	"github.com/olekukonko/tablewriter"
+)
+
+const (
+	// GoBack is the option added to list prompts to go back to the previous wizard step
+	GoBack = "Go back to previous step"
+	// BackInput is the input that goes back to the previous wizard step on text prompts
+	BackInput = "<"
+
+	confirmAnswers = "Confirm"
+	editAnswerFmt  = "Edit %s"
+)
+
+var (
+	// ErrGoBack is returned by wizard steps to go back to the previous one
+	ErrGoBack = errors.New("go back to previous step")
+	// ErrWizardCanceled is returned by RunWizard when the user cancels on review
+	ErrWizardCanceled = errors.New("canceled by user")
+)
+
+// WizardStep is a question of a multi step wizard, that can capture one or more
+// values with the prompter
+type WizardStep struct {
+	// Label identifies the step on the review screen
+	Label string
+	// Ask captures the answer of the step. Returning ErrGoBack, as the prompter
+	// does when the user chooses to, goes back to the previous step
+	Ask func() error
+	// Answer describes the captured answer on the review screen
+	Answer func() string
+	// Default optionally returns the value to show inline when the step is asked again
+	Default func() string
+}
+
+// wizardState is used by the prompter to go back and show defaults on the first
+// prompt of a wizard step
+type wizardState struct {
+	allowBack     bool
+	defaultAnswer string
+	prompted      bool
+}
+
+// wizardPrompter is implemented by interactive prompters supporting wizards
+type wizardPrompter interface {
+	setWizardState(*wizardState)
+}
+
+// RunWizard asks the steps in order, allowing the user to go back to the previous
+// one. When some answer was prompted for, a final review screen allows to edit
+// any of the answers before confirming them. Review is only available on
+// interactive prompters. With an answers file, the answers missing for any of
+// the steps are returned together
+func RunWizard(prompter Prompter, steps []WizardStep) error {
+	wp, interactive := prompter.(wizardPrompter)
+	prompted := false
+	ask := func(step WizardStep, allowBack bool) error {
+		if !interactive {
+			return step.Ask()
+		}
+		state := &wizardState{allowBack: allowBack}
+		if step.Default != nil {
+			state.defaultAnswer = step.Default()
+		}
+		wp.setWizardState(state)
+		defer wp.setWizardState(nil)
+		err := step.Ask()
+		prompted = prompted || state.prompted
+		return err
+	}
+	// the steps missing answers on an answers file are skipped, so that all the
+	// answers to add are reported at once
+	missingAnswers := []error{}
+	for i := 0; i < len(steps); {
+		err := ask(steps[i], i > 0)
+		switch {
+		case errors.Is(err, ErrGoBack):
+			if i > 0 {
+				i--
+			}
+		case errors.Is(err, ErrMissingAnswer):
+			missingAnswers = append(missingAnswers, err)
+			i++
+		case err != nil && len(missingAnswers) > 0:
+			// likely caused by the missing answers of the previous steps
+			return joinMissingAnswers(missingAnswers)
+		case err != nil:
+			return err
+		default:
+			i++
+		}
+	}
+	if len(missingAnswers) > 0 {
+		return joinMissingAnswers(missingAnswers)
+	}
+	if !interactive || !prompted {
+		return nil
+	}
+	for {
+		printAnswers(steps)
+		options := []string{confirmAnswers}
+		for _, step := range steps {
+			options = append(options, fmt.Sprintf(editAnswerFmt, step.Label))
+		}
+		options = append(options, Cancel)
+		option, err := prompter.CaptureList("Review your answers", options)
+		if err != nil {
+			return err
+		}
+		switch option {
+		case confirmAnswers:
+			return nil
+		case Cancel:
+			return ErrWizardCanceled
+		}
+		// going back from an edited step just returns to the review
+		index := slices.Index(options, option) - 1
+		if err := ask(steps[index], false); err != nil && !errors.Is(err, ErrGoBack) {
+			return err
+		}
+	}
+}
+
+func printAnswers(steps []WizardStep) {
+	ux.Logger.PrintToUser("")
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Question", "Answer"})
+	table.SetAutoWrapText(false)
+	for _, step := range steps {
+		table.Append([]string{step.Label, step.Answer()})
+	}
+	table.Render()
+}
+
+func (p *realPrompter) setWizardState(state *wizardState) {
+	p.wizard = state
+}
+
+// runPrompt runs a text prompt, showing the wizard default answer inline and
+// accepting BackInput to go back, if on a wizard step
+func (p *realPrompter) runPrompt(prompt promptui.Prompt) (string, error) {
+	state := p.wizard
+	if state == nil {
+		return prompt.Run()
+	}
+	// only the first prompt of a step shows the default and can go back
+	p.wizard = nil
+	state.prompted = true
+	if state.defaultAnswer != "" {
+		prompt.Default = state.defaultAnswer
+		prompt.AllowEdit = true
+	}
+	if state.allowBack {
+		prompt.Label = fmt.Sprintf("%v (%s to go back)", prompt.Label, BackInput)
+		if validate := prompt.Validate; validate != nil {
+			prompt.Validate = func(input string) error {
+				if input == BackInput {
+					return nil
+				}
+				return validate(input)
+			}
+		}
+	}
+	str, err := prompt.Run()
+	if err == nil && state.allowBack && str == BackInput {
+		return "", ErrGoBack
+	}
+	return str, err
+}
+
+// runSelect runs a list prompt, searchable if it has many options, and positioned
+// on the wizard default answer and with an option to go back, if on a wizard step
+func (p *realPrompter) runSelect(prompt promptui.Select) (int, string, error) {
+	state := p.wizard
+	items, ok := prompt.Items.([]string)
+	if state == nil || !ok {
+		return withSearch(&prompt).Run()
+	}
+	p.wizard = nil
+	state.prompted = true
+	if index := slices.Index(items, state.defaultAnswer); index != -1 {
+		prompt.CursorPos = index
+	} else if state.defaultAnswer != "" {
+		// not an option, so keep it for a text prompt following this one, eg a custom value
+		p.wizard = &wizardState{defaultAnswer: state.defaultAnswer}
+	}
+	addedGoBack := false
+	if state.allowBack && !slices.Contains(items, GoBack) {
+		prompt.Items = append(slices.Clone(items), GoBack)
+		addedGoBack = true
+	}
+	index, option, err := withSearch(&prompt).Run()
+	if err == nil && addedGoBack && option == GoBack {
+		return 0, "", ErrGoBack
+	}
+	return index, option, err
+}
@@ -0,0 +1,331 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package registry
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/key"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/formatting/address"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+const (
+	descriptorVersion = 1
+	descriptorsDir    = "subnets"
+	descriptorSuffix  = ".json"
+	httpTimeout       = 30 * time.Second
+)
+
+var (
+	ErrNotFound         = errors.New("subnet not found in registry")
+	ErrInvalidSignature = errors.New("invalid descriptor signature")
+)
+
+// Contents is the subnet configuration published to a registry: the genesis, the
+// sidecar metadata and configs of the subnet, and the ID of its VM
+type Contents struct {
+	Version     int
+	Name        string
+	VMID        string
+	PublishedAt time.Time
+	Subnet      models.Exportable
+}
+
+// Descriptor is the signed form of Contents, as stored in a registry
+type Descriptor struct {
+	// Payload is the JSON encoding of the contents, as signed
+	Payload json.RawMessage
+	// Signer is the ID of the address of the publisher key
+	Signer    string
+	Signature string
+}
+
+// NewDescriptor signs [contents] with [k], as key sign does
+func NewDescriptor(contents Contents, k *key.SoftKey) (*Descriptor, error) {
+	contents.Version = descriptorVersion
+	payload, err := json.Marshal(contents)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := k.SignMessage(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &Descriptor{
+		Payload:   payload,
+		Signer:    k.Key().Address().String(),
+		Signature: sig,
+	}, nil
+}
+
+// Verify checks that the descriptor was signed by its signer, and returns its contents
+func (d *Descriptor) Verify() (*Contents, error) {
+	// the payload is signed in compact form, but may be stored indented
+	var payload bytes.Buffer
+	if err := json.Compact(&payload, d.Payload); err != nil {
+		return nil, fmt.Errorf("%w: malformed payload: %w", ErrInvalidSignature, err)
+	}
+	pubKey, err := key.RecoverMessageSigner(payload.Bytes(), d.Signature)
+	if err != nil {
+		return nil, err
+	}
+	if pubKey.Address().String() != d.Signer {
+		return nil, fmt.Errorf("%w: signed by %s instead of %s", ErrInvalidSignature, pubKey.Address(), d.Signer)
+	}
+	contents := Contents{}
+	if err := json.Unmarshal(payload.Bytes(), &contents); err != nil {
+		return nil, fmt.Errorf("malformed descriptor contents: %w", err)
+	}
+	if contents.Version > descriptorVersion {
+		return nil, fmt.Errorf("descriptor version %d is not supported, update the CLI", contents.Version)
+	}
+	return &contents, nil
+}
+
+// ParseSigner returns the address ID given by [signer], either as a P-Chain
+// address or as an address ID
+func ParseSigner(signer string) (ids.ShortID, error) {
+	if addr, err := address.ParseToID(signer); err == nil {
+		return addr, nil
+	}
+	addr, err := ids.ShortFromString(signer)
+	if err != nil {
+		return ids.ShortEmpty, fmt.Errorf("invalid signer %q: expected a P-Chain address or address ID", signer)
+	}
+	return addr, nil
+}
+
+// Registry stores subnet descriptors by subnet name
+type Registry interface {
+	Push(name string, d *Descriptor) error
+	Pull(name string) (*Descriptor, error)
+}
+
+// New returns the registry at [registryURL]: a git repository (ending in .git or
+// given as an ssh URL), a HTTP server, or a local directory (file:// URL). Git
+// registries are cloned under [reposDir]
+func New(registryURL string, reposDir string) (Registry, error) {
+	switch {
+	case strings.HasSuffix(registryURL, ".git") || strings.HasPrefix(registryURL, "git@") || strings.HasPrefix(registryURL, "ssh://"):
+		urlHash := sha256.Sum256([]byte(registryURL))
+		return &gitRegistry{
+			url:  registryURL,
+			path: filepath.Join(reposDir, "registry-"+hex.EncodeToString(urlHash[:8])),
+		}, nil
+	case strings.HasPrefix(registryURL, "file://"):
+		return &dirRegistry{dir: strings.TrimPrefix(registryURL, "file://")}, nil
+	case strings.HasPrefix(registryURL, "http://") || strings.HasPrefix(registryURL, "https://"):
+		if _, err := url.ParseRequestURI(registryURL); err != nil {
+			return nil, fmt.Errorf("invalid registry URL %q: %w", registryURL, err)
+		}
+		return &httpRegistry{
+			url:    strings.TrimSuffix(registryURL, "/"),
+			token:  os.Getenv(constants.RegistryTokenEnvVarName),
+			client: &http.Client{Timeout: httpTimeout},
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported registry URL %q: expected a git, http(s) or file URL", registryURL)
+}
+
+func descriptorPath(name string) string {
+	return filepath.Join(descriptorsDir, name+descriptorSuffix)
+}
+
+func writeDescriptor(path string, d *Descriptor) error {
+	bs, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, bs, constants.WriteReadReadPerms)
+}
+
+func readDescriptor(path string) (*Descriptor, error) {
+	bs, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeDescriptor(bs)
+}
+
+func decodeDescriptor(bs []byte) (*Descriptor, error) {
+	d := Descriptor{}
+	if err := json.Unmarshal(bs, &d); err != nil {
+		return nil, fmt.Errorf("malformed descriptor: %w", err)
+	}
+	return &d, nil
+}
+
+// dirRegistry is a registry on a local or shared file system
+type dirRegistry struct {
+	dir string
+}
+
+func (r *dirRegistry) Push(name string, d *Descriptor) error {
+	return writeDescriptor(filepath.Join(r.dir, descriptorPath(name)), d)
+}
+
+func (r *dirRegistry) Pull(name string) (*Descriptor, error) {
+	return readDescriptor(filepath.Join(r.dir, descriptorPath(name)))
+}
+
+// httpRegistry is a registry served over HTTP, that accepts PUT requests to publish
+type httpRegistry struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func (r *httpRegistry) do(method string, name string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, r.url+"/"+descriptorsDir+"/"+url.PathEscape(name)+descriptorSuffix, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+	return r.client.Do(req)
+}
+
+func (r *httpRegistry) Push(name string, d *Descriptor) error {
+	bs, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	resp, err := r.do(http.MethodPut, name, bs)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("registry rejected the descriptor: %s", resp.Status)
+	}
+	return nil
+}
+
+func (r *httpRegistry) Pull(name string) (*Descriptor, error) {
+	resp, err := r.do(http.MethodGet, name, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed getting descriptor from registry: %s", resp.Status)
+	}
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return decodeDescriptor(bs)
+}
+
+// gitRegistry is a registry on a git repository, cloned locally
+type gitRegistry struct {
+	url  string
+	path string
+}
+
+// getRepo clones the registry repository, or updates the local clone
+func (r *gitRegistry) getRepo() (*git.Repository, error) {
+	if _, err := os.Stat(r.path); err != nil {
+		repo, err := git.PlainClone(r.path, false, &git.CloneOptions{URL: r.url})
+		if !errors.Is(err, transport.ErrEmptyRemoteRepository) {
+			return repo, err
+		}
+		// new registries start with the first published descriptor
+		_ = os.RemoveAll(r.path)
+		repo, err = git.PlainInit(r.path, false)
+		if err != nil {
+			return nil, err
+		}
+		_, err = repo.CreateRemote(&config.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{r.url}})
+		return repo, err
+	}
+	repo, err := git.PlainOpen(r.path)
+	if err != nil {
+		return nil, err
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	if err := wt.Pull(&git.PullOptions{}); err != nil &&
+		!errors.Is(err, git.NoErrAlreadyUpToDate) &&
+		!errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return nil, fmt.Errorf("failed updating registry clone: %w", err)
+	}
+	return repo, nil
+}
+
+func (r *gitRegistry) Push(name string, d *Descriptor) error {
+	repo, err := r.getRepo()
+	if err != nil {
+		return err
+	}
+	if err := writeDescriptor(filepath.Join(r.path, descriptorPath(name)), d); err != nil {
+		return err
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	if _, err := wt.Add(filepath.ToSlash(descriptorPath(name))); err != nil {
+		return err
+	}
+	if _, err := wt.Commit(fmt.Sprintf("Publish subnet %s", name), &git.CommitOptions{
+		Author: commitAuthor(),
+	}); err != nil {
+		return err
+	}
+	return repo.Push(&git.PushOptions{})
+}
+
+func (r *gitRegistry) Pull(name string) (*Descriptor, error) {
+	if _, err := r.getRepo(); err != nil {
+		return nil, err
+	}
+	return readDescriptor(filepath.Join(r.path, descriptorPath(name)))
+}
+
+// commitAuthor uses the global git config to try identifying the author
+func commitAuthor() *object.Signature {
+	author := &object.Signature{
+		Name:  constants.GitRepoCommitName,
+		Email: constants.GitRepoCommitEmail,
+		When:  time.Now(),
+	}
+	conf, err := config.LoadConfig(config.GlobalScope)
+	if err == nil && conf.Author.Name != "" && conf.Author.Email != "" {
+		author.Name = conf.Author.Name
+		author.Email = conf.Author.Email
+	}
+	return author
+}
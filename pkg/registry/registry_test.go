@@ -0,0 +1,150 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package registry
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/key"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDescriptor(t *testing.T) (*Descriptor, *key.SoftKey) {
+	k, err := key.NewSoft(models.NewMainnetNetwork().ID)
+	require.NoError(t, err)
+	d, err := NewDescriptor(Contents{
+		Name: "mySubnet",
+		VMID: "vmID",
+		Subnet: models.Exportable{
+			Sidecar: models.Sidecar{Name: "mySubnet", VM: models.SubnetEvm},
+			Genesis: []byte(`{"config":{"chainId":1}}`),
+		},
+	}, k)
+	require.NoError(t, err)
+	return d, k
+}
+
+func TestDescriptorVerify(t *testing.T) {
+	require := require.New(t)
+	d, k := newTestDescriptor(t)
+	require.Equal(k.Key().Address().String(), d.Signer)
+
+	contents, err := d.Verify()
+	require.NoError(err)
+	require.Equal(descriptorVersion, contents.Version)
+	require.Equal("mySubnet", contents.Subnet.Sidecar.Name)
+	require.Equal([]byte(`{"config":{"chainId":1}}`), contents.Subnet.Genesis)
+
+	// the signature survives the descriptor being stored indented
+	bs, err := json.MarshalIndent(d, "", "  ")
+	require.NoError(err)
+	stored, err := decodeDescriptor(bs)
+	require.NoError(err)
+	_, err = stored.Verify()
+	require.NoError(err)
+
+	tampered := *d
+	tampered.Payload = []byte(strings.Replace(string(d.Payload), "vmID", "otherVMID", 1))
+	_, err = tampered.Verify()
+	require.ErrorIs(err, ErrInvalidSignature)
+
+	other, err := key.NewSoft(models.NewMainnetNetwork().ID)
+	require.NoError(err)
+	impersonated := *d
+	impersonated.Signer = other.Key().Address().String()
+	_, err = impersonated.Verify()
+	require.ErrorIs(err, ErrInvalidSignature)
+}
+
+func TestParseSigner(t *testing.T) {
+	require := require.New(t)
+	k, err := key.NewSoft(models.NewMainnetNetwork().ID)
+	require.NoError(err)
+	signer, err := ParseSigner(k.P()[0])
+	require.NoError(err)
+	require.Equal(k.Key().Address(), signer)
+	signer, err = ParseSigner(k.Key().Address().String())
+	require.NoError(err)
+	require.Equal(k.Key().Address(), signer)
+	_, err = ParseSigner("nope")
+	require.Error(err)
+}
+
+func TestDirRegistry(t *testing.T) {
+	require := require.New(t)
+	reg, err := New("file://"+filepath.Join(t.TempDir(), "registry"), t.TempDir())
+	require.NoError(err)
+	_, err = reg.Pull("mySubnet")
+	require.ErrorIs(err, ErrNotFound)
+
+	d, _ := newTestDescriptor(t)
+	require.NoError(reg.Push("mySubnet", d))
+	pulled, err := reg.Pull("mySubnet")
+	require.NoError(err)
+	contents, err := pulled.Verify()
+	require.NoError(err)
+	require.Equal("mySubnet", contents.Name)
+}
+
+func TestHTTPRegistry(t *testing.T) {
+	require := require.New(t)
+	t.Setenv(constants.RegistryTokenEnvVarName, "secret")
+	var (
+		lock   sync.Mutex
+		stored = map[string][]byte{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			bs, _ := io.ReadAll(r.Body)
+			stored[r.URL.Path] = bs
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			bs, ok := stored[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(bs)
+		}
+	}))
+	defer server.Close()
+
+	reg, err := New(server.URL+"/", t.TempDir())
+	require.NoError(err)
+	_, err = reg.Pull("mySubnet")
+	require.ErrorIs(err, ErrNotFound)
+
+	d, _ := newTestDescriptor(t)
+	require.NoError(reg.Push("mySubnet", d))
+	require.Contains(stored, "/subnets/mySubnet.json")
+	pulled, err := reg.Pull("mySubnet")
+	require.NoError(err)
+	_, err = pulled.Verify()
+	require.NoError(err)
+
+	t.Setenv(constants.RegistryTokenEnvVarName, "")
+	reg, err = New(server.URL, t.TempDir())
+	require.NoError(err)
+	require.Error(reg.Push("mySubnet", d))
+}
+
+func TestNewUnsupported(t *testing.T) {
+	_, err := New("ftp://example.com/registry", t.TempDir())
+	require.Error(t, err)
+}
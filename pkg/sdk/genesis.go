@@ -0,0 +1,118 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/MetalBlockchain/metalgo/snow"
+	"github.com/MetalBlockchain/subnet-evm/commontype"
+	"github.com/MetalBlockchain/subnet-evm/core"
+	"github.com/MetalBlockchain/subnet-evm/params"
+	"github.com/MetalBlockchain/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/MetalBlockchain/subnet-evm/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/exp/maps"
+)
+
+// EVMGenesisParams are the values of a Subnet-EVM genesis
+type EVMGenesisParams struct {
+	ChainID *big.Int
+	// FeeConfig defaults to the Subnet-EVM one if not given
+	FeeConfig   *commontype.FeeConfig
+	Allocation  core.GenesisAlloc
+	Precompiles params.Precompiles
+}
+
+// NewEVMGenesis returns the genesis of a Subnet-EVM chain with [genesisParams]
+func NewEVMGenesis(genesisParams EVMGenesisParams) ([]byte, error) {
+	conf := NewEVMChainConfig()
+	if genesisParams.FeeConfig != nil {
+		conf.FeeConfig = *genesisParams.FeeConfig
+	}
+	for key, precompile := range genesisParams.Precompiles {
+		conf.GenesisPrecompiles[key] = precompile
+	}
+	return BuildEVMGenesis(conf, genesisParams.ChainID, genesisParams.Allocation)
+}
+
+// NewEVMChainConfig returns a copy of the default Subnet-EVM chain config, with the
+// network upgrades activated
+func NewEVMChainConfig() *params.ChainConfig {
+	conf := *params.SubnetEVMDefaultChainConfig
+	conf.GenesisPrecompiles = params.Precompiles{}
+	maps.Copy(conf.GenesisPrecompiles, params.SubnetEVMDefaultChainConfig.GenesisPrecompiles)
+	conf.NetworkUpgrades = params.NetworkUpgrades{
+		SubnetEVMTimestamp: utils.NewUint64(0),
+		DurangoTimestamp:   utils.NewUint64(uint64(time.Now().Unix())),
+	}
+	conf.AvalancheContext = params.AvalancheContext{
+		SnowCtx: &snow.Context{},
+	}
+	return &conf
+}
+
+// BuildEVMGenesis verifies the genesis for [conf], [chainID] and [allocation], and
+// returns it as indented JSON
+func BuildEVMGenesis(conf *params.ChainConfig, chainID *big.Int, allocation core.GenesisAlloc) ([]byte, error) {
+	if chainID == nil || chainID.Sign() <= 0 {
+		return nil, errors.New("a positive chain ID is required")
+	}
+	if conf.GenesisPrecompiles[txallowlist.ConfigKey] != nil {
+		allowListCfg, ok := conf.GenesisPrecompiles[txallowlist.ConfigKey].(*txallowlist.Config)
+		if !ok {
+			return nil, fmt.Errorf("expected config of type txallowlist.AllowListConfig, but got %T", allowListCfg)
+		}
+
+		if err := ensureAdminsHaveBalance(
+			allowListCfg.AdminAddresses,
+			allocation); err != nil {
+			return nil, err
+		}
+	}
+
+	conf.ChainID = chainID
+
+	genesis := core.Genesis{
+		Alloc:      allocation,
+		Config:     conf,
+		Difficulty: big.NewInt(0),
+		GasLimit:   conf.FeeConfig.GasLimit.Uint64(),
+	}
+
+	if err := genesis.Verify(); err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := genesis.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var prettyJSON bytes.Buffer
+	if err := json.Indent(&prettyJSON, jsonBytes, "", "    "); err != nil {
+		return nil, err
+	}
+	return prettyJSON.Bytes(), nil
+}
+
+func ensureAdminsHaveBalance(admins []common.Address, alloc core.GenesisAlloc) error {
+	if len(admins) < 1 {
+		return nil
+	}
+
+	for _, admin := range admins {
+		// we can break at the first admin who has a non-zero balance
+		if bal, ok := alloc[admin]; ok &&
+			bal.Balance != nil &&
+			bal.Balance.Uint64() > uint64(0) {
+			return nil
+		}
+	}
+	return errors.New("none of the addresses in the transaction allow list precompile have any tokens allocated to them. Currently, no address can transact on the network. Airdrop some funds to one of the allow list addresses to continue")
+}
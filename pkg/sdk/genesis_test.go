@@ -0,0 +1,127 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package sdk
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/MetalBlockchain/metal-cli/internal/testutils"
+	"github.com/MetalBlockchain/subnet-evm/core"
+	"github.com/MetalBlockchain/subnet-evm/params"
+	"github.com/MetalBlockchain/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/MetalBlockchain/subnet-evm/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ensureAdminsFunded(t *testing.T) {
+	addrs, err := testutils.GenerateEthAddrs(5)
+	require.NoError(t, err)
+
+	type test struct {
+		name       string
+		alloc      core.GenesisAlloc
+		admins     []common.Address
+		shouldFail bool
+	}
+	tests := []test{
+		{
+			name: "One address funded",
+			alloc: map[common.Address]core.GenesisAccount{
+				addrs[0]: {},
+				addrs[1]: {
+					Balance: big.NewInt(42),
+				},
+				addrs[2]: {},
+			},
+			admins:     []common.Address{addrs[1]},
+			shouldFail: false,
+		},
+		{
+			name: "Two addresses funded",
+			alloc: map[common.Address]core.GenesisAccount{
+				addrs[2]: {},
+				addrs[3]: {
+					Balance: big.NewInt(42),
+				},
+				addrs[4]: {
+					Balance: big.NewInt(42),
+				},
+			},
+			admins:     []common.Address{addrs[3], addrs[4]},
+			shouldFail: false,
+		},
+		{
+			name: "Two addresses in Genesis but no funds",
+			alloc: map[common.Address]core.GenesisAccount{
+				addrs[0]: {
+					Balance: big.NewInt(0),
+				},
+				addrs[1]: {},
+				addrs[2]: {},
+			},
+			admins:     []common.Address{addrs[0], addrs[2]},
+			shouldFail: true,
+		},
+		{
+			name: "No address funded",
+			alloc: map[common.Address]core.GenesisAccount{
+				addrs[0]: {},
+				addrs[1]: {},
+				addrs[2]: {},
+			},
+			admins:     []common.Address{addrs[3], addrs[4]},
+			shouldFail: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			err := ensureAdminsHaveBalance(tt.admins, tt.alloc)
+			if tt.shouldFail {
+				require.Error(err)
+			} else {
+				require.NoError(err)
+			}
+		})
+	}
+}
+
+func TestNewEVMGenesis(t *testing.T) {
+	require := require.New(t)
+	addrs, err := testutils.GenerateEthAddrs(1)
+	require.NoError(err)
+	allocation := core.GenesisAlloc{addrs[0]: {Balance: big.NewInt(42)}}
+
+	_, err = NewEVMGenesis(EVMGenesisParams{Allocation: allocation})
+	require.ErrorContains(err, "chain ID")
+
+	genesisBytes, err := NewEVMGenesis(EVMGenesisParams{
+		ChainID:    big.NewInt(12345),
+		Allocation: allocation,
+		Precompiles: params.Precompiles{
+			txallowlist.ConfigKey: txallowlist.NewConfig(utils.NewUint64(0), addrs, nil, nil),
+		},
+	})
+	require.NoError(err)
+	genesis := core.Genesis{}
+	require.NoError(json.Unmarshal(genesisBytes, &genesis))
+	require.Equal(big.NewInt(12345), genesis.Config.ChainID)
+	require.Equal(big.NewInt(42), genesis.Alloc[addrs[0]].Balance)
+	require.Contains(genesis.Config.GenesisPrecompiles, txallowlist.ConfigKey)
+	// the default config is not modified
+	require.NotContains(params.SubnetEVMDefaultChainConfig.GenesisPrecompiles, txallowlist.ConfigKey)
+
+	// allow listed admins must be funded
+	_, err = NewEVMGenesis(EVMGenesisParams{
+		ChainID: big.NewInt(12345),
+		Precompiles: params.Precompiles{
+			txallowlist.ConfigKey: txallowlist.NewConfig(utils.NewUint64(0), addrs, nil, nil),
+		},
+	})
+	require.Error(err)
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package sdk
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const modulePath = "github.com/MetalBlockchain/metal-cli"
+
+// the CLI packages the SDK may depend on, directly or not. In particular, it
+// must not reach the ux, prompts or cmd packages
+var allowedCLIPackages = map[string]struct{}{
+	modulePath + "/pkg/sdk":       {},
+	modulePath + "/pkg/models":    {},
+	modulePath + "/pkg/constants": {},
+	modulePath + "/pkg/utils":     {},
+}
+
+// cliImports returns the CLI packages imported by the non test files of [pkg]
+func cliImports(t *testing.T, moduleDir string, pkg string) []string {
+	dir := filepath.Join(moduleDir, strings.TrimPrefix(pkg, modulePath))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	imports := []string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, name), nil, parser.ImportsOnly)
+		require.NoError(t, err)
+		for _, spec := range f.Imports {
+			path, err := strconv.Unquote(spec.Path.Value)
+			require.NoError(t, err)
+			if strings.HasPrefix(path, modulePath+"/") {
+				imports = append(imports, path)
+			}
+		}
+	}
+	return imports
+}
+
+func TestImportGraph(t *testing.T) {
+	moduleDir, err := filepath.Abs(filepath.Join("..", ".."))
+	require.NoError(t, err)
+	visited := map[string]struct{}{}
+	pending := []string{modulePath + "/pkg/sdk"}
+	for len(pending) > 0 {
+		pkg := pending[0]
+		pending = pending[1:]
+		if _, ok := visited[pkg]; ok {
+			continue
+		}
+		visited[pkg] = struct{}{}
+		_, allowed := allowedCLIPackages[pkg]
+		require.True(t, allowed, "the SDK must not depend on %s", pkg)
+		pending = append(pending, cliImports(t, moduleDir, pkg)...)
+	}
+}
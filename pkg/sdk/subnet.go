@@ -0,0 +1,231 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package sdk is the prompt-free Go API of the CLI, that other Go tools can use to
+// create genesis files, deploy subnets and add validators. It neither prints nor
+// prompts, and only depends on metalgo, Subnet-EVM and the CLI models. The
+// deployers used by the CLI commands are built on it, adding the user output,
+// retries, ledger and multisig handling on top
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/keychain"
+	"github.com/MetalBlockchain/metalgo/utils/set"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/txs"
+	"github.com/MetalBlockchain/metalgo/vms/secp256k1fx"
+	"github.com/MetalBlockchain/metalgo/wallet/subnet/primary"
+	"github.com/MetalBlockchain/metalgo/wallet/subnet/primary/common"
+)
+
+// NewWallet returns a wallet for the network API at [endpoint], that pays and signs
+// with [kc]. The txs [preloadTxs], eg the creation txs of the subnets to operate
+// on, are fetched so the wallet can authorize subnet operations
+func NewWallet(ctx context.Context, endpoint string, kc keychain.Keychain, preloadTxs ...ids.ID) (primary.Wallet, error) {
+	// filter out ids.Empty txs
+	filteredTxs := utils.Filter(preloadTxs, func(e ids.ID) bool { return e != ids.Empty })
+	return primary.MakeWallet(
+		ctx,
+		&primary.WalletConfig{
+			URI:              endpoint,
+			AVAXKeychain:     kc,
+			EthKeychain:      secp256k1fx.NewKeychain(),
+			PChainTxsToFetch: set.Of(filteredTxs...),
+		},
+	)
+}
+
+// GetMultisigTxOptions returns the tx options to sign with the [walletAddrs] and
+// [subnetAuthKeys], sending the change to the first wallet address
+func GetMultisigTxOptions(walletAddrs []ids.ShortID, subnetAuthKeys []ids.ShortID) []common.Option {
+	options := []common.Option{}
+	// addrs to use for signing
+	customAddrsSet := set.Set[ids.ShortID]{}
+	customAddrsSet.Add(walletAddrs...)
+	customAddrsSet.Add(subnetAuthKeys...)
+	options = append(options, common.WithCustomAddresses(customAddrsSet))
+	if len(walletAddrs) > 0 {
+		// set change to go to wallet addr (instead of any other subnet auth key)
+		changeOwner := &secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{walletAddrs[0]},
+		}
+		options = append(options, common.WithChangeOwner(changeOwner))
+	}
+	return options
+}
+
+// NewCreateSubnetTx returns a CreateSubnetTx signed by [wallet], for a subnet owned
+// by [threshold] of the [controlKeys]
+func NewCreateSubnetTx(
+	ctx context.Context,
+	wallet primary.Wallet,
+	controlKeys []ids.ShortID,
+	threshold uint32,
+	options ...common.Option,
+) (*txs.Tx, error) {
+	owners := &secp256k1fx.OutputOwners{
+		Addrs:     controlKeys,
+		Threshold: threshold,
+		Locktime:  0,
+	}
+	unsignedTx, err := wallet.P().Builder().NewCreateSubnetTx(owners, options...)
+	if err != nil {
+		return nil, fmt.Errorf("error building tx: %w", err)
+	}
+	return signNewTx(ctx, wallet, unsignedTx)
+}
+
+// NewCreateChainTx returns a CreateChainTx for the blockchain [chainName] of [subnetID],
+// running [vmID] with [genesis], signed by [wallet]. It is partially signed if
+// [wallet] does not hold enough subnet auth keys
+func NewCreateChainTx(
+	ctx context.Context,
+	wallet primary.Wallet,
+	subnetID ids.ID,
+	chainName string,
+	vmID ids.ID,
+	genesis []byte,
+	options ...common.Option,
+) (*txs.Tx, error) {
+	unsignedTx, err := wallet.P().Builder().NewCreateChainTx(
+		subnetID,
+		genesis,
+		vmID,
+		[]ids.ID{},
+		chainName,
+		options...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error building tx: %w", err)
+	}
+	return signNewTx(ctx, wallet, unsignedTx)
+}
+
+// NewAddSubnetValidatorTx returns an AddSubnetValidatorTx for [validator] signed by
+// [wallet]. It is partially signed if [wallet] does not hold enough subnet auth keys
+func NewAddSubnetValidatorTx(
+	ctx context.Context,
+	wallet primary.Wallet,
+	validator *txs.SubnetValidator,
+	options ...common.Option,
+) (*txs.Tx, error) {
+	unsignedTx, err := wallet.P().Builder().NewAddSubnetValidatorTx(validator, options...)
+	if err != nil {
+		return nil, fmt.Errorf("error building tx: %w", err)
+	}
+	return signNewTx(ctx, wallet, unsignedTx)
+}
+
+// NewRemoveSubnetValidatorTx returns a RemoveSubnetValidatorTx for [nodeID] of
+// [subnetID] signed by [wallet]. It is partially signed if [wallet] does not hold
+// enough subnet auth keys
+func NewRemoveSubnetValidatorTx(
+	ctx context.Context,
+	wallet primary.Wallet,
+	nodeID ids.NodeID,
+	subnetID ids.ID,
+	options ...common.Option,
+) (*txs.Tx, error) {
+	unsignedTx, err := wallet.P().Builder().NewRemoveSubnetValidatorTx(nodeID, subnetID, options...)
+	if err != nil {
+		return nil, fmt.Errorf("error building tx: %w", err)
+	}
+	return signNewTx(ctx, wallet, unsignedTx)
+}
+
+func signNewTx(ctx context.Context, wallet primary.Wallet, unsignedTx txs.UnsignedTx) (*txs.Tx, error) {
+	tx := txs.Tx{Unsigned: unsignedTx}
+	if err := wallet.P().Signer().Sign(ctx, &tx); err != nil {
+		return nil, fmt.Errorf("error signing tx: %w", err)
+	}
+	return &tx, nil
+}
+
+// IssueTx issues the fully signed [tx] with [wallet], and waits for its acceptance
+func IssueTx(ctx context.Context, wallet primary.Wallet, tx *txs.Tx) error {
+	if err := wallet.P().IssueTx(tx, common.WithContext(ctx)); err != nil {
+		return fmt.Errorf("error issuing tx with ID %s: %w", tx.ID(), err)
+	}
+	return nil
+}
+
+// CreateSubnet creates a subnet owned by [threshold] of the [controlKeys], paid by
+// [wallet], and returns its ID
+func CreateSubnet(
+	ctx context.Context,
+	wallet primary.Wallet,
+	controlKeys []ids.ShortID,
+	threshold uint32,
+) (ids.ID, error) {
+	tx, err := NewCreateSubnetTx(ctx, wallet, controlKeys, threshold)
+	if err != nil {
+		return ids.Empty, err
+	}
+	return tx.ID(), IssueTx(ctx, wallet, tx)
+}
+
+// CreateBlockchain creates the blockchain [chainName] of [subnetID], running the VM
+// [vmName] with [genesis], and returns its ID. [wallet] must hold the subnet auth
+// keys, and have the subnet creation tx preloaded
+func CreateBlockchain(
+	ctx context.Context,
+	wallet primary.Wallet,
+	subnetID ids.ID,
+	chainName string,
+	vmName string,
+	genesis []byte,
+) (ids.ID, error) {
+	vmID, err := vmIDFromName(vmName)
+	if err != nil {
+		return ids.Empty, fmt.Errorf("failed to create VM ID from %s: %w", vmName, err)
+	}
+	tx, err := NewCreateChainTx(ctx, wallet, subnetID, chainName, vmID, genesis)
+	if err != nil {
+		return ids.Empty, err
+	}
+	return tx.ID(), IssueTx(ctx, wallet, tx)
+}
+
+// AddValidator adds [nodeID] as a validator of [subnetID] with [weight], from
+// [startTime] for [duration], and returns the tx ID. [wallet] must hold the subnet
+// auth keys, and have the subnet creation tx preloaded
+func AddValidator(
+	ctx context.Context,
+	wallet primary.Wallet,
+	subnetID ids.ID,
+	nodeID ids.NodeID,
+	weight uint64,
+	startTime time.Time,
+	duration time.Duration,
+) (ids.ID, error) {
+	tx, err := NewAddSubnetValidatorTx(ctx, wallet, &txs.SubnetValidator{
+		Validator: txs.Validator{
+			NodeID: nodeID,
+			Start:  uint64(startTime.Unix()),
+			End:    uint64(startTime.Add(duration).Unix()),
+			Wght:   weight,
+		},
+		Subnet: subnetID,
+	})
+	if err != nil {
+		return ids.Empty, err
+	}
+	return tx.ID(), IssueTx(ctx, wallet, tx)
+}
+
+// vmIDFromName returns the ID of the VM [vmName] as the network runner computes
+// it, that is its name padded to 32 bytes
+func vmIDFromName(vmName string) (ids.ID, error) {
+	if len(vmName) > ids.IDLen {
+		return ids.Empty, fmt.Errorf("VM name must be <= %d bytes, found %d", ids.IDLen, len(vmName))
+	}
+	b := make([]byte, ids.IDLen)
+	copy(b, vmName)
+	return ids.ToID(b)
+}
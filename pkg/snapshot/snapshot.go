@@ -0,0 +1,241 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+)
+
+const (
+	// network runner stores each snapshot under a dir with this prefix
+	anrSnapshotPrefix = "anr-snapshot-"
+	// AutoPrefix is prepended to the names of snapshots automatically taken by the CLI,
+	// so that they can be pruned without touching user named ones
+	AutoPrefix = "auto-"
+
+	DefaultNameTemplate = "{event}-{subnet}-{timestamp}"
+	timestampFormat     = "20060102150405"
+	noGitSHA            = "nogit"
+)
+
+var (
+	invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+	ErrNotFound = errors.New("snapshot not found")
+)
+
+type Info struct {
+	Name    string
+	Path    string
+	ModTime time.Time
+}
+
+// IsAuto returns true if the snapshot was automatically taken by the CLI
+func (i Info) IsAuto() bool {
+	return strings.HasPrefix(i.Name, AutoPrefix)
+}
+
+// Settings are the user preferences for snapshot creation and retention
+type Settings struct {
+	// Keep is the number of auto snapshots to retain, 0 means unlimited
+	Keep int
+	// Auto enables taking snapshots before deploy/upgrade operations
+	Auto         bool
+	NameTemplate string
+}
+
+func GetSettings(app *application.Avalanche) Settings {
+	settings := Settings{
+		Keep:         app.Conf.GetConfigIntValue(constants.ConfigSnapshotKeepKey),
+		Auto:         app.Conf.GetConfigBoolValue(constants.ConfigSnapshotAutoKey),
+		NameTemplate: app.Conf.GetConfigStringValue(constants.ConfigSnapshotNameTemplateKey),
+	}
+	if settings.NameTemplate == "" {
+		settings.NameTemplate = DefaultNameTemplate
+	}
+	return settings
+}
+
+// NameFromTemplate expands the snapshot name [template], replacing {event}, {subnet},
+// {timestamp} and {gitsha} placeholders. Characters not allowed on snapshot names
+// are replaced by '_'
+func NameFromTemplate(template string, event string, subnetName string, now time.Time, gitSHA string) string {
+	if gitSHA == "" {
+		gitSHA = noGitSHA
+	}
+	name := strings.NewReplacer(
+		"{event}", event,
+		"{subnet}", subnetName,
+		"{timestamp}", now.UTC().Format(timestampFormat),
+		"{gitsha}", gitSHA,
+	).Replace(template)
+	return invalidNameChars.ReplaceAllString(name, "_")
+}
+
+// AutoName returns the name to use for a snapshot automatically taken before [event]
+func AutoName(settings Settings, event string, subnetName string) string {
+	return AutoPrefix + NameFromTemplate(settings.NameTemplate, event, subnetName, time.Now(), currentGitSHA())
+}
+
+// currentGitSHA returns the short commit of the git repo at the working dir, if any
+func currentGitSHA() string {
+	out, err := exec.Command("git", "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// Dir returns the dir where the network runner stores snapshot [name] under [snapshotsDir]
+func Dir(snapshotsDir string, name string) string {
+	return filepath.Join(snapshotsDir, anrSnapshotPrefix+name)
+}
+
+// List returns all snapshots saved at [snapshotsDir] but the default one, newest first
+func List(snapshotsDir string) ([]Info, error) {
+	entries, err := os.ReadDir(snapshotsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	snapshots := []Info{}
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), anrSnapshotPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(entry.Name(), anrSnapshotPrefix)
+		if name == constants.DefaultSnapshotName {
+			continue
+		}
+		fileInfo, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, Info{
+			Name:    name,
+			Path:    filepath.Join(snapshotsDir, entry.Name()),
+			ModTime: fileInfo.ModTime(),
+		})
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].ModTime.After(snapshots[j].ModTime)
+	})
+	return snapshots, nil
+}
+
+// Get returns the snapshot named [name] saved at [snapshotsDir]
+func Get(snapshotsDir string, name string) (Info, error) {
+	snapshots, err := List(snapshotsDir)
+	if err != nil {
+		return Info{}, err
+	}
+	for _, s := range snapshots {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+	return Info{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+}
+
+// ValidateName checks that [name] can be used for a user named snapshot
+func ValidateName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("snapshot name can't be empty")
+	case invalidNameChars.MatchString(name):
+		return fmt.Errorf("invalid snapshot name %q: only letters, digits, '.', '_' and '-' are allowed", name)
+	case name == constants.DefaultSnapshotName:
+		return fmt.Errorf("snapshot name %q is reserved for the default snapshot", name)
+	case strings.HasPrefix(name, AutoPrefix):
+		return fmt.Errorf("snapshot names starting with %q are reserved for auto snapshots", AutoPrefix)
+	}
+	return nil
+}
+
+// ToPrune returns the snapshots to be removed so that only the [keep] newest ones
+// are retained. If [includeManual] is false, only auto snapshots are considered
+func ToPrune(snapshots []Info, keep int, includeManual bool) []Info {
+	candidates := []Info{}
+	for _, s := range snapshots {
+		if includeManual || s.IsAuto() {
+			candidates = append(candidates, s)
+		}
+	}
+	if keep < 0 || len(candidates) <= keep {
+		return nil
+	}
+	return candidates[keep:]
+}
+
+// Remove deletes a snapshot along with the CLI data stored for it
+func Remove(app *application.Avalanche, s Info) error {
+	if err := os.RemoveAll(s.Path); err != nil {
+		return err
+	}
+	for _, dataPath := range []string{
+		filepath.Join(app.GetAWMRelayerSnapshotConfsDir(), s.Name+".json"),
+		filepath.Join(app.GetExtraLocalNetworkSnapshotsDir(), s.Name+".json"),
+	} {
+		if err := os.RemoveAll(dataPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rename renames snapshot [s] to [name], along with the CLI data stored for it
+func Rename(app *application.Avalanche, s Info, name string) error {
+	if err := os.Rename(s.Path, Dir(filepath.Dir(s.Path), name)); err != nil {
+		return err
+	}
+	for _, dataDir := range []string{
+		app.GetAWMRelayerSnapshotConfsDir(),
+		app.GetExtraLocalNetworkSnapshotsDir(),
+	} {
+		dataPath := filepath.Join(dataDir, s.Name+".json")
+		if _, err := os.Stat(dataPath); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err := os.Rename(dataPath, filepath.Join(dataDir, name+".json")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Prune removes the auto snapshots exceeding [keep], returning the removed ones
+func Prune(app *application.Avalanche, keep int, includeManual bool) ([]Info, error) {
+	snapshots, err := List(app.GetSnapshotsDir())
+	if err != nil {
+		return nil, err
+	}
+	toPrune := ToPrune(snapshots, keep, includeManual)
+	for _, s := range toPrune {
+		if err := Remove(app, s); err != nil {
+			return nil, err
+		}
+	}
+	return toPrune, nil
+}
+
+// ApplyRetention prunes auto snapshots according to the configured retention
+func ApplyRetention(app *application.Avalanche) ([]Info, error) {
+	settings := GetSettings(app)
+	if settings.Keep <= 0 {
+		return nil, nil
+	}
+	return Prune(app, settings.Keep, false)
+}
@@ -0,0 +1,105 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metalgo/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNameFromTemplate(t *testing.T) {
+	now := time.Date(2024, 3, 5, 10, 20, 30, 0, time.UTC)
+	tests := []struct {
+		template string
+		gitSHA   string
+		expected string
+	}{
+		{template: DefaultNameTemplate, gitSHA: "abc123", expected: "deploy-mySubnet-20240305102030"},
+		{template: "{subnet}@{gitsha}", gitSHA: "abc123", expected: "mySubnet_abc123"},
+		{template: "{gitsha}-{event}", expected: "nogit-deploy"},
+		{template: "my snapshot/{event}", expected: "my_snapshot_deploy"},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.expected, NameFromTemplate(tt.template, "deploy", "mySubnet", now, tt.gitSHA))
+	}
+}
+
+func TestListAndToPrune(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	names := []string{"auto-a", "manual", "auto-b", "auto-c", constants.DefaultSnapshotName}
+	base := time.Now().Add(-time.Hour)
+	for i, name := range names {
+		path := filepath.Join(dir, anrSnapshotPrefix+name)
+		require.NoError(os.MkdirAll(path, constants.DefaultPerms755))
+		modTime := base.Add(time.Duration(i) * time.Minute)
+		require.NoError(os.Chtimes(path, modTime, modTime))
+	}
+	require.NoError(os.WriteFile(filepath.Join(dir, "not-a-snapshot"), nil, constants.DefaultPerms755))
+
+	snapshots, err := List(dir)
+	require.NoError(err)
+	listed := []string{}
+	for _, s := range snapshots {
+		listed = append(listed, s.Name)
+	}
+	require.Equal([]string{"auto-c", "auto-b", "manual", "auto-a"}, listed)
+
+	pruneNames := func(toPrune []Info) []string {
+		names := []string{}
+		for _, s := range toPrune {
+			names = append(names, s.Name)
+		}
+		return names
+	}
+	require.Equal([]string{"auto-a"}, pruneNames(ToPrune(snapshots, 2, false)))
+	require.Equal([]string{"manual", "auto-a"}, pruneNames(ToPrune(snapshots, 2, true)))
+	require.Empty(ToPrune(snapshots, 5, true))
+	require.Len(ToPrune(snapshots, 0, false), 3)
+
+	snapshots, err = List(filepath.Join(dir, "does-not-exist"))
+	require.NoError(err)
+	require.Empty(snapshots)
+}
+
+func TestValidateNameAndGet(t *testing.T) {
+	require := require.New(t)
+	require.NoError(ValidateName("seeded-state_v1.2"))
+	for _, name := range []string{"", "my snapshot", "a/b", constants.DefaultSnapshotName, AutoPrefix + "x"} {
+		require.Error(ValidateName(name), name)
+	}
+
+	dir := t.TempDir()
+	require.NoError(os.MkdirAll(filepath.Join(dir, anrSnapshotPrefix+"seeded"), constants.DefaultPerms755))
+	s, err := Get(dir, "seeded")
+	require.NoError(err)
+	require.Equal("seeded", s.Name)
+	_, err = Get(dir, "missing")
+	require.ErrorIs(err, ErrNotFound)
+}
+
+func TestRename(t *testing.T) {
+	require := require.New(t)
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, nil, nil, nil)
+	require.NoError(os.MkdirAll(Dir(app.GetSnapshotsDir(), "seeded.saving"), constants.DefaultPerms755))
+	require.NoError(os.MkdirAll(app.GetExtraLocalNetworkSnapshotsDir(), constants.DefaultPerms755))
+	require.NoError(os.WriteFile(filepath.Join(app.GetExtraLocalNetworkSnapshotsDir(), "seeded.saving.json"), []byte("{}"), constants.WriteReadReadPerms))
+
+	s, err := Get(app.GetSnapshotsDir(), "seeded.saving")
+	require.NoError(err)
+	require.NoError(Rename(app, s, "seeded"))
+	_, err = Get(app.GetSnapshotsDir(), "seeded.saving")
+	require.ErrorIs(err, ErrNotFound)
+	_, err = Get(app.GetSnapshotsDir(), "seeded")
+	require.NoError(err)
+	require.FileExists(filepath.Join(app.GetExtraLocalNetworkSnapshotsDir(), "seeded.json"))
+	require.NoFileExists(filepath.Join(app.GetAWMRelayerSnapshotConfsDir(), "seeded.json"))
+}
@@ -14,7 +14,7 @@ wget -q -nd -m https://raw.githubusercontent.com/ava-labs/avalanche-docs/master/
 #name:TASK [modify permissions]
 chmod 755 avalanchego-installer.sh
 #name:TASK [call avalanche go install script]
-./avalanchego-installer.sh --ip static --rpc private --state-sync on --tahoe --version {{ .AvalancheGoVersion }}
+./avalanchego-installer.sh --ip static --rpc private --state-sync on {{ .NetworkFlag }} --version {{ .AvalancheGoVersion }}
 #name:TASK [get avalanche cli install script]
 wget -q -nd -m https://raw.githubusercontent.com/ava-labs/avalanche-cli/main/scripts/install.sh
 #name:TASK [modify permissions]
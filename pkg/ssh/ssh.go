@@ -100,13 +100,24 @@ func PostOverSSH(host *models.Host, path string, requestBody string) ([]byte, er
 }
 
 // RunSSHSetupNode runs script to setup node
-func RunSSHSetupNode(host *models.Host, configPath, avalancheGoVersion string, cliVersion string, isDevNet bool) error {
+func RunSSHSetupNode(host *models.Host, configPath, avalancheGoVersion string, cliVersion string, network models.Network) error {
+	// devnet nodes are installed as tahoe ones, and reconfigured afterwards
+	networkFlag := "--tahoe"
+	if network.Kind == models.Mainnet {
+		networkFlag = "--mainnet"
+	}
 	if err := RunOverSSH(
 		"Setup Node",
 		host,
 		constants.SSHLongRunningScriptTimeout,
 		"shell/setupNode.sh",
-		scriptInputs{AvalancheGoVersion: avalancheGoVersion, CLIVersion: cliVersion, IsDevNet: isDevNet, IsE2E: utils.IsE2E()},
+		scriptInputs{
+			AvalancheGoVersion: avalancheGoVersion,
+			CLIVersion:         cliVersion,
+			IsDevNet:           network.Kind == models.Devnet,
+			IsE2E:              utils.IsE2E(),
+			NetworkFlag:        networkFlag,
+		},
 	); err != nil {
 		return err
 	}
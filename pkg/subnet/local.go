@@ -18,15 +18,18 @@ import (
 	"strings"
 
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 	"golang.org/x/mod/semver"
 
 	"github.com/MetalBlockchain/coreth/params"
+	"github.com/MetalBlockchain/metal-cli/pkg/apicache"
 	"github.com/MetalBlockchain/metal-cli/pkg/application"
 	"github.com/MetalBlockchain/metal-cli/pkg/binutils"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
 	"github.com/MetalBlockchain/metal-cli/pkg/key"
 	"github.com/MetalBlockchain/metal-cli/pkg/localnetworkinterface"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/snapshot"
 	"github.com/MetalBlockchain/metal-cli/pkg/utils"
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
 	"github.com/MetalBlockchain/metal-cli/pkg/vm"
@@ -185,7 +188,7 @@ func IssueTransformSubnetTx(
 	maxSupply uint64,
 ) (ids.ID, ids.ID, error) {
 	ctx := context.Background()
-	api := constants.LocalAPIEndpoint
+	api := models.LocalAPIEndpoint()
 	wallet, err := primary.MakeWallet(
 		ctx,
 		&primary.WalletConfig{
@@ -240,9 +243,10 @@ func IssueAddPermissionlessValidatorTx(
 	assetID ids.ID,
 	startTime uint64,
 	endTime uint64,
+	rewardAddr ids.ShortID,
 ) (ids.ID, error) {
 	ctx := context.Background()
-	api := constants.LocalAPIEndpoint
+	api := models.LocalAPIEndpoint()
 	wallet, err := primary.MakeWallet(
 		ctx,
 		&primary.WalletConfig{
@@ -255,10 +259,13 @@ func IssueAddPermissionlessValidatorTx(
 	if err != nil {
 		return ids.Empty, err
 	}
+	if rewardAddr == ids.ShortEmpty {
+		rewardAddr = genesis.EWOQKey.PublicKey().Address()
+	}
 	owner := &secp256k1fx.OutputOwners{
 		Threshold: 1,
 		Addrs: []ids.ShortID{
-			genesis.EWOQKey.PublicKey().Address(),
+			rewardAddr,
 		},
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), constants.DefaultConfirmTxTimeout)
@@ -283,6 +290,7 @@ func IssueAddPermissionlessValidatorTx(
 	if err != nil {
 		return ids.Empty, err
 	}
+	apicache.Purge()
 	return tx.ID(), err
 }
 
@@ -294,9 +302,10 @@ func IssueAddPermissionlessDelegatorTx(
 	assetID ids.ID,
 	startTime uint64,
 	endTime uint64,
+	rewardAddr ids.ShortID,
 ) (ids.ID, error) {
 	ctx := context.Background()
-	api := constants.LocalAPIEndpoint
+	api := models.LocalAPIEndpoint()
 	wallet, err := primary.MakeWallet(
 		ctx,
 		&primary.WalletConfig{
@@ -309,6 +318,9 @@ func IssueAddPermissionlessDelegatorTx(
 	if err != nil {
 		return ids.Empty, err
 	}
+	if rewardAddr == ids.ShortEmpty {
+		rewardAddr = genesis.EWOQKey.PublicKey().Address()
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), constants.DefaultConfirmTxTimeout)
 	tx, err := wallet.P().IssueAddPermissionlessDelegatorTx(
 		&txs.SubnetValidator{
@@ -321,13 +333,19 @@ func IssueAddPermissionlessDelegatorTx(
 			Subnet: subnetID,
 		},
 		assetID,
-		&secp256k1fx.OutputOwners{},
+		&secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				rewardAddr,
+			},
+		},
 		common.WithContext(ctx),
 	)
 	defer cancel()
 	if err != nil {
 		return ids.Empty, err
 	}
+	apicache.Purge()
 	return tx.ID(), err
 }
 
@@ -347,7 +365,7 @@ func (d *LocalDeployer) StartServer() error {
 }
 
 func GetCurrentSupply(subnetID ids.ID) error {
-	api := constants.LocalAPIEndpoint
+	api := models.LocalAPIEndpoint()
 	pClient := platformvm.NewClient(api)
 	ctx, cancel := utils.GetAPIContext()
 	defer cancel()
@@ -432,6 +450,15 @@ func (d *LocalDeployer) doDeploy(chain string, chainGenesis []byte, genesisPath
 		networkBooted = false
 	}
 
+	if networkBooted {
+		if settings := snapshot.GetSettings(d.app); settings.Auto {
+			if err := d.takeAutoSnapshot(ctx, cli, avalancheGoBinPath, runDir, settings, chain); err != nil {
+				FindErrorLogs(rootDir, backendLogDir)
+				return nil, err
+			}
+		}
+	}
+
 	if !networkBooted {
 		if err := d.startNetwork(ctx, cli, avalancheGoBinPath, runDir); err != nil {
 			FindErrorLogs(rootDir, backendLogDir)
@@ -463,14 +490,24 @@ func (d *LocalDeployer) doDeploy(chain string, chainGenesis []byte, genesisPath
 		return nil, fmt.Errorf("failed to query network health: %w", err)
 	}
 	rootDir = clusterInfo.GetRootDataDir()
+	numBlockchains := len(clusterInfo.CustomChains)
+	undeployedSubnetIDs, err := RemoveUndeployedChains(d.app, clusterInfo)
+	if err != nil {
+		return nil, err
+	}
 
 	if alreadyDeployed(chainVMID, clusterInfo) {
 		return nil, fmt.Errorf("subnet %s has already been deployed", chain)
 	}
 
-	numBlockchains := len(clusterInfo.CustomChains)
-
 	subnetIDs := maps.Keys(clusterInfo.Subnets)
+	// the nodes don't track the subnets of undeployed blockchains anymore, so
+	// they are only reused if there are no others
+	if availableSubnetIDs := utils.Filter(subnetIDs, func(subnetID string) bool {
+		return !slices.Contains(undeployedSubnetIDs, subnetID)
+	}); len(availableSubnetIDs) > 0 {
+		subnetIDs = availableSubnetIDs
+	}
 
 	// in order to make subnet deploy faster, a set of validated subnet IDs is preloaded
 	// in the bootstrap snapshot
@@ -495,6 +532,8 @@ func (d *LocalDeployer) doDeploy(chain string, chainGenesis []byte, genesisPath
 		perNodeChainConfigFile = filepath.Join(d.app.GetSubnetDir(), chain, constants.PerNodeChainConfigFileName)
 		subnetConfig           string
 		subnetConfigFile       = filepath.Join(d.app.GetSubnetDir(), chain, constants.SubnetConfigFileName)
+		networkUpgrade         string
+		networkUpgradeFile     = d.app.GetUpgradeBytesFilepath(chain)
 	)
 	if _, err := os.Stat(chainConfigFile); err == nil {
 		// currently the ANR only accepts the file as a path, not its content
@@ -506,11 +545,18 @@ func (d *LocalDeployer) doDeploy(chain string, chainGenesis []byte, genesisPath
 	if _, err := os.Stat(subnetConfigFile); err == nil {
 		subnetConfig = subnetConfigFile
 	}
+	if _, err := os.Stat(networkUpgradeFile); err == nil {
+		networkUpgrade = networkUpgradeFile
+	}
 
 	// install the plugin binary for the new VM
 	if err := d.installPlugin(chainVMID, d.vmBin); err != nil {
 		return nil, err
 	}
+	// the nodes also know the VM by its name from their next start
+	if err := AddLocalVMAlias(d.app, chainVMID, chain); err != nil {
+		d.app.Log.Warn("failed adding VM alias", zap.String("vm-id", chainVMID.String()), zap.Error(err))
+	}
 
 	ux.Logger.PrintToUser("")
 	ux.Logger.PrintToUser("Deploying Blockchain. Wait until network acknowledges...")
@@ -526,6 +572,7 @@ func (d *LocalDeployer) doDeploy(chain string, chainGenesis []byte, genesisPath
 				SubnetConfig: subnetConfig,
 			},
 			ChainConfig:        chainConfig,
+			NetworkUpgrade:     networkUpgrade,
 			BlockchainAlias:    chain,
 			PerNodeChainConfig: perNodeChainConfig,
 		},
@@ -555,6 +602,9 @@ func (d *LocalDeployer) doDeploy(chain string, chainGenesis []byte, genesisPath
 		}
 		return nil, fmt.Errorf("failed to query network health: %w", err)
 	}
+	if _, err := RemoveUndeployedChains(d.app, clusterInfo); err != nil {
+		return nil, err
+	}
 
 	ux.Logger.PrintToUser("")
 	ux.Logger.PrintToUser("Blockchain ready to use. Local network node endpoints:")
@@ -810,6 +860,25 @@ func getExpectedDefaultSnapshotSHA256Sum(isSingleNode bool, isPreCortina17 bool)
 	return expectedSum, nil
 }
 
+// isBootstrapSnapshotOutdated returns true if the bootstrap snapshot archive at [archivePath]
+// doesn't match the sum given by [getExpectedSum]. On offline mode, the archive is never
+// reported as outdated
+func isBootstrapSnapshotOutdated(archivePath string, getExpectedSum func() (string, error)) (bool, error) {
+	if utils.IsOffline() {
+		return false, nil
+	}
+	gotSum, err := utils.GetSHA256FromDisk(archivePath)
+	if err != nil {
+		return false, err
+	}
+	expectedSum, err := getExpectedSum()
+	if err != nil {
+		ux.Logger.PrintToUser("Warning: failure verifying that the local snapshot is the latest one: %s", err)
+		return false, nil
+	}
+	return gotSum != expectedSum, nil
+}
+
 // Initialize default snapshot with bootstrap snapshot archive
 // If force flag is set to true, overwrite the default snapshot if it exists
 func SetDefaultSnapshot(snapshotsDir string, resetCurrentSnapshot bool, avagoVersion string, isSingleNode bool) (bool, error) {
@@ -848,18 +917,17 @@ func SetDefaultSnapshot(snapshotsDir string, resetCurrentSnapshot bool, avagoVer
 	if _, err := os.Stat(bootstrapSnapshotArchivePath); os.IsNotExist(err) {
 		downloadSnapshot = true
 	} else {
-		gotSum, err := utils.GetSHA256FromDisk(bootstrapSnapshotArchivePath)
+		downloadSnapshot, err = isBootstrapSnapshotOutdated(bootstrapSnapshotArchivePath, func() (string, error) {
+			return getExpectedDefaultSnapshotSHA256Sum(isSingleNode, isPreCortina17)
+		})
 		if err != nil {
 			return false, err
 		}
-		expectedSum, err := getExpectedDefaultSnapshotSHA256Sum(isSingleNode, isPreCortina17)
-		if err != nil {
-			ux.Logger.PrintToUser("Warning: failure verifying that the local snapshot is the latest one: %s", err)
-		} else if gotSum != expectedSum {
-			downloadSnapshot = true
-		}
 	}
 	if downloadSnapshot {
+		if utils.IsOffline() {
+			return false, fmt.Errorf("%w: the bootstrap snapshot %s was not downloaded before", utils.ErrOffline, bootstrapSnapshotArchiveName)
+		}
 		resp, err := http.Get(url)
 		if err != nil {
 			return false, fmt.Errorf("failed downloading bootstrap snapshot: %w", err)
@@ -904,6 +972,45 @@ func (d *LocalDeployer) startNetwork(
 	cli client.Client,
 	avalancheGoBinPath string,
 	runDir string,
+) error {
+	return d.startNetworkFromSnapshot(ctx, cli, avalancheGoBinPath, runDir, constants.DefaultSnapshotName)
+}
+
+// takeAutoSnapshot saves the state of the running network into a new auto snapshot,
+// and restarts the network from it. Old auto snapshots are pruned according to
+// the retention settings
+func (d *LocalDeployer) takeAutoSnapshot(
+	ctx context.Context,
+	cli client.Client,
+	avalancheGoBinPath string,
+	runDir string,
+	settings snapshot.Settings,
+	subnetName string,
+) error {
+	snapshotName := snapshot.AutoName(settings, "deploy", subnetName)
+	ux.Logger.PrintToUser("Saving pre-deploy network state as snapshot %s...", snapshotName)
+	if _, err := cli.SaveSnapshot(ctx, snapshotName); err != nil {
+		return fmt.Errorf("failed to save snapshot %s: %w", snapshotName, err)
+	}
+	if err := d.startNetworkFromSnapshot(ctx, cli, avalancheGoBinPath, runDir, snapshotName); err != nil {
+		return err
+	}
+	pruned, err := snapshot.ApplyRetention(d.app)
+	if err != nil {
+		d.app.Log.Warn("failed pruning old snapshots", zap.Error(err))
+	}
+	for _, s := range pruned {
+		d.app.Log.Debug("pruned snapshot", zap.String("snapshot-name", s.Name))
+	}
+	return nil
+}
+
+func (d *LocalDeployer) startNetworkFromSnapshot(
+	ctx context.Context,
+	cli client.Client,
+	avalancheGoBinPath string,
+	runDir string,
+	snapshotName string,
 ) error {
 	loadSnapshotOpts := []client.OpOption{
 		client.WithExecPath(avalancheGoBinPath),
@@ -917,15 +1024,22 @@ func (d *LocalDeployer) startNetwork(
 	if err != nil {
 		return nil
 	}
+	configStr, err = GetLocalNetworkSettings(d.app).GlobalNodeConfig(configStr)
+	if err != nil {
+		return err
+	}
 	if configStr != "" {
 		loadSnapshotOpts = append(loadSnapshotOpts, client.WithGlobalNodeConfig(configStr))
 	}
 
 	ux.Logger.PrintToUser("")
 	ux.Logger.PrintToUser("Booting Network. Wait until healthy...")
-	resp, err := cli.LoadSnapshot(
+	resp, err := LoadSnapshotWithProgress(
 		ctx,
-		constants.DefaultSnapshotName,
+		cli,
+		d.app.GetSnapshotsDir(),
+		snapshotName,
+		runDir,
 		loadSnapshotOpts...,
 	)
 	if err != nil {
@@ -937,7 +1051,7 @@ func (d *LocalDeployer) startNetwork(
 }
 
 // Returns an error if the server cannot be contacted. You may want to ignore this error.
-func GetLocallyDeployedSubnets() (map[string]struct{}, error) {
+func GetLocallyDeployedSubnets(app *application.Avalanche) (map[string]struct{}, error) {
 	deployedNames := map[string]struct{}{}
 	// if the server can not be contacted, or there is a problem with the query,
 	// DO NOT FAIL, just print No for deployed status
@@ -953,6 +1067,9 @@ func GetLocallyDeployedSubnets() (map[string]struct{}, error) {
 		return nil, err
 	}
 
+	if _, err := RemoveUndeployedChains(app, resp.GetClusterInfo()); err != nil {
+		return nil, err
+	}
 	for _, chain := range resp.GetClusterInfo().CustomChains {
 		deployedNames[chain.ChainName] = struct{}{}
 	}
@@ -962,7 +1079,7 @@ func GetLocallyDeployedSubnets() (map[string]struct{}, error) {
 
 func IssueRemoveSubnetValidatorTx(kc keychain.Keychain, subnetID ids.ID, nodeID ids.NodeID) (ids.ID, error) {
 	ctx := context.Background()
-	api := constants.LocalAPIEndpoint
+	api := models.LocalAPIEndpoint()
 	wallet, err := primary.MakeWallet(
 		ctx,
 		&primary.WalletConfig{
@@ -977,11 +1094,15 @@ func IssueRemoveSubnetValidatorTx(kc keychain.Keychain, subnetID ids.ID, nodeID
 	}
 
 	tx, err := wallet.P().IssueRemoveSubnetValidatorTx(nodeID, subnetID)
-	return tx.ID(), err
+	if err != nil {
+		return ids.Empty, err
+	}
+	apicache.Purge()
+	return tx.ID(), nil
 }
 
 func GetSubnetValidators(subnetID ids.ID) ([]platformvm.ClientPermissionlessValidator, error) {
-	api := constants.LocalAPIEndpoint
+	api := models.LocalAPIEndpoint()
 	pClient := platformvm.NewClient(api)
 	ctx, cancel := utils.GetAPIContext()
 	defer cancel()
@@ -990,7 +1111,7 @@ func GetSubnetValidators(subnetID ids.ID) ([]platformvm.ClientPermissionlessVali
 }
 
 func CheckNodeIsInSubnetValidators(subnetID ids.ID, nodeID string) (bool, error) {
-	api := constants.LocalAPIEndpoint
+	api := models.LocalAPIEndpoint()
 	pClient := platformvm.NewClient(api)
 	ctx, cancel := utils.GetAPIContext()
 	defer cancel()
@@ -1010,6 +1131,15 @@ func CheckNodeIsInSubnetValidators(subnetID ids.ID, nodeID string) (bool, error)
 type ExtraLocalNetworkData struct {
 	CChainTeleporterMessengerAddress string
 	CChainTeleporterRegistryAddress  string
+	// NumNodes is the number of nodes the local network was started with
+	NumNodes uint32 `json:",omitempty"`
+	// AvalancheGoVersion and AvalancheGoPath pin the metalgo the local network
+	// was explicitly started with, so that it is reused on the next start
+	AvalancheGoVersion string `json:",omitempty"`
+	AvalancheGoPath    string `json:",omitempty"`
+	// UndeployedBlockchainIDs are the blockchains removed with subnet undeploy,
+	// which remain registered on the local P-Chain
+	UndeployedBlockchainIDs []string `json:",omitempty"`
 }
 
 func GetExtraLocalNetworkData(app *application.Avalanche) (*ExtraLocalNetworkData, error) {
@@ -1024,17 +1154,64 @@ func GetExtraLocalNetworkData(app *application.Avalanche) (*ExtraLocalNetworkDat
 	return &extraLocalNetworkData, nil
 }
 
+// loads the current extra local network data, or returns an empty one if there is none yet
+func getOrEmptyExtraLocalNetworkData(app *application.Avalanche) (*ExtraLocalNetworkData, error) {
+	extraLocalNetworkData, err := GetExtraLocalNetworkData(app)
+	if errors.Is(err, os.ErrNotExist) {
+		return &ExtraLocalNetworkData{}, nil
+	}
+	return extraLocalNetworkData, err
+}
+
+func writeExtraLocalNetworkData(app *application.Avalanche, extraLocalNetworkData *ExtraLocalNetworkData) error {
+	bs, err := json.Marshal(extraLocalNetworkData)
+	if err != nil {
+		return err
+	}
+	release, err := app.Lock()
+	if err != nil {
+		return err
+	}
+	defer release()
+	return os.WriteFile(app.GetExtraLocalNetworkDataPath(), bs, constants.WriteReadReadPerms)
+}
+
 func WriteExtraLocalNetworkData(app *application.Avalanche, cchainTeleporterMessengerAddress string, cchainTeleporterRegistryAddress string) error {
-	extraLocalNetworkDataPath := app.GetExtraLocalNetworkDataPath()
-	extraLocalNetworkData := ExtraLocalNetworkData{
-		CChainTeleporterMessengerAddress: cchainTeleporterMessengerAddress,
-		CChainTeleporterRegistryAddress:  cchainTeleporterRegistryAddress,
+	extraLocalNetworkData, err := getOrEmptyExtraLocalNetworkData(app)
+	if err != nil {
+		return err
 	}
-	bs, err := json.Marshal(&extraLocalNetworkData)
+	extraLocalNetworkData.CChainTeleporterMessengerAddress = cchainTeleporterMessengerAddress
+	extraLocalNetworkData.CChainTeleporterRegistryAddress = cchainTeleporterRegistryAddress
+	return writeExtraLocalNetworkData(app, extraLocalNetworkData)
+}
+
+// UpdateExtraLocalNetworkData applies [update] to the extra local network data,
+// keeping the fields it doesn't change
+func UpdateExtraLocalNetworkData(app *application.Avalanche, update func(*ExtraLocalNetworkData)) error {
+	extraLocalNetworkData, err := getOrEmptyExtraLocalNetworkData(app)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(extraLocalNetworkDataPath, bs, constants.WriteReadReadPerms)
+	update(extraLocalNetworkData)
+	return writeExtraLocalNetworkData(app, extraLocalNetworkData)
+}
+
+// GetSnapshotExtraLocalNetworkData returns the extra local network data saved along
+// with [snapshotName], or an empty one if there is none
+func GetSnapshotExtraLocalNetworkData(app *application.Avalanche, snapshotName string) (*ExtraLocalNetworkData, error) {
+	extraLocalNetworkData := ExtraLocalNetworkData{}
+	bs, err := os.ReadFile(filepath.Join(app.GetExtraLocalNetworkSnapshotsDir(), snapshotName+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return &extraLocalNetworkData, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(bs, &extraLocalNetworkData); err != nil {
+		return nil, err
+	}
+	return &extraLocalNetworkData, nil
 }
 
 func GetChainID(network models.Network, chainName string) (ids.ID, error) {
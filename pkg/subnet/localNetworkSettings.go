@@ -0,0 +1,180 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/snapshot"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metalgo/config"
+)
+
+const snapshotNetworkConfigFileName = "network.json"
+
+// LocalNetworkSettings are the user preferences for the local network node ports
+// and bind address. Named local networks shift the configured ports by
+// constants.LocalNetworkPortsStep times their index
+type LocalNetworkSettings struct {
+	// HTTPPort is the HTTP port of the first node. The next nodes use the following
+	// even ports
+	HTTPPort int
+	// StakingPort is the staking port of the first node. The next nodes use the
+	// following odd ports
+	StakingPort int
+	// HTTPHost is the address the node APIs listen on, empty for the metalgo default
+	HTTPHost string
+	// VMAliasesFile gives the nodes the names of the locally deployed VMs, if any
+	VMAliasesFile string
+}
+
+func GetLocalNetworkSettings(app *application.Avalanche) LocalNetworkSettings {
+	settings := LocalNetworkSettings{
+		HTTPPort:    app.Conf.GetConfigIntValue(constants.ConfigLocalHTTPPortKey),
+		StakingPort: app.Conf.GetConfigIntValue(constants.ConfigLocalStakingPortKey),
+		HTTPHost:    app.Conf.GetConfigStringValue(constants.ConfigLocalHTTPHostKey),
+	}
+	if settings.HTTPPort == 0 {
+		settings.HTTPPort = constants.LocalNetworkBaseHTTPPort
+	}
+	if settings.StakingPort == 0 {
+		settings.StakingPort = constants.LocalNetworkBaseStakingPort
+	}
+	portsOffset := app.GetSelectedLocalNetworkIndex() * constants.LocalNetworkPortsStep
+	settings.HTTPPort += portsOffset
+	settings.StakingPort += portsOffset
+	if utils.FileExists(app.GetVMAliasesPath()) {
+		settings.VMAliasesFile = app.GetVMAliasesPath()
+	}
+	return settings
+}
+
+func (s LocalNetworkSettings) Validate() error {
+	for _, port := range []int{s.HTTPPort, s.StakingPort} {
+		if port <= 0 || port > math.MaxUint16 {
+			return fmt.Errorf("invalid local network port %d", port)
+		}
+	}
+	if s.HTTPPort == s.StakingPort {
+		return fmt.Errorf("local network HTTP and staking ports can't be the same (%d)", s.HTTPPort)
+	}
+	if s.HTTPHost != "" && net.ParseIP(s.HTTPHost) == nil {
+		return fmt.Errorf("invalid local network HTTP host %q: expected an IP address", s.HTTPHost)
+	}
+	return nil
+}
+
+// NodePorts returns the HTTP and staking ports of the [i]-th node (zero based)
+func (s LocalNetworkSettings) NodePorts(i int) (int, int) {
+	return s.HTTPPort + 2*i, s.StakingPort + 2*i
+}
+
+// GlobalNodeConfig adds the bind address and the VM aliases file, if any, to the
+// node config [nodeConfig]
+func (s LocalNetworkSettings) GlobalNodeConfig(nodeConfig string) (string, error) {
+	flags := map[string]interface{}{}
+	if s.HTTPHost != "" {
+		flags[config.HTTPHostKey] = s.HTTPHost
+	}
+	if s.VMAliasesFile != "" {
+		flags[config.VMAliasesFileKey] = s.VMAliasesFile
+	}
+	if len(flags) == 0 {
+		return nodeConfig, nil
+	}
+	return setNodeConfigFlags(nodeConfig, flags)
+}
+
+// AddedNodeConfig returns the node config [nodeConfig] of a node added to the network
+// as its [i]-th node (zero based), with the bind address and the node ports
+func (s LocalNetworkSettings) AddedNodeConfig(nodeConfig string, i int) (string, error) {
+	nodeConfig, err := s.GlobalNodeConfig(nodeConfig)
+	if err != nil {
+		return "", err
+	}
+	httpPort, stakingPort := s.NodePorts(i)
+	return setNodeConfigFlags(nodeConfig, map[string]interface{}{
+		config.HTTPPortKey:    httpPort,
+		config.StakingPortKey: stakingPort,
+	})
+}
+
+func setNodeConfigFlags(nodeConfig string, flags map[string]interface{}) (string, error) {
+	configMap := map[string]interface{}{}
+	if nodeConfig != "" {
+		if err := json.Unmarshal([]byte(nodeConfig), &configMap); err != nil {
+			return "", fmt.Errorf("invalid node config: %w", err)
+		}
+	}
+	for k, v := range flags {
+		configMap[k] = v
+	}
+	configBytes, err := json.Marshal(configMap)
+	if err != nil {
+		return "", err
+	}
+	return string(configBytes), nil
+}
+
+// SetSnapshotNodePorts sets the node ports stored at snapshot [snapshotName] under
+// [snapshotsDir] to the ones given by [settings], so the network uses them when loaded
+func SetSnapshotNodePorts(snapshotsDir string, snapshotName string, settings LocalNetworkSettings) error {
+	networkConfigPath := filepath.Join(snapshot.Dir(snapshotsDir, snapshotName), snapshotNetworkConfigFileName)
+	networkConfigBytes, err := os.ReadFile(networkConfigPath)
+	if errors.Is(err, os.ErrNotExist) {
+		// the network runner reports a missing snapshot by itself
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// generic maps are used so that the snapshot fields the CLI doesn't know about are kept
+	var networkConfig map[string]interface{}
+	if err := json.Unmarshal(networkConfigBytes, &networkConfig); err != nil {
+		return fmt.Errorf("failed parsing snapshot network config %s: %w", networkConfigPath, err)
+	}
+	nodeConfigs, _ := networkConfig["nodeConfigs"].([]interface{})
+	nodeNames := make([]string, len(nodeConfigs))
+	for i, nodeConfigIntf := range nodeConfigs {
+		nodeConfig, ok := nodeConfigIntf.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("unexpected node config format at %s", networkConfigPath)
+		}
+		nodeNames[i], _ = nodeConfig["name"].(string)
+	}
+	// node1, node2, ..., node10
+	order := make([]int, len(nodeConfigs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := nodeNames[order[i]], nodeNames[order[j]]
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return a < b
+	})
+	for position, i := range order {
+		nodeConfig := nodeConfigs[i].(map[string]interface{})
+		flags, ok := nodeConfig["flags"].(map[string]interface{})
+		if !ok {
+			flags = map[string]interface{}{}
+			nodeConfig["flags"] = flags
+		}
+		flags[config.HTTPPortKey], flags[config.StakingPortKey] = settings.NodePorts(position)
+	}
+	networkConfigBytes, err = json.MarshalIndent(networkConfig, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(networkConfigPath, networkConfigBytes, constants.WriteReadReadPerms)
+}
@@ -0,0 +1,74 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/snapshot"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetSnapshotNodePorts(t *testing.T) {
+	require := require.New(t)
+	snapshotsDir := t.TempDir()
+	snapshotDir := snapshot.Dir(snapshotsDir, "test")
+	require.NoError(os.MkdirAll(snapshotDir, constants.DefaultPerms755))
+	networkConfigPath := filepath.Join(snapshotDir, snapshotNetworkConfigFileName)
+	networkConfig := `{
+		"genesis": "{}",
+		"nodeConfigs": [
+			{"name": "node10", "flags": {"http-port": 9668}},
+			{"name": "node2", "flags": {"http-port": 9652, "log-level": "info"}},
+			{"name": "node1"}
+		]
+	}`
+	require.NoError(os.WriteFile(networkConfigPath, []byte(networkConfig), constants.WriteReadReadPerms))
+
+	settings := LocalNetworkSettings{HTTPPort: 19650, StakingPort: 29650}
+	require.NoError(SetSnapshotNodePorts(snapshotsDir, "test", settings))
+
+	networkConfigBytes, err := os.ReadFile(networkConfigPath)
+	require.NoError(err)
+	var result struct {
+		Genesis     string `json:"genesis"`
+		NodeConfigs []struct {
+			Name  string                 `json:"name"`
+			Flags map[string]interface{} `json:"flags"`
+		} `json:"nodeConfigs"`
+	}
+	require.NoError(json.Unmarshal(networkConfigBytes, &result))
+	require.Equal("{}", result.Genesis)
+	ports := map[string][2]float64{}
+	for _, nodeConfig := range result.NodeConfigs {
+		ports[nodeConfig.Name] = [2]float64{
+			nodeConfig.Flags["http-port"].(float64),
+			nodeConfig.Flags["staking-port"].(float64),
+		}
+	}
+	require.Equal(map[string][2]float64{
+		"node1":  {19650, 29650},
+		"node2":  {19652, 29652},
+		"node10": {19654, 29654},
+	}, ports)
+	require.Equal("info", result.NodeConfigs[1].Flags["log-level"])
+
+	// missing snapshots are left to the network runner
+	require.NoError(SetSnapshotNodePorts(snapshotsDir, "missing", settings))
+}
+
+func TestAddedNodeConfig(t *testing.T) {
+	require := require.New(t)
+	settings := LocalNetworkSettings{HTTPPort: 9650, StakingPort: 9651, HTTPHost: "0.0.0.0"}
+	nodeConfig, err := settings.AddedNodeConfig(`{"log-level":"debug"}`, 5)
+	require.NoError(err)
+	require.JSONEq(`{"log-level":"debug","http-host":"0.0.0.0","http-port":9660,"staking-port":9661}`, nodeConfig)
+
+	require.NoError(settings.Validate())
+	settings.StakingPort = settings.HTTPPort
+	require.Error(settings.Validate())
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/txutils"
+	"github.com/MetalBlockchain/metalgo/database"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/formatting/address"
+	"github.com/MetalBlockchain/metalgo/vms/components/avax"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/fx"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/txs"
+	"github.com/MetalBlockchain/metalgo/vms/secp256k1fx"
+	"github.com/MetalBlockchain/metalgo/wallet/chain/p/signer"
+)
+
+var _ signer.Backend = (*offlineSignerBackend)(nil)
+
+// offlineSignerBackend answers the signer queries from previously exported
+// info, instead of from the P-Chain. UTXOs are never available, so the signer
+// skips the tx inputs and only fills in subnet auth signatures
+type offlineSignerBackend struct {
+	subnetID ids.ID
+	owner    *secp256k1fx.OutputOwners
+}
+
+func newOfflineSignerBackend(signingInfo *txutils.OfflineSigningInfo) (*offlineSignerBackend, error) {
+	addrs, err := address.ParseToIDs(signingInfo.ControlKeys)
+	if err != nil {
+		return nil, fmt.Errorf("failure parsing control keys: %w", err)
+	}
+	return &offlineSignerBackend{
+		subnetID: signingInfo.SubnetID,
+		owner: &secp256k1fx.OutputOwners{
+			Addrs:     addrs,
+			Threshold: signingInfo.Threshold,
+		},
+	}, nil
+}
+
+func (*offlineSignerBackend) GetUTXO(context.Context, ids.ID, ids.ID) (*avax.UTXO, error) {
+	return nil, database.ErrNotFound
+}
+
+func (b *offlineSignerBackend) GetSubnetOwner(_ context.Context, subnetID ids.ID) (fx.Owner, error) {
+	if subnetID != b.subnetID {
+		return nil, fmt.Errorf("no offline signing info for subnet %s", subnetID)
+	}
+	return b.owner, nil
+}
+
+// SignOffline adds the subnet auth signatures the wallet can provide to [tx],
+// without connecting to the P-Chain. The subnet owner is taken from [signingInfo],
+// as exported along the tx on a connected machine
+func (d *PublicDeployer) SignOffline(
+	tx *txs.Tx,
+	subnetAuthKeysStrs []string,
+	signingInfo *txutils.OfflineSigningInfo,
+) error {
+	subnetAuthKeys, err := address.ParseToIDs(subnetAuthKeysStrs)
+	if err != nil {
+		return fmt.Errorf("failure parsing subnet auth keys: %w", err)
+	}
+	if ok := d.checkWalletHasSubnetAuthAddresses(subnetAuthKeys); !ok {
+		return ErrNoSubnetAuthKeysInWallet
+	}
+	backend, err := newOfflineSignerBackend(signingInfo)
+	if err != nil {
+		return err
+	}
+	if d.kc.UsesLedger {
+		txName := txutils.GetLedgerDisplayName(tx)
+		if len(txName) == 0 {
+			showLedgerSignatureMsg(d.kc.UsesLedger, d.kc.HasOnlyOneKey(), "tx hash")
+		} else {
+			showLedgerSignatureMsg(d.kc.UsesLedger, d.kc.HasOnlyOneKey(), fmt.Sprintf("%s transaction", txName))
+		}
+	}
+	if err := signer.New(d.kc.Keychain, backend).Sign(context.Background(), tx); err != nil {
+		return fmt.Errorf("error signing tx: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metalgo/utils/units"
+)
+
+const (
+	pChainCallAttempts       = 3
+	pChainCallInitialBackoff = 1 * time.Second
+)
+
+var (
+	ErrEndpointUnreachable = errors.New("P-Chain endpoint unreachable")
+	ErrInsufficientFunds   = errors.New("insufficient funds")
+	ErrTxConflict          = errors.New("conflicting transaction")
+)
+
+type pChainErrorClass struct {
+	err      error
+	patterns []string
+	hint     string
+}
+
+var pChainErrorClasses = []pChainErrorClass{
+	{
+		err: ErrEndpointUnreachable,
+		patterns: []string{
+			"connection refused",
+			"no such host",
+			"connection reset",
+			"i/o timeout",
+			"context deadline exceeded",
+			"502 bad gateway",
+			"503 service unavailable",
+			"504 gateway timeout",
+			": eof",
+		},
+		hint: "check your internet connection, or that the node behind the endpoint is up and bootstrapped",
+	},
+	{
+		err: ErrInsufficientFunds,
+		patterns: []string{
+			"insufficient funds",
+			"insufficient unlocked funds",
+			"insufficient locked funds",
+		},
+		hint: "the keys paying for the transaction don't hold enough unlocked P-Chain funds. Fund them (eg with 'metal key transfer') and try again",
+	},
+	{
+		err: ErrTxConflict,
+		patterns: []string{
+			"conflict",
+			"already a validator",
+			"duplicate validator",
+			"missing utxo",
+			"failed to get utxo",
+			"already consumed",
+		},
+		hint: "the transaction conflicts with one already accepted or in progress. Check the current P-Chain state before retrying",
+	},
+}
+
+// ClassifyPChainError wraps well known P-Chain RPC failures into one of
+// [ErrEndpointUnreachable], [ErrInsufficientFunds] or [ErrTxConflict], with
+// a hint on how to solve them. Unknown errors are returned unchanged
+func ClassifyPChainError(err error, endpoint string) error {
+	if err == nil {
+		return nil
+	}
+	for _, class := range pChainErrorClasses {
+		if errors.Is(err, class.err) {
+			return err
+		}
+	}
+	errStr := strings.ToLower(err.Error())
+	for _, class := range pChainErrorClasses {
+		matches := class.err == ErrEndpointUnreachable && errors.Is(err, context.DeadlineExceeded)
+		for _, pattern := range class.patterns {
+			matches = matches || strings.Contains(errStr, pattern)
+		}
+		if matches {
+			if class.err == ErrEndpointUnreachable && endpoint != "" {
+				return fmt.Errorf("%w (%s): %s: %w", class.err, endpoint, class.hint, err)
+			}
+			return fmt.Errorf("%w: %s: %w", class.err, class.hint, err)
+		}
+	}
+	return err
+}
+
+// IsRetryablePChainError returns true for transient failures that may succeed
+// if the call is repeated
+func IsRetryablePChainError(err error) bool {
+	return errors.Is(ClassifyPChainError(err, ""), ErrEndpointUnreachable)
+}
+
+// retryPChainCall calls [fn] with exponential backoff on transient failures,
+// classifying the final error
+func retryPChainCall[T any](endpoint string, fn func() (T, error)) (T, error) {
+	result, err := utils.RetryWithExponentialBackoff(
+		fn,
+		pChainCallAttempts,
+		pChainCallInitialBackoff,
+		IsRetryablePChainError,
+	)
+	return result, ClassifyPChainError(err, endpoint)
+}
+
+// CheckFeeBalance returns an [ErrInsufficientFunds] error telling how much is missing,
+// and where to send it, if the unlocked P-Chain [balance] of the paying [addrs]
+// does not cover the [fee] of [txName]
+func CheckFeeBalance(txName string, fee uint64, balance uint64, addrs []string) error {
+	if balance >= fee {
+		return nil
+	}
+	target := "address " + strings.Join(addrs, ", ")
+	if len(addrs) > 1 {
+		target = "any of the addresses " + strings.Join(addrs, ", ")
+	}
+	return fmt.Errorf(
+		"%w: %s costs a fee of %.9f %s but the paying keys hold %.9f %s. Need %.9f more %s (%d nano%s) on %s",
+		ErrInsufficientFunds,
+		txName,
+		float64(fee)/float64(units.Avax),
+		constants.AVAXSymbol,
+		float64(balance)/float64(units.Avax),
+		constants.AVAXSymbol,
+		float64(fee-balance)/float64(units.Avax),
+		constants.AVAXSymbol,
+		fee-balance,
+		constants.AVAXSymbol,
+		target,
+	)
+}
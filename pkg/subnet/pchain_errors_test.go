@@ -0,0 +1,73 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyPChainError(t *testing.T) {
+	errUnknown := errors.New("some unknown failure")
+	tests := []struct {
+		name              string
+		err               error
+		expectedErr       error
+		expectedRetryable bool
+	}{
+		{
+			name:              "connection refused",
+			err:               errors.New(`Post "http://127.0.0.1:9650/ext/P": dial tcp 127.0.0.1:9650: connect: connection refused`),
+			expectedErr:       ErrEndpointUnreachable,
+			expectedRetryable: true,
+		},
+		{
+			name:              "deadline exceeded",
+			err:               fmt.Errorf("issuing tx: %w", context.DeadlineExceeded),
+			expectedErr:       ErrEndpointUnreachable,
+			expectedRetryable: true,
+		},
+		{
+			name:        "insufficient funds",
+			err:         errors.New("couldn't issue tx: insufficient funds: provided UTXOs need 1000000 more units of asset"),
+			expectedErr: ErrInsufficientFunds,
+		},
+		{
+			name:        "already validator",
+			err:         errors.New("failed verification: attempted to issue duplicate subnet validator for NodeID-xyz: already a validator"),
+			expectedErr: ErrTxConflict,
+		},
+		{
+			name:        "unknown",
+			err:         errUnknown,
+			expectedErr: errUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			err := ClassifyPChainError(tt.err, "http://127.0.0.1:9650")
+			require.ErrorIs(err, tt.expectedErr)
+			require.ErrorIs(err, tt.err)
+			require.Equal(tt.expectedRetryable, IsRetryablePChainError(tt.err))
+			// classification is idempotent
+			require.Equal(err.Error(), ClassifyPChainError(err, "http://127.0.0.1:9650").Error())
+		})
+	}
+	require.NoError(t, ClassifyPChainError(nil, ""))
+}
+
+func TestCheckFeeBalance(t *testing.T) {
+	require := require.New(t)
+	addr := "P-tahoe18jma8ppw3nhx5r4ap8clazz0dps7rv5u6wmu4t"
+	require.NoError(CheckFeeBalance("CreateSubnet transaction", 1_000_000_000, 1_000_000_000, []string{addr}))
+	err := CheckFeeBalance("CreateSubnet transaction", 1_000_000_000, 250_000_000, []string{addr})
+	require.ErrorIs(err, ErrInsufficientFunds)
+	require.ErrorContains(err, "Need 0.750000000 more METAL (750000000 nanoMETAL) on address "+addr)
+	err = CheckFeeBalance("CreateChain transaction", 1_000_000_000, 0, []string{addr, addr})
+	require.ErrorContains(err, "on any of the addresses")
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/MetalBlockchain/metalgo/vms/platformvm/signer"
@@ -13,9 +14,12 @@ import (
 	"github.com/MetalBlockchain/metalgo/vms/components/avax"
 	"github.com/MetalBlockchain/metalgo/vms/components/verify"
 
+	"github.com/MetalBlockchain/metal-cli/pkg/apicache"
 	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/audit"
 	"github.com/MetalBlockchain/metal-cli/pkg/keychain"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/sdk"
 	"github.com/MetalBlockchain/metal-cli/pkg/txutils"
 	"github.com/MetalBlockchain/metal-cli/pkg/utils"
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
@@ -24,23 +28,26 @@ import (
 	avagoconstants "github.com/MetalBlockchain/metalgo/utils/constants"
 	"github.com/MetalBlockchain/metalgo/utils/formatting/address"
 	"github.com/MetalBlockchain/metalgo/utils/logging"
-	"github.com/MetalBlockchain/metalgo/utils/set"
+	"github.com/MetalBlockchain/metalgo/utils/rpc"
 	avmtxs "github.com/MetalBlockchain/metalgo/vms/avm/txs"
 	"github.com/MetalBlockchain/metalgo/vms/platformvm"
+	platformapi "github.com/MetalBlockchain/metalgo/vms/platformvm/api"
 	"github.com/MetalBlockchain/metalgo/vms/platformvm/txs"
 	"github.com/MetalBlockchain/metalgo/vms/secp256k1fx"
 	"github.com/MetalBlockchain/metalgo/wallet/subnet/primary"
 	"github.com/MetalBlockchain/metalgo/wallet/subnet/primary/common"
+	ethcommon "github.com/ethereum/go-ethereum/common"
 )
 
 var ErrNoSubnetAuthKeysInWallet = errors.New("auth wallet does not contain subnet auth keys")
 
 type PublicDeployer struct {
 	LocalDeployer
-	kc      *keychain.Keychain
-	network models.Network
-	app     *application.Avalanche
-	wallet  primary.Wallet
+	kc        *keychain.Keychain
+	network   models.Network
+	app       *application.Avalanche
+	wallet    primary.Wallet
+	issuedTxs []models.TxRecord
 }
 
 func NewPublicDeployer(app *application.Avalanche, kc *keychain.Keychain, network models.Network) *PublicDeployer {
@@ -77,6 +84,9 @@ func (d *PublicDeployer) AddValidator(
 	if err != nil {
 		return false, nil, nil, fmt.Errorf("failure parsing subnet auth keys: %w", err)
 	}
+	if err := d.checkFeeBalance(wallet, wallet.P().Builder().Context().AddSubnetValidatorFee, "AddSubnetValidator transaction"); err != nil {
+		return false, nil, nil, err
+	}
 	validator := &txs.SubnetValidator{
 		Validator: txs.Validator{
 			NodeID: nodeID,
@@ -186,7 +196,7 @@ func (d *PublicDeployer) CreateAssetTx(
 		initialState,
 	)
 	if err != nil {
-		return ids.Empty, fmt.Errorf("error building tx: %w", err)
+		return ids.Empty, fmt.Errorf("error building tx: %w", ClassifyPChainError(err, ""))
 	}
 	tx := avmtxs.Tx{Unsigned: unsignedTx}
 	if err := wallet.X().Signer().Sign(context.Background(), &tx); err != nil {
@@ -201,12 +211,13 @@ func (d *PublicDeployer) CreateAssetTx(
 	)
 	if err != nil {
 		if ctx.Err() != nil {
-			err = fmt.Errorf("timeout issuing/verifying tx with ID %s: %w", tx.ID(), err)
+			err = fmt.Errorf("timeout issuing/verifying tx with ID %s: %w", tx.ID(), ClassifyPChainError(err, ""))
 		} else {
-			err = fmt.Errorf("error issuing tx with ID %s: %w", tx.ID(), err)
+			err = fmt.Errorf("error issuing tx with ID %s: %w", tx.ID(), ClassifyPChainError(err, ""))
 		}
 		return ids.Empty, err
 	}
+	audit.RecordTx(tx.ID())
 
 	ux.Logger.PrintToUser("Create Asset Transaction successful, transaction ID: %s", tx.ID())
 	ux.Logger.PrintToUser("Now exporting asset to P-Chain ...")
@@ -409,6 +420,9 @@ func (d *PublicDeployer) DeploySubnet(
 	if err != nil {
 		return ids.Empty, err
 	}
+	if err := d.checkFeeBalance(wallet, wallet.P().Builder().Context().CreateSubnetTxFee, "CreateSubnet transaction"); err != nil {
+		return ids.Empty, err
+	}
 	subnetID, err := d.createSubnetTx(controlKeys, threshold, wallet)
 	if err != nil {
 		return ids.Empty, err
@@ -439,6 +453,10 @@ func (d *PublicDeployer) DeployBlockchain(
 		return false, ids.Empty, nil, nil, err
 	}
 
+	if err := d.checkFeeBalance(wallet, wallet.P().Builder().Context().CreateBlockchainTxFee, "CreateChain transaction"); err != nil {
+		return false, ids.Empty, nil, nil, err
+	}
+
 	vmID, err := anrutils.VMID(chain)
 	if err != nil {
 		return false, ids.Empty, nil, nil, fmt.Errorf("failed to create VM ID from %s: %w", chain, err)
@@ -477,40 +495,63 @@ func (d *PublicDeployer) Commit(
 	tx *txs.Tx,
 	justIssueTx bool,
 ) (ids.ID, error) {
-	const (
-		repeats             = 3
-		sleepBetweenRepeats = 1 * time.Second
-	)
-	var issueTxErr error
 	wallet, err := d.loadCacheWallet()
 	if err != nil {
 		return ids.Empty, err
 	}
-	for i := 0; i < repeats; i++ {
-		ctx, cancel := utils.GetAPILargeContext()
-		defer cancel()
-		options := []common.Option{common.WithContext(ctx)}
-		if justIssueTx {
-			options = append(options, common.WithAssumeDecided())
-		}
-		issueTxErr = wallet.P().IssueTx(tx, options...)
-		if issueTxErr == nil {
-			break
-		}
-		if ctx.Err() != nil {
-			issueTxErr = fmt.Errorf("timeout issuing/verifying tx with ID %s: %w", tx.ID(), issueTxErr)
-		} else {
-			issueTxErr = fmt.Errorf("error issuing tx with ID %s: %w", tx.ID(), issueTxErr)
-		}
-		ux.Logger.RedXToUser("%s", issueTxErr)
-		time.Sleep(sleepBetweenRepeats)
-	}
+	_, issueTxErr := utils.RetryWithExponentialBackoff(
+		func() (any, error) {
+			ctx, cancel := utils.GetAPILargeContext()
+			defer cancel()
+			options := []common.Option{common.WithContext(ctx)}
+			if justIssueTx {
+				options = append(options, common.WithAssumeDecided())
+			}
+			err := wallet.P().IssueTx(tx, options...)
+			if err == nil {
+				return nil, nil
+			}
+			err = ClassifyPChainError(err, d.network.Endpoint)
+			if ctx.Err() != nil {
+				err = fmt.Errorf("timeout issuing/verifying tx with ID %s: %w", tx.ID(), err)
+			} else {
+				err = fmt.Errorf("error issuing tx with ID %s: %w", tx.ID(), err)
+			}
+			ux.Logger.RedXToUser("%s", err)
+			return nil, err
+		},
+		pChainCallAttempts,
+		pChainCallInitialBackoff,
+		IsRetryablePChainError,
+	)
 	if issueTxErr != nil {
 		d.cleanCacheWallet()
+	} else {
+		d.recordIssuedTx(tx)
+		apicache.Purge()
 	}
 	return tx.ID(), issueTxErr
 }
 
+// IssuedTxs returns the records of all txs successfully issued by this deployer
+func (d *PublicDeployer) IssuedTxs() []models.TxRecord {
+	return d.issuedTxs
+}
+
+func (d *PublicDeployer) recordIssuedTx(tx *txs.Tx) {
+	audit.RecordTx(tx.ID())
+	issuer := ""
+	if addrs, err := d.kc.PChainFormattedStrAddresses(); err == nil && len(addrs) > 0 {
+		issuer = addrs[0]
+	}
+	d.issuedTxs = append(d.issuedTxs, models.TxRecord{
+		TxID:      tx.ID(),
+		TxType:    txutils.GetTxTypeName(tx),
+		Timestamp: time.Now().UTC(),
+		Issuer:    issuer,
+	})
+}
+
 func (d *PublicDeployer) Sign(
 	tx *txs.Tx,
 	subnetAuthKeysStrs []string,
@@ -543,22 +584,9 @@ func (d *PublicDeployer) Sign(
 }
 
 func (d *PublicDeployer) loadWallet(preloadTxs ...ids.ID) (primary.Wallet, error) {
-	ctx := context.Background()
-	// filter out ids.Empty txs
-	filteredTxs := utils.Filter(preloadTxs, func(e ids.ID) bool { return e != ids.Empty })
-	wallet, err := primary.MakeWallet(
-		ctx,
-		&primary.WalletConfig{
-			URI:              d.network.Endpoint,
-			AVAXKeychain:     d.kc.Keychain,
-			EthKeychain:      secp256k1fx.NewKeychain(),
-			PChainTxsToFetch: set.Of(filteredTxs...),
-		},
-	)
-	if err != nil {
-		return nil, err
-	}
-	return wallet, nil
+	return retryPChainCall(d.network.Endpoint, func() (primary.Wallet, error) {
+		return sdk.NewWallet(context.Background(), d.network.Endpoint, d.kc.Keychain, preloadTxs...)
+	})
 }
 
 func (d *PublicDeployer) cleanCacheWallet() {
@@ -574,21 +602,7 @@ func (d *PublicDeployer) loadCacheWallet(preloadTxs ...ids.ID) (primary.Wallet,
 }
 
 func (d *PublicDeployer) getMultisigTxOptions(subnetAuthKeys []ids.ShortID) []common.Option {
-	options := []common.Option{}
-	walletAddrs := d.kc.Addresses().List()
-	changeAddr := walletAddrs[0]
-	// addrs to use for signing
-	customAddrsSet := set.Set[ids.ShortID]{}
-	customAddrsSet.Add(walletAddrs...)
-	customAddrsSet.Add(subnetAuthKeys...)
-	options = append(options, common.WithCustomAddresses(customAddrsSet))
-	// set change to go to wallet addr (instead of any other subnet auth key)
-	changeOwner := &secp256k1fx.OutputOwners{
-		Threshold: 1,
-		Addrs:     []ids.ShortID{changeAddr},
-	}
-	options = append(options, common.WithChangeOwner(changeOwner))
-	return options
+	return sdk.GetMultisigTxOptions(d.kc.Addresses().List(), subnetAuthKeys)
 }
 
 func (d *PublicDeployer) createBlockchainTx(
@@ -599,26 +613,9 @@ func (d *PublicDeployer) createBlockchainTx(
 	genesis []byte,
 	wallet primary.Wallet,
 ) (*txs.Tx, error) {
-	fxIDs := make([]ids.ID, 0)
 	options := d.getMultisigTxOptions(subnetAuthKeys)
-	// create tx
-	unsignedTx, err := wallet.P().Builder().NewCreateChainTx(
-		subnetID,
-		genesis,
-		vmID,
-		fxIDs,
-		chainName,
-		options...,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("error building tx: %w", err)
-	}
-	tx := txs.Tx{Unsigned: unsignedTx}
-	// sign with current wallet
-	if err := wallet.P().Signer().Sign(context.Background(), &tx); err != nil {
-		return nil, fmt.Errorf("error signing tx: %w", err)
-	}
-	return &tx, nil
+	tx, err := sdk.NewCreateChainTx(context.Background(), wallet, subnetID, chainName, vmID, genesis, options...)
+	return tx, ClassifyPChainError(err, "")
 }
 
 func (d *PublicDeployer) createTransferSubnetOwnershipTx(
@@ -645,7 +642,7 @@ func (d *PublicDeployer) createTransferSubnetOwnershipTx(
 		options...,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("error building tx: %w", err)
+		return nil, fmt.Errorf("error building tx: %w", ClassifyPChainError(err, ""))
 	}
 	tx := txs.Tx{Unsigned: unsignedTx}
 	// sign with current wallet
@@ -661,17 +658,8 @@ func (d *PublicDeployer) createAddSubnetValidatorTx(
 	wallet primary.Wallet,
 ) (*txs.Tx, error) {
 	options := d.getMultisigTxOptions(subnetAuthKeys)
-	// create tx
-	unsignedTx, err := wallet.P().Builder().NewAddSubnetValidatorTx(validator, options...)
-	if err != nil {
-		return nil, fmt.Errorf("error building tx: %w", err)
-	}
-	tx := txs.Tx{Unsigned: unsignedTx}
-	// sign with current wallet
-	if err := wallet.P().Signer().Sign(context.Background(), &tx); err != nil {
-		return nil, fmt.Errorf("error signing tx: %w", err)
-	}
-	return &tx, nil
+	tx, err := sdk.NewAddSubnetValidatorTx(context.Background(), wallet, validator, options...)
+	return tx, ClassifyPChainError(err, "")
 }
 
 func (d *PublicDeployer) createRemoveValidatorTX(
@@ -681,17 +669,8 @@ func (d *PublicDeployer) createRemoveValidatorTX(
 	wallet primary.Wallet,
 ) (*txs.Tx, error) {
 	options := d.getMultisigTxOptions(subnetAuthKeys)
-	// create tx
-	unsignedTx, err := wallet.P().Builder().NewRemoveSubnetValidatorTx(nodeID, subnetID, options...)
-	if err != nil {
-		return nil, fmt.Errorf("error building tx: %w", err)
-	}
-	tx := txs.Tx{Unsigned: unsignedTx}
-	// sign with current wallet
-	if err := wallet.P().Signer().Sign(context.Background(), &tx); err != nil {
-		return nil, fmt.Errorf("error signing tx: %w", err)
-	}
-	return &tx, nil
+	tx, err := sdk.NewRemoveSubnetValidatorTx(context.Background(), wallet, nodeID, subnetID, options...)
+	return tx, ClassifyPChainError(err, "")
 }
 
 func (d *PublicDeployer) createTransformSubnetTX(
@@ -708,7 +687,7 @@ func (d *PublicDeployer) createTransformSubnetTX(
 		elasticSubnetConfig.MinStakeDuration, elasticSubnetConfig.MaxStakeDuration, elasticSubnetConfig.MinDelegationFee,
 		elasticSubnetConfig.MinDelegatorStake, elasticSubnetConfig.MaxValidatorWeightFactor, elasticSubnetConfig.UptimeRequirement, options...)
 	if err != nil {
-		return nil, fmt.Errorf("error building tx: %w", err)
+		return nil, fmt.Errorf("error building tx: %w", ClassifyPChainError(err, ""))
 	}
 	tx := txs.Tx{Unsigned: unsignedTx}
 	// sign with current wallet
@@ -778,7 +757,7 @@ func (d *PublicDeployer) issueAddPermissionlessValidatorTX(
 		options...,
 	)
 	if err != nil {
-		return ids.Empty, fmt.Errorf("error building tx: %w", err)
+		return ids.Empty, fmt.Errorf("error building tx: %w", ClassifyPChainError(err, ""))
 	}
 	tx := txs.Tx{Unsigned: unsignedTx}
 	if err := wallet.P().Signer().Sign(context.Background(), &tx); err != nil {
@@ -793,12 +772,13 @@ func (d *PublicDeployer) issueAddPermissionlessValidatorTX(
 	)
 	if err != nil {
 		if ctx.Err() != nil {
-			err = fmt.Errorf("timeout issuing/verifying tx with ID %s: %w", tx.ID(), err)
+			err = fmt.Errorf("timeout issuing/verifying tx with ID %s: %w", tx.ID(), ClassifyPChainError(err, ""))
 		} else {
-			err = fmt.Errorf("error issuing tx with ID %s: %w", tx.ID(), err)
+			err = fmt.Errorf("error issuing tx with ID %s: %w", tx.ID(), ClassifyPChainError(err, ""))
 		}
 		return ids.Empty, err
 	}
+	audit.RecordTx(tx.ID())
 
 	return tx.ID(), nil
 }
@@ -839,7 +819,7 @@ func (d *PublicDeployer) issueAddPermissionlessDelegatorTX(
 		options...,
 	)
 	if err != nil {
-		return ids.Empty, fmt.Errorf("error building tx: %w", err)
+		return ids.Empty, fmt.Errorf("error building tx: %w", ClassifyPChainError(err, ""))
 	}
 	tx := txs.Tx{Unsigned: unsignedTx}
 	if err := wallet.P().Signer().Sign(context.Background(), &tx); err != nil {
@@ -854,16 +834,35 @@ func (d *PublicDeployer) issueAddPermissionlessDelegatorTX(
 	)
 	if err != nil {
 		if ctx.Err() != nil {
-			err = fmt.Errorf("timeout issuing/verifying tx with ID %s: %w", tx.ID(), err)
+			err = fmt.Errorf("timeout issuing/verifying tx with ID %s: %w", tx.ID(), ClassifyPChainError(err, ""))
 		} else {
-			err = fmt.Errorf("error issuing tx with ID %s: %w", tx.ID(), err)
+			err = fmt.Errorf("error issuing tx with ID %s: %w", tx.ID(), ClassifyPChainError(err, ""))
 		}
 		return ids.Empty, err
 	}
+	audit.RecordTx(tx.ID())
 
 	return tx.ID(), nil
 }
 
+// checkFeeBalance fails fast, before building the tx, if the wallet keys don't hold
+// enough unlocked P-Chain funds to pay for its [fee]
+func (d *PublicDeployer) checkFeeBalance(wallet primary.Wallet, fee uint64, txName string) error {
+	balances, err := wallet.P().Builder().GetBalance()
+	if err != nil {
+		return fmt.Errorf("failure getting P-Chain balance: %w", err)
+	}
+	balance := balances[wallet.P().Builder().Context().AVAXAssetID]
+	if balance >= fee {
+		return nil
+	}
+	addrs, err := d.kc.PChainFormattedStrAddresses()
+	if err != nil {
+		return err
+	}
+	return CheckFeeBalance(txName, fee, balance, addrs)
+}
+
 func (*PublicDeployer) signTx(
 	tx *txs.Tx,
 	wallet primary.Wallet,
@@ -879,26 +878,15 @@ func (d *PublicDeployer) createSubnetTx(controlKeys []string, threshold uint32,
 	if err != nil {
 		return ids.Empty, fmt.Errorf("failure parsing control keys: %w", err)
 	}
-	owners := &secp256k1fx.OutputOwners{
-		Addrs:     addrs,
-		Threshold: threshold,
-		Locktime:  0,
-	}
 	if d.kc.UsesLedger {
 		showLedgerSignatureMsg(d.kc.UsesLedger, d.kc.HasOnlyOneKey(), "CreateSubnet transaction")
 	}
-	unsignedTx, err := wallet.P().Builder().NewCreateSubnetTx(
-		owners,
-	)
+	tx, err := sdk.NewCreateSubnetTx(context.Background(), wallet, addrs, threshold)
 	if err != nil {
-		return ids.Empty, fmt.Errorf("error building tx: %w", err)
-	}
-	tx := txs.Tx{Unsigned: unsignedTx}
-	if err := wallet.P().Signer().Sign(context.Background(), &tx); err != nil {
-		return ids.Empty, fmt.Errorf("error signing tx: %w", err)
+		return ids.Empty, ClassifyPChainError(err, "")
 	}
 
-	return d.Commit(&tx, false)
+	return d.Commit(tx, false)
 }
 
 func (d *PublicDeployer) getSubnetAuthAddressesInWallet(subnetAuth []ids.ShortID) []ids.ShortID {
@@ -921,28 +909,68 @@ func (d *PublicDeployer) checkWalletHasSubnetAuthAddresses(subnetAuth []ids.Shor
 }
 
 func IsSubnetValidator(subnetID ids.ID, nodeID ids.NodeID, network models.Network) (bool, error) {
-	pClient := platformvm.NewClient(network.Endpoint)
-	ctx, cancel := utils.GetAPIContext()
-	defer cancel()
-
-	vals, err := pClient.GetCurrentValidators(ctx, subnetID, []ids.NodeID{nodeID})
+	vals, err := getCurrentValidators(network, subnetID, []ids.NodeID{nodeID})
 	if err != nil {
-		return false, fmt.Errorf("failed to get current validators")
+		return false, err
 	}
 
 	return !(len(vals) == 0), nil
 }
 
 func GetPublicSubnetValidators(subnetID ids.ID, network models.Network) ([]platformvm.ClientPermissionlessValidator, error) {
-	pClient := platformvm.NewClient(network.Endpoint)
+	return getCurrentValidators(network, subnetID, []ids.NodeID{})
+}
+
+// GetPendingValidators returns the validators of [subnetID] on [network] that are
+// yet to start validating. Since Durango validators start as soon as they are
+// added, so nodes no longer serve pending validators, and none is returned
+func GetPendingValidators(subnetID ids.ID, network models.Network) ([]platformvm.ClientPermissionlessValidator, error) {
 	ctx, cancel := utils.GetAPIContext()
 	defer cancel()
-
-	vals, err := pClient.GetCurrentValidators(ctx, subnetID, []ids.NodeID{})
+	reply := struct {
+		Validators []platformapi.Staker `json:"validators"`
+	}{}
+	err := rpc.NewEndpointRequester(network.Endpoint+"/ext/P").SendRequest(
+		ctx,
+		"platform.getPendingValidators",
+		&platformvm.GetCurrentValidatorsArgs{SubnetID: subnetID},
+		&reply,
+	)
+	if err != nil && strings.Contains(err.Error(), "can't find method") {
+		return nil, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get current validators")
+		return nil, fmt.Errorf("failed to get pending validators: %w", err)
+	}
+	validators := make([]platformvm.ClientPermissionlessValidator, len(reply.Validators))
+	for i, staker := range reply.Validators {
+		validators[i] = platformvm.ClientPermissionlessValidator{
+			ClientStaker: platformvm.ClientStaker{
+				TxID:      staker.TxID,
+				StartTime: uint64(staker.StartTime),
+				EndTime:   uint64(staker.EndTime),
+				Weight:    uint64(staker.Weight),
+				NodeID:    staker.NodeID,
+			},
+		}
 	}
+	return validators, nil
+}
 
+func getCurrentValidators(
+	network models.Network,
+	subnetID ids.ID,
+	nodeIDs []ids.NodeID,
+) ([]platformvm.ClientPermissionlessValidator, error) {
+	pClient := platformvm.NewClient(network.Endpoint)
+	vals, err := retryPChainCall(network.Endpoint, func() ([]platformvm.ClientPermissionlessValidator, error) {
+		ctx, cancel := utils.GetAPIContext()
+		defer cancel()
+		return pClient.GetCurrentValidators(ctx, subnetID, nodeIDs)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current validators: %w", err)
+	}
 	return vals, nil
 }
 
@@ -970,7 +998,7 @@ func IssueXToPExportTx(
 		},
 	)
 	if err != nil {
-		return ids.Empty, fmt.Errorf("error building tx: %w", err)
+		return ids.Empty, fmt.Errorf("error building tx: %w", ClassifyPChainError(err, ""))
 	}
 	tx := avmtxs.Tx{Unsigned: unsignedTx}
 	if err := wallet.X().Signer().Sign(context.Background(), &tx); err != nil {
@@ -984,12 +1012,13 @@ func IssueXToPExportTx(
 	)
 	if err != nil {
 		if ctx.Err() != nil {
-			err = fmt.Errorf("timeout issuing/verifying tx with ID %s: %w", tx.ID(), err)
+			err = fmt.Errorf("timeout issuing/verifying tx with ID %s: %w", tx.ID(), ClassifyPChainError(err, ""))
 		} else {
-			err = fmt.Errorf("error issuing tx with ID %s: %w", tx.ID(), err)
+			err = fmt.Errorf("error issuing tx with ID %s: %w", tx.ID(), ClassifyPChainError(err, ""))
 		}
 		return tx.ID(), err
 	}
+	audit.RecordTx(tx.ID())
 	return tx.ID(), nil
 }
 
@@ -1005,7 +1034,7 @@ func IssuePFromXImportTx(
 		owner,
 	)
 	if err != nil {
-		return ids.Empty, fmt.Errorf("error building tx: %w", err)
+		return ids.Empty, fmt.Errorf("error building tx: %w", ClassifyPChainError(err, ""))
 	}
 	tx := txs.Tx{Unsigned: unsignedTx}
 	if err := wallet.P().Signer().Sign(context.Background(), &tx); err != nil {
@@ -1019,15 +1048,135 @@ func IssuePFromXImportTx(
 	)
 	if err != nil {
 		if ctx.Err() != nil {
-			err = fmt.Errorf("timeout issuing/verifying tx with ID %s: %w", tx.ID(), err)
+			err = fmt.Errorf("timeout issuing/verifying tx with ID %s: %w", tx.ID(), ClassifyPChainError(err, ""))
 		} else {
-			err = fmt.Errorf("error issuing tx with ID %s: %w", tx.ID(), err)
+			err = fmt.Errorf("error issuing tx with ID %s: %w", tx.ID(), ClassifyPChainError(err, ""))
 		}
 		return tx.ID(), err
 	}
+	audit.RecordTx(tx.ID())
 	return tx.ID(), err
 }
 
+// IssueCToPExportTx exports [amount] from the C-Chain to [owner] on the P-Chain
+func IssueCToPExportTx(
+	wallet primary.Wallet,
+	amount uint64,
+	owner *secp256k1fx.OutputOwners,
+) (ids.ID, error) {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	tx, err := wallet.C().IssueExportTx(
+		avagoconstants.PlatformChainID,
+		[]*secp256k1fx.TransferOutput{
+			{
+				Amt:          amount,
+				OutputOwners: *owner,
+			},
+		},
+		common.WithContext(ctx),
+	)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ids.Empty, fmt.Errorf("timeout issuing/verifying C -> P export tx: %w", err)
+		}
+		return ids.Empty, fmt.Errorf("error issuing C -> P export tx: %w", err)
+	}
+	return tx.ID(), nil
+}
+
+// IssuePFromCImportTx imports to [owner] all the funds exported from the C-Chain
+// [cChainID] to the P-Chain
+func IssuePFromCImportTx(
+	wallet primary.Wallet,
+	cChainID ids.ID,
+	owner *secp256k1fx.OutputOwners,
+) (ids.ID, error) {
+	unsignedTx, err := wallet.P().Builder().NewImportTx(
+		cChainID,
+		owner,
+	)
+	if err != nil {
+		return ids.Empty, fmt.Errorf("error building tx: %w", ClassifyPChainError(err, ""))
+	}
+	tx := txs.Tx{Unsigned: unsignedTx}
+	if err := wallet.P().Signer().Sign(context.Background(), &tx); err != nil {
+		return ids.Empty, fmt.Errorf("error signing tx: %w", err)
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	if err := wallet.P().IssueTx(&tx, common.WithContext(ctx)); err != nil {
+		if ctx.Err() != nil {
+			return tx.ID(), fmt.Errorf("timeout issuing/verifying tx with ID %s: %w", tx.ID(), ClassifyPChainError(err, ""))
+		}
+		return tx.ID(), fmt.Errorf("error issuing tx with ID %s: %w", tx.ID(), ClassifyPChainError(err, ""))
+	}
+	audit.RecordTx(tx.ID())
+	return tx.ID(), nil
+}
+
+// IssuePToCExportTx exports [amount] from the P-Chain to [owner] on the C-Chain [cChainID]
+func IssuePToCExportTx(
+	wallet primary.Wallet,
+	cChainID ids.ID,
+	amount uint64,
+	owner *secp256k1fx.OutputOwners,
+) (ids.ID, error) {
+	unsignedTx, err := wallet.P().Builder().NewExportTx(
+		cChainID,
+		[]*avax.TransferableOutput{
+			{
+				Asset: avax.Asset{
+					ID: wallet.P().Builder().Context().AVAXAssetID,
+				},
+				Out: &secp256k1fx.TransferOutput{
+					Amt:          amount,
+					OutputOwners: *owner,
+				},
+			},
+		},
+	)
+	if err != nil {
+		return ids.Empty, fmt.Errorf("error building tx: %w", ClassifyPChainError(err, ""))
+	}
+	tx := txs.Tx{Unsigned: unsignedTx}
+	if err := wallet.P().Signer().Sign(context.Background(), &tx); err != nil {
+		return ids.Empty, fmt.Errorf("error signing tx: %w", err)
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	if err := wallet.P().IssueTx(&tx, common.WithContext(ctx)); err != nil {
+		if ctx.Err() != nil {
+			return tx.ID(), fmt.Errorf("timeout issuing/verifying tx with ID %s: %w", tx.ID(), ClassifyPChainError(err, ""))
+		}
+		return tx.ID(), fmt.Errorf("error issuing tx with ID %s: %w", tx.ID(), ClassifyPChainError(err, ""))
+	}
+	audit.RecordTx(tx.ID())
+	return tx.ID(), nil
+}
+
+// IssueCFromPImportTx imports to [to] all the funds exported from the P-Chain
+// to the C-Chain
+func IssueCFromPImportTx(
+	wallet primary.Wallet,
+	to ethcommon.Address,
+) (ids.ID, error) {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	tx, err := wallet.C().IssueImportTx(
+		avagoconstants.PlatformChainID,
+		to,
+		common.WithContext(ctx),
+	)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ids.Empty, fmt.Errorf("timeout issuing/verifying P -> C import tx: %w", err)
+		}
+		return ids.Empty, fmt.Errorf("error issuing P -> C import tx: %w", err)
+	}
+	return tx.ID(), nil
+}
+
 func showLedgerSignatureMsg(
 	usingLedger bool,
 	hasOnlyOneKey bool,
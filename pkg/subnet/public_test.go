@@ -0,0 +1,55 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPendingValidators(t *testing.T) {
+	require := require.New(t)
+	nodeID := ids.GenerateTestNodeID()
+	methodFound := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal("/ext/P", r.URL.Path)
+		var req struct {
+			Method string `json:"method"`
+		}
+		require.NoError(json.NewDecoder(r.Body).Decode(&req))
+		require.Equal("platform.getPendingValidators", req.Method)
+		response := map[string]interface{}{"jsonrpc": "2.0", "id": 1}
+		if methodFound {
+			response["result"] = map[string]interface{}{
+				"validators": []map[string]interface{}{
+					{"nodeID": nodeID, "startTime": "2000", "endTime": "3000", "weight": "20"},
+				},
+			}
+		} else {
+			response["error"] = map[string]interface{}{"code": -32000, "message": `rpc: can't find method "platform.getPendingValidators"`}
+		}
+		require.NoError(json.NewEncoder(w).Encode(response))
+	}))
+	defer server.Close()
+	network := models.NewDevnetNetwork(server.URL, 4242)
+
+	validators, err := GetPendingValidators(ids.GenerateTestID(), network)
+	require.NoError(err)
+	require.Len(validators, 1)
+	require.Equal(nodeID, validators[0].NodeID)
+	require.Equal(uint64(2000), validators[0].StartTime)
+	require.Equal(uint64(3000), validators[0].EndTime)
+	require.Equal(uint64(20), validators[0].Weight)
+
+	// nodes since Durango have no pending validators
+	methodFound = false
+	validators, err = GetPendingValidators(ids.GenerateTestID(), network)
+	require.NoError(err)
+	require.Empty(validators)
+}
@@ -0,0 +1,292 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/snapshot"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metal-network-runner/client"
+	"github.com/MetalBlockchain/metal-network-runner/rpcpb"
+	"github.com/MetalBlockchain/metalgo/api/health"
+	"github.com/MetalBlockchain/metalgo/api/info"
+	"github.com/MetalBlockchain/metalgo/config"
+	"github.com/MetalBlockchain/metalgo/node"
+)
+
+// primary network chains a node must have bootstrapped
+var primaryNetworkChains = []string{"P", "X", "C"}
+
+// StartupProgress is the state of a local network being started, as the
+// number of nodes that reached each startup phase
+type StartupProgress struct {
+	Total        int
+	Started      int
+	Bootstrapped int
+	Healthy      int
+}
+
+func (p StartupProgress) String() string {
+	switch {
+	case p.Total == 0:
+		return "Booting network"
+	case p.Started < p.Total:
+		return fmt.Sprintf("Launching nodes (%d/%d started)", p.Started, p.Total)
+	case p.Bootstrapped < p.Total:
+		return fmt.Sprintf("Bootstrapping nodes (%d/%d bootstrapped)", p.Bootstrapped, p.Total)
+	default:
+		return fmt.Sprintf("Waiting for nodes to be healthy (%d/%d healthy)", p.Healthy, p.Total)
+	}
+}
+
+// SnapshotNumNodes returns the number of nodes of snapshot [snapshotName] under [snapshotsDir],
+// or 0 if it can't be determined
+func SnapshotNumNodes(snapshotsDir string, snapshotName string) int {
+	networkConfigBytes, err := os.ReadFile(filepath.Join(snapshot.Dir(snapshotsDir, snapshotName), snapshotNetworkConfigFileName))
+	if err != nil {
+		return 0
+	}
+	var networkConfig struct {
+		NodeConfigs []json.RawMessage `json:"nodeConfigs"`
+	}
+	if err := json.Unmarshal(networkConfigBytes, &networkConfig); err != nil {
+		return 0
+	}
+	return len(networkConfig.NodeConfigs)
+}
+
+// GetStartupProgress checks, in parallel, the nodes of the network being started
+// under [rootDataDir]. A node is started once it has written its process context file
+// and serves its API, which is then used to query its bootstrap and health status
+func GetStartupProgress(ctx context.Context, rootDataDir string, total int) StartupProgress {
+	progress := StartupProgress{Total: total}
+	// the network runner creates the network dir inside [rootDataDir], and a dir
+	// for each node inside it
+	processContextPaths, _ := filepath.Glob(filepath.Join(rootDataDir, "*", "*", config.DefaultProcessContextFilename))
+	var (
+		wg    sync.WaitGroup
+		mutex sync.Mutex
+	)
+	for _, processContextPath := range processContextPaths {
+		processContextBytes, err := os.ReadFile(processContextPath)
+		if err != nil {
+			continue
+		}
+		var processContext node.NodeProcessContext
+		if err := json.Unmarshal(processContextBytes, &processContext); err != nil || processContext.URI == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(uri string) {
+			defer wg.Done()
+			bootstrapped, healthy := getNodeStartupStatus(ctx, uri)
+			mutex.Lock()
+			defer mutex.Unlock()
+			progress.Started++
+			if bootstrapped {
+				progress.Bootstrapped++
+			}
+			if healthy {
+				progress.Healthy++
+			}
+		}(processContext.URI)
+	}
+	wg.Wait()
+	if progress.Total < progress.Started {
+		progress.Total = progress.Started
+	}
+	return progress
+}
+
+func getNodeStartupStatus(ctx context.Context, uri string) (bool, bool) {
+	infoClient := info.NewClient(uri)
+	for _, chain := range primaryNetworkChains {
+		bootstrapped, err := infoClient.IsBootstrapped(ctx, chain)
+		if err != nil || !bootstrapped {
+			return false, false
+		}
+	}
+	resp, err := health.NewClient(uri).Health(ctx, nil)
+	return true, err == nil && resp.Healthy
+}
+
+// WatchNetworkStartup reports to [report] the startup progress of the network
+// under [rootDataDir] every [interval], until [ctx] is done
+func WatchNetworkStartup(
+	ctx context.Context,
+	rootDataDir string,
+	total int,
+	interval time.Duration,
+	report func(StartupProgress),
+) {
+	pollNetworkStartup(ctx, rootDataDir, total, interval, func(progress StartupProgress) bool {
+		report(progress)
+		return false
+	})
+}
+
+// WaitForNodesHealthy polls the nodes of the network under [rootDataDir] every [interval],
+// reporting their progress to [report], until all the [total] ones are healthy
+func WaitForNodesHealthy(
+	ctx context.Context,
+	rootDataDir string,
+	total int,
+	interval time.Duration,
+	report func(StartupProgress),
+) error {
+	var last StartupProgress
+	healthy := pollNetworkStartup(ctx, rootDataDir, total, interval, func(progress StartupProgress) bool {
+		last = progress
+		report(progress)
+		return progress.Healthy == progress.Total
+	})
+	if !healthy {
+		return fmt.Errorf("%d/%d nodes healthy: %w", last.Healthy, total, ctx.Err())
+	}
+	return nil
+}
+
+// pollNetworkStartup gets the startup progress of the network under [rootDataDir] every
+// [interval], until [done] is true for it, returning true, or [ctx] is done
+func pollNetworkStartup(
+	ctx context.Context,
+	rootDataDir string,
+	total int,
+	interval time.Duration,
+	done func(StartupProgress) bool,
+) bool {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		pollCtx, cancel := context.WithTimeout(ctx, interval)
+		progress := GetStartupProgress(pollCtx, rootDataDir, total)
+		cancel()
+		if ctx.Err() != nil {
+			return false
+		}
+		if done(progress) {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// startupPhaseTimes records how long the network took to reach each startup phase
+type startupPhaseTimes struct {
+	start        time.Time
+	started      time.Duration
+	bootstrapped time.Duration
+}
+
+func (t *startupPhaseTimes) update(progress StartupProgress) {
+	if progress.Total == 0 {
+		return
+	}
+	elapsed := time.Since(t.start)
+	if t.started == 0 && progress.Started == progress.Total {
+		t.started = elapsed
+	}
+	if t.bootstrapped == 0 && progress.Bootstrapped == progress.Total {
+		t.bootstrapped = elapsed
+	}
+}
+
+func (t *startupPhaseTimes) String() string {
+	msg := fmt.Sprintf("Network healthy after %s", time.Since(t.start).Round(time.Second))
+	if t.started != 0 && t.bootstrapped != 0 {
+		msg += fmt.Sprintf(" (nodes launched after %s, bootstrapped after %s)", t.started.Round(time.Second), t.bootstrapped.Round(time.Second))
+	}
+	return msg
+}
+
+// LoadSnapshotWithProgress loads snapshot [snapshotName] into a network with data dir [rootDataDir],
+// showing the startup phases the nodes go through until the network is healthy
+func LoadSnapshotWithProgress(
+	ctx context.Context,
+	cli client.Client,
+	snapshotsDir string,
+	snapshotName string,
+	rootDataDir string,
+	opts ...client.OpOption,
+) (*rpcpb.LoadSnapshotResponse, error) {
+	phaseTimes := &startupPhaseTimes{start: time.Now()}
+	spinSession := ux.NewUserSpinner()
+	spinner := spinSession.SpinToUser(StartupProgress{}.String())
+	watchCtx, cancelWatch := context.WithCancel(ctx)
+	watchDone := make(chan struct{})
+	go func() {
+		defer close(watchDone)
+		WatchNetworkStartup(
+			watchCtx,
+			rootDataDir,
+			SnapshotNumNodes(snapshotsDir, snapshotName),
+			constants.LocalNetworkStartupPollInterval,
+			func(progress StartupProgress) {
+				phaseTimes.update(progress)
+				spinner.UpdateMessage(progress.String())
+			},
+		)
+	}()
+	resp, err := cli.LoadSnapshot(ctx, snapshotName, opts...)
+	cancelWatch()
+	<-watchDone
+	if err != nil {
+		ux.SpinFailWithError(spinner, "", err)
+		spinSession.Stop()
+		return nil, err
+	}
+	spinner.UpdateMessage(phaseTimes.String())
+	ux.SpinComplete(spinner)
+	spinSession.Stop()
+	return resp, nil
+}
+
+// WaitForAddedNodesWithProgress waits for the nodes added to the running network with
+// [clusterInfo] to be healthy, showing their startup phases, and returns the updated
+// cluster info. The network runner does not wait for added nodes, so they are polled
+// by the CLI, all of them at once
+func WaitForAddedNodesWithProgress(
+	ctx context.Context,
+	cli client.Client,
+	clusterInfo *rpcpb.ClusterInfo,
+) (*rpcpb.ClusterInfo, error) {
+	phaseTimes := &startupPhaseTimes{start: time.Now()}
+	spinSession := ux.NewUserSpinner()
+	spinner := spinSession.SpinToUser(StartupProgress{}.String())
+	// the node dirs are looked for inside the network dirs of a root dir
+	err := WaitForNodesHealthy(
+		ctx,
+		filepath.Dir(clusterInfo.RootDataDir),
+		len(clusterInfo.NodeNames),
+		constants.LocalNetworkStartupPollInterval,
+		func(progress StartupProgress) {
+			phaseTimes.update(progress)
+			spinner.UpdateMessage(progress.String())
+		},
+	)
+	if err != nil {
+		ux.SpinFailWithError(spinner, "", err)
+		spinSession.Stop()
+		return nil, err
+	}
+	spinner.UpdateMessage(phaseTimes.String())
+	ux.SpinComplete(spinner)
+	spinSession.Stop()
+	resp, err := cli.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.ClusterInfo, nil
+}
@@ -0,0 +1,173 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/snapshot"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+)
+
+// newTestNodeServer serves the info and health APIs of a node with the given status
+func newTestNodeServer(t *testing.T, bootstrapped bool, healthy bool) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		var result interface{}
+		switch r.URL.Path {
+		case "/ext/info":
+			result = map[string]bool{"isBootstrapped": bootstrapped}
+		case "/ext/health":
+			result = map[string]interface{}{"checks": map[string]interface{}{}, "healthy": healthy}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func writeTestProcessContext(t *testing.T, nodeDir string, uri string) {
+	require := setupTest(t)
+	require.NoError(os.MkdirAll(nodeDir, constants.DefaultPerms755))
+	processContextBytes, err := json.Marshal(map[string]interface{}{"pid": 1, "uri": uri})
+	require.NoError(err)
+	require.NoError(os.WriteFile(filepath.Join(nodeDir, "process.json"), processContextBytes, constants.WriteReadReadPerms))
+}
+
+func TestGetStartupProgress(t *testing.T) {
+	require := setupTest(t)
+	rootDataDir := t.TempDir()
+	networkDir := filepath.Join(rootDataDir, "network_20240101_000000")
+
+	progress := GetStartupProgress(context.Background(), rootDataDir, 3)
+	require.Equal(StartupProgress{Total: 3}, progress)
+	require.Equal("Launching nodes (0/3 started)", progress.String())
+
+	writeTestProcessContext(t, filepath.Join(networkDir, "node1"), newTestNodeServer(t, true, true).URL)
+	writeTestProcessContext(t, filepath.Join(networkDir, "node2"), newTestNodeServer(t, true, false).URL)
+	writeTestProcessContext(t, filepath.Join(networkDir, "node3"), newTestNodeServer(t, false, false).URL)
+	// started, but not serving its API yet
+	require.NoError(os.MkdirAll(filepath.Join(networkDir, "node4"), constants.DefaultPerms755))
+
+	progress = GetStartupProgress(context.Background(), rootDataDir, 4)
+	require.Equal(StartupProgress{Total: 4, Started: 3, Bootstrapped: 2, Healthy: 1}, progress)
+	require.Equal("Launching nodes (3/4 started)", progress.String())
+
+	progress = GetStartupProgress(context.Background(), rootDataDir, 3)
+	require.Equal(StartupProgress{Total: 3, Started: 3, Bootstrapped: 2, Healthy: 1}, progress)
+	require.Equal("Bootstrapping nodes (2/3 bootstrapped)", progress.String())
+
+	// unknown number of nodes
+	progress = GetStartupProgress(context.Background(), rootDataDir, 0)
+	require.Equal(3, progress.Total)
+}
+
+func TestWatchNetworkStartup(t *testing.T) {
+	require := setupTest(t)
+	rootDataDir := t.TempDir()
+	writeTestProcessContext(t, filepath.Join(rootDataDir, "network_20240101_000000", "node1"), newTestNodeServer(t, true, true).URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reports := []StartupProgress{}
+	WatchNetworkStartup(ctx, rootDataDir, 1, 10*time.Millisecond, func(progress StartupProgress) {
+		reports = append(reports, progress)
+		if len(reports) == 2 {
+			cancel()
+		}
+	})
+	require.Len(reports, 2)
+	require.Equal(StartupProgress{Total: 1, Started: 1, Bootstrapped: 1, Healthy: 1}, reports[1])
+	require.Equal("Waiting for nodes to be healthy (1/1 healthy)", reports[1].String())
+}
+
+func TestSnapshotNumNodes(t *testing.T) {
+	require := setupTest(t)
+	snapshotsDir := t.TempDir()
+	require.Zero(SnapshotNumNodes(snapshotsDir, "missing"))
+
+	snapshotDir := snapshot.Dir(snapshotsDir, "test")
+	require.NoError(os.MkdirAll(snapshotDir, constants.DefaultPerms755))
+	networkConfig := `{"nodeConfigs": [{"name": "node1"}, {"name": "node2"}], "binaryPath": "metalgo"}`
+	require.NoError(os.WriteFile(filepath.Join(snapshotDir, snapshotNetworkConfigFileName), []byte(networkConfig), constants.WriteReadReadPerms))
+	require.Equal(2, SnapshotNumNodes(snapshotsDir, "test"))
+}
+
+func TestIsBootstrapSnapshotOutdated(t *testing.T) {
+	require := setupTest(t)
+	archivePath := filepath.Join(t.TempDir(), "bootstrapSnapshot.tar.gz")
+	require.NoError(os.WriteFile(archivePath, []byte("snapshot"), constants.WriteReadReadPerms))
+	const otherSum = "0000000000000000000000000000000000000000000000000000000000000000"
+	queries := 0
+	getExpectedSum := func(sum string) func() (string, error) {
+		return func() (string, error) {
+			queries++
+			return sum, nil
+		}
+	}
+
+	archiveSum, err := utils.GetSHA256FromDisk(archivePath)
+	require.NoError(err)
+
+	outdated, err := isBootstrapSnapshotOutdated(archivePath, getExpectedSum(otherSum))
+	require.NoError(err)
+	require.True(outdated)
+	require.Equal(1, queries)
+
+	outdated, err = isBootstrapSnapshotOutdated(archivePath, getExpectedSum(archiveSum))
+	require.NoError(err)
+	require.False(outdated)
+	require.Equal(2, queries)
+
+	// the published sum is queried on every check, so a new snapshot is never missed
+	outdated, err = isBootstrapSnapshotOutdated(archivePath, getExpectedSum(otherSum))
+	require.NoError(err)
+	require.True(outdated)
+	require.Equal(3, queries)
+
+	// failing to get the published sum doesn't force a download
+	outdated, err = isBootstrapSnapshotOutdated(archivePath, func() (string, error) {
+		return "", fmt.Errorf("no network")
+	})
+	require.NoError(err)
+	require.False(outdated)
+}
+
+func TestWaitForNodesHealthy(t *testing.T) {
+	require := setupTest(t)
+	rootDataDir := t.TempDir()
+	networkDir := filepath.Join(rootDataDir, "network_20240101_000000")
+	writeTestProcessContext(t, filepath.Join(networkDir, "node1"), newTestNodeServer(t, true, true).URL)
+
+	// waits for all the nodes, not only the ones already started
+	reports := 0
+	require.NoError(WaitForNodesHealthy(context.Background(), rootDataDir, 2, 10*time.Millisecond, func(progress StartupProgress) {
+		reports++
+		if reports == 2 {
+			writeTestProcessContext(t, filepath.Join(networkDir, "node2"), newTestNodeServer(t, true, true).URL)
+		}
+	}))
+	require.Equal(3, reports)
+
+	writeTestProcessContext(t, filepath.Join(networkDir, "node3"), newTestNodeServer(t, true, false).URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := WaitForNodesHealthy(ctx, rootDataDir, 3, 10*time.Millisecond, func(StartupProgress) {})
+	require.ErrorIs(err, context.DeadlineExceeded)
+	require.ErrorContains(err, "2/3 nodes healthy")
+}
@@ -0,0 +1,286 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/snapshot"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metal-network-runner/rpcpb"
+	"github.com/MetalBlockchain/metal-network-runner/server"
+	anrutils "github.com/MetalBlockchain/metal-network-runner/utils"
+	"github.com/MetalBlockchain/metalgo/chains"
+	"github.com/MetalBlockchain/metalgo/config"
+	"github.com/MetalBlockchain/metalgo/database"
+	"github.com/MetalBlockchain/metalgo/database/leveldb"
+	"github.com/MetalBlockchain/metalgo/database/prefixdb"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/logging"
+	"github.com/MetalBlockchain/metalgo/version"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
+)
+
+// undeploySnapshotName is the snapshot the local network is saved into while
+// an undeployed blockchain is removed from the node DBs
+const undeploySnapshotName = "undeploy"
+
+// snapshotDBDirName is the dir of a network snapshot where the node DBs are saved
+const snapshotDBDirName = "db"
+
+// clearPrefixWriteSize is the size of the batches that delete the keys of an
+// undeployed blockchain
+const clearPrefixWriteSize = 1 << 20
+
+var ErrLocalNetworkNotRunning = errors.New("local network is not running")
+
+// UndeployFromLocalNetwork removes the blockchain [blockchainID] of [chain] from
+// the running local network, leaving the other deployed blockchains untouched.
+// The network is saved into a snapshot, where:
+//   - the nodes stop tracking [subnetID], unless other blockchains are deployed
+//     on it
+//   - the blockchain data is removed from the node DBs
+//
+// and is then started again from it. The VM plugin is removed, unless other
+// blockchains use the same VM.
+//
+// The blockchain can't be removed from the local P-Chain, so it is recorded as
+// undeployed and hidden from then on
+func (d *LocalDeployer) UndeployFromLocalNetwork(chain string, subnetID ids.ID, blockchainID ids.ID) error {
+	cli, err := d.getClientFunc()
+	if err != nil {
+		return fmt.Errorf("error creating gRPC Client: %w", err)
+	}
+	defer cli.Close()
+
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+
+	status, err := cli.Status(ctx)
+	if err != nil {
+		if server.IsServerError(err, server.ErrNotBootstrapped) {
+			return ErrLocalNetworkNotRunning
+		}
+		return err
+	}
+	clusterInfo := status.GetClusterInfo()
+	if _, err := RemoveUndeployedChains(d.app, clusterInfo); err != nil {
+		return err
+	}
+	if _, ok := clusterInfo.GetCustomChains()[blockchainID.String()]; !ok {
+		return fmt.Errorf("blockchain %s of %s is not deployed on the local network", blockchainID, chain)
+	}
+	chainVMID, err := anrutils.VMID(chain)
+	if err != nil {
+		return fmt.Errorf("failed to create VM ID from %s: %w", chain, err)
+	}
+	subnetInUse, vmInUse := false, false
+	for otherBlockchainID, chainInfo := range clusterInfo.CustomChains {
+		if otherBlockchainID == blockchainID.String() {
+			continue
+		}
+		if chainInfo.SubnetId == subnetID.String() {
+			subnetInUse = true
+		}
+		if chainInfo.VmId == chainVMID.String() {
+			vmInUse = true
+		}
+	}
+
+	trackedSubnets := map[string]string{}
+	for nodeName, nodeInfo := range clusterInfo.NodeInfos {
+		trackedSubnets[nodeName] = nodeInfo.GetWhitelistedSubnets()
+		if !subnetInUse {
+			trackedSubnets[nodeName] = removeTrackedSubnet(trackedSubnets[nodeName], subnetID)
+		}
+	}
+
+	snapshotDir := snapshot.Dir(d.app.GetSnapshotsDir(), undeploySnapshotName)
+	if utils.DirectoryExists(snapshotDir) {
+		if _, err := cli.RemoveSnapshot(ctx, undeploySnapshotName); err != nil {
+			return fmt.Errorf("failed to remove snapshot %s: %w", undeploySnapshotName, err)
+		}
+	}
+	ux.Logger.PrintToUser("Stopping the local network...")
+	if _, err := cli.SaveSnapshot(ctx, undeploySnapshotName); err != nil {
+		return fmt.Errorf("failed to stop the local network: %w", err)
+	}
+	// from here on, the network is only kept at the snapshot
+	if err := removeChainFromSnapshot(snapshotDir, blockchainID, trackedSubnets); err != nil {
+		return fmt.Errorf("failed to remove blockchain %s from snapshot %s: %w", blockchainID, undeploySnapshotName, err)
+	}
+
+	if !vmInUse {
+		if err := d.removeInstalledPlugin(chainVMID); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove plugin binary: %w", err)
+		}
+	}
+
+	if err := UpdateExtraLocalNetworkData(d.app, func(data *ExtraLocalNetworkData) {
+		if !slices.Contains(data.UndeployedBlockchainIDs, blockchainID.String()) {
+			data.UndeployedBlockchainIDs = append(data.UndeployedBlockchainIDs, blockchainID.String())
+		}
+	}); err != nil {
+		return err
+	}
+
+	runDir, err := anrutils.MkDirWithTimestamp(filepath.Join(d.app.GetRunDir(), "network"))
+	if err != nil {
+		return err
+	}
+	// an empty binary path keeps the one saved at the snapshot
+	if err := d.startNetworkFromSnapshot(ctx, cli, "", runDir, undeploySnapshotName); err != nil {
+		return fmt.Errorf("%w. The local network can be started again with 'metal network start --snapshot-name %s'", err, undeploySnapshotName)
+	}
+	if _, err := cli.RemoveSnapshot(ctx, undeploySnapshotName); err != nil {
+		d.app.Log.Warn("failed removing snapshot", zap.String("snapshot-name", undeploySnapshotName), zap.Error(err))
+	}
+	return nil
+}
+
+// removeTrackedSubnet returns the comma separated [trackedSubnets] without
+// [subnetID]
+func removeTrackedSubnet(trackedSubnets string, subnetID ids.ID) string {
+	remaining := []string{}
+	for _, trackedSubnet := range strings.Split(trackedSubnets, ",") {
+		trackedSubnet = strings.TrimSpace(trackedSubnet)
+		if trackedSubnet != "" && trackedSubnet != subnetID.String() {
+			remaining = append(remaining, trackedSubnet)
+		}
+	}
+	return strings.Join(remaining, ",")
+}
+
+// removeChainFromSnapshot removes the blockchain [blockchainID] from the network
+// snapshot at [snapshotDir]: the nodes are set to track the subnets given by
+// [trackedSubnets], by node name, and the blockchain data is deleted from their DBs
+func removeChainFromSnapshot(snapshotDir string, blockchainID ids.ID, trackedSubnets map[string]string) error {
+	networkConfigPath := filepath.Join(snapshotDir, snapshotNetworkConfigFileName)
+	networkConfigBytes, err := os.ReadFile(networkConfigPath)
+	if err != nil {
+		return err
+	}
+	// generic maps are used so that the snapshot fields the CLI doesn't know about are kept
+	var networkConfig map[string]interface{}
+	if err := json.Unmarshal(networkConfigBytes, &networkConfig); err != nil {
+		return fmt.Errorf("failed parsing snapshot network config %s: %w", networkConfigPath, err)
+	}
+	nodeConfigs, _ := networkConfig["nodeConfigs"].([]interface{})
+	nodeNames := []string{}
+	for _, nodeConfigIntf := range nodeConfigs {
+		nodeConfig, ok := nodeConfigIntf.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("unexpected node config format at %s", networkConfigPath)
+		}
+		nodeName, _ := nodeConfig["name"].(string)
+		nodeNames = append(nodeNames, nodeName)
+		nodeTrackedSubnets, ok := trackedSubnets[nodeName]
+		if !ok {
+			continue
+		}
+		flags, ok := nodeConfig["flags"].(map[string]interface{})
+		if !ok {
+			flags = map[string]interface{}{}
+			nodeConfig["flags"] = flags
+		}
+		if nodeTrackedSubnets == "" {
+			delete(flags, config.TrackSubnetsKey)
+		} else {
+			flags[config.TrackSubnetsKey] = nodeTrackedSubnets
+		}
+	}
+	networkConfigBytes, err = json.MarshalIndent(networkConfig, "", "    ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(networkConfigPath, networkConfigBytes, constants.WriteReadReadPerms); err != nil {
+		return err
+	}
+	for _, nodeName := range nodeNames {
+		// the node DBs are saved at db/<node name>/<network name>/<db version>
+		nodeDBDir := filepath.Join(snapshotDir, snapshotDBDirName, nodeName)
+		entries, err := os.ReadDir(nodeDBDir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			dbPath := filepath.Join(nodeDBDir, entry.Name(), version.CurrentDatabase.String())
+			if !entry.IsDir() || !utils.DirectoryExists(dbPath) {
+				continue
+			}
+			if err := removeChainFromDB(dbPath, blockchainID); err != nil {
+				return fmt.Errorf("failed removing blockchain data from node %s db: %w", nodeName, err)
+			}
+		}
+	}
+	return nil
+}
+
+// removeChainFromDB deletes from the node leveldb at [dbPath] all the keys of the
+// blockchain [blockchainID]
+func removeChainFromDB(dbPath string, blockchainID ids.ID) error {
+	db, err := leveldb.New(dbPath, nil, logging.NoLog{}, "", prometheus.NewRegistry())
+	if err != nil {
+		return err
+	}
+	for _, prefix := range chainDBPrefixes(blockchainID) {
+		if err := database.ClearPrefix(db, prefix, clearPrefixWriteSize); err != nil {
+			_ = db.Close()
+			return err
+		}
+	}
+	return db.Close()
+}
+
+// chainDBPrefixes returns the prefixes under which metalgo keeps the keys of the
+// blockchain [blockchainID] in the node DB. The chain DB is prefixed by the
+// blockchain ID, and nested into it, the VM and consensus DBs are prefixed by
+// the hash of both prefixes, so every one of them must be cleared
+func chainDBPrefixes(blockchainID ids.ID) [][]byte {
+	chainPrefix := prefixdb.MakePrefix(blockchainID[:])
+	prefixes := [][]byte{chainPrefix}
+	for _, dbPrefix := range [][]byte{
+		chains.VMDBPrefix,
+		chains.ChainBootstrappingDBPrefix,
+		chains.VertexDBPrefix,
+		chains.VertexBootstrappingDBPrefix,
+		chains.TxBootstrappingDBPrefix,
+		chains.BlockBootstrappingDBPrefix,
+	} {
+		prefixes = append(prefixes, prefixdb.JoinPrefixes(chainPrefix, dbPrefix))
+	}
+	return prefixes
+}
+
+// RemoveUndeployedChains removes from [clusterInfo] the blockchains undeployed
+// from the local network, and returns the subnets they were deployed on
+func RemoveUndeployedChains(app *application.Avalanche, clusterInfo *rpcpb.ClusterInfo) ([]string, error) {
+	if clusterInfo == nil {
+		return nil, nil
+	}
+	extraLocalNetworkData, err := getOrEmptyExtraLocalNetworkData(app)
+	if err != nil {
+		return nil, err
+	}
+	subnetIDs := []string{}
+	for _, blockchainID := range extraLocalNetworkData.UndeployedBlockchainIDs {
+		if chainInfo, ok := clusterInfo.CustomChains[blockchainID]; ok {
+			subnetIDs = append(subnetIDs, chainInfo.SubnetId)
+			delete(clusterInfo.CustomChains, blockchainID)
+		}
+	}
+	return subnetIDs, nil
+}
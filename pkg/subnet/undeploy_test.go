@@ -0,0 +1,164 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MetalBlockchain/metal-cli/internal/mocks"
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/binutils"
+	"github.com/MetalBlockchain/metal-cli/pkg/config"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
+	"github.com/MetalBlockchain/metal-cli/pkg/snapshot"
+	"github.com/MetalBlockchain/metal-network-runner/client"
+	"github.com/MetalBlockchain/metal-network-runner/rpcpb"
+	"github.com/MetalBlockchain/metalgo/chains"
+	"github.com/MetalBlockchain/metalgo/database"
+	"github.com/MetalBlockchain/metalgo/database/leveldb"
+	"github.com/MetalBlockchain/metalgo/database/prefixdb"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/logging"
+	"github.com/MetalBlockchain/metalgo/version"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRemoveTrackedSubnet(t *testing.T) {
+	require := setupTest(t)
+	subnetID := ids.GenerateTestID()
+	otherSubnetID := ids.GenerateTestID()
+	require.Equal(otherSubnetID.String(), removeTrackedSubnet(subnetID.String()+","+otherSubnetID.String(), subnetID))
+	require.Equal(otherSubnetID.String(), removeTrackedSubnet(otherSubnetID.String(), subnetID))
+	require.Equal("", removeTrackedSubnet(subnetID.String(), subnetID))
+	require.Equal("", removeTrackedSubnet("", subnetID))
+}
+
+// putChainKey writes [key] into the VM DB of [blockchainID], as metalgo does
+func putChainKey(db database.Database, blockchainID ids.ID, key []byte) error {
+	return prefixdb.New(chains.VMDBPrefix, prefixdb.New(blockchainID[:], db)).Put(key, key)
+}
+
+func hasChainKey(db database.Database, blockchainID ids.ID, key []byte) (bool, error) {
+	return prefixdb.New(chains.VMDBPrefix, prefixdb.New(blockchainID[:], db)).Has(key)
+}
+
+func TestUndeployFromLocalNetwork(t *testing.T) {
+	require := setupTest(t)
+	app := &application.Avalanche{}
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), prompts.NewPrompter(), application.NewDownloader())
+	require.NoError(os.MkdirAll(app.GetRunDir(), constants.DefaultPerms755))
+
+	subnetID := ids.GenerateTestID()
+	otherSubnetID := ids.GenerateTestID()
+	blockchainID := ids.GenerateTestID()
+	otherBlockchainID := ids.GenerateTestID()
+	clusterInfo := &rpcpb.ClusterInfo{
+		NodeNames: []string{"node1", "node2"},
+		NodeInfos: map[string]*rpcpb.NodeInfo{
+			"node1": {
+				Name:               "node1",
+				WhitelistedSubnets: subnetID.String() + "," + otherSubnetID.String(),
+			},
+			"node2": {
+				Name:               "node2",
+				WhitelistedSubnets: subnetID.String(),
+			},
+		},
+		CustomChains: map[string]*rpcpb.CustomChainInfo{
+			blockchainID.String(): {
+				ChainName: testChainName,
+				VmId:      testVMID,
+				SubnetId:  subnetID.String(),
+				ChainId:   blockchainID.String(),
+			},
+			otherBlockchainID.String(): {
+				ChainName: "other",
+				SubnetId:  otherSubnetID.String(),
+				ChainId:   otherBlockchainID.String(),
+			},
+		},
+	}
+	cli := &mocks.Client{}
+	cli.On("Status", mock.Anything).Return(&rpcpb.StatusResponse{ClusterInfo: clusterInfo}, nil)
+	// saving the snapshot stops the network, keeping its node configs and DBs
+	key := []byte("key")
+	snapshotDir := snapshot.Dir(app.GetSnapshotsDir(), undeploySnapshotName)
+	dbPath := filepath.Join(snapshotDir, snapshotDBDirName, "node1", "network-12345", version.CurrentDatabase.String())
+	cli.On("SaveSnapshot", mock.Anything, undeploySnapshotName).Run(func(mock.Arguments) {
+		require.NoError(os.MkdirAll(snapshotDir, constants.DefaultPerms755))
+		networkConfig := fmt.Sprintf(`{"nodeConfigs": [
+			{"name": "node1", "flags": {"track-subnets": "%s,%s", "http-port": 9650}},
+			{"name": "node2", "flags": {"track-subnets": "%s", "http-port": 9652}}
+		]}`, subnetID, otherSubnetID, subnetID)
+		require.NoError(os.WriteFile(filepath.Join(snapshotDir, snapshotNetworkConfigFileName), []byte(networkConfig), constants.WriteReadReadPerms))
+		db, err := leveldb.New(dbPath, nil, logging.NoLog{}, "", prometheus.NewRegistry())
+		require.NoError(err)
+		require.NoError(putChainKey(db, blockchainID, key))
+		require.NoError(putChainKey(db, otherBlockchainID, key))
+		require.NoError(db.Close())
+	}).Return(&rpcpb.SaveSnapshotResponse{}, nil)
+	cli.On("LoadSnapshot", mock.Anything, undeploySnapshotName, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&rpcpb.LoadSnapshotResponse{ClusterInfo: clusterInfo}, nil)
+	cli.On("RemoveSnapshot", mock.Anything, undeploySnapshotName).Return(&rpcpb.RemoveSnapshotResponse{}, nil)
+	cli.On("Close").Return(nil)
+	binDownloader := &mocks.PluginBinaryDownloader{}
+	binDownloader.On("RemoveVM", testVMID).Return(nil)
+
+	deployer := &LocalDeployer{
+		getClientFunc: func(...binutils.GRPCClientOpOption) (client.Client, error) {
+			return cli, nil
+		},
+		binaryDownloader: binDownloader,
+		app:              app,
+	}
+	require.NoError(deployer.UndeployFromLocalNetwork(testChainName, subnetID, blockchainID))
+	binDownloader.AssertCalled(t, "RemoveVM", testVMID)
+	cli.AssertNumberOfCalls(t, "LoadSnapshot", 1)
+	cli.AssertCalled(t, "RemoveSnapshot", mock.Anything, undeploySnapshotName)
+
+	// the nodes no longer track the subnet, and node2 tracks no subnet at all
+	networkConfigBytes, err := os.ReadFile(filepath.Join(snapshotDir, snapshotNetworkConfigFileName))
+	require.NoError(err)
+	var networkConfig struct {
+		NodeConfigs []struct {
+			Name  string                 `json:"name"`
+			Flags map[string]interface{} `json:"flags"`
+		} `json:"nodeConfigs"`
+	}
+	require.NoError(json.Unmarshal(networkConfigBytes, &networkConfig))
+	require.Len(networkConfig.NodeConfigs, 2)
+	require.Equal(otherSubnetID.String(), networkConfig.NodeConfigs[0].Flags["track-subnets"])
+	require.NotContains(networkConfig.NodeConfigs[1].Flags, "track-subnets")
+	require.InDelta(9652, networkConfig.NodeConfigs[1].Flags["http-port"], 0)
+
+	// only the data of the undeployed blockchain is removed from the node DB
+	db, err := leveldb.New(dbPath, nil, logging.NoLog{}, "", prometheus.NewRegistry())
+	require.NoError(err)
+	has, err := hasChainKey(db, blockchainID, key)
+	require.NoError(err)
+	require.False(has)
+	has, err = hasChainKey(db, otherBlockchainID, key)
+	require.NoError(err)
+	require.True(has)
+	require.NoError(db.Close())
+
+	extraLocalNetworkData, err := GetExtraLocalNetworkData(app)
+	require.NoError(err)
+	require.Equal([]string{blockchainID.String()}, extraLocalNetworkData.UndeployedBlockchainIDs)
+
+	// the undeployed blockchain is no longer listed
+	subnetIDs, err := RemoveUndeployedChains(app, clusterInfo)
+	require.NoError(err)
+	require.Equal([]string{subnetID.String()}, subnetIDs)
+	require.Len(clusterInfo.CustomChains, 1)
+	require.Contains(clusterInfo.CustomChains, otherBlockchainID.String())
+
+	// and can't be undeployed twice
+	require.ErrorContains(deployer.UndeployFromLocalNetwork(testChainName, subnetID, blockchainID), "is not deployed")
+}
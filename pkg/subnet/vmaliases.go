@@ -0,0 +1,81 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metalgo/ids"
+)
+
+// aliases metalgo registers for its own VMs, that can't be used for plugins
+var reservedVMAliases = []string{"platform", "avm", "evm"}
+
+// VMIDToName returns the VM name [vmID] was derived from, if it was derived
+// from a name (see anrutils.VMID)
+func VMIDToName(vmID ids.ID) (string, bool) {
+	name := bytes.TrimRight(vmID[:], "\x00")
+	if len(name) == 0 {
+		return "", false
+	}
+	for _, b := range name {
+		if b < ' ' || b > '~' {
+			return "", false
+		}
+	}
+	return string(name), true
+}
+
+// LoadLocalVMAliases returns the VM aliases given to the local network nodes
+func LoadLocalVMAliases(app *application.Avalanche) (map[ids.ID][]string, error) {
+	aliases := map[ids.ID][]string{}
+	aliasesBytes, err := os.ReadFile(app.GetVMAliasesPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return aliases, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(aliasesBytes, &aliases); err != nil {
+		return nil, fmt.Errorf("invalid VM aliases file %s: %w", app.GetVMAliasesPath(), err)
+	}
+	return aliases, nil
+}
+
+// AddLocalVMAlias makes the local network nodes know the VM [vmID] also as [alias],
+// so its plugin and its API are found by the VM name. The alias is moved from any
+// other VM it was given to. The nodes load the aliases when started
+func AddLocalVMAlias(app *application.Avalanche, vmID ids.ID, alias string) error {
+	if slices.Contains(reservedVMAliases, alias) || alias == vmID.String() {
+		return nil
+	}
+	aliases, err := LoadLocalVMAliases(app)
+	if err != nil {
+		return err
+	}
+	for id, idAliases := range aliases {
+		idAliases = slices.DeleteFunc(idAliases, func(a string) bool { return a == alias })
+		if len(idAliases) == 0 {
+			delete(aliases, id)
+		} else {
+			aliases[id] = idAliases
+		}
+	}
+	aliases[vmID] = append(aliases[vmID], alias)
+	aliasesBytes, err := json.MarshalIndent(aliases, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(app.GetVMAliasesPath()), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	return os.WriteFile(app.GetVMAliasesPath(), aliasesBytes, constants.WriteReadReadPerms)
+}
@@ -0,0 +1,59 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"testing"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/config"
+	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
+	anrutils "github.com/MetalBlockchain/metal-network-runner/utils"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVMIDToName(t *testing.T) {
+	require := require.New(t)
+	vmID, err := anrutils.VMID("mySubnet")
+	require.NoError(err)
+	name, ok := VMIDToName(vmID)
+	require.True(ok)
+	require.Equal("mySubnet", name)
+
+	_, ok = VMIDToName(ids.Empty)
+	require.False(ok)
+	_, ok = VMIDToName(ids.GenerateTestID())
+	require.False(ok)
+}
+
+func TestAddLocalVMAlias(t *testing.T) {
+	require := require.New(t)
+	app := &application.Avalanche{}
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), prompts.NewPrompter(), application.NewDownloader())
+
+	aliases, err := LoadLocalVMAliases(app)
+	require.NoError(err)
+	require.Empty(aliases)
+
+	vmID1, err := anrutils.VMID("subnetA")
+	require.NoError(err)
+	vmID2 := ids.GenerateTestID()
+	require.NoError(AddLocalVMAlias(app, vmID1, "subnetA"))
+	require.NoError(AddLocalVMAlias(app, vmID1, "evm"))
+	aliases, err = LoadLocalVMAliases(app)
+	require.NoError(err)
+	require.Equal(map[ids.ID][]string{vmID1: {"subnetA"}}, aliases)
+
+	// aliases are moved to the last VM given them
+	require.NoError(AddLocalVMAlias(app, vmID2, "subnetA"))
+	aliases, err = LoadLocalVMAliases(app)
+	require.NoError(err)
+	require.Equal(map[ids.ID][]string{vmID2: {"subnetA"}}, aliases)
+
+	settings := LocalNetworkSettings{HTTPPort: 9650, StakingPort: 9651, VMAliasesFile: app.GetVMAliasesPath()}
+	nodeConfig, err := settings.GlobalNodeConfig(`{"log-level":"debug"}`)
+	require.NoError(err)
+	require.Contains(nodeConfig, `"vm-aliases-file"`)
+}
@@ -0,0 +1,144 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/txs"
+)
+
+// StakeWeightLimits are the minimum and maximum stake weight accepted for a validator
+type StakeWeightLimits struct {
+	Min uint64
+	Max uint64
+}
+
+// Check returns an error if [weight] is out of the limits
+func (l StakeWeightLimits) Check(weight uint64) error {
+	if weight < l.Min || weight > l.Max {
+		if l.Max == math.MaxUint64 {
+			return fmt.Errorf("illegal weight, must be greater than or equal to %d: %d", l.Min, weight)
+		}
+		return fmt.Errorf("illegal weight, must be between %d and %d: %d", l.Min, l.Max, weight)
+	}
+	return nil
+}
+
+// GetStakeWeightLimits returns the stake weight limits of the validators of [subnetID] on
+// [network], or of the Primary Network if [subnetID] is ids.Empty.
+// Primary Network validators must stake at least the minimum reported by the P-Chain, and
+// at most the maximum of the network genesis params. Validators of elastic subnets must
+// stake within the limits of the subnet transformation, while validators of permissioned
+// subnets only need a non zero weight. Local networks and devnets may run custom genesis
+// params, so there the limits set with 'config set MinStakeWeight/MaxStakeWeight' take
+// precedence
+func GetStakeWeightLimits(app *application.Avalanche, network models.Network, subnetID ids.ID) (StakeWeightLimits, error) {
+	limits := StakeWeightLimits{
+		Min: constants.MinStakeWeight,
+		Max: math.MaxUint64,
+	}
+	configurable := network.Kind == models.Local || network.Kind == models.Devnet
+	minIsSet := configurable && app.Conf.ConfigValueIsSet(constants.ConfigMinStakeWeightKey)
+	maxIsSet := configurable && app.Conf.ConfigValueIsSet(constants.ConfigMaxStakeWeightKey)
+	if subnetID == ids.Empty {
+		if params := network.GenesisParams(); params != nil {
+			limits.Min = params.MinValidatorStake
+			limits.Max = params.MaxValidatorStake
+		}
+		if !minIsSet {
+			pClient := platformvm.NewClient(network.Endpoint)
+			ctx, cancel := utils.GetAPIContext()
+			defer cancel()
+			minValStake, _, err := pClient.GetMinStake(ctx, ids.Empty)
+			if err != nil {
+				return StakeWeightLimits{}, fmt.Errorf("failed to get the minimum stake of %s: %w", network.Name(), err)
+			}
+			limits.Min = minValStake
+		}
+	} else if !minIsSet || !maxIsSet {
+		subnetLimits, err := getSubnetStakeWeightLimits(network, subnetID)
+		if err != nil {
+			return StakeWeightLimits{}, err
+		}
+		limits = subnetLimits
+	}
+	if minIsSet {
+		limits.Min = uint64(app.Conf.GetConfigIntValue(constants.ConfigMinStakeWeightKey))
+	}
+	if maxIsSet {
+		limits.Max = uint64(app.Conf.GetConfigIntValue(constants.ConfigMaxStakeWeightKey))
+	}
+	if limits.Min > limits.Max {
+		return StakeWeightLimits{}, fmt.Errorf("invalid stake weight limits for %s: minimum %d is greater than maximum %d", network.Name(), limits.Min, limits.Max)
+	}
+	return limits, nil
+}
+
+// getSubnetStakeWeightLimits returns the stake weight limits of the validators of
+// [subnetID] on [network], as set by its transformation into an elastic subnet
+func getSubnetStakeWeightLimits(network models.Network, subnetID ids.ID) (StakeWeightLimits, error) {
+	limits := StakeWeightLimits{
+		Min: constants.MinStakeWeight,
+		Max: math.MaxUint64,
+	}
+	pClient := platformvm.NewClient(network.Endpoint)
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	subnetInfo, err := pClient.GetSubnet(ctx, subnetID)
+	if err != nil {
+		return StakeWeightLimits{}, fmt.Errorf("failed to get subnet %s on %s: %w", subnetID, network.Name(), err)
+	}
+	if subnetInfo.IsPermissioned {
+		return limits, nil
+	}
+	minValStake, _, err := pClient.GetMinStake(ctx, subnetID)
+	if err != nil {
+		return StakeWeightLimits{}, fmt.Errorf("failed to get the minimum stake of subnet %s: %w", subnetID, err)
+	}
+	txBytes, err := pClient.GetTx(ctx, subnetInfo.SubnetTransformationTxID)
+	if err != nil {
+		return StakeWeightLimits{}, fmt.Errorf("transform subnet tx %s query error: %w", subnetInfo.SubnetTransformationTxID, err)
+	}
+	var tx txs.Tx
+	if _, err := txs.Codec.Unmarshal(txBytes, &tx); err != nil {
+		return StakeWeightLimits{}, fmt.Errorf("couldn't unmarshal tx %s: %w", subnetInfo.SubnetTransformationTxID, err)
+	}
+	transformSubnetTx, ok := tx.Unsigned.(*txs.TransformSubnetTx)
+	if !ok {
+		return StakeWeightLimits{}, fmt.Errorf("got unexpected type %T for tx %s", tx.Unsigned, subnetInfo.SubnetTransformationTxID)
+	}
+	limits.Min = minValStake
+	limits.Max = transformSubnetTx.MaxValidatorStake
+	return limits, nil
+}
+
+// GetMaxWeightShare returns the percentage of the subnet total stake weight above
+// which a single validator is considered to centralize the subnet, as set
+// with 'config max-weight-share', or the default one
+func GetMaxWeightShare(app *application.Avalanche) int {
+	if app.Conf.ConfigValueIsSet(constants.ConfigMaxWeightShareKey) {
+		return app.Conf.GetConfigIntValue(constants.ConfigMaxWeightShareKey)
+	}
+	return constants.DefaultMaxWeightShare
+}
+
+// GetWeightShare returns the percentage of the subnet total stake weight that
+// a new validator with [weight] would hold, once added to [validators]
+func GetWeightShare(validators []platformvm.ClientPermissionlessValidator, weight uint64) float64 {
+	total := float64(weight)
+	for _, validator := range validators {
+		total += float64(validator.Weight)
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(weight) / total * 100
+}
@@ -0,0 +1,162 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/config"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/formatting"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/txs"
+	"github.com/MetalBlockchain/metalgo/vms/secp256k1fx"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetWeightShare(t *testing.T) {
+	validatorsWithWeights := func(weights ...uint64) []platformvm.ClientPermissionlessValidator {
+		validators := []platformvm.ClientPermissionlessValidator{}
+		for _, weight := range weights {
+			validators = append(validators, platformvm.ClientPermissionlessValidator{
+				ClientStaker: platformvm.ClientStaker{Weight: weight},
+			})
+		}
+		return validators
+	}
+
+	tests := []struct {
+		name       string
+		validators []platformvm.ClientPermissionlessValidator
+		weight     uint64
+		expected   float64
+	}{
+		{
+			name:     "first validator",
+			weight:   20,
+			expected: 100,
+		},
+		{
+			name:       "even share",
+			validators: validatorsWithWeights(20, 20, 20),
+			weight:     20,
+			expected:   25,
+		},
+		{
+			name:       "dominant weight",
+			validators: validatorsWithWeights(10, 10),
+			weight:     80,
+			expected:   80,
+		},
+		{
+			name:     "zero weight",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.InDelta(t, tt.expected, GetWeightShare(tt.validators, tt.weight), 1e-9)
+		})
+	}
+}
+
+// newPChainServer returns a server answering the P-Chain API calls to get the
+// stake weight limits of a subnet, elastic if [transformSubnetTx] is given
+func newPChainServer(t *testing.T, transformSubnetTx *txs.TransformSubnetTx) *httptest.Server {
+	transformTxID := ids.GenerateTestID()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		var result interface{}
+		switch req.Method {
+		case "platform.getSubnet":
+			subnet := map[string]interface{}{"isPermissioned": true, "threshold": "1", "locktime": "0"}
+			if transformSubnetTx != nil {
+				subnet["isPermissioned"] = false
+				subnet["subnetTransformationTxID"] = transformTxID
+			}
+			result = subnet
+		case "platform.getMinStake":
+			result = map[string]interface{}{
+				"minValidatorStake": strconv.FormatUint(transformSubnetTx.MinValidatorStake, 10),
+				"minDelegatorStake": strconv.FormatUint(transformSubnetTx.MinDelegatorStake, 10),
+			}
+		case "platform.getTx":
+			tx := &txs.Tx{Unsigned: transformSubnetTx}
+			txBytes, err := txs.Codec.Marshal(txs.CodecVersion, tx)
+			require.NoError(t, err)
+			encoded, err := formatting.Encode(formatting.Hex, txBytes)
+			require.NoError(t, err)
+			result = map[string]interface{}{"tx": encoded, "encoding": "hex"}
+		default:
+			require.FailNow(t, "unexpected method "+req.Method)
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result}))
+	}))
+}
+
+func TestStakeWeightLimits(t *testing.T) {
+	require := require.New(t)
+	app := &application.Avalanche{Conf: config.New()}
+	subnetID := ids.GenerateTestID()
+	server := newPChainServer(t, nil)
+	defer server.Close()
+	devnet := models.NewDevnetNetwork(server.URL, 4242)
+
+	limits, err := GetStakeWeightLimits(app, devnet, subnetID)
+	require.NoError(err)
+	require.Equal(StakeWeightLimits{Min: constants.MinStakeWeight, Max: math.MaxUint64}, limits)
+	require.NoError(limits.Check(1))
+	require.ErrorContains(limits.Check(0), "must be greater than or equal to 1")
+
+	viper.Set(constants.ConfigMinStakeWeightKey, 10)
+	viper.Set(constants.ConfigMaxStakeWeightKey, 100)
+	defer func() {
+		viper.Set(constants.ConfigMinStakeWeightKey, nil)
+		viper.Set(constants.ConfigMaxStakeWeightKey, nil)
+	}()
+	// configured limits don't need network access
+	limits, err = GetStakeWeightLimits(app, models.NewLocalNetwork(), subnetID)
+	require.NoError(err)
+	require.Equal(StakeWeightLimits{Min: 10, Max: 100}, limits)
+	require.NoError(limits.Check(100))
+	require.ErrorContains(limits.Check(101), "must be between 10 and 100")
+
+	// configured limits only apply to custom networks
+	limits, err = GetStakeWeightLimits(app, models.NewNetwork(models.Tahoe, 5, server.URL, ""), subnetID)
+	require.NoError(err)
+	require.Equal(StakeWeightLimits{Min: constants.MinStakeWeight, Max: math.MaxUint64}, limits)
+
+	viper.Set(constants.ConfigMinStakeWeightKey, 1000)
+	_, err = GetStakeWeightLimits(app, models.NewLocalNetwork(), subnetID)
+	require.ErrorContains(err, "minimum 1000 is greater than maximum 100")
+}
+
+func TestStakeWeightLimitsElasticSubnet(t *testing.T) {
+	require := require.New(t)
+	app := &application.Avalanche{Conf: config.New()}
+	server := newPChainServer(t, &txs.TransformSubnetTx{
+		MinValidatorStake: 2000,
+		MaxValidatorStake: 5000,
+		MinDelegatorStake: 100,
+		SubnetAuth:        &secp256k1fx.Input{},
+	})
+	defer server.Close()
+
+	limits, err := GetStakeWeightLimits(app, models.NewDevnetNetwork(server.URL, 4242), ids.GenerateTestID())
+	require.NoError(err)
+	require.Equal(StakeWeightLimits{Min: 2000, Max: 5000}, limits)
+	require.ErrorContains(limits.Check(20), "must be between 2000 and 5000")
+}
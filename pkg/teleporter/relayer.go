@@ -0,0 +1,278 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package teleporter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/binutils"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/logging"
+	"github.com/shirou/gopsutil/process"
+)
+
+const (
+	relayerVM            = "evm"
+	relayerMessageFormat = "teleporter"
+
+	// time given to the relayer to exit after being interrupted, before killing it
+	relayerStopTimeout = 10 * time.Second
+	// time given to the relayer to exit after being killed
+	relayerKillTimeout = 2 * time.Second
+	relayerExitPoll    = 100 * time.Millisecond
+)
+
+var ErrRelayerNotRunning = errors.New("relayer is not running")
+
+// RelayerChain is a blockchain whose teleporter messages are delivered by the relayer
+type RelayerChain struct {
+	Name             string
+	SubnetID         ids.ID
+	BlockchainID     ids.ID
+	RPCEndpoint      string
+	WSEndpoint       string
+	MessengerAddress string
+}
+
+// RelayerConfig is the AWM relayer config file
+type RelayerConfig struct {
+	LogLevel               string                     `json:"log-level"`
+	PChainAPIURL           string                     `json:"p-chain-api-url"`
+	InfoAPIURL             string                     `json:"info-api-url"`
+	StorageLocation        string                     `json:"storage-location"`
+	ProcessMissedBlocks    bool                       `json:"process-missed-blocks"`
+	SourceBlockchains      []*RelayerSourceChain      `json:"source-blockchains"`
+	DestinationBlockchains []*RelayerDestinationChain `json:"destination-blockchains"`
+	MetricsPort            uint16                     `json:"metrics-port"`
+}
+
+type RelayerSourceChain struct {
+	SubnetID         string                                  `json:"subnet-id"`
+	BlockchainID     string                                  `json:"blockchain-id"`
+	VM               string                                  `json:"vm"`
+	RPCEndpoint      string                                  `json:"rpc-endpoint"`
+	WSEndpoint       string                                  `json:"ws-endpoint"`
+	MessageContracts map[string]RelayerMessageProtocolConfig `json:"message-contracts"`
+}
+
+type RelayerMessageProtocolConfig struct {
+	MessageFormat string                 `json:"message-format"`
+	Settings      map[string]interface{} `json:"settings"`
+}
+
+type RelayerDestinationChain struct {
+	SubnetID          string `json:"subnet-id"`
+	BlockchainID      string `json:"blockchain-id"`
+	VM                string `json:"vm"`
+	RPCEndpoint       string `json:"rpc-endpoint"`
+	AccountPrivateKey string `json:"account-private-key"`
+}
+
+// relayerRunFile keeps track of the relayer process started by the CLI
+type relayerRunFile struct {
+	Pid     int    `json:"pid"`
+	Version string `json:"version"`
+}
+
+// CreateRelayerConfig returns a config that relays the teleporter messages sent
+// between any two of [chains] of [network], paying for the deliveries with
+// [privateKey] and receiving the rewards at [rewardAddress]
+func CreateRelayerConfig(
+	network models.Network,
+	storageDir string,
+	chains []RelayerChain,
+	privateKey string,
+	rewardAddress string,
+) *RelayerConfig {
+	config := &RelayerConfig{
+		LogLevel:               logging.Info.LowerString(),
+		PChainAPIURL:           network.Endpoint,
+		InfoAPIURL:             network.Endpoint,
+		StorageLocation:        storageDir,
+		ProcessMissedBlocks:    false,
+		SourceBlockchains:      []*RelayerSourceChain{},
+		DestinationBlockchains: []*RelayerDestinationChain{},
+		MetricsPort:            constants.AWMRelayerMetricsPort,
+	}
+	for _, chain := range chains {
+		config.SourceBlockchains = append(config.SourceBlockchains, &RelayerSourceChain{
+			SubnetID:     chain.SubnetID.String(),
+			BlockchainID: chain.BlockchainID.String(),
+			VM:           relayerVM,
+			RPCEndpoint:  chain.RPCEndpoint,
+			WSEndpoint:   chain.WSEndpoint,
+			MessageContracts: map[string]RelayerMessageProtocolConfig{
+				chain.MessengerAddress: {
+					MessageFormat: relayerMessageFormat,
+					Settings: map[string]interface{}{
+						"reward-address": rewardAddress,
+					},
+				},
+			},
+		})
+		config.DestinationBlockchains = append(config.DestinationBlockchains, &RelayerDestinationChain{
+			SubnetID:          chain.SubnetID.String(),
+			BlockchainID:      chain.BlockchainID.String(),
+			VM:                relayerVM,
+			RPCEndpoint:       chain.RPCEndpoint,
+			AccountPrivateKey: privateKey,
+		})
+	}
+	return config
+}
+
+// WriteRelayerConfig saves [config] at the app relayer config path
+func WriteRelayerConfig(app *application.Avalanche, config *RelayerConfig) error {
+	bs, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(app.GetAWMRelayerConfigPath(), bs, constants.WriteReadUserOnlyPerms)
+}
+
+// StartRelayer installs the given relayer version if needed, and runs it in the
+// background with the app relayer config, logging into the app relayer log file
+func StartRelayer(app *application.Avalanche, version string) (int, error) {
+	if !utils.FileExists(app.GetAWMRelayerConfigPath()) {
+		return 0, fmt.Errorf("relayer config not found at %s", app.GetAWMRelayerConfigPath())
+	}
+	if running, err := RelayerIsRunning(app); err != nil {
+		return 0, err
+	} else if running {
+		return 0, fmt.Errorf("relayer is already running")
+	}
+	version, binPath, err := binutils.SetupAWMRelayer(app, version)
+	if err != nil {
+		return 0, fmt.Errorf("failed to install awm-relayer: %w", err)
+	}
+	logFile, err := os.OpenFile(app.GetAWMRelayerLogPath(), os.O_WRONLY|os.O_CREATE|os.O_APPEND, constants.WriteReadReadPerms)
+	if err != nil {
+		return 0, err
+	}
+	defer logFile.Close()
+	cmd := exec.Command(binPath, "--config-file", app.GetAWMRelayerConfigPath())
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	if err := cmd.Start(); err != nil {
+		return 0, err
+	}
+	rf := relayerRunFile{
+		Pid:     cmd.Process.Pid,
+		Version: version,
+	}
+	bs, err := json.Marshal(&rf)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(app.GetAWMRelayerRunPath(), bs, constants.WriteReadReadPerms); err != nil {
+		return 0, err
+	}
+	return rf.Pid, nil
+}
+
+// StopRelayer stops the relayer process started by the CLI, and waits for it to
+// exit, so that a new one can be started right after. The process is killed if it
+// doesn't exit on time after being interrupted
+func StopRelayer(app *application.Avalanche) error {
+	rf, err := loadRelayerRunFile(app)
+	if err != nil {
+		return err
+	}
+	if exists, err := process.PidExists(int32(rf.Pid)); err != nil {
+		return err
+	} else if exists {
+		proc, err := os.FindProcess(rf.Pid)
+		if err != nil {
+			return fmt.Errorf("could not find process with pid %d: %w", rf.Pid, err)
+		}
+		if err := proc.Signal(os.Interrupt); err != nil {
+			return fmt.Errorf("failed stopping relayer process with pid %d: %w", rf.Pid, err)
+		}
+		exited, err := waitForExit(rf.Pid, relayerStopTimeout)
+		if err != nil {
+			return err
+		}
+		if !exited {
+			if err := proc.Kill(); err != nil {
+				return fmt.Errorf("failed killing relayer process with pid %d: %w", rf.Pid, err)
+			}
+			if exited, err := waitForExit(rf.Pid, relayerKillTimeout); err != nil {
+				return err
+			} else if !exited {
+				return fmt.Errorf("relayer process with pid %d did not exit after being killed", rf.Pid)
+			}
+		}
+	}
+	return os.Remove(app.GetAWMRelayerRunPath())
+}
+
+// waitForExit polls for up to [timeout] until the process [pid] exits, and returns
+// whether it did
+func waitForExit(pid int, timeout time.Duration) (bool, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		exists, err := processAlive(pid)
+		if err != nil || !exists {
+			return !exists, err
+		}
+		if time.Now().After(deadline) {
+			return false, nil
+		}
+		time.Sleep(relayerExitPoll)
+	}
+}
+
+// processAlive returns true if the process [pid] exists and is not a zombie, as a
+// child process of this one is until reaped
+func processAlive(pid int) (bool, error) {
+	exists, err := process.PidExists(int32(pid))
+	if err != nil || !exists {
+		return false, err
+	}
+	proc, err := process.NewProcess(int32(pid))
+	if err != nil {
+		// exited meanwhile
+		return false, nil
+	}
+	status, err := proc.Status()
+	if err != nil {
+		return true, nil
+	}
+	return status != "Z", nil
+}
+
+// RelayerIsRunning returns true if the relayer process started by the CLI is alive
+func RelayerIsRunning(app *application.Avalanche) (bool, error) {
+	rf, err := loadRelayerRunFile(app)
+	if errors.Is(err, ErrRelayerNotRunning) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return process.PidExists(int32(rf.Pid))
+}
+
+func loadRelayerRunFile(app *application.Avalanche) (relayerRunFile, error) {
+	rf := relayerRunFile{}
+	bs, err := os.ReadFile(app.GetAWMRelayerRunPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return rf, ErrRelayerNotRunning
+	}
+	if err != nil {
+		return rf, err
+	}
+	if err := json.Unmarshal(bs, &rf); err != nil {
+		return rf, fmt.Errorf("failed unmarshalling relayer run file %s: %w", app.GetAWMRelayerRunPath(), err)
+	}
+	return rf, nil
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build !windows
+
+package teleporter
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/config"
+	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
+	"github.com/MetalBlockchain/metalgo/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStopRelayerWaitsForExit(t *testing.T) {
+	require := require.New(t)
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, &config.Config{}, prompts.NewPrompter(), application.NewDownloader())
+
+	cmd := exec.Command("sleep", "30")
+	require.NoError(cmd.Start())
+	bs, err := json.Marshal(relayerRunFile{Pid: cmd.Process.Pid, Version: testVersion})
+	require.NoError(err)
+	require.NoError(os.MkdirAll(filepath.Dir(app.GetAWMRelayerRunPath()), 0o755))
+	require.NoError(os.WriteFile(app.GetAWMRelayerRunPath(), bs, 0o600))
+
+	require.NoError(StopRelayer(app))
+	alive, err := processAlive(cmd.Process.Pid)
+	require.NoError(err)
+	require.False(alive)
+	require.NoFileExists(app.GetAWMRelayerRunPath())
+	_ = cmd.Wait()
+
+	require.ErrorIs(StopRelayer(app), ErrRelayerNotRunning)
+}
@@ -0,0 +1,198 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package teleporter
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/binutils"
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/evm"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/subnet-evm/accounts/abi"
+	"github.com/MetalBlockchain/subnet-evm/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	releaseURL                      = "https://github.com/%s/%s/releases/download/%s/%s"
+	messengerContractAddressFileFmt = "TeleporterMessenger_Contract_Address_%s.txt"
+	messengerDeployerAddressFileFmt = "TeleporterMessenger_Deployer_Address_%s.txt"
+	messengerDeployerTxFileFmt      = "TeleporterMessenger_Deployment_Transaction_%s.txt"
+	registryBytecodeFileFmt         = "TeleporterRegistry_Bytecode_%s.txt"
+
+	// the registry is created with the messenger as the protocol version 1
+	registryInitialProtocolVersion = 1
+	registryConstructorABI         = `[{
+		"type": "constructor",
+		"inputs": [{
+			"name": "initialEntries",
+			"type": "tuple[]",
+			"components": [
+				{"name": "version", "type": "uint256"},
+				{"name": "protocolAddress", "type": "address"}
+			]
+		}]
+	}]`
+)
+
+// the messenger deployer address must pay for the messenger deploy tx
+var messengerDeployerRequiredBalance = new(big.Int).Mul(big.NewInt(1e18), big.NewInt(10))
+
+// protocolRegistryEntry is the registry constructor entry type
+type protocolRegistryEntry struct {
+	Version         *big.Int
+	ProtocolAddress common.Address
+}
+
+// Deployer deploys the Teleporter messenger and registry contracts of a given
+// release, into EVM chains
+type Deployer struct {
+	version                  string
+	messengerContractAddress string
+	messengerDeployerAddress string
+	messengerDeployerTx      string
+	registryBytecode         string
+}
+
+// GetLatestVersion returns the latest Teleporter release
+func GetLatestVersion(app *application.Avalanche) (string, error) {
+	return app.Downloader.GetLatestReleaseVersion(binutils.GetGithubLatestReleaseURL(
+		constants.AvaLabsOrg,
+		constants.TeleporterRepoName,
+	))
+}
+
+// NewDeployer returns a deployer for Teleporter release [version], downloading
+// its artifacts into the app Teleporter dir if they are not already there
+func NewDeployer(app *application.Avalanche, version string) (*Deployer, error) {
+	d := &Deployer{version: version}
+	assets := []struct {
+		fileFmt string
+		value   *string
+	}{
+		{messengerContractAddressFileFmt, &d.messengerContractAddress},
+		{messengerDeployerAddressFileFmt, &d.messengerDeployerAddress},
+		{messengerDeployerTxFileFmt, &d.messengerDeployerTx},
+		{registryBytecodeFileFmt, &d.registryBytecode},
+	}
+	versionDir := filepath.Join(app.GetTeleporterBinDir(), version)
+	if err := os.MkdirAll(versionDir, constants.DefaultPerms755); err != nil {
+		return nil, err
+	}
+	for _, asset := range assets {
+		fileName := fmt.Sprintf(asset.fileFmt, version)
+		path := filepath.Join(versionDir, fileName)
+		if !utils.FileExists(path) {
+			url := fmt.Sprintf(releaseURL, constants.AvaLabsOrg, constants.TeleporterRepoName, version, fileName)
+			bs, err := app.Downloader.Download(url)
+			if err != nil {
+				return nil, fmt.Errorf("failure downloading teleporter %s asset %s: %w", version, url, err)
+			}
+			if err := os.WriteFile(path, bs, constants.WriteReadReadPerms); err != nil {
+				return nil, err
+			}
+		}
+		bs, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		*asset.value = strings.TrimSpace(string(bs))
+	}
+	return d, nil
+}
+
+// Version returns the Teleporter release of the deployer
+func (d *Deployer) Version() string {
+	return d.version
+}
+
+// Deploy deploys the messenger and registry contracts into the chain at [rpcURL],
+// paying with [privateKey], and returns their addresses. The messenger is not
+// deployed again if it is already present
+func (d *Deployer) Deploy(chainName string, rpcURL string, privateKey string) (string, string, error) {
+	if err := d.DeployMessenger(chainName, rpcURL, privateKey); err != nil {
+		return "", "", err
+	}
+	registryAddress, err := d.DeployRegistry(chainName, rpcURL, privateKey)
+	if err != nil {
+		return "", "", err
+	}
+	return d.messengerContractAddress, registryAddress, nil
+}
+
+// DeployMessenger deploys the messenger contract into the chain at [rpcURL] by
+// issuing the release deploy tx, after funding its deployer address with [privateKey]
+func (d *Deployer) DeployMessenger(chainName string, rpcURL string, privateKey string) error {
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return err
+	}
+	if deployed, err := evm.ContractAlreadyDeployed(client, d.messengerContractAddress); err != nil {
+		return fmt.Errorf("failure making a request to %s: %w", rpcURL, err)
+	} else if deployed {
+		ux.Logger.PrintToUser("Teleporter Messenger has already been deployed to %s", chainName)
+		return nil
+	}
+	balance, err := evm.GetAddressBalance(client, d.messengerDeployerAddress)
+	if err != nil {
+		return err
+	}
+	if balance.Cmp(messengerDeployerRequiredBalance) < 0 {
+		toFund := new(big.Int).Sub(messengerDeployerRequiredBalance, balance)
+		if err := evm.FundAddress(client, privateKey, d.messengerDeployerAddress, toFund); err != nil {
+			return fmt.Errorf("failure funding teleporter messenger deployer on %s: %w", chainName, err)
+		}
+	}
+	if err := evm.IssueTx(client, d.messengerDeployerTx); err != nil {
+		return fmt.Errorf("failure deploying teleporter messenger on %s: %w", chainName, err)
+	}
+	ux.Logger.PrintToUser("Teleporter Messenger successfully deployed to %s (%s)", chainName, d.messengerContractAddress)
+	return nil
+}
+
+// DeployRegistry deploys a registry contract into the chain at [rpcURL], with the
+// messenger as its initial protocol version, paying with [privateKey]
+func (d *Deployer) DeployRegistry(chainName string, rpcURL string, privateKey string) (string, error) {
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return "", err
+	}
+	registryABI, err := abi.JSON(strings.NewReader(registryConstructorABI))
+	if err != nil {
+		return "", err
+	}
+	txOpts, err := evm.GetTxOptsWithSigner(client, privateKey)
+	if err != nil {
+		return "", err
+	}
+	initialEntries := []protocolRegistryEntry{
+		{
+			Version:         big.NewInt(registryInitialProtocolVersion),
+			ProtocolAddress: common.HexToAddress(d.messengerContractAddress),
+		},
+	}
+	registryAddress, tx, _, err := bind.DeployContract(
+		txOpts,
+		registryABI,
+		common.FromHex(d.registryBytecode),
+		client,
+		initialEntries,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failure deploying teleporter registry on %s: %w", chainName, err)
+	}
+	if _, success, err := evm.WaitForTransaction(client, tx); err != nil {
+		return "", err
+	} else if !success {
+		return "", fmt.Errorf("failure deploying teleporter registry on %s: tx %s failed", chainName, tx.Hash())
+	}
+	ux.Logger.PrintToUser("Teleporter Registry successfully deployed to %s (%s)", chainName, registryAddress.Hex())
+	return registryAddress.Hex(), nil
+}
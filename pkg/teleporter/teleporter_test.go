@@ -0,0 +1,74 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package teleporter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/MetalBlockchain/metal-cli/internal/mocks"
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/config"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/logging"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testVersion = "v1.0.0"
+
+func TestNewDeployer(t *testing.T) {
+	require := require.New(t)
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, &config.Config{}, prompts.NewPrompter(), application.NewDownloader())
+	mockDownloader := &mocks.Downloader{}
+	mockDownloader.On("Download", mock.Anything).Return(
+		func(url string) []byte {
+			// each asset contains its own file name
+			return []byte(url[strings.LastIndex(url, "/")+1:] + "\n")
+		},
+		nil,
+	)
+	app.Downloader = mockDownloader
+
+	d, err := NewDeployer(app, testVersion)
+	require.NoError(err)
+	require.Equal(testVersion, d.Version())
+	require.Equal(fmt.Sprintf(messengerContractAddressFileFmt, testVersion), d.messengerContractAddress)
+	require.Equal(fmt.Sprintf(messengerDeployerAddressFileFmt, testVersion), d.messengerDeployerAddress)
+	require.Equal(fmt.Sprintf(messengerDeployerTxFileFmt, testVersion), d.messengerDeployerTx)
+	require.Equal(fmt.Sprintf(registryBytecodeFileFmt, testVersion), d.registryBytecode)
+	mockDownloader.AssertNumberOfCalls(t, "Download", 4)
+
+	// assets are downloaded only once
+	_, err = NewDeployer(app, testVersion)
+	require.NoError(err)
+	mockDownloader.AssertNumberOfCalls(t, "Download", 4)
+}
+
+func TestCreateRelayerConfig(t *testing.T) {
+	require := require.New(t)
+	chains := []RelayerChain{
+		{Name: "a", SubnetID: ids.GenerateTestID(), BlockchainID: ids.GenerateTestID(), RPCEndpoint: "rpcA", WSEndpoint: "wsA", MessengerAddress: "0xA"},
+		{Name: "b", SubnetID: ids.GenerateTestID(), BlockchainID: ids.GenerateTestID(), RPCEndpoint: "rpcB", WSEndpoint: "wsB", MessengerAddress: "0xB"},
+	}
+	network := models.NewLocalNetwork()
+	config := CreateRelayerConfig(network, "storage", chains, "privKey", "0xReward")
+	require.Equal(network.Endpoint, config.PChainAPIURL)
+	require.Equal("storage", config.StorageLocation)
+	require.Len(config.SourceBlockchains, 2)
+	require.Len(config.DestinationBlockchains, 2)
+	for i, chain := range chains {
+		source := config.SourceBlockchains[i]
+		require.Equal(chain.BlockchainID.String(), source.BlockchainID)
+		require.Equal(chain.WSEndpoint, source.WSEndpoint)
+		require.Equal("0xReward", source.MessageContracts[chain.MessengerAddress].Settings["reward-address"])
+		destination := config.DestinationBlockchains[i]
+		require.Equal(chain.SubnetID.String(), destination.SubnetID)
+		require.Equal(chain.RPCEndpoint, destination.RPCEndpoint)
+		require.Equal("privKey", destination.AccountPrivateKey)
+	}
+}
@@ -0,0 +1,224 @@
+# Infrastructure of the {{ .ClusterName }} cluster, generated by metal-cli.
+#
+# The import blocks adopt the existing resources into the Terraform state on the
+# first apply (Terraform >= 1.5), so nothing is recreated. Review the plan before
+# applying, and remove the import blocks once imported.
+
+terraform {
+  required_providers {
+{{- if .AWSRegions }}
+    aws = {
+      source = "hashicorp/aws"
+    }
+{{- end }}
+{{- if .GCP }}
+    google = {
+      source = "hashicorp/google"
+    }
+{{- end }}
+  }
+}
+{{- range $region := .AWSRegions }}
+
+provider "aws" {
+  alias  = "{{ resourceName $region.Region }}"
+  region = "{{ $region.Region }}"
+}
+{{- range $region.SecurityGroups }}
+
+resource "aws_security_group" "{{ resourceName .Name }}" {
+  provider    = aws.{{ resourceName $region.Region }}
+  name        = "{{ .Name }}"
+  description = "{{ .Description }}"
+{{- range .Rules }}
+
+  ingress {
+    protocol    = "{{ .Protocol }}"
+    from_port   = {{ .FromPort }}
+    to_port     = {{ .ToPort }}
+    cidr_blocks = [{{ quoteList .CIDRs }}]
+  }
+{{- end }}
+
+  egress {
+    protocol    = "-1"
+    from_port   = 0
+    to_port     = 0
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+}
+{{- if .ID }}
+
+import {
+  provider = aws.{{ resourceName $region.Region }}
+  to       = aws_security_group.{{ resourceName .Name }}
+  id       = "{{ .ID }}"
+}
+{{- end }}
+{{- end }}
+{{- range $region.Instances }}
+
+# {{ .Roles }} node
+resource "aws_instance" "{{ resourceName .ID }}" {
+  provider               = aws.{{ resourceName $region.Region }}
+  ami                    = "{{ .AMI }}"
+  instance_type          = "{{ .InstanceType }}"
+  key_name               = "{{ .KeyPair }}"
+  vpc_security_group_ids = [aws_security_group.{{ resourceName .SecurityGroup }}.id]
+
+  root_block_device {
+    volume_size           = {{ .VolumeSize }}
+    volume_type           = "{{ .VolumeType }}"
+{{- if .IOPS }}
+    iops                  = {{ .IOPS }}
+{{- end }}
+{{- if .Throughput }}
+    throughput            = {{ .Throughput }}
+{{- end }}
+    delete_on_termination = true
+  }
+
+{{- if .Tags }}
+
+  tags = {
+{{ hclMap .Tags }}
+  }
+{{- end }}
+}
+
+import {
+  provider = aws.{{ resourceName $region.Region }}
+  to       = aws_instance.{{ resourceName .ID }}
+  id       = "{{ .ID }}"
+}
+{{- if .EIPAllocationID }}
+
+resource "aws_eip" "{{ resourceName .ID }}" {
+  provider = aws.{{ resourceName $region.Region }}
+  domain   = "vpc"
+}
+
+resource "aws_eip_association" "{{ resourceName .ID }}" {
+  provider      = aws.{{ resourceName $region.Region }}
+  instance_id   = aws_instance.{{ resourceName .ID }}.id
+  allocation_id = aws_eip.{{ resourceName .ID }}.id
+}
+
+import {
+  provider = aws.{{ resourceName $region.Region }}
+  to       = aws_eip.{{ resourceName .ID }}
+  id       = "{{ .EIPAllocationID }}"
+}
+{{- if .EIPAssociationID }}
+
+import {
+  provider = aws.{{ resourceName $region.Region }}
+  to       = aws_eip_association.{{ resourceName .ID }}
+  id       = "{{ .EIPAssociationID }}"
+}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- with .GCP }}
+
+provider "google" {
+  project = "{{ .Project }}"
+}
+{{- range $network := .Networks }}
+
+resource "google_compute_network" "{{ resourceName $network.Name }}" {
+  name                    = "{{ $network.Name }}"
+  auto_create_subnetworks = true
+}
+
+import {
+  to = google_compute_network.{{ resourceName $network.Name }}
+  id = "projects/{{ $.GCP.Project }}/global/networks/{{ $network.Name }}"
+}
+{{- range $network.Firewalls }}
+
+resource "google_compute_firewall" "{{ resourceName .Name }}" {
+  name          = "{{ .Name }}"
+  network       = google_compute_network.{{ resourceName $network.Name }}.name
+  source_ranges = [{{ quoteList .SourceRanges }}]
+{{- range .Allowed }}
+
+  allow {
+    protocol = "{{ .Protocol }}"
+    ports    = [{{ quoteList .Ports }}]
+  }
+{{- end }}
+}
+
+import {
+  to = google_compute_firewall.{{ resourceName .Name }}
+  id = "projects/{{ $.GCP.Project }}/global/firewalls/{{ .Name }}"
+}
+{{- end }}
+{{- end }}
+{{- range .Instances }}
+{{- if .StaticIPName }}
+
+resource "google_compute_address" "{{ resourceName .StaticIPName }}" {
+  name         = "{{ .StaticIPName }}"
+  region       = "{{ .Region }}"
+  address_type = "EXTERNAL"
+  network_tier = "PREMIUM"
+}
+
+import {
+  to = google_compute_address.{{ resourceName .StaticIPName }}
+  id = "projects/{{ $.GCP.Project }}/regions/{{ .Region }}/addresses/{{ .StaticIPName }}"
+}
+{{- end }}
+
+# {{ .Roles }} node
+resource "google_compute_instance" "{{ resourceName .Name }}" {
+  name         = "{{ .Name }}"
+  zone         = "{{ .Zone }}"
+  machine_type = "{{ .MachineType }}"
+
+  boot_disk {
+    auto_delete = true
+    initialize_params {
+      image = "{{ .Image }}"
+      size  = {{ .DiskSizeGb }}
+{{- if .DiskType }}
+      type  = "{{ .DiskType }}"
+{{- end }}
+    }
+  }
+
+  network_interface {
+    network = google_compute_network.{{ resourceName .Network }}.name
+    access_config {
+{{- if .StaticIPName }}
+      nat_ip = google_compute_address.{{ resourceName .StaticIPName }}.address
+{{- end }}
+    }
+  }
+{{- if .SSHKeys }}
+
+  metadata = {
+    ssh-keys = {{ printf "%q" .SSHKeys }}
+  }
+{{- end }}
+
+  scheduling {
+    automatic_restart = true
+  }
+{{- if .Labels }}
+
+  labels = {
+{{ hclMap .Labels }}
+  }
+{{- end }}
+}
+
+import {
+  to = google_compute_instance.{{ resourceName .Name }}
+  id = "projects/{{ $.GCP.Project }}/zones/{{ .Zone }}/instances/{{ .Name }}"
+}
+{{- end }}
+{{- end }}
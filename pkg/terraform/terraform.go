@@ -0,0 +1,165 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package terraform
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+//go:embed configs/*
+var configs embed.FS
+
+// Infra describes the cloud resources of a cluster
+type Infra struct {
+	ClusterName string
+	AWSRegions  []AWSRegion
+	GCP         *GCPProject
+}
+
+// AWSRegion holds the AWS resources of a cluster in a region
+type AWSRegion struct {
+	Region         string
+	SecurityGroups []AWSSecurityGroup
+	Instances      []AWSInstance
+}
+
+// AWSSecurityGroup is a security group with its ingress rules
+type AWSSecurityGroup struct {
+	// ID is empty if the group was not found, so it is not imported
+	ID          string
+	Name        string
+	Description string
+	Rules       []AWSRule
+}
+
+// AWSRule is an ingress rule of a security group
+type AWSRule struct {
+	Protocol string
+	FromPort int32
+	ToPort   int32
+	CIDRs    []string
+}
+
+// AWSInstance is an EC2 instance, together with its root volume and elastic IP
+type AWSInstance struct {
+	ID            string
+	Name          string
+	Roles         string
+	AMI           string
+	InstanceType  string
+	KeyPair       string
+	SecurityGroup string
+	VolumeSize    int32
+	VolumeType    string
+	IOPS          int32
+	Throughput    int32
+	// elastic IP, if the instance has a static IP
+	EIPAllocationID  string
+	EIPAssociationID string
+	Tags             map[string]string
+}
+
+// GCPProject holds the GCP resources of a cluster
+type GCPProject struct {
+	Project   string
+	Networks  []GCPNetwork
+	Instances []GCPInstance
+}
+
+// GCPNetwork is a network with its firewall rules
+type GCPNetwork struct {
+	Name      string
+	Firewalls []GCPFirewall
+}
+
+// GCPFirewall is an ingress firewall rule
+type GCPFirewall struct {
+	Name         string
+	SourceRanges []string
+	Allowed      []GCPAllowed
+}
+
+// GCPAllowed is a protocol and ports allowed by a firewall rule
+type GCPAllowed struct {
+	Protocol string
+	Ports    []string
+}
+
+// GCPInstance is a compute instance, together with its boot disk and static IP
+type GCPInstance struct {
+	Name         string
+	Roles        string
+	Zone         string
+	Region       string
+	MachineType  string
+	Image        string
+	DiskSizeGb   int64
+	DiskType     string
+	Network      string
+	SSHKeys      string
+	StaticIPName string
+	Labels       map[string]string
+}
+
+var nonResourceNameChars = regexp.MustCompile("[^a-zA-Z0-9_]")
+
+// resourceName returns a valid terraform resource name based on [name]
+func resourceName(name string) string {
+	name = nonResourceNameChars.ReplaceAllString(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+func quoteList(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, fmt.Sprintf("%q", value))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// hclMap returns the sorted entries of [m], as the body of an HCL map
+func hclMap(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("    %q = %q", key, m[key]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Render returns the terraform configuration reproducing [infra], with import
+// blocks adopting its existing resources
+func Render(infra Infra) ([]byte, error) {
+	mainTemplate, err := configs.ReadFile("configs/main.tf")
+	if err != nil {
+		return nil, err
+	}
+	t, err := template.New("Terraform Config").
+		Funcs(template.FuncMap{
+			"resourceName": resourceName,
+			"quoteList":    quoteList,
+			"hclMap":       hclMap,
+		}).
+		Parse(string(mainTemplate))
+	if err != nil {
+		return nil, err
+	}
+	var config bytes.Buffer
+	if err := t.Execute(&config, infra); err != nil {
+		return nil, err
+	}
+	return config.Bytes(), nil
+}
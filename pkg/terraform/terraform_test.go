@@ -0,0 +1,97 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package terraform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceName(t *testing.T) {
+	require := require.New(t)
+	require.Equal("i_0abc", resourceName("i-0abc"))
+	require.Equal("us_east_1", resourceName("us-east-1"))
+	require.Equal("_1node", resourceName("1node"))
+}
+
+func TestRender(t *testing.T) {
+	require := require.New(t)
+	config, err := Render(Infra{
+		ClusterName: "myCluster",
+		AWSRegions: []AWSRegion{{
+			Region: "us-east-1",
+			SecurityGroups: []AWSSecurityGroup{{
+				ID:          "sg-123",
+				Name:        "metal-us-east-1",
+				Description: "Allow SSH, AVAX HTTP outbound traffic",
+				Rules: []AWSRule{
+					{Protocol: "tcp", FromPort: 9651, ToPort: 9651, CIDRs: []string{"0.0.0.0/0"}},
+				},
+			}},
+			Instances: []AWSInstance{{
+				ID:               "i-0abc",
+				Name:             "myCluster",
+				Roles:            "Validator",
+				AMI:              "ami-1",
+				InstanceType:     "c5.2xlarge",
+				KeyPair:          "kp",
+				SecurityGroup:    "metal-us-east-1",
+				VolumeSize:       1000,
+				VolumeType:       "gp3",
+				IOPS:             3000,
+				Throughput:       125,
+				EIPAllocationID:  "eipalloc-1",
+				EIPAssociationID: "eipassoc-1",
+				Tags:             map[string]string{"Name": "myCluster", "Managed-By": "avalanche-cli"},
+			}},
+		}},
+		GCP: &GCPProject{
+			Project: "proj",
+			Networks: []GCPNetwork{{
+				Name: "metal-network",
+				Firewalls: []GCPFirewall{{
+					Name:         "metal-network-default",
+					SourceRanges: []string{"0.0.0.0/0"},
+					Allowed:      []GCPAllowed{{Protocol: "tcp", Ports: []string{"9651", "23101"}}},
+				}},
+			}},
+			Instances: []GCPInstance{{
+				Name:         "metal-node-0",
+				Roles:        "API",
+				Zone:         "us-east1-b",
+				Region:       "us-east1",
+				MachineType:  "e2-standard-8",
+				Image:        "projects/ubuntu-os-cloud/global/images/ubuntu-2004",
+				DiskSizeGb:   1000,
+				Network:      "metal-network",
+				StaticIPName: "static-ip-node-0",
+			}},
+		},
+	})
+	require.NoError(err)
+	tf := string(config)
+	for _, expected := range []string{
+		`source = "hashicorp/aws"`,
+		`source = "hashicorp/google"`,
+		`alias  = "us_east_1"`,
+		`resource "aws_security_group" "metal_us_east_1" {`,
+		`cidr_blocks = ["0.0.0.0/0"]`,
+		`resource "aws_instance" "i_0abc" {`,
+		`vpc_security_group_ids = [aws_security_group.metal_us_east_1.id]`,
+		`iops                  = 3000`,
+		`"Managed-By" = "avalanche-cli"`,
+		`id       = "eipalloc-1"`,
+		`id       = "eipassoc-1"`,
+		`ports    = ["9651", "23101"]`,
+		`nat_ip = google_compute_address.static_ip_node_0.address`,
+		`id = "projects/proj/zones/us-east1-b/instances/metal-node-0"`,
+		`id = "projects/proj/regions/us-east1/addresses/static-ip-node-0"`,
+	} {
+		require.Contains(tf, expected)
+	}
+	require.Equal(4, strings.Count(tf, "\nimport {\n  provider = aws.us_east_1"))
+	// balanced blocks
+	require.Equal(strings.Count(tf, "{"), strings.Count(tf, "}"))
+}
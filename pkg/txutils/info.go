@@ -77,6 +77,40 @@ func GetLedgerDisplayName(tx *txs.Tx) string {
 	}
 }
 
+// GetKeyRole returns the key role (see models.KeyRoles) of the subnet operation of [tx]
+func GetKeyRole(tx *txs.Tx) string {
+	switch tx.Unsigned.(type) {
+	case *txs.AddSubnetValidatorTx, *txs.RemoveSubnetValidatorTx, *txs.AddPermissionlessValidatorTx:
+		return models.KeyRoleValidatorManager
+	default:
+		return models.KeyRoleDeployer
+	}
+}
+
+// GetTxTypeName returns a short human readable name for the tx type
+func GetTxTypeName(tx *txs.Tx) string {
+	switch tx.Unsigned.(type) {
+	case *txs.CreateSubnetTx:
+		return "CreateSubnet"
+	case *txs.CreateChainTx:
+		return "CreateChain"
+	case *txs.AddSubnetValidatorTx:
+		return "AddSubnetValidator"
+	case *txs.RemoveSubnetValidatorTx:
+		return "RemoveSubnetValidator"
+	case *txs.TransformSubnetTx:
+		return "TransformSubnet"
+	case *txs.TransferSubnetOwnershipTx:
+		return "TransferSubnetOwnership"
+	case *txs.AddPermissionlessValidatorTx:
+		return "AddPermissionlessValidator"
+	case *txs.AddPermissionlessDelegatorTx:
+		return "AddPermissionlessDelegator"
+	default:
+		return fmt.Sprintf("%T", tx.Unsigned)
+	}
+}
+
 func IsCreateChainTx(tx *txs.Tx) bool {
 	_, ok := tx.Unsigned.(*txs.CreateChainTx)
 	return ok
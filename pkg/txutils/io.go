@@ -3,27 +3,45 @@
 package txutils
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 
+	"github.com/MetalBlockchain/metalgo/ids"
 	"github.com/MetalBlockchain/metalgo/utils/formatting"
 	"github.com/MetalBlockchain/metalgo/vms/platformvm/txs"
 )
 
-// saves a given [tx] to [txPath]
-func SaveToDisk(tx *txs.Tx, txPath string, forceOverwrite bool) error {
+// OfflineSigningInfo holds the P-Chain state needed to sign a subnet tx
+// on a machine without network access (eg air-gapped)
+type OfflineSigningInfo struct {
+	SubnetID    ids.ID   `json:"subnetID"`
+	ControlKeys []string `json:"controlKeys"`
+	Threshold   uint32   `json:"threshold"`
+}
+
+// txFile is the JSON layout used to save a tx together with its offline signing info
+type txFile struct {
+	Tx          string              `json:"tx"`
+	SigningInfo *OfflineSigningInfo `json:"signingInfo,omitempty"`
+}
+
+func encodeTx(tx *txs.Tx) (string, error) {
 	// Serialize the signed tx
 	txBytes, err := txs.Codec.Marshal(txs.CodecVersion, tx)
 	if err != nil {
-		return fmt.Errorf("couldn't marshal signed tx: %w", err)
+		return "", fmt.Errorf("couldn't marshal signed tx: %w", err)
 	}
-
 	// Get the encoded (in hex + checksum) signed tx
 	txStr, err := formatting.Encode(formatting.Hex, txBytes)
 	if err != nil {
-		return fmt.Errorf("couldn't encode signed tx: %w", err)
+		return "", fmt.Errorf("couldn't encode signed tx: %w", err)
 	}
-	// save
+	return txStr, nil
+}
+
+func writeTxFile(txPath string, content []byte, forceOverwrite bool) error {
 	if _, err := os.Stat(txPath); err == nil && !forceOverwrite {
 		return fmt.Errorf("couldn't create file to write tx to: file exists")
 	}
@@ -32,29 +50,77 @@ func SaveToDisk(tx *txs.Tx, txPath string, forceOverwrite bool) error {
 		return fmt.Errorf("couldn't create file to write tx to: %w", err)
 	}
 	defer f.Close()
-	_, err = f.WriteString(txStr)
-	if err != nil {
+	if _, err := f.Write(content); err != nil {
 		return fmt.Errorf("couldn't write tx into file: %w", err)
 	}
 	return nil
 }
 
+// saves a given [tx] to [txPath]
+func SaveToDisk(tx *txs.Tx, txPath string, forceOverwrite bool) error {
+	txStr, err := encodeTx(tx)
+	if err != nil {
+		return err
+	}
+	return writeTxFile(txPath, []byte(txStr), forceOverwrite)
+}
+
+// saves a given [tx] to [txPath], as JSON, together with the [signingInfo]
+// needed to sign it offline
+func SaveToDiskWithSigningInfo(
+	tx *txs.Tx,
+	signingInfo *OfflineSigningInfo,
+	txPath string,
+	forceOverwrite bool,
+) error {
+	txStr, err := encodeTx(tx)
+	if err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(txFile{
+		Tx:          txStr,
+		SigningInfo: signingInfo,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("couldn't marshal tx file: %w", err)
+	}
+	return writeTxFile(txPath, content, forceOverwrite)
+}
+
 // loads a tx from [txPath]
 func LoadFromDisk(txPath string) (*txs.Tx, error) {
+	tx, _, err := LoadFromDiskWithSigningInfo(txPath)
+	return tx, err
+}
+
+// loads a tx from [txPath], together with its offline signing info if present.
+// Both the JSON layout and the plain hex layout are accepted
+func LoadFromDiskWithSigningInfo(txPath string) (*txs.Tx, *OfflineSigningInfo, error) {
 	txEncodedBytes, err := os.ReadFile(txPath)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	txEncodedBytes = bytes.TrimSpace(txEncodedBytes)
+	txStr := string(txEncodedBytes)
+	var signingInfo *OfflineSigningInfo
+	if bytes.HasPrefix(txEncodedBytes, []byte("{")) {
+		var f txFile
+		if err := json.Unmarshal(txEncodedBytes, &f); err != nil {
+			return nil, nil, fmt.Errorf("couldn't parse tx file: %w", err)
+		}
+		txStr = f.Tx
+		signingInfo = f.SigningInfo
 	}
-	txBytes, err := formatting.Decode(formatting.Hex, string(txEncodedBytes))
+	txBytes, err := formatting.Decode(formatting.Hex, txStr)
 	if err != nil {
-		return nil, fmt.Errorf("couldn't decode signed tx: %w", err)
+		return nil, nil, fmt.Errorf("couldn't decode signed tx: %w", err)
 	}
 	var tx txs.Tx
 	if _, err := txs.Codec.Unmarshal(txBytes, &tx); err != nil {
-		return nil, fmt.Errorf("error unmarshaling signed tx: %w", err)
+		return nil, nil, fmt.Errorf("error unmarshaling signed tx: %w", err)
 	}
 	if err := tx.Initialize(txs.Codec); err != nil {
-		return nil, fmt.Errorf("error initializing signed tx: %w", err)
+		return nil, nil, fmt.Errorf("error initializing signed tx: %w", err)
 	}
-	return &tx, nil
+	return &tx, signingInfo, nil
 }
@@ -0,0 +1,70 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package txutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	avagoconstants "github.com/MetalBlockchain/metalgo/utils/constants"
+	"github.com/MetalBlockchain/metalgo/vms/components/avax"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/txs"
+	"github.com/MetalBlockchain/metalgo/vms/secp256k1fx"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTx(t *testing.T) *txs.Tx {
+	tx := &txs.Tx{
+		Unsigned: &txs.AddSubnetValidatorTx{
+			BaseTx: txs.BaseTx{BaseTx: avax.BaseTx{
+				NetworkID:    avagoconstants.TahoeID,
+				BlockchainID: avagoconstants.PlatformChainID,
+			}},
+			SubnetValidator: txs.SubnetValidator{
+				Subnet: ids.GenerateTestID(),
+			},
+			SubnetAuth: &secp256k1fx.Input{SigIndices: []uint32{0}},
+		},
+	}
+	require.NoError(t, tx.Initialize(txs.Codec))
+	return tx
+}
+
+func TestSaveLoadTx(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	tx := newTestTx(t)
+
+	// plain hex layout, no signing info
+	hexPath := filepath.Join(dir, "tx.hex")
+	require.NoError(SaveToDisk(tx, hexPath, false))
+	loadedTx, signingInfo, err := LoadFromDiskWithSigningInfo(hexPath)
+	require.NoError(err)
+	require.Nil(signingInfo)
+	require.Equal(tx.ID(), loadedTx.ID())
+	require.Error(SaveToDisk(tx, hexPath, false))
+
+	// JSON layout, with signing info
+	jsonPath := filepath.Join(dir, "tx.json")
+	expectedInfo := &OfflineSigningInfo{
+		SubnetID:    ids.GenerateTestID(),
+		ControlKeys: []string{"P-tahoe1wq7m7jeqn0ex4wnymfxyqwlq0dtd5u0tcnuxcf"},
+		Threshold:   1,
+	}
+	require.NoError(SaveToDiskWithSigningInfo(tx, expectedInfo, jsonPath, false))
+	loadedTx, signingInfo, err = LoadFromDiskWithSigningInfo(jsonPath)
+	require.NoError(err)
+	require.Equal(expectedInfo, signingInfo)
+	require.Equal(tx.ID(), loadedTx.ID())
+	loadedTx, err = LoadFromDisk(jsonPath)
+	require.NoError(err)
+	require.Equal(tx.ID(), loadedTx.ID())
+
+	// malformed JSON
+	badPath := filepath.Join(dir, "bad.json")
+	require.NoError(os.WriteFile(badPath, []byte("{\"tx\":"), 0o600))
+	_, _, err = LoadFromDiskWithSigningInfo(badPath)
+	require.Error(err)
+}
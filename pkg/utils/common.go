@@ -6,6 +6,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"math/rand"
@@ -244,6 +245,14 @@ func RandomString(length int) string {
 	return string(result)
 }
 
+// ValidatePercentage returns an error if [value] is not a percentage between 1 and 100
+func ValidatePercentage(value int) error {
+	if value < 1 || value > 100 {
+		return fmt.Errorf("must be between 1 and 100")
+	}
+	return nil
+}
+
 // Sum calculates the sum of all the elements in the given slice of integers.
 func Sum(s []int) int {
 	sum := 0
@@ -331,14 +340,19 @@ func GetGitCommit(gitRepoURL string) string {
 // ReadLongString reads a long string from the user input.
 func ReadLongString(msg string, args ...interface{}) (string, error) {
 	fmt.Println(fmt.Sprintf(msg, args...))
-	reader := bufio.NewReader(os.Stdin)
-	longString, err := reader.ReadString('\n')
-	if err != nil {
+	return readLine(bufio.NewReader(os.Stdin))
+}
+
+// readLine reads a line from [reader], without its line ending. Input that ends
+// without a newline, as piped from a file without a trailing one, is a full line
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
 		return "", err
 	}
 	// Remove newline character at the end
-	longString = strings.TrimSuffix(longString, "\n")
-	return longString, nil
+	line = strings.TrimSuffix(line, "\n")
+	return strings.TrimSuffix(line, "\r"), nil
 }
 
 func SupportedAvagoArch() []string {
@@ -3,7 +3,11 @@
 package utils
 
 import (
+	"bufio"
+	"errors"
+	"io"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -171,3 +175,30 @@ func TestAddSingleQuotes(t *testing.T) {
 		t.Errorf("AddSingleQuotes(%v) = %v, expected %v", input, output, expected)
 	}
 }
+
+func TestReadLine(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"private key\n", "private key"},
+		{"private key\r\n", "private key"},
+		// piped input without a trailing newline
+		{"private key", "private key"},
+		{"first\nsecond\n", "first"},
+	}
+
+	for _, test := range tests {
+		result, err := readLine(bufio.NewReader(strings.NewReader(test.input)))
+		if err != nil {
+			t.Errorf("readLine(%q) failed: %s", test.input, err)
+		}
+		if result != test.expected {
+			t.Errorf("readLine(%q) = %q, expected %q", test.input, result, test.expected)
+		}
+	}
+
+	if _, err := readLine(bufio.NewReader(strings.NewReader(""))); !errors.Is(err, io.EOF) {
+		t.Errorf("readLine of empty input returned %v, expected EOF", err)
+	}
+}
@@ -133,6 +133,16 @@ func StopDockerCompose(filePath string) error {
 	return cmd.Run()
 }
 
+// RemoveDockerCompose stops the Docker Compose services defined in the specified file,
+// and removes their volumes.
+func RemoveDockerCompose(filePath string) error {
+	cmd := exec.Command("docker-compose", "-f", filePath, "down", "--volumes")
+	cmd.Env = os.Environ()
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
 // GenerateDockerHostIDs generates a list of Docker host IDs.
 func GenerateDockerHostIDs(numNodes int) []string {
 	var ids []string
@@ -0,0 +1,111 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package utils
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/MetalBlockchain/metalgo/utils/logging"
+	"go.uber.org/zap"
+)
+
+// max number of body bytes logged for each request and response
+const maxTracedBodySize = 4096
+
+const redacted = "<redacted>"
+
+var (
+	privateKeyRegex  = regexp.MustCompile(`PrivateKey-[1-9A-HJ-NP-Za-km-z]+`)
+	secretFieldRegex = regexp.MustCompile(`(?i)("(?:private_?key|password|mnemonic|secret|seed)"\s*:\s*)"[^"]*"`)
+)
+
+// EnableHTTPTracing makes all the HTTP requests done with the default transport,
+// that is, the ones of the RPC clients, downloads and other API calls, to be
+// logged into [log] at debug level, together with their responses
+func EnableHTTPTracing(log logging.Logger) {
+	if _, ok := http.DefaultTransport.(*tracingTransport); ok {
+		return
+	}
+	http.DefaultTransport = &tracingTransport{
+		base: http.DefaultTransport,
+		log:  log,
+	}
+}
+
+type tracingTransport struct {
+	base http.RoundTripper
+	log  logging.Logger
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fields := []zap.Field{
+		zap.String("method", req.Method),
+		zap.String("url", req.URL.Redacted()),
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		// round trippers must not modify the given request
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		fields = append(fields, zap.String("body", traceBody(body)))
+	}
+	t.log.Debug("http request", fields...)
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	fields = []zap.Field{
+		zap.String("method", req.Method),
+		zap.String("url", req.URL.Redacted()),
+		zap.Duration("duration", time.Since(start)),
+	}
+	if err != nil {
+		t.log.Debug("http request failed", append(fields, zap.Error(err))...)
+		return resp, err
+	}
+	fields = append(fields, zap.Int("status", resp.StatusCode), zap.Int64("contentLength", resp.ContentLength))
+	if isTextContent(resp.Header.Get("Content-Type")) {
+		// only the logged prefix is read here, the rest is left to the caller
+		prefix, err := io.ReadAll(io.LimitReader(resp.Body, maxTracedBodySize))
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = readCloser{
+			Reader: io.MultiReader(bytes.NewReader(prefix), resp.Body),
+			Closer: resp.Body,
+		}
+		fields = append(fields, zap.String("body", traceBody(prefix)))
+	}
+	t.log.Debug("http response", fields...)
+	return resp, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func isTextContent(contentType string) bool {
+	return strings.Contains(contentType, "json") || strings.HasPrefix(contentType, "text/")
+}
+
+func traceBody(body []byte) string {
+	s := string(body)
+	if len(s) > maxTracedBodySize {
+		s = s[:maxTracedBodySize] + "..."
+	}
+	return RedactSecrets(s)
+}
+
+// RedactSecrets replaces the private keys and other secrets found in [s]
+func RedactSecrets(s string) string {
+	s = privateKeyRegex.ReplaceAllString(s, "PrivateKey-"+redacted)
+	return secretFieldRegex.ReplaceAllString(s, `${1}"`+redacted+`"`)
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package utils
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MetalBlockchain/metalgo/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+func TestRedactSecrets(t *testing.T) {
+	require := require.New(t)
+	require.Equal(
+		`{"key": "PrivateKey-<redacted>", "address": "P-tahoe1abc"}`,
+		RedactSecrets(`{"key": "PrivateKey-ewoqjP7PxY4yr3iLTpLisriqt94hdyDFNgchSxGGztUrTXtNN", "address": "P-tahoe1abc"}`),
+	)
+	require.Equal(
+		`{"privateKey":"<redacted>","password": "<redacted>","txID":"abc"}`,
+		RedactSecrets(`{"privateKey":"56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d8027","password": "pass","txID":"abc"}`),
+	)
+}
+
+func TestTracingTransport(t *testing.T) {
+	require := require.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"result":` + string(body) + `}`))
+	}))
+	defer server.Close()
+
+	logs := &bytes.Buffer{}
+	log := logging.NewLogger("", logging.NewWrappedCore(logging.Debug, nopCloser{logs}, logging.Plain.ConsoleEncoder()))
+	client := &http.Client{Transport: &tracingTransport{base: http.DefaultTransport, log: log}}
+
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"privateKey":"secret"}`))
+	require.NoError(err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(err)
+	// the traced request and response are not altered
+	require.Equal(`{"result":{"privateKey":"secret"}}`, string(body))
+
+	require.Contains(logs.String(), "http request")
+	require.Contains(logs.String(), "http response")
+	require.Contains(logs.String(), "<redacted>")
+	require.NotContains(logs.String(), "secret")
+}
@@ -0,0 +1,21 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package utils
+
+import "errors"
+
+var ErrOffline = errors.New("network access is disabled on offline mode")
+
+var offline bool
+
+// SetOffline enables or disables the offline mode. On offline mode, commands use
+// the previously downloaded binaries and release information, and never attempt
+// network calls
+func SetOffline(enabled bool) {
+	offline = enabled
+}
+
+// IsOffline returns true if network calls must not be attempted
+func IsOffline() bool {
+	return offline
+}
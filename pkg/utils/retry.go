@@ -0,0 +1,33 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package utils
+
+import (
+	"time"
+)
+
+// RetryWithExponentialBackoff calls [fn] up to [maxAttempts] times, while it fails
+// with an error for which [isRetryable] returns true. The wait between attempts
+// starts at [initialBackoff] and is doubled after each failure.
+// Returns the result of the last attempt
+func RetryWithExponentialBackoff[T any](
+	fn func() (T, error),
+	maxAttempts int,
+	initialBackoff time.Duration,
+	isRetryable func(error) bool,
+) (T, error) {
+	var (
+		result T
+		err    error
+	)
+	backoff := initialBackoff
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		result, err = fn()
+		if err == nil || !isRetryable(err) || attempt == maxAttempts {
+			break
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+	return result, err
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryWithExponentialBackoff(t *testing.T) {
+	errTransient := errors.New("transient")
+	errFatal := errors.New("fatal")
+	isRetryable := func(err error) bool { return errors.Is(err, errTransient) }
+
+	tests := []struct {
+		name             string
+		errs             []error
+		expectedErr      error
+		expectedAttempts int
+	}{
+		{name: "success", errs: []error{nil}, expectedAttempts: 1},
+		{name: "success after retries", errs: []error{errTransient, errTransient, nil}, expectedAttempts: 3},
+		{name: "non retryable", errs: []error{errFatal}, expectedErr: errFatal, expectedAttempts: 1},
+		{name: "attempts exhausted", errs: []error{errTransient, errTransient, errTransient, nil}, expectedErr: errTransient, expectedAttempts: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			result, err := RetryWithExponentialBackoff(func() (int, error) {
+				err := tt.errs[attempts]
+				attempts++
+				return attempts, err
+			}, 3, time.Millisecond, isRetryable)
+			require.ErrorIs(t, err, tt.expectedErr)
+			require.Equal(t, tt.expectedAttempts, attempts)
+			require.Equal(t, tt.expectedAttempts, result)
+		})
+	}
+}
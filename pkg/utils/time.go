@@ -0,0 +1,85 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	// embeds the time zone database, for systems without one
+	_ "time/tzdata"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+)
+
+// ParseStartTime parses a staking start time, either as an absolute time in
+// [constants.TimeParseLayout] format, taken in [loc] (UTC if nil), as an RFC3339
+// time with offset (eg "2024-05-02T14:30:00+02:00"), or as a duration relative
+// to [now] (eg "10m", "in 2h", "+1h30m"). The result is always in UTC
+func ParseStartTime(s string, now time.Time, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	s = strings.TrimSpace(s)
+	if t, err := time.ParseInLocation(constants.TimeParseLayout, s, loc); err == nil {
+		return t.UTC(), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	relative := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(s, "in "), "+"))
+	d, err := time.ParseDuration(relative)
+	if err != nil {
+		return time.Time{}, fmt.Errorf(
+			"invalid start time %q: expected 'YYYY-MM-DD HH:MM:SS' %s time, RFC3339 time such as '2024-05-02T14:30:00+02:00', or relative duration such as '10m' or 'in 2h'",
+			s,
+			loc,
+		)
+	}
+	if d <= 0 {
+		return time.Time{}, fmt.Errorf("invalid start time %q: relative duration must be positive", s)
+	}
+	return now.Add(d).UTC(), nil
+}
+
+// LoadTimeZone returns the location of the IANA time zone [name] (eg "Europe/Paris"),
+// the system one for "local", or UTC if [name] is empty
+func LoadTimeZone(name string) (*time.Location, error) {
+	switch strings.ToLower(name) {
+	case "", "utc":
+		return time.UTC, nil
+	case "local":
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q, expected an IANA name such as Europe/Paris, or local: %w", name, err)
+	}
+	return loc, nil
+}
+
+// FormatTimeWithUTC formats [t] in [loc] (the system time zone if nil), followed
+// by its UTC time if it differs, eg "2024-05-02 14:30:00 CEST (2024-05-02 12:30:00 UTC)"
+func FormatTimeWithUTC(t time.Time, loc *time.Location) string {
+	if loc == nil {
+		loc = time.Local
+	}
+	utc := t.UTC().Format(constants.TimeParseLayout) + " UTC"
+	local := t.In(loc)
+	if name, offset := local.Zone(); name == "UTC" && offset == 0 {
+		return utc
+	}
+	return fmt.Sprintf("%s (%s)", local.Format(constants.TimeParseLayout+" MST"), utc)
+}
+
+// CheckStartTimeLeadTime returns an error if [start] is less than [minLeadTime] after [now]
+func CheckStartTimeLeadTime(start time.Time, now time.Time, minLeadTime time.Duration) error {
+	if start.Before(now.Add(minLeadTime)) {
+		return fmt.Errorf(
+			"start time %s should be at least %s in the future",
+			FormatTimeWithUTC(start, nil),
+			minLeadTime,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStartTime(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	tests := []struct {
+		input       string
+		loc         *time.Location
+		expected    time.Time
+		expectedErr bool
+	}{
+		{input: "2024-05-02 12:30:00", expected: time.Date(2024, 5, 2, 12, 30, 0, 0, time.UTC)},
+		{input: "2024-05-02 12:30:00", loc: paris, expected: time.Date(2024, 5, 2, 10, 30, 0, 0, time.UTC)},
+		{input: "2024-05-02T14:30:00+02:00", expected: time.Date(2024, 5, 2, 12, 30, 0, 0, time.UTC)},
+		{input: "2024-05-02T12:30:00Z", loc: paris, expected: time.Date(2024, 5, 2, 12, 30, 0, 0, time.UTC)},
+		{input: "10m", loc: paris, expected: now.Add(10 * time.Minute)},
+		{input: "10m", expected: now.Add(10 * time.Minute)},
+		{input: "in 2h", expected: now.Add(2 * time.Hour)},
+		{input: "+1h30m", expected: now.Add(90 * time.Minute)},
+		{input: " in 45s ", expected: now.Add(45 * time.Second)},
+		{input: "-10m", expectedErr: true},
+		{input: "0s", expectedErr: true},
+		{input: "tomorrow", expectedErr: true},
+		{input: "2024-05-02", expectedErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			start, err := ParseStartTime(tt.input, now, tt.loc)
+			if tt.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, start)
+		})
+	}
+}
+
+func TestLoadTimeZone(t *testing.T) {
+	for _, name := range []string{"", "UTC", "utc"} {
+		loc, err := LoadTimeZone(name)
+		require.NoError(t, err)
+		require.Equal(t, time.UTC, loc)
+	}
+	loc, err := LoadTimeZone("local")
+	require.NoError(t, err)
+	require.Equal(t, time.Local, loc)
+	loc, err = LoadTimeZone("America/New_York")
+	require.NoError(t, err)
+	require.Equal(t, "America/New_York", loc.String())
+	_, err = LoadTimeZone("Mars/Olympus_Mons")
+	require.Error(t, err)
+}
+
+func TestFormatTimeWithUTC(t *testing.T) {
+	start := time.Date(2024, 5, 2, 12, 30, 0, 0, time.UTC)
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	require.Equal(t, "2024-05-02 14:30:00 CEST (2024-05-02 12:30:00 UTC)", FormatTimeWithUTC(start, paris))
+	require.Equal(t, "2024-05-02 12:30:00 UTC", FormatTimeWithUTC(start, time.UTC))
+}
+
+func TestCheckStartTimeLeadTime(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, CheckStartTimeLeadTime(now.Add(time.Minute), now, 30*time.Second))
+	require.Error(t, CheckStartTimeLeadTime(now.Add(10*time.Second), now, 30*time.Second))
+}
@@ -0,0 +1,124 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package ux
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// OutputEnvVarName selects the output format when set to JSONOutputFormat,
+// same as passing --json
+const (
+	OutputEnvVarName = "METAL_OUTPUT"
+	JSONOutputFormat = "json"
+)
+
+// ErrorCode is a stable, machine readable identifier of an error kind,
+// included in the JSON output of failed commands
+type ErrorCode string
+
+const (
+	ErrCodeGeneric              ErrorCode = "ERROR"
+	ErrCodeInvalidArguments     ErrorCode = "INVALID_ARGUMENTS"
+	ErrCodeNotFound             ErrorCode = "NOT_FOUND"
+	ErrCodeNetworkNotRunning    ErrorCode = "NETWORK_NOT_RUNNING"
+	ErrCodeNetworkUnhealthy     ErrorCode = "NETWORK_UNHEALTHY"
+	ErrCodeIncompatibleVersions ErrorCode = "INCOMPATIBLE_VERSIONS"
+	ErrCodeTimeout              ErrorCode = "TIMEOUT"
+)
+
+var jsonOutput bool
+
+// SetJSONOutput enables or disables the JSON output mode. On JSON mode, command
+// results are printed as JSON to stdout, while messages to the user go to stderr
+func SetJSONOutput(enabled bool) {
+	jsonOutput = enabled
+	if Logger != nil {
+		if enabled {
+			Logger.Writer = os.Stderr
+		} else {
+			Logger.Writer = os.Stdout
+		}
+	}
+}
+
+// JSONOutput returns true if command results must be printed as JSON
+func JSONOutput() bool {
+	return jsonOutput
+}
+
+// JSONOutputFromEnv returns true if the env selects the JSON output mode
+func JSONOutputFromEnv() bool {
+	return os.Getenv(OutputEnvVarName) == JSONOutputFormat
+}
+
+// PrintResult prints the result of a command as indented JSON to stdout
+func PrintResult(result interface{}) error {
+	return printJSON(os.Stdout, result)
+}
+
+// PrintError prints err as a JSON error object to stdout, including its code
+func PrintError(err error) {
+	errObj := struct {
+		Error jsonError `json:"error"`
+	}{
+		Error: jsonError{
+			Code:    GetErrorCode(err),
+			Message: err.Error(),
+		},
+	}
+	if err := printJSON(os.Stdout, errObj); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+type jsonError struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+}
+
+func printJSON(w io.Writer, v interface{}) error {
+	bs, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(bs))
+	return err
+}
+
+// CodedError is an error with an associated error code
+type CodedError struct {
+	Code ErrorCode
+	Err  error
+}
+
+// NewCodedError associates code to err
+func NewCodedError(code ErrorCode, err error) error {
+	return &CodedError{Code: code, Err: err}
+}
+
+func (e *CodedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CodedError) Unwrap() error {
+	return e.Err
+}
+
+func (e *CodedError) ErrorCode() ErrorCode {
+	return e.Code
+}
+
+// GetErrorCode returns the code of the first error in err's chain that has one,
+// or ErrCodeGeneric
+func GetErrorCode(err error) ErrorCode {
+	var coded interface{ ErrorCode() ErrorCode }
+	if errors.As(err, &coded) {
+		return coded.ErrorCode()
+	}
+	return ErrCodeGeneric
+}
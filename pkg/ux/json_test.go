@@ -0,0 +1,37 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package ux
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetErrorCode(t *testing.T) {
+	require := require.New(t)
+
+	baseErr := errors.New("no network")
+	require.Equal(ErrCodeGeneric, GetErrorCode(baseErr))
+
+	codedErr := NewCodedError(ErrCodeNetworkNotRunning, baseErr)
+	require.Equal(ErrCodeNetworkNotRunning, GetErrorCode(codedErr))
+	require.ErrorIs(codedErr, baseErr)
+	require.Equal(baseErr.Error(), codedErr.Error())
+
+	// the code is kept when the error is wrapped
+	wrappedErr := fmt.Errorf("failed to deploy: %w", codedErr)
+	require.Equal(ErrCodeNetworkNotRunning, GetErrorCode(wrappedErr))
+}
+
+func TestPrintJSON(t *testing.T) {
+	require := require.New(t)
+
+	var buf bytes.Buffer
+	err := printJSON(&buf, jsonError{Code: ErrCodeNotFound, Message: "local network not found"})
+	require.NoError(err)
+	require.Equal("{\n  \"code\": \"NOT_FOUND\",\n  \"message\": \"local network not found\"\n}\n", buf.String())
+}
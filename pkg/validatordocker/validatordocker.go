@@ -0,0 +1,154 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatordocker
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metalgo/config"
+	"github.com/MetalBlockchain/metalgo/ids"
+)
+
+const (
+	// Image is the metalgo image the validator image is based on
+	Image = "metalblockchain/metalgo"
+
+	DefaultHTTPPort    = 9650
+	DefaultStakingPort = 9651
+
+	// PluginsDir is the dir, relative to the output dir, the VM binary must be
+	// written into before building the image
+	PluginsDir = "plugins"
+
+	configsDir         = "configs"
+	nodeConfigFileName = "node.json"
+)
+
+//go:embed configs/*
+var configs embed.FS
+
+// Config describes the validator packaged by the generated files
+type Config struct {
+	SubnetName string
+	// MetalGoVersion is the tag of the base image
+	MetalGoVersion string
+	// NetworkID is the value of the metalgo network-id flag
+	NetworkID    string
+	SubnetID     ids.ID
+	BlockchainID ids.ID
+	HTTPPort     int
+	StakingPort  int
+	// optional subnet files, as kept by the CLI
+	NodeConfig      []byte
+	ChainConfig     []byte
+	SubnetConfig    []byte
+	NetworkUpgrades []byte
+}
+
+type templateInputs struct {
+	Config
+	Image       string
+	ProjectName string
+}
+
+// WriteFiles writes into [dir] a Dockerfile building a validator image for the
+// subnet described by [conf], a docker compose file running it, and the node,
+// subnet and chain configs copied into the image. The VM binary must be
+// written into the PluginsDir subdir
+func WriteFiles(dir string, conf Config) error {
+	nodeConfig, err := NodeConfig(conf)
+	if err != nil {
+		return err
+	}
+	files := map[string][]byte{
+		filepath.Join(configsDir, nodeConfigFileName): nodeConfig,
+	}
+	if len(conf.SubnetConfig) > 0 {
+		files[filepath.Join(configsDir, "subnets", conf.SubnetID.String()+".json")] = conf.SubnetConfig
+	}
+	if len(conf.ChainConfig) > 0 {
+		files[filepath.Join(configsDir, "chains", conf.BlockchainID.String(), "config.json")] = conf.ChainConfig
+	}
+	if len(conf.NetworkUpgrades) > 0 {
+		files[filepath.Join(configsDir, "chains", conf.BlockchainID.String(), "upgrade.json")] = conf.NetworkUpgrades
+	}
+	inputs := templateInputs{
+		Config:      conf,
+		Image:       Image + ":" + conf.MetalGoVersion,
+		ProjectName: strings.ReplaceAll(strings.ToLower("metal-validator-"+conf.SubnetName), " ", "-"),
+	}
+	for templateName, fileName := range map[string]string{
+		"Dockerfile":  "Dockerfile",
+		"compose.yml": constants.ValidatorComposeFileName,
+	} {
+		fileTemplate, err := configs.ReadFile("configs/" + templateName)
+		if err != nil {
+			return err
+		}
+		t, err := template.New(templateName).Parse(string(fileTemplate))
+		if err != nil {
+			return err
+		}
+		var file bytes.Buffer
+		if err := t.Execute(&file, inputs); err != nil {
+			return err
+		}
+		files[fileName] = file.Bytes()
+	}
+	if err := os.MkdirAll(filepath.Join(dir, PluginsDir), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	for path, content := range files {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, content, constants.WriteReadReadPerms); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NodeConfig returns the metalgo config of the validator: the node config of
+// the subnet, if any, tracking the subnet and pointing to the dirs of the image
+func NodeConfig(conf Config) ([]byte, error) {
+	nodeConfig := map[string]interface{}{}
+	if len(conf.NodeConfig) > 0 {
+		if err := json.Unmarshal(conf.NodeConfig, &nodeConfig); err != nil {
+			return nil, fmt.Errorf("invalid node config of subnet %s: %w", conf.SubnetName, err)
+		}
+	}
+	trackedSubnets := []string{}
+	if prev, ok := nodeConfig[config.TrackSubnetsKey].(string); ok {
+		for _, subnetID := range strings.Split(prev, ",") {
+			if subnetID = strings.TrimSpace(subnetID); subnetID != "" && subnetID != conf.SubnetID.String() {
+				trackedSubnets = append(trackedSubnets, subnetID)
+			}
+		}
+	}
+	trackedSubnets = append(trackedSubnets, conf.SubnetID.String())
+	nodeConfig[config.TrackSubnetsKey] = strings.Join(trackedSubnets, ",")
+	nodeConfig[config.NetworkNameKey] = conf.NetworkID
+	nodeConfig[config.PluginDirKey] = "/" + PluginsDir
+	nodeConfig[config.ChainConfigDirKey] = "/" + configsDir + "/chains"
+	nodeConfig[config.SubnetConfigDirKey] = "/" + configsDir + "/subnets"
+	nodeConfig[config.HTTPPortKey] = conf.HTTPPort
+	nodeConfig[config.StakingPortKey] = conf.StakingPort
+	// the API must listen on the container address to be published
+	nodeConfig[config.HTTPHostKey] = "0.0.0.0"
+	if _, ok := nodeConfig[config.PublicIPKey]; !ok {
+		if _, ok := nodeConfig[config.PublicIPResolutionServiceKey]; !ok {
+			nodeConfig[config.PublicIPResolutionServiceKey] = "opendns"
+		}
+	}
+	return json.MarshalIndent(nodeConfig, "", "  ")
+}
@@ -0,0 +1,82 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatordocker
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeConfig(t *testing.T) {
+	require := require.New(t)
+	subnetID := ids.GenerateTestID()
+	conf := Config{
+		SubnetName:  "mySubnet",
+		NetworkID:   "tahoe",
+		SubnetID:    subnetID,
+		HTTPPort:    DefaultHTTPPort,
+		StakingPort: DefaultStakingPort,
+		NodeConfig:  []byte(`{"track-subnets": "otherSubnet,` + subnetID.String() + `", "public-ip": "1.2.3.4", "log-level": "debug"}`),
+	}
+	bs, err := NodeConfig(conf)
+	require.NoError(err)
+	nodeConfig := map[string]interface{}{}
+	require.NoError(json.Unmarshal(bs, &nodeConfig))
+	require.Equal("otherSubnet,"+subnetID.String(), nodeConfig["track-subnets"])
+	require.Equal("tahoe", nodeConfig["network-id"])
+	require.Equal("/plugins", nodeConfig["plugin-dir"])
+	require.Equal("0.0.0.0", nodeConfig["http-host"])
+	require.Equal(float64(9651), nodeConfig["staking-port"])
+	require.Equal("debug", nodeConfig["log-level"])
+	require.NotContains(nodeConfig, "public-ip-resolution-service")
+
+	conf.NodeConfig = nil
+	bs, err = NodeConfig(conf)
+	require.NoError(err)
+	require.NoError(json.Unmarshal(bs, &nodeConfig))
+	require.Equal("opendns", nodeConfig["public-ip-resolution-service"])
+
+	conf.NodeConfig = []byte("{")
+	_, err = NodeConfig(conf)
+	require.ErrorContains(err, "invalid node config of subnet mySubnet")
+}
+
+func TestWriteFiles(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	conf := Config{
+		SubnetName:     "My Subnet",
+		MetalGoVersion: "v1.11.0",
+		NetworkID:      "mainnet",
+		SubnetID:       ids.GenerateTestID(),
+		BlockchainID:   ids.GenerateTestID(),
+		HTTPPort:       9650,
+		StakingPort:    9661,
+		ChainConfig:    []byte(`{"pruning-enabled": false}`),
+	}
+	require.NoError(WriteFiles(dir, conf))
+
+	dockerfile, err := os.ReadFile(filepath.Join(dir, "Dockerfile"))
+	require.NoError(err)
+	require.Contains(string(dockerfile), "FROM metalblockchain/metalgo:v1.11.0\n")
+	require.Contains(string(dockerfile), "EXPOSE 9650 9661\n")
+
+	compose, err := os.ReadFile(filepath.Join(dir, constants.ValidatorComposeFileName))
+	require.NoError(err)
+	require.Contains(string(compose), "name: metal-validator-my-subnet\n")
+	require.Contains(string(compose), `- "127.0.0.1:9650:9650"`)
+	require.Contains(string(compose), `- "9661:9661"`)
+
+	require.DirExists(filepath.Join(dir, PluginsDir))
+	require.FileExists(filepath.Join(dir, "configs", "node.json"))
+	chainConfig, err := os.ReadFile(filepath.Join(dir, "configs", "chains", conf.BlockchainID.String(), "config.json"))
+	require.NoError(err)
+	require.Equal(conf.ChainConfig, chainConfig)
+	require.NoFileExists(filepath.Join(dir, "configs", "subnets", conf.SubnetID.String()+".json"))
+}
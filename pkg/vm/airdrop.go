@@ -10,6 +10,7 @@ import (
 	"github.com/MetalBlockchain/metal-cli/pkg/application"
 	"github.com/MetalBlockchain/metal-cli/pkg/key"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
 	"github.com/MetalBlockchain/metal-cli/pkg/statemachine"
 	"github.com/MetalBlockchain/metal-cli/pkg/utils"
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
@@ -91,7 +92,7 @@ func getAllocation(
 	allocation := core.GenesisAlloc{}
 
 	airdropType, err := app.Prompt.CaptureList(
-		"How would you like to distribute funds",
+		prompts.Keyed("airdrop", "How would you like to distribute funds"),
 		[]string{newAirdrop, ewoqAirdrop, customAirdrop, goBackMsg},
 	)
 	if err != nil {
@@ -115,12 +116,12 @@ func getAllocation(
 	var addressHex common.Address
 
 	for {
-		addressHex, err = app.Prompt.CaptureAddress("Address to airdrop to")
+		addressHex, err = app.Prompt.CaptureAddress(prompts.Keyed("airdrop-address", "Address to airdrop to"))
 		if err != nil {
 			return nil, statemachine.Stop, err
 		}
 
-		amount, err := app.Prompt.CapturePositiveBigInt(captureAmountLabel)
+		amount, err := app.Prompt.CapturePositiveBigInt(prompts.Keyed("airdrop-amount", captureAmountLabel))
 		if err != nil {
 			return nil, statemachine.Stop, err
 		}
@@ -135,7 +136,7 @@ func getAllocation(
 
 		allocation[addressHex] = account
 
-		continueAirdrop, err := app.Prompt.CaptureNoYes(extendAirdrop)
+		continueAirdrop, err := app.Prompt.CaptureNoYes(prompts.Keyed("airdrop-more", extendAirdrop))
 		if err != nil {
 			return nil, statemachine.Stop, err
 		}
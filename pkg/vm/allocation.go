@@ -0,0 +1,176 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/MetalBlockchain/subnet-evm/core"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// allocations below one whole token are most likely a unit mistake (wei vs tokens)
+var dustAllocationThreshold = oneAvax
+
+// AllocationEntry is a single funded address of a genesis allocation
+type AllocationEntry struct {
+	Address common.Address
+	Balance *big.Int
+}
+
+// AllocationReport summarizes the initial token supply set by a genesis allocation
+type AllocationReport struct {
+	// Entries sorted by decreasing balance
+	Entries     []AllocationEntry
+	TotalSupply *big.Int
+	// MaxSupply is the user declared supply cap, nil if none was given
+	MaxSupply *big.Int
+	// Addresses that appear more than once in the genesis alloc
+	// (eg same address with different casing)
+	DuplicateAddresses []common.Address
+	// Addresses with a non zero balance below [dustAllocationThreshold]
+	DustAddresses     []common.Address
+	ZeroAddressFunded bool
+}
+
+// Percentage returns the share of the total supply held by [balance]
+func (r *AllocationReport) Percentage(balance *big.Int) float64 {
+	if r.TotalSupply.Sign() == 0 {
+		return 0
+	}
+	share, _ := new(big.Rat).SetFrac(balance, r.TotalSupply).Float64()
+	return share * 100
+}
+
+// ExceedsMaxSupply reports whether a max supply was declared and the total supply is over it
+func (r *AllocationReport) ExceedsMaxSupply() bool {
+	return r.MaxSupply != nil && r.TotalSupply.Cmp(r.MaxSupply) > 0
+}
+
+// Warnings returns human readable descriptions of suspicious allocations
+func (r *AllocationReport) Warnings() []string {
+	warnings := []string{}
+	for _, addr := range r.DuplicateAddresses {
+		warnings = append(warnings, fmt.Sprintf("address %s is allocated more than once, only the last entry is kept", addr.Hex()))
+	}
+	for _, addr := range r.DustAddresses {
+		warnings = append(warnings, fmt.Sprintf("address %s is allocated less than one token (dust), check the amount is not expressed in tokens instead of wei", addr.Hex()))
+	}
+	if r.ZeroAddressFunded {
+		warnings = append(warnings, "the zero address is allocated funds, which can never be spent")
+	}
+	return warnings
+}
+
+// GetAllocationReport parses the alloc section of a Subnet-EVM genesis and computes
+// its supply totals. The raw JSON is inspected so that duplicated addresses, which are
+// silently merged when decoding into a core.GenesisAlloc, can be detected.
+// [maxSupply] is optional, and expressed in wei
+func GetAllocationReport(genesisBytes []byte, maxSupply *big.Int) (*AllocationReport, error) {
+	var genesis map[string]json.RawMessage
+	if err := json.Unmarshal(genesisBytes, &genesis); err != nil {
+		return nil, fmt.Errorf("failed parsing genesis: %w", err)
+	}
+	report := &AllocationReport{
+		TotalSupply: big.NewInt(0),
+		MaxSupply:   maxSupply,
+	}
+	rawAlloc, ok := genesis["alloc"]
+	if !ok {
+		return report, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(rawAlloc))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, errors.New("genesis alloc is not a JSON object")
+	}
+	alloc := core.GenesisAlloc{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing genesis alloc: %w", err)
+		}
+		addrStr, ok := tok.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected genesis alloc key %v", tok)
+		}
+		if !common.IsHexAddress(addrStr) {
+			return nil, fmt.Errorf("invalid genesis alloc address %q", addrStr)
+		}
+		var account core.GenesisAccount
+		if err := dec.Decode(&account); err != nil {
+			return nil, fmt.Errorf("failed parsing genesis alloc for %s: %w", addrStr, err)
+		}
+		addr := common.HexToAddress(addrStr)
+		if _, ok := alloc[addr]; ok {
+			report.DuplicateAddresses = append(report.DuplicateAddresses, addr)
+		}
+		alloc[addr] = account
+	}
+	fillAllocationReport(report, alloc)
+	return report, nil
+}
+
+func fillAllocationReport(report *AllocationReport, alloc core.GenesisAlloc) {
+	for addr, account := range alloc {
+		balance := account.Balance
+		if balance == nil {
+			balance = big.NewInt(0)
+		}
+		report.Entries = append(report.Entries, AllocationEntry{
+			Address: addr,
+			Balance: balance,
+		})
+		report.TotalSupply.Add(report.TotalSupply, balance)
+		if balance.Sign() > 0 && balance.Cmp(dustAllocationThreshold) < 0 {
+			report.DustAddresses = append(report.DustAddresses, addr)
+		}
+		if addr == (common.Address{}) && balance.Sign() > 0 {
+			report.ZeroAddressFunded = true
+		}
+	}
+	sort.Slice(report.Entries, func(i, j int) bool {
+		if c := report.Entries[i].Balance.Cmp(report.Entries[j].Balance); c != 0 {
+			return c > 0
+		}
+		return report.Entries[i].Address.Hex() < report.Entries[j].Address.Hex()
+	})
+	sort.Slice(report.DustAddresses, func(i, j int) bool {
+		return report.DustAddresses[i].Hex() < report.DustAddresses[j].Hex()
+	})
+}
+
+// AddGenesisAllocation funds [address] with [balance] on the Subnet-EVM genesis
+// [genesisBytes]. Other genesis fields are kept as they are
+func AddGenesisAllocation(genesisBytes []byte, address common.Address, balance *big.Int) ([]byte, error) {
+	var genesis map[string]json.RawMessage
+	if err := json.Unmarshal(genesisBytes, &genesis); err != nil {
+		return nil, fmt.Errorf("failed parsing genesis: %w", err)
+	}
+	alloc := map[string]json.RawMessage{}
+	if rawAlloc, ok := genesis["alloc"]; ok {
+		if err := json.Unmarshal(rawAlloc, &alloc); err != nil {
+			return nil, fmt.Errorf("failed parsing genesis alloc: %w", err)
+		}
+	}
+	for addrStr := range alloc {
+		if common.HexToAddress(addrStr) == address {
+			return nil, fmt.Errorf("address %s is already allocated on the genesis", address.Hex())
+		}
+	}
+	account, err := json.Marshal(core.GenesisAccount{Balance: balance})
+	if err != nil {
+		return nil, err
+	}
+	alloc[hex.EncodeToString(address.Bytes())] = account
+	if genesis["alloc"], err = json.Marshal(alloc); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(genesis, "", "  ")
+}
@@ -0,0 +1,135 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAllocationReport(t *testing.T) {
+	addr1 := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	addr2 := common.HexToAddress("0x0000000000000000000000000000000000000abc")
+
+	type test struct {
+		name              string
+		genesis           string
+		maxSupply         *big.Int
+		expectedErr       bool
+		expectedTotal     string
+		expectedEntries   []common.Address
+		expectedDuplicate []common.Address
+		expectedDust      []common.Address
+		expectedZero      bool
+		expectedExceeds   bool
+	}
+
+	tests := []test{
+		{
+			name:          "no alloc",
+			genesis:       `{"config":{}}`,
+			expectedTotal: "0",
+		},
+		{
+			name: "totals and ordering",
+			genesis: `{"alloc":{
+				"0x0000000000000000000000000000000000000abc":{"balance":"0xde0b6b3a7640000"},
+				"8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC":{"balance":"0x29a2241af62c0000"}
+			}}`,
+			expectedTotal:   "4000000000000000000",
+			expectedEntries: []common.Address{addr1, addr2},
+		},
+		{
+			name: "duplicate with different casing",
+			genesis: `{"alloc":{
+				"0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC":{"balance":"0xde0b6b3a7640000"},
+				"0x8db97c7cece249c2b98bdc0226cc4c2a57bf52fc":{"balance":"0xde0b6b3a7640000"}
+			}}`,
+			expectedTotal:     "1000000000000000000",
+			expectedEntries:   []common.Address{addr1},
+			expectedDuplicate: []common.Address{addr1},
+		},
+		{
+			name:            "dust and zero address",
+			genesis:         `{"alloc":{"0x0000000000000000000000000000000000000000":{"balance":"0x64"}}}`,
+			expectedTotal:   "100",
+			expectedEntries: []common.Address{{}},
+			expectedDust:    []common.Address{{}},
+			expectedZero:    true,
+		},
+		{
+			name:            "exceeds max supply",
+			genesis:         `{"alloc":{"0x0000000000000000000000000000000000000abc":{"balance":"0xde0b6b3a7640000"}}}`,
+			maxSupply:       big.NewInt(1000),
+			expectedTotal:   "1000000000000000000",
+			expectedEntries: []common.Address{addr2},
+			expectedExceeds: true,
+		},
+		{
+			name:        "invalid address",
+			genesis:     `{"alloc":{"0xnothex":{"balance":"0x1"}}}`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			report, err := GetAllocationReport([]byte(tt.genesis), tt.maxSupply)
+			if tt.expectedErr {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+			require.Equal(tt.expectedTotal, report.TotalSupply.String())
+			entries := []common.Address{}
+			for _, entry := range report.Entries {
+				entries = append(entries, entry.Address)
+			}
+			if tt.expectedEntries == nil {
+				tt.expectedEntries = []common.Address{}
+			}
+			require.Equal(tt.expectedEntries, entries)
+			require.Equal(tt.expectedDuplicate, report.DuplicateAddresses)
+			require.Equal(tt.expectedDust, report.DustAddresses)
+			require.Equal(tt.expectedZero, report.ZeroAddressFunded)
+			require.Equal(tt.expectedExceeds, report.ExceedsMaxSupply())
+		})
+	}
+}
+
+func TestAllocationReportPercentage(t *testing.T) {
+	require := require.New(t)
+	report := &AllocationReport{TotalSupply: big.NewInt(400)}
+	require.InDelta(25.0, report.Percentage(big.NewInt(100)), 1e-9)
+	report.TotalSupply = big.NewInt(0)
+	require.Zero(report.Percentage(big.NewInt(100)))
+}
+
+func TestAddGenesisAllocation(t *testing.T) {
+	require := require.New(t)
+	addr := common.HexToAddress("0x0000000000000000000000000000000000000abc")
+	genesis := `{"config":{"chainId":123},"alloc":{"8db97c7cece249c2b98bdc0226cc4c2a57bf52fc":{"balance":"0x10"}},"gasLimit":"0x7a1200"}`
+
+	updated, err := AddGenesisAllocation([]byte(genesis), addr, big.NewInt(16))
+	require.NoError(err)
+	report, err := GetAllocationReport(updated, nil)
+	require.NoError(err)
+	require.Equal("32", report.TotalSupply.String())
+	require.Len(report.Entries, 2)
+	require.Contains(string(updated), `"chainId": 123`)
+	require.Contains(string(updated), `"gasLimit": "0x7a1200"`)
+
+	_, err = AddGenesisAllocation(updated, addr, big.NewInt(1))
+	require.Error(err)
+
+	updated, err = AddGenesisAllocation([]byte(`{"config":{}}`), addr, big.NewInt(1))
+	require.NoError(err)
+	report, err = GetAllocationReport(updated, nil)
+	require.NoError(err)
+	require.Equal([]AllocationEntry{{Address: addr, Balance: big.NewInt(1)}}, report.Entries)
+}
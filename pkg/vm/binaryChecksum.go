@@ -0,0 +1,62 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/binutils"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+)
+
+var ErrVMBinaryChanged = errors.New("VM binary does not match the checksum recorded for the subnet")
+
+// SetVMBinary records into the sidecar the VM version, and the checksum and
+// source of [vmBin]
+func SetVMBinary(sc *models.Sidecar, vmBin string, source string) error {
+	checksum, err := utils.GetSHA256FromDisk(vmBin)
+	if err != nil {
+		return err
+	}
+	sc.VMBinary = models.VMBinary{
+		Version: sc.VMVersion,
+		SHA256:  checksum,
+		Source:  source,
+	}
+	return nil
+}
+
+// CheckVMBinary verifies that [vmBin] has the checksum recorded in the sidecar.
+// It returns false if there is no binary recorded for the current VM version,
+// eg for sidecars previous to checksum tracking, or after a version change
+func CheckVMBinary(sc models.Sidecar, vmBin string) (bool, error) {
+	if sc.VMBinary.SHA256 == "" || sc.VMBinary.Version != sc.VMVersion {
+		return false, nil
+	}
+	checksum, err := utils.GetSHA256FromDisk(vmBin)
+	if err != nil {
+		return false, err
+	}
+	if checksum != sc.VMBinary.SHA256 {
+		return true, fmt.Errorf("%w: %s has sha256 %s, expected %s", ErrVMBinaryChanged, vmBin, checksum, sc.VMBinary.SHA256)
+	}
+	return true, nil
+}
+
+// GetVMBinarySource describes where the VM binary [vmBin] of the sidecar comes from:
+// the release download URL for Subnet-EVM, the source repository for custom VMs
+// built by the CLI, or else the binary path
+func GetVMBinarySource(sc models.Sidecar, vmBin string) string {
+	switch {
+	case sc.VM == models.SubnetEvm:
+		url, _, err := binutils.NewSubnetEVMDownloader().GetDownloadURL(sc.VMVersion, binutils.NewInstaller())
+		if err == nil {
+			return url
+		}
+	case sc.CustomVMRepoURL != "":
+		return fmt.Sprintf("%s@%s", sc.CustomVMRepoURL, sc.CustomVMBranch)
+	}
+	return vmBin
+}
@@ -0,0 +1,40 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckVMBinary(t *testing.T) {
+	require := require.New(t)
+	vmBin := filepath.Join(t.TempDir(), "vm")
+	require.NoError(os.WriteFile(vmBin, []byte("vm binary"), 0o600))
+
+	sc := models.Sidecar{VM: models.CustomVM, VMVersion: "v1.0.0"}
+	recorded, err := CheckVMBinary(sc, vmBin)
+	require.NoError(err)
+	require.False(recorded)
+
+	require.NoError(SetVMBinary(&sc, vmBin, "source"))
+	require.Equal("v1.0.0", sc.VMBinary.Version)
+	require.Equal("source", sc.VMBinary.Source)
+	recorded, err = CheckVMBinary(sc, vmBin)
+	require.NoError(err)
+	require.True(recorded)
+
+	require.NoError(os.WriteFile(vmBin, []byte("tampered vm binary"), 0o600))
+	_, err = CheckVMBinary(sc, vmBin)
+	require.ErrorIs(err, ErrVMBinaryChanged)
+
+	// a version change requires recording the binary again
+	sc.VMVersion = "v1.1.0"
+	recorded, err = CheckVMBinary(sc, vmBin)
+	require.NoError(err)
+	require.False(recorded)
+}
@@ -10,7 +10,9 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	pb "github.com/MetalBlockchain/metalgo/proto/pb/vm/runtime"
@@ -26,7 +28,11 @@ import (
 	"golang.org/x/mod/semver"
 )
 
-var ErrNoAvagoVersion = errors.New("unable to find a compatible avalanchego version")
+var (
+	ErrNoAvagoVersion     = errors.New("unable to find a compatible avalanchego version")
+	ErrNoSubnetEVMVersion = errors.New("unable to find a compatible subnet-evm version")
+	ErrNoRPCVersion       = errors.New("no RPC version found")
+)
 
 // protocolVersionQueryInitializer gets vm protocol version during handshake and provides it on a channel
 
@@ -157,7 +163,7 @@ func GetRPCProtocolVersion(app *application.Avalanche, vmType models.VMType, vmV
 
 	version, ok := parsedCompat.RPCChainVMProtocolVersion[vmVersion]
 	if !ok {
-		return 0, errors.New("no RPC version found")
+		return 0, ErrNoRPCVersion
 	}
 
 	return version, nil
@@ -220,3 +226,67 @@ func GetLatestAvalancheGoByProtocolVersion(app *application.Avalanche, rpcVersio
 	}
 	return useVersion[0], nil
 }
+
+// GetAvalancheGoRPCVersion returns the rpc version used by the given avalanche go version,
+// as listed in the avalanche go compatibility file at url
+func GetAvalancheGoRPCVersion(app *application.Avalanche, avagoVersion string, url string) (int, error) {
+	compatibilityBytes, err := app.Downloader.Download(url)
+	if err != nil {
+		return 0, err
+	}
+
+	var parsedCompat models.AvagoCompatiblity
+	if err = json.Unmarshal(compatibilityBytes, &parsedCompat); err != nil {
+		return 0, err
+	}
+
+	// ignore pre release suffixes, eg v1.10.0-fuji
+	avagoVersion = strings.Split(avagoVersion, "-")[0]
+	for rpcStr, versions := range parsedCompat {
+		if !slices.Contains(versions, avagoVersion) {
+			continue
+		}
+		rpcVersion, err := strconv.Atoi(rpcStr)
+		if err != nil {
+			return 0, fmt.Errorf("invalid rpc version %q in compatibility file: %w", rpcStr, err)
+		}
+		return rpcVersion, nil
+	}
+	return 0, fmt.Errorf("no RPC version found for avalanchego %s", avagoVersion)
+}
+
+// GetSubnetEVMVersionsForRPC returns list of subnet-evm versions available for download that
+// use the specified rpcVersion, with latest version in first index
+func GetSubnetEVMVersionsForRPC(app *application.Avalanche, rpcVersion int) ([]string, error) {
+	compatibilityBytes, err := app.Downloader.Download(constants.SubnetEVMRPCCompatibilityURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsedCompat models.VMCompatibility
+	if err = json.Unmarshal(compatibilityBytes, &parsedCompat); err != nil {
+		return nil, err
+	}
+
+	// the compatibility file may list a release in progress, not yet available for download
+	latestSubnetEVMVersion, err := app.Downloader.GetLatestReleaseVersion(binutils.GetGithubLatestReleaseURL(
+		constants.AvaLabsOrg,
+		constants.SubnetEVMRepoName,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	var eligibleVersions []string
+	for version, versionRPC := range parsedCompat.RPCChainVMProtocolVersion {
+		if versionRPC == rpcVersion && semver.Compare(version, latestSubnetEVMVersion) != 1 {
+			eligibleVersions = append(eligibleVersions, version)
+		}
+	}
+	if len(eligibleVersions) == 0 {
+		return nil, ErrNoSubnetEVMVersion
+	}
+	semver.Sort(eligibleVersions)
+	slices.Reverse(eligibleVersions)
+	return eligibleVersions, nil
+}
@@ -0,0 +1,137 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"golang.org/x/mod/semver"
+)
+
+// snapshotDBDirName is the dir, inside a network runner snapshot, containing the
+// db of each node
+const snapshotDBDirName = "db"
+
+var ErrVMNotCompatible = errors.New("VM binary is not compatible with the existing chain data")
+
+// CompatibilityCheck is a user provided script that verifies that a new VM binary
+// can take over the chain data of the current one
+type CompatibilityCheck struct {
+	ScriptPath   string
+	OldVMBinary  string
+	NewVMBinary  string
+	SnapshotDir  string
+	VMID         string
+	BlockchainID string
+}
+
+// Run executes the script against a copy of the nodes db of the local network snapshot,
+// so the snapshot itself is never modified. The script receives the new binary and the
+// copied db dir as arguments, and the full check information as env vars:
+//   - METAL_OLD_VM_BINARY, METAL_NEW_VM_BINARY
+//   - METAL_CHAIN_DATA_DIR
+//   - METAL_VM_ID, METAL_BLOCKCHAIN_ID
+//
+// A non zero exit status means the new binary is not compatible
+func (c CompatibilityCheck) Run() error {
+	if !utils.IsExecutable(c.ScriptPath) {
+		return fmt.Errorf("compatibility script %s not found or not executable", c.ScriptPath)
+	}
+	snapshotDBDir := filepath.Join(c.SnapshotDir, snapshotDBDirName)
+	if !utils.DirectoryExists(snapshotDBDir) {
+		return fmt.Errorf("no chain data found at %s. Deploy the subnet locally and stop the network before checking compatibility", c.SnapshotDir)
+	}
+	chainDataDir, err := os.MkdirTemp("", "vm-compat-check")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(chainDataDir)
+	if err := copyDir(snapshotDBDir, chainDataDir); err != nil {
+		return fmt.Errorf("failed to copy chain data: %w", err)
+	}
+
+	ux.Logger.PrintToUser("Running compatibility script %s against a copy of the local chain data...", c.ScriptPath)
+	cmd := exec.Command(c.ScriptPath, c.NewVMBinary, chainDataDir)
+	cmd.Env = append(os.Environ(),
+		"METAL_OLD_VM_BINARY="+c.OldVMBinary,
+		"METAL_NEW_VM_BINARY="+c.NewVMBinary,
+		"METAL_CHAIN_DATA_DIR="+chainDataDir,
+		"METAL_VM_ID="+c.VMID,
+		"METAL_BLOCKCHAIN_ID="+c.BlockchainID,
+	)
+	utils.SetupRealtimeCLIOutput(cmd, true, true)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: compatibility script %s failed: %w", ErrVMNotCompatible, c.ScriptPath, err)
+	}
+	return nil
+}
+
+func copyDir(srcDir string, destDir string) error {
+	return filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		destPath := filepath.Join(destDir, relPath)
+		if d.IsDir() {
+			return os.MkdirAll(destPath, constants.DefaultPerms755)
+		}
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		dest, err := os.Create(destPath)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(dest, src); err != nil {
+			dest.Close()
+			return err
+		}
+		return dest.Close()
+	})
+}
+
+// NextCustomVMVersion returns the version to record for a new binary of a VM currently
+// at [currentVersion]: [requestedVersion] if given, which must be greater than the current
+// one, or else the current version with its patch number increased. A VM without version
+// is considered to be at v0.0.0
+func NextCustomVMVersion(currentVersion string, requestedVersion string) (string, error) {
+	if currentVersion == "" {
+		currentVersion = "v0.0.0"
+	}
+	if requestedVersion != "" {
+		if !semver.IsValid(requestedVersion) {
+			return "", fmt.Errorf("invalid version string, should be semantic version (ex: v1.1.1): %s", requestedVersion)
+		}
+		if semver.IsValid(currentVersion) && semver.Compare(requestedVersion, currentVersion) <= 0 {
+			return "", fmt.Errorf("version %s must be greater than the current VM version %s", requestedVersion, currentVersion)
+		}
+		return requestedVersion, nil
+	}
+	if !semver.IsValid(currentVersion) {
+		return "", fmt.Errorf("unable to increase VM version %s, as it is not a semantic version. Provide the new version explicitly", currentVersion)
+	}
+	var major, minor, patch int
+	if _, err := fmt.Sscanf(semver.Canonical(currentVersion), "v%d.%d.%d", &major, &minor, &patch); err != nil {
+		return "", err
+	}
+	if semver.Prerelease(currentVersion) != "" {
+		// v1.2.3-rc.1 is followed by v1.2.3
+		return fmt.Sprintf("v%d.%d.%d", major, minor, patch), nil
+	}
+	return fmt.Sprintf("v%d.%d.%d", major, minor, patch+1), nil
+}
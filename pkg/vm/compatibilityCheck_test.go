@@ -0,0 +1,80 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/constants"
+	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/metalgo/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextCustomVMVersion(t *testing.T) {
+	tests := []struct {
+		current   string
+		requested string
+		expected  string
+		fails     bool
+	}{
+		{current: "", expected: "v0.0.1"},
+		{current: "v1.2.3", expected: "v1.2.4"},
+		{current: "v1.2.3-rc.1", expected: "v1.2.3"},
+		{current: "v1.2.3", requested: "v1.3.0", expected: "v1.3.0"},
+		{current: "v1.2.3", requested: "v1.2.3", fails: true},
+		{current: "v1.2.3", requested: "1.3.0", fails: true},
+		{current: "custom", fails: true},
+		{current: "custom", requested: "v1.0.0", expected: "v1.0.0"},
+	}
+	for _, tt := range tests {
+		version, err := NextCustomVMVersion(tt.current, tt.requested)
+		if tt.fails {
+			require.Error(t, err, tt.current+" "+tt.requested)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, tt.expected, version)
+	}
+}
+
+func TestCompatibilityCheckRun(t *testing.T) {
+	require := require.New(t)
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+	snapshotDir := t.TempDir()
+	outputPath := filepath.Join(t.TempDir(), "output")
+	scriptPath := filepath.Join(t.TempDir(), "check.sh")
+	script := "#!/usr/bin/env bash\n" +
+		"echo \"$1 $METAL_BLOCKCHAIN_ID $(cat $2/node1/data)\" > " + outputPath + "\n" +
+		"echo modified > $METAL_CHAIN_DATA_DIR/node1/data\n" +
+		"[ \"$METAL_VM_ID\" == compatible ]\n"
+	require.NoError(os.WriteFile(scriptPath, []byte(script), constants.DefaultPerms755))
+	check := CompatibilityCheck{
+		ScriptPath:   scriptPath,
+		NewVMBinary:  "newvm",
+		SnapshotDir:  snapshotDir,
+		VMID:         "compatible",
+		BlockchainID: "chain",
+	}
+
+	// there is no chain data
+	require.ErrorContains(check.Run(), "no chain data found")
+
+	dataPath := filepath.Join(snapshotDir, snapshotDBDirName, "node1", "data")
+	require.NoError(os.MkdirAll(filepath.Dir(dataPath), constants.DefaultPerms755))
+	require.NoError(os.WriteFile(dataPath, []byte("state"), constants.WriteReadReadPerms))
+	require.NoError(check.Run())
+	output, err := os.ReadFile(outputPath)
+	require.NoError(err)
+	require.Equal("newvm chain state\n", string(output))
+	// the script works on a copy of the chain data
+	data, err := os.ReadFile(dataPath)
+	require.NoError(err)
+	require.Equal("state", string(data))
+
+	check.VMID = "incompatible"
+	require.ErrorIs(check.Run(), ErrVMNotCompatible)
+}
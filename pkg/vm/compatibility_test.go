@@ -171,3 +171,76 @@ func TestGetLatestAvalancheGoByProtocolVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAvalancheGoRPCVersion(t *testing.T) {
+	require := require.New(t)
+
+	mockDownloader := &mocks.Downloader{}
+	mockDownloader.On("Download", mock.Anything).Return(testAvagoCompat, nil)
+
+	app := application.New()
+	app.Downloader = mockDownloader
+
+	rpcVersion, err := GetAvalancheGoRPCVersion(app, "v1.8.0", constants.AvalancheGoCompatibilityURL)
+	require.NoError(err)
+	require.Equal(17, rpcVersion)
+
+	rpcVersion, err = GetAvalancheGoRPCVersion(app, "v1.9.2-fuji", constants.AvalancheGoCompatibilityURL)
+	require.NoError(err)
+	require.Equal(19, rpcVersion)
+
+	_, err = GetAvalancheGoRPCVersion(app, "v1.7.0", constants.AvalancheGoCompatibilityURL)
+	require.ErrorContains(err, "no RPC version found")
+}
+
+func TestGetSubnetEVMVersionsForRPC(t *testing.T) {
+	type versionTest struct {
+		name             string
+		rpc              int
+		latestVersion    string
+		expectedVersions []string
+		expectedErr      error
+	}
+
+	tests := []versionTest{
+		{
+			name:             "multiple entries, latest first",
+			rpc:              18,
+			latestVersion:    "v0.4.2",
+			expectedVersions: []string{"v0.4.2", "v0.4.1"},
+		},
+		{
+			name:             "skip versions not released",
+			rpc:              18,
+			latestVersion:    "v0.4.1",
+			expectedVersions: []string{"v0.4.1"},
+		},
+		{
+			name:          "no entry",
+			rpc:           19,
+			latestVersion: "v0.4.2",
+			expectedErr:   ErrNoSubnetEVMVersion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			mockDownloader := &mocks.Downloader{}
+			mockDownloader.On("Download", mock.Anything).Return(testSubnetEVMCompat, nil)
+			mockDownloader.On("GetLatestReleaseVersion", mock.Anything).Return(tt.latestVersion, nil)
+
+			app := application.New()
+			app.Downloader = mockDownloader
+
+			versions, err := GetSubnetEVMVersionsForRPC(app, tt.rpc)
+			if tt.expectedErr != nil {
+				require.ErrorIs(err, tt.expectedErr)
+			} else {
+				require.NoError(err)
+				require.Equal(tt.expectedVersions, versions)
+			}
+		})
+	}
+}
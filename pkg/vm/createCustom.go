@@ -47,14 +47,14 @@ func CreateCustomSubnetConfig(
 		githubOption := "Download and build from a git repository (recommended for cloud deployments)"
 		localOption := "I already have a VM binary (local network deployments only)"
 		options := []string{githubOption, localOption}
-		option, err := app.Prompt.CaptureList("How do you want to set up the VM binary?", options)
+		option, err := app.Prompt.CaptureList(prompts.Keyed("vm-binary-source", "How do you want to set up the VM binary?"), options)
 		if err != nil {
 			return nil, &models.Sidecar{}, err
 		}
 		if option == githubOption {
 			useRepo = true
 		} else {
-			vmPath, err = app.Prompt.CaptureExistingFilepath("Enter path to VM binary")
+			vmPath, err = app.Prompt.CaptureExistingFilepath(prompts.Keyed("vm-binary-path", "Enter path to VM binary"))
 			if err != nil {
 				return nil, &models.Sidecar{}, err
 			}
@@ -81,13 +81,17 @@ func CreateCustomSubnetConfig(
 
 	sc.RPCVersion = rpcVersion
 
+	if err := SetVMBinary(sc, app.GetCustomVMPath(subnetName), GetVMBinarySource(*sc, vmPath)); err != nil {
+		return nil, &models.Sidecar{}, err
+	}
+
 	return genesisBytes, sc, nil
 }
 
 func loadCustomGenesis(app *application.Avalanche, genesisPath string) ([]byte, error) {
 	var err error
 	if genesisPath == "" {
-		genesisPath, err = app.Prompt.CaptureExistingFilepath("Enter path to custom genesis")
+		genesisPath, err = app.Prompt.CaptureExistingFilepath(prompts.Keyed("genesis-path", "Enter path to custom genesis"))
 		if err != nil {
 			return nil, err
 		}
@@ -107,7 +111,7 @@ func SetCustomVMSourceCodeFields(app *application.Avalanche, sc *models.Sidecar,
 		}
 	}
 	if customVMRepoURL == "" {
-		customVMRepoURL, err = app.Prompt.CaptureURL("Source code repository URL", true)
+		customVMRepoURL, err = app.Prompt.CaptureURL(prompts.Keyed("vm-repo-url", "Source code repository URL"), true)
 		if err != nil {
 			return err
 		}
@@ -120,7 +124,7 @@ func SetCustomVMSourceCodeFields(app *application.Avalanche, sc *models.Sidecar,
 		}
 	}
 	if customVMBranch == "" {
-		customVMBranch, err = app.Prompt.CaptureRepoBranch("Branch", customVMRepoURL)
+		customVMBranch, err = app.Prompt.CaptureRepoBranch(prompts.Keyed("vm-repo-branch", "Branch"), customVMRepoURL)
 		if err != nil {
 			return err
 		}
@@ -133,7 +137,7 @@ func SetCustomVMSourceCodeFields(app *application.Avalanche, sc *models.Sidecar,
 		}
 	}
 	if customVMBuildScript == "" {
-		customVMBuildScript, err = app.Prompt.CaptureRepoFile("Build script", customVMRepoURL, customVMBranch)
+		customVMBuildScript, err = app.Prompt.CaptureRepoFile(prompts.Keyed("vm-build-script", "Build script"), customVMRepoURL, customVMBranch)
 		if err != nil {
 			return err
 		}
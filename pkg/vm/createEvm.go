@@ -3,26 +3,24 @@
 package vm
 
 import (
-	"bytes"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
 	"os"
-	"time"
+	"sort"
+	"strings"
 
 	"github.com/MetalBlockchain/metal-cli/pkg/application"
 	"github.com/MetalBlockchain/metal-cli/pkg/binutils"
 	"github.com/MetalBlockchain/metal-cli/pkg/constants"
 	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
+	"github.com/MetalBlockchain/metal-cli/pkg/sdk"
 	"github.com/MetalBlockchain/metal-cli/pkg/statemachine"
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
-	"github.com/MetalBlockchain/metalgo/snow"
 	"github.com/MetalBlockchain/subnet-evm/core"
-	"github.com/MetalBlockchain/subnet-evm/params"
-	"github.com/MetalBlockchain/subnet-evm/precompile/contracts/txallowlist"
-	"github.com/MetalBlockchain/subnet-evm/utils"
-	"github.com/ethereum/go-ethereum/common"
+	"github.com/MetalBlockchain/subnet-evm/precompile/contracts/nativeminter"
+	"golang.org/x/exp/maps"
 )
 
 var versionComments = map[string]string{
@@ -45,6 +43,7 @@ func CreateEvmSubnetConfig(
 		sc           *models.Sidecar
 		err          error
 		rpcVersion   int
+		vmBin        string
 	)
 
 	subnetEVMVersion, err = getVMVersion(app, "Subnet-EVM", constants.SubnetEVMRepoName, subnetEVMVersion)
@@ -53,7 +52,10 @@ func CreateEvmSubnetConfig(
 	}
 
 	if getRPCVersionFromBinary {
-		_, vmBin, err := binutils.SetupSubnetEVM(app, subnetEVMVersion)
+		_, vmBin, err = binutils.SetupSubnetEVM(app, subnetEVMVersion)
+		if errors.Is(err, application.ErrDownloadNotFound) {
+			return nil, &models.Sidecar{}, unknownVMVersionError("Subnet-EVM", constants.SubnetEVMRepoName, subnetEVMVersion)
+		}
 		if err != nil {
 			return nil, &models.Sidecar{}, fmt.Errorf("failed to install subnet-evm: %w", err)
 		}
@@ -63,6 +65,9 @@ func CreateEvmSubnetConfig(
 		}
 	} else {
 		rpcVersion, err = GetRPCProtocolVersion(app, models.SubnetEvm, subnetEVMVersion)
+		if errors.Is(err, ErrNoRPCVersion) {
+			return nil, &models.Sidecar{}, unknownVMVersionError("Subnet-EVM", constants.SubnetEVMRepoName, subnetEVMVersion)
+		}
 		if err != nil {
 			return nil, &models.Sidecar{}, err
 		}
@@ -98,6 +103,16 @@ func CreateEvmSubnetConfig(
 		}
 	}
 
+	if err := setNativeTokenInfo(app, sc, genesisBytes); err != nil {
+		return nil, &models.Sidecar{}, err
+	}
+
+	if vmBin != "" {
+		if err := SetVMBinary(sc, vmBin, GetVMBinarySource(*sc, vmBin)); err != nil {
+			return nil, &models.Sidecar{}, err
+		}
+	}
+
 	return genesisBytes, sc, nil
 }
 
@@ -113,88 +128,121 @@ func createEvmGenesis(
 ) ([]byte, *models.Sidecar, error) {
 	ux.Logger.PrintToUser("creating genesis for subnet %s", subnetName)
 
-	genesis := core.Genesis{}
-	conf := params.SubnetEVMDefaultChainConfig
-
-	conf.NetworkUpgrades = params.NetworkUpgrades{
-		SubnetEVMTimestamp: utils.NewUint64(0),
-		DurangoTimestamp:   utils.NewUint64(uint64(time.Now().Unix())),
-	}
-	conf.AvalancheContext = params.AvalancheContext{
-		SnowCtx: &snow.Context{},
-	}
-
-	const (
-		descriptorsState = "descriptors"
-		feeState         = "fee"
-		airdropState     = "airdrop"
-		precompilesState = "precompiles"
-	)
+	conf := sdk.NewEVMChainConfig()
 
 	var (
 		chainID     *big.Int
 		tokenSymbol string
+		tokenName   string
+		mintConfig  *nativeminter.Config
 		allocation  core.GenesisAlloc
-		direction   statemachine.StateDirection
-		err         error
-	)
-
-	subnetEvmState, err := statemachine.NewStateMachine(
-		[]string{descriptorsState, feeState, airdropState, precompilesState},
 	)
-	if err != nil {
-		return nil, nil, err
-	}
-	for subnetEvmState.Running() {
-		switch subnetEvmState.CurrentState() {
-		case descriptorsState:
-			chainID, tokenSymbol, direction, err = getDescriptors(app, subnetEVMChainID, subnetEVMTokenSymbol)
-		case feeState:
-			*conf, direction, err = GetFeeConfig(*conf, app, useSubnetEVMDefaults)
-		case airdropState:
-			allocation, direction, err = getEVMAllocation(app, subnetName, useSubnetEVMDefaults, tokenSymbol)
-		case precompilesState:
-			*conf, direction, err = getPrecompiles(*conf, app, useSubnetEVMDefaults, useWarp)
-		default:
-			err = errors.New("invalid creation stage")
-		}
-		if err != nil {
-			return nil, nil, err
-		}
-		subnetEvmState.NextState(direction)
-	}
-
-	if conf != nil && conf.GenesisPrecompiles[txallowlist.ConfigKey] != nil {
-		allowListCfg, ok := conf.GenesisPrecompiles[txallowlist.ConfigKey].(*txallowlist.Config)
-		if !ok {
-			return nil, nil, fmt.Errorf("expected config of type txallowlist.AllowListConfig, but got %T", allowListCfg)
-		}
-
-		if err := ensureAdminsHaveBalance(
-			allowListCfg.AdminAddresses,
-			allocation); err != nil {
-			return nil, nil, err
-		}
-	}
-
-	conf.ChainID = chainID
-
-	genesis.Alloc = allocation
-	genesis.Config = conf
-	genesis.Difficulty = Difficulty
-	genesis.GasLimit = conf.FeeConfig.GasLimit.Uint64()
 
-	if err := genesis.Verify(); err != nil {
-		return nil, nil, err
+	// precompiles are added to the ones of the base config each time they are asked
+	basePrecompiles := maps.Clone(conf.GenesisPrecompiles)
+	steps := []prompts.WizardStep{
+		{
+			Label: "Chain ID and token",
+			Ask: func() error {
+				var (
+					direction statemachine.StateDirection
+					err       error
+				)
+				chainID, tokenSymbol, direction, err = getDescriptors(app, subnetEVMChainID, subnetEVMTokenSymbol)
+				return directionErr(direction, err)
+			},
+			Answer: func() string {
+				return fmt.Sprintf("chain ID %s, token %s", chainID, tokenSymbol)
+			},
+			Default: func() string {
+				if chainID == nil {
+					return ""
+				}
+				return chainID.String()
+			},
+		},
+		{
+			Label: "Native token",
+			Ask: func() error {
+				var err error
+				tokenName, mintConfig, err = getNativeToken(app, tokenSymbol, useSubnetEVMDefaults)
+				return err
+			},
+			Answer: func() string {
+				if mintConfig == nil {
+					return fmt.Sprintf("%s, fixed supply", tokenName)
+				}
+				return fmt.Sprintf("%s, mintable by %d admins", tokenName, len(mintConfig.AdminAddresses))
+			},
+		},
+		{
+			Label: "Fees",
+			Ask: func() error {
+				var (
+					direction statemachine.StateDirection
+					err       error
+				)
+				*conf, direction, err = GetFeeConfig(*conf, app, useSubnetEVMDefaults)
+				return directionErr(direction, err)
+			},
+			Answer: func() string {
+				return fmt.Sprintf("gas limit %s, target block rate %ds, min base fee %s",
+					conf.FeeConfig.GasLimit, conf.FeeConfig.TargetBlockRate, conf.FeeConfig.MinBaseFee)
+			},
+		},
+		{
+			Label: "Airdrop",
+			Ask: func() error {
+				var (
+					direction statemachine.StateDirection
+					err       error
+				)
+				prevAllocation := allocation
+				allocation, direction, err = getEVMAllocation(app, subnetName, useSubnetEVMDefaults, tokenSymbol)
+				if err := directionErr(direction, err); err != nil {
+					allocation = prevAllocation
+					return err
+				}
+				return nil
+			},
+			Answer: func() string {
+				return fmt.Sprintf("%d funded addresses", len(allocation))
+			},
+		},
+		{
+			Label: "Precompiles",
+			Ask: func() error {
+				var (
+					direction statemachine.StateDirection
+					err       error
+				)
+				prevPrecompiles := conf.GenesisPrecompiles
+				conf.GenesisPrecompiles = maps.Clone(basePrecompiles)
+				if mintConfig != nil {
+					conf.GenesisPrecompiles[nativeminter.ConfigKey] = mintConfig
+				}
+				*conf, direction, err = getPrecompiles(*conf, app, useSubnetEVMDefaults, useWarp)
+				if err := directionErr(direction, err); err != nil {
+					conf.GenesisPrecompiles = prevPrecompiles
+					return err
+				}
+				return nil
+			},
+			Answer: func() string {
+				if len(conf.GenesisPrecompiles) == 0 {
+					return "none"
+				}
+				keys := maps.Keys(conf.GenesisPrecompiles)
+				sort.Strings(keys)
+				return strings.Join(keys, ", ")
+			},
+		},
 	}
-
-	jsonBytes, err := genesis.MarshalJSON()
-	if err != nil {
+	if err := prompts.RunWizard(app.Prompt, steps); err != nil {
 		return nil, nil, err
 	}
 
-	var prettyJSON bytes.Buffer
-	err = json.Indent(&prettyJSON, jsonBytes, "", "    ")
+	genesisBytes, err := sdk.BuildEVMGenesis(conf, chainID, allocation)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -206,26 +254,10 @@ func createEvmGenesis(
 		RPCVersion:  rpcVersion,
 		Subnet:      subnetName,
 		TokenSymbol: tokenSymbol,
-		TokenName:   tokenSymbol + " Token",
-	}
-
-	return prettyJSON.Bytes(), sc, nil
-}
-
-func ensureAdminsHaveBalance(admins []common.Address, alloc core.GenesisAlloc) error {
-	if len(admins) < 1 {
-		return nil
+		TokenName:   tokenName,
 	}
 
-	for _, admin := range admins {
-		// we can break at the first admin who has a non-zero balance
-		if bal, ok := alloc[admin]; ok &&
-			bal.Balance != nil &&
-			bal.Balance.Uint64() > uint64(0) {
-			return nil
-		}
-	}
-	return errors.New("none of the addresses in the transaction allow list precompile have any tokens allocated to them. Currently, no address can transact on the network. Airdrop some funds to one of the allow list addresses to continue")
+	return genesisBytes, sc, nil
 }
 
 // In own function to facilitate testing
@@ -273,6 +305,17 @@ func getVMVersion(
 	return vmVersion, nil
 }
 
+// unknownVMVersionError explains that [vmVersion] is not a published release of [repoName]
+func unknownVMVersionError(vmName string, repoName string, vmVersion string) error {
+	return fmt.Errorf(
+		"%s version %s not found. Available versions are listed at https://github.com/%s/%s/releases",
+		vmName,
+		vmVersion,
+		constants.AvaLabsOrg,
+		repoName,
+	)
+}
+
 func askForVMVersion(
 	app *application.Avalanche,
 	vmName string,
@@ -305,7 +348,7 @@ func askForVMVersion(
 	}
 
 	versionOption, err := app.Prompt.CaptureList(
-		defaultPrompt,
+		prompts.Keyed("vm-version", defaultPrompt),
 		versionOptions,
 	)
 	if err != nil {
@@ -325,10 +368,19 @@ func askForVMVersion(
 	if err != nil {
 		return "", err
 	}
-	version, err := app.Prompt.CaptureList("Pick the version for this VM", versions)
+	version, err := app.Prompt.CaptureList(prompts.Keyed("vm-custom-version", "Pick the version for this VM"), versions)
 	if err != nil {
 		return "", err
 	}
 
 	return version, nil
 }
+
+// directionErr translates the direction of a subnet creation step into the
+// error expected by the wizard
+func directionErr(direction statemachine.StateDirection, err error) error {
+	if err == nil && direction == statemachine.Backward {
+		return prompts.ErrGoBack
+	}
+	return err
+}
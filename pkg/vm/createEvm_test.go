@@ -7,87 +7,19 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/MetalBlockchain/metal-cli/internal/mocks"
 	"github.com/MetalBlockchain/metal-cli/internal/testutils"
+	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/sdk"
 	"github.com/MetalBlockchain/subnet-evm/core"
-	"github.com/ethereum/go-ethereum/common"
+	"github.com/MetalBlockchain/subnet-evm/params"
+	"github.com/MetalBlockchain/subnet-evm/precompile/contracts/nativeminter"
+	"github.com/MetalBlockchain/subnet-evm/utils"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
-func Test_ensureAdminsFunded(t *testing.T) {
-	addrs, err := testutils.GenerateEthAddrs(5)
-	require.NoError(t, err)
-
-	type test struct {
-		name       string
-		alloc      core.GenesisAlloc
-		admins     []common.Address
-		shouldFail bool
-	}
-	tests := []test{
-		{
-			name: "One address funded",
-			alloc: map[common.Address]core.GenesisAccount{
-				addrs[0]: {},
-				addrs[1]: {
-					Balance: big.NewInt(42),
-				},
-				addrs[2]: {},
-			},
-			admins:     []common.Address{addrs[1]},
-			shouldFail: false,
-		},
-		{
-			name: "Two addresses funded",
-			alloc: map[common.Address]core.GenesisAccount{
-				addrs[2]: {},
-				addrs[3]: {
-					Balance: big.NewInt(42),
-				},
-				addrs[4]: {
-					Balance: big.NewInt(42),
-				},
-			},
-			admins:     []common.Address{addrs[3], addrs[4]},
-			shouldFail: false,
-		},
-		{
-			name: "Two addresses in Genesis but no funds",
-			alloc: map[common.Address]core.GenesisAccount{
-				addrs[0]: {
-					Balance: big.NewInt(0),
-				},
-				addrs[1]: {},
-				addrs[2]: {},
-			},
-			admins:     []common.Address{addrs[0], addrs[2]},
-			shouldFail: true,
-		},
-		{
-			name: "No address funded",
-			alloc: map[common.Address]core.GenesisAccount{
-				addrs[0]: {},
-				addrs[1]: {},
-				addrs[2]: {},
-			},
-			admins:     []common.Address{addrs[3], addrs[4]},
-			shouldFail: true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			require := require.New(t)
-
-			err := ensureAdminsHaveBalance(tt.admins, tt.alloc)
-			if tt.shouldFail {
-				require.Error(err)
-			} else {
-				require.NoError(err)
-			}
-		})
-	}
-}
-
 func Test_removePrecompile(t *testing.T) {
 	allowList := "allow list"
 	minter := "minter"
@@ -133,3 +65,45 @@ func Test_removePrecompile(t *testing.T) {
 		})
 	}
 }
+
+func TestSetNativeTokenInfo(t *testing.T) {
+	require := require.New(t)
+	app := &application.Avalanche{}
+	addrs, err := testutils.GenerateEthAddrs(2)
+	require.NoError(err)
+	allocation := core.GenesisAlloc{
+		addrs[0]: {Balance: big.NewInt(40)},
+		addrs[1]: {Balance: big.NewInt(2)},
+	}
+
+	genesisBytes, err := sdk.NewEVMGenesis(sdk.EVMGenesisParams{ChainID: big.NewInt(1), Allocation: allocation})
+	require.NoError(err)
+	sc := models.Sidecar{TokenMintable: true}
+	require.NoError(setNativeTokenInfo(app, &sc, genesisBytes))
+	require.Equal("42", sc.TokenInitialSupply)
+	require.False(sc.TokenMintable)
+	require.Empty(sc.TokenMintAdmins)
+
+	genesisBytes, err = sdk.NewEVMGenesis(sdk.EVMGenesisParams{
+		ChainID:    big.NewInt(1),
+		Allocation: allocation,
+		Precompiles: params.Precompiles{
+			nativeminter.ConfigKey: nativeminter.NewConfig(utils.NewUint64(0), addrs[:1], nil, nil, nil),
+		},
+	})
+	require.NoError(err)
+	require.NoError(setNativeTokenInfo(app, &sc, genesisBytes))
+	require.True(sc.TokenMintable)
+	require.Equal([]string{addrs[0].Hex()}, sc.TokenMintAdmins)
+}
+
+func TestCreateEvmSubnetConfigUnknownVersion(t *testing.T) {
+	require := require.New(t)
+	mockDownloader := &mocks.Downloader{}
+	mockDownloader.On("Download", mock.Anything).Return(testSubnetEVMCompat, nil)
+	app := application.New()
+	app.Downloader = mockDownloader
+
+	_, _, err := CreateEvmSubnetConfig(app, "testSubnet", "", "v0.9.99", false, 0, "", false, false)
+	require.ErrorContains(err, "Subnet-EVM version v0.9.99 not found")
+}
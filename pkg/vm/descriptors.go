@@ -4,11 +4,16 @@
 package vm
 
 import (
+	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/models"
+	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
 	"github.com/MetalBlockchain/metal-cli/pkg/statemachine"
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
+	"github.com/MetalBlockchain/subnet-evm/precompile/contracts/nativeminter"
 )
 
 func getChainID(app *application.Avalanche, subnetEVMChainID uint64) (*big.Int, error) {
@@ -16,7 +21,7 @@ func getChainID(app *application.Avalanche, subnetEVMChainID uint64) (*big.Int,
 		return new(big.Int).SetUint64(subnetEVMChainID), nil
 	}
 	ux.Logger.PrintToUser("Enter your subnet's ChainId. It can be any positive integer.")
-	return app.Prompt.CapturePositiveBigInt("ChainId")
+	return app.Prompt.CapturePositiveBigInt(prompts.Keyed("chain-id", "ChainId"))
 }
 
 func getTokenSymbol(app *application.Avalanche, subnetEVMTokenSymbol string) (string, error) {
@@ -24,7 +29,7 @@ func getTokenSymbol(app *application.Avalanche, subnetEVMTokenSymbol string) (st
 		return subnetEVMTokenSymbol, nil
 	}
 	ux.Logger.PrintToUser("Select a symbol for your subnet's native token")
-	tokenSymbol, err := app.Prompt.CaptureString("Token symbol")
+	tokenSymbol, err := app.Prompt.CaptureString(prompts.Keyed("token-symbol", "Token symbol"))
 	if err != nil {
 		return "", err
 	}
@@ -54,3 +59,72 @@ func getDescriptors(
 
 	return chainID, tokenSymbol, statemachine.Forward, nil
 }
+
+func defaultTokenName(tokenSymbol string) string {
+	return tokenSymbol + " Token"
+}
+
+// getNativeToken asks for the name of the native token, and whether it can be minted
+// after genesis. Minting is enabled with the native minter precompile, whose admins
+// can allow other addresses to mint
+func getNativeToken(
+	app *application.Avalanche,
+	tokenSymbol string,
+	useDefaults bool,
+) (string, *nativeminter.Config, error) {
+	tokenName := defaultTokenName(tokenSymbol)
+	if useDefaults {
+		return tokenName, nil, nil
+	}
+	ux.Logger.PrintToUser("Select a name for your subnet's native token")
+	name, err := app.Prompt.CaptureStringAllowEmpty(prompts.Keyed("token-name", fmt.Sprintf("Token name (leave empty for %q)", tokenName)))
+	if err != nil {
+		return "", nil, err
+	}
+	if name = strings.TrimSpace(name); name != "" {
+		tokenName = name
+	}
+	mintable, err := app.Prompt.CaptureNoYes(prompts.Keyed("mintable", "Should it be possible to mint more tokens after genesis (native minting precompile)?"))
+	if err != nil {
+		return "", nil, err
+	}
+	if !mintable {
+		return tokenName, nil, nil
+	}
+	mintConfig, cancelled, err := configureMinterList(app)
+	if err != nil {
+		return "", nil, err
+	}
+	if cancelled {
+		return tokenName, nil, nil
+	}
+	return tokenName, &mintConfig, nil
+}
+
+// setNativeTokenInfo records on [sc] the initial supply and the minting config of the
+// native token of the Subnet-EVM genesis [genesisBytes]
+func setNativeTokenInfo(app *application.Avalanche, sc *models.Sidecar, genesisBytes []byte) error {
+	report, err := GetAllocationReport(genesisBytes, nil)
+	if err != nil {
+		return err
+	}
+	sc.TokenInitialSupply = report.TotalSupply.String()
+	genesis, err := app.LoadEvmGenesisFromJSON(genesisBytes)
+	if err != nil {
+		return err
+	}
+	sc.TokenMintable = false
+	sc.TokenMintAdmins = nil
+	if genesis.Config == nil {
+		return nil
+	}
+	mintConfig, ok := genesis.Config.GenesisPrecompiles[nativeminter.ConfigKey].(*nativeminter.Config)
+	if !ok || mintConfig.IsDisabled() {
+		return nil
+	}
+	sc.TokenMintable = true
+	for _, admin := range mintConfig.AdminAddresses {
+		sc.TokenMintAdmins = append(sc.TokenMintAdmins, admin.Hex())
+	}
+	return nil
+}
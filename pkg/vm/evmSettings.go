@@ -6,13 +6,14 @@ package vm
 import (
 	"math/big"
 
+	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
 	"github.com/MetalBlockchain/subnet-evm/commontype"
 	"github.com/ethereum/go-ethereum/common"
 )
 
 const (
 	defaultEvmAirdropAmount = "1000000000000000000000000"
-	goBackMsg               = "Go back to previous step"
+	goBackMsg               = prompts.GoBack
 )
 
 var (
@@ -5,6 +5,7 @@ package vm
 
 import (
 	"github.com/MetalBlockchain/metal-cli/pkg/application"
+	"github.com/MetalBlockchain/metal-cli/pkg/prompts"
 	"github.com/MetalBlockchain/metal-cli/pkg/statemachine"
 	"github.com/MetalBlockchain/metal-cli/pkg/ux"
 	"github.com/MetalBlockchain/subnet-evm/commontype"
@@ -42,7 +43,7 @@ func GetFeeConfig(config params.ChainConfig, app *application.Avalanche, useDefa
 	feeConfigOptions := []string{useSlow, useMedium, useFast, customFee, goBackMsg}
 
 	feeDefault, err := app.Prompt.CaptureList(
-		"How would you like to set fees",
+		prompts.Keyed("fee-config", "How would you like to set fees"),
 		feeConfigOptions,
 	)
 	if err != nil {
@@ -65,42 +66,42 @@ func GetFeeConfig(config params.ChainConfig, app *application.Avalanche, useDefa
 		ux.Logger.PrintToUser("Customizing fee config")
 	}
 
-	gasLimit, err := app.Prompt.CapturePositiveBigInt(setGasLimit)
+	gasLimit, err := app.Prompt.CapturePositiveBigInt(prompts.Keyed("gas-limit", setGasLimit))
 	if err != nil {
 		return config, statemachine.Stop, err
 	}
 
-	blockRate, err := app.Prompt.CapturePositiveBigInt(setBlockRate)
+	blockRate, err := app.Prompt.CapturePositiveBigInt(prompts.Keyed("block-rate", setBlockRate))
 	if err != nil {
 		return config, statemachine.Stop, err
 	}
 
-	minBaseFee, err := app.Prompt.CapturePositiveBigInt(setMinBaseFee)
+	minBaseFee, err := app.Prompt.CapturePositiveBigInt(prompts.Keyed("min-base-fee", setMinBaseFee))
 	if err != nil {
 		return config, statemachine.Stop, err
 	}
 
-	targetGas, err := app.Prompt.CapturePositiveBigInt(setTargetGas)
+	targetGas, err := app.Prompt.CapturePositiveBigInt(prompts.Keyed("target-gas", setTargetGas))
 	if err != nil {
 		return config, statemachine.Stop, err
 	}
 
-	baseDenominator, err := app.Prompt.CapturePositiveBigInt(setBaseFeeChangeDenominator)
+	baseDenominator, err := app.Prompt.CapturePositiveBigInt(prompts.Keyed("base-fee-change-denominator", setBaseFeeChangeDenominator))
 	if err != nil {
 		return config, statemachine.Stop, err
 	}
 
-	minBlockGas, err := app.Prompt.CapturePositiveBigInt(setMinBlockGas)
+	minBlockGas, err := app.Prompt.CapturePositiveBigInt(prompts.Keyed("min-block-gas", setMinBlockGas))
 	if err != nil {
 		return config, statemachine.Stop, err
 	}
 
-	maxBlockGas, err := app.Prompt.CapturePositiveBigInt(setMaxBlockGas)
+	maxBlockGas, err := app.Prompt.CapturePositiveBigInt(prompts.Keyed("max-block-gas", setMaxBlockGas))
 	if err != nil {
 		return config, statemachine.Stop, err
 	}
 
-	gasStep, err := app.Prompt.CapturePositiveBigInt(setGasStep)
+	gasStep, err := app.Prompt.CapturePositiveBigInt(prompts.Keyed("gas-step", setGasStep))
 	if err != nil {
 		return config, statemachine.Stop, err
 	}
@@ -88,7 +88,7 @@ func ConfigureInitialRewardConfig(app *application.Avalanche) (*rewardmanager.In
 	config := &rewardmanager.InitialRewardConfig{}
 
 	burnPrompt := "Should fees be burnt?"
-	burnFees, err := app.Prompt.CaptureYesNo(burnPrompt)
+	burnFees, err := app.Prompt.CaptureYesNo(prompts.Keyed("burn-fees", burnPrompt))
 	if err != nil {
 		return config, err
 	}
@@ -97,7 +97,7 @@ func ConfigureInitialRewardConfig(app *application.Avalanche) (*rewardmanager.In
 	}
 
 	feeRcpdPrompt := "Allow block producers to claim fees?"
-	allowFeeRecipients, err := app.Prompt.CaptureYesNo(feeRcpdPrompt)
+	allowFeeRecipients, err := app.Prompt.CaptureYesNo(prompts.Keyed("allow-fee-recipients", feeRcpdPrompt))
 	if err != nil {
 		return config, err
 	}
@@ -107,7 +107,7 @@ func ConfigureInitialRewardConfig(app *application.Avalanche) (*rewardmanager.In
 	}
 
 	rewardPrompt := "Provide the address to which fees will be sent to"
-	rewardAddress, err := app.Prompt.CaptureAddress(rewardPrompt)
+	rewardAddress, err := app.Prompt.CaptureAddress(prompts.Keyed("reward-address", rewardPrompt))
 	if err != nil {
 		return config, err
 	}
@@ -320,6 +320,14 @@ func getPrecompiles(
 	if useWarp {
 		remainingPrecompiles = []string{NativeMint, ContractAllowList, TxAllowList, FeeManager, RewardManager, cancel}
 	}
+	// native minting may already be configured along with the native token
+	if _, ok := config.GenesisPrecompiles[nativeminter.ConfigKey]; ok {
+		var err error
+		remainingPrecompiles, err = removePrecompile(remainingPrecompiles, NativeMint)
+		if err != nil {
+			return config, statemachine.Stop, err
+		}
+	}
 
 	for {
 		firstStr := "Advanced: Would you like to add a custom precompile to modify the EVM?"
@@ -331,7 +339,7 @@ func getPrecompiles(
 			first = false
 		}
 
-		addPrecompile, err := app.Prompt.CaptureList(promptStr, []string{prompts.No, prompts.Yes, goBackMsg})
+		addPrecompile, err := app.Prompt.CaptureList(prompts.Keyed("add-precompile", promptStr), []string{prompts.No, prompts.Yes, goBackMsg})
 		if err != nil {
 			return config, statemachine.Stop, err
 		}
@@ -344,7 +352,7 @@ func getPrecompiles(
 		}
 
 		precompileDecision, err := app.Prompt.CaptureListWithSize(
-			"Choose precompile",
+			prompts.Keyed("precompile", "Choose precompile"),
 			remainingPrecompiles,
 			len(remainingPrecompiles),
 		)
@@ -0,0 +1,50 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/MetalBlockchain/metal-cli/pkg/utils"
+)
+
+// Event is the JSON payload posted to a webhook. [Text] makes it directly
+// usable by Slack compatible incoming webhooks
+type Event struct {
+	Text    string    `json:"text"`
+	Kind    string    `json:"kind"`
+	Subnet  string    `json:"subnet,omitempty"`
+	Network string    `json:"network,omitempty"`
+	Command string    `json:"command,omitempty"`
+	TxIDs   []string  `json:"txIDs,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// Send posts [event] as JSON to [url], failing on non 2xx responses
+func Send(url string, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request for %s: %w", url, err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed posting to webhook %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed posting to webhook %s: unexpected http status code: %d", url, resp.StatusCode)
+	}
+	return nil
+}
@@ -93,6 +93,7 @@ func ExportKey(keyName string) (string, error) {
 		KeyCmd,
 		"export",
 		keyName,
+		"--force",
 		"--"+constants.SkipUpdateFlag,
 	)
 
@@ -110,6 +111,7 @@ func ExportKeyToFile(keyName string, outputPath string) (string, error) {
 		keyName,
 		"-o",
 		outputPath,
+		"--force",
 		"--"+constants.SkipUpdateFlag,
 	)
 